@@ -20,7 +20,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
 
+	"github.com/kyma-project/module-manager/pkg/labels"
 	"github.com/kyma-project/module-manager/pkg/types"
 )
 
@@ -35,6 +37,68 @@ func (m *Manifest) IsSpecUpdated() bool {
 	return m.Status.ObservedGeneration != m.Generation
 }
 
+// IsDryRun indicates if changes of the Manifest should only be previewed instead of being applied.
+func (m *Manifest) IsDryRun() bool {
+	return m.GetAnnotations()[labels.DryRunAnnotation] == "true"
+}
+
+// IsPaused indicates if the reconciliation of the Manifest is halted.
+func (m *Manifest) IsPaused() bool {
+	return m.GetAnnotations()[labels.PausedAnnotation] == "true"
+}
+
+// RolloutHold returns the name of the ModuleRelease holding spec changes of the Manifest until its rollout
+// releases them, or an empty string if they are not held.
+func (m *Manifest) RolloutHold() string {
+	return m.GetAnnotations()[labels.RolloutHoldAnnotation]
+}
+
+// InstalledVersion returns the chart version installed last by the install with the passed name,
+// or an empty string if it was not installed yet.
+func (m *Manifest) InstalledVersion(installName string) string {
+	for _, installed := range m.Status.InstalledVersions {
+		if installed.Name == installName {
+			return installed.Version
+		}
+	}
+	return ""
+}
+
+// BackupLocation returns the policy locating the snapshot referenced in the backups of the install with the passed
+// name, or nil if no snapshot was taken for the install, e.g. to remove the snapshot of a removed install.
+func (m *Manifest) BackupLocation(installName string) *types.BackupPolicy {
+	for _, backup := range m.Status.Backups {
+		if backup.Name == installName {
+			return &types.BackupPolicy{Namespace: backup.Namespace, Driver: types.BackupStorageDriver(backup.Kind)}
+		}
+	}
+	return nil
+}
+
+// InstallChannel returns the channel the install with the passed reference follows, with the channel of the Manifest
+// if the reference does not name a channel, or nil if the install does not reference a ModuleCatalog.
+func (m *Manifest) InstallChannel(reference *types.ChannelReference) *types.ChannelReference {
+	if reference == nil {
+		return nil
+	}
+	channel := *reference
+	if channel.Channel == "" {
+		channel.Channel = m.Spec.Channel
+	}
+	return &channel
+}
+
+// IsInstallApplied indicates if resources of the install with the passed name were applied before,
+// either recorded as applied install or with an installed version.
+func (m *Manifest) IsInstallApplied(installName string) bool {
+	for _, applied := range m.Status.AppliedInstalls {
+		if applied == installName {
+			return true
+		}
+	}
+	return m.InstalledVersion(installName) != ""
+}
+
 // InstallInfo defines installation information.
 type InstallInfo struct {
 	// Source can either be described as ImageSpec, HelmChartSpec or KustomizeSpec
@@ -43,6 +107,124 @@ type InstallInfo struct {
 
 	// Name specifies a unique install name for Manifest
 	Name string `json:"name"`
+
+	// NamespaceCreatePolicy determines how the target namespace of the install is managed.
+	// CreateIfMissing creates a missing namespace, MustExist fails the install if the namespace is missing,
+	// e.g. for namespaces shared between modules, and CreateAndDelete additionally deletes the namespace
+	// on uninstall, unless it existed before the install.
+	// If not set, a missing namespace is only created with the CreateNamespace flag of the install config.
+	// +kubebuilder:validation:Optional
+	NamespaceCreatePolicy types.NamespaceCreatePolicy `json:"namespaceCreatePolicy,omitempty"`
+
+	// ConflictPolicy determines how conflicts on the field ownership of resources applied with server-side apply
+	// are resolved, e.g. if a HorizontalPodAutoscaler changed the replicas of a Deployment.
+	// Force takes over the ownership of conflicting fields, Ignore leaves them to their current manager
+	// and Fail fails the install. If not set, the ownership is forced.
+	// +kubebuilder:validation:Optional
+	ConflictPolicy types.ConflictPolicy `json:"conflictPolicy,omitempty"`
+
+	// MissingAPIPolicy determines how rendered resources of APIs not served by the target cluster are handled,
+	// e.g. ServiceMonitors on clusters without Prometheus. Fail fails the install before any resource is applied
+	// and Skip applies all other resources. In both cases, the PrerequisitesMet condition names the missing APIs.
+	// If not set, the install fails.
+	// +kubebuilder:validation:Optional
+	MissingAPIPolicy types.MissingAPIPolicy `json:"missingAPIPolicy,omitempty"`
+
+	// Exclude lists selectors of rendered resources, which are not applied, e.g. ServiceMonitors bundled
+	// with a chart for clusters without Prometheus. A resource is excluded if it matches any selector.
+	// +kubebuilder:validation:Optional
+	Exclude []types.ResourceSelector `json:"exclude,omitempty"`
+
+	// SkipResources lists patterns of rendered resources, which are neither applied nor recorded in the inventory,
+	// e.g. PrometheusRules bundled with a chart, which conflict with rules managed elsewhere. Unlike excluded
+	// resources, skipped resources applied before are kept in the target cluster instead of being pruned.
+	// +kubebuilder:validation:Optional
+	SkipResources []types.ResourcePattern `json:"skipResources,omitempty"`
+
+	// UpgradePolicy refuses changes of the chart version, which skip mandatory versions or downgrade
+	// the version installed last, with the UpgradeAllowed condition naming the refused change.
+	// If not set, all version changes are allowed.
+	// +kubebuilder:validation:Optional
+	UpgradePolicy *types.UpgradePolicy `json:"upgradePolicy,omitempty"`
+
+	// HookPolicy determines how the Helm hooks of the chart are handled. Skip renders the chart without hooks,
+	// Run runs its pre-install and post-install hooks on the first install and its pre-upgrade and post-upgrade
+	// hooks whenever the rendered manifest changes, waiting for their completion and respecting their
+	// hook-delete-policy. Delete hooks are not run. If not set, hooks are skipped.
+	// +kubebuilder:validation:Optional
+	HookPolicy types.HookPolicy `json:"hookPolicy,omitempty"`
+
+	// DeletionPolicy configures the order of deletions, the propagation to dependents and if the uninstall
+	// waits for the termination of all resources, e.g. so that resources blocked by finalizers are not orphaned
+	// once the Manifest is gone. If not set, all resources are deleted at once in the background.
+	// +kubebuilder:validation:Optional
+	DeletionPolicy *types.DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// OverrideSelector lists blocks of values, which are merged into the values of the install if their selector
+	// matches the labels of the Manifest, extended by operator.kyma-project.io/remote set to "true" or "false".
+	// The values of matching overrides take precedence over the values of the config and profile, later overrides
+	// take precedence over earlier ones, e.g. to configure installs per environment from a single spec.
+	// +kubebuilder:validation:Optional
+	OverrideSelector []types.SelectedOverride `json:"overrideSelector,omitempty"`
+
+	// SecretValues set chart values to keys of Secrets in the namespace of the Manifest, which are resolved
+	// whenever the install is rendered and take precedence over all other values. Resolved values are redacted
+	// from the logs of the operator.
+	// +kubebuilder:validation:Optional
+	SecretValues []types.SecretValue `json:"secretValues,omitempty"`
+
+	// ReleaseStorage configures the namespace, the kind of resource and the impersonated ServiceAccount
+	// the release history of the install is stored with in the target cluster, e.g. for read-only or multi-tenant
+	// target clusters. If not set, it is stored as a Secret next to the inventory of the install.
+	// +kubebuilder:validation:Optional
+	ReleaseStorage *types.ReleaseStorage `json:"releaseStorage,omitempty"`
+
+	// WaitFor lists objects in the target cluster, which have to satisfy their expectation before the resources
+	// of the install are applied, e.g. custom resources of other modules the install depends on. Until then,
+	// the install stays Processing and reports the unsatisfied objects in its DependenciesReady condition.
+	// +kubebuilder:validation:Optional
+	WaitFor []types.WaitFor `json:"waitFor,omitempty"`
+
+	// HealthRules evaluate the health of applied resources of the install by CEL expressions during consistency
+	// checks, in addition to the built-in health checks of workloads. Resources, for which an expression evaluates
+	// to false, are reported in the Healthy condition of the install. Compiled expressions are cached per Manifest.
+	// +kubebuilder:validation:Optional
+	HealthRules []types.HealthRule `json:"healthRules,omitempty"`
+
+	// AdoptExisting allows the first apply of the install to adopt rendered resources, which already exist in
+	// the target cluster without being managed by the operator, e.g. as they were installed manually, by labeling them
+	// as owned by the Manifest. Otherwise, the install fails with reason ResourceExists naming the resources.
+	// Resources owned by other Manifests are never adopted.
+	// +kubebuilder:validation:Optional
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+
+	// TargetNamespace overrides the namespace of all rendered namespaced resources of the install, including those
+	// rendered into explicit namespaces, and takes precedence over the Namespace of the install config.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+
+	// NamespaceMappings split the rendered namespaced resources of the install across namespaces, e.g. to move
+	// webhooks into a system namespace. Resources matching the patterns of a mapping are moved to its namespace,
+	// which has to exist, all other resources stay in the target namespace. The install fails with reason
+	// NamespaceConflict if a resource matches mappings of different namespaces, or if resources of the same kind
+	// and name end up in the same namespace.
+	// +kubebuilder:validation:Optional
+	NamespaceMappings []types.NamespaceMapping `json:"namespaceMappings,omitempty"`
+
+	// Channel references a channel of a ModuleCatalog in the namespace of the Manifest, which resolves the
+	// version of the chart of a Helm source instead of a pinned version. The install fails with reason
+	// ChannelNotResolved as long as the catalog does not resolve the channel to a version.
+	// +kubebuilder:validation:Optional
+	Channel *types.ChannelReference `json:"channel,omitempty"`
+
+	// Backup snapshots the live resources of the install before an upgrade overwrites them, so that a failed upgrade
+	// can be inspected or restored manually. The snapshot is stored as multi-document YAML in a Secret or ConfigMap
+	// in the target cluster, which is referenced by the backups in the status and removed on uninstall.
+	// Upgrades fail with reason BackupFailed as long as the snapshot cannot be stored.
+	// +kubebuilder:validation:Optional
+	Backup *types.BackupPolicy `json:"backup,omitempty"`
 }
 
 // ManifestSpec defines the specification of Manifest.
@@ -52,6 +234,10 @@ type ManifestSpec struct {
 	// +kubebuilder:default:=true
 	Remote bool `json:"remote"`
 
+	// RemoteInfo configures how resources are applied to the remote cluster, if Remote is enabled
+	// +kubebuilder:validation:Optional
+	RemoteInfo *RemoteInfo `json:"remoteInfo,omitempty"`
+
 	// Config specifies OCI image configuration for Manifest
 	// +kubebuilder:validation:Optional
 	Config types.ImageSpec `json:"config"`
@@ -59,17 +245,110 @@ type ManifestSpec struct {
 	// Installs specifies a list of installations for Manifest
 	Installs []InstallInfo `json:"installs"`
 
+	// InstallOrder determines if Installs are processed in parallel or sequentially in their listed order,
+	// where each install is only processed once the previous one is ready. Uninstalls are processed in reverse order.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Parallel
+	InstallOrder InstallOrder `json:"installOrder,omitempty"`
+
+	// Profile selects the preset of values bundled with the chart of each install as profile-<profile>.yaml,
+	// e.g. "evaluation" or "production". The values of the profile are merged into the values of the install
+	// before rendering, the values of the config take precedence. If not set, no preset is used.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Profile string `json:"profile,omitempty"`
+
 	//+kubebuilder:pruning:PreserveUnknownFields
 	// +kubebuilder:validation:Optional
 	// Resource specifies a resource to be watched for state updates
 	Resource unstructured.Unstructured `json:"resource"`
 
+	// ResourceStatusPaths locate the state of Resource, if it does not report its state in `.status.state`,
+	// e.g. `.status.moduleState`
+	// +kubebuilder:validation:Optional
+	ResourceStatusPaths *types.StatusPaths `json:"resourceStatusPaths,omitempty"`
+
 	// CRDs specifies the custom resource definitions' ImageSpec
 	// +kubebuilder:validation:Optional
 	CRDs types.ImageSpec `json:"crds"`
+
+	// CRDPolicy determines if the CustomResourceDefinitions of CRDs and of the installed charts are removed
+	// when the Manifest is deleted. Removing them also deletes all custom resources of them in the cluster.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Keep
+	CRDPolicy types.CRDPolicy `json:"crdPolicy,omitempty"`
+
+	// Transforms specifies a list of transformations executed in order on all rendered resources
+	// before they are applied
+	// +kubebuilder:validation:Optional
+	Transforms []types.TransformSpec `json:"transforms,omitempty"`
+
+	// Resilience injects PodDisruptionBudgets and optionally topology spread constraints for highly available
+	// Deployments of all installs, which do not define them already. If not set, nothing is injected.
+	// +kubebuilder:validation:Optional
+	Resilience *types.ResilienceSpec `json:"resilience,omitempty"`
+
+	// SecretRotation restarts the workloads of all installs consuming rendered Secrets, whose data changed,
+	// e.g. rotated credentials. The Secrets are applied first, the consumers are restarted afterwards one after
+	// another, each once the previous one is rolled out. If not set, consumers are not restarted.
+	// +kubebuilder:validation:Optional
+	SecretRotation *types.SecretRotationSpec `json:"secretRotation,omitempty"`
+
+	// Timeout limits the duration of a single install, uninstall or consistency check of each install.
+	// Exceeding it results in a retryable Error state. If not set, the operator default is used.
+	// +kubebuilder:validation:Optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// UpgradeWindows restrict changes of the chart version of installed charts to recurring maintenance windows.
+	// Outside of all windows, installs with a pending change of their chart version keep their installed
+	// version with the UpgradePending condition set, while all other installs are still reconciled.
+	// If not set, versions are changed immediately.
+	// +kubebuilder:validation:Optional
+	UpgradeWindows []types.MaintenanceWindow `json:"upgradeWindows,omitempty"`
+
+	// Channel is the channel of all installs referencing a ModuleCatalog without naming a channel,
+	// e.g. "fast" or "regular". Installs follow their channel and are upgraded once it advances.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Channel string `json:"channel,omitempty"`
 }
 
-// +kubebuilder:validation:Enum=Processing;Deleting;Ready;Error
+// RemoteInfo defines the identity used to apply resources to a remote cluster.
+type RemoteInfo struct {
+	// ImpersonateUser is the user impersonated for all requests to the remote cluster,
+	// so that installs run with the permissions granted to it instead of the ones of the kubeconfig.
+	// ServiceAccounts are impersonated as "system:serviceaccount:<namespace>:<name>".
+	// +kubebuilder:validation:Optional
+	ImpersonateUser string `json:"impersonateUser,omitempty"`
+
+	// ImpersonateGroups are the groups impersonated for all requests to the remote cluster.
+	// They can only be set together with ImpersonateUser.
+	// +kubebuilder:validation:Optional
+	ImpersonateGroups []string `json:"impersonateGroups,omitempty"`
+}
+
+// ImpersonationConfig returns the rest.ImpersonationConfig for the RemoteInfo.
+// If RemoteInfo is nil, no identity is impersonated.
+func (r *RemoteInfo) ImpersonationConfig() rest.ImpersonationConfig {
+	if r == nil {
+		return rest.ImpersonationConfig{}
+	}
+	return rest.ImpersonationConfig{UserName: r.ImpersonateUser, Groups: r.ImpersonateGroups}
+}
+
+// InstallOrder determines how the Installs of a Manifest are processed.
+// +kubebuilder:validation:Enum=Parallel;Sequential
+type InstallOrder string
+
+const (
+	// InstallOrderParallel processes all installs at the same time.
+	InstallOrderParallel InstallOrder = "Parallel"
+	// InstallOrderSequential processes installs one after another, gated on the readiness of the previous install.
+	InstallOrderSequential InstallOrder = "Sequential"
+)
+
+// +kubebuilder:validation:Enum=Processing;Deleting;Ready;Warning;Error
 type ManifestState string
 
 // Valid Helm States.
@@ -85,12 +364,16 @@ const (
 
 	// ManifestStateDeleting signifies Manifest is being deleted.
 	ManifestStateDeleting ManifestState = "Deleting"
+
+	// ManifestStateWarning signifies Manifest was installed, but some of its workloads are degraded,
+	// e.g. Deployments with fewer ready replicas than desired.
+	ManifestStateWarning ManifestState = "Warning"
 )
 
 // ManifestStatus defines the observed state of Manifest.
 type ManifestStatus struct {
 	// State signifies current state of Manifest
-	// +kubebuilder:validation:Enum=Ready;Processing;Error;Deleting;
+	// +kubebuilder:validation:Enum=Ready;Processing;Warning;Error;Deleting;
 	State ManifestState `json:"state"`
 
 	// Conditions is a list of status conditions to indicate the status of Manifest
@@ -100,6 +383,80 @@ type ManifestStatus struct {
 	// ObservedGeneration
 	// +kubebuilder:validation:Optional
 	ObservedGeneration int64 `json:"observedGeneration"`
+
+	// LastAppliedChartVersion lists the chart versions of the last successful install,
+	// formatted as "<install>@<version>" and separated by commas for multiple installs
+	// +kubebuilder:validation:Optional
+	LastAppliedChartVersion string `json:"lastAppliedChartVersion,omitempty"`
+
+	// LastAppliedValuesHash is the hash of the values and chart sources of all installs of the last successful
+	// install, combined with the remote, resource, crds and transforms of the spec
+	// +kubebuilder:validation:Optional
+	LastAppliedValuesHash string `json:"lastAppliedValuesHash,omitempty"`
+
+	// LastSuccessfulInstallTime is the time the last install of the Manifest became ready
+	// +kubebuilder:validation:Optional
+	LastSuccessfulInstallTime *metav1.Time `json:"lastSuccessfulInstallTime,omitempty"`
+
+	// ErrorClassification indicates if the error of a Manifest in Error state is expected to resolve on retry
+	// +kubebuilder:validation:Optional
+	ErrorClassification types.ErrorClassification `json:"errorClassification,omitempty"`
+
+	// SecurityFindings lists issues detected by content scanners in the rendered resources of the last install
+	// +kubebuilder:validation:Optional
+	SecurityFindings []types.SecurityFinding `json:"securityFindings,omitempty"`
+
+	// Releases lists the latest revision of each release, if the release history is enabled
+	// +kubebuilder:validation:Optional
+	Releases []types.ReleaseRevision `json:"releases,omitempty"`
+
+	// Preview lists the changes an install would apply, while the Manifest is annotated for a dry-run
+	// +kubebuilder:validation:Optional
+	Preview []types.ResourceDiff `json:"preview,omitempty"`
+
+	// AppliedMigrations lists the versions of the migrations applied to the resources of all installs,
+	// so that each migration of the operator is applied only once
+	// +kubebuilder:validation:Optional
+	AppliedMigrations []string `json:"appliedMigrations,omitempty"`
+
+	// AppliedInstalls lists the names of all installs, whose resources were applied to the target cluster.
+	// Installs removed from the spec are uninstalled and removed from the list, while all other installs stay untouched
+	// +kubebuilder:validation:Optional
+	AppliedInstalls []string `json:"appliedInstalls,omitempty"`
+
+	// InstalledVersions lists the chart version installed last by each install, against which the UpgradePolicy
+	// of the install is validated
+	// +kubebuilder:validation:Optional
+	InstalledVersions []types.InstalledVersion `json:"installedVersions,omitempty"`
+
+	// Channels lists the version the channel of each install subscribed to a channel resolved to,
+	// when the install was installed last
+	// +kubebuilder:validation:Optional
+	Channels []types.ResolvedChannel `json:"channels,omitempty"`
+
+	// Images lists the image the followed tag of each install following a tag resolved to,
+	// when the install was installed last
+	// +kubebuilder:validation:Optional
+	Images []types.ResolvedImage `json:"images,omitempty"`
+
+	// LastApply lists per install how its last apply changed each of its resources, e.g. to find the resources
+	// which failed to apply. Failed resources are listed first and the number of listed resources is capped per install.
+	// +kubebuilder:validation:Optional
+	LastApply []types.InstallApplyStatus `json:"lastApply,omitempty"`
+
+	// Backups locate the snapshot of the live resources each install with a backup policy took before its last
+	// upgrade, e.g. to restore the resources after a failed upgrade
+	// +kubebuilder:validation:Optional
+	Backups []types.BackupReference `json:"backups,omitempty"`
+
+	// UninstallFailures counts the failed uninstall attempts since the deletion of the Manifest
+	// +kubebuilder:validation:Optional
+	UninstallFailures int `json:"uninstallFailures,omitempty"`
+
+	// ManagedBy identifies the version of module-manager which last updated the status,
+	// e.g. "module-manager/v1.2.3"
+	// +kubebuilder:validation:Optional
+	ManagedBy string `json:"managedBy,omitempty"`
 }
 
 // InstallItem describes install information for ManifestCondition.
@@ -148,6 +505,46 @@ type ManifestConditionType string
 const (
 	// ConditionTypeReady represents ManifestConditionType Ready.
 	ConditionTypeReady ManifestConditionType = "Ready"
+
+	// ConditionTypeSchedulable represents ManifestConditionType Schedulable,
+	// indicating if the workloads of an install fit the nodes of the target cluster.
+	ConditionTypeSchedulable ManifestConditionType = "Schedulable"
+
+	// ConditionTypeSufficientCapacity represents ManifestConditionType SufficientCapacity,
+	// indicating if the resources requested by the workloads of an install fit the capacity of the target cluster.
+	ConditionTypeSufficientCapacity ManifestConditionType = "SufficientCapacity"
+
+	// ConditionTypeHealthy represents ManifestConditionType Healthy,
+	// indicating if the workloads of an install stayed healthy after it was installed.
+	ConditionTypeHealthy ManifestConditionType = "Healthy"
+
+	// ConditionTypePrerequisitesMet represents ManifestConditionType PrerequisitesMet,
+	// indicating if the target cluster serves the APIs of all resources of an install.
+	ConditionTypePrerequisitesMet ManifestConditionType = "PrerequisitesMet"
+
+	// ConditionTypeUpgradeAllowed represents ManifestConditionType UpgradeAllowed,
+	// indicating if the chart version of an install complies with its UpgradePolicy.
+	ConditionTypeUpgradeAllowed ManifestConditionType = "UpgradeAllowed"
+
+	// ConditionTypeUpgradePending represents ManifestConditionType UpgradePending,
+	// indicating if the change of the chart version of an install waits for the next upgrade window.
+	ConditionTypeUpgradePending ManifestConditionType = "UpgradePending"
+
+	// ConditionTypeVerified represents ManifestConditionType Verified,
+	// indicating if the chart archive of an install matches its declared digest and provenance.
+	ConditionTypeVerified ManifestConditionType = "Verified"
+
+	// ConditionTypeDependenciesReady represents ManifestConditionType DependenciesReady,
+	// indicating if the objects an install waits for satisfy their expectation.
+	ConditionTypeDependenciesReady ManifestConditionType = "DependenciesReady"
+
+	// ConditionTypeUninstalled represents ManifestConditionType Uninstalled,
+	// indicating if the resources of an install removed from the spec were uninstalled.
+	ConditionTypeUninstalled ManifestConditionType = "Uninstalled"
+
+	// ConditionTypePaused represents ManifestConditionType Paused,
+	// indicating if the reconciliation of the Manifest is halted by its paused annotation.
+	ConditionTypePaused ManifestConditionType = "Paused"
 )
 
 type ManifestConditionStatus string
@@ -166,6 +563,7 @@ const (
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:storageversion
 //+kubebuilder:printcolumn:name="State",type=string,JSONPath=".status.state"
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
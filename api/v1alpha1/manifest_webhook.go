@@ -17,15 +17,20 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"strings"
+
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
 )
 
 // log is for logging in this package.
@@ -108,6 +113,14 @@ func (m *Manifest) validateInstalls() error {
 		}
 	}
 
+	fieldErrors = append(fieldErrors, m.validateResourceStatusPaths()...)
+	fieldErrors = append(fieldErrors, m.validateOverrideSelectors()...)
+	fieldErrors = append(fieldErrors, m.validateUpgradeWindows()...)
+	fieldErrors = append(fieldErrors, m.validateWaitFor()...)
+	fieldErrors = append(fieldErrors, m.validateHealthRules()...)
+	fieldErrors = append(fieldErrors, m.validateNamespaceMappings()...)
+	fieldErrors = append(fieldErrors, m.validateChannels()...)
+
 	if len(fieldErrors) > 0 {
 		return apierrors.NewInvalid(
 			schema.GroupKind{Group: GroupVersion.Group, Kind: ManifestKind},
@@ -116,3 +129,123 @@ func (m *Manifest) validateInstalls() error {
 
 	return nil
 }
+
+// validateResourceStatusPaths refuses paths, which cannot be read or written as a single field of the resource.
+func (m *Manifest) validateResourceStatusPaths() field.ErrorList {
+	if m.Spec.ResourceStatusPaths == nil {
+		return nil
+	}
+	fieldErrors := make(field.ErrorList, 0)
+	path := field.NewPath("spec").Child("resourceStatusPaths")
+	for _, statusPath := range []struct{ name, value string }{
+		{"state", m.Spec.ResourceStatusPaths.State},
+		{"conditions", m.Spec.ResourceStatusPaths.Conditions},
+	} {
+		if statusPath.value == "" {
+			continue
+		}
+		if _, err := util.ParseFieldPath(statusPath.value); err != nil {
+			fieldErrors = append(fieldErrors, field.Invalid(path.Child(statusPath.name), statusPath.value, err.Error()))
+		}
+	}
+	return fieldErrors
+}
+
+// validateOverrideSelectors refuses override selectors, which cannot be evaluated as label selectors.
+func (m *Manifest) validateOverrideSelectors() field.ErrorList {
+	fieldErrors := make(field.ErrorList, 0)
+	for i, install := range m.Spec.Installs {
+		for j := range install.OverrideSelector {
+			selector := &install.OverrideSelector[j].Selector
+			if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+				path := field.NewPath("spec").Child("installs").Index(i).Child("overrideSelector").Index(j)
+				fieldErrors = append(fieldErrors, field.Invalid(path.Child("selector"), selector, err.Error()))
+			}
+		}
+	}
+	return fieldErrors
+}
+
+// validateUpgradeWindows refuses upgrade windows, whose schedule cannot be parsed or which never open.
+func (m *Manifest) validateUpgradeWindows() field.ErrorList {
+	fieldErrors := make(field.ErrorList, 0)
+	for i, window := range m.Spec.UpgradeWindows {
+		if err := util.ValidateMaintenanceWindow(window); err != nil {
+			path := field.NewPath("spec").Child("upgradeWindows").Index(i)
+			fieldErrors = append(fieldErrors, field.Invalid(path, window, err.Error()))
+		}
+	}
+	return fieldErrors
+}
+
+// validateWaitFor refuses objects to wait for, whose expectation cannot be evaluated.
+func (m *Manifest) validateWaitFor() field.ErrorList {
+	fieldErrors := make(field.ErrorList, 0)
+	for i, install := range m.Spec.Installs {
+		for j, waitFor := range install.WaitFor {
+			if err := util.ValidateWaitFor(waitFor); err != nil {
+				path := field.NewPath("spec").Child("installs").Index(i).Child("waitFor").Index(j)
+				fieldErrors = append(fieldErrors, field.Invalid(path, waitFor, err.Error()))
+			}
+		}
+	}
+	return fieldErrors
+}
+
+// validateHealthRules refuses health rules, whose expression does not compile to a boolean.
+func (m *Manifest) validateHealthRules() field.ErrorList {
+	fieldErrors := make(field.ErrorList, 0)
+	for i, install := range m.Spec.Installs {
+		for j, rule := range install.HealthRules {
+			if err := util.ValidateHealthRule(rule); err != nil {
+				path := field.NewPath("spec").Child("installs").Index(i).Child("healthRules").Index(j)
+				fieldErrors = append(fieldErrors, field.Invalid(path.Child("expression"), rule.Expression, err.Error()))
+			}
+		}
+	}
+	return fieldErrors
+}
+
+// validateNamespaceMappings refuses mappings to invalid namespaces and mappings with patterns matching all resources.
+func (m *Manifest) validateNamespaceMappings() field.ErrorList {
+	fieldErrors := make(field.ErrorList, 0)
+	for i, install := range m.Spec.Installs {
+		for j, mapping := range install.NamespaceMappings {
+			path := field.NewPath("spec").Child("installs").Index(i).Child("namespaceMappings").Index(j)
+			if errs := validation.IsDNS1123Label(mapping.Namespace); len(errs) > 0 {
+				fieldErrors = append(fieldErrors,
+					field.Invalid(path.Child("namespace"), mapping.Namespace, strings.Join(errs, ", ")))
+			}
+			if len(mapping.Resources) == 0 {
+				fieldErrors = append(fieldErrors, field.Required(path.Child("resources"), "no resources are moved"))
+			} else if err := util.ValidateResourcePatterns(mapping.Resources); err != nil {
+				fieldErrors = append(fieldErrors, field.Invalid(path.Child("resources"), mapping.Resources, err.Error()))
+			}
+		}
+	}
+	return fieldErrors
+}
+
+// validateChannels refuses channel references of installs without a Helm chart source, whose version they resolve,
+// and references without a channel, if the Manifest does not set a channel either.
+func (m *Manifest) validateChannels() field.ErrorList {
+	fieldErrors := make(field.ErrorList, 0)
+	for i, install := range m.Spec.Installs {
+		if install.Channel == nil {
+			continue
+		}
+		path := field.NewPath("spec").Child("installs").Index(i).Child("channel")
+		if specType, err := types.GetSpecType(install.Source.Raw); err == nil && specType != types.HelmChartType {
+			fieldErrors = append(fieldErrors, field.Invalid(path, install.Channel,
+				"channels only resolve the version of Helm chart sources"))
+		}
+		if install.Channel.Catalog == "" {
+			fieldErrors = append(fieldErrors, field.Required(path.Child("catalog"), "catalog of the channel"))
+		}
+		if m.InstallChannel(install.Channel).Channel == "" {
+			fieldErrors = append(fieldErrors, field.Required(path.Child("channel"),
+				"name of the channel, unless the Manifest sets a channel"))
+		}
+	}
+	return fieldErrors
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const ModuleCatalogKind = "ModuleCatalog"
+
+// ModuleCatalogSpec defines the repository listed by a ModuleCatalog and its channels.
+// Exactly one of Helm or OCI must be set.
+type ModuleCatalogSpec struct {
+	// Helm lists the versions of a chart of a Helm repository
+	// +kubebuilder:validation:Optional
+	Helm *CatalogHelmSource `json:"helm,omitempty"`
+
+	// OCI lists the tags of an OCI repository, which are semantic versions
+	// +kubebuilder:validation:Optional
+	OCI *CatalogOCISource `json:"oci,omitempty"`
+
+	// Channels publish the latest available version matching their version constraint,
+	// which installs of Manifests reference instead of a pinned version
+	// +kubebuilder:validation:Optional
+	Channels []CatalogChannel `json:"channels,omitempty"`
+
+	// Interval between two listings of the repository. If not set, it is listed every 10 minutes.
+	// +kubebuilder:validation:Optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// CatalogHelmSource locates a chart of a Helm repository.
+type CatalogHelmSource struct {
+	// URL is the URL of the Helm repository
+	URL string `json:"url"`
+
+	// Chart is the name of the chart in the repository
+	Chart string `json:"chart"`
+
+	// CredSecretSelector selects the Secret in the namespace of the ModuleCatalog with the credentials
+	// of a private repository, like for Helm sources of installs
+	// +kubebuilder:validation:Optional
+	CredSecretSelector *metav1.LabelSelector `json:"credSecretSelector,omitempty"`
+}
+
+// CatalogOCISource locates an OCI repository.
+type CatalogOCISource struct {
+	// Repository is the reference of the repository without tag, e.g. "europe-docker.pkg.dev/kyma/modules/redis"
+	Repository string `json:"repository"`
+
+	// CredSecretSelector selects the docker config Secret in the namespace of the ModuleCatalog with the
+	// credentials of a private registry, like for OCI sources of installs
+	// +kubebuilder:validation:Optional
+	CredSecretSelector *metav1.LabelSelector `json:"credSecretSelector,omitempty"`
+}
+
+// CatalogChannel resolves to the latest available version matching its constraint.
+type CatalogChannel struct {
+	// Name of the channel, e.g. "regular" or "fast"
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Version is a semver constraint, e.g. ">=1.2.0 <2.0.0". Pre-releases only match constraints naming
+	// a pre-release, e.g. ">=1.3.0-0". If not set, the channel resolves to the latest stable version.
+	// +kubebuilder:validation:Optional
+	Version string `json:"version,omitempty"`
+}
+
+// ModuleCatalogStatus defines the versions and channels discovered by the last listing.
+type ModuleCatalogStatus struct {
+	// State is Ready once the repository was listed and all channels resolve to a version,
+	// Warning if a channel does not resolve to a version and Error if the last listing failed
+	// +kubebuilder:validation:Optional
+	State ManifestState `json:"state,omitempty"`
+
+	// Message is a human-readable description of the last listing, naming its error or the unresolved channels
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+
+	// Versions lists the available versions, latest first, capped at the 50 latest versions
+	// +kubebuilder:validation:Optional
+	Versions []string `json:"versions,omitempty"`
+
+	// Channels lists the versions the channels resolve to
+	// +kubebuilder:validation:Optional
+	Channels []CatalogChannelStatus `json:"channels,omitempty"`
+
+	// LastSyncTime is the time the repository was listed successfully the last time
+	// +kubebuilder:validation:Optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// ObservedGeneration is the generation of the ModuleCatalog the status was discovered for
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// CatalogChannelStatus describes the version a channel resolves to.
+type CatalogChannelStatus struct {
+	// Name of the channel
+	Name string `json:"name"`
+
+	// Version is the latest available version matching the constraint of the channel,
+	// empty if no version matches
+	// +kubebuilder:validation:Optional
+	Version string `json:"version,omitempty"`
+}
+
+// ChannelVersion returns the version the channel with the passed name resolves to,
+// or an empty string if the channel is unknown or does not resolve to a version.
+func (c *ModuleCatalog) ChannelVersion(channel string) string {
+	for _, status := range c.Status.Channels {
+		if status.Name == channel {
+			return status.Version
+		}
+	}
+	return ""
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="State",type=string,JSONPath=".status.state"
+//+kubebuilder:printcolumn:name="Latest",type=string,JSONPath=".status.versions[0]"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ModuleCatalog periodically lists a Helm or OCI repository of a module and publishes its available versions
+// and the versions of its channels, which installs of Manifests reference instead of a pinned version.
+type ModuleCatalog struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Spec locates the listed repository and defines the channels
+	Spec ModuleCatalogSpec `json:"spec"`
+
+	// Status signifies the versions and channels discovered by the last listing
+	// +kubebuilder:validation:Optional
+	Status ModuleCatalogStatus `json:"status"`
+}
+
+//+kubebuilder:object:root=true
+
+// ModuleCatalogList contains a list of ModuleCatalog.
+type ModuleCatalogList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []ModuleCatalog `json:"items"`
+}
+
+//nolint:gochecknoinits
+func init() {
+	SchemeBuilder.Register(&ModuleCatalog{}, &ModuleCatalogList{})
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const ModuleReleaseKind = "ModuleRelease"
+
+// ModuleReleaseSpec defines the Manifests aggregated by a ModuleRelease.
+type ModuleReleaseSpec struct {
+	// Selector selects the Manifests of the module in the namespace of the ModuleRelease by their labels
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// Rollout rolls out spec changes of the selected Manifests, e.g. of the same module on different target
+	// clusters, in stages instead of all at once. If not set, all Manifests apply their spec changes immediately.
+	// +kubebuilder:validation:Optional
+	Rollout *RolloutStrategy `json:"rollout,omitempty"`
+}
+
+// RolloutStrategy releases the spec changes of installed Manifests in stages, starting with a canary stage followed
+// by batches. Each stage is released once all Manifests of the previous stages are Ready and the pause passed.
+// Manifests, which were not installed yet, are never held.
+type RolloutStrategy struct {
+	// Canary is the number of Manifests released in the first stage. If not set, the first stage is a batch.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	Canary int32 `json:"canary,omitempty"`
+
+	// BatchSize is the number of Manifests released in each stage after the canary.
+	// If not set, all remaining Manifests are released at once.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	BatchSize int32 `json:"batchSize,omitempty"`
+
+	// Pause between a stage becoming Ready and the release of the next stage. If not set, the next stage
+	// is released immediately.
+	// +kubebuilder:validation:Optional
+	Pause *metav1.Duration `json:"pause,omitempty"`
+
+	// MaxFailures is the error budget of the rollout, the number of released Manifests tolerated in Error state.
+	// The rollout halts once more released Manifests are in Error state and resumes once they recover.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MaxFailures int32 `json:"maxFailures,omitempty"`
+}
+
+// RolloutPhase describes the progress of a rollout of a ModuleRelease.
+// +kubebuilder:validation:Enum=Progressing;Waiting;Halted;Complete
+type RolloutPhase string
+
+const (
+	// RolloutPhaseProgressing signifies that the released Manifests apply their spec changes.
+	RolloutPhaseProgressing RolloutPhase = "Progressing"
+	// RolloutPhaseWaiting signifies that all released Manifests are Ready and the next stage waits for the pause.
+	RolloutPhaseWaiting RolloutPhase = "Waiting"
+	// RolloutPhaseHalted signifies that the error budget is exceeded, no further stages are released.
+	RolloutPhaseHalted RolloutPhase = "Halted"
+	// RolloutPhaseComplete signifies that all spec changes were rolled out.
+	RolloutPhaseComplete RolloutPhase = "Complete"
+)
+
+// RolloutStatus describes the current or last rollout of a ModuleRelease.
+type RolloutStatus struct {
+	// Phase of the rollout
+	Phase RolloutPhase `json:"phase"`
+
+	// Stage is the number of stages released so far, the canary being the first stage
+	// +kubebuilder:validation:Optional
+	Stage int32 `json:"stage,omitempty"`
+
+	// Released lists the Manifests released by the rollout
+	// +kubebuilder:validation:Optional
+	Released []string `json:"released,omitempty"`
+
+	// Pending lists the Manifests whose spec changes are held until a later stage
+	// +kubebuilder:validation:Optional
+	Pending []string `json:"pending,omitempty"`
+
+	// Failed lists the released Manifests in Error state, which count against the error budget
+	// +kubebuilder:validation:Optional
+	Failed []string `json:"failed,omitempty"`
+
+	// StageReadyTime is the time all Manifests of the released stages became Ready, from which the pause
+	// before the next stage is measured
+	// +kubebuilder:validation:Optional
+	StageReadyTime *metav1.Time `json:"stageReadyTime,omitempty"`
+
+	// Message is a human-readable description of the progress of the rollout
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+}
+
+// ModuleReleaseStatus defines the aggregated state of the selected Manifests.
+type ModuleReleaseStatus struct {
+	// State is the worst state of all selected Manifests, ordered Error, Deleting, Processing and Ready.
+	// Manifests with a spec change not observed yet are considered Processing.
+	// It is empty if no Manifest is selected.
+	// +kubebuilder:validation:Optional
+	State ManifestState `json:"state,omitempty"`
+
+	// Message is a human-readable summary of the selected Manifests, naming the Manifests not Ready
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+
+	// Manifests lists the states of all selected Manifests
+	// +kubebuilder:validation:Optional
+	Manifests []ModuleReleaseManifest `json:"manifests,omitempty"`
+
+	// Rollout describes the current or last rollout, if the ModuleRelease has a rollout strategy
+	// +kubebuilder:validation:Optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// ObservedGeneration is the generation of the ModuleRelease the status was aggregated for
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// ModuleReleaseManifest describes the state of a Manifest selected by a ModuleRelease.
+type ModuleReleaseManifest struct {
+	// Name of the Manifest
+	Name string `json:"name"`
+
+	// State of the Manifest
+	// +kubebuilder:validation:Optional
+	State ManifestState `json:"state,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="State",type=string,JSONPath=".status.state"
+//+kubebuilder:printcolumn:name="Rollout",type=string,JSONPath=".status.rollout.phase"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ModuleRelease aggregates the states of the Manifests of a module into a single status,
+// which is only Ready if all selected Manifests are Ready.
+type ModuleRelease struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Spec selects the Manifests of the ModuleRelease
+	Spec ModuleReleaseSpec `json:"spec"`
+
+	// Status signifies the aggregated state of the selected Manifests
+	// +kubebuilder:validation:Optional
+	Status ModuleReleaseStatus `json:"status"`
+}
+
+//+kubebuilder:object:root=true
+
+// ModuleReleaseList contains a list of ModuleRelease.
+type ModuleReleaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []ModuleRelease `json:"items"`
+}
+
+//nolint:gochecknoinits
+func init() {
+	SchemeBuilder.Register(&ModuleRelease{}, &ModuleReleaseList{})
+}
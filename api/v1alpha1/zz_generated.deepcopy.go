@@ -22,13 +22,153 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"github.com/kyma-project/module-manager/pkg/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CatalogChannel) DeepCopyInto(out *CatalogChannel) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogChannel.
+func (in *CatalogChannel) DeepCopy() *CatalogChannel {
+	if in == nil {
+		return nil
+	}
+	out := new(CatalogChannel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CatalogChannelStatus) DeepCopyInto(out *CatalogChannelStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogChannelStatus.
+func (in *CatalogChannelStatus) DeepCopy() *CatalogChannelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CatalogChannelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CatalogHelmSource) DeepCopyInto(out *CatalogHelmSource) {
+	*out = *in
+	if in.CredSecretSelector != nil {
+		in, out := &in.CredSecretSelector, &out.CredSecretSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogHelmSource.
+func (in *CatalogHelmSource) DeepCopy() *CatalogHelmSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CatalogHelmSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CatalogOCISource) DeepCopyInto(out *CatalogOCISource) {
+	*out = *in
+	if in.CredSecretSelector != nil {
+		in, out := &in.CredSecretSelector, &out.CredSecretSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogOCISource.
+func (in *CatalogOCISource) DeepCopy() *CatalogOCISource {
+	if in == nil {
+		return nil
+	}
+	out := new(CatalogOCISource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstallInfo) DeepCopyInto(out *InstallInfo) {
 	*out = *in
 	in.Source.DeepCopyInto(&out.Source)
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]types.ResourceSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SkipResources != nil {
+		in, out := &in.SkipResources, &out.SkipResources
+		*out = make([]types.ResourcePattern, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpgradePolicy != nil {
+		in, out := &in.UpgradePolicy, &out.UpgradePolicy
+		*out = new(types.UpgradePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(types.DeletionPolicy)
+		**out = **in
+	}
+	if in.OverrideSelector != nil {
+		in, out := &in.OverrideSelector, &out.OverrideSelector
+		*out = make([]types.SelectedOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SecretValues != nil {
+		in, out := &in.SecretValues, &out.SecretValues
+		*out = make([]types.SecretValue, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReleaseStorage != nil {
+		in, out := &in.ReleaseStorage, &out.ReleaseStorage
+		*out = new(types.ReleaseStorage)
+		**out = **in
+	}
+	if in.WaitFor != nil {
+		in, out := &in.WaitFor, &out.WaitFor
+		*out = make([]types.WaitFor, len(*in))
+		copy(*out, *in)
+	}
+	if in.HealthRules != nil {
+		in, out := &in.HealthRules, &out.HealthRules
+		*out = make([]types.HealthRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceMappings != nil {
+		in, out := &in.NamespaceMappings, &out.NamespaceMappings
+		*out = make([]types.NamespaceMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Channel != nil {
+		in, out := &in.Channel, &out.Channel
+		*out = new(types.ChannelReference)
+		**out = **in
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(types.BackupPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallInfo.
@@ -138,6 +278,11 @@ func (in *ManifestList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManifestSpec) DeepCopyInto(out *ManifestSpec) {
 	*out = *in
+	if in.RemoteInfo != nil {
+		in, out := &in.RemoteInfo, &out.RemoteInfo
+		*out = new(RemoteInfo)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Config.DeepCopyInto(&out.Config)
 	if in.Installs != nil {
 		in, out := &in.Installs, &out.Installs
@@ -147,7 +292,39 @@ func (in *ManifestSpec) DeepCopyInto(out *ManifestSpec) {
 		}
 	}
 	in.Resource.DeepCopyInto(&out.Resource)
+	if in.ResourceStatusPaths != nil {
+		in, out := &in.ResourceStatusPaths, &out.ResourceStatusPaths
+		*out = new(types.StatusPaths)
+		**out = **in
+	}
 	in.CRDs.DeepCopyInto(&out.CRDs)
+	if in.Transforms != nil {
+		in, out := &in.Transforms, &out.Transforms
+		*out = make([]types.TransformSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resilience != nil {
+		in, out := &in.Resilience, &out.Resilience
+		*out = new(types.ResilienceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretRotation != nil {
+		in, out := &in.SecretRotation, &out.SecretRotation
+		*out = new(types.SecretRotationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.UpgradeWindows != nil {
+		in, out := &in.UpgradeWindows, &out.UpgradeWindows
+		*out = make([]types.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestSpec.
@@ -170,6 +347,64 @@ func (in *ManifestStatus) DeepCopyInto(out *ManifestStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastSuccessfulInstallTime != nil {
+		in, out := &in.LastSuccessfulInstallTime, &out.LastSuccessfulInstallTime
+		*out = (*in).DeepCopy()
+	}
+	if in.SecurityFindings != nil {
+		in, out := &in.SecurityFindings, &out.SecurityFindings
+		*out = make([]types.SecurityFinding, len(*in))
+		copy(*out, *in)
+	}
+	if in.Releases != nil {
+		in, out := &in.Releases, &out.Releases
+		*out = make([]types.ReleaseRevision, len(*in))
+		copy(*out, *in)
+	}
+	if in.Preview != nil {
+		in, out := &in.Preview, &out.Preview
+		*out = make([]types.ResourceDiff, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppliedMigrations != nil {
+		in, out := &in.AppliedMigrations, &out.AppliedMigrations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppliedInstalls != nil {
+		in, out := &in.AppliedInstalls, &out.AppliedInstalls
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstalledVersions != nil {
+		in, out := &in.InstalledVersions, &out.InstalledVersions
+		*out = make([]types.InstalledVersion, len(*in))
+		copy(*out, *in)
+	}
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]types.ResolvedChannel, len(*in))
+		copy(*out, *in)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]types.ResolvedImage, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastApply != nil {
+		in, out := &in.LastApply, &out.LastApply
+		*out = make([]types.InstallApplyStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Backups != nil {
+		in, out := &in.Backups, &out.Backups
+		*out = make([]types.BackupReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestStatus.
@@ -181,3 +416,320 @@ func (in *ManifestStatus) DeepCopy() *ManifestStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleCatalog) DeepCopyInto(out *ModuleCatalog) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleCatalog.
+func (in *ModuleCatalog) DeepCopy() *ModuleCatalog {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleCatalog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ModuleCatalog) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleCatalogList) DeepCopyInto(out *ModuleCatalogList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ModuleCatalog, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleCatalogList.
+func (in *ModuleCatalogList) DeepCopy() *ModuleCatalogList {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleCatalogList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ModuleCatalogList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleCatalogSpec) DeepCopyInto(out *ModuleCatalogSpec) {
+	*out = *in
+	if in.Helm != nil {
+		in, out := &in.Helm, &out.Helm
+		*out = new(CatalogHelmSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(CatalogOCISource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]CatalogChannel, len(*in))
+		copy(*out, *in)
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleCatalogSpec.
+func (in *ModuleCatalogSpec) DeepCopy() *ModuleCatalogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleCatalogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleCatalogStatus) DeepCopyInto(out *ModuleCatalogStatus) {
+	*out = *in
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]CatalogChannelStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleCatalogStatus.
+func (in *ModuleCatalogStatus) DeepCopy() *ModuleCatalogStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleCatalogStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleRelease) DeepCopyInto(out *ModuleRelease) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleRelease.
+func (in *ModuleRelease) DeepCopy() *ModuleRelease {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleRelease)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ModuleRelease) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleReleaseList) DeepCopyInto(out *ModuleReleaseList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ModuleRelease, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleReleaseList.
+func (in *ModuleReleaseList) DeepCopy() *ModuleReleaseList {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleReleaseList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ModuleReleaseList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleReleaseManifest) DeepCopyInto(out *ModuleReleaseManifest) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleReleaseManifest.
+func (in *ModuleReleaseManifest) DeepCopy() *ModuleReleaseManifest {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleReleaseManifest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleReleaseSpec) DeepCopyInto(out *ModuleReleaseSpec) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleReleaseSpec.
+func (in *ModuleReleaseSpec) DeepCopy() *ModuleReleaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleReleaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleReleaseStatus) DeepCopyInto(out *ModuleReleaseStatus) {
+	*out = *in
+	if in.Manifests != nil {
+		in, out := &in.Manifests, &out.Manifests
+		*out = make([]ModuleReleaseManifest, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleReleaseStatus.
+func (in *ModuleReleaseStatus) DeepCopy() *ModuleReleaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleReleaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteInfo) DeepCopyInto(out *RemoteInfo) {
+	*out = *in
+	if in.ImpersonateGroups != nil {
+		in, out := &in.ImpersonateGroups, &out.ImpersonateGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteInfo.
+func (in *RemoteInfo) DeepCopy() *RemoteInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	if in.Released != nil {
+		in, out := &in.Released, &out.Released
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pending != nil {
+		in, out := &in.Pending, &out.Pending
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Failed != nil {
+		in, out := &in.Failed, &out.Failed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StageReadyTime != nil {
+		in, out := &in.StageReadyTime, &out.StageReadyTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategy) DeepCopyInto(out *RolloutStrategy) {
+	*out = *in
+	if in.Pause != nil {
+		in, out := &in.Pause, &out.Pause
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategy.
+func (in *RolloutStrategy) DeepCopy() *RolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,328 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+var (
+	ErrUnsupportedHub        = errors.New("unsupported conversion hub")
+	ErrAmbiguousChartSource  = errors.New("chart source must set exactly one of helm, oci, kustomize, archive or git")
+	ErrUnsupportedSourceType = errors.New("unsupported chart source type")
+)
+
+// SetupWebhookWithManager registers the conversion webhook converting Manifests between v1beta1
+// and the v1alpha1 hub, which is served on /convert.
+func (m *Manifest) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(m).
+		Complete()
+}
+
+var _ conversion.Convertible = &Manifest{}
+
+// ConvertTo converts the Manifest to the v1alpha1 hub version.
+func (m *Manifest) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.Manifest)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrUnsupportedHub, dstRaw)
+	}
+	src := m.DeepCopy()
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Status = src.Status
+
+	installs := make([]v1alpha1.InstallInfo, 0, len(src.Spec.Installs))
+	for _, install := range src.Spec.Installs {
+		source, err := install.Source.toRawExtension()
+		if err != nil {
+			return fmt.Errorf("converting source of install %s: %w", install.Name, err)
+		}
+		installs = append(installs, v1alpha1.InstallInfo{
+			Source:                source,
+			Name:                  install.Name,
+			NamespaceCreatePolicy: install.NamespaceCreatePolicy,
+			ConflictPolicy:        install.ConflictPolicy,
+			MissingAPIPolicy:      install.MissingAPIPolicy,
+			Exclude:               install.Exclude,
+			SkipResources:         install.SkipResources,
+			UpgradePolicy:         install.UpgradePolicy,
+			HookPolicy:            install.HookPolicy,
+			DeletionPolicy:        install.DeletionPolicy,
+			OverrideSelector:      install.OverrideSelector,
+			SecretValues:          install.SecretValues,
+			ReleaseStorage:        install.ReleaseStorage,
+			WaitFor:               install.WaitFor,
+			HealthRules:           install.HealthRules,
+			AdoptExisting:         install.AdoptExisting,
+			TargetNamespace:       install.TargetNamespace,
+			NamespaceMappings:     install.NamespaceMappings,
+			Channel:               install.Channel,
+			Backup:                install.Backup,
+		})
+	}
+
+	var config types.ImageSpec
+	if src.Spec.Overrides != nil {
+		config = src.Spec.Overrides.Image.toImageSpec()
+	}
+	dst.Spec = v1alpha1.ManifestSpec{
+		Remote:              src.Spec.Remote,
+		RemoteInfo:          src.Spec.RemoteInfo,
+		Config:              config,
+		Installs:            installs,
+		InstallOrder:        src.Spec.InstallOrder,
+		Profile:             src.Spec.Profile,
+		Resource:            src.Spec.Resource,
+		ResourceStatusPaths: src.Spec.ResourceStatusPaths,
+		CRDs:                src.Spec.CRDs.toImageSpec(),
+		CRDPolicy:           src.Spec.CRDPolicy,
+		Transforms:          src.Spec.Transforms,
+		Resilience:          src.Spec.Resilience,
+		SecretRotation:      src.Spec.SecretRotation,
+		Timeout:             src.Spec.Timeout,
+		UpgradeWindows:      src.Spec.UpgradeWindows,
+		Channel:             src.Spec.Channel,
+	}
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 hub version to the Manifest.
+func (m *Manifest) ConvertFrom(srcRaw conversion.Hub) error {
+	hub, ok := srcRaw.(*v1alpha1.Manifest)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrUnsupportedHub, srcRaw)
+	}
+	src := hub.DeepCopy()
+	m.ObjectMeta = src.ObjectMeta
+	m.Status = src.Status
+
+	installs := make([]InstallInfo, 0, len(src.Spec.Installs))
+	for _, install := range src.Spec.Installs {
+		source, err := chartSourceFromRawExtension(install.Source)
+		if err != nil {
+			return fmt.Errorf("converting source of install %s: %w", install.Name, err)
+		}
+		installs = append(installs, InstallInfo{
+			Source:                source,
+			Name:                  install.Name,
+			NamespaceCreatePolicy: install.NamespaceCreatePolicy,
+			ConflictPolicy:        install.ConflictPolicy,
+			MissingAPIPolicy:      install.MissingAPIPolicy,
+			Exclude:               install.Exclude,
+			SkipResources:         install.SkipResources,
+			UpgradePolicy:         install.UpgradePolicy,
+			HookPolicy:            install.HookPolicy,
+			DeletionPolicy:        install.DeletionPolicy,
+			OverrideSelector:      install.OverrideSelector,
+			SecretValues:          install.SecretValues,
+			ReleaseStorage:        install.ReleaseStorage,
+			WaitFor:               install.WaitFor,
+			HealthRules:           install.HealthRules,
+			AdoptExisting:         install.AdoptExisting,
+			TargetNamespace:       install.TargetNamespace,
+			NamespaceMappings:     install.NamespaceMappings,
+			Channel:               install.Channel,
+			Backup:                install.Backup,
+		})
+	}
+
+	var overrides *Overrides
+	if image := ociSourceFromImageSpec(src.Spec.Config); image != nil {
+		overrides = &Overrides{Image: image}
+	}
+	m.Spec = ManifestSpec{
+		Remote:              src.Spec.Remote,
+		RemoteInfo:          src.Spec.RemoteInfo,
+		Overrides:           overrides,
+		Installs:            installs,
+		InstallOrder:        src.Spec.InstallOrder,
+		Profile:             src.Spec.Profile,
+		Resource:            src.Spec.Resource,
+		ResourceStatusPaths: src.Spec.ResourceStatusPaths,
+		CRDs:                ociSourceFromImageSpec(src.Spec.CRDs),
+		CRDPolicy:           src.Spec.CRDPolicy,
+		Transforms:          src.Spec.Transforms,
+		Resilience:          src.Spec.Resilience,
+		SecretRotation:      src.Spec.SecretRotation,
+		Timeout:             src.Spec.Timeout,
+		UpgradeWindows:      src.Spec.UpgradeWindows,
+		Channel:             src.Spec.Channel,
+	}
+	return nil
+}
+
+// toRawExtension returns the v1alpha1 source of the install, whose type is derived from the set source.
+// An empty source results in an empty v1alpha1 source.
+func (s ChartSource) toRawExtension() (runtime.RawExtension, error) {
+	var spec any
+	switch {
+	case s.isAmbiguous():
+		return runtime.RawExtension{}, ErrAmbiguousChartSource
+	case s.Helm != nil:
+		spec = types.HelmChartSpec{
+			URL:                s.Helm.URL,
+			ChartName:          s.Helm.Chart,
+			Version:            s.Helm.Version,
+			CredSecretSelector: s.Helm.CredSecretSelector,
+			Digest:             s.Helm.Digest,
+			Provenance:         s.Helm.Provenance,
+			Type:               types.HelmChartType,
+		}
+	case s.OCI != nil:
+		spec = s.OCI.toImageSpec()
+	case s.Kustomize != nil:
+		spec = types.KustomizeSpec{Path: s.Kustomize.Path, URL: s.Kustomize.URL, Type: types.KustomizeType}
+	case s.Archive != nil:
+		spec = types.ArchiveSpec{
+			URL:                s.Archive.URL,
+			Digest:             s.Archive.Digest,
+			Region:             s.Archive.Region,
+			Endpoint:           s.Archive.Endpoint,
+			CredSecretSelector: s.Archive.CredSecretSelector,
+			Type:               types.ArchiveType,
+		}
+	case s.Git != nil:
+		spec = types.GitSpec{
+			URL:                s.Git.URL,
+			Revision:           s.Git.Revision,
+			Path:               s.Git.Path,
+			CredSecretSelector: s.Git.CredSecretSelector,
+			Type:               types.GitType,
+		}
+	default:
+		return runtime.RawExtension{}, nil
+	}
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return runtime.RawExtension{}, err
+	}
+	return runtime.RawExtension{Raw: raw}, nil
+}
+
+func (s ChartSource) isAmbiguous() bool {
+	set := 0
+	for _, source := range []bool{s.Helm != nil, s.OCI != nil, s.Kustomize != nil, s.Archive != nil, s.Git != nil} {
+		if source {
+			set++
+		}
+	}
+	return set > 1
+}
+
+// chartSourceFromRawExtension returns the ChartSource of a v1alpha1 source by its type.
+func chartSourceFromRawExtension(raw runtime.RawExtension) (ChartSource, error) {
+	if len(raw.Raw) == 0 {
+		return ChartSource{}, nil
+	}
+	refType, err := types.GetSpecType(raw.Raw)
+	if err != nil {
+		return ChartSource{}, err
+	}
+	switch refType {
+	case types.HelmChartType:
+		spec := types.HelmChartSpec{}
+		if err := json.Unmarshal(raw.Raw, &spec); err != nil {
+			return ChartSource{}, err
+		}
+		return ChartSource{Helm: &HelmChartSource{
+			URL:                spec.URL,
+			Chart:              spec.ChartName,
+			Version:            spec.Version,
+			CredSecretSelector: spec.CredSecretSelector,
+			Digest:             spec.Digest,
+			Provenance:         spec.Provenance,
+		}}, nil
+	case types.OciRefType:
+		spec := types.ImageSpec{}
+		if err := json.Unmarshal(raw.Raw, &spec); err != nil {
+			return ChartSource{}, err
+		}
+		return ChartSource{OCI: ociSourceFromImageSpec(spec)}, nil
+	case types.KustomizeType:
+		spec := types.KustomizeSpec{}
+		if err := json.Unmarshal(raw.Raw, &spec); err != nil {
+			return ChartSource{}, err
+		}
+		return ChartSource{Kustomize: &KustomizeSource{Path: spec.Path, URL: spec.URL}}, nil
+	case types.ArchiveType:
+		spec := types.ArchiveSpec{}
+		if err := json.Unmarshal(raw.Raw, &spec); err != nil {
+			return ChartSource{}, err
+		}
+		return ChartSource{Archive: &ArchiveSource{
+			URL:                spec.URL,
+			Digest:             spec.Digest,
+			Region:             spec.Region,
+			Endpoint:           spec.Endpoint,
+			CredSecretSelector: spec.CredSecretSelector,
+		}}, nil
+	case types.GitType:
+		spec := types.GitSpec{}
+		if err := json.Unmarshal(raw.Raw, &spec); err != nil {
+			return ChartSource{}, err
+		}
+		return ChartSource{Git: &GitSource{
+			URL:                spec.URL,
+			Revision:           spec.Revision,
+			Path:               spec.Path,
+			CredSecretSelector: spec.CredSecretSelector,
+		}}, nil
+	}
+	return ChartSource{}, fmt.Errorf("%w: %q", ErrUnsupportedSourceType, refType)
+}
+
+// toImageSpec returns the v1alpha1 types.ImageSpec of the OCISource, which is empty if the source is not set.
+func (s *OCISource) toImageSpec() types.ImageSpec {
+	if s == nil {
+		return types.ImageSpec{}
+	}
+	return types.ImageSpec{
+		Repo:               s.Registry,
+		Name:               s.Name,
+		Ref:                s.Ref,
+		Tag:                s.Tag,
+		Type:               types.OciRefType,
+		MediaType:          s.MediaType,
+		CredSecretSelector: s.CredSecretSelector,
+	}
+}
+
+// ociSourceFromImageSpec returns the OCISource of a v1alpha1 types.ImageSpec, or nil if its type is not set,
+// as images without type are not processed.
+func ociSourceFromImageSpec(spec types.ImageSpec) *OCISource {
+	if !spec.Type.NotEmpty() {
+		return nil
+	}
+	return &OCISource{
+		Registry:           spec.Repo,
+		Name:               spec.Name,
+		Ref:                spec.Ref,
+		Tag:                spec.Tag,
+		MediaType:          spec.MediaType,
+		CredSecretSelector: spec.CredSecretSelector,
+	}
+}
@@ -0,0 +1,162 @@
+package v1beta1_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/api/v1beta1"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+func TestManifestIsConvertible(t *testing.T) {
+	t.Parallel()
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	convertible, err := conversion.IsConvertible(scheme, &v1beta1.Manifest{})
+	require.NoError(t, err)
+	assert.True(t, convertible)
+}
+
+func TestManifestConversionRoundTrip(t *testing.T) {
+	t.Parallel()
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"operator.kyma-project.io/managed-by": "lifecycle"}}
+	manifest := &v1beta1.Manifest{
+		ObjectMeta: metav1.ObjectMeta{Name: "sample", Namespace: "default", Labels: map[string]string{"a": "b"}},
+		Spec: v1beta1.ManifestSpec{
+			Remote:     true,
+			RemoteInfo: &v1beta1.RemoteInfo{ImpersonateUser: "deployer"},
+			Overrides: &v1beta1.Overrides{Image: &v1beta1.OCISource{
+				Registry: "registry.example.com/modules", Name: "redis-config", Ref: "sha256:config",
+			}},
+			Installs: []v1beta1.InstallInfo{
+				{
+					Name: "redis",
+					Source: v1beta1.ChartSource{OCI: &v1beta1.OCISource{
+						Registry: "registry.example.com/modules", Name: "redis", Ref: "sha256:chart", Tag: "1.0.0",
+						CredSecretSelector: selector,
+					}},
+					HookPolicy:     types.HookPolicyRun,
+					DeletionPolicy: &types.DeletionPolicy{Order: types.DeletionOrderReverse},
+					OverrideSelector: []types.SelectedOverride{{
+						Selector: metav1.LabelSelector{MatchLabels: map[string]string{"environment": "production"}},
+						Values:   apiextensionsv1.JSON{Raw: []byte(`{"replicaCount":3}`)},
+					}},
+					ReleaseStorage: &types.ReleaseStorage{Namespace: "redis-system", Driver: types.ReleaseStorageConfigMap},
+					WaitFor: []types.WaitFor{{
+						APIVersion: "operator.kyma-project.io/v1alpha1", Kind: "Istio", Name: "default",
+						Namespace: "kyma-system", JSONPath: ".status.state", Value: "Ready",
+					}},
+					HealthRules: []types.HealthRule{{
+						APIVersion: "apps/v1", Kind: "StatefulSet", Name: "redis",
+						Expression: "object.status.readyReplicas == object.spec.replicas",
+					}},
+					AdoptExisting:   true,
+					TargetNamespace: "redis",
+					NamespaceMappings: []types.NamespaceMapping{{
+						Namespace: "redis-system",
+						Resources: []types.ResourcePattern{{Group: "admissionregistration.k8s.io"}},
+					}},
+					Channel: &types.ChannelReference{Catalog: "redis", Channel: "regular"},
+					Backup:  &types.BackupPolicy{Driver: types.BackupStorageConfigMap},
+				},
+				{
+					Name: "nginx",
+					Source: v1beta1.ChartSource{Helm: &v1beta1.HelmChartSource{
+						URL: "https://helm.nginx.com/stable", Chart: "nginx-ingress", Version: ">=0.15.0",
+						Digest: "sha256:0123456789abcdef",
+						Provenance: &types.ProvenanceVerification{KeyringSecretRef: corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "nginx-keyring"}, Key: "pubring.gpg",
+						}},
+					}},
+				},
+				{
+					Name:   "samples",
+					Source: v1beta1.ChartSource{Kustomize: &v1beta1.KustomizeSource{URL: "https://example.com/samples"}},
+				},
+				{
+					Name: "dashboards",
+					Source: v1beta1.ChartSource{Archive: &v1beta1.ArchiveSource{
+						URL: "s3://modules/dashboards-1.0.0.tgz", Digest: "sha256:fedcba9876543210", Region: "eu-west-1",
+						CredSecretSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"bucket": "modules"}},
+					}},
+				},
+				{
+					Name: "development",
+					Source: v1beta1.ChartSource{Git: &v1beta1.GitSource{
+						URL: "https://github.com/kyma-project/module-manager.git", Revision: "main", Path: "config/samples",
+					}},
+				},
+			},
+			InstallOrder: v1alpha1.InstallOrderSequential,
+			CRDs:         &v1beta1.OCISource{Registry: "registry.example.com/modules", Name: "crds", Ref: "sha256:crds"},
+			CRDPolicy:    types.CRDPolicyDelete,
+			Timeout:      &metav1.Duration{Duration: time.Minute},
+			UpgradeWindows: []types.MaintenanceWindow{
+				{Schedule: "0 2 * * SAT", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+			},
+			Channel: "fast",
+		},
+		Status: v1beta1.ManifestStatus{State: v1alpha1.ManifestStateReady, AppliedInstalls: []string{"redis"}},
+	}
+
+	hub := &v1alpha1.Manifest{}
+	require.NoError(t, manifest.ConvertTo(hub))
+	assert.Equal(t, manifest.ObjectMeta, hub.ObjectMeta)
+	assert.Equal(t, manifest.Status, hub.Status)
+	assert.Equal(t, types.ImageSpec{
+		Repo: "registry.example.com/modules", Name: "redis-config", Ref: "sha256:config", Type: types.OciRefType,
+	}, hub.Spec.Config)
+	assert.Equal(t, types.OciRefType, hub.Spec.CRDs.Type)
+	require.Len(t, hub.Spec.Installs, 5)
+	helmSpec := types.HelmChartSpec{}
+	require.NoError(t, json.Unmarshal(hub.Spec.Installs[1].Source.Raw, &helmSpec))
+	assert.Equal(t, types.HelmChartSpec{
+		URL: "https://helm.nginx.com/stable", ChartName: "nginx-ingress", Version: ">=0.15.0", Type: types.HelmChartType,
+		Digest: "sha256:0123456789abcdef", Provenance: manifest.Spec.Installs[1].Source.Helm.Provenance,
+	}, helmSpec)
+
+	converted := &v1beta1.Manifest{}
+	require.NoError(t, converted.ConvertFrom(hub))
+	assert.Equal(t, manifest, converted)
+}
+
+func TestManifestConversionEmptySources(t *testing.T) {
+	t.Parallel()
+	hub := &v1alpha1.Manifest{Spec: v1alpha1.ManifestSpec{
+		Installs: []v1alpha1.InstallInfo{{Name: "empty"}},
+	}}
+	converted := &v1beta1.Manifest{}
+	require.NoError(t, converted.ConvertFrom(hub))
+	assert.Nil(t, converted.Spec.Overrides, "images without type are not converted")
+	assert.Nil(t, converted.Spec.CRDs)
+	assert.Equal(t, v1beta1.ChartSource{}, converted.Spec.Installs[0].Source)
+}
+
+func TestManifestConversionInvalidSources(t *testing.T) {
+	t.Parallel()
+	ambiguous := &v1beta1.Manifest{Spec: v1beta1.ManifestSpec{Installs: []v1beta1.InstallInfo{{
+		Name: "ambiguous",
+		Source: v1beta1.ChartSource{
+			Helm:      &v1beta1.HelmChartSource{URL: "https://helm.nginx.com/stable", Chart: "nginx-ingress"},
+			Kustomize: &v1beta1.KustomizeSource{Path: "./samples"},
+		},
+	}}}}
+	assert.ErrorIs(t, ambiguous.ConvertTo(&v1alpha1.Manifest{}), v1beta1.ErrAmbiguousChartSource)
+
+	unsupported := &v1alpha1.Manifest{Spec: v1alpha1.ManifestSpec{Installs: []v1alpha1.InstallInfo{{
+		Name:   "unsupported",
+		Source: runtime.RawExtension{Raw: []byte(`{"type":"svn"}`)},
+	}}}}
+	assert.ErrorIs(t, (&v1beta1.Manifest{}).ConvertFrom(unsupported), v1beta1.ErrUnsupportedSourceType)
+}
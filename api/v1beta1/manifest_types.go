@@ -0,0 +1,397 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// RemoteInfo defines the identity used to apply resources to a remote cluster, unchanged from v1alpha1.
+type RemoteInfo = v1alpha1.RemoteInfo
+
+// InstallOrder determines how the Installs of a Manifest are processed, unchanged from v1alpha1.
+type InstallOrder = v1alpha1.InstallOrder
+
+// ManifestStatus defines the observed state of Manifest, unchanged from v1alpha1.
+type ManifestStatus = v1alpha1.ManifestStatus
+
+// OCISource locates a layer of an OCI image.
+type OCISource struct {
+	// Registry is the repository of the image in its registry, e.g. "europe-docker.pkg.dev/kyma/modules"
+	Registry string `json:"registry"`
+
+	// Name is the name of the image
+	Name string `json:"name"`
+
+	// Ref is the digest of the layer, a tag or a version
+	Ref string `json:"ref"`
+
+	// Tag is the tag of the image the install follows instead of Ref, see the Tag of the v1alpha1 ImageSpec.
+	// It is only followed by the sources of installs.
+	// +kubebuilder:validation:Optional
+	Tag string `json:"tag,omitempty"`
+
+	// MediaType is the media type of the layer in the OCI descriptor, which determines how the layer is unpacked.
+	// If not set, it is detected from the content of the layer.
+	// +kubebuilder:validation:Optional
+	MediaType string `json:"mediaType,omitempty"`
+
+	// CredSecretSelector selects the secret with the registry credentials for images of private registries,
+	// which must exist in the namespace of the Manifest
+	// +kubebuilder:validation:Optional
+	CredSecretSelector *metav1.LabelSelector `json:"credSecretSelector,omitempty"`
+}
+
+// HelmChartSource locates a chart of a Helm repository.
+type HelmChartSource struct {
+	// URL is the URL of the Helm repository
+	URL string `json:"url"`
+
+	// Chart is the name of the chart in the repository
+	Chart string `json:"chart"`
+
+	// Version is an exact version or a semver range of the chart, e.g. ">=1.2.0 <2.0.0", where the latest
+	// matching version is used. If not set, the latest stable version is used.
+	// +kubebuilder:validation:Optional
+	Version string `json:"version,omitempty"`
+
+	// CredSecretSelector selects the secret with the basic auth credentials and the CA or client certificate
+	// for charts of private repositories, which must exist in the namespace of the Manifest
+	// +kubebuilder:validation:Optional
+	CredSecretSelector *metav1.LabelSelector `json:"credSecretSelector,omitempty"`
+
+	// Digest is the sha256 digest of the chart archive, e.g. "sha256:<hex>", which the downloaded archive has to match
+	// +kubebuilder:validation:Optional
+	Digest string `json:"digest,omitempty"`
+
+	// Provenance verifies the provenance file published next to the chart archive, as signed by helm package --sign
+	// +kubebuilder:validation:Optional
+	Provenance *types.ProvenanceVerification `json:"provenance,omitempty"`
+}
+
+// KustomizeSource locates a Kustomization.
+type KustomizeSource struct {
+	// Path is the local path of the Kustomization
+	// +kubebuilder:validation:Optional
+	Path string `json:"path,omitempty"`
+
+	// URL is the remote URL of the Kustomization
+	// +kubebuilder:validation:Optional
+	URL string `json:"url,omitempty"`
+}
+
+// ArchiveSource locates a tarball of a chart or of a directory of manifests, or a single manifest file,
+// which is downloaded from an HTTP(S) URL or from an S3 or GCS bucket.
+type ArchiveSource struct {
+	// URL of the archive, e.g. "https://example.com/charts/redis-1.2.0.tgz", "s3://bucket/redis-1.2.0.tgz"
+	// or "gs://bucket/redis-1.2.0.tgz"
+	// +kubebuilder:validation:Pattern=`^(https?|s3|gs)://.+`
+	URL string `json:"url"`
+
+	// Digest is the sha256 digest of the archive, e.g. "sha256:<hex>", which the downloaded archive has to match.
+	// Archives with a digest are downloaded only once, all others again once their cached download expired.
+	// +kubebuilder:validation:Optional
+	Digest string `json:"digest,omitempty"`
+
+	// Region of the S3 bucket, "us-east-1" if not set
+	// +kubebuilder:validation:Optional
+	Region string `json:"region,omitempty"`
+
+	// Endpoint of an S3 compatible storage, e.g. "https://minio.example.com", whose buckets are addressed
+	// path-style. If not set, the endpoint of AWS S3 in Region is used.
+	// +kubebuilder:validation:Optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// CredSecretSelector selects the secret with the credentials of the archive, which must exist in the namespace
+	// of the Manifest. HTTP(S) downloads use basic auth, a bearer token and the CA or client certificate,
+	// S3 and GCS downloads an access key signing the request or a bearer token
+	// +kubebuilder:validation:Optional
+	CredSecretSelector *metav1.LabelSelector `json:"credSecretSelector,omitempty"`
+}
+
+// GitSource locates a chart or a directory of manifests in a Git repository.
+type GitSource struct {
+	// URL of the repository, e.g. "https://github.com/kyma-project/module-manager.git"
+	// or "ssh://git@github.com/kyma-project/module-manager.git"
+	URL string `json:"url"`
+
+	// Revision is a commit, tag or branch of the repository. Commits are given as their full SHA-1 and pin the
+	// revision, tags and branches are resolved again once their cached commit expired.
+	// If not set, the default branch of the repository is used.
+	// +kubebuilder:validation:Optional
+	Revision string `json:"revision,omitempty"`
+
+	// Path of the chart or the manifests in the repository, the root of the repository if not set
+	// +kubebuilder:validation:Optional
+	Path string `json:"path,omitempty"`
+
+	// CredSecretSelector selects the secret with the credentials of the repository, which must exist in the namespace
+	// of the Manifest. HTTPS repositories use basic auth, SSH repositories the private key and known hosts
+	// +kubebuilder:validation:Optional
+	CredSecretSelector *metav1.LabelSelector `json:"credSecretSelector,omitempty"`
+}
+
+// ChartSource locates the chart of an install. Exactly one of its sources must be set.
+// +kubebuilder:validation:MinProperties=1
+// +kubebuilder:validation:MaxProperties=1
+type ChartSource struct {
+	// Helm is a chart of a Helm repository
+	// +kubebuilder:validation:Optional
+	Helm *HelmChartSource `json:"helm,omitempty"`
+
+	// OCI is a chart packaged as a layer of an OCI image
+	// +kubebuilder:validation:Optional
+	OCI *OCISource `json:"oci,omitempty"`
+
+	// Kustomize is a Kustomization
+	// +kubebuilder:validation:Optional
+	Kustomize *KustomizeSource `json:"kustomize,omitempty"`
+
+	// Archive is a tarball of a chart or of manifests, downloaded from an HTTP(S) URL or from an S3 or GCS bucket
+	// +kubebuilder:validation:Optional
+	Archive *ArchiveSource `json:"archive,omitempty"`
+
+	// Git is a chart or a directory of manifests in a Git repository, installed without packaging
+	// +kubebuilder:validation:Optional
+	Git *GitSource `json:"git,omitempty"`
+}
+
+// Overrides locates the configuration and values of the installs of a Manifest.
+type Overrides struct {
+	// Image is the OCI image whose layer lists the config flags and values of each install by its name,
+	// and optionally the Kubernetes versions supported by the module
+	// +kubebuilder:validation:Optional
+	Image *OCISource `json:"image,omitempty"`
+}
+
+// InstallInfo defines installation information.
+type InstallInfo struct {
+	// Source locates the chart of the install
+	Source ChartSource `json:"source"`
+
+	// Name specifies a unique install name for Manifest, which is used as name of its release
+	Name string `json:"name"`
+
+	// NamespaceCreatePolicy determines how the target namespace of the install is managed.
+	// CreateIfMissing creates a missing namespace, MustExist fails the install if the namespace is missing,
+	// and CreateAndDelete additionally deletes the namespace on uninstall, unless it existed before the install.
+	// +kubebuilder:validation:Optional
+	NamespaceCreatePolicy types.NamespaceCreatePolicy `json:"namespaceCreatePolicy,omitempty"`
+
+	// ConflictPolicy determines how conflicts on the field ownership of resources applied with server-side apply
+	// are resolved. If not set, the ownership is forced.
+	// +kubebuilder:validation:Optional
+	ConflictPolicy types.ConflictPolicy `json:"conflictPolicy,omitempty"`
+
+	// MissingAPIPolicy determines how rendered resources of APIs not served by the target cluster are handled.
+	// If not set, the install fails.
+	// +kubebuilder:validation:Optional
+	MissingAPIPolicy types.MissingAPIPolicy `json:"missingAPIPolicy,omitempty"`
+
+	// Exclude lists selectors of rendered resources, which are not applied.
+	// +kubebuilder:validation:Optional
+	Exclude []types.ResourceSelector `json:"exclude,omitempty"`
+
+	// SkipResources lists patterns of rendered resources, which are neither applied nor recorded in the inventory.
+	// +kubebuilder:validation:Optional
+	SkipResources []types.ResourcePattern `json:"skipResources,omitempty"`
+
+	// UpgradePolicy refuses changes of the chart version, which skip mandatory versions or downgrade
+	// the version installed last. If not set, all version changes are allowed.
+	// +kubebuilder:validation:Optional
+	UpgradePolicy *types.UpgradePolicy `json:"upgradePolicy,omitempty"`
+
+	// HookPolicy determines how the Helm hooks of the chart are handled. If not set, hooks are skipped.
+	// +kubebuilder:validation:Optional
+	HookPolicy types.HookPolicy `json:"hookPolicy,omitempty"`
+
+	// DeletionPolicy configures the order of deletions, the propagation to dependents and if the uninstall
+	// waits for the termination of all resources. If not set, all resources are deleted at once in the background.
+	// +kubebuilder:validation:Optional
+	DeletionPolicy *types.DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// OverrideSelector lists blocks of values, which are merged into the values of the install if their selector
+	// matches the runtime labels of the install. Later overrides take precedence over earlier ones.
+	// +kubebuilder:validation:Optional
+	OverrideSelector []types.SelectedOverride `json:"overrideSelector,omitempty"`
+
+	// SecretValues set chart values to keys of Secrets in the namespace of the Manifest, which are resolved
+	// whenever the install is rendered and take precedence over all other values.
+	// +kubebuilder:validation:Optional
+	SecretValues []types.SecretValue `json:"secretValues,omitempty"`
+
+	// ReleaseStorage configures the namespace, the kind of resource and the impersonated ServiceAccount
+	// the release history of the install is stored with in the target cluster, e.g. for read-only or multi-tenant
+	// target clusters. If not set, it is stored as a Secret next to the inventory of the install.
+	// +kubebuilder:validation:Optional
+	ReleaseStorage *types.ReleaseStorage `json:"releaseStorage,omitempty"`
+
+	// WaitFor lists objects in the target cluster, which have to satisfy their expectation before the resources
+	// of the install are applied, e.g. custom resources of other modules the install depends on.
+	// +kubebuilder:validation:Optional
+	WaitFor []types.WaitFor `json:"waitFor,omitempty"`
+
+	// HealthRules evaluate the health of applied resources of the install by CEL expressions,
+	// in addition to the built-in health checks of workloads.
+	// +kubebuilder:validation:Optional
+	HealthRules []types.HealthRule `json:"healthRules,omitempty"`
+
+	// AdoptExisting allows the first apply of the install to adopt rendered resources, which already exist in
+	// the target cluster without being managed by the operator, instead of failing.
+	// +kubebuilder:validation:Optional
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+
+	// TargetNamespace overrides the namespace of all rendered namespaced resources of the install, including those
+	// rendered into explicit namespaces, and takes precedence over the Namespace of the install config.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+
+	// NamespaceMappings split the rendered namespaced resources of the install across namespaces, e.g. to move
+	// webhooks into a system namespace. Resources matching the patterns of a mapping are moved to its namespace,
+	// which has to exist, all other resources stay in the target namespace. The install fails with reason
+	// NamespaceConflict if a resource matches mappings of different namespaces, or if resources of the same kind
+	// and name end up in the same namespace.
+	// +kubebuilder:validation:Optional
+	NamespaceMappings []types.NamespaceMapping `json:"namespaceMappings,omitempty"`
+
+	// Channel references a channel of a ModuleCatalog in the namespace of the Manifest, which resolves the
+	// version of the chart of a Helm source instead of a pinned version. The install fails with reason
+	// ChannelNotResolved as long as the catalog does not resolve the channel to a version.
+	// +kubebuilder:validation:Optional
+	Channel *types.ChannelReference `json:"channel,omitempty"`
+
+	// Backup snapshots the live resources of the install before an upgrade overwrites them, so that a failed upgrade
+	// can be inspected or restored manually. The snapshot is stored as multi-document YAML in a Secret or ConfigMap
+	// in the target cluster, which is referenced by the backups in the status and removed on uninstall.
+	// Upgrades fail with reason BackupFailed as long as the snapshot cannot be stored.
+	// +kubebuilder:validation:Optional
+	Backup *types.BackupPolicy `json:"backup,omitempty"`
+}
+
+// ManifestSpec defines the specification of Manifest.
+type ManifestSpec struct {
+	// Remote indicates if Manifest should be installed on a remote cluster
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=true
+	Remote bool `json:"remote"`
+
+	// RemoteInfo configures how resources are applied to the remote cluster, if Remote is enabled
+	// +kubebuilder:validation:Optional
+	RemoteInfo *RemoteInfo `json:"remoteInfo,omitempty"`
+
+	// Overrides locates the configuration and values of the installs
+	// +kubebuilder:validation:Optional
+	Overrides *Overrides `json:"overrides,omitempty"`
+
+	// Installs specifies a list of installations for Manifest
+	Installs []InstallInfo `json:"installs"`
+
+	// InstallOrder determines if Installs are processed in parallel or sequentially in their listed order.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Parallel
+	InstallOrder InstallOrder `json:"installOrder,omitempty"`
+
+	// Profile selects the preset of values bundled with the chart of each install as profile-<profile>.yaml.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Profile string `json:"profile,omitempty"`
+
+	//+kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	// Resource specifies a resource to be watched for state updates
+	Resource unstructured.Unstructured `json:"resource"`
+
+	// ResourceStatusPaths locate the state of Resource, if it does not report its state in `.status.state`
+	// +kubebuilder:validation:Optional
+	ResourceStatusPaths *types.StatusPaths `json:"resourceStatusPaths,omitempty"`
+
+	// CRDs locates the image layer of the custom resource definitions installed before all installs
+	// +kubebuilder:validation:Optional
+	CRDs *OCISource `json:"crds,omitempty"`
+
+	// CRDPolicy determines if the CustomResourceDefinitions of CRDs and of the installed charts are removed
+	// when the Manifest is deleted.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Keep
+	CRDPolicy types.CRDPolicy `json:"crdPolicy,omitempty"`
+
+	// Transforms specifies a list of transformations executed in order on all rendered resources
+	// before they are applied
+	// +kubebuilder:validation:Optional
+	Transforms []types.TransformSpec `json:"transforms,omitempty"`
+
+	// Resilience injects PodDisruptionBudgets and optionally topology spread constraints for highly available
+	// Deployments of all installs, which do not define them already.
+	// +kubebuilder:validation:Optional
+	Resilience *types.ResilienceSpec `json:"resilience,omitempty"`
+
+	// SecretRotation restarts the workloads of all installs consuming rendered Secrets, whose data changed.
+	// +kubebuilder:validation:Optional
+	SecretRotation *types.SecretRotationSpec `json:"secretRotation,omitempty"`
+
+	// Timeout limits the duration of a single install, uninstall or consistency check of each install.
+	// +kubebuilder:validation:Optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// UpgradeWindows restrict changes of the chart version of installed charts to recurring maintenance windows.
+	// +kubebuilder:validation:Optional
+	UpgradeWindows []types.MaintenanceWindow `json:"upgradeWindows,omitempty"`
+
+	// Channel is the channel of all installs referencing a ModuleCatalog without naming a channel.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Channel string `json:"channel,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="State",type=string,JSONPath=".status.state"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Manifest is the Schema for the manifests API.
+type Manifest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Spec specifies the content and configuration for Manifest
+	Spec ManifestSpec `json:"spec"`
+
+	// Status signifies the current status of the Manifest
+	// +kubebuilder:validation:Optional
+	Status ManifestStatus `json:"status"`
+}
+
+//+kubebuilder:object:root=true
+
+// ManifestList contains a list of Manifest.
+type ManifestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []Manifest `json:"items"`
+}
+
+//nolint:gochecknoinits
+func init() {
+	SchemeBuilder.Register(&Manifest{}, &ManifestList{})
+}
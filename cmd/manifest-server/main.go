@@ -0,0 +1,259 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// manifest-server exposes the operations of the manifest library over gRPC and REST,
+// so that components not written in Go can drive installs without embedding the library.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	// to ensure that exec-entrypoint and run can make use of them.
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+
+	"github.com/kyma-project/module-manager/pkg/log"
+	"github.com/kyma-project/module-manager/pkg/server"
+)
+
+const (
+	readHeaderTimeoutDefault = 10 * time.Second
+	shutdownTimeoutDefault   = 30 * time.Second
+)
+
+var (
+	errNoAuthentication   = errors.New("neither token authentication nor a client CA is configured")
+	errClientCAWithoutTLS = errors.New("--client-ca-file requires --tls-cert-file")
+	// without allowed groups, every identity of the cluster could install with the identity of the server
+	errDefaultClusterWithoutGroups = errors.New("--use-default-cluster requires --allowed-groups")
+)
+
+type FlagVar struct {
+	httpAddr           string
+	grpcAddr           string
+	useDefaultCluster  bool
+	chartRoot          string
+	tlsCertFile        string
+	tlsKeyFile         string
+	clientCAFile       string
+	authenticateTokens bool
+	tokenAudiences     string
+	allowedGroups      string
+	shutdownTimeout    time.Duration
+	logVerbosity       int
+}
+
+func main() {
+	flagVar := defineFlagVar()
+	flag.Parse()
+
+	ctrl.SetLogger(log.ConfigLoggerWithVerbosity(flagVar.logVerbosity))
+	setupLog := ctrl.Log.WithName("setup")
+
+	service, err := newService(flagVar)
+	if err != nil {
+		setupLog.Error(err, "unable to create service")
+		os.Exit(1)
+	}
+	var tlsConfig *tls.Config
+	if flagVar.tlsCertFile != "" {
+		if tlsConfig, err = serverTLSConfig(flagVar); err != nil {
+			setupLog.Error(err, "unable to load TLS config")
+			os.Exit(1)
+		}
+	} else if flagVar.clientCAFile != "" {
+		setupLog.Error(errClientCAWithoutTLS, "unable to load TLS config")
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 2)
+	var httpServer *http.Server
+	if flagVar.httpAddr != "" {
+		httpServer = &http.Server{
+			Addr:              flagVar.httpAddr,
+			Handler:           server.NewHTTPHandler(service),
+			ReadHeaderTimeout: readHeaderTimeoutDefault,
+			TLSConfig:         tlsConfig,
+		}
+		go func() {
+			setupLog.Info("starting REST API", "address", flagVar.httpAddr, "tls", tlsConfig != nil)
+			var err error
+			if tlsConfig != nil {
+				err = httpServer.ListenAndServeTLS("", "")
+			} else {
+				err = httpServer.ListenAndServe()
+			}
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errs <- err
+			}
+		}()
+	}
+	var grpcServer *grpc.Server
+	if flagVar.grpcAddr != "" {
+		listener, err := net.Listen("tcp", flagVar.grpcAddr)
+		if err != nil {
+			setupLog.Error(err, "unable to listen for gRPC API")
+			os.Exit(1)
+		}
+		var options []grpc.ServerOption
+		if tlsConfig != nil {
+			options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		}
+		grpcServer = grpc.NewServer(options...)
+		server.RegisterGRPC(grpcServer, service)
+		go func() {
+			setupLog.Info("starting gRPC API", "address", flagVar.grpcAddr)
+			if err := grpcServer.Serve(listener); err != nil {
+				errs <- err
+			}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+	case err := <-errs:
+		setupLog.Error(err, "server failed")
+	}
+
+	setupLog.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), flagVar.shutdownTimeout)
+	defer cancel()
+	if grpcServer != nil {
+		go func() {
+			<-shutdownCtx.Done()
+			grpcServer.Stop()
+		}()
+		grpcServer.GracefulStop()
+	}
+	if httpServer != nil {
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			setupLog.Error(err, "unable to shut down REST API")
+		}
+	}
+}
+
+func defineFlagVar() *FlagVar {
+	flagVar := &FlagVar{}
+	flag.StringVar(&flagVar.httpAddr, "http-bind-address", "127.0.0.1:8090",
+		"The address the REST API binds to, empty disables it.")
+	flag.StringVar(&flagVar.grpcAddr, "grpc-bind-address", "127.0.0.1:9090",
+		"The address the gRPC API binds to, empty disables it.")
+	flag.BoolVar(&flagVar.useDefaultCluster, "use-default-cluster", false,
+		"indicates if requests without a kubeconfig are installed to the cluster of the server, requires --allowed-groups")
+	flag.StringVar(&flagVar.chartRoot, "chart-root", "",
+		"The directory chart paths of requests are resolved in, empty rejects requests with a chart path.")
+	flag.StringVar(&flagVar.tlsCertFile, "tls-cert-file", "",
+		"The certificate file of both APIs, empty serves them without TLS.")
+	flag.StringVar(&flagVar.tlsKeyFile, "tls-key-file", "", "The private key file of --tls-cert-file.")
+	flag.StringVar(&flagVar.clientCAFile, "client-ca-file", "",
+		"The CA file verifying client certificates, which authenticate their callers. Requires --tls-cert-file.")
+	flag.BoolVar(&flagVar.authenticateTokens, "authenticate-tokens", true,
+		"indicates if callers are authenticated by bearer tokens reviewed by the cluster of the server")
+	flag.StringVar(&flagVar.tokenAudiences, "token-audiences", "",
+		"Comma-separated list of audiences, one of which bearer tokens have to be issued for.")
+	flag.StringVar(&flagVar.allowedGroups, "allowed-groups", "",
+		"Comma-separated list of groups, one of which callers authenticated by bearer tokens have to be a member of.")
+	flag.DurationVar(&flagVar.shutdownTimeout, "shutdown-timeout", shutdownTimeoutDefault,
+		"Timeout for running requests to complete on shutdown.")
+	flag.IntVar(&flagVar.logVerbosity, "log-verbosity", log.InfoLevel,
+		"The verbosity of the JSON log lines, e.g. 2 for debug and 3 for trace lines.")
+	return flagVar
+}
+
+// newService returns the service for the flags, which authenticates callers by bearer tokens
+// or by client certificates.
+func newService(flagVar *FlagVar) (*server.Service, error) {
+	var config *rest.Config
+	if flagVar.useDefaultCluster {
+		if len(commaSeparated(flagVar.allowedGroups)) == 0 {
+			return nil, errDefaultClusterWithoutGroups
+		}
+		config = ctrl.GetConfigOrDie()
+	}
+	service := server.NewService(ctrl.Log.WithName("manifest-server"), config)
+	service.ChartRoot = flagVar.chartRoot
+	if !flagVar.authenticateTokens {
+		if flagVar.clientCAFile == "" {
+			return nil, errNoAuthentication
+		}
+		return service, nil
+	}
+	clientSet, err := kubernetes.NewForConfig(ctrl.GetConfigOrDie())
+	if err != nil {
+		return nil, fmt.Errorf("creating client for token reviews: %w", err)
+	}
+	service.Authenticator = &server.TokenReviewAuthenticator{
+		TokenReviews:  clientSet.AuthenticationV1().TokenReviews(),
+		Audiences:     commaSeparated(flagVar.tokenAudiences),
+		AllowedGroups: commaSeparated(flagVar.allowedGroups),
+	}
+	return service, nil
+}
+
+// serverTLSConfig returns the TLS config of both APIs. Client certificates are optional,
+// so that callers can authenticate by bearer tokens instead.
+func serverTLSConfig(flagVar *FlagVar) (*tls.Config, error) {
+	certificate, err := tls.LoadX509KeyPair(flagVar.tlsCertFile, flagVar.tlsKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}
+	if flagVar.clientCAFile == "" {
+		return config, nil
+	}
+	clientCAs, err := os.ReadFile(flagVar.clientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	config.ClientCAs = x509.NewCertPool()
+	if !config.ClientCAs.AppendCertsFromPEM(clientCAs) {
+		return nil, fmt.Errorf("no certificates found in %s", flagVar.clientCAFile)
+	}
+	config.ClientAuth = tls.VerifyClientCertIfGiven
+	return config, nil
+}
+
+// commaSeparated returns the non-empty values of a comma-separated flag.
+func commaSeparated(flagValue string) []string {
+	var values []string
+	for _, value := range strings.Split(flagValue, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
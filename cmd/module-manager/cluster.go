@@ -0,0 +1,148 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+const waitIntervalDefault = 5 * time.Second
+
+var errNotReady = errors.New("not all installs are ready")
+
+// operation runs an operation of the manifest library on an install and reports if it is ready.
+type operation func(options manifest.OperationOptions) (bool, error)
+
+type clusterOptions struct {
+	*globalOptions
+	checkReadyStates bool
+	wait             bool
+	waitInterval     time.Duration
+}
+
+func newInstallCommand(global *globalOptions) *cobra.Command {
+	options := &clusterOptions{globalOptions: global}
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install all installs of the Manifest to the cluster of the kubeconfig",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return options.run(cmd, manifest.InstallChart, manifest.ConsistencyCheck)
+		},
+	}
+	options.addFlags(cmd)
+	return cmd
+}
+
+func newUninstallCommand(global *globalOptions) *cobra.Command {
+	options := &clusterOptions{globalOptions: global}
+	cmd := &cobra.Command{
+		Use:   "uninstall",
+		Short: "Uninstall all installs of the Manifest from the cluster of the kubeconfig",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			// uninstalls are repeated until all resources are removed
+			return options.run(cmd, manifest.UninstallChart, manifest.UninstallChart)
+		},
+	}
+	options.addFlags(cmd)
+	return cmd
+}
+
+func newStatusCommand(global *globalOptions) *cobra.Command {
+	options := &clusterOptions{globalOptions: global}
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Print the readiness of all installs of the Manifest in the cluster of the kubeconfig",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return options.run(cmd, manifest.ConsistencyCheck, manifest.ConsistencyCheck)
+		},
+	}
+	options.addFlags(cmd)
+	return cmd
+}
+
+func (o *clusterOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.checkReadyStates, "check-ready-states", true,
+		"Indicates if installed resources should be verified to be ready.")
+	cmd.Flags().BoolVar(&o.wait, "wait", false,
+		"Repeats the readiness evaluation until all installs are ready or the command is interrupted.")
+	cmd.Flags().DurationVar(&o.waitInterval, "wait-interval", waitIntervalDefault,
+		"The interval in which the readiness is evaluated with --wait.")
+}
+
+// run runs the operation on all installs of the Manifest and prints their readiness. With --wait, the readiness
+// is evaluated by the retry operation until all installs are ready.
+func (o *clusterOptions) run(cmd *cobra.Command, run, retry operation) error {
+	manifestObj, err := o.loadManifest(cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	infos, err := o.installInfos(cmd.Context(), manifestObj, false, o.checkReadyStates)
+	if err != nil {
+		return err
+	}
+
+	for {
+		if o.runOnce(cmd, infos, run) {
+			return nil
+		}
+		if !o.wait {
+			return errNotReady
+		}
+		select {
+		case <-cmd.Context().Done():
+			return cmd.Context().Err()
+		case <-time.After(o.waitInterval):
+		}
+		run = retry
+	}
+}
+
+// runOnce runs the operation on all installs and prints their readiness evaluation.
+func (o *clusterOptions) runOnce(cmd *cobra.Command, infos []*types.InstallInfo, run operation) bool {
+	allReady := true
+	for _, info := range infos {
+		name := info.ReleaseName
+		ready, err := run(manifest.OperationOptions{
+			Logger:      o.logger(),
+			InstallInfo: info,
+			ReportScheduling: func(issues []types.SchedulingIssue) {
+				for _, issue := range issues {
+					fmt.Fprintf(cmd.ErrOrStderr(), "%s: unschedulable: %s\n", name, issue)
+				}
+			},
+			ReportCapacity: func(shortages []types.CapacityShortage) {
+				for _, shortage := range shortages {
+					fmt.Fprintf(cmd.ErrOrStderr(), "%s: insufficient capacity: %s\n", name, shortage)
+				}
+			},
+			ReportHealth: func(issues []types.HealthIssue) {
+				for _, issue := range issues {
+					fmt.Fprintf(cmd.ErrOrStderr(), "%s: degraded: %s\n", name, issue)
+				}
+			},
+		})
+		allReady = allReady && ready && err == nil
+		switch {
+		case err != nil:
+			reason := "Error"
+			var operationErr *types.OperationError
+			if errors.As(err, &operationErr) {
+				reason = operationErr.Reason
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s: %v\n", name, reason, manifest.ClassifyError(err), err)
+		case ready:
+			fmt.Fprintf(cmd.OutOrStdout(), "%s\tReady\n", name)
+		default:
+			fmt.Fprintf(cmd.OutOrStdout(), "%s\tNotReady\n", name)
+		}
+	}
+	return allReady
+}
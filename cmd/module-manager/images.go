@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/spf13/cobra"
+
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func newVerifyImagesCommand(global *globalOptions) *cobra.Command {
+	options := &renderOptions{globalOptions: global}
+	cmd := &cobra.Command{
+		Use:   "verify-images",
+		Short: "Verify that all images of the rendered workloads of the Manifest can be pulled",
+		Long: "Render the resources of all installs of the Manifest without a cluster and resolve the images " +
+			"of all of their workloads in their registries with the credentials of the docker config.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return options.verifyImages(cmd)
+		},
+	}
+	cmd.Flags().StringVar(&options.kubeVersion, "kube-version", "",
+		"The Kubernetes version used for the capabilities of Helm charts, defaults to the version supported by Helm.")
+	return cmd
+}
+
+func (o *renderOptions) verifyImages(cmd *cobra.Command) error {
+	rendered, err := o.render(cmd)
+	if err != nil {
+		return err
+	}
+	craneOptions := []crane.Option{crane.WithAuthFromKeychain(authn.DefaultKeychain), crane.WithContext(cmd.Context())}
+	if o.insecureRegistry {
+		craneOptions = append(craneOptions, crane.Insecure)
+	}
+
+	var missing int
+	for _, install := range rendered {
+		images, err := util.ImageReferences(install.objects.Items)
+		if err != nil {
+			return err
+		}
+		for _, image := range images {
+			digest, err := crane.Digest(image, craneOptions...)
+			if err != nil {
+				missing++
+				fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\tMISSING: %v\n", install.name, image, err)
+				continue
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\n", install.name, image, digest)
+		}
+	}
+	if missing > 0 {
+		return fmt.Errorf("%d images cannot be resolved", missing)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// module-manager renders, verifies, installs and uninstalls the charts of a Manifest custom resource locally,
+// so that modules can be tried out and debugged without running the operator.
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	// to ensure that the kubeconfig of any cluster can be used.
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	ctrlzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/kyma-project/module-manager/pkg/descriptor"
+	"github.com/kyma-project/module-manager/pkg/version"
+)
+
+// globalOptions are the flags shared by all commands.
+type globalOptions struct {
+	filename         string
+	kubeconfig       string
+	insecureRegistry bool
+	layerStoreDir    string
+	timeout          time.Duration
+	verbose          bool
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := newRootCommand().ExecuteContext(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	options := &globalOptions{}
+	root := &cobra.Command{
+		Use:          "module-manager",
+		Short:        "Render, verify, install and uninstall the charts of a Manifest locally",
+		SilenceUsage: true,
+		Version:      version.Get().Version,
+	}
+	flags := root.PersistentFlags()
+	flags.StringVarP(&options.filename, "filename", "f", "",
+		"The file containing the Manifest custom resource, - reads it from stdin.")
+	flags.StringVar(&options.kubeconfig, "kubeconfig", "",
+		"The kubeconfig of the target cluster, defaults to the KUBECONFIG environment variable or ~/.kube/config. "+
+			"Offline commands only use the cluster to look up credential secrets of the Manifest.")
+	flags.BoolVar(&options.insecureRegistry, "insecure-registry", false,
+		"indicates if insecure (http) response is expected from image registry")
+	flags.StringVar(&options.layerStoreDir, "layer-store-dir", descriptor.DefaultLayerStoreRoot(),
+		"The directory in which pulled OCI layers are unpacked and kept across commands.")
+	flags.DurationVar(&options.timeout, "timeout", 0,
+		"Limits the duration of each operation, unless overridden by the Manifest. A timeout of 0 disables the limit.")
+	flags.BoolVarP(&options.verbose, "verbose", "v", false, "Logs the progress of operations to stderr.")
+	_ = root.MarkPersistentFlagRequired("filename")
+
+	root.AddCommand(
+		newRenderCommand(options),
+		newVerifyImagesCommand(options),
+		newInstallCommand(options),
+		newUninstallCommand(options),
+		newStatusCommand(options),
+	)
+	return root
+}
+
+func (o *globalOptions) logger() logr.Logger {
+	if !o.verbose {
+		return logr.Discard()
+	}
+	return ctrlzap.New(ctrlzap.WriteTo(os.Stderr), ctrlzap.UseDevMode(true))
+}
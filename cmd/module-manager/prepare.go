@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/internal/pkg/prepare"
+	internalTypes "github.com/kyma-project/module-manager/internal/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/descriptor"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+const layerStoreMaxSizeDefault = 2 << 30
+
+var errOffline = errors.New("no cluster available offline, pass a kubeconfig to look up credential secrets")
+
+// offlineClient replaces the client of the default cluster for commands running without a cluster.
+// Preparing installs only reads credential secrets of the Manifest from the cluster, which fails offline.
+type offlineClient struct {
+	client.Client
+}
+
+func (offlineClient) Get(context.Context, client.ObjectKey, client.Object, ...client.GetOption) error {
+	return errOffline
+}
+
+func (offlineClient) List(context.Context, client.ObjectList, ...client.ListOption) error {
+	return errOffline
+}
+
+// loadManifest reads the Manifest custom resource from the file passed with --filename.
+func (o *globalOptions) loadManifest(in io.Reader) (*v1alpha1.Manifest, error) {
+	var data []byte
+	var err error
+	if o.filename == "-" {
+		data, err = io.ReadAll(in)
+	} else {
+		data, err = os.ReadFile(o.filename)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading Manifest: %w", err)
+	}
+	manifestObj := &v1alpha1.Manifest{}
+	if err := yaml.UnmarshalStrict(data, manifestObj); err != nil {
+		return nil, fmt.Errorf("decoding Manifest %s: %w", o.filename, err)
+	}
+	if manifestObj.Kind != v1alpha1.ManifestKind {
+		return nil, fmt.Errorf("%s contains a %s instead of a %s", o.filename, manifestObj.Kind, v1alpha1.ManifestKind)
+	}
+	if manifestObj.Namespace == "" {
+		manifestObj.Namespace = "default"
+	}
+	return manifestObj, nil
+}
+
+// clusterInfo returns the types.ClusterInfo of the cluster of the kubeconfig.
+func (o *globalOptions) clusterInfo() (types.ClusterInfo, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.kubeconfig
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, nil).ClientConfig()
+	if err != nil {
+		return types.ClusterInfo{}, fmt.Errorf("loading kubeconfig: %w", err)
+	}
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	clnt, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return types.ClusterInfo{}, fmt.Errorf("creating client: %w", err)
+	}
+	return types.ClusterInfo{Config: config, Client: clnt}, nil
+}
+
+// installInfos prepares the installs of the Manifest like the operator does. The Manifest is always installed to
+// the cluster of the kubeconfig, even if it is remote. Offline, the cluster is only used if a kubeconfig is passed.
+func (o *globalOptions) installInfos(ctx context.Context, manifestObj *v1alpha1.Manifest, offline bool,
+	checkReadyStates bool,
+) ([]*types.InstallInfo, error) {
+	clusterInfo := types.ClusterInfo{Client: offlineClient{}}
+	if !offline || o.kubeconfig != "" {
+		var err error
+		if clusterInfo, err = o.clusterInfo(); err != nil {
+			return nil, err
+		}
+	}
+	codec, err := types.NewCodec()
+	if err != nil {
+		return nil, err
+	}
+	manifestObj.Spec.Remote = false
+	layerStore := descriptor.NewLayerStore(o.layerStoreDir, layerStoreMaxSizeDefault)
+
+	return prepare.GetInstallInfos(ctx, manifestObj, clusterInfo, internalTypes.ReconcileFlagConfig{
+		Codec:            codec,
+		CheckReadyStates: checkReadyStates,
+		InsecureRegistry: o.insecureRegistry,
+		TrackInventory:   true,
+		OperationTimeout: o.timeout,
+		LayerStore:       layerStore,
+		ChartRepositories: descriptor.NewChartRepositories(
+			descriptor.DefaultChartRepositoriesRoot(), descriptor.DefaultChartIndexTTL),
+		Archives: descriptor.NewArchives(layerStore, descriptor.DefaultArchiveTTL),
+		GitRepositories: descriptor.NewGitRepositories(
+			descriptor.DefaultGitRepositoriesRoot(), descriptor.DefaultGitRevisionTTL),
+	}, nil)
+}
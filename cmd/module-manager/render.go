@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+type renderOptions struct {
+	*globalOptions
+	kubeVersion string
+}
+
+func newRenderCommand(global *globalOptions) *cobra.Command {
+	options := &renderOptions{globalOptions: global}
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "Render the resources of all installs of the Manifest without a cluster",
+		Long: "Render the resources of all installs of the Manifest without a cluster, " +
+			"including all transforms of the Manifest. Helm charts are rendered like with helm template.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return options.run(cmd)
+		},
+	}
+	cmd.Flags().StringVar(&options.kubeVersion, "kube-version", "",
+		"The Kubernetes version used for the capabilities of Helm charts, defaults to the version supported by Helm.")
+	return cmd
+}
+
+func (o *renderOptions) run(cmd *cobra.Command) error {
+	rendered, err := o.render(cmd)
+	if err != nil {
+		return err
+	}
+	for _, install := range rendered {
+		if err := writeObjects(cmd.OutOrStdout(), install.name, install.objects); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type renderedInstall struct {
+	name    string
+	objects *types.ManifestResources
+}
+
+// render renders the resources of all installs of the Manifest offline.
+func (o *renderOptions) render(cmd *cobra.Command) ([]renderedInstall, error) {
+	manifestObj, err := o.loadManifest(cmd.InOrStdin())
+	if err != nil {
+		return nil, err
+	}
+	infos, err := o.installInfos(cmd.Context(), manifestObj, true, false)
+	if err != nil {
+		return nil, err
+	}
+	rendered := make([]renderedInstall, 0, len(infos))
+	for _, info := range infos {
+		_, objects, err := manifest.RenderOffline(info, o.logger(), o.kubeVersion)
+		if err != nil {
+			return nil, fmt.Errorf("rendering install %s: %w", info.ReleaseName, err)
+		}
+		rendered = append(rendered, renderedInstall{name: info.ReleaseName, objects: objects})
+	}
+	return rendered, nil
+}
+
+// writeObjects writes the objects as a stream of YAML documents, each annotated with the install it belongs to.
+func writeObjects(out io.Writer, install string, objects *types.ManifestResources) error {
+	for _, obj := range objects.Items {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(out, "---\n# Install: %s\n%s", install,
+			strings.TrimSuffix(string(data), "\n")+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
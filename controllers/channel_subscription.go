@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrlLog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// catalogChannelsChanged passes events of ModuleCatalogs, except for updates keeping the versions of their channels,
+// e.g. of the time of their last listing.
+//
+//nolint:gochecknoglobals
+var catalogChannelsChanged = predicate.Funcs{
+	UpdateFunc: func(updateEvent event.UpdateEvent) bool {
+		oldCatalog, oldIsCatalog := updateEvent.ObjectOld.(*v1alpha1.ModuleCatalog)
+		newCatalog, newIsCatalog := updateEvent.ObjectNew.(*v1alpha1.ModuleCatalog)
+		return !oldIsCatalog || !newIsCatalog ||
+			!reflect.DeepEqual(oldCatalog.Status.Channels, newCatalog.Status.Channels)
+	},
+}
+
+// enqueueChannelSubscribers enqueues all Manifests in the namespace of a ModuleCatalog,
+// which have installs following a channel of the catalog.
+func enqueueChannelSubscribers(ctx context.Context, reader client.Reader) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(catalog client.Object) []reconcile.Request {
+		manifestList := &v1alpha1.ManifestList{}
+		if err := reader.List(ctx, manifestList, client.InNamespace(catalog.GetNamespace())); err != nil {
+			ctrlLog.FromContext(ctx).Error(err, "cannot list manifests following module catalog",
+				"catalog", client.ObjectKeyFromObject(catalog))
+			return nil
+		}
+		var requests []reconcile.Request
+		for i := range manifestList.Items {
+			manifestObj := &manifestList.Items[i]
+			if manifestFollowsCatalog(manifestObj, catalog.GetName()) {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(manifestObj)})
+			}
+		}
+		return requests
+	})
+}
+
+// manifestFollowsCatalog indicates if any install of the Manifest follows a channel of the named ModuleCatalog.
+func manifestFollowsCatalog(manifestObj *v1alpha1.Manifest, name string) bool {
+	for _, install := range manifestObj.Spec.Installs {
+		if install.Channel != nil && install.Channel.Catalog == name {
+			return true
+		}
+	}
+	return false
+}
+
+// advancedChannels returns the names of all installed installs following a channel,
+// which resolves to another version than the chart version installed last.
+func advancedChannels(deployInfos []*types.InstallInfo) sets.String {
+	advanced := sets.NewString()
+	for _, deployInfo := range deployInfos {
+		if deployInfo.ChartInfo == nil || deployInfo.Channel == nil || deployInfo.InstalledVersion == "" {
+			continue
+		}
+		if deployInfo.Channel.Version != deployInfo.InstalledVersion {
+			advanced.Insert(deployInfo.ChartName)
+		}
+	}
+	return advanced
+}
@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// HealthCriteria configures the internal health, which fails the readiness probe of the operator,
+// so that Kubernetes stops routing webhook traffic to a degraded replica and restarts it if configured.
+// Zero values disable the respective criterion.
+type HealthCriteria struct {
+	// StuckThreshold is the duration after which reconciles are considered stuck, if reconciles are running,
+	// but none completed in the meantime. It has to exceed the longest expected reconcile, e.g. the operation timeout.
+	StuckThreshold time.Duration
+	// RequireLeader fails replicas, which do not hold the leader election lease.
+	RequireLeader bool
+	// RequireRemoteCacheSync fails until the informers of applied resources in target clusters are synced.
+	RequireRemoteCacheSync bool
+	// ErrorRateThreshold is the ratio of failed reconciles within ErrorRateWindow, above which the replica fails.
+	ErrorRateThreshold float64
+	// ErrorRateWindow is the duration of the sliding window the error rate is calculated for.
+	ErrorRateWindow time.Duration
+	// ErrorRateMinSamples is the number of reconciles within the window required to evaluate the error rate.
+	ErrorRateMinSamples int
+}
+
+var (
+	ErrNotLeader            = errors.New("replica does not hold the leader election lease")
+	ErrRemoteCacheNotSynced = errors.New("informers of target clusters not synced")
+)
+
+type reconcileOutcome struct {
+	finished time.Time
+	failed   bool
+}
+
+type syncChecker interface {
+	HasSynced() bool
+}
+
+// HealthMonitor observes the reconciles of a reconciler and evaluates them against HealthCriteria.
+// Its Checker is meant to be registered as readiness check of the manager.
+type HealthMonitor struct {
+	Criteria HealthCriteria
+	// Clock is the time source of reconcile progress and outcomes, defaults to the real clock
+	Clock clock.PassiveClock
+
+	elected      <-chan struct{}
+	remoteCaches syncChecker
+
+	mu           sync.Mutex
+	running      int
+	lastProgress time.Time
+	outcomes     []reconcileOutcome
+}
+
+// NewHealthMonitor returns a HealthMonitor for the passed criteria, where elected is closed once the replica
+// holds the leader election lease, e.g. manager.Manager.Elected().
+func NewHealthMonitor(criteria HealthCriteria, elected <-chan struct{}) *HealthMonitor {
+	return &HealthMonitor{
+		Criteria: criteria,
+		Clock:    clock.RealClock{},
+		elected:  elected,
+	}
+}
+
+// Wrap returns a reconciler recording the reconciles of the passed reconciler.
+func (m *HealthMonitor) Wrap(reconciler reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		m.started()
+		result, err := reconciler.Reconcile(ctx, req)
+		m.finished(err != nil)
+		return result, err
+	})
+}
+
+// Checker is a healthz.Checker failing if any of the configured criteria is violated.
+func (m *HealthMonitor) Checker(_ *http.Request) error {
+	if m.Criteria.RequireLeader && !m.isLeader() {
+		return ErrNotLeader
+	}
+	if m.Criteria.RequireRemoteCacheSync && m.remoteCaches != nil && !m.remoteCaches.HasSynced() {
+		return ErrRemoteCacheNotSynced
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.Clock.Now()
+	if m.Criteria.StuckThreshold > 0 && m.running > 0 && now.Sub(m.lastProgress) > m.Criteria.StuckThreshold {
+		return fmt.Errorf("%d reconciles running without progress since %s", m.running,
+			m.lastProgress.Format(time.RFC3339))
+	}
+	if m.Criteria.ErrorRateThreshold > 0 {
+		m.pruneOutcomes(now)
+		if len(m.outcomes) >= m.Criteria.ErrorRateMinSamples && len(m.outcomes) > 0 {
+			failed := 0
+			for _, outcome := range m.outcomes {
+				if outcome.failed {
+					failed++
+				}
+			}
+			if rate := float64(failed) / float64(len(m.outcomes)); rate > m.Criteria.ErrorRateThreshold {
+				return fmt.Errorf("error rate of reconciles %.2f exceeds threshold %.2f within %s", rate,
+					m.Criteria.ErrorRateThreshold, m.Criteria.ErrorRateWindow)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *HealthMonitor) isLeader() bool {
+	select {
+	case <-m.elected:
+		return true
+	default:
+		return false
+	}
+}
+
+func (m *HealthMonitor) started() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// an idle monitor has made no progress to wait for
+	if m.running == 0 {
+		m.lastProgress = m.Clock.Now()
+	}
+	m.running++
+}
+
+func (m *HealthMonitor) finished(failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.Clock.Now()
+	m.running--
+	m.lastProgress = now
+	if m.Criteria.ErrorRateThreshold > 0 {
+		m.outcomes = append(m.outcomes, reconcileOutcome{finished: now, failed: failed})
+		m.pruneOutcomes(now)
+	}
+}
+
+// pruneOutcomes drops outcomes outside the error rate window, the caller has to hold the lock.
+func (m *HealthMonitor) pruneOutcomes(now time.Time) {
+	expired := 0
+	for expired < len(m.outcomes) && now.Sub(m.outcomes[expired].finished) > m.Criteria.ErrorRateWindow {
+		expired++
+	}
+	m.outcomes = m.outcomes[expired:]
+}
@@ -0,0 +1,107 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kyma-project/module-manager/controllers"
+)
+
+var errHealthTestReconcile = errors.New("reconcile failed")
+
+func newTestHealthMonitor(criteria controllers.HealthCriteria) (*controllers.HealthMonitor, *clocktesting.FakeClock) {
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	monitor := controllers.NewHealthMonitor(criteria, nil)
+	monitor.Clock = fakeClock
+	return monitor, fakeClock
+}
+
+func healthTestRequest(name string) ctrl.Request {
+	return ctrl.Request{NamespacedName: client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: name}}
+}
+
+func Test_HealthMonitor_StuckReconciles(t *testing.T) {
+	t.Parallel()
+	monitor, fakeClock := newTestHealthMonitor(controllers.HealthCriteria{StuckThreshold: time.Minute})
+	started, release := make(chan struct{}), make(chan struct{})
+	reconciler := monitor.Wrap(reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		if req.Name == "blocking" {
+			started <- struct{}{}
+			<-release
+		}
+		return ctrl.Result{}, nil
+	}))
+
+	fakeClock.Step(2 * time.Minute)
+	assert.NoError(t, monitor.Checker(nil), "idle replicas are not stuck")
+
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		_, _ = reconciler.Reconcile(context.Background(), healthTestRequest("blocking"))
+	}()
+	<-started
+	fakeClock.Step(30 * time.Second)
+	assert.NoError(t, monitor.Checker(nil), "reconciles are not stuck within the threshold")
+
+	fakeClock.Step(30 * time.Second)
+	_, err := reconciler.Reconcile(context.Background(), healthTestRequest("other"))
+	require.NoError(t, err)
+	fakeClock.Step(45 * time.Second)
+	assert.NoError(t, monitor.Checker(nil), "completed reconciles are progress")
+
+	fakeClock.Step(30 * time.Second)
+	assert.Error(t, monitor.Checker(nil), "running reconciles without progress beyond the threshold are stuck")
+
+	close(release)
+	<-finished
+	assert.NoError(t, monitor.Checker(nil))
+}
+
+func Test_HealthMonitor_ErrorRate(t *testing.T) {
+	t.Parallel()
+	monitor, fakeClock := newTestHealthMonitor(controllers.HealthCriteria{
+		ErrorRateThreshold: 0.5, ErrorRateWindow: time.Minute, ErrorRateMinSamples: 4,
+	})
+	reconciler := monitor.Wrap(reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		if req.Name == "failing" {
+			return ctrl.Result{}, errHealthTestReconcile
+		}
+		return ctrl.Result{}, nil
+	}))
+	reconcileAll := func(names ...string) {
+		for _, name := range names {
+			_, _ = reconciler.Reconcile(context.Background(), healthTestRequest(name))
+		}
+	}
+
+	reconcileAll("failing", "failing", "failing")
+	assert.NoError(t, monitor.Checker(nil), "the error rate is not evaluated below the minimum samples")
+
+	reconcileAll("succeeding")
+	assert.Error(t, monitor.Checker(nil), "3 of 4 reconciles failed")
+
+	fakeClock.Step(30 * time.Second)
+	reconcileAll("succeeding", "succeeding")
+	assert.NoError(t, monitor.Checker(nil), "3 of 6 reconciles failed, which does not exceed the threshold")
+
+	fakeClock.Step(45 * time.Second)
+	reconcileAll("failing", "failing")
+	assert.NoError(t, monitor.Checker(nil), "outcomes outside the window are pruned, 2 of 4 reconciles failed")
+
+	reconcileAll("failing")
+	assert.Error(t, monitor.Checker(nil), "3 of 5 reconciles failed")
+
+	fakeClock.Step(2 * time.Minute)
+	assert.NoError(t, monitor.Checker(nil), "all outcomes left the window")
+}
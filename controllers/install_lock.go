@@ -0,0 +1,301 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+var errInstallLockLost = errors.New("install lock was lost")
+
+const (
+	installLockSuffix = "-install-lock"
+	// installLockRenewals is the number of renewals within a lease duration,
+	// so that a single failed renewal does not release the lock to another replica.
+	installLockRenewals = 3
+)
+
+// InstallLocker guards the installs of a Manifest with a coordination.k8s.io Lease next to the Manifest,
+// so that only one replica of the operator runs Helm operations for a Manifest at a time.
+// Without it, a replica that just became leader could start operations on a Manifest while the operations
+// of the previous leader are still running. The lock is held by the replica until all of its operations of the
+// Manifest finished, and is renewed while they run. Locks of replicas which stopped renewing them
+// are taken over after LeaseDuration, which cancels the operations still running on the previous holder.
+// Leases are read by Client, which should be uncached, as the operator may not list and watch Leases.
+type InstallLocker struct {
+	Client        client.Client
+	Identity      string
+	LeaseDuration time.Duration
+	Logger        logr.Logger
+
+	mu      sync.Mutex
+	entries map[client.ObjectKey]*installLockEntry
+}
+
+// InstallLock is a lock of a Manifest held by the replica, as returned by InstallLocker.Acquire.
+type InstallLock struct {
+	key client.ObjectKey
+	// operations is the number of running operations holding the lock, guarded by the mutex of its entry
+	operations int
+	stop       chan struct{}
+	// lost is closed once the lock was taken over by another replica, which cancels all running operations
+	lost chan struct{}
+}
+
+// installLockEntry serializes the requests for the lock of a Manifest, without blocking the locks of
+// other Manifests. Entries are removed once they are neither used nor hold a lock.
+type installLockEntry struct {
+	mu sync.Mutex
+	// users is the number of callers using the entry, guarded by InstallLocker.mu
+	users int
+	// lock is the currently held lock of the Manifest, guarded by mu
+	lock *InstallLock
+}
+
+// NewInstallLocker returns an InstallLocker identifying the replica by its hostname and a random suffix,
+// the same way as leader election does.
+func NewInstallLocker(clnt client.Client, leaseDuration time.Duration, logger logr.Logger) (*InstallLocker, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	return &InstallLocker{
+		Client:        clnt,
+		Identity:      fmt.Sprintf("%s_%s", hostname, uuid.NewUUID()),
+		LeaseDuration: leaseDuration,
+		Logger:        logger,
+		entries:       make(map[client.ObjectKey]*installLockEntry),
+	}, nil
+}
+
+// Acquire acquires the lock of the Manifest for an operation. It returns no lock if another replica holds it.
+// The returned context of the operation is cancelled once the lock is taken over by another replica.
+// Every acquired lock must be passed to Release once the operation finished.
+func (l *InstallLocker) Acquire(ctx context.Context, manifest client.Object) (context.Context, *InstallLock, error) {
+	key := client.ObjectKeyFromObject(manifest)
+	entry := l.lockEntry(key)
+	defer l.unlockEntry(key, entry)
+
+	lock := entry.lock
+	if lock != nil {
+		lock.operations++
+	} else {
+		acquired, err := l.tryAcquire(ctx, manifest)
+		if err != nil || !acquired {
+			return ctx, nil, err
+		}
+		lock = &InstallLock{key: key, operations: 1, stop: make(chan struct{}), lost: make(chan struct{})}
+		entry.lock = lock
+		go l.renew(lock)
+	}
+
+	operationCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		defer cancel()
+		select {
+		case <-lock.lost:
+		case <-lock.stop:
+		case <-operationCtx.Done():
+		}
+	}()
+	return operationCtx, lock, nil
+}
+
+// Release releases the lock once all of its operations finished. Locks taken over by another replica
+// in the meantime are only forgotten, so that the lock acquired again afterwards is kept.
+func (l *InstallLocker) Release(ctx context.Context, lock *InstallLock) {
+	if lock == nil {
+		return
+	}
+	entry := l.lockEntry(lock.key)
+	defer l.unlockEntry(lock.key, entry)
+
+	if lock.operations--; lock.operations > 0 {
+		return
+	}
+	close(lock.stop)
+	if entry.lock != lock {
+		return
+	}
+	entry.lock = nil
+
+	lease := &coordinationv1.Lease{}
+	if err := l.Client.Get(ctx, leaseKey(lock.key), lease); err != nil {
+		if !apierrors.IsNotFound(err) {
+			l.Logger.Error(err, "unable to release install lock", "resource", lock.key.String())
+		}
+		return
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != l.Identity {
+		return
+	}
+	// the precondition ensures that a lock taken over in the meantime is never deleted
+	if err := l.Client.Delete(ctx, lease, client.Preconditions{ResourceVersion: &lease.ResourceVersion}); err != nil &&
+		!apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		l.Logger.Error(err, "unable to release install lock", "resource", lock.key.String())
+	}
+}
+
+// lockEntry returns the locked entry of the Manifest, which has to be unlocked with unlockEntry.
+func (l *InstallLocker) lockEntry(key client.ObjectKey) *installLockEntry {
+	l.mu.Lock()
+	entry, found := l.entries[key]
+	if !found {
+		entry = &installLockEntry{}
+		l.entries[key] = entry
+	}
+	entry.users++
+	l.mu.Unlock()
+
+	entry.mu.Lock()
+	return entry
+}
+
+func (l *InstallLocker) unlockEntry(key client.ObjectKey, entry *installLockEntry) {
+	unused := entry.lock == nil
+	entry.mu.Unlock()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// entries used in the meantime are removed by their last user
+	if entry.users--; entry.users == 0 && unused && l.entries[key] == entry {
+		delete(l.entries, key)
+	}
+}
+
+func (l *InstallLocker) tryAcquire(ctx context.Context, manifest client.Object) (bool, error) {
+	lease := &coordinationv1.Lease{}
+	err := l.Client.Get(ctx, leaseKey(client.ObjectKeyFromObject(manifest)), lease)
+	if apierrors.IsNotFound(err) {
+		lease = l.newLease(manifest)
+		if err := l.Client.Create(ctx, lease); apierrors.IsAlreadyExists(err) {
+			return false, nil
+		} else if err != nil {
+			return false, fmt.Errorf("creating install lock: %w", err)
+		}
+		return true, nil
+	} else if err != nil {
+		return false, fmt.Errorf("reading install lock: %w", err)
+	}
+
+	now := metav1.NowMicro()
+	if lease.Spec.HolderIdentity != nil && *lease.Spec.HolderIdentity != l.Identity && !leaseExpired(lease, now) {
+		l.Logger.V(util.DebugLogLevel).Info("install lock is held by another replica",
+			"resource", client.ObjectKeyFromObject(manifest).String(), "holder", *lease.Spec.HolderIdentity)
+		return false, nil
+	}
+	lease.Spec.HolderIdentity = &l.Identity
+	lease.Spec.AcquireTime = &now
+	lease.Spec.RenewTime = &now
+	lease.Spec.LeaseDurationSeconds = l.leaseDurationSeconds()
+	// a conflict means another replica took over the lock in the meantime
+	if err := l.Client.Update(ctx, lease); apierrors.IsConflict(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("taking over install lock: %w", err)
+	}
+	return true, nil
+}
+
+// renew renews the lease of the lock until it is released. If the lock was taken over by another replica,
+// the operations holding it are cancelled and the lock is forgotten, so that the next operation acquires it again.
+func (l *InstallLocker) renew(lock *InstallLock) {
+	ticker := time.NewTicker(l.LeaseDuration / installLockRenewals)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-lock.stop:
+			return
+		case <-ticker.C:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), l.LeaseDuration/installLockRenewals)
+		err := l.renewLease(ctx, lock.key)
+		cancel()
+		if errors.Is(err, errInstallLockLost) {
+			l.Logger.Error(err, "cancelling operations of install lock", "resource", lock.key.String())
+			entry := l.lockEntry(lock.key)
+			if entry.lock == lock {
+				entry.lock = nil
+			}
+			l.unlockEntry(lock.key, entry)
+			close(lock.lost)
+			return
+		}
+		if err != nil {
+			l.Logger.Error(err, "unable to renew install lock", "resource", lock.key.String())
+		}
+	}
+}
+
+func (l *InstallLocker) renewLease(ctx context.Context, key client.ObjectKey) error {
+	lease := &coordinationv1.Lease{}
+	if err := l.Client.Get(ctx, leaseKey(key), lease); apierrors.IsNotFound(err) {
+		return fmt.Errorf("%w: lease was removed", errInstallLockLost)
+	} else if err != nil {
+		return err
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != l.Identity {
+		holder := ""
+		if lease.Spec.HolderIdentity != nil {
+			holder = *lease.Spec.HolderIdentity
+		}
+		return fmt.Errorf("%w: taken over by %q", errInstallLockLost, holder)
+	}
+	now := metav1.NowMicro()
+	lease.Spec.RenewTime = &now
+	return l.Client.Update(ctx, lease)
+}
+
+func (l *InstallLocker) newLease(manifest client.Object) *coordinationv1.Lease {
+	now := metav1.NowMicro()
+	key := leaseKey(client.ObjectKeyFromObject(manifest))
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+			// leases of crashed replicas are removed together with the Manifest
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: v1alpha1.GroupVersion.String(),
+				Kind:       v1alpha1.ManifestKind,
+				Name:       manifest.GetName(),
+				UID:        manifest.GetUID(),
+			}},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &l.Identity,
+			LeaseDurationSeconds: l.leaseDurationSeconds(),
+			AcquireTime:          &now,
+			RenewTime:            &now,
+		},
+	}
+}
+
+func (l *InstallLocker) leaseDurationSeconds() *int32 {
+	seconds := int32(l.LeaseDuration / time.Second)
+	return &seconds
+}
+
+func leaseKey(manifest client.ObjectKey) client.ObjectKey {
+	return client.ObjectKey{Namespace: manifest.Namespace, Name: manifest.Name + installLockSuffix}
+}
+
+func leaseExpired(lease *coordinationv1.Lease, now metav1.MicroTime) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return now.After(expiry)
+}
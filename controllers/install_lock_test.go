@@ -0,0 +1,203 @@
+package controllers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/controllers"
+)
+
+const installLockTestDuration = 3 * time.Second
+
+func installLockKey(manifestObj client.Object) client.ObjectKey {
+	return client.ObjectKey{Namespace: manifestObj.GetNamespace(), Name: manifestObj.GetName() + "-install-lock"}
+}
+
+func newTestInstallLocker(t *testing.T, clnt client.Client) *controllers.InstallLocker {
+	t.Helper()
+	locker, err := controllers.NewInstallLocker(clnt, installLockTestDuration, logr.Discard())
+	require.NoError(t, err)
+	return locker
+}
+
+func Test_InstallLocker_AcquireRelease(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	manifestObj := newTestManifest("manifest", nil)
+	clnt := newFakeClientBuilder(t).Build()
+	locker, other := newTestInstallLocker(t, clnt), newTestInstallLocker(t, clnt)
+
+	_, lock, err := locker.Acquire(ctx, manifestObj)
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+	lease := &coordinationv1.Lease{}
+	require.NoError(t, clnt.Get(ctx, installLockKey(manifestObj), lease))
+	assert.Equal(t, locker.Identity, *lease.Spec.HolderIdentity)
+
+	_, shared, err := locker.Acquire(ctx, manifestObj)
+	require.NoError(t, err)
+	assert.Same(t, lock, shared, "operations of the same replica share the lock")
+	_, otherLock, err := other.Acquire(ctx, manifestObj)
+	require.NoError(t, err)
+	assert.Nil(t, otherLock, "the lock is held by another replica")
+
+	locker.Release(ctx, lock)
+	require.NoError(t, clnt.Get(ctx, installLockKey(manifestObj), lease), "the lock is held until all operations finished")
+	locker.Release(ctx, shared)
+	assert.True(t, apierrors.IsNotFound(clnt.Get(ctx, installLockKey(manifestObj), lease)))
+
+	_, otherLock, err = other.Acquire(ctx, manifestObj)
+	require.NoError(t, err)
+	assert.NotNil(t, otherLock, "released locks are acquired by other replicas")
+	other.Release(ctx, otherLock)
+}
+
+func Test_InstallLocker_TakeOverExpired(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	manifestObj := newTestManifest("manifest", nil)
+	holder, seconds := "crashed-replica", int32(1)
+	expired := metav1.NewMicroTime(time.Now().Add(-time.Minute))
+	key := installLockKey(manifestObj)
+	clnt := newFakeClientBuilder(t).WithObjects(&coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity: &holder, LeaseDurationSeconds: &seconds, AcquireTime: &expired, RenewTime: &expired,
+		},
+	}).Build()
+	locker := newTestInstallLocker(t, clnt)
+
+	_, lock, err := locker.Acquire(ctx, manifestObj)
+	require.NoError(t, err)
+	require.NotNil(t, lock, "locks not renewed for their lease duration are taken over")
+	lease := &coordinationv1.Lease{}
+	require.NoError(t, clnt.Get(ctx, key, lease))
+	assert.Equal(t, locker.Identity, *lease.Spec.HolderIdentity)
+	locker.Release(ctx, lock)
+}
+
+func Test_InstallLocker_CancelOnTakeOver(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	manifestObj := newTestManifest("manifest", nil)
+	clnt := newFakeClientBuilder(t).Build()
+	locker := newTestInstallLocker(t, clnt)
+
+	operationCtx, lock, err := locker.Acquire(ctx, manifestObj)
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+
+	lease := &coordinationv1.Lease{}
+	require.NoError(t, clnt.Get(ctx, installLockKey(manifestObj), lease))
+	holder := "other-replica"
+	lease.Spec.HolderIdentity = &holder
+	require.NoError(t, clnt.Update(ctx, lease))
+
+	select {
+	case <-operationCtx.Done():
+	case <-time.After(2 * installLockTestDuration):
+		t.Fatal("the operation was not cancelled after the lock was taken over")
+	}
+	locker.Release(ctx, lock)
+	require.NoError(t, clnt.Get(ctx, installLockKey(manifestObj), lease))
+	assert.Equal(t, holder, *lease.Spec.HolderIdentity, "locks taken over are not released")
+}
+
+func Test_InstallLocker_ReleaseLostLock(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	manifestObj := newTestManifest("manifest", nil)
+	clnt := newFakeClientBuilder(t).Build()
+	locker := newTestInstallLocker(t, clnt)
+
+	lostCtx, lost, err := locker.Acquire(ctx, manifestObj)
+	require.NoError(t, err)
+	require.NotNil(t, lost)
+	lease := &coordinationv1.Lease{}
+	require.NoError(t, clnt.Get(ctx, installLockKey(manifestObj), lease))
+	holder := "other-replica"
+	lease.Spec.HolderIdentity = &holder
+	require.NoError(t, clnt.Update(ctx, lease))
+	select {
+	case <-lostCtx.Done():
+	case <-time.After(2 * installLockTestDuration):
+		t.Fatal("the operation was not cancelled after the lock was taken over")
+	}
+	// the other replica finished its operations, so that the lock is acquired again
+	require.NoError(t, clnt.Delete(ctx, lease))
+	operationCtx, lock, err := locker.Acquire(ctx, manifestObj)
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+	require.NotSame(t, lost, lock)
+
+	locker.Release(ctx, lost)
+	assert.NoError(t, operationCtx.Err(), "releasing the lost lock does not cancel operations of the new lock")
+	require.NoError(t, clnt.Get(ctx, installLockKey(manifestObj), lease), "the new lock is kept")
+	assert.Equal(t, locker.Identity, *lease.Spec.HolderIdentity)
+
+	locker.Release(ctx, lock)
+	assert.Eventually(t, func() bool { return operationCtx.Err() != nil }, installLockTestDuration, 10*time.Millisecond,
+		"operations end with the release of their lock")
+	assert.True(t, apierrors.IsNotFound(clnt.Get(ctx, installLockKey(manifestObj), lease)))
+}
+
+// blockingLeaseClient blocks reads of the Lease of blocked until unblock is closed.
+type blockingLeaseClient struct {
+	client.Client
+	blocked client.ObjectKey
+	unblock chan struct{}
+}
+
+func (c *blockingLeaseClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object,
+	opts ...client.GetOption,
+) error {
+	if key == c.blocked {
+		<-c.unblock
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func Test_InstallLocker_IndependentManifests(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	slow, fast := newTestManifest("slow", nil), newTestManifest("fast", nil)
+	clnt := &blockingLeaseClient{
+		Client: newFakeClientBuilder(t).Build(), blocked: installLockKey(slow), unblock: make(chan struct{}),
+	}
+	locker := newTestInstallLocker(t, clnt)
+
+	slowLock := make(chan *controllers.InstallLock)
+	go func() {
+		_, lock, err := locker.Acquire(ctx, slow)
+		assert.NoError(t, err)
+		slowLock <- lock
+	}()
+
+	acquired := make(chan *controllers.InstallLock)
+	go func() {
+		_, lock, err := locker.Acquire(ctx, fast)
+		assert.NoError(t, err)
+		acquired <- lock
+	}()
+	select {
+	case lock := <-acquired:
+		assert.NotNil(t, lock)
+		locker.Release(ctx, lock)
+	case <-time.After(installLockTestDuration):
+		t.Fatal("the lock of a manifest is blocked by a pending request for the lock of another manifest")
+	}
+
+	close(clnt.unblock)
+	lock := <-slowLock
+	assert.NotNil(t, lock)
+	locker.Release(ctx, lock)
+}
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/pkg/labels"
+)
+
+const localIsolationGroup = "cluster/local"
+
+type isolationSlotKey struct{}
+
+// IsolationGroups caps the number of Manifests processed at the same time within an isolation group, e.g. all
+// Manifests of the same target cluster, while Manifests of different groups are processed fully in parallel.
+// Without it, the Manifests of a cluster with a slow API server can occupy all reconciles and workers.
+// Manifests of a group at its limit are not blocked on, but reconciled again after RetryInterval.
+type IsolationGroups struct {
+	// Limit is the number of Manifests of a group processed at the same time, a limit of 1 serializes them
+	Limit int
+	// RetryInterval is the interval after which a Manifest of a group at its limit is reconciled again
+	RetryInterval time.Duration
+
+	mu      sync.Mutex
+	running map[string]int
+}
+
+// NewIsolationGroups returns IsolationGroups processing up to limit Manifests of each group at the same time.
+func NewIsolationGroups(limit int, retryInterval time.Duration) *IsolationGroups {
+	return &IsolationGroups{
+		Limit:         limit,
+		RetryInterval: retryInterval,
+		running:       make(map[string]int),
+	}
+}
+
+// IsolationGroup returns the isolation group of the Manifest, which is the value of its
+// labels.IsolationGroupAnnotation, if set. Otherwise, Manifests are grouped by their target cluster,
+// where remote clusters are identified by the labels.ComponentOwner label referencing their kubeconfig.
+func IsolationGroup(manifestObj *v1alpha1.Manifest) string {
+	if group := manifestObj.GetAnnotations()[labels.IsolationGroupAnnotation]; group != "" {
+		return group
+	}
+	if !manifestObj.Spec.Remote {
+		return localIsolationGroup
+	}
+	return "cluster/" + manifestObj.Namespace + "/" + manifestObj.GetLabels()[labels.ComponentOwner]
+}
+
+// TryAcquire returns a slot of the group, or nil if the group is at its limit.
+// Every returned slot must be released once the Manifest was processed.
+func (g *IsolationGroups) TryAcquire(group string) *isolationSlot {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.running[group] >= g.Limit {
+		return nil
+	}
+	g.running[group]++
+	return &isolationSlot{groups: g, group: group}
+}
+
+func (g *IsolationGroups) release(group string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.running[group]--; g.running[group] <= 0 {
+		delete(g.running, group)
+	}
+}
+
+// isolationSlot is the slot of an isolation group held while a Manifest is processed.
+// Operations continuing asynchronously after the reconciliation hand the slot off and release it once they finished.
+type isolationSlot struct {
+	groups    *IsolationGroups
+	group     string
+	handedOff bool
+	once      sync.Once
+}
+
+// Release releases the slot, repeated calls have no effect.
+func (s *isolationSlot) Release() {
+	s.once.Do(func() {
+		s.groups.release(s.group)
+	})
+}
+
+// releaseUnlessHandedOff releases the slot at the end of a reconciliation,
+// unless asynchronous operations took it over.
+func (s *isolationSlot) releaseUnlessHandedOff() {
+	if !s.handedOff {
+		s.Release()
+	}
+}
+
+func withIsolationSlot(ctx context.Context, slot *isolationSlot) context.Context {
+	return context.WithValue(ctx, isolationSlotKey{}, slot)
+}
+
+// isolationSlotFrom returns the slot held by the reconciliation of the context, or nil if there is none.
+func isolationSlotFrom(ctx context.Context) *isolationSlot {
+	slot, _ := ctx.Value(isolationSlotKey{}).(*isolationSlot)
+	return slot
+}
@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/pkg/labels"
+)
+
+const localTargetCluster = "local"
+
+// ModuleName returns the name of the module the Manifest installs, which is the value of its labels.ModuleName,
+// if set, and its name otherwise.
+func ModuleName(manifestObj *v1alpha1.Manifest) string {
+	if module := manifestObj.GetLabels()[labels.ModuleName]; module != "" {
+		return module
+	}
+	return manifestObj.GetName()
+}
+
+// TargetCluster identifies the target cluster of the Manifest in log lines, remote clusters are identified
+// by the namespace of the Manifest and its labels.ComponentOwner label referencing their kubeconfig.
+func TargetCluster(manifestObj *v1alpha1.Manifest) string {
+	if !manifestObj.Spec.Remote {
+		return localTargetCluster
+	}
+	return manifestObj.GetNamespace() + "/" + manifestObj.GetLabels()[labels.ComponentOwner]
+}
@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/internal/pkg/prepare"
+	internalTypes "github.com/kyma-project/module-manager/internal/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// ManifestArchiveVersion is the format version of archives written by ManifestArchiver.
+const ManifestArchiveVersion = "v1"
+
+var ErrUnsupportedArchiveVersion = errors.New("unsupported manifest archive version")
+
+// ManifestArchive is a portable snapshot of all Manifests together with the inventories of their installs.
+type ManifestArchive struct {
+	Version   string             `json:"version"`
+	Created   metav1.Time        `json:"created"`
+	Manifests []ArchivedManifest `json:"manifests"`
+}
+
+// ArchivedManifest is a Manifest without cluster specific metadata and the inventories of its installs.
+type ArchivedManifest struct {
+	Manifest v1alpha1.Manifest `json:"manifest"`
+	// Inventories are the recorded resources in the target cluster by install name
+	Inventories map[string][]manifest.InventoryEntry `json:"inventories,omitempty"`
+}
+
+// ManifestArchiver exports all Manifests to a ManifestArchive and imports them for disaster recovery.
+// On import, Manifests are re-created with their archived status, so that their resources,
+// which are still running in the target clusters, are adopted by the next consistency check
+// instead of being installed again. Imports should therefore run before the operator is started.
+type ManifestArchiver struct {
+	Client client.Client
+	Config *rest.Config
+	// CustomRESTCfg optionally resolves the REST config of remote clusters, see ReconcileFlagConfig
+	CustomRESTCfg internalTypes.RESTConfigGetter
+	Logger        logr.Logger
+}
+
+// Export returns a ManifestArchive of all Manifests, which are not being deleted.
+func (a *ManifestArchiver) Export(ctx context.Context) (*ManifestArchive, error) {
+	manifestList := &v1alpha1.ManifestList{}
+	if err := a.Client.List(ctx, manifestList); err != nil {
+		return nil, fmt.Errorf("listing manifests for export: %w", err)
+	}
+
+	archive := &ManifestArchive{Version: ManifestArchiveVersion, Created: metav1.Now()}
+	for i := range manifestList.Items {
+		manifestObj := &manifestList.Items[i]
+		if !manifestObj.DeletionTimestamp.IsZero() {
+			continue
+		}
+		inventories, err := a.exportInventories(ctx, manifestObj)
+		if err != nil {
+			return nil, fmt.Errorf("exporting inventories of %s: %w", client.ObjectKeyFromObject(manifestObj), err)
+		}
+		archive.Manifests = append(archive.Manifests, ArchivedManifest{
+			Manifest:    archivedManifest(manifestObj),
+			Inventories: inventories,
+		})
+	}
+	return archive, nil
+}
+
+// Import creates all Manifests of the archive with their archived status and restores the inventories
+// of their installs in the target clusters, unless they still exist.
+// Already existing Manifests are not modified, so that an interrupted import can be repeated.
+func (a *ManifestArchiver) Import(ctx context.Context, archive *ManifestArchive) error {
+	if archive.Version != ManifestArchiveVersion {
+		return fmt.Errorf("%w: %q", ErrUnsupportedArchiveVersion, archive.Version)
+	}
+	for i := range archive.Manifests {
+		manifestObj := archive.Manifests[i].Manifest.DeepCopy()
+		key := client.ObjectKeyFromObject(manifestObj)
+		status := manifestObj.Status
+		if err := a.Client.Create(ctx, manifestObj); apierrors.IsAlreadyExists(err) {
+			// inventories are still restored, as a previous import could have been interrupted
+			a.Logger.Info("skipping import of existing manifest", "resource", key.String())
+		} else if err != nil {
+			return fmt.Errorf("importing manifest %s: %w", key, err)
+		} else {
+			// the status is observed for the generation of the created Manifest,
+			// so that the import is not processed as a spec change
+			manifestObj.Status = status
+			manifestObj.SetObservedGeneration()
+			if err := a.Client.Status().Update(ctx, manifestObj); err != nil {
+				return fmt.Errorf("importing status of manifest %s: %w", key, err)
+			}
+			a.Logger.Info("imported manifest", "resource", key.String(), "state", status.State)
+		}
+
+		if err := a.importInventories(ctx, manifestObj, archive.Manifests[i].Inventories); err != nil {
+			return fmt.Errorf("importing inventories of %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func (a *ManifestArchiver) exportInventories(ctx context.Context, manifestObj *v1alpha1.Manifest,
+) (map[string][]manifest.InventoryEntry, error) {
+	targetClient, err := a.targetClient(ctx, manifestObj)
+	if err != nil {
+		return nil, err
+	}
+	inventories := make(map[string][]manifest.InventoryEntry, len(manifestObj.Spec.Installs))
+	for _, install := range manifestObj.Spec.Installs {
+		entries, err := manifest.NewInventory(targetClient, manifestObj, install.Name).Load(ctx)
+		if errors.Is(err, manifest.ErrInventoryNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		inventories[install.Name] = entries
+	}
+	return inventories, nil
+}
+
+func (a *ManifestArchiver) importInventories(ctx context.Context, manifestObj *v1alpha1.Manifest,
+	inventories map[string][]manifest.InventoryEntry,
+) error {
+	if len(inventories) == 0 {
+		return nil
+	}
+	targetClient, err := a.targetClient(ctx, manifestObj)
+	if err != nil {
+		return err
+	}
+	for install, entries := range inventories {
+		restored, err := manifest.NewInventory(targetClient, manifestObj, install).Restore(ctx, entries)
+		if err != nil {
+			return err
+		}
+		if restored {
+			a.Logger.Info("restored inventory", "resource", client.ObjectKeyFromObject(manifestObj).String(),
+				"install", install)
+		}
+	}
+	return nil
+}
+
+// targetClient returns a client for the cluster the Manifest is installed to.
+func (a *ManifestArchiver) targetClient(ctx context.Context, manifestObj *v1alpha1.Manifest,
+) (client.Client, error) {
+	clusterInfo, err := prepare.GetDestinationClusterInfo(ctx,
+		types.ClusterInfo{Client: a.Client, Config: a.Config}, manifestObj, a.CustomRESTCfg)
+	if err != nil {
+		return nil, err
+	}
+	if clusterInfo.Client != nil {
+		return clusterInfo.Client, nil
+	}
+	return client.New(clusterInfo.Config, client.Options{Scheme: a.Client.Scheme()})
+}
+
+// archivedManifest returns a copy of the Manifest without metadata bound to the cluster it was exported from.
+// Owner references are dropped, as their owners are re-created with different UIDs.
+func archivedManifest(manifestObj *v1alpha1.Manifest) v1alpha1.Manifest {
+	archived := manifestObj.DeepCopy()
+	archived.SetGroupVersionKind(v1alpha1.GroupVersionKind)
+	archived.SetResourceVersion("")
+	archived.SetUID("")
+	archived.SetGeneration(0)
+	archived.SetCreationTimestamp(metav1.Time{})
+	archived.SetManagedFields(nil)
+	archived.SetOwnerReferences(nil)
+	return *archived
+}
+
+// WriteManifestArchive encodes the ManifestArchive as JSON.
+func WriteManifestArchive(writer io.Writer, archive *ManifestArchive) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(archive)
+}
+
+// ReadManifestArchive decodes a ManifestArchive written by WriteManifestArchive.
+func ReadManifestArchive(reader io.Reader) (*ManifestArchive, error) {
+	archive := &ManifestArchive{}
+	if err := json.NewDecoder(reader).Decode(archive); err != nil {
+		return nil, fmt.Errorf("decoding manifest archive: %w", err)
+	}
+	return archive, nil
+}
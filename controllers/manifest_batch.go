@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+const defaultBatchConcurrency = 5
+
+var ErrStatusMergePatch = errors.New("the status of manifests cannot be patched")
+
+// ManifestMutateFunc changes the spec, labels or annotations of the passed Manifest in place
+// and returns false if the Manifest is left unchanged.
+type ManifestMutateFunc func(manifestObj *v1alpha1.Manifest) (bool, error)
+
+// ManifestBatchReport summarizes a batch update of Manifests.
+type ManifestBatchReport struct {
+	Matched   int `json:"matched"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	// Failed are the errors of all Manifests which could not be updated by their namespaced name
+	Failed map[string]string `json:"failed,omitempty"`
+}
+
+// ManifestBatchUpdater patches many Manifests, e.g. for a channel bump of a module catalog or a policy change.
+// Manifests are patched with Concurrency parallel requests. Each patch carries the resource version of the
+// Manifest it was computed from, and is recomputed from the latest version on conflicts, up to Retries times.
+// A failed Manifest does not stop the batch, all failures are collected in the ManifestBatchReport.
+type ManifestBatchUpdater struct {
+	Client client.Client
+	Logger logr.Logger
+	// Concurrency is the number of Manifests patched at a time, defaults to 5
+	Concurrency int
+	// Retries is the number of retries of a patch on conflicts, defaults to the client-go default
+	Retries int
+}
+
+// Update lists the Manifests matching the passed list options, e.g. client.MatchingLabelsSelector,
+// and patches all Manifests changed by mutate. Manifests being deleted are skipped.
+func (u *ManifestBatchUpdater) Update(ctx context.Context, mutate ManifestMutateFunc, opts ...client.ListOption,
+) (*ManifestBatchReport, error) {
+	manifestList := &v1alpha1.ManifestList{}
+	if err := u.Client.List(ctx, manifestList, opts...); err != nil {
+		return nil, fmt.Errorf("listing manifests for batch update: %w", err)
+	}
+
+	concurrency := u.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+	report := &ManifestBatchReport{Failed: map[string]string{}}
+	var reportLock sync.Mutex
+	var waitGroup sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+
+	for i := range manifestList.Items {
+		manifestObj := &manifestList.Items[i]
+		if !manifestObj.DeletionTimestamp.IsZero() {
+			continue
+		}
+		report.Matched++
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			waitGroup.Wait()
+			return report, ctx.Err()
+		}
+		waitGroup.Add(1)
+		go func() {
+			defer func() {
+				<-semaphore
+				waitGroup.Done()
+			}()
+			key := client.ObjectKeyFromObject(manifestObj)
+			updated, err := u.update(ctx, manifestObj, mutate)
+
+			reportLock.Lock()
+			defer reportLock.Unlock()
+			switch {
+			case err != nil:
+				u.Logger.Error(err, "batch update of manifest failed", "resource", key.String())
+				report.Failed[key.String()] = err.Error()
+			case updated:
+				u.Logger.V(util.DebugLogLevel).Info("batch updated manifest", "resource", key.String())
+				report.Updated++
+			default:
+				report.Unchanged++
+			}
+		}()
+	}
+	waitGroup.Wait()
+	return report, nil
+}
+
+// update patches the Manifest with the changes of mutate, which is re-run on the latest version on conflicts.
+func (u *ManifestBatchUpdater) update(ctx context.Context, manifestObj *v1alpha1.Manifest,
+	mutate ManifestMutateFunc,
+) (bool, error) {
+	backoff := retry.DefaultRetry
+	if u.Retries > 0 {
+		backoff.Steps = u.Retries + 1
+	}
+	updated := false
+	first := true
+	err := retry.RetryOnConflict(backoff, func() error {
+		if !first {
+			if err := u.Client.Get(ctx, client.ObjectKeyFromObject(manifestObj), manifestObj); err != nil {
+				return err
+			}
+		}
+		first = false
+		original := manifestObj.DeepCopy()
+		changed, err := mutate(manifestObj)
+		if err != nil || !changed {
+			return err
+		}
+		patch := client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})
+		if err := u.Client.Patch(ctx, manifestObj, patch); err != nil {
+			return err
+		}
+		updated = true
+		return nil
+	})
+	return updated, err
+}
+
+// MergePatchMutation returns a ManifestMutateFunc applying the JSON merge patch (RFC 7386) to the spec
+// and metadata of Manifests, e.g. {"spec":{"installs":[...]}} or {"metadata":{"annotations":{...}}}.
+// Patches of the status are rejected, as it is owned by the operator.
+func MergePatchMutation(mergePatch []byte) (ManifestMutateFunc, error) {
+	patchObj := map[string]any{}
+	if err := json.Unmarshal(mergePatch, &patchObj); err != nil {
+		return nil, fmt.Errorf("invalid merge patch: %w", err)
+	}
+	if _, found := patchObj["status"]; found {
+		return nil, ErrStatusMergePatch
+	}
+
+	return func(manifestObj *v1alpha1.Manifest) (bool, error) {
+		original, err := json.Marshal(manifestObj)
+		if err != nil {
+			return false, err
+		}
+		patched, err := jsonpatch.MergePatch(original, mergePatch)
+		if err != nil {
+			return false, err
+		}
+		if jsonpatch.Equal(original, patched) {
+			return false, nil
+		}
+		patchedObj := &v1alpha1.Manifest{}
+		if err := json.Unmarshal(patched, patchedObj); err != nil {
+			return false, err
+		}
+		// only the passed Manifest is updated, so that the patch is computed against it
+		patchedObj.Status = manifestObj.Status
+		*manifestObj = *patchedObj
+		return true, nil
+	}, nil
+}
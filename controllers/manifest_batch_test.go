@@ -0,0 +1,148 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/controllers"
+)
+
+var errBatchTestMutation = errors.New("mutation failed")
+
+func Test_ManifestBatchUpdater_Report(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	deleting := newTestManifest("deleting", map[string]string{"module": "redis"})
+	deleting.Finalizers = []string{"test"}
+	deletionTime := metav1.Now()
+	deleting.DeletionTimestamp = &deletionTime
+	clnt := newFakeClientBuilder(t).WithObjects(
+		newTestManifest("update", map[string]string{"module": "redis"}),
+		newTestManifest("unchanged", map[string]string{"module": "redis", "channel": "fast"}),
+		newTestManifest("fail", map[string]string{"module": "redis"}),
+		newTestManifest("unselected", map[string]string{"module": "nginx"}),
+		deleting,
+	).Build()
+	updater := &controllers.ManifestBatchUpdater{Client: clnt, Logger: logr.Discard()}
+
+	report, err := updater.Update(ctx, func(manifestObj *v1alpha1.Manifest) (bool, error) {
+		if manifestObj.Name == "fail" {
+			return false, errBatchTestMutation
+		}
+		if manifestObj.Labels["channel"] == "fast" {
+			return false, nil
+		}
+		manifestObj.Labels["channel"] = "fast"
+		return true, nil
+	}, client.MatchingLabels{"module": "redis"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Matched, "manifests being deleted are skipped")
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 1, report.Unchanged)
+	assert.Equal(t, map[string]string{"default/fail": errBatchTestMutation.Error()}, report.Failed)
+
+	updated := &v1alpha1.Manifest{}
+	require.NoError(t, clnt.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "update"}, updated))
+	assert.Equal(t, "fast", updated.Labels["channel"])
+}
+
+func Test_ManifestBatchUpdater_Concurrency(t *testing.T) {
+	t.Parallel()
+	builder := newFakeClientBuilder(t)
+	for i := 0; i < 6; i++ {
+		builder = builder.WithObjects(newTestManifest(fmt.Sprintf("manifest-%d", i), nil))
+	}
+	updater := &controllers.ManifestBatchUpdater{Client: builder.Build(), Logger: logr.Discard(), Concurrency: 2}
+
+	var running, maxRunning int32
+	report, err := updater.Update(context.Background(), func(manifestObj *v1alpha1.Manifest) (bool, error) {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			observed := atomic.LoadInt32(&maxRunning)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return false, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 6, report.Unchanged)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maxRunning), "manifests are patched with the configured concurrency")
+}
+
+func Test_ManifestBatchUpdater_ConflictRetries(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	clnt := newFakeClientBuilder(t).WithObjects(newTestManifest("manifest", nil)).Build()
+	updater := &controllers.ManifestBatchUpdater{Client: clnt, Logger: logr.Discard(), Retries: 2}
+
+	// every mutation but the last one races with a concurrent update, so that its patch conflicts
+	newUpdater := func(conflicts int) (controllers.ManifestMutateFunc, *int) {
+		var lock sync.Mutex
+		attempts := 0
+		return func(manifestObj *v1alpha1.Manifest) (bool, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			attempts++
+			if attempts <= conflicts {
+				concurrent := manifestObj.DeepCopy()
+				concurrent.Labels = map[string]string{"concurrent": fmt.Sprint(attempts)}
+				// mutations run in the goroutines of the updater, so that failures are only asserted
+				assert.NoError(t, clnt.Update(ctx, concurrent))
+			}
+			if manifestObj.Labels == nil {
+				manifestObj.Labels = map[string]string{}
+			}
+			manifestObj.Labels["batch"] = "patched"
+			return true, nil
+		}, &attempts
+	}
+
+	mutate, attempts := newUpdater(2)
+	report, err := updater.Update(ctx, mutate)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 3, *attempts, "conflicting patches are recomputed from the latest version")
+	manifestObj := &v1alpha1.Manifest{}
+	require.NoError(t, clnt.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "manifest"}, manifestObj))
+	assert.Equal(t, map[string]string{"concurrent": "2", "batch": "patched"}, manifestObj.Labels)
+
+	mutate, attempts = newUpdater(3)
+	report, err = updater.Update(ctx, mutate)
+	require.NoError(t, err)
+	assert.Len(t, report.Failed, 1, "patches still conflicting after all retries fail")
+	assert.Equal(t, 3, *attempts)
+}
+
+func Test_MergePatchMutation(t *testing.T) {
+	t.Parallel()
+	_, err := controllers.MergePatchMutation([]byte(`{"status": {"state": "Ready"}}`))
+	assert.ErrorIs(t, err, controllers.ErrStatusMergePatch)
+
+	mutate, err := controllers.MergePatchMutation([]byte(`{"metadata": {"labels": {"channel": "fast"}}}`))
+	require.NoError(t, err)
+	manifestObj := newTestManifest("manifest", map[string]string{"module": "redis"})
+	manifestObj.Status.State = v1alpha1.ManifestStateReady
+	changed, err := mutate(manifestObj)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, map[string]string{"module": "redis", "channel": "fast"}, manifestObj.Labels)
+	assert.Equal(t, v1alpha1.ManifestStateReady, manifestObj.Status.State)
+	changed, err = mutate(manifestObj)
+	require.NoError(t, err)
+	assert.False(t, changed, "patches already applied leave manifests unchanged")
+}
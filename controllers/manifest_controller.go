@@ -24,8 +24,12 @@ import (
 	"strings"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
 
 	"github.com/kyma-project/module-manager/pkg/cache"
@@ -33,27 +37,35 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/time/rate"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/kyma-project/module-manager/api/v1alpha1"
 	"github.com/kyma-project/module-manager/internal/pkg/prepare"
 	internalTypes "github.com/kyma-project/module-manager/internal/pkg/types"
 	internalUtil "github.com/kyma-project/module-manager/internal/pkg/util"
+	"github.com/kyma-project/module-manager/pkg/descriptor"
 	"github.com/kyma-project/module-manager/pkg/labels"
+	logging "github.com/kyma-project/module-manager/pkg/log"
 	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/tracing"
 	"github.com/kyma-project/module-manager/pkg/types"
 	"github.com/kyma-project/module-manager/pkg/util"
+	"github.com/kyma-project/module-manager/pkg/version"
 	listener "github.com/kyma-project/runtime-watcher/listener/pkg/event"
 )
 
@@ -78,17 +90,46 @@ type ManifestReconciler struct {
 	CacheManager     types.CacheManager
 	internalTypes.ReconcileFlagConfig
 	CacheSyncTimeout time.Duration
+	// RegistryWebhookAddr is the address of the RegistryWebhookListener, an empty address disables it
+	RegistryWebhookAddr string
+	// RegistryWebhookSecret authenticates the push notifications received by the RegistryWebhookListener
+	RegistryWebhookSecret []byte
+	// WatchModuleCatalogs enqueues Manifests following channels of ModuleCatalogs, once the channels advance
+	WatchModuleCatalogs bool
+	// resourceWatcher enqueues Manifests on changes of their applied resources, if WatchInstalledResources is set
+	resourceWatcher *ResourceWatcher
+	// InstallLockDuration is the lease duration of the InstallLocker guarding operations of Manifests
+	// against concurrent operations of other replicas, zero disables the lock
+	InstallLockDuration time.Duration
+	installLocker       *InstallLocker
+	// IsolationGroups caps the Manifests processed at the same time per target cluster or explicit group,
+	// nil disables the limit
+	IsolationGroups *IsolationGroups
+	// HealthMonitor records reconciles for the readiness check of the operator, nil disables it
+	HealthMonitor *HealthMonitor
+	// UninstallRetryBudget is the number of failed uninstall attempts, after which the finalizer of a deleted
+	// Manifest annotated with labels.ForceDeleteAnnotation is removed regardless, zero disables force deletion
+	UninstallRetryBudget int
+	// Recorder records events of Manifests, e.g. for the resources left behind by a forced deletion
+	Recorder record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=operator.kyma-project.io,resources=manifests,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=operator.kyma-project.io,resources=manifests/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=operator.kyma-project.io,resources=manifests/finalizers,verbs=update
+//+kubebuilder:rbac:groups=operator.kyma-project.io,resources=modulecatalogs,verbs=get;list;watch
+//+kubebuilder:rbac:groups=operator.kyma-project.io,resources=modulereleases,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch;get;list;watch
-//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups="",namespace=default,resources=secrets,verbs=create;update;patch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=list
+//+kubebuilder:rbac:groups="",resources=resourcequotas,verbs=list
+//+kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-func (r *ManifestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *ManifestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	logger := log.FromContext(ctx)
 	logger.Info("Reconciliation loop starting for", "resource", req.NamespacedName.String())
 
@@ -102,6 +143,22 @@ func (r *ManifestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 	manifestObj = *manifestObj.DeepCopy()
+	// all further log lines of the reconcile, also of the manifest library, are tagged with module and cluster
+	ctx, logger = logging.ForModule(ctx, ModuleName(&manifestObj), TargetCluster(&manifestObj))
+	// the reconcile continues the trace propagated by the creator of the Manifest, if any
+	ctx = tracing.ExtractFromAnnotations(ctx, manifestObj.GetAnnotations(), labels.TraceParentAnnotation)
+	ctx, span := tracing.Start(ctx, tracing.SpanReconcile,
+		attribute.String("manifest", req.NamespacedName.String()),
+		attribute.String("module", ModuleName(&manifestObj)),
+		attribute.String("state", string(manifestObj.Status.State)),
+		attribute.String("targetCluster", TargetCluster(&manifestObj)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	// paused Manifests are left untouched, apart from their Paused condition, until they are resumed
+	if handled, err := r.handlePause(ctx, logger, &manifestObj); handled || err != nil {
+		return ctrl.Result{Requeue: !manifestObj.IsPaused()}, err
+	}
 
 	// check if deletionTimestamp is set, retry until it gets fully deleted
 	if !manifestObj.DeletionTimestamp.IsZero() && manifestObj.Status.State != v1alpha1.ManifestStateDeleting {
@@ -115,6 +172,28 @@ func (r *ManifestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, r.updateManifest(ctx, &manifestObj)
 	}
 
+	// Manifests of the same isolation group, e.g. of the same target cluster, are capped,
+	// so that a slow cluster cannot occupy all reconciles, while other groups are processed in parallel
+	if r.IsolationGroups != nil {
+		group := IsolationGroup(&manifestObj)
+		slot := r.IsolationGroups.TryAcquire(group)
+		if slot == nil {
+			logger.V(util.DebugLogLevel).Info("isolation group at its limit, retrying later",
+				"resource", req.NamespacedName.String(), "group", group)
+			return ctrl.Result{RequeueAfter: r.IsolationGroups.RetryInterval}, nil
+		}
+		defer slot.releaseUnlessHandedOff()
+		ctx = withIsolationSlot(ctx, slot)
+	}
+
+	// preview changes instead of applying them while annotated for a dry-run
+	if manifestObj.DeletionTimestamp.IsZero() && manifestObj.IsDryRun() {
+		return ctrl.Result{RequeueAfter: r.RequeueIntervals.Success}, r.HandleDryRun(ctx, logger, &manifestObj)
+	} else if len(manifestObj.Status.Preview) > 0 {
+		manifestObj.Status.Preview = nil
+		return ctrl.Result{}, r.Status().Update(ctx, &manifestObj)
+	}
+
 	// state handling
 	switch manifestObj.Status.State {
 	case "":
@@ -124,8 +203,15 @@ func (r *ManifestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	case v1alpha1.ManifestStateDeleting:
 		return ctrl.Result{Requeue: true}, r.HandleDeletingState(ctx, logger, &manifestObj)
 	case v1alpha1.ManifestStateError:
+		// terminal errors persist on retry, they are only retried once the spec of the Manifest changed
+		if !manifestObj.IsSpecUpdated() &&
+			manifestObj.Status.ErrorClassification == types.ErrorClassificationTerminal {
+			logger.Info("skipping reconciliation of terminal error until the spec changes",
+				"resource", req.NamespacedName.String())
+			return ctrl.Result{}, nil
+		}
 		return ctrl.Result{Requeue: true}, r.HandleProcessingState(ctx, logger, &manifestObj)
-	case v1alpha1.ManifestStateReady:
+	case v1alpha1.ManifestStateReady, v1alpha1.ManifestStateWarning:
 		return ctrl.Result{RequeueAfter: r.RequeueIntervals.Success}, r.HandleReadyState(ctx, logger, &manifestObj)
 	}
 
@@ -150,21 +236,54 @@ func (r *ManifestReconciler) HandleDeletingState(ctx context.Context, logger log
 	return r.sendJobToInstallChannel(ctx, logger, manifestObj, internalTypes.DeletionMode)
 }
 
+// HandleDryRun records the changes an install of the Manifest would apply in its status, without applying them.
+func (r *ManifestReconciler) HandleDryRun(ctx context.Context, logger logr.Logger, manifestObj *v1alpha1.Manifest,
+) error {
+	deployInfos, err := prepare.GetInstallInfos(ctx, manifestObj, types.ClusterInfo{
+		Client: r.Client, Config: r.RESTConfig,
+	}, r.ReconcileFlagConfig, r.CacheManager.GetRendererCache())
+	if err != nil {
+		return err
+	}
+
+	var preview []types.ResourceDiff
+	for _, deployInfo := range deployInfos {
+		result, err := manifest.DryRunChart(manifest.OperationOptions{
+			Logger:      logger,
+			InstallInfo: deployInfo,
+			Cache:       r.CacheManager.GetRendererCache(),
+		})
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("error while performing dry-run on manifest %s",
+				client.ObjectKeyFromObject(manifestObj)))
+			return err
+		}
+		preview = append(preview, result.Diffs...)
+	}
+
+	if equality.Semantic.DeepEqual(manifestObj.Status.Preview, preview) {
+		return nil
+	}
+	manifestObj.Status.Preview = preview
+	return r.Status().Update(ctx, manifestObj)
+}
+
 func (r *ManifestReconciler) sendJobToInstallChannel(ctx context.Context, logger logr.Logger,
 	manifestObj *v1alpha1.Manifest, mode internalTypes.Mode,
 ) error {
 	namespacedName := client.ObjectKeyFromObject(manifestObj)
 	responseChan := make(internalTypes.ResponseChan)
 
-	chartCount := len(manifestObj.Spec.Installs)
-
-	// response handler in a separate go-routine
-	go r.ResponseHandlerFunc(ctx, logger, chartCount, responseChan, namespacedName)
-
 	// send deploy requests
 	deployInfos, err := prepare.GetInstallInfos(ctx, manifestObj, types.ClusterInfo{
 		Client: r.Client, Config: r.RESTConfig,
 	}, r.ReconcileFlagConfig, r.CacheManager.GetRendererCache())
+	// installs changing their chart version outside of the upgrade windows keep their installed version
+	var held sets.String
+	previousConditions := append([]v1alpha1.ManifestCondition(nil), manifestObj.Status.Conditions...)
+	if err == nil && mode == internalTypes.CreateMode {
+		held, err = holdUpgrades(manifestObj, deployInfos)
+	}
 	if err != nil {
 		logger.Error(err, fmt.Sprintf("cannot prepare install information for %s resource %s",
 			v1alpha1.ManifestKind, namespacedName))
@@ -174,12 +293,49 @@ func (r *ManifestReconciler) sendJobToInstallChannel(ctx context.Context, logger
 			// so remove finalizer in this case, to process with Manifest deletion
 			return r.finalizeDeletion(ctx, manifestObj)
 		}
+		var verificationErr *types.ChartVerificationError
+		if errors.As(err, &verificationErr) {
+			internalUtil.SetVerifiedCondition(manifestObj, verificationErr.Install, nil, verificationErr.Err)
+		}
+		manifestObj.Status.ErrorClassification = manifest.ClassifyError(err)
 		if err := r.updateManifestStatus(ctx, manifestObj, v1alpha1.ManifestStateError, err.Error()); err != nil {
 			return err
 		}
 		return err
 	}
 
+	// installs removed from the spec are uninstalled before the remaining installs are processed
+	if err := r.uninstallRemovedInstalls(ctx, logger, manifestObj, deployInfos); err != nil {
+		return err
+	}
+	if !equality.Semantic.DeepEqual(previousConditions, manifestObj.Status.Conditions) {
+		if err := r.Status().Update(ctx, manifestObj); err != nil {
+			return err
+		}
+	}
+	deployInfos = withoutHeldUpgrades(deployInfos, held)
+
+	// response handler in a separate go-routine, which releases the slot of the isolation group once all
+	// responses are handled
+	slot := isolationSlotFrom(ctx)
+	chartCount := len(deployInfos)
+	go func() {
+		r.ResponseHandlerFunc(ctx, logger, chartCount, responseChan, namespacedName)
+		if slot != nil {
+			slot.Release()
+		}
+	}()
+
+	// the operations continue after the reconciliation, so they hold the slot of the isolation group until then
+	if slot != nil {
+		slot.handedOff = true
+	}
+
+	if manifestObj.Spec.InstallOrder == v1alpha1.InstallOrderSequential {
+		go r.sendJobsSequentially(ctx, deployInfos, mode, responseChan)
+		return nil
+	}
+
 	// send processing requests (installation / uninstallation) to deployment channel
 	// each individual request will be processed by the next available worker
 	for _, deployInfo := range deployInfos {
@@ -192,16 +348,145 @@ func (r *ManifestReconciler) sendJobToInstallChannel(ctx context.Context, logger
 	return nil
 }
 
+// uninstallRemovedInstalls uninstalls the resources of all applied installs, which were removed from the spec
+// of the Manifest, while the resources of the remaining installs stay untouched.
+// The removed installs are uninstalled on the target cluster of the remaining installs.
+// Removed installs without an inventory stay applied and report why in their Uninstalled condition.
+func (r *ManifestReconciler) uninstallRemovedInstalls(ctx context.Context, logger logr.Logger,
+	manifestObj *v1alpha1.Manifest, deployInfos []*types.InstallInfo,
+) error {
+	removedInstalls := internalUtil.RemovedInstalls(manifestObj)
+	internalUtil.RemoveUninstalledConditions(manifestObj, removedInstalls)
+	if len(removedInstalls) == 0 || len(deployInfos) == 0 {
+		return nil
+	}
+	var uninstalled []string
+	for _, removedInstall := range removedInstalls {
+		installInfo := *deployInfos[0]
+		if installInfo.ChartInfo != nil {
+			// the backup policy of the removed install is unknown, its snapshot is located by its reference instead
+			chartInfo := *installInfo.ChartInfo
+			chartInfo.Backup = manifestObj.BackupLocation(removedInstall)
+			installInfo.ChartInfo = &chartInfo
+		}
+		err := manifest.UninstallRemovedRelease(manifest.OperationOptions{
+			Logger:      logger,
+			InstallInfo: &installInfo,
+			Cache:       r.CacheManager.GetRendererCache(),
+		}, removedInstall)
+		if errors.Is(err, manifest.ErrRemovedInstallUntracked) {
+			logger.Info("removed install kept, as its resources are unknown",
+				"resource", client.ObjectKeyFromObject(manifestObj).String(), "install", removedInstall)
+			internalUtil.SetUninstalledCondition(manifestObj, removedInstall, err)
+			continue
+		}
+		if err != nil {
+			logger.Error(err, "cannot uninstall removed install",
+				"resource", client.ObjectKeyFromObject(manifestObj).String(), "install", removedInstall)
+			manifestObj.Status.ErrorClassification = manifest.ClassifyError(err)
+			if err := r.updateManifestStatus(ctx, manifestObj, v1alpha1.ManifestStateError, err.Error()); err != nil {
+				return err
+			}
+			return err
+		}
+		logger.Info("uninstalled removed install",
+			"resource", client.ObjectKeyFromObject(manifestObj).String(), "install", removedInstall)
+		internalUtil.ForgetInstall(manifestObj, removedInstall)
+		uninstalled = append(uninstalled, removedInstall)
+	}
+	if len(uninstalled) == 0 {
+		return nil
+	}
+	return r.updateManifestStatus(ctx, manifestObj, manifestObj.Status.State,
+		fmt.Sprintf("uninstalled removed installs: %s", strings.Join(uninstalled, ", ")))
+}
+
+// sendJobsSequentially sends processing requests to the deployment channel one at a time,
+// each only after the response of the previous request is ready. Installations are processed in the order
+// of the deployInfos, uninstallations in reverse order. Once a request is not ready, all subsequent
+// requests are reported as still processing without being sent.
+func (r *ManifestReconciler) sendJobsSequentially(ctx context.Context, deployInfos []*types.InstallInfo,
+	mode internalTypes.Mode, responseChan internalTypes.ResponseChan,
+) {
+	ordered := make([]*types.InstallInfo, len(deployInfos))
+	copy(ordered, deployInfos)
+	if mode == internalTypes.DeletionMode {
+		for i, j := 0, len(ordered)-1; i < j; i, j = i+1, j-1 {
+			ordered[i], ordered[j] = ordered[j], ordered[i]
+		}
+	}
+
+	itemResponseChan := make(internalTypes.ResponseChan)
+	gated := false
+	for _, deployInfo := range ordered {
+		response := &internalTypes.InstallResponse{
+			ResNamespacedName: client.ObjectKeyFromObject(deployInfo.BaseResource),
+			ChartName:         deployInfo.ChartName,
+			Flags:             deployInfo.Flags,
+		}
+		if !gated {
+			select {
+			case r.DeployChan <- OperationRequest{Info: deployInfo, Mode: mode, ResponseChan: itemResponseChan}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case response = <-itemResponseChan:
+			case <-ctx.Done():
+				return
+			}
+			gated = response.Err != nil || !response.Ready
+		}
+		select {
+		case responseChan <- response:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 func (r *ManifestReconciler) HandleReadyState(ctx context.Context, logger logr.Logger, manifestObj *v1alpha1.Manifest,
 ) error {
 	namespacedName := client.ObjectKeyFromObject(manifestObj)
+	previousConditions := append([]v1alpha1.ManifestCondition(nil), manifestObj.Status.Conditions...)
+	var held sets.String
 	if manifestObj.IsSpecUpdated() {
-		logger.Info("observed generation change for " + namespacedName.String())
-		return r.updateManifestStatus(ctx, manifestObj, v1alpha1.ManifestStateProcessing,
-			"observed generation change")
+		// spec changes wait for the stage of the rollout of a ModuleRelease releasing the Manifest
+		if rolloutHeld, err := r.isRolloutHeld(ctx, manifestObj); err != nil {
+			return err
+		} else if rolloutHeld {
+			logger.Info("spec change held by the rollout of module release "+manifestObj.RolloutHold(),
+				"resource", namespacedName.String())
+			return nil
+		}
+		var err error
+		if held, err = r.heldUpgrades(ctx, manifestObj); err != nil {
+			return err
+		}
+		if held.Len() == 0 {
+			if r.isNoOpSpecChange(ctx, manifestObj) {
+				logger.Info("observed generation change without changes to applied charts for " + namespacedName.String())
+				return r.updateManifestStatus(ctx, manifestObj, v1alpha1.ManifestStateReady,
+					"observed generation change without changes to applied charts")
+			}
+			logger.Info("observed generation change for " + namespacedName.String())
+			return r.updateManifestStatus(ctx, manifestObj, v1alpha1.ManifestStateProcessing,
+				"observed generation change")
+		}
+		// only the installs keeping their chart version are checked until the next upgrade window
+		logger.Info("chart version changes wait for the next upgrade window", "resource", namespacedName.String(),
+			"installs", strings.Join(held.List(), ", "))
+	}
+
+	// changed values of Secrets are applied like changes of the spec
+	if changed, err := r.secretValuesChanged(ctx, manifestObj); err != nil {
+		return err
+	} else if changed {
+		logger.Info("secret values changed for " + namespacedName.String())
+		return r.updateManifestStatus(ctx, manifestObj, v1alpha1.ManifestStateProcessing, "secret values changed")
 	}
 
-	logger.V(1).Info("checking consistent state for " + namespacedName.String())
+	logger.V(util.DebugLogLevel).Info("checking consistent state for " + namespacedName.String())
 
 	// send deploy requests
 	deployInfos, err := prepare.GetInstallInfos(ctx, manifestObj, types.ClusterInfo{
@@ -211,13 +496,48 @@ func (r *ManifestReconciler) HandleReadyState(ctx context.Context, logger logr.L
 		return err
 	}
 
-	for _, deployInfo := range deployInfos {
+	// installs following a channel are upgraded once it advances, unless held until an upgrade window
+	if advanced := advancedChannels(deployInfos); advanced.Len() > 0 {
+		if held == nil {
+			if held, err = holdUpgrades(manifestObj, deployInfos); err != nil {
+				return err
+			}
+		}
+		if upgraded := advanced.Difference(held); upgraded.Len() > 0 {
+			logger.Info("channels advanced for "+namespacedName.String(), "installs", strings.Join(upgraded.List(), ", "))
+			return r.updateManifestStatus(ctx, manifestObj, v1alpha1.ManifestStateProcessing, "channels advanced")
+		}
+	}
+
+	var degraded []string
+	for _, deployInfo := range withoutHeldUpgrades(deployInfos, held) {
+		var healthVerified bool
+		var healthIssues []types.HealthIssue
 		ready, err := manifest.ConsistencyCheck(manifest.OperationOptions{
 			Logger:      logger,
 			InstallInfo: deployInfo,
 			Cache:       r.CacheManager.GetRendererCache(),
+			ReportHealth: func(reported []types.HealthIssue) {
+				healthVerified = true
+				healthIssues = reported
+			},
+			ReportMissingAPIs: func(reported []types.MissingAPI) {
+				internalUtil.SetPrerequisitesMetCondition(manifestObj, deployInfo.ChartName, reported)
+			},
 		})
 
+		if healthVerified {
+			internalUtil.SetHealthyCondition(manifestObj, deployInfo.ChartName, healthIssues)
+		}
+		// degraded workloads of an installed chart are reported instead of reinstalling the chart,
+		// so that the Manifest does not flap between Processing and Ready while they recover
+		if len(healthIssues) > 0 {
+			for _, issue := range healthIssues {
+				degraded = append(degraded, issue.String())
+			}
+			continue
+		}
+
 		// prepare chart response object
 		chartResponse := &internalTypes.InstallResponse{
 			Ready:             ready,
@@ -236,12 +556,34 @@ func (r *ManifestReconciler) HandleReadyState(ctx context.Context, logger logr.L
 		} else if err != nil {
 			logger.Error(err, fmt.Sprintf("error while performing consistency check on manifest %s", namespacedName))
 			internalUtil.AddReadyConditionForResponses([]*internalTypes.InstallResponse{chartResponse}, logger, manifestObj)
+			manifestObj.Status.ErrorClassification = manifest.ClassifyError(err)
 			if err := r.updateManifestStatus(ctx, manifestObj, v1alpha1.ManifestStateError, err.Error()); err != nil {
 				return err
 			}
 			return err
 		}
 	}
+
+	if len(degraded) > 0 {
+		logger.Info("workloads of manifest are degraded", "resource", namespacedName.String(),
+			"issues", strings.Join(degraded, "; "))
+		if manifestObj.Status.State != v1alpha1.ManifestStateWarning ||
+			!equality.Semantic.DeepEqual(previousConditions, manifestObj.Status.Conditions) {
+			return r.updateManifestStatus(ctx, manifestObj, v1alpha1.ManifestStateWarning,
+				"workloads are degraded: "+strings.Join(degraded, "; "))
+		}
+		return nil
+	}
+	if manifestObj.Status.State == v1alpha1.ManifestStateWarning {
+		return r.updateManifestStatus(ctx, manifestObj, v1alpha1.ManifestStateReady, "workloads recovered")
+	}
+
+	// stamp Manifests reconciled by a previous version, e.g. after an upgrade of the operator,
+	// or record the health of workloads verified for the first time
+	if manifestObj.Status.ManagedBy != version.ManagedBy() ||
+		!equality.Semantic.DeepEqual(previousConditions, manifestObj.Status.Conditions) {
+		return r.updateManifestStatus(ctx, manifestObj, v1alpha1.ManifestStateReady, "all workloads are healthy")
+	}
 	return nil
 }
 
@@ -251,8 +593,15 @@ func (r *ManifestReconciler) updateManifest(ctx context.Context, manifestObj *v1
 
 func (r *ManifestReconciler) updateManifestStatus(ctx context.Context, manifestObj *v1alpha1.Manifest,
 	state v1alpha1.ManifestState, message string,
-) error {
+) (err error) {
+	ctx, span := tracing.Start(ctx, tracing.SpanStateTransition,
+		attribute.String("from", string(manifestObj.Status.State)), attribute.String("to", string(state)))
+	defer func() { tracing.End(span, err) }()
 	manifestObj.Status.State = state
+	manifestObj.Status.ManagedBy = version.ManagedBy()
+	if state != v1alpha1.ManifestStateError {
+		manifestObj.Status.ErrorClassification = ""
+	}
 	switch state {
 	case v1alpha1.ManifestStateReady:
 		internalUtil.AddReadyConditionForObjects(manifestObj, []v1alpha1.InstallItem{{ChartName: v1alpha1.ManifestKind}},
@@ -262,11 +611,16 @@ func (r *ManifestReconciler) updateManifestStatus(ctx context.Context, manifestO
 			v1alpha1.ConditionStatusUnknown, message)
 	case v1alpha1.ManifestStateError,
 		v1alpha1.ManifestStateDeleting,
-		v1alpha1.ManifestStateProcessing:
+		v1alpha1.ManifestStateProcessing,
+		v1alpha1.ManifestStateWarning:
 		internalUtil.AddReadyConditionForObjects(manifestObj, []v1alpha1.InstallItem{{ChartName: v1alpha1.ManifestKind}},
 			v1alpha1.ConditionStatusFalse, message)
 	}
-	return r.Status().Update(ctx, manifestObj.SetObservedGeneration())
+	// the spec is only observed once no upgrade waits for an upgrade window, so that held upgrades are applied later
+	if internalUtil.PendingUpgrades(manifestObj).Len() == 0 {
+		manifestObj.SetObservedGeneration()
+	}
+	return r.Status().Update(ctx, manifestObj)
 }
 
 func (r *ManifestReconciler) HandleCharts(deployInfo *types.InstallInfo, mode internalTypes.Mode,
@@ -275,13 +629,93 @@ func (r *ManifestReconciler) HandleCharts(deployInfo *types.InstallInfo, mode in
 	// evaluate create or delete chart
 	create := mode == internalTypes.CreateMode
 
+	if r.installLocker != nil {
+		ctx := deployInfo.Ctx
+		lockCtx, lock, err := r.installLocker.Acquire(ctx, deployInfo.BaseResource)
+		if err != nil || lock == nil {
+			// operations of another replica are still running, the install is processed once they finished
+			return &internalTypes.InstallResponse{
+				ResNamespacedName: client.ObjectKeyFromObject(deployInfo.BaseResource),
+				Err:               err,
+				ChartName:         deployInfo.ChartName,
+				Flags:             deployInfo.Flags,
+			}
+		}
+		// the operation is cancelled once another replica takes over the lock
+		deployInfo.Ctx = lockCtx
+		defer func() {
+			deployInfo.Ctx = ctx
+			r.installLocker.Release(ctx, lock)
+		}()
+	}
+
 	var ready bool
 	var err error
+	var findings []types.SecurityFinding
+	var release *types.ReleaseRevision
+	var schedulingVerified bool
+	var schedulingIssues []types.SchedulingIssue
+	var capacityVerified bool
+	var capacityShortages []types.CapacityShortage
+	var apisVerified bool
+	var missingAPIs []types.MissingAPI
+	var upgradeVerified bool
+	var upgradeViolation error
+	var appliedMigrations []string
+	var waitForVerified bool
+	var unsatisfiedWaits []types.UnsatisfiedWait
+	var applyStatus *types.InstallApplyStatus
+	var backup *types.BackupReference
 
 	options := manifest.OperationOptions{
 		Logger:      logger,
 		InstallInfo: deployInfo,
 		Cache:       r.CacheManager.GetRendererCache(),
+		ReportFindings: func(reported []types.SecurityFinding) {
+			findings = reported
+		},
+		ReportRelease: func(reported types.ReleaseRevision) {
+			release = &reported
+		},
+		ReportScheduling: func(reported []types.SchedulingIssue) {
+			schedulingVerified = true
+			schedulingIssues = reported
+		},
+		ReportMissingAPIs: func(reported []types.MissingAPI) {
+			apisVerified = true
+			missingAPIs = reported
+		},
+		ReportUpgrade: func(reported error) {
+			upgradeVerified = true
+			upgradeViolation = reported
+		},
+		ReportMigrations: func(reported []string) {
+			appliedMigrations = reported
+		},
+		ReportWaitFor: func(reported []types.UnsatisfiedWait) {
+			waitForVerified = true
+			unsatisfiedWaits = reported
+		},
+		ReportApply: func(reported types.InstallApplyStatus) {
+			applyStatus = &reported
+		},
+		ReportBackup: func(reported types.BackupReference) {
+			backup = &reported
+		},
+	}
+	if !r.SkipCapacityVerification {
+		options.ReportCapacity = func(reported []types.CapacityShortage) {
+			capacityVerified = true
+			capacityShortages = reported
+		}
+	}
+	if r.resourceWatcher != nil {
+		options.ReportResources = func(resources []schema.GroupVersionResource) {
+			if err := r.resourceWatcher.Watch(deployInfo.Config, resources); err != nil {
+				logger.Error(err, "unable to watch applied resources",
+					"resource", client.ObjectKeyFromObject(deployInfo.BaseResource).String())
+			}
+		}
 	}
 	if create {
 		ready, err = manifest.InstallChart(options)
@@ -289,12 +723,36 @@ func (r *ManifestReconciler) HandleCharts(deployInfo *types.InstallInfo, mode in
 		ready, err = manifest.UninstallChart(options)
 	}
 
+	// values hash is only used for the last applied state, failing to calculate it is not critical
+	valuesHash, _ := internalUtil.InstallValuesHash(deployInfo)
+
 	return &internalTypes.InstallResponse{
-		Ready:             ready,
-		ResNamespacedName: client.ObjectKeyFromObject(deployInfo.BaseResource),
-		Err:               err,
-		ChartName:         deployInfo.ChartName,
-		Flags:             deployInfo.Flags,
+		Ready:              ready,
+		ResNamespacedName:  client.ObjectKeyFromObject(deployInfo.BaseResource),
+		Err:                err,
+		ChartName:          deployInfo.ChartName,
+		ReleaseName:        deployInfo.ReleaseName,
+		ChartVersion:       manifest.ChartVersion(deployInfo),
+		ValuesHash:         valuesHash,
+		Flags:              deployInfo.Flags,
+		SecurityFindings:   findings,
+		Release:            release,
+		SchedulingVerified: schedulingVerified,
+		Verification:       deployInfo.Verification,
+		WaitForVerified:    waitForVerified,
+		UnsatisfiedWaits:   unsatisfiedWaits,
+		SchedulingIssues:   schedulingIssues,
+		CapacityVerified:   capacityVerified,
+		CapacityShortages:  capacityShortages,
+		APIsVerified:       apisVerified,
+		MissingAPIs:        missingAPIs,
+		UpgradeVerified:    upgradeVerified,
+		UpgradeViolation:   upgradeViolation,
+		AppliedMigrations:  appliedMigrations,
+		ApplyStatus:        applyStatus,
+		Channel:            deployInfo.Channel,
+		Image:              deployInfo.Image,
+		Backup:             backup,
 	}
 }
 
@@ -346,8 +804,73 @@ func (r *ManifestReconciler) ResponseHandlerFunc(ctx context.Context, logger log
 
 	internalUtil.AddReadyConditionForResponses(responses, logger, latestManifestObj)
 
+	latestManifestObj.Status.ErrorClassification = ""
+	for _, response := range responses {
+		latestManifestObj.Status.ErrorClassification = latestManifestObj.Status.ErrorClassification.
+			MoreSevere(manifest.ClassifyError(response.Err))
+	}
+
+	// findings and releases are only reported during installation
+	if latestManifestObj.DeletionTimestamp.IsZero() {
+		latestManifestObj.Status.SecurityFindings = nil
+		for _, response := range responses {
+			latestManifestObj.Status.SecurityFindings = append(latestManifestObj.Status.SecurityFindings,
+				response.SecurityFindings...)
+		}
+		latestManifestObj.Status.Releases = nil
+		for _, response := range responses {
+			if response.Release != nil {
+				latestManifestObj.Status.Releases = append(latestManifestObj.Status.Releases, *response.Release)
+			}
+		}
+		for _, response := range responses {
+			if response.SchedulingVerified {
+				internalUtil.SetSchedulableCondition(latestManifestObj, response.ChartName, response.SchedulingIssues)
+			}
+			if response.CapacityVerified {
+				internalUtil.SetSufficientCapacityCondition(latestManifestObj, response.ChartName,
+					response.CapacityShortages)
+			}
+			if response.APIsVerified {
+				internalUtil.SetPrerequisitesMetCondition(latestManifestObj, response.ChartName, response.MissingAPIs)
+			}
+			if response.UpgradeVerified {
+				internalUtil.SetUpgradeAllowedCondition(latestManifestObj, response.ChartName, response.UpgradeViolation)
+			}
+			if response.WaitForVerified {
+				internalUtil.SetDependenciesReadyCondition(latestManifestObj, response.ChartName, response.UnsatisfiedWaits)
+			}
+			if response.Verification != nil {
+				internalUtil.SetVerifiedCondition(latestManifestObj, response.ChartName, response.Verification, nil)
+			}
+		}
+		internalUtil.RecordAppliedMigrations(latestManifestObj, responses)
+		internalUtil.RecordAppliedInstalls(latestManifestObj, responses)
+		internalUtil.RecordInstalledVersions(latestManifestObj, responses)
+		internalUtil.RecordResolvedChannels(latestManifestObj, responses)
+		internalUtil.RecordResolvedImages(latestManifestObj, responses)
+		internalUtil.RecordLastApply(latestManifestObj, responses)
+		internalUtil.RecordBackups(latestManifestObj, responses)
+	}
+
+	// record what is actually deployed once all installs are ready
+	if latestManifestObj.DeletionTimestamp.IsZero() && !errorState && !processing {
+		r.setLastApplied(responses, latestManifestObj, logger)
+	}
+
+	// failed uninstalls are retried until the budget is exhausted, afterwards Manifests annotated
+	// for force deletion are finalized, so that they do not block the deletion of their namespace
+	forceDelete := false
+	if errorState && !pathError && !latestManifestObj.DeletionTimestamp.IsZero() {
+		latestManifestObj.Status.UninstallFailures++
+		forceDelete = r.isForceDeletable(latestManifestObj, logger)
+		if forceDelete {
+			r.recordForceDeletion(latestManifestObj, responses, logger)
+		}
+	}
+
 	// handle deletion if no previous error occurred
-	if (!errorState || pathError) &&
+	if (!errorState || pathError || forceDelete) &&
 		!latestManifestObj.DeletionTimestamp.IsZero() &&
 		!processing {
 		err := r.finalizeDeletion(ctx, latestManifestObj)
@@ -358,12 +881,119 @@ func (r *ManifestReconciler) ResponseHandlerFunc(ctx context.Context, logger log
 		// finalizer removal failure - set error state
 		logger.Error(err, "unexpected error while removing finalizer from",
 			"resource", namespacedName)
+		latestManifestObj.Status.ErrorClassification = latestManifestObj.Status.ErrorClassification.
+			MoreSevere(manifest.ClassifyError(err))
 		errorState = true
 	}
 
 	r.setProcessedState(ctx, errorState, processing, latestManifestObj, logger)
 }
 
+// isForceDeletable indicates if the uninstall retry budget of the Manifest is exhausted
+// and it is annotated for force deletion.
+func (r *ManifestReconciler) isForceDeletable(manifestObj *v1alpha1.Manifest, logger logr.Logger) bool {
+	if r.UninstallRetryBudget <= 0 || manifestObj.Status.UninstallFailures < r.UninstallRetryBudget {
+		return false
+	}
+	if manifestObj.GetAnnotations()[labels.ForceDeleteAnnotation] != "true" {
+		logger.Info("uninstall retry budget exhausted, annotate to remove the finalizer regardless",
+			"resource", client.ObjectKeyFromObject(manifestObj), "annotation", labels.ForceDeleteAnnotation,
+			"failures", manifestObj.Status.UninstallFailures)
+		return false
+	}
+	return true
+}
+
+// handlePause records in the Paused condition of the Manifest and in an event, once its reconciliation
+// is paused or resumed. It indicates if the reconciliation is handled, i.e. if the Manifest is paused
+// or its resumption was recorded, in which case it is processed by the next reconciliation.
+func (r *ManifestReconciler) handlePause(ctx context.Context, logger logr.Logger,
+	manifestObj *v1alpha1.Manifest,
+) (bool, error) {
+	paused := manifestObj.IsPaused()
+	if !internalUtil.SetPausedCondition(manifestObj, paused) {
+		return paused, nil
+	}
+	if err := r.Status().Update(ctx, manifestObj); err != nil {
+		return true, err
+	}
+	key := client.ObjectKeyFromObject(manifestObj)
+	if !paused {
+		logger.Info("reconciliation resumed", "resource", key.String())
+		r.recordEvent(manifestObj, v1.EventTypeNormal, "Resumed", "reconciliation resumed")
+		return true, nil
+	}
+	logger.Info("reconciliation paused", "resource", key.String(), "annotation", labels.PausedAnnotation)
+	r.recordEvent(manifestObj, v1.EventTypeNormal, "Paused",
+		"reconciliation paused until the "+labels.PausedAnnotation+" annotation is removed")
+	return true, nil
+}
+
+func (r *ManifestReconciler) recordEvent(manifestObj *v1alpha1.Manifest, eventType, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(manifestObj, eventType, reason, message)
+	}
+}
+
+// recordForceDeletion records the installs left behind by a forced deletion in an event,
+// which outlives the Manifest.
+func (r *ManifestReconciler) recordForceDeletion(manifestObj *v1alpha1.Manifest,
+	responses []*internalTypes.InstallResponse, logger logr.Logger,
+) {
+	leftBehind := strings.Join(internalUtil.FailedInstalls(responses), ", ")
+	logger.Info("force deleting Manifest, resources of failed installs are left behind",
+		"resource", client.ObjectKeyFromObject(manifestObj), "failures", manifestObj.Status.UninstallFailures,
+		"leftBehind", leftBehind)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(manifestObj, v1.EventTypeWarning, "ForceDeleted",
+			"finalizer removed after %d failed uninstall attempts, left behind: %s",
+			manifestObj.Status.UninstallFailures, leftBehind)
+	}
+}
+
+func (r *ManifestReconciler) setLastApplied(responses []*internalTypes.InstallResponse,
+	manifestObj *v1alpha1.Manifest, logger logr.Logger,
+) {
+	chartVersion, valuesHash, err := internalUtil.LastApplied(responses, manifestObj.Spec)
+	if err != nil {
+		logger.Error(err, "cannot determine last applied state", "resource", client.ObjectKeyFromObject(manifestObj))
+		return
+	}
+	now := metav1.Now()
+	manifestObj.Status.LastAppliedChartVersion = chartVersion
+	manifestObj.Status.LastAppliedValuesHash = valuesHash
+	manifestObj.Status.LastSuccessfulInstallTime = &now
+}
+
+// isNoOpSpecChange indicates if the changed spec of a Manifest resolves to the chart versions and values
+// of its last successful install, so that the change does not need to be applied.
+func (r *ManifestReconciler) isNoOpSpecChange(ctx context.Context, manifestObj *v1alpha1.Manifest) bool {
+	if manifestObj.Status.LastAppliedValuesHash == "" {
+		return false
+	}
+	deployInfos, err := prepare.GetInstallInfos(ctx, manifestObj, types.ClusterInfo{
+		Client: r.Client, Config: r.RESTConfig,
+	}, r.ReconcileFlagConfig, r.CacheManager.GetRendererCache())
+	if err != nil {
+		return false
+	}
+	responses := make([]*internalTypes.InstallResponse, 0, len(deployInfos))
+	for _, deployInfo := range deployInfos {
+		valuesHash, err := internalUtil.InstallValuesHash(deployInfo)
+		if err != nil {
+			return false
+		}
+		responses = append(responses, &internalTypes.InstallResponse{
+			ChartName:    deployInfo.ChartName,
+			ChartVersion: manifest.ChartVersion(deployInfo),
+			ValuesHash:   valuesHash,
+		})
+	}
+	chartVersion, valuesHash, err := internalUtil.LastApplied(responses, manifestObj.Spec)
+	return err == nil && chartVersion == manifestObj.Status.LastAppliedChartVersion &&
+		valuesHash == manifestObj.Status.LastAppliedValuesHash
+}
+
 func (r *ManifestReconciler) setProcessedState(ctx context.Context, errorState bool, processing bool,
 	manifestObj *v1alpha1.Manifest, logger logr.Logger,
 ) {
@@ -399,6 +1029,41 @@ func ManifestRateLimiter(failureBaseDelay time.Duration, failureMaxDelay time.Du
 func (r *ManifestReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager,
 	failureBaseDelay time.Duration, failureMaxDelay time.Duration, frequency int, burst int, listenerAddr string,
 ) error {
+	// the watcher is set up before the workers reporting applied resources to it are started
+	var resourceEvents *source.Channel
+	if r.WatchInstalledResources {
+		r.resourceWatcher, resourceEvents = NewResourceWatcher(ctrl.Log.WithName("resource-watcher"))
+	}
+
+	if r.InstallLockDuration > 0 {
+		// leases are read uncached, as a cache would watch all Leases of the cluster
+		leaseClient, err := client.New(mgr.GetConfig(), client.Options{
+			Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper(),
+		})
+		if err != nil {
+			return err
+		}
+		if r.installLocker, err = NewInstallLocker(leaseClient, r.InstallLockDuration,
+			ctrl.Log.WithName("install-lock")); err != nil {
+			return err
+		}
+	}
+
+	if r.LayerStore == nil {
+		r.LayerStore = descriptor.NewLayerStore(descriptor.DefaultLayerStoreRoot(), 0)
+	}
+	if r.ChartRepositories == nil {
+		r.ChartRepositories = descriptor.NewChartRepositories(descriptor.DefaultChartRepositoriesRoot(),
+			descriptor.DefaultChartIndexTTL)
+	}
+	if r.Archives == nil {
+		r.Archives = descriptor.NewArchives(r.LayerStore, descriptor.DefaultArchiveTTL)
+	}
+	if r.GitRepositories == nil {
+		r.GitRepositories = descriptor.NewGitRepositories(descriptor.DefaultGitRepositoriesRoot(),
+			descriptor.DefaultGitRevisionTTL)
+	}
+
 	r.DeployChan = make(chan OperationRequest, r.Workers.GetWorkerPoolSize())
 	r.Workers.StartWorkers(ctx, r.DeployChan, r.HandleCharts)
 
@@ -408,6 +1073,10 @@ func (r *ManifestReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Mana
 	// initialize new cluster cache
 	r.CacheManager = cache.NewCacheManager()
 
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor(strings.ToLower(labels.OperatorName))
+	}
+
 	// register listener component
 	runnableListener, eventChannel := listener.RegisterListenerComponent(
 		listenerAddr, strings.ToLower(labels.OperatorName))
@@ -417,9 +1086,41 @@ func (r *ManifestReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Mana
 		return err
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	var reconciler reconcile.Reconciler = r
+	if r.HealthMonitor != nil {
+		reconciler = r.HealthMonitor.Wrap(r)
+		if r.resourceWatcher != nil {
+			r.HealthMonitor.remoteCaches = r.resourceWatcher
+		}
+	}
+
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr)
+	if r.RegistryWebhookAddr != "" {
+		if len(r.RegistryWebhookSecret) == 0 {
+			return ErrNoRegistryWebhookSecret
+		}
+		registryListener, registryEvents := NewRegistryWebhookListener(r.RegistryWebhookAddr,
+			r.RegistryWebhookSecret, mgr.GetClient(), ctrl.Log.WithName("registry-listener"))
+		if err := mgr.Add(registryListener); err != nil {
+			return err
+		}
+		controllerBuilder = controllerBuilder.Watches(registryEvents, &handler.EnqueueRequestForObject{})
+	}
+	if resourceEvents != nil {
+		if err := mgr.Add(r.resourceWatcher); err != nil {
+			return err
+		}
+		controllerBuilder = controllerBuilder.Watches(resourceEvents, &handler.EnqueueRequestForObject{})
+	}
+	if r.WatchModuleCatalogs {
+		controllerBuilder = controllerBuilder.Watches(&source.Kind{Type: &v1alpha1.ModuleCatalog{}},
+			enqueueChannelSubscribers(ctx, mgr.GetClient()), builder.WithPredicates(catalogChannelsChanged))
+	}
+
+	return controllerBuilder.
 		For(&v1alpha1.Manifest{}).
-		Watches(&source.Kind{Type: &v1.Secret{}}, handler.Funcs{}).
+		Watches(&source.Kind{Type: &v1.Secret{}}, enqueueSecretValueReferences(ctx, mgr.GetClient()),
+			builder.WithPredicates(secretDataChanged)).
 		Watches(eventChannel, &handler.Funcs{
 			GenericFunc: func(event event.GenericEvent, queue workqueue.RateLimitingInterface) {
 				ctrl.Log.WithName("listener").Info(
@@ -437,7 +1138,7 @@ func (r *ManifestReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Mana
 			MaxConcurrentReconciles: r.MaxConcurrentReconciles,
 			CacheSyncTimeout:        r.CacheSyncTimeout,
 		}).
-		Complete(r)
+		Complete(reconciler)
 }
 
 func (r *ManifestReconciler) finalizeDeletion(ctx context.Context, manifestObj *v1alpha1.Manifest) error {
@@ -468,7 +1169,12 @@ func (r *ManifestReconciler) finalizeDeletion(ctx context.Context, manifestObj *
 	// delete cluster cache entry only if the Manifest being deleted is the only one
 	// with the corresponding kyma name
 	if len(manifestList.Items) == 1 {
-		r.CacheManager.InvalidateForOwner(client.ObjectKey{Name: kymaOwnerLabel, Namespace: manifestObj.Namespace})
+		ownerKey := client.ObjectKey{Name: kymaOwnerLabel, Namespace: manifestObj.Namespace}
+		r.CacheManager.InvalidateForOwner(ownerKey)
+		// processors of an impersonated identity are cached separately
+		if impersonation := manifestObj.Spec.RemoteInfo.ImpersonationConfig(); impersonation.UserName != "" {
+			r.CacheManager.InvalidateForOwner(manifest.ImpersonatedCacheKey(ownerKey, impersonation))
+		}
 	}
 
 	return r.updateManifest(ctx, manifestObj)
@@ -21,7 +21,6 @@ import (
 	"github.com/kyma-project/module-manager/api/v1alpha1"
 	"github.com/kyma-project/module-manager/pkg/labels"
 	manifestTypes "github.com/kyma-project/module-manager/pkg/types"
-	"github.com/kyma-project/module-manager/pkg/util"
 )
 
 type mockLayer struct {
@@ -144,20 +143,27 @@ func NewTestManifest(prefix string) *v1alpha1.Manifest {
 	}
 }
 
+// chartPath returns the path of the extracted chart of the imageSpec in the layerStore.
+func chartPath(imageSpec manifestTypes.ImageSpec) string {
+	digest, err := v1.NewHash(imageSpec.Ref)
+	Expect(err).ToNot(HaveOccurred())
+	return layerStore.ContentPath(digest)
+}
+
 func deleteHelmChartResources(imageSpec manifestTypes.ImageSpec) {
-	chartYamlPath := filepath.Join(util.GetFsChartPath(imageSpec), "Chart.yaml")
+	chartYamlPath := filepath.Join(chartPath(imageSpec), "Chart.yaml")
 	Expect(os.RemoveAll(chartYamlPath)).Should(Succeed())
-	valuesYamlPath := filepath.Join(util.GetFsChartPath(imageSpec), "values.yaml")
+	valuesYamlPath := filepath.Join(chartPath(imageSpec), "values.yaml")
 	Expect(os.RemoveAll(valuesYamlPath)).Should(Succeed())
-	templatesPath := filepath.Join(util.GetFsChartPath(imageSpec), "templates")
+	templatesPath := filepath.Join(chartPath(imageSpec), "templates")
 	Expect(os.RemoveAll(templatesPath)).Should(Succeed())
 }
 
 func verifyHelmResourcesDeletion(imageSpec manifestTypes.ImageSpec) {
-	_, err := os.Stat(filepath.Join(util.GetFsChartPath(imageSpec), "Chart.yaml"))
+	_, err := os.Stat(filepath.Join(chartPath(imageSpec), "Chart.yaml"))
 	Expect(os.IsNotExist(err)).To(BeTrue())
-	_, err = os.Stat(filepath.Join(util.GetFsChartPath(imageSpec), "values.yaml"))
+	_, err = os.Stat(filepath.Join(chartPath(imageSpec), "values.yaml"))
 	Expect(os.IsNotExist(err)).To(BeTrue())
-	_, err = os.Stat(filepath.Join(util.GetFsChartPath(imageSpec), "templates"))
+	_, err = os.Stat(filepath.Join(chartPath(imageSpec), "templates"))
 	Expect(os.IsNotExist(err)).To(BeTrue())
 }
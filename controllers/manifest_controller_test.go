@@ -296,7 +296,7 @@ func expectManifestStateIn(state v1alpha1.ManifestState) func(manifestName strin
 func expectHelmClientCacheExist(expectExist bool) func(componentOwner string) bool {
 	return func(componentOwner string) bool {
 		key := client.ObjectKey{Name: componentOwner, Namespace: v1.NamespaceDefault}
-		renderSrc := reconciler.CacheManager.GetRendererCache().GetProcessor(key)
+		renderSrc := reconciler.CacheManager.GetRendererCache().GetClusterProcessor(key)
 		if expectExist {
 			return renderSrc != nil
 		}
@@ -0,0 +1,82 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/controllers"
+	internalTypes "github.com/kyma-project/module-manager/internal/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/cache"
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+const dryRunConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: previewed
+  namespace: default
+data:
+  key: value
+`
+
+func Test_ManifestReconciler_DryRun(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	chartPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(chartPath, "manifest.yaml"), []byte(dryRunConfigMap), 0o600))
+	source, err := json.Marshal(types.KustomizeSpec{Path: chartPath, Type: types.KustomizeType})
+	require.NoError(t, err)
+
+	manifestObj := newTestManifest("dry-run", nil)
+	manifestObj.Annotations = map[string]string{labels.DryRunAnnotation: "true"}
+	manifestObj.Spec.Installs = []v1alpha1.InstallInfo{{Name: "preview", Source: runtime.RawExtension{Raw: source}}}
+	clnt := newFakeClientBuilder(t).WithObjects(manifestObj).Build()
+	key := client.ObjectKeyFromObject(manifestObj)
+	codec, err := types.NewCodec()
+	require.NoError(t, err)
+	reconciler := &controllers.ManifestReconciler{
+		Client:              clnt,
+		RESTConfig:          &rest.Config{},
+		Recorder:            record.NewFakeRecorder(10),
+		CacheManager:        cache.NewCacheManager(),
+		ReconcileFlagConfig: internalTypes.ReconcileFlagConfig{Codec: codec},
+	}
+
+	reconcile := func() {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+	}
+
+	// the first reconcile only initializes labels and finalizers
+	reconcile()
+	reconcile()
+	stored := &v1alpha1.Manifest{}
+	require.NoError(t, clnt.Get(ctx, key, stored))
+	require.Len(t, stored.Status.Preview, 1)
+	assert.Equal(t, types.DiffActionCreate, stored.Status.Preview[0].Action)
+	assert.Equal(t, "previewed", stored.Status.Preview[0].Name)
+	assert.Empty(t, stored.Status.State, "Manifests are not processed while annotated for a dry-run")
+	assert.True(t, apierrors.IsNotFound(clnt.Get(ctx, client.ObjectKey{Namespace: "default", Name: "previewed"},
+		&v1.ConfigMap{})), "previewed changes are not applied")
+
+	stored.Annotations = nil
+	require.NoError(t, clnt.Update(ctx, stored))
+	reconcile()
+	require.NoError(t, clnt.Get(ctx, key, stored))
+	assert.Empty(t, stored.Status.Preview, "the preview is dropped once the dry-run annotation is removed")
+}
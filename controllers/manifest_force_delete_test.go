@@ -0,0 +1,84 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/controllers"
+	internalTypes "github.com/kyma-project/module-manager/internal/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/cache"
+	"github.com/kyma-project/module-manager/pkg/labels"
+)
+
+var errUninstallFailed = errors.New("uninstall failed")
+
+func Test_ManifestReconciler_ForceDeletion(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		budget      int
+		failures    int
+		annotated   bool
+		forceDelete bool
+	}{
+		{"budget left", 3, 1, true, false},
+		{"budget exhausted without annotation", 3, 2, false, false},
+		{"budget exhausted with annotation", 3, 2, true, true},
+		{"force deletion disabled", 0, 10, true, false},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			manifestObj := newTestManifest("deleted", map[string]string{labels.CacheKey: "kyma"})
+			manifestObj.Finalizers = []string{labels.ManifestFinalizer}
+			manifestObj.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+			manifestObj.Status.State = v1alpha1.ManifestStateDeleting
+			manifestObj.Status.UninstallFailures = testCase.failures
+			if testCase.annotated {
+				manifestObj.Annotations = map[string]string{labels.ForceDeleteAnnotation: "true"}
+			}
+			clnt := newFakeClientBuilder(t).WithObjects(manifestObj).Build()
+			key := client.ObjectKeyFromObject(manifestObj)
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &controllers.ManifestReconciler{
+				Client:               clnt,
+				Recorder:             recorder,
+				CacheManager:         cache.NewCacheManager(),
+				UninstallRetryBudget: testCase.budget,
+			}
+
+			responseChan := make(internalTypes.ResponseChan, 1)
+			responseChan <- &internalTypes.InstallResponse{ChartName: "broken", ResNamespacedName: key,
+				Err: errUninstallFailed}
+			reconciler.ResponseHandlerFunc(ctx, logr.Discard(), 1, responseChan, key)
+
+			stored := &v1alpha1.Manifest{}
+			err := clnt.Get(ctx, key, stored)
+			if testCase.forceDelete {
+				assert.True(t, apierrors.IsNotFound(err), "the finalizer is removed regardless of the failure")
+				require.Len(t, recorder.Events, 1)
+				event := <-recorder.Events
+				assert.Contains(t, event, "ForceDeleted")
+				assert.Contains(t, event, "broken", "the installs left behind are recorded")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{labels.ManifestFinalizer}, stored.Finalizers)
+			assert.Equal(t, testCase.failures+1, stored.Status.UninstallFailures)
+			assert.Equal(t, v1alpha1.ManifestStateError, stored.Status.State)
+			assert.Empty(t, recorder.Events)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package controllers_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/controllers"
+	"github.com/kyma-project/module-manager/pkg/labels"
+)
+
+func pausedCondition(t *testing.T, clnt client.Client, key client.ObjectKey) *v1alpha1.ManifestCondition {
+	t.Helper()
+	manifestObj := &v1alpha1.Manifest{}
+	require.NoError(t, clnt.Get(context.Background(), key, manifestObj))
+	for i := range manifestObj.Status.Conditions {
+		if manifestObj.Status.Conditions[i].Type == v1alpha1.ConditionTypePaused {
+			return &manifestObj.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func Test_ManifestReconciler_Pause(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	manifestObj := newTestManifest("paused", nil)
+	manifestObj.Annotations = map[string]string{labels.PausedAnnotation: "true"}
+	manifestObj.Status.State = v1alpha1.ManifestStateReady
+	clnt := newFakeClientBuilder(t).WithObjects(manifestObj).Build()
+	key := client.ObjectKeyFromObject(manifestObj)
+	reconcile := func() (ctrl.Result, *record.FakeRecorder) {
+		// every reconcile runs on a new reconciler, as after a restart of the operator
+		recorder := record.NewFakeRecorder(10)
+		result, err := (&controllers.ManifestReconciler{Client: clnt, Recorder: recorder}).
+			Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+		return result, recorder
+	}
+
+	result, recorder := reconcile()
+	assert.False(t, result.Requeue)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Paused")
+	condition := pausedCondition(t, clnt, key)
+	require.NotNil(t, condition)
+	assert.Equal(t, v1alpha1.ConditionStatusTrue, condition.Status)
+
+	_, recorder = reconcile()
+	assert.Empty(t, recorder.Events, "the paused state is recorded in the status and survives restarts")
+	stored := &v1alpha1.Manifest{}
+	require.NoError(t, clnt.Get(ctx, key, stored))
+	assert.Equal(t, v1alpha1.ManifestStateReady, stored.Status.State, "paused Manifests keep their state")
+
+	stored.Annotations = nil
+	require.NoError(t, clnt.Update(ctx, stored))
+	result, recorder = reconcile()
+	assert.True(t, result.Requeue, "resumed Manifests are processed by the next reconcile")
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Resumed")
+	condition = pausedCondition(t, clnt, key)
+	require.NotNil(t, condition)
+	assert.Equal(t, v1alpha1.ConditionStatusFalse, condition.Status)
+}
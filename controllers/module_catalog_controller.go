@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/internal/pkg/prepare"
+	"github.com/kyma-project/module-manager/pkg/descriptor"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+const (
+	defaultCatalogInterval = 10 * time.Minute
+	maxCatalogVersions     = 50
+)
+
+var ErrCatalogSourceInvalid = errors.New("exactly one of helm or oci has to be set")
+
+// ModuleCatalogReconciler lists the repository of every ModuleCatalog periodically and publishes the available
+// versions and the versions its channels resolve to in its status. Failed listings keep the versions discovered
+// before, so that installs referencing a channel are not affected by an unreachable repository.
+type ModuleCatalogReconciler struct {
+	client.Client
+	// ChartRepositories lists the versions of charts of Helm repositories, sharing cached indexes with Manifests
+	ChartRepositories *descriptor.ChartRepositories
+	// InsecureRegistry lists OCI repositories over plain HTTP
+	InsecureRegistry bool
+}
+
+//+kubebuilder:rbac:groups=operator.kyma-project.io,resources=modulecatalogs,verbs=get;list;watch
+//+kubebuilder:rbac:groups=operator.kyma-project.io,resources=modulecatalogs/status,verbs=get;update;patch
+
+func (r *ModuleCatalogReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithName(req.NamespacedName.String())
+
+	catalog := &v1alpha1.ModuleCatalog{}
+	if err := r.Get(ctx, req.NamespacedName, catalog); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	interval := defaultCatalogInterval
+	if catalog.Spec.Interval != nil && catalog.Spec.Interval.Duration > 0 {
+		interval = catalog.Spec.Interval.Duration
+	}
+
+	status := catalog.Status.DeepCopy()
+	status.ObservedGeneration = catalog.Generation
+	versions, err := r.listVersions(ctx, catalog)
+	if err != nil {
+		logger.Info("listing versions of module catalog failed", "error", err.Error())
+		status.State = v1alpha1.ManifestStateError
+		status.Message = err.Error()
+	} else {
+		resolved := ResolveCatalogChannels(versions, catalog.Spec.Channels)
+		resolved.ObservedGeneration = catalog.Generation
+		now := metav1.Now()
+		resolved.LastSyncTime = &now
+		status = &resolved
+	}
+
+	if !reflect.DeepEqual(*status, catalog.Status) {
+		catalog.Status = *status
+		if err := r.Status().Update(ctx, catalog); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// ResolveCatalogChannels returns the status of a ModuleCatalog listing the passed versions, sorted latest first,
+// with the channels resolved to the latest version matching their constraint. Its state is Warning if a channel
+// does not resolve to a version.
+func ResolveCatalogChannels(versions []string, channels []v1alpha1.CatalogChannel) v1alpha1.ModuleCatalogStatus {
+	status := v1alpha1.ModuleCatalogStatus{State: v1alpha1.ManifestStateReady}
+	status.Versions = versions
+	if len(versions) > maxCatalogVersions {
+		status.Versions = versions[:maxCatalogVersions]
+	}
+
+	var unresolved []string
+	for _, channel := range channels {
+		constraint := channel.Version
+		if constraint == "" {
+			constraint = "*"
+		}
+		version, err := descriptor.LatestMatchingVersion(versions, constraint)
+		if err != nil {
+			unresolved = append(unresolved, fmt.Sprintf("%s (%s)", channel.Name, err.Error()))
+		}
+		status.Channels = append(status.Channels, v1alpha1.CatalogChannelStatus{Name: channel.Name, Version: version})
+	}
+
+	switch {
+	case len(unresolved) > 0:
+		status.State = v1alpha1.ManifestStateWarning
+		status.Message = fmt.Sprintf("%d of %d channels have no version: %s",
+			len(unresolved), len(channels), strings.Join(unresolved, ", "))
+	case len(versions) == 0:
+		status.Message = "no versions available"
+	default:
+		status.Message = fmt.Sprintf("%d versions available, latest %s", len(versions), versions[0])
+	}
+	return status
+}
+
+// listVersions returns the versions of the repository of the catalog, latest first.
+func (r *ModuleCatalogReconciler) listVersions(ctx context.Context, catalog *v1alpha1.ModuleCatalog,
+) ([]string, error) {
+	switch helm, oci := catalog.Spec.Helm, catalog.Spec.OCI; {
+	case helm != nil && oci == nil:
+		credentials, err := prepare.GetHelmRepositoryCredentials(ctx, helm.CredSecretSelector, r.Client,
+			catalog.Namespace, helm.URL)
+		if err != nil {
+			return nil, err
+		}
+		return r.ChartRepositories.ChartVersions(ctx, helm.URL, helm.Chart, credentials)
+	case oci != nil && helm == nil:
+		var keyChain authn.Keychain = authn.DefaultKeychain
+		if oci.CredSecretSelector != nil {
+			var err error
+			if keyChain, err = prepare.GetAuthnKeychain(ctx, types.ImageSpec{CredSecretSelector: oci.CredSecretSelector},
+				r.Client, catalog.Namespace); err != nil {
+				return nil, err
+			}
+		}
+		return descriptor.OCIVersions(ctx, oci.Repository, r.InsecureRegistry, keyChain)
+	default:
+		return nil, ErrCatalogSourceInvalid
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ModuleCatalogReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.ChartRepositories == nil {
+		r.ChartRepositories = descriptor.NewChartRepositories(descriptor.DefaultChartRepositoriesRoot(),
+			descriptor.DefaultChartIndexTTL)
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ModuleCatalog{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+)
+
+//nolint:gochecknoglobals
+var manifestStateSeverity = map[v1alpha1.ManifestState]int{
+	v1alpha1.ManifestStateReady:      0,
+	v1alpha1.ManifestStateWarning:    1,
+	v1alpha1.ManifestStateProcessing: 2,
+	v1alpha1.ManifestStateDeleting:   3,
+	v1alpha1.ManifestStateError:      4,
+}
+
+// ModuleReleaseReconciler aggregates the states of the Manifests selected by a ModuleRelease into its status
+// and rolls out their spec changes in stages, if the ModuleRelease has a rollout strategy.
+// ModuleReleases are reconciled on every change of a Manifest they select before or after the change.
+type ModuleReleaseReconciler struct {
+	client.Client
+}
+
+//+kubebuilder:rbac:groups=operator.kyma-project.io,resources=modulereleases,verbs=get;list;watch
+//+kubebuilder:rbac:groups=operator.kyma-project.io,resources=modulereleases/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=operator.kyma-project.io,resources=manifests,verbs=get;list;watch;patch
+
+func (r *ModuleReleaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithName(req.NamespacedName.String())
+
+	release := &v1alpha1.ModuleRelease{}
+	if err := r.Get(ctx, req.NamespacedName, release); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&release.Spec.Selector)
+	if err != nil {
+		// invalid selectors are only fixed by a spec change, which triggers a new reconciliation
+		logger.Error(err, "invalid manifest selector of module release")
+		return ctrl.Result{}, nil
+	}
+
+	manifestList := &v1alpha1.ManifestList{}
+	if err := r.List(ctx, manifestList, client.InNamespace(release.Namespace),
+		client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("listing manifests of module release: %w", err)
+	}
+
+	status := AggregateManifestStates(manifestList.Items)
+	status.ObservedGeneration = release.Generation
+	var result ctrl.Result
+	if release.Spec.Rollout != nil {
+		rollout, nextStage := PlanRollout(*release.Spec.Rollout, release.Status.Rollout, manifestList.Items, time.Now())
+		status.Rollout = &rollout
+		result.RequeueAfter = nextStage
+	}
+	// the released stage is recorded before the Manifests are released, so that no stage is released twice
+	if !reflect.DeepEqual(status, release.Status) {
+		release.Status = status
+		if err := r.Status().Update(ctx, release); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return result, r.syncRolloutHolds(ctx, release, manifestList.Items)
+}
+
+// AggregateManifestStates returns the status of a ModuleRelease selecting the passed Manifests.
+// Its state is the worst state of all Manifests, Manifests with an unobserved spec change count as Processing.
+func AggregateManifestStates(manifests []v1alpha1.Manifest) v1alpha1.ModuleReleaseStatus {
+	status := v1alpha1.ModuleReleaseStatus{}
+	if len(manifests) == 0 {
+		status.Message = "no Manifests selected"
+		return status
+	}
+
+	var notReady []string
+	status.State = v1alpha1.ManifestStateReady
+	for i := range manifests {
+		state := manifests[i].Status.State
+		if state == "" || (state == v1alpha1.ManifestStateReady && manifests[i].IsSpecUpdated()) {
+			state = v1alpha1.ManifestStateProcessing
+		}
+		if manifestStateSeverity[state] > manifestStateSeverity[status.State] {
+			status.State = state
+		}
+		if state != v1alpha1.ManifestStateReady {
+			notReady = append(notReady, fmt.Sprintf("%s (%s)", manifests[i].Name, state))
+		}
+		status.Manifests = append(status.Manifests, v1alpha1.ModuleReleaseManifest{
+			Name: manifests[i].Name, State: state,
+		})
+	}
+	sort.Slice(status.Manifests, func(i, j int) bool {
+		return status.Manifests[i].Name < status.Manifests[j].Name
+	})
+	sort.Strings(notReady)
+
+	if len(notReady) == 0 {
+		status.Message = fmt.Sprintf("all %d Manifests are Ready", len(manifests))
+	} else {
+		status.Message = fmt.Sprintf("%d of %d Manifests are not Ready: %s",
+			len(notReady), len(manifests), strings.Join(notReady, ", "))
+	}
+	return status
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ModuleReleaseReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ModuleRelease{}).
+		Watches(&source.Kind{Type: &v1alpha1.Manifest{}}, r.ManifestHandler()).
+		Complete(r)
+}
+
+// ManifestHandler enqueues the ModuleReleases selecting a changed Manifest. Updates enqueue the ModuleReleases
+// selecting its old labels as well, so that ModuleReleases no longer selecting a relabeled Manifest drop it.
+func (r *ModuleReleaseReconciler) ManifestHandler() handler.EventHandler {
+	enqueue := func(queue workqueue.RateLimitingInterface, objs ...client.Object) {
+		for _, obj := range objs {
+			for _, request := range r.releasesOfManifest(obj) {
+				queue.Add(request)
+			}
+		}
+	}
+	return handler.Funcs{
+		CreateFunc: func(evt event.CreateEvent, queue workqueue.RateLimitingInterface) {
+			enqueue(queue, evt.Object)
+		},
+		UpdateFunc: func(evt event.UpdateEvent, queue workqueue.RateLimitingInterface) {
+			enqueue(queue, evt.ObjectOld, evt.ObjectNew)
+		},
+		DeleteFunc: func(evt event.DeleteEvent, queue workqueue.RateLimitingInterface) {
+			enqueue(queue, evt.Object)
+		},
+		GenericFunc: func(evt event.GenericEvent, queue workqueue.RateLimitingInterface) {
+			enqueue(queue, evt.Object)
+		},
+	}
+}
+
+// releasesOfManifest maps a Manifest to all ModuleReleases in its namespace selecting it.
+func (r *ModuleReleaseReconciler) releasesOfManifest(obj client.Object) []reconcile.Request {
+	releaseList := &v1alpha1.ModuleReleaseList{}
+	if err := r.List(context.Background(), releaseList, client.InNamespace(obj.GetNamespace())); err != nil {
+		ctrl.Log.WithName("module-release").Error(err, "unable to list module releases",
+			"resource", client.ObjectKeyFromObject(obj).String())
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range releaseList.Items {
+		selector, err := metav1.LabelSelectorAsSelector(&releaseList.Items[i].Spec.Selector)
+		if err != nil || !selector.Matches(k8slabels.Set(obj.GetLabels())) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&releaseList.Items[i]),
+		})
+	}
+	return requests
+}
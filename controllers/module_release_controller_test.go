@@ -0,0 +1,110 @@
+package controllers_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/controllers"
+)
+
+func Test_AggregateManifestStates(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		manifests []v1alpha1.Manifest
+		state     v1alpha1.ManifestState
+		message   string
+		states    []v1alpha1.ModuleReleaseManifest
+	}{
+		{
+			name:    "no manifests",
+			message: "no Manifests selected",
+		},
+		{
+			name: "all ready",
+			manifests: []v1alpha1.Manifest{
+				rolloutManifest("b", v1alpha1.ManifestStateReady, false),
+				rolloutManifest("a", v1alpha1.ManifestStateReady, false),
+			},
+			state:   v1alpha1.ManifestStateReady,
+			message: "all 2 Manifests are Ready",
+			states: []v1alpha1.ModuleReleaseManifest{
+				{Name: "a", State: v1alpha1.ManifestStateReady}, {Name: "b", State: v1alpha1.ManifestStateReady},
+			},
+		},
+		{
+			name: "unobserved spec changes and missing states are processing",
+			manifests: []v1alpha1.Manifest{
+				rolloutManifest("a", v1alpha1.ManifestStateReady, true),
+				rolloutManifest("b", "", false),
+				rolloutManifest("c", v1alpha1.ManifestStateWarning, false),
+			},
+			state:   v1alpha1.ManifestStateProcessing,
+			message: "3 of 3 Manifests are not Ready: a (Processing), b (Processing), c (Warning)",
+			states: []v1alpha1.ModuleReleaseManifest{
+				{Name: "a", State: v1alpha1.ManifestStateProcessing}, {Name: "b", State: v1alpha1.ManifestStateProcessing},
+				{Name: "c", State: v1alpha1.ManifestStateWarning},
+			},
+		},
+		{
+			name: "the worst state wins",
+			manifests: []v1alpha1.Manifest{
+				rolloutManifest("a", v1alpha1.ManifestStateError, false),
+				rolloutManifest("b", v1alpha1.ManifestStateDeleting, false),
+				rolloutManifest("c", v1alpha1.ManifestStateReady, false),
+			},
+			state:   v1alpha1.ManifestStateError,
+			message: "2 of 3 Manifests are not Ready: a (Error), b (Deleting)",
+			states: []v1alpha1.ModuleReleaseManifest{
+				{Name: "a", State: v1alpha1.ManifestStateError}, {Name: "b", State: v1alpha1.ManifestStateDeleting},
+				{Name: "c", State: v1alpha1.ManifestStateReady},
+			},
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			status := controllers.AggregateManifestStates(testCase.manifests)
+			assert.Equal(t, testCase.state, status.State)
+			assert.Equal(t, testCase.message, status.Message)
+			assert.Equal(t, testCase.states, status.Manifests)
+		})
+	}
+}
+
+func Test_ModuleRelease_ManifestHandler(t *testing.T) {
+	t.Parallel()
+	newRelease := func(name, module string) *v1alpha1.ModuleRelease {
+		return &v1alpha1.ModuleRelease{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name},
+			Spec: v1alpha1.ModuleReleaseSpec{
+				Selector: metav1.LabelSelector{MatchLabels: map[string]string{"module": module}},
+			},
+		}
+	}
+	clnt := newFakeClientBuilder(t).
+		WithObjects(newRelease("redis", "redis"), newRelease("nginx", "nginx"), newRelease("other", "other")).Build()
+	eventHandler := (&controllers.ModuleReleaseReconciler{Client: clnt}).ManifestHandler()
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	t.Cleanup(queue.ShutDown)
+
+	oldManifest := newTestManifest("manifest", map[string]string{"module": "redis"})
+	newManifest := newTestManifest("manifest", map[string]string{"module": "nginx"})
+	eventHandler.Update(event.UpdateEvent{ObjectOld: oldManifest, ObjectNew: newManifest}, queue)
+
+	enqueued := map[string]bool{}
+	for queue.Len() > 0 {
+		item, _ := queue.Get()
+		enqueued[item.(ctrl.Request).Name] = true
+		queue.Done(item)
+	}
+	assert.Equal(t, map[string]bool{"redis": true, "nginx": true}, enqueued,
+		"releases selecting the old labels drop the relabeled manifest")
+}
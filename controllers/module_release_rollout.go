@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/pkg/labels"
+)
+
+// PlanRollout returns the status of the rollout of the passed Manifests following the previous status,
+// with the next stage released if it is due, and the duration until the pause before the next stage passed,
+// zero if no stage waits for its pause. A new rollout starts once a completed rollout is followed by spec changes
+// of installed Manifests.
+func PlanRollout(strategy v1alpha1.RolloutStrategy, previous *v1alpha1.RolloutStatus,
+	manifests []v1alpha1.Manifest, now time.Time,
+) (v1alpha1.RolloutStatus, time.Duration) {
+	status := v1alpha1.RolloutStatus{Phase: v1alpha1.RolloutPhaseComplete, Message: "no spec changes to roll out"}
+	if previous != nil {
+		status = *previous.DeepCopy()
+	}
+	// the Manifests released by a completed rollout are held again until the next rollout
+	released := sets.NewString()
+	if status.Phase != v1alpha1.RolloutPhaseComplete {
+		released.Insert(status.Released...)
+	}
+	stageReady := true
+	var existing, pending, failed []string
+	for i := range manifests {
+		manifestObj := &manifests[i]
+		state := manifestObj.Status.State
+		if released.Has(manifestObj.Name) {
+			existing = append(existing, manifestObj.Name)
+			if state == v1alpha1.ManifestStateError {
+				failed = append(failed, manifestObj.Name)
+			} else if state != v1alpha1.ManifestStateReady || manifestObj.IsSpecUpdated() {
+				stageReady = false
+			}
+			continue
+		}
+		// only spec changes of installed Manifests are held, new Manifests are installed right away
+		if manifestObj.IsSpecUpdated() &&
+			(state == v1alpha1.ManifestStateReady || state == v1alpha1.ManifestStateWarning) {
+			pending = append(pending, manifestObj.Name)
+		}
+	}
+	sort.Strings(pending)
+	sort.Strings(failed)
+
+	if status.Phase == v1alpha1.RolloutPhaseComplete {
+		if len(pending) == 0 {
+			return status, 0
+		}
+		status = v1alpha1.RolloutStatus{Phase: v1alpha1.RolloutPhaseProgressing}
+	}
+	// Manifests deleted during the rollout are no longer tracked
+	status.Released = keepListed(status.Released, existing)
+	status.Pending = pending
+	status.Failed = failed
+
+	switch {
+	case len(failed) > int(strategy.MaxFailures):
+		status.Phase = v1alpha1.RolloutPhaseHalted
+		status.StageReadyTime = nil
+		status.Message = fmt.Sprintf("%d released Manifests failed, exceeding the error budget of %d: %s",
+			len(failed), strategy.MaxFailures, strings.Join(failed, ", "))
+		return status, 0
+	case !stageReady:
+		status.Phase = v1alpha1.RolloutPhaseProgressing
+		status.StageReadyTime = nil
+		status.Message = fmt.Sprintf("stage %d in progress, %d Manifests pending", status.Stage, len(pending))
+		return status, 0
+	case len(pending) == 0:
+		status.Phase = v1alpha1.RolloutPhaseComplete
+		status.StageReadyTime = nil
+		status.Message = fmt.Sprintf("rolled out to %d Manifests in %d stages", len(status.Released), status.Stage)
+		return status, 0
+	}
+
+	if status.Stage > 0 && strategy.Pause != nil && strategy.Pause.Duration > 0 {
+		if status.StageReadyTime == nil {
+			readyTime := metav1.NewTime(now.Truncate(time.Second))
+			status.StageReadyTime = &readyTime
+		}
+		if due := status.StageReadyTime.Add(strategy.Pause.Duration); now.Before(due) {
+			status.Phase = v1alpha1.RolloutPhaseWaiting
+			status.Message = fmt.Sprintf("stage %d is Ready, releasing the next stage at %s",
+				status.Stage, due.UTC().Format(time.RFC3339))
+			return status, due.Sub(now)
+		}
+	}
+
+	size := int(strategy.BatchSize)
+	if status.Stage == 0 && strategy.Canary > 0 {
+		size = int(strategy.Canary)
+	}
+	if size <= 0 || size > len(pending) {
+		size = len(pending)
+	}
+	status.Stage++
+	status.Released = append(status.Released, pending[:size]...)
+	status.Pending = pending[size:]
+	status.Phase = v1alpha1.RolloutPhaseProgressing
+	status.StageReadyTime = nil
+	status.Message = fmt.Sprintf("released stage %d to %s", status.Stage, strings.Join(pending[:size], ", "))
+	return status, 0
+}
+
+// keepListed returns the names, which are listed in the passed list, in their order.
+func keepListed(names []string, listed []string) []string {
+	listedNames := sets.NewString(listed...)
+	kept := make([]string, 0, len(names))
+	for _, name := range names {
+		if listedNames.Has(name) {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
+
+// syncRolloutHolds annotates all selected Manifests, which are not released by the current rollout, with the
+// labels.RolloutHoldAnnotation naming the ModuleRelease, and removes it from all others.
+// Manifests held by another ModuleRelease are left untouched.
+func (r *ModuleReleaseReconciler) syncRolloutHolds(ctx context.Context, release *v1alpha1.ModuleRelease,
+	manifests []v1alpha1.Manifest,
+) error {
+	released := sets.NewString()
+	if rollout := release.Status.Rollout; rollout != nil && rollout.Phase != v1alpha1.RolloutPhaseComplete {
+		released.Insert(rollout.Released...)
+	}
+	for i := range manifests {
+		manifestObj := &manifests[i]
+		hold := ""
+		if release.Spec.Rollout != nil && !released.Has(manifestObj.Name) {
+			hold = release.Name
+		}
+		current := manifestObj.RolloutHold()
+		if current == hold || (current != "" && current != release.Name) {
+			continue
+		}
+		patch := client.MergeFrom(manifestObj.DeepCopy())
+		annotations := manifestObj.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		if hold == "" {
+			delete(annotations, labels.RolloutHoldAnnotation)
+		} else {
+			annotations[labels.RolloutHoldAnnotation] = hold
+		}
+		manifestObj.SetAnnotations(annotations)
+		if err := r.Patch(ctx, manifestObj, patch); err != nil {
+			return fmt.Errorf("updating rollout hold of manifest %s: %w", manifestObj.Name, err)
+		}
+	}
+	return nil
+}
+
+// isRolloutHeld indicates if spec changes of the Manifest are held by the rollout of the ModuleRelease named
+// in its labels.RolloutHoldAnnotation. Holds of deleted ModuleReleases or of ModuleReleases without a rollout
+// strategy are ignored, so that Manifests are never held forever.
+func (r *ManifestReconciler) isRolloutHeld(ctx context.Context, manifestObj *v1alpha1.Manifest) (bool, error) {
+	name := manifestObj.RolloutHold()
+	if name == "" {
+		return false, nil
+	}
+	release := &v1alpha1.ModuleRelease{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: manifestObj.Namespace, Name: name}, release); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return release.Spec.Rollout != nil, nil
+}
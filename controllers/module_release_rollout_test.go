@@ -0,0 +1,263 @@
+package controllers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/controllers"
+	"github.com/kyma-project/module-manager/pkg/labels"
+)
+
+// rolloutManifest returns a Manifest in the passed state, whose latest spec change is not applied yet if specUpdated.
+func rolloutManifest(name string, state v1alpha1.ManifestState, specUpdated bool) v1alpha1.Manifest {
+	manifestObj := newTestManifest(name, map[string]string{"module": "redis"})
+	manifestObj.Generation = 2
+	manifestObj.Status.State = state
+	manifestObj.Status.ObservedGeneration = 2
+	if specUpdated {
+		manifestObj.Status.ObservedGeneration = 1
+	}
+	return *manifestObj
+}
+
+func Test_PlanRollout(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	pause := &metav1.Duration{Duration: 10 * time.Minute}
+	stageReady := func(ago time.Duration) *metav1.Time {
+		readyTime := metav1.NewTime(now.Add(-ago))
+		return &readyTime
+	}
+	ready, failed := v1alpha1.ManifestStateReady, v1alpha1.ManifestStateError
+	tests := []struct {
+		name      string
+		strategy  v1alpha1.RolloutStrategy
+		previous  *v1alpha1.RolloutStatus
+		manifests []v1alpha1.Manifest
+		want      v1alpha1.RolloutStatus
+		requeue   time.Duration
+	}{
+		{
+			name:      "no spec changes",
+			manifests: []v1alpha1.Manifest{rolloutManifest("a", ready, false)},
+			want:      v1alpha1.RolloutStatus{Phase: v1alpha1.RolloutPhaseComplete},
+		},
+		{
+			name:     "new manifests are not held",
+			strategy: v1alpha1.RolloutStrategy{Canary: 1},
+			manifests: []v1alpha1.Manifest{
+				rolloutManifest("a", "", true), rolloutManifest("b", v1alpha1.ManifestStateProcessing, true),
+			},
+			want: v1alpha1.RolloutStatus{Phase: v1alpha1.RolloutPhaseComplete},
+		},
+		{
+			name:     "canary stage",
+			strategy: v1alpha1.RolloutStrategy{Canary: 1, BatchSize: 2},
+			manifests: []v1alpha1.Manifest{
+				rolloutManifest("c", ready, true), rolloutManifest("a", ready, true),
+				rolloutManifest("b", v1alpha1.ManifestStateWarning, true),
+			},
+			want: v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseProgressing, Stage: 1, Released: []string{"a"}, Pending: []string{"b", "c"},
+			},
+		},
+		{
+			name:     "stage in progress",
+			strategy: v1alpha1.RolloutStrategy{Canary: 1, BatchSize: 2},
+			previous: &v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseProgressing, Stage: 1, Released: []string{"a"}, Pending: []string{"b", "c"},
+			},
+			manifests: []v1alpha1.Manifest{
+				rolloutManifest("a", ready, true), rolloutManifest("b", ready, true), rolloutManifest("c", ready, true),
+			},
+			want: v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseProgressing, Stage: 1, Released: []string{"a"}, Pending: []string{"b", "c"},
+			},
+		},
+		{
+			name:     "next batch",
+			strategy: v1alpha1.RolloutStrategy{Canary: 1, BatchSize: 2},
+			previous: &v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseProgressing, Stage: 1, Released: []string{"a"}, Pending: []string{"b", "c", "d"},
+			},
+			manifests: []v1alpha1.Manifest{
+				rolloutManifest("a", ready, false), rolloutManifest("b", ready, true),
+				rolloutManifest("c", ready, true), rolloutManifest("d", ready, true),
+			},
+			want: v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseProgressing, Stage: 2, Released: []string{"a", "b", "c"}, Pending: []string{"d"},
+			},
+		},
+		{
+			name:     "pause after a ready stage",
+			strategy: v1alpha1.RolloutStrategy{Canary: 1, Pause: pause},
+			previous: &v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseProgressing, Stage: 1, Released: []string{"a"}, Pending: []string{"b"},
+			},
+			manifests: []v1alpha1.Manifest{rolloutManifest("a", ready, false), rolloutManifest("b", ready, true)},
+			want: v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseWaiting, Stage: 1, Released: []string{"a"}, Pending: []string{"b"},
+				StageReadyTime: stageReady(0),
+			},
+			requeue: pause.Duration,
+		},
+		{
+			name:     "pause still running",
+			strategy: v1alpha1.RolloutStrategy{Canary: 1, Pause: pause},
+			previous: &v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseWaiting, Stage: 1, Released: []string{"a"}, Pending: []string{"b"},
+				StageReadyTime: stageReady(4 * time.Minute),
+			},
+			manifests: []v1alpha1.Manifest{rolloutManifest("a", ready, false), rolloutManifest("b", ready, true)},
+			want: v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseWaiting, Stage: 1, Released: []string{"a"}, Pending: []string{"b"},
+				StageReadyTime: stageReady(4 * time.Minute),
+			},
+			requeue: 6 * time.Minute,
+		},
+		{
+			name:     "pause passed",
+			strategy: v1alpha1.RolloutStrategy{Canary: 1, Pause: pause},
+			previous: &v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseWaiting, Stage: 1, Released: []string{"a"}, Pending: []string{"b"},
+				StageReadyTime: stageReady(11 * time.Minute),
+			},
+			manifests: []v1alpha1.Manifest{rolloutManifest("a", ready, false), rolloutManifest("b", ready, true)},
+			want: v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseProgressing, Stage: 2, Released: []string{"a", "b"}, Pending: []string{},
+			},
+		},
+		{
+			name:     "error budget exceeded",
+			strategy: v1alpha1.RolloutStrategy{Canary: 2},
+			previous: &v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseProgressing, Stage: 1, Released: []string{"a", "b"}, Pending: []string{"c"},
+			},
+			manifests: []v1alpha1.Manifest{
+				rolloutManifest("a", failed, false), rolloutManifest("b", ready, false), rolloutManifest("c", ready, true),
+			},
+			want: v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseHalted, Stage: 1, Released: []string{"a", "b"}, Pending: []string{"c"},
+				Failed: []string{"a"},
+			},
+		},
+		{
+			name:     "failures within the error budget",
+			strategy: v1alpha1.RolloutStrategy{Canary: 2, MaxFailures: 1},
+			previous: &v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseHalted, Stage: 1, Released: []string{"a", "b"}, Pending: []string{"c"},
+			},
+			manifests: []v1alpha1.Manifest{
+				rolloutManifest("a", failed, false), rolloutManifest("b", ready, false), rolloutManifest("c", ready, true),
+			},
+			want: v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseProgressing, Stage: 2, Released: []string{"a", "b", "c"},
+				Pending: []string{}, Failed: []string{"a"},
+			},
+		},
+		{
+			name:     "completed",
+			strategy: v1alpha1.RolloutStrategy{Canary: 1},
+			previous: &v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseProgressing, Stage: 2, Released: []string{"a", "b", "deleted"},
+			},
+			manifests: []v1alpha1.Manifest{rolloutManifest("a", ready, false), rolloutManifest("b", ready, false)},
+			want: v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseComplete, Stage: 2, Released: []string{"a", "b"},
+			},
+		},
+		{
+			name:     "new rollout after a completed one",
+			strategy: v1alpha1.RolloutStrategy{Canary: 1},
+			previous: &v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseComplete, Stage: 2, Released: []string{"a", "b"},
+			},
+			manifests: []v1alpha1.Manifest{rolloutManifest("a", ready, true), rolloutManifest("b", ready, true)},
+			want: v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseProgressing, Stage: 1, Released: []string{"a"}, Pending: []string{"b"},
+			},
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			status, requeue := controllers.PlanRollout(testCase.strategy, testCase.previous, testCase.manifests, now)
+			assert.Equal(t, testCase.want.Phase, status.Phase, status.Message)
+			assert.Equal(t, testCase.want.Stage, status.Stage)
+			assert.Equal(t, testCase.want.Released, status.Released)
+			assert.ElementsMatch(t, testCase.want.Pending, status.Pending)
+			assert.Equal(t, testCase.want.Failed, status.Failed)
+			assert.Equal(t, testCase.want.StageReadyTime, status.StageReadyTime)
+			assert.Equal(t, testCase.requeue, requeue)
+		})
+	}
+}
+
+func Test_ModuleRelease_RolloutHolds(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	release := &v1alpha1.ModuleRelease{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "redis"},
+		Spec: v1alpha1.ModuleReleaseSpec{
+			Selector: metav1.LabelSelector{MatchLabels: map[string]string{"module": "redis"}},
+			Rollout:  &v1alpha1.RolloutStrategy{Canary: 1},
+		},
+	}
+	first, second := rolloutManifest("a", v1alpha1.ManifestStateReady, true),
+		rolloutManifest("b", v1alpha1.ManifestStateReady, true)
+	foreign := rolloutManifest("c", v1alpha1.ManifestStateReady, false)
+	foreign.SetAnnotations(map[string]string{labels.RolloutHoldAnnotation: "other"})
+	clnt := newFakeClientBuilder(t).WithObjects(release, &first, &second, &foreign).Build()
+	reconciler := &controllers.ModuleReleaseReconciler{Client: clnt}
+	request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(release)}
+
+	holds := func() map[string]string {
+		manifestList := &v1alpha1.ManifestList{}
+		require.NoError(t, clnt.List(ctx, manifestList))
+		held := map[string]string{}
+		for i := range manifestList.Items {
+			held[manifestList.Items[i].Name] = manifestList.Items[i].RolloutHold()
+		}
+		return held
+	}
+	applySpec := func(name string) {
+		manifestObj := &v1alpha1.Manifest{}
+		require.NoError(t, clnt.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: name}, manifestObj))
+		manifestObj.Status.ObservedGeneration = manifestObj.Generation
+		require.NoError(t, clnt.Status().Update(ctx, manifestObj))
+	}
+
+	_, err := reconciler.Reconcile(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "", "b": "redis", "c": "other"}, holds(),
+		"the canary is released, manifests held by other releases are left untouched")
+
+	applySpec("a")
+	_, err = reconciler.Reconcile(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "", "b": "", "c": "other"}, holds())
+
+	applySpec("b")
+	_, err = reconciler.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.NoError(t, clnt.Get(ctx, request.NamespacedName, release))
+	assert.Equal(t, v1alpha1.RolloutPhaseComplete, release.Status.Rollout.Phase)
+	assert.Equal(t, map[string]string{"a": "redis", "b": "redis", "c": "other"}, holds(),
+		"completed rollouts hold all manifests until the next rollout")
+
+	release.Spec.Rollout = nil
+	require.NoError(t, clnt.Update(ctx, release))
+	_, err = reconciler.Reconcile(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "", "b": "", "c": "other"}, holds(),
+		"holds are removed with the rollout strategy")
+}
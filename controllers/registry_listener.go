@@ -0,0 +1,329 @@
+package controllers
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+// RegistryWebhookFormat identifies the payload format of a registry push notification.
+type RegistryWebhookFormat string
+
+const (
+	HarborWebhookFormat    RegistryWebhookFormat = "harbor"
+	DockerHubWebhookFormat RegistryWebhookFormat = "dockerhub"
+	GARWebhookFormat       RegistryWebhookFormat = "gar"
+
+	registryListenerPathPrefix = "/v1/registry/"
+	registryListenerTokenParam = "token"
+	dockerHubRegistryHost      = "docker.io"
+	harborPushEventType        = "PUSH_ARTIFACT"
+	garInsertAction            = "INSERT"
+	registryListenerTimeout    = 60 * time.Second
+	maxRegistryWebhookBodySize = 1 << 20
+)
+
+var (
+	ErrUnsupportedWebhookFormat = errors.New("unsupported registry webhook format")
+	ErrNoPushedImages           = errors.New("registry webhook does not contain pushed images")
+	ErrNoRegistryWebhookSecret  = errors.New("registry webhook listener requires a secret")
+)
+
+// PushedImage describes an image tag or digest pushed to a registry.
+type PushedImage struct {
+	// Repository is the fully qualified repository including the registry host, e.g. "eu.gcr.io/kyma/module".
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// RegistryWebhookListener receives push notifications of OCI registries and enqueues all Manifests
+// following the tag of a pushed image, so that changes in the registry are picked up without polling.
+// Each format is served on its own path, e.g. "/v1/registry/harbor".
+// Notifications are only accepted with the Secret, passed either as bearer token of the authorization header,
+// e.g. as auth header of a Harbor webhook, or as "token" query parameter for registries that cannot set headers,
+// e.g. DockerHub or Pub/Sub push subscriptions of Artifact Registry.
+type RegistryWebhookListener struct {
+	Addr   string
+	Secret []byte
+	Reader client.Reader
+	Logger logr.Logger
+
+	events chan event.GenericEvent
+}
+
+var _ manager.Runnable = &RegistryWebhookListener{}
+
+// NewRegistryWebhookListener returns a RegistryWebhookListener and a source of reconciliation events for Manifests.
+func NewRegistryWebhookListener(addr string, secret []byte, reader client.Reader, logger logr.Logger,
+) (*RegistryWebhookListener, *source.Channel) {
+	events := make(chan event.GenericEvent)
+	return &RegistryWebhookListener{
+		Addr:   addr,
+		Secret: secret,
+		Reader: reader,
+		Logger: logger,
+		events: events,
+	}, &source.Channel{Source: events}
+}
+
+// Start serves registry webhooks until the context is closed.
+func (l *RegistryWebhookListener) Start(ctx context.Context) error {
+	if len(l.Secret) == 0 {
+		return ErrNoRegistryWebhookSecret
+	}
+	router := http.NewServeMux()
+	router.Handle(registryListenerPathPrefix, l)
+
+	server := &http.Server{
+		Addr: l.Addr, Handler: router,
+		ReadHeaderTimeout: registryListenerTimeout, ReadTimeout: registryListenerTimeout,
+		WriteTimeout: registryListenerTimeout,
+	}
+	go func() {
+		l.Logger.Info("registry webhook listener is starting up", "addr", l.Addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			l.Logger.Error(err, "registry webhook listener startup failed")
+		}
+	}()
+	<-ctx.Done()
+	l.Logger.Info("registry webhook listener is shutting down: context got closed")
+	return server.Shutdown(context.Background())
+}
+
+// ServeHTTP handles a push notification of the format of the request path.
+func (l *RegistryWebhookListener) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(writer, fmt.Sprintf("%s method is not allowed on this path", req.Method),
+			http.StatusMethodNotAllowed)
+		return
+	}
+	if !l.authenticated(req) {
+		http.Error(writer, "invalid or missing registry webhook secret", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxRegistryWebhookBodySize))
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	format := RegistryWebhookFormat(strings.TrimPrefix(req.URL.Path, registryListenerPathPrefix))
+	images, err := ParseRegistryWebhook(format, body)
+	if errors.Is(err, ErrNoPushedImages) {
+		// other event types, e.g. deletions or scans, are acknowledged but ignored
+		writer.WriteHeader(http.StatusOK)
+		return
+	} else if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	manifests, err := l.manifestsReferencing(req.Context(), images)
+	if err != nil {
+		l.Logger.Error(err, "cannot resolve manifests for registry webhook")
+		http.Error(writer, "cannot resolve manifests", http.StatusInternalServerError)
+		return
+	}
+	for _, manifestObj := range manifests {
+		l.Logger.V(util.DebugLogLevel).Info("enqueueing manifest for pushed image",
+			"resource", client.ObjectKeyFromObject(manifestObj).String())
+		l.events <- event.GenericEvent{Object: manifestObj}
+	}
+	writer.WriteHeader(http.StatusOK)
+}
+
+// authenticated indicates if the request passes the Secret as bearer token or token query parameter.
+func (l *RegistryWebhookListener) authenticated(req *http.Request) bool {
+	secret := req.URL.Query().Get(registryListenerTokenParam)
+	if authorization := req.Header.Get("Authorization"); authorization != "" {
+		secret = strings.TrimPrefix(authorization, "Bearer ")
+	}
+	return secret != "" && subtle.ConstantTimeCompare([]byte(secret), l.Secret) == 1
+}
+
+func (l *RegistryWebhookListener) manifestsReferencing(ctx context.Context, images []PushedImage,
+) ([]*v1alpha1.Manifest, error) {
+	manifestList := &v1alpha1.ManifestList{}
+	if err := l.Reader.List(ctx, manifestList); err != nil {
+		return nil, err
+	}
+	var referencing []*v1alpha1.Manifest
+	for i := range manifestList.Items {
+		manifestObj := &manifestList.Items[i]
+		if ManifestReferencesImage(manifestObj, images) {
+			referencing = append(referencing, manifestObj)
+		}
+	}
+	return referencing, nil
+}
+
+// ManifestReferencesImage indicates if an install of the Manifest follows the tag of one of the pushed images,
+// which was pushed with another digest than the one the tag resolved to when the install was installed last.
+// Pushes without digest, e.g. of DockerHub, always match. Images pinned by their ref are never changed by a push.
+func ManifestReferencesImage(manifestObj *v1alpha1.Manifest, images []PushedImage) bool {
+	installed := make(map[string]types.ResolvedImage, len(manifestObj.Status.Images))
+	for _, image := range manifestObj.Status.Images {
+		installed[image.Name] = image
+	}
+	for _, install := range manifestObj.Spec.Installs {
+		var imageSpec types.ImageSpec
+		if err := json.Unmarshal(install.Source.Raw, &imageSpec); err != nil ||
+			imageSpec.Type != types.OciRefType || imageSpec.Tag == "" {
+			continue
+		}
+		repository := normalizeRepository(imageSpec.Repo + "/" + imageSpec.Name)
+		for _, image := range images {
+			if normalizeRepository(image.Repository) != repository || image.Tag != imageSpec.Tag {
+				continue
+			}
+			resolved, found := installed[install.Name]
+			if image.Digest == "" || !found || resolved.Tag != image.Tag || resolved.Digest != image.Digest {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func normalizeRepository(repository string) string {
+	for _, scheme := range []string{"https://", "http://", "oci://"} {
+		repository = strings.TrimPrefix(repository, scheme)
+	}
+	return strings.ToLower(strings.Trim(repository, "/"))
+}
+
+// ParseRegistryWebhook extracts all pushed images from a registry webhook payload of the given format.
+func ParseRegistryWebhook(format RegistryWebhookFormat, body []byte) ([]PushedImage, error) {
+	var images []PushedImage
+	var err error
+	switch format {
+	case HarborWebhookFormat:
+		images, err = parseHarborWebhook(body)
+	case DockerHubWebhookFormat:
+		images, err = parseDockerHubWebhook(body)
+	case GARWebhookFormat:
+		images, err = parseGARWebhook(body)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedWebhookFormat, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s registry webhook: %w", format, err)
+	}
+	if len(images) == 0 {
+		return nil, ErrNoPushedImages
+	}
+	return images, nil
+}
+
+func parseHarborWebhook(body []byte) ([]PushedImage, error) {
+	var payload struct {
+		Type      string `json:"type"`
+		EventData struct {
+			Resources []struct {
+				Digest      string `json:"digest"`
+				Tag         string `json:"tag"`
+				ResourceURL string `json:"resource_url"`
+			} `json:"resources"`
+		} `json:"event_data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	if payload.Type != harborPushEventType {
+		return nil, nil
+	}
+	images := make([]PushedImage, 0, len(payload.EventData.Resources))
+	for _, resource := range payload.EventData.Resources {
+		repository, _, _ := splitImageReference(resource.ResourceURL)
+		images = append(images, PushedImage{Repository: repository, Tag: resource.Tag, Digest: resource.Digest})
+	}
+	return images, nil
+}
+
+func parseDockerHubWebhook(body []byte) ([]PushedImage, error) {
+	var payload struct {
+		PushData struct {
+			Tag string `json:"tag"`
+		} `json:"push_data"`
+		Repository struct {
+			RepoName string `json:"repo_name"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	if payload.Repository.RepoName == "" {
+		return nil, nil
+	}
+	return []PushedImage{{
+		Repository: dockerHubRegistryHost + "/" + payload.Repository.RepoName,
+		Tag:        payload.PushData.Tag,
+	}}, nil
+}
+
+// parseGARWebhook parses Artifact Registry notifications delivered as Pub/Sub push messages.
+func parseGARWebhook(body []byte) ([]PushedImage, error) {
+	var envelope struct {
+		Message struct {
+			Data string `json:"data"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(envelope.Message.Data)
+	if err != nil {
+		return nil, err
+	}
+	var notification struct {
+		Action string `json:"action"`
+		Digest string `json:"digest"`
+		Tag    string `json:"tag"`
+	}
+	if err := json.Unmarshal(data, &notification); err != nil {
+		return nil, err
+	}
+	if notification.Action != garInsertAction {
+		return nil, nil
+	}
+	image := PushedImage{}
+	if notification.Digest != "" {
+		image.Repository, _, image.Digest = splitImageReference(notification.Digest)
+	}
+	if notification.Tag != "" {
+		image.Repository, image.Tag, _ = splitImageReference(notification.Tag)
+	}
+	return []PushedImage{image}, nil
+}
+
+// splitImageReference splits "<repository>[:<tag>][@<digest>]" into its parts.
+func splitImageReference(reference string) (string, string, string) {
+	var tag, digest string
+	if repository, found, ok := strings.Cut(reference, "@"); ok {
+		reference, digest = repository, found
+	}
+	// a colon after the last slash separates the tag, others belong to the registry port
+	lastSlash, lastColon := strings.LastIndex(reference, "/"), strings.LastIndex(reference, ":")
+	if lastColon > lastSlash {
+		reference, tag = reference[:lastColon], reference[lastColon+1:]
+	}
+	return reference, tag, digest
+}
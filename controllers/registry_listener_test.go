@@ -0,0 +1,209 @@
+package controllers_test
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/controllers"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+const (
+	harborPushWebhook = `{"type": "PUSH_ARTIFACT", "event_data": {"resources": [{
+		"digest": "sha256:pushed", "tag": "1.0.0", "resource_url": "harbor.example.com/kyma/redis:1.0.0"}]}}`
+	harborScanWebhook    = `{"type": "SCANNING_COMPLETED", "event_data": {"resources": []}}`
+	dockerHubPushWebhook = `{"push_data": {"tag": "latest"}, "repository": {"repo_name": "kyma/redis"}}`
+	garPushNotification  = `{"action": "INSERT", "digest": "europe-docker.pkg.dev/kyma/modules/redis@sha256:pushed",
+		"tag": "europe-docker.pkg.dev/kyma/modules/redis:1.0.0"}`
+	garDeleteNotification = `{"action": "DELETE", "digest": "europe-docker.pkg.dev/kyma/modules/redis@sha256:pushed"}`
+)
+
+func garWebhook(notification string) string {
+	return `{"message": {"data": "` + base64.StdEncoding.EncodeToString([]byte(notification)) + `"}}`
+}
+
+func Test_ParseRegistryWebhook(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		format controllers.RegistryWebhookFormat
+		body   string
+		images []controllers.PushedImage
+		err    error
+	}{
+		{
+			name: "harbor push", format: controllers.HarborWebhookFormat, body: harborPushWebhook,
+			images: []controllers.PushedImage{
+				{Repository: "harbor.example.com/kyma/redis", Tag: "1.0.0", Digest: "sha256:pushed"},
+			},
+		},
+		{
+			name: "harbor scan", format: controllers.HarborWebhookFormat, body: harborScanWebhook,
+			err: controllers.ErrNoPushedImages,
+		},
+		{
+			name: "dockerhub push", format: controllers.DockerHubWebhookFormat, body: dockerHubPushWebhook,
+			images: []controllers.PushedImage{{Repository: "docker.io/kyma/redis", Tag: "latest"}},
+		},
+		{
+			name: "dockerhub without repository", format: controllers.DockerHubWebhookFormat, body: `{}`,
+			err: controllers.ErrNoPushedImages,
+		},
+		{
+			name: "gar insert", format: controllers.GARWebhookFormat, body: garWebhook(garPushNotification),
+			images: []controllers.PushedImage{
+				{Repository: "europe-docker.pkg.dev/kyma/modules/redis", Tag: "1.0.0", Digest: "sha256:pushed"},
+			},
+		},
+		{
+			name: "gar delete", format: controllers.GARWebhookFormat, body: garWebhook(garDeleteNotification),
+			err: controllers.ErrNoPushedImages,
+		},
+		{
+			name: "unsupported format", format: "quay", body: dockerHubPushWebhook,
+			err: controllers.ErrUnsupportedWebhookFormat,
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			images, err := controllers.ParseRegistryWebhook(testCase.format, []byte(testCase.body))
+			if testCase.err != nil {
+				assert.ErrorIs(t, err, testCase.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.images, images)
+		})
+	}
+	_, err := controllers.ParseRegistryWebhook(controllers.GARWebhookFormat, []byte(`{"message": {"data": "%"}}`))
+	assert.Error(t, err, "invalid payloads are refused")
+}
+
+// newTagFollowingManifest returns a Manifest, whose install "redis" follows the tag of an image
+// and was installed last with the image of installedDigest, unless it is empty.
+func newTagFollowingManifest(repo, tag, installedDigest string) *v1alpha1.Manifest {
+	manifestObj := newTestManifest("manifest", nil)
+	manifestObj.Spec.Installs = []v1alpha1.InstallInfo{{
+		Name: "redis",
+		Source: runtime.RawExtension{Raw: []byte(`{"repo": "` + repo + `", "name": "redis", "ref": "sha256:layer", ` +
+			`"tag": "` + tag + `", "type": "oci-ref"}`)},
+	}}
+	if installedDigest != "" {
+		manifestObj.Status.Images = []types.ResolvedImage{{Name: "redis", Tag: tag, Digest: installedDigest}}
+	}
+	return manifestObj
+}
+
+func Test_ManifestReferencesImage(t *testing.T) {
+	t.Parallel()
+	pushed := controllers.PushedImage{Repository: "harbor.example.com/kyma/redis", Tag: "1.0.0", Digest: "sha256:pushed"}
+	tests := []struct {
+		name       string
+		manifest   *v1alpha1.Manifest
+		images     []controllers.PushedImage
+		references bool
+	}{
+		{
+			name:       "new digest of the followed tag",
+			manifest:   newTagFollowingManifest("https://Harbor.example.com/kyma", "1.0.0", "sha256:installed"),
+			images:     []controllers.PushedImage{pushed},
+			references: true,
+		},
+		{
+			name:       "followed tag not installed yet",
+			manifest:   newTagFollowingManifest("harbor.example.com/kyma", "1.0.0", ""),
+			images:     []controllers.PushedImage{pushed},
+			references: true,
+		},
+		{
+			name:     "installed digest pushed again",
+			manifest: newTagFollowingManifest("harbor.example.com/kyma", "1.0.0", "sha256:pushed"),
+			images:   []controllers.PushedImage{pushed},
+		},
+		{
+			name:       "push without digest",
+			manifest:   newTagFollowingManifest("docker.io/kyma", "latest", "sha256:installed"),
+			images:     []controllers.PushedImage{{Repository: "docker.io/kyma/redis", Tag: "latest"}},
+			references: true,
+		},
+		{
+			name:     "other tag",
+			manifest: newTagFollowingManifest("harbor.example.com/kyma", "2.0.0", "sha256:installed"),
+			images:   []controllers.PushedImage{pushed},
+		},
+		{
+			name:     "other repository",
+			manifest: newTagFollowingManifest("harbor.example.com/other", "1.0.0", "sha256:installed"),
+			images:   []controllers.PushedImage{pushed},
+		},
+		{
+			name:     "pinned image",
+			manifest: newTagFollowingManifest("harbor.example.com/kyma", "", ""),
+			images:   []controllers.PushedImage{{Repository: "harbor.example.com/kyma/redis", Digest: "sha256:layer"}},
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, testCase.references, controllers.ManifestReferencesImage(testCase.manifest, testCase.images))
+		})
+	}
+}
+
+func Test_RegistryWebhookListener(t *testing.T) {
+	t.Parallel()
+	manifestObj := newTagFollowingManifest("harbor.example.com/kyma", "1.0.0", "sha256:installed")
+	clnt := newFakeClientBuilder(t).WithObjects(manifestObj).Build()
+	listener, events := controllers.NewRegistryWebhookListener(":0", []byte("secret"), clnt, logr.Discard())
+	server := httptest.NewServer(listener)
+	t.Cleanup(server.Close)
+
+	// post is called by other goroutines, so that failures are only asserted
+	post := func(path, authorization string) int {
+		req, err := http.NewRequest(http.MethodPost, server.URL+path, strings.NewReader(harborPushWebhook))
+		if !assert.NoError(t, err) {
+			return 0
+		}
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if !assert.NoError(t, err) {
+			return 0
+		}
+		assert.NoError(t, resp.Body.Close())
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusUnauthorized, post("/v1/registry/harbor", ""))
+	assert.Equal(t, http.StatusUnauthorized, post("/v1/registry/harbor", "Bearer other"))
+	assert.Equal(t, http.StatusUnauthorized, post("/v1/registry/harbor?token=other", ""))
+
+	for _, authenticated := range []struct{ path, authorization string }{
+		{path: "/v1/registry/harbor", authorization: "Bearer secret"},
+		{path: "/v1/registry/harbor?token=secret"},
+	} {
+		status := make(chan int)
+		go func(path, authorization string) { status <- post(path, authorization) }(
+			authenticated.path, authenticated.authorization)
+		select {
+		case enqueued := <-events.Source:
+			assert.Equal(t, manifestObj.GetName(), enqueued.Object.GetName())
+		case <-time.After(10 * time.Second):
+			t.Fatal("the manifest following the pushed tag was not enqueued")
+		}
+		assert.Equal(t, http.StatusOK, <-status)
+	}
+}
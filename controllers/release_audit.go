@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+const (
+	helmReleaseOwnerLabel      = "owner"
+	helmReleaseOwnerValue      = "helm"
+	helmReleaseNameLabel       = "name"
+	helmReleaseStatusLabel     = "status"
+	helmReleasePendingPrefix   = "pending"
+	orphanedReleaseMetricName  = "module_manager_orphaned_release_storage"
+	orphanedReleaseMetricLabel = "release"
+)
+
+//nolint:gochecknoglobals
+var orphanedReleaseStorage = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: orphanedReleaseMetricName,
+	Help: "Indicates helm release storage secrets for which no Manifest install exists anymore",
+}, []string{"namespace", orphanedReleaseMetricLabel})
+
+//nolint:gochecknoinits
+func init() {
+	metrics.Registry.MustRegister(orphanedReleaseStorage)
+}
+
+// OrphanedRelease describes a helm release storage record that has no matching install in any Manifest.
+type OrphanedRelease struct {
+	Release string
+	Status  string
+	Secrets []client.ObjectKey
+}
+
+// pending indicates if the release is still being processed by a helm action,
+// in which case it must never be purged.
+func (o OrphanedRelease) pending() bool {
+	return strings.HasPrefix(o.Status, helmReleasePendingPrefix)
+}
+
+// ReleaseStorageAudit periodically detects helm release storage secrets whose owning Manifest install
+// no longer exists, e.g. because the Manifest was deleted with a force-removed finalizer or the operator
+// crashed during uninstallation. Orphans are reported through the orphanedReleaseStorage metric and,
+// if Purge is enabled, removed from the cluster.
+// Only releases created by the operator are audited, i.e. release storage labeled as managed by the operator
+// or stored in one of the Namespaces the operator stores its releases in. Releases of other helm users are
+// never reported, even if no Manifest installs them.
+// Only the release storage of the cluster the operator runs in is audited. Releases of remote Manifests are
+// stored in their target clusters, which are never audited, but still protect storage of the same release here.
+type ReleaseStorageAudit struct {
+	// Reader should be uncached, as the manager cache only contains a subset of secrets.
+	Reader   client.Reader
+	Writer   client.Writer
+	Interval time.Duration
+	Purge    bool
+	// Namespaces are the namespaces, in which all helm releases are created by the operator.
+	Namespaces []string
+	Logger     logr.Logger
+}
+
+var _ manager.LeaderElectionRunnable = &ReleaseStorageAudit{}
+
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;delete
+
+// NeedLeaderElection ensures only one replica audits and purges release storage.
+func (a *ReleaseStorageAudit) NeedLeaderElection() bool {
+	return true
+}
+
+// Start runs the audit until the context is closed.
+func (a *ReleaseStorageAudit) Start(ctx context.Context) error {
+	ticker := time.NewTicker(a.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			orphans, err := a.Audit(ctx)
+			if err != nil {
+				a.Logger.Error(err, "release storage audit failed")
+				continue
+			}
+			if !a.Purge {
+				continue
+			}
+			if err := a.Cleanup(ctx, orphans); err != nil {
+				a.Logger.Error(err, "release storage cleanup failed")
+			}
+		}
+	}
+}
+
+// Audit lists the helm release storage secrets created by the operator
+// and returns the ones not referenced by any Manifest install.
+func (a *ReleaseStorageAudit) Audit(ctx context.Context) ([]OrphanedRelease, error) {
+	manifests := &v1alpha1.ManifestList{}
+	if err := a.Reader.List(ctx, manifests); err != nil {
+		return nil, fmt.Errorf("listing manifests for release storage audit: %w", err)
+	}
+	installed := make(map[client.ObjectKey]struct{})
+	for _, manifestObj := range manifests.Items {
+		for _, install := range manifestObj.Spec.Installs {
+			// the release namespace of installs without target namespace is only known from their config,
+			// so their release is matched in all namespaces. Helm stores releases by their normalized name.
+			installed[client.ObjectKey{
+				Namespace: install.TargetNamespace, Name: util.NormalizeReleaseName(install.Name),
+			}] = struct{}{}
+		}
+	}
+
+	secrets := &v1.SecretList{}
+	if err := a.Reader.List(ctx, secrets, client.MatchingLabelsSelector{
+		Selector: k8slabels.SelectorFromSet(k8slabels.Set{helmReleaseOwnerLabel: helmReleaseOwnerValue}),
+	}); err != nil {
+		return nil, fmt.Errorf("listing release storage for audit: %w", err)
+	}
+
+	tracked := sets.NewString(a.Namespaces...)
+	orphansByRelease := make(map[client.ObjectKey]*OrphanedRelease)
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		release := secret.Labels[helmReleaseNameLabel]
+		if release == "" || !a.createdByOperator(secret, tracked) {
+			continue
+		}
+		key := client.ObjectKey{Namespace: secret.Namespace, Name: release}
+		if _, found := installed[key]; found {
+			continue
+		}
+		if _, found := installed[client.ObjectKey{Name: release}]; found {
+			continue
+		}
+		orphan, found := orphansByRelease[key]
+		if !found {
+			orphan = &OrphanedRelease{Release: release}
+			orphansByRelease[key] = orphan
+		}
+		// a single pending revision blocks the entire release from being purged
+		if !orphan.pending() {
+			orphan.Status = secret.Labels[helmReleaseStatusLabel]
+		}
+		orphan.Secrets = append(orphan.Secrets, client.ObjectKeyFromObject(secret))
+	}
+
+	orphanedReleaseStorage.Reset()
+	orphans := make([]OrphanedRelease, 0, len(orphansByRelease))
+	for key, orphan := range orphansByRelease {
+		orphanedReleaseStorage.WithLabelValues(key.Namespace, key.Name).Set(float64(len(orphan.Secrets)))
+		a.Logger.V(util.DebugLogLevel).Info("detected orphaned release storage",
+			"release", key.String(), "revisions", len(orphan.Secrets))
+		orphans = append(orphans, *orphan)
+	}
+
+	return orphans, nil
+}
+
+// createdByOperator indicates if the release storage secret was created by the operator.
+func (a *ReleaseStorageAudit) createdByOperator(secret *v1.Secret, tracked sets.String) bool {
+	return secret.Labels[labels.ManagedBy] == labels.OperatorName || tracked.Has(secret.Namespace)
+}
+
+// Cleanup purges the storage of the given orphaned releases. Releases with a pending revision are skipped,
+// as they might still be processed by a helm action.
+func (a *ReleaseStorageAudit) Cleanup(ctx context.Context, orphans []OrphanedRelease) error {
+	var errs []error
+	for _, orphan := range orphans {
+		if orphan.pending() {
+			a.Logger.Info("skipping cleanup of pending release", "release", orphan.Release)
+			continue
+		}
+		for _, key := range orphan.Secrets {
+			secret := &v1.Secret{}
+			secret.SetName(key.Name)
+			secret.SetNamespace(key.Namespace)
+			err := a.Writer.Delete(ctx, secret)
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			a.Logger.Info("purged orphaned release storage", "secret", key.String())
+		}
+	}
+	if len(errs) > 0 {
+		return types.NewMultiError(errs)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package controllers_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/controllers"
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func newFakeClientBuilder(t *testing.T) *fake.ClientBuilder {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme)
+}
+
+// newTestManifest returns a Manifest in the default namespace with the passed labels, which can be stored
+// by the fake client, as its resource is set.
+func newTestManifest(name string, manifestLabels map[string]string) *v1alpha1.Manifest {
+	manifestObj := &v1alpha1.Manifest{ObjectMeta: metav1.ObjectMeta{
+		Namespace: metav1.NamespaceDefault, Name: name, Labels: manifestLabels,
+	}}
+	manifestObj.Spec.Resource.SetAPIVersion("operator.kyma-project.io/v1alpha1")
+	manifestObj.Spec.Resource.SetKind("SampleCRD")
+	return manifestObj
+}
+
+func releaseStorageSecret(namespace, release string, extraLabels map[string]string) *v1.Secret {
+	secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Namespace: namespace,
+		Name:      "sh.helm.release.v1." + release + ".v1",
+		Labels:    map[string]string{"owner": "helm", "name": release, "status": "deployed"},
+	}}
+	for key, value := range extraLabels {
+		secret.Labels[key] = value
+	}
+	return secret
+}
+
+func Test_ReleaseStorageAudit(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	manifestObj := newTestManifest("manifest", nil)
+	manifestObj.Spec.Installs = []v1alpha1.InstallInfo{
+		{Name: "redis", TargetNamespace: "modules"}, {Name: "nginx"}, {Name: "Kafka_Broker", TargetNamespace: "modules"},
+	}
+	// releases of remote Manifests are stored in their target clusters, which are not audited
+	remoteManifest := newTestManifest("remote", nil)
+	remoteManifest.Spec.Remote = true
+	remoteManifest.Spec.Installs = []v1alpha1.InstallInfo{{Name: "mysql", TargetNamespace: "modules"}}
+	managed := map[string]string{labels.ManagedBy: labels.OperatorName}
+	clnt := newFakeClientBuilder(t).WithObjects(manifestObj, remoteManifest,
+		releaseStorageSecret("modules", util.NormalizeReleaseName("Kafka_Broker"), nil),
+		releaseStorageSecret("modules", "mysql", nil),
+		releaseStorageSecret("modules", "redis", nil),
+		releaseStorageSecret("other", "redis", managed),
+		releaseStorageSecret("kyma-system", "nginx", nil),
+		releaseStorageSecret("modules", "orphan", nil),
+		releaseStorageSecret("apps", "unrelated", nil),
+		releaseStorageSecret("modules", "unrelated", nil),
+	).Build()
+	audit := &controllers.ReleaseStorageAudit{
+		Reader: clnt, Writer: clnt, Purge: true, Namespaces: []string{"modules", "kyma-system"}, Logger: logr.Discard(),
+	}
+
+	orphans, err := audit.Audit(ctx)
+	require.NoError(t, err)
+	releases := map[client.ObjectKey]bool{}
+	for _, orphan := range orphans {
+		releases[orphan.Secrets[0]] = true
+	}
+	assert.Len(t, orphans, 3)
+	assert.True(t, releases[client.ObjectKeyFromObject(releaseStorageSecret("other", "redis", nil))],
+		"releases are matched by namespace and name")
+	assert.True(t, releases[client.ObjectKeyFromObject(releaseStorageSecret("modules", "orphan", nil))])
+	assert.True(t, releases[client.ObjectKeyFromObject(releaseStorageSecret("modules", "unrelated", nil))])
+
+	require.NoError(t, audit.Cleanup(ctx, orphans))
+	unrelated := releaseStorageSecret("apps", "unrelated", nil)
+	assert.NoError(t, clnt.Get(ctx, client.ObjectKeyFromObject(unrelated), &v1.Secret{}),
+		"releases not created by the operator survive")
+	for _, release := range []string{"redis", util.NormalizeReleaseName("Kafka_Broker"), "mysql"} {
+		installed := releaseStorageSecret("modules", release, nil)
+		assert.NoError(t, clnt.Get(ctx, client.ObjectKeyFromObject(installed), &v1.Secret{}),
+			"releases are matched by the normalized names of installs, also of remote Manifests")
+	}
+	orphan := releaseStorageSecret("modules", "orphan", nil)
+	assert.True(t, apierrors.IsNotFound(clnt.Get(ctx, client.ObjectKeyFromObject(orphan), &v1.Secret{})))
+
+	audit.Namespaces = nil
+	orphans, err = audit.Audit(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, orphans, "without tracked namespaces, only releases labeled as managed are audited")
+}
@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+// ResourceWatcher watches the resources applied for Manifests in their target clusters and enqueues the owning
+// Manifest on changes, so that drift, e.g. a modified Deployment, is reconciled immediately instead of on resync.
+// Resources are mapped to their Manifest by labels.OwnedByLabel, see manifest.OwnerLabelTransform.
+// Informers are registered per cluster and resource type once resources of the type are applied,
+// and are kept until the operator shuts down.
+type ResourceWatcher struct {
+	Logger logr.Logger
+
+	events chan event.GenericEvent
+	stop   chan struct{}
+
+	mu       sync.Mutex
+	clusters map[string]*watchedCluster
+}
+
+type watchedCluster struct {
+	factory   dynamicinformer.DynamicSharedInformerFactory
+	resources map[schema.GroupVersionResource]bool
+}
+
+var _ manager.Runnable = &ResourceWatcher{}
+
+// NewResourceWatcher returns a ResourceWatcher and a source of reconciliation events for Manifests.
+func NewResourceWatcher(logger logr.Logger) (*ResourceWatcher, *source.Channel) {
+	events := make(chan event.GenericEvent)
+	return &ResourceWatcher{
+		Logger:   logger,
+		events:   events,
+		stop:     make(chan struct{}),
+		clusters: make(map[string]*watchedCluster),
+	}, &source.Channel{Source: events}
+}
+
+// Start runs until the context is closed and stops all informers afterwards.
+func (w *ResourceWatcher) Start(ctx context.Context) error {
+	<-ctx.Done()
+	w.Logger.Info("resource watcher is shutting down: context got closed")
+	close(w.stop)
+	return nil
+}
+
+// Watch registers informers for the passed resource types in the cluster of the passed config,
+// unless they are watched already.
+func (w *ResourceWatcher) Watch(config *rest.Config, resources []schema.GroupVersionResource) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	key := clusterKey(config)
+	cluster, found := w.clusters[key]
+	if !found {
+		dynamicClient, err := dynamic.NewForConfig(config)
+		if err != nil {
+			return err
+		}
+		cluster = &watchedCluster{
+			factory: dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicClient, 0,
+				metav1.NamespaceAll, func(options *metav1.ListOptions) {
+					options.LabelSelector = labels.OwnedByLabel
+				}),
+			resources: make(map[schema.GroupVersionResource]bool),
+		}
+		w.clusters[key] = cluster
+	}
+
+	added := false
+	for _, resource := range resources {
+		if cluster.resources[resource] {
+			continue
+		}
+		informer := cluster.factory.ForResource(resource).Informer()
+		if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: w.onUpdate,
+			DeleteFunc: w.onDelete,
+		}); err != nil {
+			return err
+		}
+		cluster.resources[resource] = true
+		added = true
+		w.Logger.V(util.DebugLogLevel).Info("watching applied resources",
+			"cluster", config.Host, "resource", resource.String())
+	}
+	// only informers which were not started before are started
+	if added {
+		cluster.factory.Start(w.stop)
+	}
+	return nil
+}
+
+// HasSynced indicates if the informers of all watched resources have completed their initial list.
+func (w *ResourceWatcher) HasSynced() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, cluster := range w.clusters {
+		for resource := range cluster.resources {
+			if !cluster.factory.ForResource(resource).Informer().HasSynced() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// onUpdate enqueues the owning Manifest, unless only the status of the resource changed.
+// Changes of resources without a generation, e.g. ConfigMaps, are always enqueued.
+func (w *ResourceWatcher) onUpdate(oldObj, newObj interface{}) {
+	oldResource, oldOk := oldObj.(*unstructured.Unstructured)
+	newResource, newOk := newObj.(*unstructured.Unstructured)
+	if !oldOk || !newOk || oldResource.GetResourceVersion() == newResource.GetResourceVersion() {
+		return
+	}
+	if newResource.GetGeneration() != 0 && oldResource.GetGeneration() == newResource.GetGeneration() &&
+		k8slabels.Equals(oldResource.GetLabels(), newResource.GetLabels()) &&
+		k8slabels.Equals(oldResource.GetAnnotations(), newResource.GetAnnotations()) {
+		return
+	}
+	w.enqueueOwner(newResource)
+}
+
+func (w *ResourceWatcher) onDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if resource, ok := obj.(*unstructured.Unstructured); ok {
+		w.enqueueOwner(resource)
+	}
+}
+
+func (w *ResourceWatcher) enqueueOwner(resource *unstructured.Unstructured) {
+	owner, found := manifest.OwnerOf(resource)
+	if !found {
+		return
+	}
+	w.Logger.V(util.DebugLogLevel).Info("applied resource changed, enqueueing owning manifest",
+		"resource", client.ObjectKeyFromObject(resource).String(), "kind", resource.GetKind(),
+		"manifest", owner.String())
+	manifestObj := &v1alpha1.Manifest{}
+	manifestObj.SetNamespace(owner.Namespace)
+	manifestObj.SetName(owner.Name)
+	select {
+	case w.events <- event.GenericEvent{Object: manifestObj}:
+	case <-w.stop:
+	}
+}
+
+// clusterKey identifies a cluster together with the identity used to access it.
+func clusterKey(config *rest.Config) string {
+	return manifest.ImpersonatedCacheKey(client.ObjectKey{Name: config.Host}, config.Impersonate).Name
+}
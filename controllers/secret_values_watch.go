@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrlLog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+// secretDataChanged passes events of Secrets, except for updates keeping their data, e.g. of labels.
+//
+//nolint:gochecknoglobals
+var secretDataChanged = predicate.Funcs{
+	UpdateFunc: func(updateEvent event.UpdateEvent) bool {
+		oldSecret, oldIsSecret := updateEvent.ObjectOld.(*v1.Secret)
+		newSecret, newIsSecret := updateEvent.ObjectNew.(*v1.Secret)
+		return !oldIsSecret || !newIsSecret || !reflect.DeepEqual(oldSecret.Data, newSecret.Data)
+	},
+}
+
+// enqueueSecretValueReferences enqueues all Manifests in the namespace of a Secret,
+// which set chart values to keys of the Secret.
+func enqueueSecretValueReferences(ctx context.Context, reader client.Reader) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(secret client.Object) []reconcile.Request {
+		manifestList := &v1alpha1.ManifestList{}
+		if err := reader.List(ctx, manifestList, client.InNamespace(secret.GetNamespace())); err != nil {
+			ctrlLog.FromContext(ctx).Error(err, "cannot list manifests referencing secret",
+				"secret", client.ObjectKeyFromObject(secret))
+			return nil
+		}
+		var requests []reconcile.Request
+		for i := range manifestList.Items {
+			manifestObj := &manifestList.Items[i]
+			if manifestReferencesSecret(manifestObj, secret.GetName()) {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(manifestObj)})
+			}
+		}
+		return requests
+	})
+}
+
+// manifestReferencesSecret indicates if any install of the Manifest sets chart values to keys of the named Secret.
+func manifestReferencesSecret(manifestObj *v1alpha1.Manifest, name string) bool {
+	for _, secretValue := range manifestSecretValues(manifestObj) {
+		if secretValue.SecretKeyRef.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func manifestSecretValues(manifestObj *v1alpha1.Manifest) []types.SecretValue {
+	var secretValues []types.SecretValue
+	for _, install := range manifestObj.Spec.Installs {
+		secretValues = append(secretValues, install.SecretValues...)
+	}
+	return secretValues
+}
+
+// secretValuesChanged indicates if the referenced keys of Secrets changed since the checksum of
+// labels.SecretValuesChecksumAnnotation was recorded. The annotation is updated to the current checksum,
+// Manifests without the annotation only record it.
+func (r *ManifestReconciler) secretValuesChanged(ctx context.Context, manifestObj *v1alpha1.Manifest,
+) (bool, error) {
+	secretValues := manifestSecretValues(manifestObj)
+	recorded, isRecorded := manifestObj.GetAnnotations()[labels.SecretValuesChecksumAnnotation]
+	if len(secretValues) == 0 && !isRecorded {
+		return false, nil
+	}
+	checksum, err := util.SecretValuesChecksum(ctx, r.Client, manifestObj.Namespace, secretValues)
+	if err != nil {
+		return false, err
+	}
+	if isRecorded && recorded == checksum {
+		return false, nil
+	}
+	annotations := manifestObj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[labels.SecretValuesChecksumAnnotation] = checksum
+	manifestObj.SetAnnotations(annotations)
+	if err := r.updateManifest(ctx, manifestObj); err != nil {
+		return false, err
+	}
+	return isRecorded, nil
+}
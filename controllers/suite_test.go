@@ -41,6 +41,7 @@ import (
 	"github.com/kyma-project/module-manager/controllers"
 	internalTypes "github.com/kyma-project/module-manager/internal/pkg/types"
 	"github.com/kyma-project/module-manager/internal/pkg/util"
+	"github.com/kyma-project/module-manager/pkg/descriptor"
 	"github.com/kyma-project/module-manager/pkg/log"
 	"github.com/kyma-project/module-manager/pkg/types"
 )
@@ -59,6 +60,7 @@ var (
 	helmRepoFile  = filepath.Join(helmCacheHome, "repositories.yaml") //nolint:gochecknoglobals
 	reconciler    *controllers.ManifestReconciler                     //nolint:gochecknoglobals
 	cfg           *rest.Config                                        //nolint:gochecknoglobals
+	layerStore    *descriptor.LayerStore                              //nolint:gochecknoglobals
 )
 
 const (
@@ -130,6 +132,7 @@ var _ = BeforeSuite(func() {
 		return authUser.Config(), nil
 	}
 
+	layerStore = descriptor.NewLayerStore(filepath.Join(os.TempDir(), "module-manager-test-layers"), 0)
 	reconciler = &controllers.ManifestReconciler{
 		Client:  k8sManager.GetClient(),
 		Scheme:  scheme.Scheme,
@@ -141,6 +144,7 @@ var _ = BeforeSuite(func() {
 			CustomStateCheck:        false,
 			InsecureRegistry:        true,
 			CustomRESTCfg:           testRESTConfigGetter,
+			LayerStore:              layerStore,
 		},
 		RequeueIntervals: controllers.RequeueIntervals{
 			Success: time.Second * 10,
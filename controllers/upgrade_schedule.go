@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/internal/pkg/prepare"
+	internalUtil "github.com/kyma-project/module-manager/internal/pkg/util"
+	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+// holdUpgrades returns the names of all installs, which would change their installed chart version outside
+// of the upgrade windows of the Manifest, and records them in their UpgradePending condition.
+// Installs whose upgrade no longer waits, e.g. as a window opened, get their condition reset.
+func holdUpgrades(manifestObj *v1alpha1.Manifest, deployInfos []*types.InstallInfo) (sets.String, error) {
+	open, next, err := util.MaintenanceWindowOpen(manifestObj.Spec.UpgradeWindows, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	held := sets.NewString()
+	if !open {
+		for _, deployInfo := range deployInfos {
+			installed, target := deployInfo.InstalledVersion, manifest.ChartVersion(deployInfo)
+			if installed == "" || target == "" || installed == target {
+				continue
+			}
+			internalUtil.SetUpgradePendingCondition(manifestObj, deployInfo.ChartName, installed, target, next)
+			held.Insert(deployInfo.ChartName)
+		}
+	}
+	for _, installName := range internalUtil.PendingUpgrades(manifestObj).Difference(held).List() {
+		internalUtil.ClearUpgradePendingCondition(manifestObj, installName)
+	}
+	return held, nil
+}
+
+// heldUpgrades returns the installs of the Manifest, whose change of the chart version is held until
+// an upgrade window. Charts are only fetched, if the Manifest has upgrade windows or pending upgrades.
+func (r *ManifestReconciler) heldUpgrades(ctx context.Context, manifestObj *v1alpha1.Manifest) (sets.String, error) {
+	if len(manifestObj.Spec.UpgradeWindows) == 0 && internalUtil.PendingUpgrades(manifestObj).Len() == 0 {
+		return nil, nil
+	}
+	deployInfos, err := prepare.GetInstallInfos(ctx, manifestObj, types.ClusterInfo{
+		Client: r.Client, Config: r.RESTConfig,
+	}, r.ReconcileFlagConfig, r.CacheManager.GetRendererCache())
+	if err != nil {
+		return nil, err
+	}
+	return holdUpgrades(manifestObj, deployInfos)
+}
+
+// withoutHeldUpgrades returns the deployInfos of all installs, which are not held until an upgrade window.
+func withoutHeldUpgrades(deployInfos []*types.InstallInfo, held sets.String) []*types.InstallInfo {
+	if held.Len() == 0 {
+		return deployInfos
+	}
+	filtered := make([]*types.InstallInfo, 0, len(deployInfos))
+	for _, deployInfo := range deployInfos {
+		if !held.Has(deployInfo.ChartName) {
+			filtered = append(filtered, deployInfo)
+		}
+	}
+	return filtered
+}
@@ -20,6 +20,8 @@ var ErrResourceUnstructuredExtraction = errors.New("unstructured could not be ex
 type Resource struct {
 	DefaultClient client.Client
 	types.Check
+	// StatusPaths locate the state of the custom resource, if it is not reported in `.status.state`
+	StatusPaths types.StatusPaths
 }
 
 func (r *Resource) DefaultFn(context.Context, *unstructured.Unstructured, logr.Logger,
@@ -45,6 +47,7 @@ func (r *Resource) CheckFn(ctx context.Context, manifestObj *unstructured.Unstru
 	// check custom resource for states
 	customStatus := &custom.Status{
 		Reader: clusterInfo.Client,
+		Paths:  r.StatusPaths,
 	}
 
 	ready, err := customStatus.WaitForCustomResources(ctx, &unstructured.Unstructured{Object: resource})
@@ -5,11 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"reflect"
+	"strconv"
+	"strings"
 
+	"github.com/Masterminds/semver/v3"
+	"go.opentelemetry.io/otel/attribute"
 	"helm.sh/helm/v3/pkg/strvals"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/rest"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -22,7 +28,10 @@ import (
 	"github.com/kyma-project/module-manager/pkg/custom"
 	"github.com/kyma-project/module-manager/pkg/descriptor"
 	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/log"
+	"github.com/kyma-project/module-manager/pkg/manifest"
 	"github.com/kyma-project/module-manager/pkg/resource"
+	"github.com/kyma-project/module-manager/pkg/tracing"
 	"github.com/kyma-project/module-manager/pkg/types"
 	"github.com/kyma-project/module-manager/pkg/util"
 	corev1 "k8s.io/api/core/v1"
@@ -31,7 +40,22 @@ import (
 
 const configReadError = "reading install %s resulted in an error for " + v1alpha1.ManifestKind
 
-var ErrNoAuthSecretFound = errors.New("no auth secret found")
+// keys of the credentials of archives in their Secret, next to the keys of basic auth and TLS Secrets.
+const (
+	archiveTokenKey           = "token"
+	archiveAccessKeyIDKey     = "accessKeyID"
+	archiveSecretAccessKeyKey = "secretAccessKey"
+	archiveSessionTokenKey    = "sessionToken"
+	// gitKnownHostsKey holds the host keys of SSH repositories next to the keys of basic auth and SSH auth Secrets
+	gitKnownHostsKey = "known_hosts"
+)
+
+var (
+	ErrNoAuthSecretFound            = errors.New("no auth secret found")
+	ErrAmbiguousAuthSecret          = errors.New("more than one auth secret found")
+	ErrImpersonateGroupsWithoutUser = errors.New("impersonated groups require an impersonated user")
+	ErrChannelWithoutHelmSource     = errors.New("channel references require a Helm chart source")
+)
 
 // GetInstallInfos pre-processes the passed Manifest CR and returns a list types.InstallInfo objects,
 // each representing an installation artifact.
@@ -40,9 +64,12 @@ func GetInstallInfos(ctx context.Context, manifestObj *v1alpha1.Manifest, defaul
 ) ([]*types.InstallInfo, error) {
 	// evaluate rest config
 	customResCheck := &manifestCustom.Resource{DefaultClient: defaultClusterInfo.Client}
+	if manifestObj.Spec.ResourceStatusPaths != nil {
+		customResCheck.StatusPaths = *manifestObj.Spec.ResourceStatusPaths
+	}
 
 	// check crds - if present do not update
-	crds, err := parseCrds(ctx, manifestObj, flags.InsecureRegistry, defaultClusterInfo.Client)
+	crds, err := parseCrds(ctx, manifestObj, flags.InsecureRegistry, flags.LayerStore, defaultClusterInfo.Client)
 	if err != nil {
 		return nil, err
 	}
@@ -62,6 +89,20 @@ func GetInstallInfos(ctx context.Context, manifestObj *v1alpha1.Manifest, defaul
 	// ensure runtime-watcher labels are set to CustomResource
 	InsertWatcherLabels(manifestObj)
 
+	// declarative post-render transforms
+	transforms, err := util.TransformsFromSpecs(manifestObj.Spec.Transforms)
+	if err != nil {
+		return nil, err
+	}
+	// resilience defaults are injected after the declarative transforms, so that they see the final resources
+	if manifestObj.Spec.Resilience != nil {
+		transforms = append(transforms, util.ResilienceTransform(*manifestObj.Spec.Resilience))
+	}
+	// owner labels map watched resources back to the Manifest, they are set last so they cannot be overridden
+	if flags.WatchInstalledResources {
+		transforms = append(transforms, manifest.OwnerLabelTransform)
+	}
+
 	// parse installs
 	baseDeployInfo := types.InstallInfo{
 		ClusterInfo: &clusterInfo,
@@ -70,9 +111,28 @@ func GetInstallInfos(ctx context.Context, manifestObj *v1alpha1.Manifest, defaul
 			BaseResource:    &unstructured.Unstructured{Object: manifestObjMetadata},
 			CustomResources: []*unstructured.Unstructured{},
 		},
-		Ctx:              ctx,
-		CheckFn:          customResCheck.DefaultFn,
-		CheckReadyStates: flags.CheckReadyStates,
+		Ctx:                 ctx,
+		CheckFn:             customResCheck.DefaultFn,
+		Transforms:          transforms,
+		CheckReadyStates:    flags.CheckReadyStates,
+		TrackInventory:      flags.TrackInventory,
+		Scanners:            flags.ContentScanners,
+		ScanMode:            flags.ContentScanMode,
+		Timeout:             flags.OperationTimeout,
+		ReleaseHistoryLimit: flags.ReleaseHistoryLimit,
+		RollbackWindow:      flags.RollbackWindow,
+		ReleaseEncrypter:    flags.ReleaseEncrypter,
+		CRDPolicy:           manifestObj.Spec.CRDPolicy,
+		Migrations:          manifest.PendingMigrations(flags.Migrations, manifestObj.Status.AppliedMigrations),
+
+		ServerVersionCheckInterval: flags.ServerVersionCheckInterval,
+		PartialInstallPolicy:       flags.PartialInstallPolicy,
+		SecretRotation:             manifestObj.Spec.SecretRotation,
+	}
+
+	// operation timeout of the Manifest takes precedence over the operator default
+	if manifestObj.Spec.Timeout != nil {
+		baseDeployInfo.Timeout = manifestObj.Spec.Timeout.Duration
 	}
 
 	// replace with check function that checks for readiness of custom resources
@@ -86,13 +146,15 @@ func GetInstallInfos(ctx context.Context, manifestObj *v1alpha1.Manifest, defaul
 	}
 
 	// extract config
-	configs, err := parseConfigs(ctx, manifestObj.Spec.Config,
-		manifestObj.Namespace, defaultClusterInfo.Client, flags.InsecureRegistry)
+	configs, kubernetesVersions, err := parseConfigs(ctx, manifestObj.Spec.Config,
+		manifestObj.Namespace, defaultClusterInfo.Client, flags.InsecureRegistry, flags.LayerStore)
 	if err != nil {
 		return nil, err
 	}
+	baseDeployInfo.KubernetesVersions = kubernetesVersions
 	return parseInstallations(ctx, manifestObj, flags.Codec, configs, &baseDeployInfo,
-		flags.InsecureRegistry, defaultClusterInfo.Client)
+		flags.InsecureRegistry, flags.LayerStore, flags.ChartRepositories, flags.Archives,
+		flags.GitRepositories, defaultClusterInfo.Client)
 }
 
 func parseConfigs(ctx context.Context,
@@ -100,45 +162,57 @@ func parseConfigs(ctx context.Context,
 	namespace string,
 	clusterClient client.Client,
 	insecureRegistry bool,
-) ([]interface{}, error) {
+	layerStore *descriptor.LayerStore,
+) ([]interface{}, string, error) {
 	var configs []any
+	var kubernetesVersions string
 	if config.Type.NotEmpty() { //nolint:nestif
-		filePath := util.GetConfigFilePath(config)
-		decodedConfig, err := getDecodedConfig(ctx, namespace, clusterClient, insecureRegistry, config, filePath)
+		decodedConfig, err := getDecodedConfig(ctx, namespace, clusterClient, insecureRegistry, layerStore, config)
 		if err != nil {
 			// if EOF error proceed without config
 			if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
-				return nil, err
+				return nil, "", err
 			}
-			return configs, nil
+			return configs, kubernetesVersions, nil
 		}
 		installConfigObj, decodeOk := decodedConfig.(map[string]any)
 		if !decodeOk {
-			return nil, fmt.Errorf(configReadError, ".spec.config")
+			return nil, "", fmt.Errorf(configReadError, ".spec.config")
 		}
 		if installConfigObj["configs"] != nil {
 			var configOk bool
 			configs, configOk = installConfigObj["configs"].([]any)
 			if !configOk {
-				return nil, fmt.Errorf(configReadError, "chart config object of .spec.config")
+				return nil, "", fmt.Errorf(configReadError, "chart config object of .spec.config")
+			}
+		}
+		// supported Kubernetes versions of the module, e.g. ">=1.24.0 <1.27.0"
+		if installConfigObj["kubernetesVersions"] != nil {
+			var versionsOk bool
+			kubernetesVersions, versionsOk = installConfigObj["kubernetesVersions"].(string)
+			if !versionsOk {
+				return nil, "", fmt.Errorf(configReadError, "kubernetesVersions of .spec.config")
+			}
+			if _, err := semver.NewConstraint(kubernetesVersions); err != nil {
+				return nil, "", fmt.Errorf("invalid kubernetesVersions %q of .spec.config: %w", kubernetesVersions, err)
 			}
 		}
 	}
-	return configs, nil
+	return configs, kubernetesVersions, nil
 }
 
 func getDecodedConfig(ctx context.Context,
 	namespace string,
 	clusterClient client.Client,
 	insecureRegistry bool,
+	layerStore *descriptor.LayerStore,
 	config types.ImageSpec,
-	filePath string,
 ) (any, error) {
 	keyChain, err := configKeyChain(ctx, namespace, clusterClient, config)
 	if err != nil {
 		return nil, err
 	}
-	return descriptor.DecodeUncompressedLayer(config, insecureRegistry, keyChain, filePath)
+	return descriptor.DecodeUncompressedLayer(layerStore, config, insecureRegistry, keyChain)
 }
 
 func configKeyChain(ctx context.Context,
@@ -158,6 +232,14 @@ func configKeyChain(ctx context.Context,
 	return keyChain, nil
 }
 
+// GetDestinationClusterInfo returns the types.ClusterInfo of the cluster the Manifest is installed to,
+// without looking up cached processors.
+func GetDestinationClusterInfo(ctx context.Context, defaultClusterInfo types.ClusterInfo,
+	manifestObj *v1alpha1.Manifest, customCfgGetter internalTypes.RESTConfigGetter,
+) (types.ClusterInfo, error) {
+	return getDestinationConfigAndClient(ctx, defaultClusterInfo, manifestObj, nil, customCfgGetter)
+}
+
 func getDestinationConfigAndClient(ctx context.Context, defaultClusterInfo types.ClusterInfo,
 	manifestObj *v1alpha1.Manifest, processorCache types.RendererCache, customCfgGetter internalTypes.RESTConfigGetter,
 ) (types.ClusterInfo, error) {
@@ -172,11 +254,21 @@ func getDestinationConfigAndClient(ctx context.Context, defaultClusterInfo types
 		return types.ClusterInfo{}, err
 	}
 
+	impersonation := manifestObj.Spec.RemoteInfo.ImpersonationConfig()
+	if impersonation.UserName == "" && len(impersonation.Groups) > 0 {
+		return types.ClusterInfo{}, ErrImpersonateGroupsWithoutUser
+	}
+
 	// cluster info record from cluster cache
 	kymaNsName := client.ObjectKey{Name: kymaOwnerLabel, Namespace: manifestObj.Namespace}
-	processor := processorCache.GetProcessor(kymaNsName)
-	if processor != nil {
-		return processor.GetClusterInfo()
+	if processorCache != nil {
+		if processor := processorCache.GetClusterProcessor(kymaNsName); processor != nil {
+			clusterInfo, err := processor.GetClusterInfo()
+			if err != nil {
+				return types.ClusterInfo{}, err
+			}
+			return impersonate(clusterInfo, impersonation), nil
+		}
 	}
 
 	// RESTConfig can either be retrieved by a secret with name contained in labels.ComponentOwner Manifest CR label,
@@ -193,10 +285,32 @@ func getDestinationConfigAndClient(ctx context.Context, defaultClusterInfo types
 		return types.ClusterInfo{}, err
 	}
 
-	return types.ClusterInfo{
+	return impersonate(types.ClusterInfo{
 		Config: restConfig,
 		// client will be set during processing of manifest
-	}, nil
+	}, impersonation), nil
+}
+
+// impersonate returns the ClusterInfo with a copy of its REST config impersonating the passed identity.
+// As a client of the ClusterInfo is bound to the identity of the original config, it is dropped on change.
+func impersonate(clusterInfo types.ClusterInfo, impersonation rest.ImpersonationConfig) types.ClusterInfo {
+	if reflect.DeepEqual(clusterInfo.Config.Impersonate, impersonation) {
+		return clusterInfo
+	}
+	config := rest.CopyConfig(clusterInfo.Config)
+	config.Impersonate = impersonation
+	return types.ClusterInfo{Config: config}
+}
+
+// runtimeLabels returns the labels matched by the override selectors of the installs of the Manifest.
+func runtimeLabels(manifestObj *v1alpha1.Manifest) map[string]string {
+	matched := map[string]string{labels.RemoteLabel: strconv.FormatBool(manifestObj.Spec.Remote)}
+	for key, value := range manifestObj.GetLabels() {
+		if key != labels.RemoteLabel {
+			matched[key] = value
+		}
+	}
+	return matched
 }
 
 func parseInstallations(ctx context.Context,
@@ -205,16 +319,21 @@ func parseInstallations(ctx context.Context,
 	configs []interface{},
 	baseDeployInfo *types.InstallInfo,
 	insecureRegistry bool,
+	layerStore *descriptor.LayerStore,
+	chartRepositories *descriptor.ChartRepositories,
+	archives *descriptor.Archives,
+	gitRepositories *descriptor.GitRepositories,
 	clusterClient client.Client,
 ) ([]*types.InstallInfo, error) {
 	namespacedName := client.ObjectKeyFromObject(manifestObj)
 	deployInfos := make([]*types.InstallInfo, 0)
 
 	for _, install := range manifestObj.Spec.Installs {
-		deployInfo := baseDeployInfo
+		deployInfo := *baseDeployInfo
 
 		// retrieve chart info
-		chartInfo, err := getChartInfoForInstall(ctx, install, codec, manifestObj, insecureRegistry, clusterClient)
+		chartInfo, err := getChartInfoForInstall(ctx, install, codec, manifestObj, insecureRegistry, layerStore,
+			chartRepositories, archives, gitRepositories, clusterClient)
 		if err != nil {
 			return nil, err
 		}
@@ -224,16 +343,73 @@ func parseInstallations(ctx context.Context,
 		if err != nil {
 			return nil, err
 		}
+		// the target namespace of the install takes precedence over the namespace of its config
+		if install.TargetNamespace != "" {
+			chartConfig["Namespace"] = install.TargetNamespace
+		}
+		if err := validateNamespace(install, chartConfig); err != nil {
+			return nil, err
+		}
+		// values of the config take precedence over the preset values of the profile
+		if chartValues, err = util.MergeProfileValues(chartInfo.ChartPath, manifestObj.Spec.Profile,
+			chartValues); err != nil {
+			return nil, err
+		}
+		// values of matching overrides take precedence over all other values,
+		// they may reference fields of the resource of the Manifest
+		if chartValues, err = util.MergeSelectedOverrides(install.OverrideSelector, runtimeLabels(manifestObj),
+			chartValues, manifestObj.Spec.Resource.Object); err != nil {
+			return nil, fmt.Errorf("install %s: %w", install.Name, err)
+		}
+		// values of Secrets take precedence over overrides and are never logged
+		var secretValues []string
+		if chartValues, secretValues, err = util.ResolveSecretValues(ctx, clusterClient, manifestObj.Namespace,
+			install.SecretValues, chartValues); err != nil {
+			return nil, fmt.Errorf("install %s: %w", install.Name, err)
+		}
+		log.DefaultRedactor.Register(secretValues...)
 
 		// common deploy properties
 		chartInfo.ReleaseName = install.Name
+		chartInfo.NamespacePolicy = install.NamespaceCreatePolicy
+		chartInfo.ConflictPolicy = install.ConflictPolicy
+		chartInfo.MissingAPIPolicy = install.MissingAPIPolicy
+		chartInfo.UpgradePolicy = install.UpgradePolicy
+		chartInfo.InstalledVersion = manifestObj.InstalledVersion(install.Name)
+		chartInfo.SkipResources = install.SkipResources
+		chartInfo.HookPolicy = install.HookPolicy
+		chartInfo.DeletionPolicy = install.DeletionPolicy
+		chartInfo.ReleaseStorage = install.ReleaseStorage
+		chartInfo.Backup = install.Backup
+		chartInfo.WaitFor = install.WaitFor
+		chartInfo.HealthRules = install.HealthRules
+		chartInfo.AdoptExisting = install.AdoptExisting
+		chartInfo.Applied = manifestObj.IsInstallApplied(install.Name)
+		chartInfo.TargetNamespace = install.TargetNamespace
+		chartInfo.NamespaceMappings = install.NamespaceMappings
 		chartInfo.Flags = types.ChartFlags{
 			ConfigFlags: chartConfig,
 			SetFlags:    chartValues,
 		}
 
+		// excluded and skipped resources are removed before any other transform is executed
+		if len(install.Exclude) > 0 {
+			exclusion, err := util.ExclusionTransform(install.Exclude)
+			if err != nil {
+				return nil, fmt.Errorf("install %s: %w", install.Name, err)
+			}
+			deployInfo.Transforms = append([]types.ObjectTransform{exclusion}, baseDeployInfo.Transforms...)
+		}
+		if len(install.SkipResources) > 0 {
+			skip, err := util.SkipTransform(install.SkipResources)
+			if err != nil {
+				return nil, fmt.Errorf("install %s: %w", install.Name, err)
+			}
+			deployInfo.Transforms = append([]types.ObjectTransform{skip}, deployInfo.Transforms...)
+		}
+
 		deployInfo.ChartInfo = chartInfo
-		deployInfos = append(deployInfos, deployInfo)
+		deployInfos = append(deployInfos, &deployInfo)
 	}
 
 	return deployInfos, nil
@@ -242,12 +418,14 @@ func parseInstallations(ctx context.Context,
 func parseCrds(ctx context.Context,
 	manifestObj *v1alpha1.Manifest,
 	insecureRegistry bool,
+	layerStore *descriptor.LayerStore,
 	clusterClient client.Client,
 ) ([]*v1.CustomResourceDefinition, error) {
 	// if crds do not exist - do nothing
 	if manifestObj.Spec.CRDs.Type.NotEmpty() {
 		// extract helm chart from layer digest
-		crdsPath, err := getChartPath(ctx, manifestObj.Spec.CRDs, manifestObj.Namespace, insecureRegistry, clusterClient)
+		crdsPath, err := getChartPath(ctx, manifestObj.Spec.CRDs, manifestObj.Namespace, insecureRegistry, layerStore,
+			clusterClient)
 		if err != nil {
 			return nil, err
 		}
@@ -260,13 +438,14 @@ func getChartPath(ctx context.Context,
 	imageSpec types.ImageSpec,
 	namespace string,
 	insecureRegistry bool,
+	layerStore *descriptor.LayerStore,
 	clusterClient client.Client,
 ) (string, error) {
 	keyChain, err := configKeyChain(ctx, namespace, clusterClient, imageSpec)
 	if err != nil {
 		return "", err
 	}
-	return descriptor.GetPathFromExtractedTarGz(imageSpec, insecureRegistry, keyChain)
+	return descriptor.GetPathFromExtractedLayer(layerStore, imageSpec, insecureRegistry, keyChain)
 }
 
 func GetAuthnKeychain(ctx context.Context,
@@ -309,21 +488,45 @@ func getChartInfoForInstall(ctx context.Context,
 	codec *types.Codec,
 	manifestObj *v1alpha1.Manifest,
 	insecureRegistry bool,
+	layerStore *descriptor.LayerStore,
+	chartRepositories *descriptor.ChartRepositories,
+	archives *descriptor.Archives,
+	gitRepositories *descriptor.GitRepositories,
 	clusterClient client.Client,
-) (*types.ChartInfo, error) {
+) (chartInfo *types.ChartInfo, err error) {
 	namespacedName := client.ObjectKeyFromObject(manifestObj)
+	ctx, span := tracing.Start(ctx, tracing.SpanChartFetch, attribute.String("install", install.Name))
+	defer func() { tracing.End(span, err) }()
 	specType, err := types.GetSpecType(install.Source.Raw)
 	if err != nil {
 		return nil, err
 	}
 
+	channel := manifestObj.InstallChannel(install.Channel)
+	if channel != nil && specType != types.HelmChartType {
+		return nil, fmt.Errorf("install %s: %w", install.Name, ErrChannelWithoutHelmSource)
+	}
+
 	switch specType {
 	case types.HelmChartType:
-		return createHelmChartInfo(codec, install, specType)
+		return createHelmChartInfo(ctx, codec, install, specType, manifestObj.Namespace, channel, chartRepositories,
+			clusterClient)
 	case types.OciRefType:
-		return createOciChartInfo(ctx, install, codec, specType, manifestObj, insecureRegistry, clusterClient)
+		return createOciChartInfo(ctx, install, codec, specType, manifestObj, insecureRegistry, layerStore,
+			clusterClient)
 	case types.KustomizeType:
 		return createKustomizeChartInfo(codec, install, specType)
+	case types.ArchiveType:
+		if archives == nil {
+			archives = descriptor.NewArchives(layerStore, descriptor.DefaultArchiveTTL)
+		}
+		return createArchiveChartInfo(ctx, codec, install, specType, manifestObj.Namespace, archives, clusterClient)
+	case types.GitType:
+		if gitRepositories == nil {
+			gitRepositories = descriptor.NewGitRepositories(descriptor.DefaultGitRepositoriesRoot(),
+				descriptor.DefaultGitRevisionTTL)
+		}
+		return createGitChartInfo(ctx, codec, install, specType, manifestObj.Namespace, gitRepositories, clusterClient)
 	case types.NilRefType:
 		return nil, fmt.Errorf("empty image type for %s resource chart installation", namespacedName.String())
 	}
@@ -353,18 +556,92 @@ func createOciChartInfo(ctx context.Context,
 	specType types.RefTypeMetadata,
 	manifestObj *v1alpha1.Manifest,
 	insecureRegistry bool,
+	layerStore *descriptor.LayerStore,
 	clusterClient client.Client,
 ) (*types.ChartInfo, error) {
 	var imageSpec types.ImageSpec
 	if err := codec.Decode(install.Source.Raw, &imageSpec, specType); err != nil {
 		return nil, err
 	}
+	var resolvedImage *types.ResolvedImage
+	if imageSpec.Tag != "" {
+		keyChain, err := configKeyChain(ctx, manifestObj.Namespace, clusterClient, imageSpec)
+		if err != nil {
+			return nil, err
+		}
+		var imageDigest string
+		if imageSpec, imageDigest, err = descriptor.ResolveTag(ctx, imageSpec, insecureRegistry, keyChain); err != nil {
+			return nil, fmt.Errorf("install %s: %w", install.Name, err)
+		}
+		resolvedImage = &types.ResolvedImage{Name: install.Name, Tag: imageSpec.Tag, Digest: imageDigest}
+	}
 
 	// extract helm chart from layer digest
-	chartPath, err := getChartPath(ctx, imageSpec, manifestObj.Namespace, insecureRegistry, clusterClient)
+	chartPath, err := getChartPath(ctx, imageSpec, manifestObj.Namespace, insecureRegistry, layerStore, clusterClient)
+	if err != nil {
+		return nil, err
+	}
+
+	// layers are verified against their digest, which is the ref of the spec, when they are pulled into the store
+	return &types.ChartInfo{
+		ChartName:    install.Name,
+		ChartPath:    chartPath,
+		Verification: &types.ChartVerification{Digest: imageSpec.Ref},
+		Image:        resolvedImage,
+	}, nil
+}
+
+func createArchiveChartInfo(ctx context.Context,
+	codec *types.Codec,
+	install v1alpha1.InstallInfo,
+	specType types.RefTypeMetadata,
+	namespace string,
+	archives *descriptor.Archives,
+	clusterClient client.Client,
+) (*types.ChartInfo, error) {
+	var archiveSpec types.ArchiveSpec
+	if err := codec.Decode(install.Source.Raw, &archiveSpec, specType); err != nil {
+		return nil, err
+	}
+
+	credentials, err := GetArchiveCredentials(ctx, archiveSpec.CredSecretSelector, clusterClient, namespace,
+		archiveSpec.URL)
+	if err != nil {
+		return nil, err
+	}
+	chartPath, verification, err := archives.Locate(ctx, archiveSpec, credentials)
+	if err != nil {
+		return nil, chartVerificationError(install.Name, err)
+	}
+
+	return &types.ChartInfo{
+		ChartName:    install.Name,
+		ChartPath:    chartPath,
+		Verification: verification,
+	}, nil
+}
+
+func createGitChartInfo(ctx context.Context,
+	codec *types.Codec,
+	install v1alpha1.InstallInfo,
+	specType types.RefTypeMetadata,
+	namespace string,
+	gitRepositories *descriptor.GitRepositories,
+	clusterClient client.Client,
+) (*types.ChartInfo, error) {
+	var gitSpec types.GitSpec
+	if err := codec.Decode(install.Source.Raw, &gitSpec, specType); err != nil {
+		return nil, err
+	}
+
+	credentials, err := GetGitCredentials(ctx, gitSpec.CredSecretSelector, clusterClient, namespace, gitSpec.URL)
 	if err != nil {
 		return nil, err
 	}
+	chartPath, _, err := gitRepositories.Locate(ctx, gitSpec, credentials)
+	if err != nil {
+		return nil, fmt.Errorf("install %s: %w", install.Name, err)
+	}
 
 	return &types.ChartInfo{
 		ChartName: install.Name,
@@ -372,19 +649,212 @@ func createOciChartInfo(ctx context.Context,
 	}, nil
 }
 
-func createHelmChartInfo(codec *types.Codec,
+func createHelmChartInfo(ctx context.Context,
+	codec *types.Codec,
 	install v1alpha1.InstallInfo,
 	specType types.RefTypeMetadata,
+	namespace string,
+	channel *types.ChannelReference,
+	chartRepositories *descriptor.ChartRepositories,
+	clusterClient client.Client,
 ) (*types.ChartInfo, error) {
 	var helmChartSpec types.HelmChartSpec
 	if err := codec.Decode(install.Source.Raw, &helmChartSpec, specType); err != nil {
 		return nil, err
 	}
+	var resolvedChannel *types.ResolvedChannel
+	if channel != nil {
+		version, err := resolveChannel(ctx, clusterClient, namespace, *channel, helmChartSpec.ChartName)
+		if err != nil {
+			return nil, fmt.Errorf("install %s: %w", install.Name, err)
+		}
+		helmChartSpec.Version = version
+		resolvedChannel = &types.ResolvedChannel{
+			Name: install.Name, Catalog: channel.Catalog, Channel: channel.Channel, Version: version,
+		}
+	}
+
+	// legacy case - the chart is located with the repository configuration of the Helm CLI during rendering
+	if chartRepositories == nil {
+		if helmChartSpec.Digest != "" || helmChartSpec.Provenance != nil {
+			return nil, types.ErrChartVerificationFailed.Wrap(
+				fmt.Errorf("install %s: charts located by the Helm CLI cannot be verified", install.Name))
+		}
+		return &types.ChartInfo{
+			ChartName: fmt.Sprintf("%s/%s", install.Name, helmChartSpec.ChartName),
+			RepoName:  install.Name,
+			URL:       helmChartSpec.URL,
+			Channel:   resolvedChannel,
+		}, nil
+	}
+
+	credentials, err := GetHelmRepositoryCredentials(ctx, helmChartSpec.CredSecretSelector, clusterClient, namespace,
+		helmChartSpec.URL)
+	if err != nil {
+		return nil, err
+	}
+	verificationOptions := descriptor.ChartVerificationOptions{Digest: helmChartSpec.Digest}
+	if helmChartSpec.Provenance != nil {
+		keyring, err := provenanceKeyring(ctx, clusterClient, namespace, helmChartSpec.Provenance.KeyringSecretRef)
+		if err != nil {
+			return nil, chartVerificationError(install.Name, err)
+		}
+		verificationOptions.Keyring = keyring
+	}
+	chartPath, verification, err := chartRepositories.LocateVerified(ctx, helmChartSpec.URL,
+		helmChartSpec.ChartName, helmChartSpec.Version, credentials, verificationOptions)
+	if err != nil {
+		return nil, chartVerificationError(install.Name, err)
+	}
 
 	return &types.ChartInfo{
-		ChartName: fmt.Sprintf("%s/%s", install.Name, helmChartSpec.ChartName),
-		RepoName:  install.Name,
-		URL:       helmChartSpec.URL,
+		ChartName:    install.Name,
+		ChartPath:    chartPath,
+		Verification: verification,
+		Channel:      resolvedChannel,
+	}, nil
+}
+
+// resolveChannel returns the version the referenced channel of a ModuleCatalog resolves to,
+// or a types.ErrChannelNotResolved if the catalog is missing, lists another chart or does not resolve the channel.
+func resolveChannel(ctx context.Context, clusterClient client.Client, namespace string,
+	reference types.ChannelReference, chartName string,
+) (string, error) {
+	catalog := &v1alpha1.ModuleCatalog{}
+	key := client.ObjectKey{Namespace: namespace, Name: reference.Catalog}
+	if reference.Channel == "" {
+		return "", types.ErrChannelNotResolved.Wrap(fmt.Errorf("no channel of module catalog %s is set", key))
+	}
+	if err := clusterClient.Get(ctx, key, catalog); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return "", fmt.Errorf("getting module catalog %s: %w", key, err)
+		}
+		return "", types.ErrChannelNotResolved.Wrap(fmt.Errorf("module catalog %s not found", key))
+	}
+	if catalog.Spec.Helm != nil && catalog.Spec.Helm.Chart != chartName {
+		return "", types.ErrChannelNotResolved.Wrap(fmt.Errorf("module catalog %s lists chart %s instead of %s",
+			key, catalog.Spec.Helm.Chart, chartName))
+	}
+	version := catalog.ChannelVersion(reference.Channel)
+	if version == "" {
+		return "", types.ErrChannelNotResolved.Wrap(fmt.Errorf("channel %s of module catalog %s has no version",
+			reference.Channel, key))
+	}
+	return version, nil
+}
+
+// chartVerificationError attributes failed verifications to the install, so that they are recorded in its condition.
+func chartVerificationError(installName string, err error) error {
+	if errors.Is(err, types.ErrChartVerificationFailed) {
+		return &types.ChartVerificationError{Install: installName, Err: err}
+	}
+	return err
+}
+
+// provenanceKeyring returns the keyring of the selected key of a Secret, which verifies provenance files of charts.
+func provenanceKeyring(ctx context.Context, clusterClient client.Client, namespace string,
+	ref corev1.SecretKeySelector,
+) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := clusterClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("getting keyring secret %s: %w", ref.Name, err)
+		}
+		return nil, types.ErrChartVerificationFailed.Wrap(fmt.Errorf("keyring secret %s/%s not found", namespace, ref.Name))
+	}
+	keyring, found := secret.Data[ref.Key]
+	if !found {
+		return nil, types.ErrChartVerificationFailed.Wrap(
+			fmt.Errorf("key %s of keyring secret %s/%s not found", ref.Key, namespace, ref.Name))
+	}
+	return keyring, nil
+}
+
+// GetHelmRepositoryCredentials returns the credentials of the Helm repository at repoURL from the single Secret
+// selected in the namespace, or nil without selector.
+func GetHelmRepositoryCredentials(ctx context.Context,
+	credSecretSelector *metav1.LabelSelector,
+	clusterClient client.Client,
+	namespace, repoURL string,
+) (*types.HelmRepositoryCredentials, error) {
+	if credSecretSelector == nil {
+		return nil, nil
+	}
+	secretList, err := getCredSecrets(ctx, credSecretSelector, clusterClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(secretList.Items) > 1 {
+		return nil, fmt.Errorf("%w: %d secrets selected for chart repository %s",
+			ErrAmbiguousAuthSecret, len(secretList.Items), repoURL)
+	}
+	return helmRepositoryCredentials(secretList.Items[0]), nil
+}
+
+// helmRepositoryCredentials reads the credentials of a Helm repository from the keys of basic auth
+// and TLS Secrets, where ca.crt holds the CA of the repository.
+func helmRepositoryCredentials(secret corev1.Secret) *types.HelmRepositoryCredentials {
+	return &types.HelmRepositoryCredentials{
+		Username: string(secret.Data[corev1.BasicAuthUsernameKey]),
+		Password: string(secret.Data[corev1.BasicAuthPasswordKey]),
+		CAData:   secret.Data[corev1.ServiceAccountRootCAKey],
+		CertData: secret.Data[corev1.TLSCertKey],
+		KeyData:  secret.Data[corev1.TLSPrivateKeyKey],
+	}
+}
+
+// GetArchiveCredentials returns the credentials of the archive at archiveURL from the single Secret
+// selected in the namespace, or nil without selector.
+func GetArchiveCredentials(ctx context.Context,
+	credSecretSelector *metav1.LabelSelector,
+	clusterClient client.Client,
+	namespace, archiveURL string,
+) (*types.ArchiveCredentials, error) {
+	if credSecretSelector == nil {
+		return nil, nil
+	}
+	secretList, err := getCredSecrets(ctx, credSecretSelector, clusterClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(secretList.Items) > 1 {
+		return nil, fmt.Errorf("%w: %d secrets selected for archive %s",
+			ErrAmbiguousAuthSecret, len(secretList.Items), archiveURL)
+	}
+	secret := secretList.Items[0]
+	return &types.ArchiveCredentials{
+		HelmRepositoryCredentials: *helmRepositoryCredentials(secret),
+		Token:                     string(secret.Data[archiveTokenKey]),
+		AccessKeyID:               string(secret.Data[archiveAccessKeyIDKey]),
+		SecretAccessKey:           string(secret.Data[archiveSecretAccessKeyKey]),
+		SessionToken:              string(secret.Data[archiveSessionTokenKey]),
+	}, nil
+}
+
+// GetGitCredentials returns the credentials of the Git repository at repoURL from the single Secret
+// selected in the namespace, or nil without selector.
+func GetGitCredentials(ctx context.Context,
+	credSecretSelector *metav1.LabelSelector,
+	clusterClient client.Client,
+	namespace, repoURL string,
+) (*types.GitCredentials, error) {
+	if credSecretSelector == nil {
+		return nil, nil
+	}
+	secretList, err := getCredSecrets(ctx, credSecretSelector, clusterClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(secretList.Items) > 1 {
+		return nil, fmt.Errorf("%w: %d secrets selected for git repository %s",
+			ErrAmbiguousAuthSecret, len(secretList.Items), repoURL)
+	}
+	secret := secretList.Items[0]
+	return &types.GitCredentials{
+		Username:      string(secret.Data[corev1.BasicAuthUsernameKey]),
+		Password:      string(secret.Data[corev1.BasicAuthPasswordKey]),
+		SSHPrivateKey: secret.Data[corev1.SSHAuthPrivateKey],
+		KnownHosts:    secret.Data[gitKnownHostsKey],
 	}, nil
 }
 
@@ -435,6 +905,20 @@ func parseChartConfigAndValues(install v1alpha1.InstallInfo, configs []interface
 	return config, values, nil
 }
 
+// validateNamespace returns a types.ErrInvalidName if the target namespace configured for the install
+// is not a valid DNS-1123 label, instead of failing on apply.
+func validateNamespace(install v1alpha1.InstallInfo, chartConfig map[string]interface{}) error {
+	namespace, ok := chartConfig["Namespace"].(string)
+	if !ok || namespace == "" {
+		return nil
+	}
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return types.ErrInvalidName.Wrap(fmt.Errorf("namespace %q of install %s: %s",
+			namespace, install.Name, strings.Join(errs, ", ")))
+	}
+	return nil
+}
+
 // InsertWatcherLabels adds watcher labels to custom resource of the Manifest CR.
 func InsertWatcherLabels(manifestObj *v1alpha1.Manifest) {
 	// Make sure Manifest CR is enabled for remote and Spec.Resource is a valid resource
@@ -450,9 +934,19 @@ func InsertWatcherLabels(manifestObj *v1alpha1.Manifest) {
 		manifestLabels = make(map[string]string)
 	}
 
-	manifestLabels[labels.OwnedByLabel] = ownedByValue
+	manifestLabels[labels.OwnedByLabel] = util.NormalizeLabelValue(ownedByValue)
 	manifestLabels[labels.WatchedByLabel] = labels.OperatorName
 
+	// owners too long for a label value are recorded in full as annotation
+	if manifestLabels[labels.OwnedByLabel] != ownedByValue {
+		annotations := manifestObj.Spec.Resource.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[labels.OwnedByAnnotation] = ownedByValue
+		manifestObj.Spec.Resource.SetAnnotations(annotations)
+	}
+
 	manifestObj.Spec.Resource.SetLabels(manifestLabels)
 }
 
@@ -0,0 +1,43 @@
+package prepare_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	"github.com/kyma-project/module-manager/internal/pkg/prepare"
+	internalTypes "github.com/kyma-project/module-manager/internal/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+func Test_GetInstallInfosTimeout(t *testing.T) {
+	t.Parallel()
+	source, err := json.Marshal(types.KustomizeSpec{Path: t.TempDir(), Type: types.KustomizeType})
+	require.NoError(t, err)
+	codec, err := types.NewCodec()
+	require.NoError(t, err)
+	clusterInfo := types.ClusterInfo{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()}
+	flags := internalTypes.ReconcileFlagConfig{Codec: codec, OperationTimeout: time.Minute}
+
+	manifestObj := &v1alpha1.Manifest{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "manifest"}}
+	manifestObj.Spec.Installs = []v1alpha1.InstallInfo{{Name: "install", Source: runtime.RawExtension{Raw: source}}}
+	installInfos, err := prepare.GetInstallInfos(context.Background(), manifestObj, clusterInfo, flags, nil)
+	require.NoError(t, err)
+	require.Len(t, installInfos, 1)
+	assert.Equal(t, time.Minute, installInfos[0].Timeout, "the operation timeout of the operator is the default")
+
+	manifestObj.Spec.Timeout = &metav1.Duration{Duration: time.Second}
+	installInfos, err = prepare.GetInstallInfos(context.Background(), manifestObj, clusterInfo, flags, nil)
+	require.NoError(t, err)
+	require.Len(t, installInfos, 1)
+	assert.Equal(t, time.Second, installInfos[0].Timeout, "the timeout of the Manifest takes precedence")
+}
@@ -1,9 +1,12 @@
 package types
 
 import (
+	"time"
+
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/kyma-project/module-manager/pkg/descriptor"
 	"github.com/kyma-project/module-manager/pkg/types"
 )
 
@@ -15,8 +18,34 @@ type ReconcileFlagConfig struct {
 	CheckReadyStates        bool
 	CustomStateCheck        bool
 	InsecureRegistry        bool
+	TrackInventory          bool
 	MaxConcurrentReconciles int
 	CustomRESTCfg           RESTConfigGetter
+	ContentScanMode         types.ScanMode
+	ContentScanners         []types.ContentScanner
+	OperationTimeout        time.Duration
+	ReleaseHistoryLimit     int
+	RollbackWindow          time.Duration
+	ReleaseEncrypter        types.KeyEncrypter
+	// Migrations rewrite resources applied by previous versions of the operator, see types.Migration
+	Migrations []types.Migration
+	// WatchInstalledResources labels applied resources with their owning Manifest and watches them for changes
+	WatchInstalledResources bool
+	// ServerVersionCheckInterval is the interval in which target clusters are checked for upgrades, see types.InstallInfo
+	ServerVersionCheckInterval time.Duration
+	// SkipCapacityVerification applies installs without comparing the resources requested by their workloads
+	// with the capacity left in the target cluster
+	SkipCapacityVerification bool
+	// PartialInstallPolicy determines how leftovers of failed install attempts are handled on retry
+	PartialInstallPolicy types.PartialInstallPolicy
+	// LayerStore stores the pulled OCI layers of all Manifests by their digest
+	LayerStore *descriptor.LayerStore
+	// ChartRepositories resolves charts of Helm repositories, if it is nil they are located with the Helm CLI
+	ChartRepositories *descriptor.ChartRepositories
+	// Archives resolves archives of installs downloaded from HTTP(S) URLs or S3 or GCS buckets into the LayerStore
+	Archives *descriptor.Archives
+	// GitRepositories resolves revisions of Git repositories of installs into local checkouts
+	GitRepositories *descriptor.GitRepositories
 }
 
 type ResponseChan chan *InstallResponse
@@ -27,9 +56,43 @@ type ResponseChan chan *InstallResponse
 type InstallResponse struct {
 	Ready             bool
 	ChartName         string
+	ChartVersion      string
+	ValuesHash        uint32
 	Flags             types.ChartFlags
 	ResNamespacedName client.ObjectKey
 	Err               error
+	SecurityFindings  []types.SecurityFinding
+	Release           *types.ReleaseRevision
+	// SchedulingVerified indicates if SchedulingIssues were determined for the nodes of the target cluster
+	SchedulingVerified bool
+	SchedulingIssues   []types.SchedulingIssue
+	// CapacityVerified indicates if CapacityShortages were determined for the capacity of the target cluster
+	CapacityVerified  bool
+	CapacityShortages []types.CapacityShortage
+	// APIsVerified indicates if MissingAPIs were determined for the APIs served by the target cluster
+	APIsVerified bool
+	MissingAPIs  []types.MissingAPI
+	// UpgradeVerified indicates if the chart version was validated against the UpgradePolicy of the install,
+	// which is violated if UpgradeViolation is set
+	UpgradeVerified  bool
+	UpgradeViolation error
+	// WaitForVerified indicates if UnsatisfiedWaits were determined for the WaitFor of the install
+	WaitForVerified  bool
+	UnsatisfiedWaits []types.UnsatisfiedWait
+	// Verification records how the chart archive of the install was verified, nil if it was not verified
+	Verification *types.ChartVerification
+	// AppliedMigrations lists the versions of all migrations applied to the install
+	AppliedMigrations []string
+	// ApplyStatus describes how the last apply changed each resource of the install, nil if nothing was applied
+	ApplyStatus *types.InstallApplyStatus
+	// ReleaseName is the name of the install, whose resources were applied
+	ReleaseName string
+	// Channel is the channel the chart version of the install was resolved from, nil if it follows no channel
+	Channel *types.ResolvedChannel
+	// Image is the image the followed tag of the install resolved to, nil if it follows no tag
+	Image *types.ResolvedImage
+	// Backup references the snapshot of the live resources stored before an upgrade, nil if none was stored
+	Backup *types.BackupReference
 }
 
 func (r *InstallResponse) Error() string {
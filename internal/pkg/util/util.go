@@ -2,11 +2,17 @@ package util
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -16,6 +22,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kyma-project/module-manager/api/v1alpha1"
+	manifestTypes "github.com/kyma-project/module-manager/pkg/types"
 	"github.com/kyma-project/module-manager/pkg/util"
 )
 
@@ -69,7 +76,15 @@ func AddReadyConditionForResponses(responses []*types.InstallResponse, logger lo
 		status := v1alpha1.ConditionStatusTrue
 		message := "installation successful"
 
-		if response.Err != nil {
+		var timeoutErr *manifestTypes.OperationTimeoutError
+		var operationErr *manifestTypes.OperationError
+		if errors.As(response.Err, &timeoutErr) {
+			status = v1alpha1.ConditionStatusFalse
+			message = timeoutErr.Error()
+		} else if errors.As(response.Err, &operationErr) {
+			status = v1alpha1.ConditionStatusFalse
+			message = operationErr.Error()
+		} else if response.Err != nil {
 			status = v1alpha1.ConditionStatusFalse
 			message = "installation error"
 		} else if !response.Ready {
@@ -97,6 +112,512 @@ func AddReadyConditionForResponses(responses []*types.InstallResponse, logger lo
 	}
 }
 
+// SetSchedulableCondition records in the Schedulable condition of the install, if all of its workloads
+// can be scheduled on the nodes of the target cluster. The transition time only changes with the status.
+func SetSchedulableCondition(manifest *v1alpha1.Manifest, installName string,
+	issues []manifestTypes.SchedulingIssue,
+) {
+	condition := v1alpha1.ManifestCondition{
+		Type:    v1alpha1.ConditionTypeSchedulable,
+		Status:  v1alpha1.ConditionStatusTrue,
+		Reason:  installName,
+		Message: "all workloads can be scheduled",
+	}
+	if len(issues) > 0 {
+		messages := make([]string, 0, len(issues))
+		for _, issue := range issues {
+			messages = append(messages, issue.String())
+		}
+		condition.Status = v1alpha1.ConditionStatusFalse
+		condition.Message = "workloads cannot be scheduled on any node: " + strings.Join(messages, "; ")
+	}
+
+	setInstallCondition(manifest, condition)
+}
+
+// SetSufficientCapacityCondition records in the SufficientCapacity condition of the install, if the resources
+// requested by its workloads fit the capacity left in the target cluster.
+func SetSufficientCapacityCondition(manifest *v1alpha1.Manifest, installName string,
+	shortages []manifestTypes.CapacityShortage,
+) {
+	condition := v1alpha1.ManifestCondition{
+		Type:    v1alpha1.ConditionTypeSufficientCapacity,
+		Status:  v1alpha1.ConditionStatusTrue,
+		Reason:  installName,
+		Message: "all workloads fit the capacity of the target cluster",
+	}
+	if len(shortages) > 0 {
+		messages := make([]string, 0, len(shortages))
+		for _, shortage := range shortages {
+			messages = append(messages, shortage.String())
+		}
+		condition.Status = v1alpha1.ConditionStatusFalse
+		condition.Message = manifestTypes.ErrInsufficientCapacity.Reason + ": " + strings.Join(messages, "; ")
+	}
+	setInstallCondition(manifest, condition)
+}
+
+// SetPrerequisitesMetCondition records in the PrerequisitesMet condition of the install, if the target cluster
+// serves the APIs of all of its rendered resources. The transition time only changes with the status.
+func SetPrerequisitesMetCondition(manifest *v1alpha1.Manifest, installName string,
+	missing []manifestTypes.MissingAPI,
+) {
+	condition := v1alpha1.ManifestCondition{
+		Type:    v1alpha1.ConditionTypePrerequisitesMet,
+		Status:  v1alpha1.ConditionStatusTrue,
+		Reason:  installName,
+		Message: "all required APIs are served by the target cluster",
+	}
+	if len(missing) > 0 {
+		messages := make([]string, 0, len(missing))
+		for _, api := range missing {
+			messages = append(messages, api.String())
+		}
+		condition.Status = v1alpha1.ConditionStatusFalse
+		condition.Message = manifestTypes.ErrPrerequisitesNotMet.Reason + ": " + strings.Join(messages, "; ")
+	}
+	setInstallCondition(manifest, condition)
+}
+
+// SetUpgradeAllowedCondition records in the UpgradeAllowed condition of the install, if its chart version
+// complies with its UpgradePolicy. The transition time only changes with the status.
+func SetUpgradeAllowedCondition(manifest *v1alpha1.Manifest, installName string, violation error) {
+	condition := v1alpha1.ManifestCondition{
+		Type:    v1alpha1.ConditionTypeUpgradeAllowed,
+		Status:  v1alpha1.ConditionStatusTrue,
+		Reason:  installName,
+		Message: "chart version complies with the upgrade policy",
+	}
+	if violation != nil {
+		condition.Status = v1alpha1.ConditionStatusFalse
+		condition.Message = violation.Error()
+	}
+	setInstallCondition(manifest, condition)
+}
+
+// SetUpgradePendingCondition records in the UpgradePending condition of the install, that the change of its chart
+// version waits for the passed start of the next upgrade window.
+func SetUpgradePendingCondition(manifest *v1alpha1.Manifest, installName, installed, target string, next time.Time) {
+	setInstallCondition(manifest, v1alpha1.ManifestCondition{
+		Type:   v1alpha1.ConditionTypeUpgradePending,
+		Status: v1alpha1.ConditionStatusTrue,
+		Reason: installName,
+		Message: fmt.Sprintf("upgrade from %s to %s waits for the upgrade window starting at %s",
+			installed, target, next.UTC().Format(time.RFC3339)),
+	})
+}
+
+// ClearUpgradePendingCondition resets the UpgradePending condition of the install,
+// once the change of its chart version no longer waits for an upgrade window.
+func ClearUpgradePendingCondition(manifest *v1alpha1.Manifest, installName string) {
+	setInstallCondition(manifest, v1alpha1.ManifestCondition{
+		Type:    v1alpha1.ConditionTypeUpgradePending,
+		Status:  v1alpha1.ConditionStatusFalse,
+		Reason:  installName,
+		Message: "no upgrade pending",
+	})
+}
+
+// PendingUpgrades returns the names of all installs, whose change of the chart version waits for an upgrade window.
+func PendingUpgrades(manifest *v1alpha1.Manifest) sets.String {
+	pending := sets.NewString()
+	for _, condition := range manifest.Status.Conditions {
+		if condition.Type == v1alpha1.ConditionTypeUpgradePending && condition.Status == v1alpha1.ConditionStatusTrue {
+			pending.Insert(condition.Reason)
+		}
+	}
+	return pending
+}
+
+// SetVerifiedCondition records in the Verified condition of the install, how its chart archive was verified,
+// or why its verification failed.
+func SetVerifiedCondition(manifest *v1alpha1.Manifest, installName string,
+	verification *manifestTypes.ChartVerification, err error,
+) {
+	condition := v1alpha1.ManifestCondition{
+		Type:   v1alpha1.ConditionTypeVerified,
+		Status: v1alpha1.ConditionStatusTrue,
+		Reason: installName,
+	}
+	if err != nil {
+		condition.Status = v1alpha1.ConditionStatusFalse
+		condition.Message = err.Error()
+	} else if verification != nil {
+		condition.Message = verification.String()
+	}
+	setInstallCondition(manifest, condition)
+}
+
+// SetDependenciesReadyCondition records in the DependenciesReady condition of the install, if the objects
+// it waits for satisfy their expectation. The transition time only changes with the status.
+func SetDependenciesReadyCondition(manifest *v1alpha1.Manifest, installName string,
+	unsatisfied []manifestTypes.UnsatisfiedWait,
+) {
+	condition := v1alpha1.ManifestCondition{
+		Type:    v1alpha1.ConditionTypeDependenciesReady,
+		Status:  v1alpha1.ConditionStatusTrue,
+		Reason:  installName,
+		Message: "all dependencies are ready",
+	}
+	if len(unsatisfied) > 0 {
+		condition.Status = v1alpha1.ConditionStatusFalse
+		condition.Message = "waiting for " + manifestTypes.UnsatisfiedWaitsMessage(unsatisfied)
+	}
+	setInstallCondition(manifest, condition)
+}
+
+// SetUninstalledCondition records in the Uninstalled condition of the removed install, why its resources
+// were not uninstalled. Uninstalled installs are forgotten along with their conditions instead.
+func SetUninstalledCondition(manifest *v1alpha1.Manifest, installName string, err error) {
+	setInstallCondition(manifest, v1alpha1.ManifestCondition{
+		Type:    v1alpha1.ConditionTypeUninstalled,
+		Status:  v1alpha1.ConditionStatusFalse,
+		Reason:  installName,
+		Message: err.Error(),
+	})
+}
+
+// RemoveUninstalledConditions removes the Uninstalled conditions of all installs, which are not removed anymore,
+// e.g. as they were added to the spec again.
+func RemoveUninstalledConditions(manifest *v1alpha1.Manifest, removedInstalls []string) {
+	removed := sets.NewString(removedInstalls...)
+	conditions := make([]v1alpha1.ManifestCondition, 0, len(manifest.Status.Conditions))
+	for _, condition := range manifest.Status.Conditions {
+		if condition.Type != v1alpha1.ConditionTypeUninstalled || removed.Has(condition.Reason) {
+			conditions = append(conditions, condition)
+		}
+	}
+	manifest.Status.Conditions = conditions
+}
+
+// SetPausedCondition records in the Paused condition of the Manifest, if its reconciliation is halted,
+// and indicates if the condition changed. Manifests, which were never paused, get no condition.
+func SetPausedCondition(manifest *v1alpha1.Manifest, paused bool) bool {
+	condition := v1alpha1.ManifestCondition{
+		Type:    v1alpha1.ConditionTypePaused,
+		Status:  v1alpha1.ConditionStatusTrue,
+		Reason:  opLabels.PausedAnnotation,
+		Message: "reconciliation paused until the " + opLabels.PausedAnnotation + " annotation is removed",
+	}
+	if !paused {
+		condition.Status = v1alpha1.ConditionStatusFalse
+		condition.Message = "reconciliation resumed"
+	}
+	for _, existing := range manifest.Status.Conditions {
+		if existing.Type == condition.Type && existing.Reason == condition.Reason {
+			if existing.Status == condition.Status {
+				return false
+			}
+			setInstallCondition(manifest, condition)
+			return true
+		}
+	}
+	if !paused {
+		return false
+	}
+	setInstallCondition(manifest, condition)
+	return true
+}
+
+// SetHealthyCondition records in the Healthy condition of the install, if all of its applied workloads
+// are healthy after the install succeeded. The transition time only changes with the status.
+func SetHealthyCondition(manifest *v1alpha1.Manifest, installName string, issues []manifestTypes.HealthIssue) {
+	condition := v1alpha1.ManifestCondition{
+		Type:    v1alpha1.ConditionTypeHealthy,
+		Status:  v1alpha1.ConditionStatusTrue,
+		Reason:  installName,
+		Message: "all workloads are healthy",
+	}
+	if len(issues) > 0 {
+		messages := make([]string, 0, len(issues))
+		for _, issue := range issues {
+			messages = append(messages, issue.String())
+		}
+		condition.Status = v1alpha1.ConditionStatusFalse
+		condition.Message = "workloads are degraded: " + strings.Join(messages, "; ")
+	}
+	setInstallCondition(manifest, condition)
+}
+
+// setInstallCondition replaces the condition of the same type and install, the transition time
+// only changes with the status.
+func setInstallCondition(manifest *v1alpha1.Manifest, condition v1alpha1.ManifestCondition) {
+	status := &manifest.Status
+	for i, existingCondition := range status.Conditions {
+		if existingCondition.Type != condition.Type || existingCondition.Reason != condition.Reason {
+			continue
+		}
+		condition.LastTransitionTime = existingCondition.LastTransitionTime
+		if existingCondition.Status != condition.Status {
+			condition.LastTransitionTime = &metav1.Time{Time: time.Now()}
+		}
+		status.Conditions[i] = condition
+		return
+	}
+	condition.LastTransitionTime = &metav1.Time{Time: time.Now()}
+	status.Conditions = append(status.Conditions, condition)
+}
+
+// RecordAppliedMigrations adds the versions of all migrations applied to every install
+// to the applied migrations of the Manifest, so that they are not applied again.
+func RecordAppliedMigrations(manifest *v1alpha1.Manifest, responses []*types.InstallResponse) {
+	if len(responses) == 0 {
+		return
+	}
+	appliedToAll := sets.NewString(responses[0].AppliedMigrations...)
+	for _, response := range responses[1:] {
+		appliedToAll = appliedToAll.Intersection(sets.NewString(response.AppliedMigrations...))
+	}
+	recorded := sets.NewString(manifest.Status.AppliedMigrations...)
+	// the order of the migrations is kept
+	for _, version := range responses[0].AppliedMigrations {
+		if appliedToAll.Has(version) && !recorded.Has(version) {
+			manifest.Status.AppliedMigrations = append(manifest.Status.AppliedMigrations, version)
+			recorded.Insert(version)
+		}
+	}
+}
+
+// RecordAppliedInstalls adds the release names of all passed responses to the applied installs of the Manifest,
+// so that their resources are uninstalled once they are removed from the spec.
+func RecordAppliedInstalls(manifest *v1alpha1.Manifest, responses []*types.InstallResponse) {
+	recorded := sets.NewString(manifest.Status.AppliedInstalls...)
+	for _, response := range responses {
+		// installs refused due to existing resources applied nothing, their next attempt verifies the resources again
+		if errors.Is(response.Err, manifestTypes.ErrResourceExists) {
+			continue
+		}
+		if response.ReleaseName != "" && !recorded.Has(response.ReleaseName) {
+			manifest.Status.AppliedInstalls = append(manifest.Status.AppliedInstalls, response.ReleaseName)
+			recorded.Insert(response.ReleaseName)
+		}
+	}
+}
+
+// FailedInstalls describes the installs of the passed responses, which failed with an error,
+// e.g. to record the resources left behind by a forced deletion.
+func FailedInstalls(responses []*types.InstallResponse) []string {
+	failed := make([]string, 0)
+	for _, response := range responses {
+		if response.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%s)", response.ChartName, response.Err))
+		}
+	}
+	return failed
+}
+
+// RecordInstalledVersions records the chart versions of all ready installs of the passed responses
+// as their installed versions, against which their UpgradePolicy is validated.
+func RecordInstalledVersions(manifest *v1alpha1.Manifest, responses []*types.InstallResponse) {
+	for _, response := range responses {
+		if !response.Ready || response.Err != nil || response.ReleaseName == "" || response.ChartVersion == "" {
+			continue
+		}
+		recorded := false
+		for i := range manifest.Status.InstalledVersions {
+			if manifest.Status.InstalledVersions[i].Name == response.ReleaseName {
+				manifest.Status.InstalledVersions[i].Version = response.ChartVersion
+				recorded = true
+			}
+		}
+		if !recorded {
+			manifest.Status.InstalledVersions = append(manifest.Status.InstalledVersions,
+				manifestTypes.InstalledVersion{Name: response.ReleaseName, Version: response.ChartVersion})
+		}
+	}
+}
+
+// RecordResolvedChannels records the versions the channels of all ready installs of the passed responses resolved to.
+// Installs, which no longer follow a channel, are removed from the resolved channels.
+func RecordResolvedChannels(manifest *v1alpha1.Manifest, responses []*types.InstallResponse) {
+	for _, response := range responses {
+		if !response.Ready || response.Err != nil || response.ReleaseName == "" {
+			continue
+		}
+		recorded := false
+		channels := make([]manifestTypes.ResolvedChannel, 0, len(manifest.Status.Channels))
+		for _, channel := range manifest.Status.Channels {
+			if channel.Name != response.ReleaseName {
+				channels = append(channels, channel)
+			} else if response.Channel != nil {
+				channels = append(channels, *response.Channel)
+				recorded = true
+			}
+		}
+		if !recorded && response.Channel != nil {
+			channels = append(channels, *response.Channel)
+		}
+		manifest.Status.Channels = channels
+	}
+}
+
+// RecordResolvedImages records the images the followed tags of all ready installs of the passed responses resolved to.
+// Installs, which no longer follow a tag, are removed from the resolved images.
+func RecordResolvedImages(manifest *v1alpha1.Manifest, responses []*types.InstallResponse) {
+	for _, response := range responses {
+		if !response.Ready || response.Err != nil || response.ReleaseName == "" {
+			continue
+		}
+		recorded := false
+		images := make([]manifestTypes.ResolvedImage, 0, len(manifest.Status.Images))
+		for _, image := range manifest.Status.Images {
+			if image.Name != response.ReleaseName {
+				images = append(images, image)
+			} else if response.Image != nil {
+				images = append(images, *response.Image)
+				recorded = true
+			}
+		}
+		if !recorded && response.Image != nil {
+			images = append(images, *response.Image)
+		}
+		manifest.Status.Images = images
+	}
+}
+
+// RecordLastApply records the apply status of all installs of the passed responses, which applied resources,
+// replacing the status of their previous apply.
+func RecordLastApply(manifest *v1alpha1.Manifest, responses []*types.InstallResponse) {
+	for _, response := range responses {
+		if response.ApplyStatus == nil {
+			continue
+		}
+		recorded := false
+		for i := range manifest.Status.LastApply {
+			if manifest.Status.LastApply[i].Install == response.ApplyStatus.Install {
+				manifest.Status.LastApply[i] = *response.ApplyStatus
+				recorded = true
+			}
+		}
+		if !recorded {
+			manifest.Status.LastApply = append(manifest.Status.LastApply, *response.ApplyStatus)
+		}
+	}
+}
+
+// RecordBackups records the snapshots stored by all installs of the passed responses before an upgrade,
+// replacing the snapshots stored before, also if the upgrade failed afterwards.
+func RecordBackups(manifest *v1alpha1.Manifest, responses []*types.InstallResponse) {
+	for _, response := range responses {
+		if response.Backup == nil {
+			continue
+		}
+		recorded := false
+		for i := range manifest.Status.Backups {
+			if manifest.Status.Backups[i].Name == response.Backup.Name {
+				manifest.Status.Backups[i] = *response.Backup
+				recorded = true
+			}
+		}
+		if !recorded {
+			manifest.Status.Backups = append(manifest.Status.Backups, *response.Backup)
+		}
+	}
+}
+
+// RemovedInstalls returns the names of all applied installs of the Manifest, which were removed from its spec.
+func RemovedInstalls(manifest *v1alpha1.Manifest) []string {
+	installs := sets.NewString()
+	for _, install := range manifest.Spec.Installs {
+		installs.Insert(install.Name)
+	}
+	var removed []string
+	for _, name := range manifest.Status.AppliedInstalls {
+		if !installs.Has(name) {
+			removed = append(removed, name)
+		}
+	}
+	return removed
+}
+
+// ForgetInstall removes the uninstalled install with the passed name from the applied installs of the Manifest,
+// together with its installed version, its resolved channel, its last apply, its backup and all of its conditions.
+func ForgetInstall(manifest *v1alpha1.Manifest, name string) {
+	status := &manifest.Status
+	appliedInstalls := make([]string, 0, len(status.AppliedInstalls))
+	for _, appliedInstall := range status.AppliedInstalls {
+		if appliedInstall != name {
+			appliedInstalls = append(appliedInstalls, appliedInstall)
+		}
+	}
+	status.AppliedInstalls = appliedInstalls
+	installedVersions := make([]manifestTypes.InstalledVersion, 0, len(status.InstalledVersions))
+	for _, installed := range status.InstalledVersions {
+		if installed.Name != name {
+			installedVersions = append(installedVersions, installed)
+		}
+	}
+	status.InstalledVersions = installedVersions
+	channels := make([]manifestTypes.ResolvedChannel, 0, len(status.Channels))
+	for _, channel := range status.Channels {
+		if channel.Name != name {
+			channels = append(channels, channel)
+		}
+	}
+	status.Channels = channels
+	images := make([]manifestTypes.ResolvedImage, 0, len(status.Images))
+	for _, image := range status.Images {
+		if image.Name != name {
+			images = append(images, image)
+		}
+	}
+	status.Images = images
+	lastApply := make([]manifestTypes.InstallApplyStatus, 0, len(status.LastApply))
+	for _, applied := range status.LastApply {
+		if applied.Install != name {
+			lastApply = append(lastApply, applied)
+		}
+	}
+	status.LastApply = lastApply
+	backups := make([]manifestTypes.BackupReference, 0, len(status.Backups))
+	for _, backup := range status.Backups {
+		if backup.Name != name {
+			backups = append(backups, backup)
+		}
+	}
+	status.Backups = backups
+	conditions := make([]v1alpha1.ManifestCondition, 0, len(status.Conditions))
+	for _, condition := range status.Conditions {
+		// conditions of charts located with the Helm CLI repository configuration are named "<install>/<chart>"
+		if condition.Reason != name && !strings.HasPrefix(condition.Reason, name+"/") {
+			conditions = append(conditions, condition)
+		}
+	}
+	status.Conditions = conditions
+}
+
+// InstallValuesHash returns the hash of the values and the chart source of the passed install.
+func InstallValuesHash(info *manifestTypes.InstallInfo) (uint32, error) {
+	return util.CalculateHash([]any{info.Flags, info.ChartName, info.ChartPath, info.URL})
+}
+
+// LastApplied returns the chart versions of the passed responses and the hash of their values,
+// combined with the parts of the Manifest spec affecting the applied resources,
+// as recorded in the status of a Manifest. Both are independent of the order of the responses.
+func LastApplied(responses []*types.InstallResponse, spec v1alpha1.ManifestSpec) (string, string, error) {
+	sorted := make([]*types.InstallResponse, len(responses))
+	copy(sorted, responses)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ChartName < sorted[j].ChartName
+	})
+
+	versions := make([]string, 0, len(sorted))
+	values := make(map[string]uint32, len(sorted))
+	for _, response := range sorted {
+		values[response.ChartName] = response.ValuesHash
+		if response.ChartVersion != "" {
+			versions = append(versions, response.ChartName+"@"+response.ChartVersion)
+		}
+	}
+	valuesHash, err := util.CalculateHash(
+		[]any{values, spec.Remote, spec.RemoteInfo, spec.Resource, spec.CRDs, spec.Transforms},
+	)
+	if err != nil {
+		return "", "", err
+	}
+	return strings.Join(versions, ","), strconv.FormatUint(uint64(valuesHash), 10), nil
+}
+
 func GetCacheFunc() cache.NewCacheFunc {
 	return cache.BuilderWithOptions(cache.Options{
 		SelectorsByObject: cache.SelectorsByObject{
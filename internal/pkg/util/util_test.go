@@ -0,0 +1,85 @@
+package util_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/module-manager/api/v1alpha1"
+	internalTypes "github.com/kyma-project/module-manager/internal/pkg/types"
+	"github.com/kyma-project/module-manager/internal/pkg/util"
+	manifestTypes "github.com/kyma-project/module-manager/pkg/types"
+)
+
+var errUntracked = errors.New("untracked")
+
+func Test_RemovedInstalls(t *testing.T) {
+	t.Parallel()
+	manifestObj := &v1alpha1.Manifest{}
+	manifestObj.Spec.Installs = []v1alpha1.InstallInfo{{Name: "kept"}, {Name: "added"}}
+	assert.Empty(t, util.RemovedInstalls(manifestObj), "installs were not applied yet")
+
+	manifestObj.Status.AppliedInstalls = []string{"removed", "kept", "dropped"}
+	assert.Equal(t, []string{"removed", "dropped"}, util.RemovedInstalls(manifestObj))
+}
+
+func Test_ForgetInstall(t *testing.T) {
+	t.Parallel()
+	manifestObj := &v1alpha1.Manifest{}
+	manifestObj.Status = v1alpha1.ManifestStatus{
+		AppliedInstalls:   []string{"kept", "removed"},
+		InstalledVersions: []manifestTypes.InstalledVersion{{Name: "kept"}, {Name: "removed"}},
+		Backups:           []manifestTypes.BackupReference{{Name: "removed"}},
+		Conditions: []v1alpha1.ManifestCondition{
+			{Type: v1alpha1.ConditionTypeReady, Reason: "kept"},
+			{Type: v1alpha1.ConditionTypeReady, Reason: "removed"},
+			{Type: v1alpha1.ConditionTypeVerified, Reason: "removed/chart"},
+		},
+	}
+	util.ForgetInstall(manifestObj, "removed")
+
+	assert.Equal(t, []string{"kept"}, manifestObj.Status.AppliedInstalls)
+	assert.Equal(t, []manifestTypes.InstalledVersion{{Name: "kept"}}, manifestObj.Status.InstalledVersions)
+	assert.Empty(t, manifestObj.Status.Backups)
+	assert.Equal(t, []v1alpha1.ManifestCondition{{Type: v1alpha1.ConditionTypeReady, Reason: "kept"}},
+		manifestObj.Status.Conditions, "conditions of the install and its charts are removed")
+}
+
+func Test_UninstalledConditions(t *testing.T) {
+	t.Parallel()
+	manifestObj := &v1alpha1.Manifest{}
+	util.SetUninstalledCondition(manifestObj, "untracked", errUntracked)
+	util.SetUninstalledCondition(manifestObj, "added", errUntracked)
+	util.SetUninstalledCondition(manifestObj, "untracked", errUntracked)
+	assert.Len(t, manifestObj.Status.Conditions, 2, "conditions are set per install")
+	assert.Equal(t, v1alpha1.ConditionStatusFalse, manifestObj.Status.Conditions[0].Status)
+	assert.Equal(t, errUntracked.Error(), manifestObj.Status.Conditions[0].Message)
+
+	util.RemoveUninstalledConditions(manifestObj, []string{"untracked"})
+	assert.Len(t, manifestObj.Status.Conditions, 1, "installs added again are not removed anymore")
+	assert.Equal(t, "untracked", manifestObj.Status.Conditions[0].Reason)
+
+	util.ForgetInstall(manifestObj, "untracked")
+	assert.Empty(t, manifestObj.Status.Conditions)
+}
+
+func Test_ReadyConditionOfTimedOutInstall(t *testing.T) {
+	t.Parallel()
+	manifestObj := &v1alpha1.Manifest{}
+	timeoutErr := &manifestTypes.OperationTimeoutError{Timeout: time.Minute, Err: context.DeadlineExceeded}
+	util.AddReadyConditionForResponses([]*internalTypes.InstallResponse{{ChartName: "slow", Err: timeoutErr}},
+		logr.Discard(), manifestObj)
+
+	require.Len(t, manifestObj.Status.Conditions, 1)
+	condition := manifestObj.Status.Conditions[0]
+	assert.Equal(t, v1alpha1.ConditionTypeReady, condition.Type)
+	assert.Equal(t, "slow", condition.Reason)
+	assert.Equal(t, v1alpha1.ConditionStatusFalse, condition.Status)
+	assert.Equal(t, timeoutErr.Error(), condition.Message, "the timeout is reported instead of a generic error")
+	assert.Contains(t, condition.Message, "will be retried")
+}
@@ -17,22 +17,33 @@ limitations under the License.
 package main
 
 import (
+	"bytes"
+	"context"
 	"flag"
 	"net/http"
 	"net/http/pprof"
 	"os"
+	"strings"
 	"time"
 
 	"k8s.io/client-go/rest"
 
 	manifestv1alpha1 "github.com/kyma-project/module-manager/api/v1alpha1"
+	manifestv1beta1 "github.com/kyma-project/module-manager/api/v1beta1"
 	"github.com/kyma-project/module-manager/controllers"
 	internalTypes "github.com/kyma-project/module-manager/internal/pkg/types"
 	"github.com/kyma-project/module-manager/internal/pkg/util"
+	"github.com/kyma-project/module-manager/pkg/descriptor"
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/tracing"
 	"github.com/kyma-project/module-manager/pkg/types"
+	manifestUtil "github.com/kyma-project/module-manager/pkg/util"
+	"github.com/kyma-project/module-manager/pkg/version"
 
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	apiExtensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 
@@ -40,6 +51,7 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	k8slabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -65,12 +77,21 @@ const (
 	clientBurstDefault            = 150
 	defaultPprofServerTimeout     = 90 * time.Second
 	defaultCacheSyncTimeout       = 2 * time.Minute
+	serverVersionCheckDefault     = 5 * time.Minute
+	installLockDurationDefault    = 30 * time.Second
+	batchConcurrencyDefault       = 5
+	layerStoreMaxSizeDefault      = 2 << 30
+	isolationGroupRetryDefault    = 5 * time.Second
+	errorRateWindowDefault        = 5 * time.Minute
+	errorRateMinSamplesDefault    = 10
+	uninstallRetryBudgetDefault   = 10
 )
 
 //nolint:gochecknoinits
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(manifestv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(manifestv1beta1.AddToScheme(scheme))
 	utilruntime.Must(apiExtensionsv1.AddToScheme(scheme))
 
 	utilruntime.Must(manifestv1alpha1.AddToScheme(scheme))
@@ -81,6 +102,8 @@ type FlagVar struct {
 	metricsAddr, listenerAddr                            string
 	enableLeaderElection, enablePProf, enableWebhooks    bool
 	checkReadyStates, customStateCheck, insecureRegistry bool
+	trackInventory, watchInstalledResources              bool
+	partialInstallPolicy                                 string
 	probeAddr                                            string
 	requeueSuccessInterval                               time.Duration
 	failureBaseDelay, failureMaxDelay                    time.Duration
@@ -91,19 +114,65 @@ type FlagVar struct {
 	pprofAddr                                            string
 	pprofServerTimeout                                   time.Duration
 	cacheSyncTimeout                                     time.Duration
+	releaseAuditInterval                                 time.Duration
+	releaseAuditPurge                                    bool
+	releaseAuditNamespaces                               string
+	contentScanMode, contentScanDeniedImages             string
+	operationTimeout                                     time.Duration
+	releaseHistoryLimit                                  int
+	rollbackWindow                                       time.Duration
+	serverVersionCheckInterval                           time.Duration
+	installLockDuration                                  time.Duration
+	releaseHistoryKeys                                   string
+	registryWebhookAddr                                  string
+	registryWebhookSecretFile                            string
+	exportArchive, importArchive                         string
+	batchPatch, batchSelector                            string
+	batchConcurrency                                     int
+	batchAllManifests                                    bool
+	isolationGroupLimit                                  int
+	layerStoreDir                                        string
+	layerStoreMaxSize                                    int64
+	chartRepositoryIndexTTL                              time.Duration
+	archiveTTL                                           time.Duration
+	gitRevisionTTL                                       time.Duration
+	gitBinary                                            string
+	enableModuleReleases                                 bool
+	enableModuleCatalogs                                 bool
+	readinessStuckThreshold, readinessErrorRateWindow    time.Duration
+	readinessRequireLeader, readinessRemoteCacheSync     bool
+	readinessErrorRateThreshold                          float64
+	readinessErrorRateMinSamples                         int
+	uninstallRetryBudget                                 int
+	logVerbosity                                         int
+	tracingCollectorEndpoint                             string
+	tracingSampleRatio                                   float64
+	verifyCapacity                                       bool
 }
 
 func main() {
 	flagVar := defineFlagVar()
 	flag.Parse()
-	ctrl.SetLogger(log.ConfigLogger())
+	ctrl.SetLogger(log.ConfigLoggerWithVerbosity(flagVar.logVerbosity))
+	setupLog.Info("module-manager build", "version", version.Get().Version, "gitCommit", version.Get().GitCommit)
 
 	config := ctrl.GetConfigOrDie()
 	config.QPS = float32(flagVar.clientQPS)
 	config.Burst = flagVar.clientBurst
+	if flagVar.exportArchive != "" || flagVar.importArchive != "" {
+		runManifestArchive(flagVar, scheme, config)
+		return
+	}
+	if flagVar.batchPatch != "" {
+		runManifestBatchPatch(flagVar, scheme, config)
+		return
+	}
 	if flagVar.enablePProf {
 		go pprofStartServer(flagVar.pprofAddr, flagVar.pprofServerTimeout)
 	}
+	if flagVar.tracingCollectorEndpoint != "" {
+		defer setupTracing(flagVar)()
+	}
 	setupWithManager(flagVar, util.GetCacheFunc(), scheme, config)
 }
 
@@ -128,7 +197,138 @@ func pprofStartServer(addr string, timeout time.Duration) {
 	}
 }
 
+// runManifestArchive exports or imports all Manifests and the inventories of their installs
+// instead of starting the manager.
+func runManifestArchive(flagVar *FlagVar, scheme *runtime.Scheme, config *rest.Config) {
+	clnt, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client for manifest archive")
+		os.Exit(1)
+	}
+	archiver := &controllers.ManifestArchiver{
+		Client: clnt, Config: config, Logger: ctrl.Log.WithName("manifest-archive"),
+	}
+	ctx := ctrl.SetupSignalHandler()
+
+	if flagVar.exportArchive != "" {
+		if err := exportManifestArchive(ctx, archiver, flagVar.exportArchive); err != nil {
+			setupLog.Error(err, "unable to export manifests")
+			os.Exit(1)
+		}
+		return
+	}
+	if err := importManifestArchive(ctx, archiver, flagVar.importArchive); err != nil {
+		setupLog.Error(err, "unable to import manifests")
+		os.Exit(1)
+	}
+}
+
+func exportManifestArchive(ctx context.Context, archiver *controllers.ManifestArchiver, path string) error {
+	archive, err := archiver.Export(ctx)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := controllers.WriteManifestArchive(file, archive); err != nil {
+		return err
+	}
+	setupLog.Info("exported manifests", "count", len(archive.Manifests), "archive", path)
+	return nil
+}
+
+func importManifestArchive(ctx context.Context, archiver *controllers.ManifestArchiver, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	archive, err := controllers.ReadManifestArchive(file)
+	if err != nil {
+		return err
+	}
+	if err := archiver.Import(ctx, archive); err != nil {
+		return err
+	}
+	setupLog.Info("imported manifests", "count", len(archive.Manifests), "archive", path)
+	return nil
+}
+
+// runManifestBatchPatch applies a JSON merge patch to all Manifests matching the batch selector
+// instead of starting the manager.
+func runManifestBatchPatch(flagVar *FlagVar, scheme *runtime.Scheme, config *rest.Config) {
+	mergePatch, err := os.ReadFile(flagVar.batchPatch)
+	if err != nil {
+		setupLog.Error(err, "unable to read manifest batch patch")
+		os.Exit(1)
+	}
+	mutate, err := controllers.MergePatchMutation(mergePatch)
+	if err != nil {
+		setupLog.Error(err, "unable to parse manifest batch patch")
+		os.Exit(1)
+	}
+	// an empty selector matches all Manifests, which have to be patched on purpose
+	if (flagVar.batchSelector == "") == !flagVar.batchAllManifests {
+		setupLog.Error(nil, "either --batch-manifest-selector or --batch-all-manifests has to be set")
+		os.Exit(1)
+	}
+	selector, err := k8slabels.Parse(flagVar.batchSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid manifest batch selector")
+		os.Exit(1)
+	}
+	clnt, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client for manifest batch patch")
+		os.Exit(1)
+	}
+	updater := &controllers.ManifestBatchUpdater{
+		Client: clnt, Logger: ctrl.Log.WithName("manifest-batch"), Concurrency: flagVar.batchConcurrency,
+	}
+	report, err := updater.Update(ctrl.SetupSignalHandler(), mutate, client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		setupLog.Error(err, "unable to patch manifests")
+		os.Exit(1)
+	}
+	setupLog.Info("patched manifests", "matched", report.Matched, "updated", report.Updated,
+		"unchanged", report.Unchanged, "failed", len(report.Failed))
+	if len(report.Failed) > 0 {
+		os.Exit(1)
+	}
+}
+
+// setupTracing exports the spans of reconciles and operations to the configured collector
+// and returns a function flushing the remaining spans.
+func setupTracing(flagVar *FlagVar) func() {
+	shutdown, err := tracing.Setup(flagVar.tracingCollectorEndpoint, labels.OperatorName, flagVar.tracingSampleRatio)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	return func() {
+		if err := shutdown(context.Background()); err != nil {
+			setupLog.Error(err, "unable to flush traces")
+		}
+	}
+}
+
 func setupWithManager(flagVar *FlagVar, newCacheFunc cache.NewCacheFunc, scheme *runtime.Scheme, config *rest.Config) {
+	switch types.ScanMode(flagVar.contentScanMode) {
+	case types.ScanModeDisabled, types.ScanModeWarn, types.ScanModeBlock:
+	default:
+		setupLog.Error(nil, "invalid content scan mode", "mode", flagVar.contentScanMode)
+		os.Exit(1)
+	}
+	switch types.PartialInstallPolicy(flagVar.partialInstallPolicy) {
+	case types.PartialInstallPolicyComplete, types.PartialInstallPolicyRollback:
+	default:
+		setupLog.Error(nil, "invalid partial install policy", "policy", flagVar.partialInstallPolicy)
+		os.Exit(1)
+	}
+
 	mgr, err := ctrl.NewManager(config, ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     flagVar.metricsAddr,
@@ -150,31 +350,99 @@ func setupWithManager(flagVar *FlagVar, newCacheFunc cache.NewCacheFunc, scheme
 		setupLog.Error(err, "unable to initialize codec")
 		os.Exit(1)
 	}
+	var releaseEncrypter types.KeyEncrypter
+	if flagVar.releaseHistoryKeys != "" {
+		if releaseEncrypter, err = manifestUtil.LoadLocalKeyEncrypter(flagVar.releaseHistoryKeys); err != nil {
+			setupLog.Error(err, "unable to load release history encryption keys")
+			os.Exit(1)
+		}
+	}
+	healthMonitor := newHealthMonitor(flagVar, mgr)
+	// indexes of chart repositories are shared between Manifests and ModuleCatalogs
+	chartRepos := chartRepositories(flagVar)
+	layerStore := descriptor.NewLayerStore(flagVar.layerStoreDir, flagVar.layerStoreMaxSize)
 	if err = (&controllers.ManifestReconciler{
-		Client:           mgr.GetClient(),
-		Scheme:           mgr.GetScheme(),
-		Workers:          manifestWorkers,
-		CacheSyncTimeout: flagVar.cacheSyncTimeout,
+		Client:                mgr.GetClient(),
+		Scheme:                mgr.GetScheme(),
+		Workers:               manifestWorkers,
+		CacheSyncTimeout:      flagVar.cacheSyncTimeout,
+		RegistryWebhookAddr:   flagVar.registryWebhookAddr,
+		RegistryWebhookSecret: registryWebhookSecret(flagVar),
+		WatchModuleCatalogs:   flagVar.enableModuleCatalogs,
 		ReconcileFlagConfig: internalTypes.ReconcileFlagConfig{
 			Codec:                   codec,
 			MaxConcurrentReconciles: flagVar.concurrentReconciles,
 			CheckReadyStates:        flagVar.checkReadyStates,
 			CustomStateCheck:        flagVar.customStateCheck,
 			InsecureRegistry:        flagVar.insecureRegistry,
+			TrackInventory:          flagVar.trackInventory,
+			ContentScanMode:         types.ScanMode(flagVar.contentScanMode),
+			ContentScanners:         manifestUtil.DefaultContentScanners(commaSeparated(flagVar.contentScanDeniedImages)),
+			OperationTimeout:        flagVar.operationTimeout,
+			ReleaseHistoryLimit:     flagVar.releaseHistoryLimit,
+			RollbackWindow:          flagVar.rollbackWindow,
+			ReleaseEncrypter:        releaseEncrypter,
+			Migrations:              migrations(),
+			WatchInstalledResources: flagVar.watchInstalledResources,
+
+			ServerVersionCheckInterval: flagVar.serverVersionCheckInterval,
+			LayerStore:                 layerStore,
+			ChartRepositories:          chartRepos,
+			Archives:                   descriptor.NewArchives(layerStore, flagVar.archiveTTL),
+			GitRepositories:            gitRepositories(flagVar),
+			PartialInstallPolicy:       types.PartialInstallPolicy(flagVar.partialInstallPolicy),
+			SkipCapacityVerification:   !flagVar.verifyCapacity,
 		},
 		RequeueIntervals: controllers.RequeueIntervals{
 			Success: flagVar.requeueSuccessInterval,
 		},
+		InstallLockDuration:  installLockDuration(flagVar),
+		IsolationGroups:      isolationGroups(flagVar),
+		HealthMonitor:        healthMonitor,
+		UninstallRetryBudget: flagVar.uninstallRetryBudget,
 	}).SetupWithManager(context, mgr, flagVar.failureBaseDelay, flagVar.failureMaxDelay,
 		flagVar.rateLimiterFrequency, flagVar.rateLimiterBurst, flagVar.listenerAddr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Manifest")
 		os.Exit(1)
 	}
+	if flagVar.releaseAuditInterval > 0 {
+		if err = mgr.Add(&controllers.ReleaseStorageAudit{
+			Reader:     mgr.GetAPIReader(),
+			Writer:     mgr.GetClient(),
+			Interval:   flagVar.releaseAuditInterval,
+			Purge:      flagVar.releaseAuditPurge,
+			Namespaces: commaSeparated(flagVar.releaseAuditNamespaces),
+			Logger:     ctrl.Log.WithName("release-audit"),
+		}); err != nil {
+			setupLog.Error(err, "unable to add release storage audit")
+			os.Exit(1)
+		}
+	}
+	if flagVar.enableModuleReleases {
+		if err = (&controllers.ModuleReleaseReconciler{Client: mgr.GetClient()}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ModuleRelease")
+			os.Exit(1)
+		}
+	}
+	if flagVar.enableModuleCatalogs {
+		if err = (&controllers.ModuleCatalogReconciler{
+			Client:            mgr.GetClient(),
+			ChartRepositories: chartRepos,
+			InsecureRegistry:  flagVar.insecureRegistry,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ModuleCatalog")
+			os.Exit(1)
+		}
+	}
 	if flagVar.enableWebhooks {
 		if err = (&manifestv1alpha1.Manifest{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Manifest")
 			os.Exit(1)
 		}
+		if err = (&manifestv1beta1.Manifest{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create conversion webhook", "webhook", "Manifest")
+			os.Exit(1)
+		}
 	}
 
 	//+kubebuilder:scaffold:builder
@@ -186,6 +454,17 @@ func setupWithManager(flagVar *FlagVar, newCacheFunc cache.NewCacheFunc, scheme
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if healthMonitor != nil {
+		if err := mgr.AddReadyzCheck("manifest-health", healthMonitor.Checker); err != nil {
+			setupLog.Error(err, "unable to set up manifest health check")
+			os.Exit(1)
+		}
+	}
+	if err := mgr.AddMetricsExtraHandler("/version", version.Handler()); err != nil {
+		setupLog.Error(err, "unable to set up version endpoint")
+		os.Exit(1)
+	}
+	metrics.Registry.MustRegister(version.Collector())
 	setupLog.Info("starting manager")
 	if err := mgr.Start(context); err != nil {
 		setupLog.Error(err, "problem running manager")
@@ -206,6 +485,10 @@ func defineFlagVar() *FlagVar {
 	flag.BoolVar(&flagVar.enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&flagVar.installLockDuration, "install-lock-duration", installLockDurationDefault,
+		"Determines the lease duration of the per Manifest install lock, which prevents a new leader from "+
+			"operating on a Manifest while operations of the previous leader are still running. The lock is only "+
+			"used with leader election, a duration of 0 disables it.")
 	flag.DurationVar(&flagVar.requeueSuccessInterval, "requeue-success-interval", requeueSuccessIntervalDefault,
 		"Determines the duration after which an already successfully reconciled Manifest is "+
 			"enqueued for checking, if it's still in a consistent state.")
@@ -213,6 +496,9 @@ func defineFlagVar() *FlagVar {
 		"Determines the number of concurrent reconciliations by the operator.")
 	flag.IntVar(&flagVar.workersConcurrentManifests, "workers-concurrent-manifest", workersCountDefault,
 		"Determines the number of concurrent manifest operations for a single resource by the operator.")
+	flag.IntVar(&flagVar.isolationGroupLimit, "isolation-group-limit", 0,
+		"Determines the number of Manifests of the same isolation group, by default the same target cluster, "+
+			"processed at the same time. A limit of 0 disables isolation groups.")
 	flag.BoolVar(&flagVar.checkReadyStates, "check-ready-states", false,
 		"Indicates if installed resources should be verified after installation, "+
 			"before marking the resource state to a consistent state.")
@@ -230,6 +516,26 @@ func defineFlagVar() *FlagVar {
 	flag.IntVar(&flagVar.clientBurst, "k8s-client-burst", clientBurstDefault, "kubernetes client Burst")
 	flag.BoolVar(&flagVar.insecureRegistry, "insecure-registry", false,
 		"indicates if insecure (http) response is expected from image registry")
+	flag.BoolVar(&flagVar.trackInventory, "track-inventory", false,
+		"indicates if installed resources should be recorded in an inventory in the target cluster, "+
+			"which is used to prune resources no longer part of an install. Disabled by default, as it writes "+
+			"an inventory next to every install in the target cluster.")
+	flag.StringVar(&flagVar.partialInstallPolicy, "partial-install-policy", string(types.PartialInstallPolicyComplete),
+		"determines how resources left over by a failed install are handled before it is retried, "+
+			"either Complete or Rollback. Requires the inventory to be tracked.")
+	flag.BoolVar(&flagVar.watchInstalledResources, "watch-installed-resources", false,
+		"indicates if installed resources should be labeled with their owning Manifest and watched in the target "+
+			"cluster, so that changes to them trigger a reconciliation immediately instead of on the next resync")
+	flag.BoolVar(&flagVar.verifyCapacity, "verify-capacity", true,
+		"indicates if the CPU and memory requested by rendered workloads should be compared with the ResourceQuotas "+
+			"of their namespace and the allocatable resources of the nodes before they are applied, blocking installs "+
+			"exceeding the capacity left in the target cluster")
+	flag.BoolVar(&flagVar.enableModuleReleases, "enable-module-releases", false,
+		"Enables the aggregation of the states of the Manifests selected by ModuleReleases. "+
+			"Requires the ModuleRelease CRD to be installed.")
+	flag.BoolVar(&flagVar.enableModuleCatalogs, "enable-module-catalogs", false,
+		"Enables the discovery of the versions and channels of the repositories listed by ModuleCatalogs, "+
+			"which installs of Manifests reference by channel. Requires the ModuleCatalog CRD to be installed.")
 	flag.BoolVar(&flagVar.enableWebhooks, "enable-webhooks", false,
 		"indicates if webhooks should be enabled")
 	flag.BoolVar(&flagVar.enablePProf, "enable-pprof", false,
@@ -238,5 +544,184 @@ func defineFlagVar() *FlagVar {
 		"Timeout of Read / Write for the pprof server.")
 	flag.DurationVar(&flagVar.cacheSyncTimeout, "cache-sync-timeout", defaultCacheSyncTimeout,
 		"Indicates the cache sync timeout in seconds")
+	flag.DurationVar(&flagVar.releaseAuditInterval, "release-audit-interval", 0,
+		"Determines the interval in which helm release storage is audited for releases without a matching "+
+			"Manifest. An interval of 0 disables the audit. Release storage in the target clusters of remote "+
+			"Manifests is not audited.")
+	flag.BoolVar(&flagVar.releaseAuditPurge, "release-audit-purge", false,
+		"Indicates if orphaned helm release storage detected by the audit should be purged.")
+	flag.StringVar(&flagVar.releaseAuditNamespaces, "release-audit-namespaces", "",
+		"Comma-separated list of namespaces, in which all helm releases are created by the operator. "+
+			"Besides releases labeled as managed by the operator, only releases in these namespaces are audited.")
+	flag.StringVar(&flagVar.contentScanMode, "content-scan-mode", string(types.ScanModeDisabled),
+		"Determines if rendered resources are scanned for embedded credentials and denied images before they "+
+			"are applied. Findings are recorded in the Manifest status with \"warn\" and additionally block the "+
+			"installation with \"block\". An empty value disables scanning.")
+	flag.StringVar(&flagVar.contentScanDeniedImages, "content-scan-denied-images", "",
+		"Comma-separated list of image prefixes reported by the content scan, e.g. \"docker.io/library/busybox\"")
+	flag.DurationVar(&flagVar.operationTimeout, "operation-timeout", 0,
+		"Limits the duration of a single install, uninstall or consistency check of a Manifest install, "+
+			"unless overridden by the Manifest. A timeout of 0 disables the limit.")
+	flag.IntVar(&flagVar.releaseHistoryLimit, "release-history-limit", 0,
+		"Determines the number of release revisions recorded per Manifest install in the target cluster. "+
+			"Changed manifests are applied as upgrades of the last deployed revision and Helm charts are rendered "+
+			"with the revision, as .Release.Revision, .Release.IsInstall and .Release.IsUpgrade. A limit of 0 "+
+			"disables the release history.")
+	flag.DurationVar(&flagVar.rollbackWindow, "rollback-window", 0,
+		"Determines the duration a new release revision may take to become ready before it is rolled back "+
+			"to the last deployed revision. Requires the release history, a window of 0 disables rollbacks.")
+	flag.DurationVar(&flagVar.serverVersionCheckInterval, "server-version-check-interval", serverVersionCheckDefault,
+		"Determines the interval in which the API server version of target clusters is checked. On a change, e.g. "+
+			"after a cluster upgrade, the cached discovery data of the cluster is invalidated. "+
+			"An interval of 0 disables the check.")
+	flag.StringVar(&flagVar.releaseHistoryKeys, "release-history-encryption-keys", "",
+		"Path to a file of AES keys envelope encrypting the rendered manifests of the release history, one "+
+			"\"<key-id>=<base64 encoded key>\" per line. The first key encrypts, all keys decrypt, so that a key is "+
+			"rotated by prepending a new one. An empty path disables encryption.")
+	flag.StringVar(&flagVar.registryWebhookAddr, "registry-webhook-listener-addr", "",
+		"The address the registry webhook listener binds to. Push notifications of Harbor, DockerHub and "+
+			"Artifact Registry are accepted on /v1/registry/<format> and enqueue all Manifests following the tag "+
+			"of the pushed image. An empty address disables the listener.")
+	flag.StringVar(&flagVar.registryWebhookSecretFile, "registry-webhook-secret-file", "",
+		"Path to a file of the secret, which registry push notifications have to pass as bearer token or as "+
+			"\"token\" query parameter. It is required by the registry webhook listener.")
+	flag.StringVar(&flagVar.exportArchive, "export-manifest-archive", "",
+		"Path of an archive to which all Manifests and the inventories of their installs are exported for "+
+			"disaster recovery. If set, the operator exits after the export instead of starting.")
+	flag.StringVar(&flagVar.importArchive, "import-manifest-archive", "",
+		"Path of an archive written with --export-manifest-archive, whose Manifests are imported with their "+
+			"status, so that their running installs are adopted instead of being reinstalled. If set, the operator "+
+			"exits after the import instead of starting.")
+	flag.StringVar(&flagVar.layerStoreDir, "layer-store-dir", descriptor.DefaultLayerStoreRoot(),
+		"The directory in which pulled OCI layers are stored by their digest and shared by all Manifests.")
+	flag.Int64Var(&flagVar.layerStoreMaxSize, "layer-store-max-size", layerStoreMaxSizeDefault,
+		"The size in bytes up to which OCI layers are stored, before the least recently used layers are evicted. "+
+			"A size of 0 disables eviction.")
+	flag.DurationVar(&flagVar.chartRepositoryIndexTTL, "chart-repository-index-ttl", descriptor.DefaultChartIndexTTL,
+		"The duration for which the index of a Helm chart repository is cached, before it is downloaded again. "+
+			"Cached indexes without a version matching a chart are downloaded again immediately.")
+	flag.DurationVar(&flagVar.archiveTTL, "archive-ttl", descriptor.DefaultArchiveTTL,
+		"The duration for which an archive source without digest is cached, before it is downloaded again. "+
+			"Archives with a digest are only downloaded once.")
+	flag.DurationVar(&flagVar.gitRevisionTTL, "git-revision-ttl", descriptor.DefaultGitRevisionTTL,
+		"The duration for which the commit of a branch or tag of a Git source is cached, before it is resolved "+
+			"again. Revisions given as commit are never resolved again.")
+	flag.StringVar(&flagVar.gitBinary, "git-binary", "git",
+		"The name or path of the git binary, with which Git sources are fetched.")
+	flag.StringVar(&flagVar.batchPatch, "batch-patch-manifests", "",
+		"Path of a JSON merge patch applied to the spec and metadata of all Manifests matching "+
+			"--batch-manifest-selector, e.g. to bump the channel of a module. If set, the operator exits after "+
+			"patching instead of starting.")
+	flag.StringVar(&flagVar.batchSelector, "batch-manifest-selector", "",
+		"Label selector of the Manifests patched with --batch-patch-manifests. "+
+			"Either a selector or --batch-all-manifests is required.")
+	flag.BoolVar(&flagVar.batchAllManifests, "batch-all-manifests", false,
+		"Patches all Manifests with --batch-patch-manifests instead of those matching --batch-manifest-selector.")
+	flag.IntVar(&flagVar.batchConcurrency, "batch-concurrency", batchConcurrencyDefault,
+		"The number of Manifests patched at a time with --batch-patch-manifests.")
+	flag.DurationVar(&flagVar.readinessStuckThreshold, "readiness-stuck-threshold", 0,
+		"Fails the readiness probe, if reconciles are running without any completing for this duration. "+
+			"It has to exceed the longest expected reconcile, a duration of 0 disables the check.")
+	flag.BoolVar(&flagVar.readinessRequireLeader, "readiness-require-leader", false,
+		"Fails the readiness probe of replicas not holding the leader election lease.")
+	flag.BoolVar(&flagVar.readinessRemoteCacheSync, "readiness-require-remote-cache-sync", false,
+		"Fails the readiness probe until the informers of resources watched in target clusters are synced, "+
+			"requires --watch-installed-resources.")
+	flag.Float64Var(&flagVar.readinessErrorRateThreshold, "readiness-error-rate-threshold", 0,
+		"Fails the readiness probe, if the ratio of failed reconciles within --readiness-error-rate-window "+
+			"exceeds the threshold, e.g. 0.5. A threshold of 0 disables the check.")
+	flag.DurationVar(&flagVar.readinessErrorRateWindow, "readiness-error-rate-window", errorRateWindowDefault,
+		"The sliding window the error rate of reconciles is calculated for.")
+	flag.IntVar(&flagVar.readinessErrorRateMinSamples, "readiness-error-rate-min-samples", errorRateMinSamplesDefault,
+		"The number of reconciles within the error rate window required to evaluate the error rate.")
+	flag.IntVar(&flagVar.uninstallRetryBudget, "uninstall-retry-budget", uninstallRetryBudgetDefault,
+		"The number of failed uninstall attempts, after which Manifests annotated with "+
+			labels.ForceDeleteAnnotation+"=true are finalized regardless. Zero disables force deletion.")
+	flag.IntVar(&flagVar.logVerbosity, "log-verbosity", log.InfoLevel,
+		"The verbosity of the JSON log lines, e.g. 2 for debug and 3 for trace lines.")
+	flag.StringVar(&flagVar.tracingCollectorEndpoint, "tracing-collector-endpoint", "",
+		"The Jaeger collector endpoint, e.g. http://jaeger-collector:14268/api/traces, to which spans of "+
+			"reconciles and operations are exported. An empty endpoint disables tracing.")
+	flag.Float64Var(&flagVar.tracingSampleRatio, "tracing-sample-ratio", 1,
+		"The ratio of traces recorded, unless continued from a sampled "+labels.TraceParentAnnotation+" annotation.")
 	return flagVar
 }
+
+// installLockDuration returns the duration of the install lock, which is only needed if other replicas could
+// still run operations, i.e. with leader election.
+func installLockDuration(flagVar *FlagVar) time.Duration {
+	if !flagVar.enableLeaderElection {
+		return 0
+	}
+	return flagVar.installLockDuration
+}
+
+// commaSeparated returns the non-empty values of a comma-separated flag.
+func commaSeparated(flagValue string) []string {
+	var values []string
+	for _, value := range strings.Split(flagValue, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// migrations returns the migrations of resources applied by previous versions of the operator.
+// New migrations are appended when the conventions for managed resources change, e.g. their labels,
+// and are applied once to every Manifest, see types.Migration.
+func migrations() []types.Migration {
+	return []types.Migration{}
+}
+
+// isolationGroups returns the isolation groups capping concurrent Manifests per group,
+// or nil if no limit was configured.
+func isolationGroups(flagVar *FlagVar) *controllers.IsolationGroups {
+	if flagVar.isolationGroupLimit <= 0 {
+		return nil
+	}
+	return controllers.NewIsolationGroups(flagVar.isolationGroupLimit, isolationGroupRetryDefault)
+}
+
+// gitRepositories returns the GitRepositories checking out Git sources for all Manifests.
+func gitRepositories(flagVar *FlagVar) *descriptor.GitRepositories {
+	repositories := descriptor.NewGitRepositories(descriptor.DefaultGitRepositoriesRoot(), flagVar.gitRevisionTTL)
+	repositories.Binary = flagVar.gitBinary
+	return repositories
+}
+
+// registryWebhookSecret returns the secret of the registry webhook listener, nil if no secret file is set.
+func registryWebhookSecret(flagVar *FlagVar) []byte {
+	if flagVar.registryWebhookSecretFile == "" {
+		return nil
+	}
+	secret, err := os.ReadFile(flagVar.registryWebhookSecretFile)
+	if err != nil {
+		setupLog.Error(err, "unable to read registry webhook secret")
+		os.Exit(1)
+	}
+	return bytes.TrimSpace(secret)
+}
+
+// chartRepositories returns the ChartRepositories resolving charts of Helm repositories for all Manifests.
+func chartRepositories(flagVar *FlagVar) *descriptor.ChartRepositories {
+	return descriptor.NewChartRepositories(descriptor.DefaultChartRepositoriesRoot(), flagVar.chartRepositoryIndexTTL)
+}
+
+// newHealthMonitor returns the HealthMonitor failing the readiness probe on violations of the configured criteria,
+// or nil if no criterion is configured.
+func newHealthMonitor(flagVar *FlagVar, mgr ctrl.Manager) *controllers.HealthMonitor {
+	criteria := controllers.HealthCriteria{
+		StuckThreshold:         flagVar.readinessStuckThreshold,
+		RequireLeader:          flagVar.readinessRequireLeader,
+		RequireRemoteCacheSync: flagVar.readinessRemoteCacheSync,
+		ErrorRateThreshold:     flagVar.readinessErrorRateThreshold,
+		ErrorRateWindow:        flagVar.readinessErrorRateWindow,
+		ErrorRateMinSamples:    flagVar.readinessErrorRateMinSamples,
+	}
+	if criteria.StuckThreshold <= 0 && !criteria.RequireLeader && !criteria.RequireRemoteCacheSync &&
+		criteria.ErrorRateThreshold <= 0 {
+		return nil
+	}
+	return controllers.NewHealthMonitor(criteria, mgr.Elected())
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -13,6 +14,7 @@ import (
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kyma-project/module-manager/pkg/client"
+	"github.com/kyma-project/module-manager/pkg/tracing"
 	"github.com/kyma-project/module-manager/pkg/types"
 	"github.com/kyma-project/module-manager/pkg/util"
 )
@@ -28,10 +30,11 @@ type SetApplier struct {
 	clients       *client.SingletonClients
 }
 
+// NewSSAApplier returns an Applier using server-side apply, which resolves field ownership conflicts according to
+// the types.ConflictPolicy of each install.
 func NewSSAApplier(clients *client.SingletonClients, logger logr.Logger) *SetApplier {
-	force := true
 	return &SetApplier{
-		patchOptions: metav1.PatchOptions{FieldManager: fieldManager, Force: &force},
+		patchOptions: metav1.PatchOptions{FieldManager: fieldManager},
 		logger:       logger,
 		clients:      clients,
 	}
@@ -39,9 +42,12 @@ func NewSSAApplier(clients *client.SingletonClients, logger logr.Logger) *SetApp
 
 func (s *SetApplier) Apply(deployInfo *types.InstallInfo, objects *types.ManifestResources,
 	namespace string,
-) (bool, error) {
+) (_ bool, err error) {
+	_, span := tracing.Start(deployInfo.Ctx, tracing.SpanApply, attribute.Int("resources", len(objects.Items)))
+	defer func() { tracing.End(span, err) }()
+
 	// Populate the namespace on any namespace-scoped objects
-	err := s.adjustNs(objects, namespace)
+	err = s.adjustNs(objects, namespace)
 	if err != nil {
 		return false, err
 	}
@@ -69,7 +75,10 @@ func (s *SetApplier) Apply(deployInfo *types.InstallInfo, objects *types.Manifes
 
 func (s *SetApplier) Delete(deployInfo *types.InstallInfo, objects *types.ManifestResources,
 	namespace string,
-) (bool, error) {
+) (_ bool, err error) {
+	_, span := tracing.Start(deployInfo.Ctx, tracing.SpanDelete, attribute.Int("resources", len(objects.Items)))
+	defer func() { tracing.End(span, err) }()
+
 	// Populate the namespace on any namespace-scoped objects
 	if err := s.adjustNs(objects, namespace); err != nil {
 		return false, err
@@ -79,6 +88,10 @@ func (s *SetApplier) Delete(deployInfo *types.InstallInfo, objects *types.Manife
 	deleteErrors := make([]error, 0)
 
 	for _, obj := range objects.Items {
+		// abort remaining deletions once the reconciliation is cancelled
+		if err := deployInfo.Ctx.Err(); err != nil {
+			return false, err
+		}
 		name := obj.GetName()
 		// get dynamic client interface for object
 		resourceInterface, err := s.clients.DynamicResourceInterface(obj)
@@ -144,9 +157,17 @@ func (s *SetApplier) execute(
 	objects []*unstructured.Unstructured,
 ) ([]*unstructured.Unstructured, error) {
 	appliedObjects := make([]*unstructured.Unstructured, 0)
+	conflictPolicy := types.ConflictPolicyForce
+	if deployInfo.ChartInfo != nil {
+		conflictPolicy = deployInfo.ConflictPolicy
+	}
 
 	applyErrors := make([]error, 0)
 	for _, obj := range objects {
+		// abort remaining patches once the reconciliation is cancelled
+		if err := deployInfo.Ctx.Err(); err != nil {
+			return appliedObjects, err
+		}
 		name := obj.GetName()
 
 		// get dynamic client interface for object
@@ -157,18 +178,29 @@ func (s *SetApplier) execute(
 			continue
 		}
 
-		marshaledObject, err := json.Marshal(obj)
-		if err != nil {
-			applyErrors = append(applyErrors, fmt.Errorf("failed to marshal object to JSON: %w", err))
-			continue
-		}
-
-		obj, err = resourceInterface.Patch(deployInfo.Ctx, name, machineryTypes.ApplyPatchType,
-			marshaledObject, s.patchOptions)
+		applied, err := util.ApplyWithConflictPolicy(obj, conflictPolicy,
+			func(objToApply *unstructured.Unstructured, force bool) error {
+				marshaledObject, err := json.Marshal(objToApply)
+				if err != nil {
+					return fmt.Errorf("failed to marshal object to JSON: %w", err)
+				}
+				patchOptions := s.patchOptions
+				patchOptions.Force = &force
+				appliedObject, err := resourceInterface.Patch(deployInfo.Ctx, name, machineryTypes.ApplyPatchType,
+					marshaledObject, patchOptions)
+				if err == nil {
+					obj = appliedObject
+				}
+				return err
+			})
 		if err != nil {
 			applyErrors = append(applyErrors, fmt.Errorf("error from apply: %w", err))
 			continue
 		}
+		if !applied {
+			s.logger.Info("skipped applying resource with fields owned by another manager",
+				"resource", ctrlclient.ObjectKeyFromObject(obj).String(), "kind", obj.GetKind())
+		}
 		appliedObjects = append(appliedObjects, obj)
 	}
 
@@ -5,6 +5,7 @@ import (
 
 	"github.com/kyma-project/module-manager/pkg/manifest"
 	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
 )
 
 type ManifestClientCache struct {
@@ -28,7 +29,9 @@ func (mc *ManifestClientCache) InvalidateForOwner(key client.ObjectKey) {
 	mc.renderSources.DeleteProcessor(key)
 }
 
-// InvalidateSelf invalidates entries assigned by the resource being processed.
+// InvalidateSelf invalidates entries assigned by the resource being processed,
+// including the compiled expressions of its health rules.
 func (mc *ManifestClientCache) InvalidateSelf(key client.ObjectKey) {
 	mc.renderSources.DeleteConfig(key)
+	util.ForgetHealthRules(key)
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	"helm.sh/helm/v3/pkg/action"
@@ -74,6 +75,11 @@ type SingletonClients struct {
 	// GVK based unstructured Client Cache
 	unstructuredSyncLock        sync.Mutex
 	unstructuredRESTClientCache map[string]resource.RESTClient
+
+	// last known version of the API server, used to detect cluster upgrades
+	serverVersionLock      sync.Mutex
+	serverVersion          string
+	serverVersionCheckedAt time.Time
 }
 
 func NewSingletonClients(info *types.ClusterInfo, logger logr.Logger) (*SingletonClients, error) {
@@ -88,6 +94,8 @@ func NewSingletonClients(info *types.ClusterInfo, logger logr.Logger) (*Singleto
 
 	discoveryConfig := *info.Config
 	discoveryConfig.Burst = 200
+	// discovery is not limited by a rate limiter shared with other clients of the cluster, as it bursts on startup
+	discoveryConfig.RateLimiter = nil
 	discoveryClient, err := discovery.NewDiscoveryClientForConfigAndClient(&discoveryConfig, httpClient)
 	if err != nil {
 		return nil, err
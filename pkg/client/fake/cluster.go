@@ -0,0 +1,229 @@
+// Package fake provides an instrumented fake target cluster, so that readiness checks and drift detection
+// can be unit tested deterministically without envtest.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"helm.sh/helm/v3/pkg/kube"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// Verbs of recorded calls.
+const (
+	VerbGet    = "get"
+	VerbList   = "list"
+	VerbCreate = "create"
+	VerbUpdate = "update"
+	VerbPatch  = "patch"
+	VerbDelete = "delete"
+)
+
+// Call is a call on the Cluster recorded by its verb and the resource it addressed.
+type Call struct {
+	Verb      string
+	Resource  schema.GroupVersionResource
+	Namespace string
+	// Name is empty for lists
+	Name string
+}
+
+func (c Call) String() string {
+	return fmt.Sprintf("%s %s %s/%s", c.Verb, c.Resource.Resource, c.Namespace, c.Name)
+}
+
+// Cluster is a fake target cluster, which serves the same objects to a controller-runtime client and
+// a Kubernetes clientset, as used by Helm to check readiness. All calls of both clients are recorded.
+// The state of objects is scripted with SetState, which sets their status as the controllers of
+// a real cluster would, e.g. ready replicas of a Deployment.
+type Cluster struct {
+	client.Client
+	clientset *k8sfake.Clientset
+	tracker   k8stesting.ObjectTracker
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewCluster returns a Cluster containing the passed objects. Unstructured objects of built-in kinds
+// are stored typed, so that they are served to the Kubernetes clientset as well.
+func NewCluster(objects ...client.Object) (*Cluster, error) {
+	cluster := &Cluster{clientset: k8sfake.NewSimpleClientset()}
+	cluster.tracker = cluster.clientset.Tracker()
+	cluster.clientset.PrependReactor("*", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		call := Call{Verb: action.GetVerb(), Resource: action.GetResource(), Namespace: action.GetNamespace()}
+		if named, ok := action.(interface{ GetName() string }); ok {
+			call.Name = named.GetName()
+		}
+		cluster.record(call)
+		// the call is only recorded and served by the tracker
+		return false, nil, nil
+	})
+	cluster.Client = &recordingClient{
+		WithWatch: crfake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjectTracker(cluster.tracker).Build(),
+		cluster:   cluster,
+	}
+	for _, obj := range objects {
+		if err := cluster.Add(obj); err != nil {
+			return nil, err
+		}
+	}
+	return cluster, nil
+}
+
+// Add adds the object to the Cluster without recording a call.
+func (c *Cluster) Add(obj client.Object) error {
+	typed, err := toTyped(obj)
+	if err != nil {
+		return err
+	}
+	return c.tracker.Add(typed)
+}
+
+// Clientset returns the Kubernetes clientset of the Cluster.
+func (c *Cluster) Clientset() kubernetes.Interface {
+	return c.clientset
+}
+
+// ClusterInfo returns the types.ClusterInfo of the Cluster, which has no REST config.
+func (c *Cluster) ClusterInfo() *types.ClusterInfo {
+	return &types.ClusterInfo{Client: c}
+}
+
+// ReadyChecker returns the Helm ReadyChecker of the Cluster, which also checks Jobs.
+func (c *Cluster) ReadyChecker() kube.ReadyChecker {
+	return kube.NewReadyChecker(c.clientset, func(string, ...interface{}) {}, kube.CheckJobs(true))
+}
+
+// ResourceList returns the resources of the passed objects as checked by Helm.
+func (c *Cluster) ResourceList(objects ...client.Object) (kube.ResourceList, error) {
+	resources := make(kube.ResourceList, 0, len(objects))
+	for _, obj := range objects {
+		typed, err := toTyped(obj)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, &resource.Info{
+			Name: obj.GetName(), Namespace: obj.GetNamespace(), Object: typed,
+		})
+	}
+	return resources, nil
+}
+
+// Calls returns all recorded calls in the order they were made.
+func (c *Cluster) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Call(nil), c.calls...)
+}
+
+// CallsWithVerb returns all recorded calls with the passed verb in the order they were made.
+func (c *Cluster) CallsWithVerb(verb string) []Call {
+	var calls []Call
+	for _, call := range c.Calls() {
+		if call.Verb == verb {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// ResetCalls forgets all recorded calls.
+func (c *Cluster) ResetCalls() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = nil
+}
+
+func (c *Cluster) record(call Call) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, call)
+}
+
+func (c *Cluster) recordObject(verb string, obj runtime.Object, key client.ObjectKey) {
+	gvk, err := apiutil.GVKForObject(obj, scheme.Scheme)
+	if err != nil {
+		gvk = obj.GetObjectKind().GroupVersionKind()
+	}
+	if list, isList := obj.(client.ObjectList); isList && meta.IsListType(list) {
+		gvk.Kind = gvk.Kind[:len(gvk.Kind)-len("List")]
+	}
+	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+	c.record(Call{Verb: verb, Resource: gvr, Namespace: key.Namespace, Name: key.Name})
+}
+
+// toTyped converts unstructured objects of built-in kinds to their typed objects.
+func toTyped(obj client.Object) (client.Object, error) {
+	unstructuredObj, isUnstructured := obj.(*unstructured.Unstructured)
+	if !isUnstructured || !scheme.Scheme.Recognizes(unstructuredObj.GroupVersionKind()) {
+		return obj.DeepCopyObject().(client.Object), nil
+	}
+	typed, err := scheme.Scheme.New(unstructuredObj.GroupVersionKind())
+	if err != nil {
+		return nil, err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredObj.Object, typed); err != nil {
+		return nil, fmt.Errorf("converting %s %s: %w", unstructuredObj.GetKind(), unstructuredObj.GetName(), err)
+	}
+	typedObj, _ := typed.(client.Object)
+	typedObj.GetObjectKind().SetGroupVersionKind(unstructuredObj.GroupVersionKind())
+	return typedObj, nil
+}
+
+// recordingClient records all calls of the controller-runtime client of the Cluster.
+type recordingClient struct {
+	client.WithWatch
+	cluster *Cluster
+}
+
+func (r *recordingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object,
+	opts ...client.GetOption,
+) error {
+	r.cluster.recordObject(VerbGet, obj, key)
+	return r.WithWatch.Get(ctx, key, obj, opts...)
+}
+
+func (r *recordingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOptions := &client.ListOptions{}
+	listOptions.ApplyOptions(opts)
+	r.cluster.recordObject(VerbList, list, client.ObjectKey{Namespace: listOptions.Namespace})
+	return r.WithWatch.List(ctx, list, opts...)
+}
+
+func (r *recordingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	r.cluster.recordObject(VerbCreate, obj, client.ObjectKeyFromObject(obj))
+	return r.WithWatch.Create(ctx, obj, opts...)
+}
+
+func (r *recordingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	r.cluster.recordObject(VerbUpdate, obj, client.ObjectKeyFromObject(obj))
+	return r.WithWatch.Update(ctx, obj, opts...)
+}
+
+func (r *recordingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch,
+	opts ...client.PatchOption,
+) error {
+	r.cluster.recordObject(VerbPatch, obj, client.ObjectKeyFromObject(obj))
+	return r.WithWatch.Patch(ctx, obj, patch, opts...)
+}
+
+func (r *recordingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	r.cluster.recordObject(VerbDelete, obj, client.ObjectKeyFromObject(obj))
+	return r.WithWatch.Delete(ctx, obj, opts...)
+}
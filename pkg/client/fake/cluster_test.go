@@ -0,0 +1,128 @@
+package fake_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/pkg/client/fake"
+	"github.com/kyma-project/module-manager/pkg/custom"
+)
+
+func workloads() []client.Object {
+	labels := map[string]string{"app": "sample"}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "sample", Namespace: metav1.NamespaceDefault},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app:1.0.0"}}},
+			},
+		},
+	}
+	pod := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]any{"name": "sample", "namespace": metav1.NamespaceDefault},
+		"spec":       map[string]any{"containers": []any{map[string]any{"name": "app", "image": "app:1.0.0"}}},
+	}}
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "sample", Namespace: metav1.NamespaceDefault}}
+	return []client.Object{deployment, pod, job}
+}
+
+func Test_Cluster_ReadyStates(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	objects := workloads()
+	cluster, err := fake.NewCluster()
+	require.NoError(t, err)
+	resources, err := cluster.ResourceList(objects...)
+	require.NoError(t, err)
+	readyChecker := cluster.ReadyChecker()
+
+	isReady := func(state fake.ObjectState) []bool {
+		ready := make([]bool, 0, len(objects))
+		for i, obj := range objects {
+			require.NoError(t, cluster.SetState(obj, state))
+			objectReady, err := readyChecker.IsReady(ctx, resources[i])
+			if state == fake.StateMissing {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			ready = append(ready, objectReady)
+		}
+		return ready
+	}
+
+	assert.Equal(t, []bool{false, false, false}, isReady(fake.StateProgressing))
+	assert.Equal(t, []bool{true, true, true}, isReady(fake.StateReady))
+	assert.Equal(t, []bool{false, false, false}, isReady(fake.StateMissing))
+}
+
+func Test_Cluster_CustomResourceStates(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	sample := &unstructured.Unstructured{}
+	sample.SetAPIVersion("operator.kyma-project.io/v1alpha1")
+	sample.SetKind("Sample")
+	sample.SetName("sample")
+	sample.SetNamespace(metav1.NamespaceDefault)
+	cluster, err := fake.NewCluster(sample)
+	require.NoError(t, err)
+	status := &custom.Status{Reader: cluster}
+
+	require.NoError(t, cluster.SetState(sample, fake.StateProgressing))
+	ready, err := status.WaitForCustomResources(ctx, sample)
+	require.NoError(t, err)
+	assert.False(t, ready)
+
+	require.NoError(t, cluster.SetState(sample, fake.StateReady))
+	ready, err = status.WaitForCustomResources(ctx, sample)
+	require.NoError(t, err)
+	assert.True(t, ready)
+
+	require.NoError(t, cluster.SetState(sample, fake.StateMissing))
+	_, err = status.WaitForCustomResources(ctx, sample)
+	require.Error(t, err)
+}
+
+func Test_Cluster_RecordsCalls(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	objects := workloads()
+	cluster, err := fake.NewCluster(objects...)
+	require.NoError(t, err)
+	assert.Empty(t, cluster.Calls())
+
+	deployment := &appsv1.Deployment{}
+	require.NoError(t, cluster.Get(ctx, client.ObjectKeyFromObject(objects[0]), deployment))
+	require.NoError(t, cluster.List(ctx, &corev1.PodList{}, client.InNamespace(metav1.NamespaceDefault)))
+	_, err = cluster.Clientset().BatchV1().Jobs(metav1.NamespaceDefault).Get(ctx, "sample", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NoError(t, cluster.Delete(ctx, deployment))
+
+	calls := make([]string, 0, len(cluster.Calls()))
+	for _, call := range cluster.Calls() {
+		calls = append(calls, call.String())
+	}
+	assert.Equal(t, []string{
+		"get deployments default/sample",
+		"list pods default/",
+		"get jobs default/sample",
+		"delete deployments default/sample",
+	}, calls)
+	assert.Len(t, cluster.CallsWithVerb(fake.VerbGet), 2)
+
+	cluster.ResetCalls()
+	assert.Empty(t, cluster.Calls())
+}
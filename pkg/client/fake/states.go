@@ -0,0 +1,188 @@
+package fake
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// ObjectState is the scripted state of an object in a Cluster.
+type ObjectState string
+
+const (
+	// StateReady marks an object as ready, e.g. all replicas of a Deployment are ready.
+	StateReady ObjectState = "Ready"
+	// StateProgressing marks an object as existing, but not ready yet, e.g. a Pod is pending.
+	StateProgressing ObjectState = "Progressing"
+	// StateMissing removes an object from the cluster.
+	StateMissing ObjectState = "Missing"
+
+	// customStateReady and customStateProcessing are the states of .status.state of custom resources.
+	customStateReady      = "Ready"
+	customStateProcessing = "Processing"
+)
+
+// SetState sets the status of the object in the Cluster to the passed state, without recording a call.
+// The object is added first, if it is not part of the Cluster yet. Objects of kinds without a readiness,
+// e.g. ConfigMaps, are only added or removed. Custom resources get the state in .status.state.
+func (c *Cluster) SetState(obj client.Object, state ObjectState) error {
+	gvk, err := apiutil.GVKForObject(obj, scheme.Scheme)
+	if err != nil {
+		return err
+	}
+	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+
+	if state == StateMissing {
+		err := c.tracker.Delete(gvr, obj.GetNamespace(), obj.GetName())
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	current, err := c.tracker.Get(gvr, obj.GetNamespace(), obj.GetName())
+	if apierrors.IsNotFound(err) {
+		if err := c.Add(obj); err != nil {
+			return err
+		}
+		current, err = c.tracker.Get(gvr, obj.GetNamespace(), obj.GetName())
+	}
+	if err != nil {
+		return err
+	}
+	current = current.DeepCopyObject()
+
+	ready := state == StateReady
+	switch typed := current.(type) {
+	case *corev1.Pod:
+		setPodState(typed, ready)
+	case *corev1.PersistentVolumeClaim:
+		typed.Status.Phase = corev1.ClaimPending
+		if ready {
+			typed.Status.Phase = corev1.ClaimBound
+		}
+	case *appsv1.Deployment:
+		if err := c.setDeploymentState(typed, ready); err != nil {
+			return err
+		}
+	case *appsv1.StatefulSet:
+		setStatefulSetState(typed, ready)
+	case *appsv1.DaemonSet:
+		typed.Status.DesiredNumberScheduled = 1
+		typed.Status.UpdatedNumberScheduled, typed.Status.NumberReady = 0, 0
+		if ready {
+			typed.Status.UpdatedNumberScheduled, typed.Status.NumberReady = 1, 1
+		}
+	case *batchv1.Job:
+		setJobState(typed, ready)
+	case *unstructured.Unstructured:
+		customState := customStateProcessing
+		if ready {
+			customState = customStateReady
+		}
+		if err := unstructured.SetNestedField(typed.Object, customState, "status", "state"); err != nil {
+			return err
+		}
+	}
+	return c.tracker.Update(gvr, current, obj.GetNamespace())
+}
+
+func setPodState(pod *corev1.Pod, ready bool) {
+	pod.Status.Phase = corev1.PodPending
+	condition := corev1.ConditionFalse
+	if ready {
+		pod.Status.Phase = corev1.PodRunning
+		condition = corev1.ConditionTrue
+	}
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: condition}}
+}
+
+func setStatefulSetState(sts *appsv1.StatefulSet, ready bool) {
+	replicas := defaultReplicas(&sts.Spec.Replicas)
+	if sts.Spec.UpdateStrategy.Type == "" {
+		sts.Spec.UpdateStrategy.Type = appsv1.RollingUpdateStatefulSetStrategyType
+	}
+	sts.Status.ObservedGeneration = sts.Generation
+	sts.Status.Replicas = replicas
+	sts.Status.UpdatedReplicas, sts.Status.ReadyReplicas, sts.Status.CurrentReplicas = 0, 0, 0
+	if ready {
+		sts.Status.UpdatedReplicas, sts.Status.ReadyReplicas, sts.Status.CurrentReplicas = replicas, replicas, replicas
+	}
+}
+
+func setJobState(job *batchv1.Job, ready bool) {
+	completions := defaultReplicas(&job.Spec.Completions)
+	if job.Spec.BackoffLimit == nil {
+		backoffLimit := int32(6)
+		job.Spec.BackoffLimit = &backoffLimit
+	}
+	job.Status.Active, job.Status.Succeeded = 1, 0
+	if ready {
+		job.Status.Active, job.Status.Succeeded = 0, completions
+	}
+}
+
+// setDeploymentState sets the status of the Deployment and of its current ReplicaSet,
+// which is used by Helm to determine the ready replicas.
+func (c *Cluster) setDeploymentState(deployment *appsv1.Deployment, ready bool) error {
+	replicas := defaultReplicas(&deployment.Spec.Replicas)
+	if deployment.UID == "" {
+		deployment.UID = k8stypes.UID(deployment.Namespace + "/" + deployment.Name)
+	}
+	var readyReplicas int32
+	if ready {
+		readyReplicas = replicas
+	}
+	deployment.Status = appsv1.DeploymentStatus{
+		ObservedGeneration: deployment.Generation,
+		Replicas:           replicas,
+		UpdatedReplicas:    replicas,
+		ReadyReplicas:      readyReplicas,
+		AvailableReplicas:  readyReplicas,
+	}
+
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deployment.Name + "-replicas",
+			Namespace: deployment.Namespace,
+			Labels:    deployment.Spec.Template.Labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(deployment, appsv1.SchemeGroupVersion.WithKind("Deployment")),
+			},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Replicas: &replicas,
+			Selector: deployment.Spec.Selector,
+			Template: deployment.Spec.Template,
+		},
+		Status: appsv1.ReplicaSetStatus{
+			Replicas: replicas, ReadyReplicas: readyReplicas, AvailableReplicas: readyReplicas,
+		},
+	}
+	gvr := appsv1.SchemeGroupVersion.WithResource("replicasets")
+	if _, err := c.tracker.Get(gvr, replicaSet.Namespace, replicaSet.Name); apierrors.IsNotFound(err) {
+		return c.tracker.Add(replicaSet)
+	} else if err != nil {
+		return fmt.Errorf("setting state of ReplicaSet of Deployment %s: %w", deployment.Name, err)
+	}
+	return c.tracker.Update(gvr, replicaSet, replicaSet.Namespace)
+}
+
+// defaultReplicas defaults the replicas to 1, as the API server does.
+func defaultReplicas(replicas **int32) int32 {
+	if *replicas == nil {
+		one := int32(1)
+		*replicas = &one
+	}
+	return **replicas
+}
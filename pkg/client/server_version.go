@@ -0,0 +1,53 @@
+package client
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// ServerVersion returns the version of the API server, which is never cached.
+func (s *SingletonClients) ServerVersion() (*version.Info, error) {
+	return s.discoveryClient.ServerVersion()
+}
+
+// InvalidateOnServerVersionChange pings the version of the API server, unless it was already checked within the
+// passed interval. If the version changed since the last check, e.g. after an upgrade of the cluster,
+// the cached discovery data, the RESTMapper and the cached REST clients are invalidated,
+// so that API groups and versions added or removed by the upgrade are discovered again.
+// It returns true if the caches were invalidated.
+func (s *SingletonClients) InvalidateOnServerVersionChange(interval time.Duration) (bool, error) {
+	s.serverVersionLock.Lock()
+	defer s.serverVersionLock.Unlock()
+
+	if !s.serverVersionCheckedAt.IsZero() && time.Since(s.serverVersionCheckedAt) < interval {
+		return false, nil
+	}
+	serverVersion, err := s.ServerVersion()
+	if err != nil {
+		return false, err
+	}
+	s.serverVersionCheckedAt = time.Now()
+	previous := s.serverVersion
+	s.serverVersion = serverVersion.GitVersion
+	if previous == "" || previous == s.serverVersion {
+		return false, nil
+	}
+	s.invalidateDiscovery()
+	return true, nil
+}
+
+func (s *SingletonClients) invalidateDiscovery() {
+	s.discoveryClient.Invalidate()
+	meta.MaybeResetRESTMapper(s.discoveryShortcutExpander)
+
+	s.structuredSyncLock.Lock()
+	s.structuredRESTClientCache = map[string]resource.RESTClient{}
+	s.structuredSyncLock.Unlock()
+
+	s.unstructuredSyncLock.Lock()
+	s.unstructuredRESTClientCache = map[string]resource.RESTClient{}
+	s.unstructuredSyncLock.Unlock()
+}
@@ -0,0 +1,58 @@
+package client_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/rest"
+
+	manifestClient "github.com/kyma-project/module-manager/pkg/client"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+func Test_InvalidateOnServerVersionChange(t *testing.T) {
+	t.Parallel()
+	var serverVersion atomic.Value
+	serverVersion.Store("v1.25.4")
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/version" {
+			http.NotFound(writer, request)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(writer).Encode(version.Info{GitVersion: serverVersion.Load().(string)})
+	}))
+	t.Cleanup(server.Close)
+
+	clients, err := manifestClient.NewSingletonClients(&types.ClusterInfo{
+		Config: &rest.Config{Host: server.URL},
+	}, logr.Discard())
+	require.NoError(t, err)
+
+	// the first check only records the version
+	invalidated, err := clients.InvalidateOnServerVersionChange(0)
+	require.NoError(t, err)
+	assert.False(t, invalidated)
+
+	serverVersion.Store("v1.26.0")
+	// the version is not checked again within the interval
+	invalidated, err = clients.InvalidateOnServerVersionChange(time.Hour)
+	require.NoError(t, err)
+	assert.False(t, invalidated)
+
+	invalidated, err = clients.InvalidateOnServerVersionChange(0)
+	require.NoError(t, err)
+	assert.True(t, invalidated)
+
+	invalidated, err = clients.InvalidateOnServerVersionChange(0)
+	require.NoError(t, err)
+	assert.False(t, invalidated)
+}
@@ -2,23 +2,35 @@ package custom
 
 import (
 	"context"
+	"fmt"
 
-	"k8s.io/apimachinery/pkg/util/validation/field"
-
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
 )
 
 type Status struct {
 	client.Reader
+	// Paths locate the state of the custom resources, the state is read from `.status.state` by default
+	Paths types.StatusPaths
 }
 
 // TODO: Define centrally.
 const readyState = "Ready"
 
+const (
+	defaultStatePath      = ".status.state"
+	defaultConditionsPath = ".status.conditions"
+)
+
 func (c *Status) WaitForCustomResources(ctx context.Context, stateResource *unstructured.Unstructured,
 ) (bool, error) {
-	// unstructured resource containing .status.state
+	// unstructured resource containing the state at the configured path
 	if stateResource != nil {
 		existingStateResource := &unstructured.Unstructured{}
 		existingStateResource.SetGroupVersionKind(stateResource.GroupVersionKind())
@@ -32,7 +44,7 @@ func (c *Status) WaitForCustomResources(ctx context.Context, stateResource *unst
 			return false, err
 		}
 
-		state, err := getStateFieldFromUnstructured(existingStateResource)
+		state, err := ResourceStatus{Paths: c.Paths}.GetState(existingStateResource)
 		if err != nil {
 			return false, err
 		}
@@ -45,17 +57,82 @@ func (c *Status) WaitForCustomResources(ctx context.Context, stateResource *unst
 	return true, nil
 }
 
-func getStateFieldFromUnstructured(resource *unstructured.Unstructured) (string, error) {
-	path := field.NewPath("status")
-	status, statusExists := resource.Object["status"]
-	if !statusExists {
-		return "", field.NotFound(path, resource.Object)
+// ResourceStatus reads and writes the state and conditions of unstructured custom resources at the passed Paths,
+// paths which are not set default to `.status.state` and `.status.conditions`.
+type ResourceStatus struct {
+	Paths types.StatusPaths
+}
+
+// GetState returns the state of the resource, a missing state is returned as field.ErrorTypeNotFound.
+func (s ResourceStatus) GetState(resource *unstructured.Unstructured) (string, error) {
+	fields, err := fieldsOf(s.Paths.State, defaultStatePath)
+	if err != nil {
+		return "", err
+	}
+	state, found, err := unstructured.NestedString(resource.Object, fields...)
+	if err != nil {
+		return "", fmt.Errorf("reading state of %s: %w", client.ObjectKeyFromObject(resource), err)
 	}
+	if !found {
+		return "", field.NotFound(field.NewPath(fields[0], fields[1:]...), resource.Object)
+	}
+	return state, nil
+}
+
+// SetState sets the state of the resource.
+func (s ResourceStatus) SetState(resource *unstructured.Unstructured, state string) error {
+	fields, err := fieldsOf(s.Paths.State, defaultStatePath)
+	if err != nil {
+		return err
+	}
+	return unstructured.SetNestedField(resource.Object, state, fields...)
+}
+
+// GetConditions returns the conditions of the resource, or no conditions if none are set.
+func (s ResourceStatus) GetConditions(resource *unstructured.Unstructured) ([]metav1.Condition, error) {
+	fields, err := fieldsOf(s.Paths.Conditions, defaultConditionsPath)
+	if err != nil {
+		return nil, err
+	}
+	items, _, err := unstructured.NestedSlice(resource.Object, fields...)
+	if err != nil {
+		return nil, fmt.Errorf("reading conditions of %s: %w", client.ObjectKeyFromObject(resource), err)
+	}
+	conditions := make([]metav1.Condition, 0, len(items))
+	for _, item := range items {
+		content, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("condition of %s is no object", client.ObjectKeyFromObject(resource))
+		}
+		var condition metav1.Condition
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &condition); err != nil {
+			return nil, fmt.Errorf("reading conditions of %s: %w", client.ObjectKeyFromObject(resource), err)
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// SetConditions replaces the conditions of the resource.
+func (s ResourceStatus) SetConditions(resource *unstructured.Unstructured, conditions []metav1.Condition) error {
+	fields, err := fieldsOf(s.Paths.Conditions, defaultConditionsPath)
+	if err != nil {
+		return err
+	}
+	items := make([]interface{}, 0, len(conditions))
+	for i := range conditions {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&conditions[i])
+		if err != nil {
+			return err
+		}
+		items = append(items, content)
+	}
+	return unstructured.SetNestedSlice(resource.Object, items, fields...)
+}
 
-	path = path.Child("state")
-	state, stateExists := status.(map[string]interface{})["state"]
-	if !stateExists {
-		return "", field.NotFound(path, resource.Object)
+func fieldsOf(path string, defaultPath string) ([]string, error) {
+	if path == "" {
+		path = defaultPath
 	}
-	return state.(string), nil
+	return util.ParseFieldPath(path)
 }
@@ -0,0 +1,51 @@
+package declarative
+
+import (
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// consistencyCheckIntervalDefault is the interval in which Ready objects with an unchanged generation are verified.
+const consistencyCheckIntervalDefault = time.Minute
+
+// ConsistencyChecks limits the consistency checks of Ready objects, whose generation did not change since they were
+// processed, to one per interval, so that status-only updates and resyncs do not verify all resources again.
+type ConsistencyChecks struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	last map[client.ObjectKey]time.Time
+}
+
+// NewConsistencyChecks returns ConsistencyChecks running at most one check per object in the passed interval.
+// A non-positive interval runs a check on every reconcile.
+func NewConsistencyChecks(interval time.Duration) *ConsistencyChecks {
+	return &ConsistencyChecks{interval: interval, last: make(map[client.ObjectKey]time.Time)}
+}
+
+// Due indicates if a consistency check of the object is due and records it as checked.
+// Otherwise, the duration until the next check is due is returned.
+func (c *ConsistencyChecks) Due(key client.ObjectKey) (bool, time.Duration) {
+	if c.interval <= 0 {
+		return true, 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if last, found := c.last[key]; found {
+		if wait := c.interval - now.Sub(last); wait > 0 {
+			return false, wait
+		}
+	}
+	c.last[key] = now
+	return true, 0
+}
+
+// Forget drops the recorded check of the object, e.g. once it was deleted.
+func (c *ConsistencyChecks) Forget(key client.ObjectKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.last, key)
+}
@@ -0,0 +1,40 @@
+package declarative_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/pkg/declarative"
+)
+
+func TestConsistencyChecksDue(t *testing.T) {
+	t.Parallel()
+	checks := declarative.NewConsistencyChecks(time.Hour)
+	key := client.ObjectKey{Namespace: "default", Name: "sample"}
+
+	due, _ := checks.Due(key)
+	assert.True(t, due, "the first check is always due")
+	due, wait := checks.Due(key)
+	assert.False(t, due)
+	assert.InDelta(t, time.Hour, wait, float64(time.Second))
+	due, _ = checks.Due(client.ObjectKey{Namespace: "default", Name: "other"})
+	assert.True(t, due, "checks are tracked per object")
+
+	checks.Forget(key)
+	due, _ = checks.Due(key)
+	assert.True(t, due, "forgotten objects are checked again")
+}
+
+func TestConsistencyChecksWithoutInterval(t *testing.T) {
+	t.Parallel()
+	checks := declarative.NewConsistencyChecks(0)
+	key := client.ObjectKey{Namespace: "default", Name: "sample"}
+	for i := 0; i < 2; i++ {
+		due, wait := checks.Due(key)
+		assert.True(t, due)
+		assert.Zero(t, wait)
+	}
+}
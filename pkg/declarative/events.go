@@ -0,0 +1,96 @@
+package declarative
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// eventDeduplicationWindow is the duration in which repeated identical events for an object are suppressed.
+	eventDeduplicationWindow = 5 * time.Minute
+
+	EventReasonStateChanged           = "StateChanged"
+	EventReasonInstallFailed          = "InstallFailed"
+	EventReasonUninstallFailed        = "UninstallFailed"
+	EventReasonConsistencyCheckFailed = "ConsistencyCheckFailed"
+	EventReasonReadinessTimeout       = "ReadinessTimeout"
+	EventReasonFinalizerRemoved       = "FinalizerRemoved"
+)
+
+type eventKey struct {
+	uid                        k8stypes.UID
+	eventType, reason, message string
+}
+
+// deduplicatingRecorder is a record.EventRecorder suppressing an event if the same event was recorded
+// for the same object within the deduplication window, e.g. for a failure repeated on every requeue.
+type deduplicatingRecorder struct {
+	record.EventRecorder
+	window time.Duration
+
+	mu       sync.Mutex
+	recorded map[eventKey]time.Time
+}
+
+func newDeduplicatingRecorder(recorder record.EventRecorder, window time.Duration) *deduplicatingRecorder {
+	return &deduplicatingRecorder{
+		EventRecorder: recorder,
+		window:        window,
+		recorded:      make(map[eventKey]time.Time),
+	}
+}
+
+func (r *deduplicatingRecorder) Event(object runtime.Object, eventType, reason, message string) {
+	if r.isDuplicate(object, eventType, reason, message) {
+		return
+	}
+	r.EventRecorder.Event(object, eventType, reason, message)
+}
+
+func (r *deduplicatingRecorder) Eventf(object runtime.Object, eventType, reason, messageFmt string,
+	args ...interface{},
+) {
+	r.Event(object, eventType, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// Forget drops the recorded events of the object, e.g. once it is deleted.
+func (r *deduplicatingRecorder) Forget(object runtime.Object) {
+	metaObject, err := meta.Accessor(object)
+	if err != nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key := range r.recorded {
+		if key.uid == metaObject.GetUID() {
+			delete(r.recorded, key)
+		}
+	}
+}
+
+func (r *deduplicatingRecorder) isDuplicate(object runtime.Object, eventType, reason, message string) bool {
+	metaObject, err := meta.Accessor(object)
+	if err != nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for key, recorded := range r.recorded {
+		if now.Sub(recorded) >= r.window {
+			delete(r.recorded, key)
+		}
+	}
+	key := eventKey{uid: metaObject.GetUID(), eventType: eventType, reason: reason, message: message}
+	if _, found := r.recorded[key]; found {
+		return true
+	}
+	r.recorded[key] = now
+	return false
+}
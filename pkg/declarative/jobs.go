@@ -0,0 +1,101 @@
+package declarative
+
+import (
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	JobOperationInstall   = "install"
+	JobOperationUninstall = "uninstall"
+)
+
+// JobResult is the outcome of a finished job.
+type JobResult struct {
+	Ready bool
+	Err   error
+}
+
+type job struct {
+	operation  string
+	generation int64
+	done       chan struct{}
+	result     JobResult
+}
+
+func (j *job) isDone() bool {
+	select {
+	case <-j.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// JobPool runs long-running installations and uninstallations in the background, limited to a number of
+// concurrent workers, so that reconciles return immediately instead of blocking the worker of the controller.
+// At most one job is tracked per object, its result is polled by subsequent reconciles.
+type JobPool struct {
+	workers chan struct{}
+
+	mu   sync.Mutex
+	jobs map[client.ObjectKey]*job
+}
+
+// NewJobPool returns a JobPool running at most the passed number of jobs concurrently, at least one.
+func NewJobPool(workers int) *JobPool {
+	if workers < 1 {
+		workers = 1
+	}
+	return &JobPool{
+		workers: make(chan struct{}, workers),
+		jobs:    make(map[client.ObjectKey]*job),
+	}
+}
+
+// Run returns the result of the finished job of the object for the operation and generation of the object,
+// and finished set to true. Otherwise, it starts a job running fn for the object, unless one is in progress
+// already, and returns finished set to false. Results of jobs for other operations, e.g. an install outdated
+// by a deletion, and for other generations, e.g. an install of a spec changed while it ran, are dropped.
+func (p *JobPool) Run(key client.ObjectKey, operation string, generation int64,
+	fn func() (bool, error),
+) (JobResult, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if existing, found := p.jobs[key]; found {
+		if !existing.isDone() {
+			return JobResult{}, false
+		}
+		delete(p.jobs, key)
+		if existing.operation == operation && existing.generation == generation {
+			return existing.result, true
+		}
+	}
+
+	started := &job{operation: operation, generation: generation, done: make(chan struct{})}
+	p.jobs[key] = started
+	go func() {
+		p.workers <- struct{}{}
+		defer func() { <-p.workers }()
+		defer close(started.done)
+		ready, err := fn()
+		started.result = JobResult{Ready: ready, Err: err}
+	}()
+	return JobResult{}, false
+}
+
+// Pending indicates if a job of the object is queued or running.
+func (p *JobPool) Pending(key client.ObjectKey) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	existing, found := p.jobs[key]
+	return found && !existing.isDone()
+}
+
+// Forget drops the job of the object, e.g. once it is deleted. A running job finishes regardless.
+func (p *JobPool) Forget(key client.ObjectKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.jobs, key)
+}
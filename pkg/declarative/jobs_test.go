@@ -0,0 +1,125 @@
+package declarative_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/pkg/declarative"
+)
+
+func awaitJob(t *testing.T, pool *declarative.JobPool, key client.ObjectKey) {
+	t.Helper()
+	require.Eventually(t, func() bool { return !pool.Pending(key) }, time.Second, time.Millisecond)
+}
+
+func TestJobPoolRun(t *testing.T) {
+	t.Parallel()
+	pool := declarative.NewJobPool(1)
+	key := client.ObjectKey{Namespace: "default", Name: "sample"}
+	release := make(chan struct{})
+	var runs int32
+	install := func() (bool, error) {
+		atomic.AddInt32(&runs, 1)
+		<-release
+		return true, nil
+	}
+
+	_, finished := pool.Run(key, declarative.JobOperationInstall, 1, install)
+	assert.False(t, finished)
+	assert.True(t, pool.Pending(key))
+	_, finished = pool.Run(key, declarative.JobOperationInstall, 1, install)
+	assert.False(t, finished, "jobs in progress are not started again")
+
+	close(release)
+	awaitJob(t, pool, key)
+	result, finished := pool.Run(key, declarative.JobOperationInstall, 1, install)
+	assert.True(t, finished)
+	assert.Equal(t, declarative.JobResult{Ready: true}, result)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+}
+
+func TestJobPoolRunDropsResultsOfOtherOperations(t *testing.T) {
+	t.Parallel()
+	pool := declarative.NewJobPool(1)
+	key := client.ObjectKey{Namespace: "default", Name: "sample"}
+	uninstallErr := errors.New("uninstall failed")
+
+	pool.Run(key, declarative.JobOperationInstall, 1, func() (bool, error) { return true, nil })
+	awaitJob(t, pool, key)
+
+	_, finished := pool.Run(key, declarative.JobOperationUninstall, 1, func() (bool, error) { return false, uninstallErr })
+	assert.False(t, finished, "the install result is dropped and the uninstall started")
+	awaitJob(t, pool, key)
+	result, finished := pool.Run(key, declarative.JobOperationUninstall, 1, nil)
+	assert.True(t, finished)
+	assert.ErrorIs(t, result.Err, uninstallErr)
+}
+
+func TestJobPoolRunDropsResultsOfOtherGenerations(t *testing.T) {
+	t.Parallel()
+	pool := declarative.NewJobPool(1)
+	key := client.ObjectKey{Namespace: "default", Name: "sample"}
+	release := make(chan struct{})
+	var installedGenerations []int64
+	install := func(generation int64) func() (bool, error) {
+		return func() (bool, error) {
+			<-release
+			installedGenerations = append(installedGenerations, generation)
+			return true, nil
+		}
+	}
+
+	pool.Run(key, declarative.JobOperationInstall, 1, install(1))
+	_, finished := pool.Run(key, declarative.JobOperationInstall, 2, install(2))
+	assert.False(t, finished, "the spec changed while the job is in progress")
+	close(release)
+	awaitJob(t, pool, key)
+
+	_, finished = pool.Run(key, declarative.JobOperationInstall, 2, install(2))
+	assert.False(t, finished, "the result of the outdated generation is dropped and the current one installed")
+	awaitJob(t, pool, key)
+	result, finished := pool.Run(key, declarative.JobOperationInstall, 2, nil)
+	assert.True(t, finished)
+	assert.Equal(t, declarative.JobResult{Ready: true}, result)
+	assert.Equal(t, []int64{1, 2}, installedGenerations)
+}
+
+func TestJobPoolLimitsWorkers(t *testing.T) {
+	t.Parallel()
+	pool := declarative.NewJobPool(2)
+	release := make(chan struct{})
+	var running, maxRunning int32
+	run := func() (bool, error) {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			observed := atomic.LoadInt32(&maxRunning)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+				break
+			}
+		}
+		<-release
+		atomic.AddInt32(&running, -1)
+		return true, nil
+	}
+
+	keys := []client.ObjectKey{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}}
+	for _, key := range keys {
+		pool.Run(key, declarative.JobOperationInstall, 1, run)
+	}
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&running) == 2 }, time.Second, time.Millisecond)
+	close(release)
+	for _, key := range keys {
+		awaitJob(t, pool, key)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maxRunning))
+
+	pool.Forget(keys[0])
+	_, finished := pool.Run(keys[0], declarative.JobOperationInstall, 1, run)
+	assert.False(t, finished, "results of forgotten jobs are dropped")
+}
@@ -0,0 +1,158 @@
+package declarative
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/uuid"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+const (
+	// defaultNotificationTimeout limits the delivery of a single notification.
+	defaultNotificationTimeout = 5 * time.Second
+
+	// TransitionEventType is the type of CloudEvents sent for state transitions.
+	TransitionEventType    = "io.kyma-project.module.state-transition"
+	cloudEventsSpecVersion = "1.0"
+	cloudEventsContentType = "application/cloudevents+json"
+)
+
+// Transition is the payload sent for a state transition of a custom resource.
+type Transition struct {
+	APIVersion string      `json:"apiVersion"`
+	Kind       string      `json:"kind"`
+	Namespace  string      `json:"namespace,omitempty"`
+	Name       string      `json:"name"`
+	UID        string      `json:"uid"`
+	Generation int64       `json:"generation"`
+	OldState   types.State `json:"oldState"`
+	NewState   types.State `json:"newState"`
+	Error      string      `json:"error,omitempty"`
+	Time       time.Time   `json:"time"`
+}
+
+func newTransition(obj types.BaseCustomObject, oldState, newState types.State, err error) Transition {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	transition := Transition{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		UID:        string(obj.GetUID()),
+		Generation: obj.GetGeneration(),
+		OldState:   oldState,
+		NewState:   newState,
+		Time:       time.Now().UTC(),
+	}
+	if err != nil {
+		transition.Error = err.Error()
+	}
+	return transition
+}
+
+// WebhookNotifier posts every Transition as JSON to URL.
+// Failed deliveries are logged and not retried, so that reconciliations are not blocked by the receiver.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+	// Timeout limits each delivery, 5 seconds if not set
+	Timeout time.Duration
+	Logger  logr.Logger
+}
+
+var _ types.TransitionNotifier = &WebhookNotifier{}
+
+// OnTransition implements types.TransitionNotifier.
+func (n *WebhookNotifier) OnTransition(obj types.BaseCustomObject, oldState, newState types.State, err error) {
+	body, marshalErr := json.Marshal(newTransition(obj, oldState, newState, err))
+	if marshalErr != nil {
+		n.Logger.Error(marshalErr, "encoding state transition")
+		return
+	}
+	if postErr := post(n.Client, n.Timeout, n.URL, "application/json", body); postErr != nil {
+		n.Logger.Error(postErr, "notifying webhook of state transition", "url", n.URL)
+	}
+}
+
+// CloudEventsNotifier sends every Transition as data of a CloudEvent in structured JSON mode to URL,
+// with TransitionEventType as type and the namespace and name of the custom resource as subject.
+type CloudEventsNotifier struct {
+	URL string
+	// Source identifies the sender of the events, e.g. the name of the operator
+	Source string
+	Client *http.Client
+	// Timeout limits each delivery, 5 seconds if not set
+	Timeout time.Duration
+	Logger  logr.Logger
+}
+
+var _ types.TransitionNotifier = &CloudEventsNotifier{}
+
+type cloudEvent struct {
+	SpecVersion     string     `json:"specversion"`
+	ID              string     `json:"id"`
+	Source          string     `json:"source"`
+	Type            string     `json:"type"`
+	Subject         string     `json:"subject"`
+	Time            time.Time  `json:"time"`
+	DataContentType string     `json:"datacontenttype"`
+	Data            Transition `json:"data"`
+}
+
+// OnTransition implements types.TransitionNotifier.
+func (n *CloudEventsNotifier) OnTransition(obj types.BaseCustomObject, oldState, newState types.State, err error) {
+	transition := newTransition(obj, oldState, newState, err)
+	subject := transition.Name
+	if transition.Namespace != "" {
+		subject = transition.Namespace + "/" + transition.Name
+	}
+	body, marshalErr := json.Marshal(cloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		ID:              string(uuid.NewUUID()),
+		Source:          n.Source,
+		Type:            TransitionEventType,
+		Subject:         subject,
+		Time:            transition.Time,
+		DataContentType: "application/json",
+		Data:            transition,
+	})
+	if marshalErr != nil {
+		n.Logger.Error(marshalErr, "encoding state transition")
+		return
+	}
+	if postErr := post(n.Client, n.Timeout, n.URL, cloudEventsContentType, body); postErr != nil {
+		n.Logger.Error(postErr, "sending state transition event", "url", n.URL)
+	}
+}
+
+func post(client *http.Client, timeout time.Duration, url, contentType string, body []byte) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if timeout <= 0 {
+		timeout = defaultNotificationTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package declarative_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kyma-project/module-manager/pkg/declarative"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+func newTransitionObject() *TestCRD {
+	obj := &TestCRD{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "operator.kyma-project.io", Version: "v1alpha1",
+		Kind: "Sample"})
+	obj.SetNamespace("kyma-system")
+	obj.SetName("sample")
+	obj.SetUID("3f1c0c2e-8d2a-4b4e-9a57-5d1b2c3d4e5f")
+	return obj
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	t.Parallel()
+	received := make(chan declarative.Transition, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		var transition declarative.Transition
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&transition))
+		received <- transition
+	}))
+	defer server.Close()
+
+	notifier := &declarative.WebhookNotifier{URL: server.URL, Logger: logr.Discard()}
+	notifier.OnTransition(newTransitionObject(), types.StateProcessing, types.StateError, errors.New("install failed"))
+
+	transition := <-received
+	assert.Equal(t, "operator.kyma-project.io/v1alpha1", transition.APIVersion)
+	assert.Equal(t, "Sample", transition.Kind)
+	assert.Equal(t, "kyma-system", transition.Namespace)
+	assert.Equal(t, "sample", transition.Name)
+	assert.Equal(t, types.StateProcessing, transition.OldState)
+	assert.Equal(t, types.StateError, transition.NewState)
+	assert.Equal(t, "install failed", transition.Error)
+}
+
+func TestCloudEventsNotifier(t *testing.T) {
+	t.Parallel()
+	received := make(chan map[string]interface{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "application/cloudevents+json", req.Header.Get("Content-Type"))
+		event := map[string]interface{}{}
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&event))
+		received <- event
+	}))
+	defer server.Close()
+
+	notifier := &declarative.CloudEventsNotifier{URL: server.URL, Source: "template-operator", Logger: logr.Discard()}
+	notifier.OnTransition(newTransitionObject(), types.StateProcessing, types.StateReady, nil)
+
+	event := <-received
+	assert.Equal(t, "1.0", event["specversion"])
+	assert.Equal(t, declarative.TransitionEventType, event["type"])
+	assert.Equal(t, "template-operator", event["source"])
+	assert.Equal(t, "kyma-system/sample", event["subject"])
+	assert.NotEmpty(t, event["id"])
+	data, ok := event["data"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, string(types.StateReady), data["newState"])
+	assert.NotContains(t, data, "error")
+}
@@ -1,6 +1,10 @@
 package declarative
 
-import "github.com/kyma-project/module-manager/pkg/types"
+import (
+	"time"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
 
 // WithCustomResourceLabels adds the specified labels to the list of labels for the reconciled resource.
 func WithCustomResourceLabels(labels map[string]string) ReconcilerOption {
@@ -13,6 +17,26 @@ func WithCustomResourceLabels(labels map[string]string) ReconcilerOption {
 	}
 }
 
+// WithTrackingMetadata labels every rendered resource as managed by the operator, owned by the reconciled resource
+// and part of the module and application of the passed metadata, extended by its labels and annotations.
+// Contrary to WithCustomResourceLabels, which only labels the reconciled resource, the labels are set by an
+// ObjectTransform running after all post-render transforms, so that they cannot be overridden.
+func WithTrackingMetadata(metadata types.TrackingMetadata) ReconcilerOption {
+	return func(allOptions manifestOptions) manifestOptions {
+		allOptions.tracking = &metadata
+		return allOptions
+	}
+}
+
+// WithImagePullSecret adds the passed secret to the image pull secrets of all rendered ServiceAccounts and
+// workloads, rendering it alongside them if it has an inline docker config, e.g. for private registry mirrors.
+func WithImagePullSecret(secret types.ImagePullSecret) ReconcilerOption {
+	return func(allOptions manifestOptions) manifestOptions {
+		allOptions.imagePullSecret = &secret
+		return allOptions
+	}
+}
+
 // WithPostRenderTransform adds the specified ObjectTransforms to the list of manifest resource changes.
 func WithPostRenderTransform(operations ...types.ObjectTransform) ReconcilerOption {
 	return func(allOptions manifestOptions) manifestOptions {
@@ -38,6 +62,15 @@ func WithDefaultResolver() ReconcilerOption {
 	}
 }
 
+// WithSpecPaths resolves manifest object using the default resolver, with the fields of the InstallationSpec
+// resolved by the passed JSONPath expressions, e.g. for custom resources with a custom spec shape.
+func WithSpecPaths(paths SpecPaths) ReconcilerOption {
+	return func(allOptions manifestOptions) manifestOptions {
+		allOptions.manifestResolver = DefaultManifestResolver{Paths: paths}
+		return allOptions
+	}
+}
+
 // WithResourcesReady verifies if native resources are in their respective ready states.
 func WithResourcesReady(verify bool) ReconcilerOption {
 	return func(allOptions manifestOptions) manifestOptions {
@@ -46,6 +79,15 @@ func WithResourcesReady(verify bool) ReconcilerOption {
 	}
 }
 
+// WithConflictPolicy determines how field ownership conflicts are resolved on server-side apply for all
+// installations, which do not set their own types.ConflictPolicy. The ownership is forced by default.
+func WithConflictPolicy(policy types.ConflictPolicy) ReconcilerOption {
+	return func(allOptions manifestOptions) manifestOptions {
+		allOptions.conflictPolicy = policy
+		return allOptions
+	}
+}
+
 // WithFinalizer adds a finalizer to the reconciled resource.
 func WithFinalizer(finalizer string) ReconcilerOption {
 	return func(allOptions manifestOptions) manifestOptions {
@@ -62,6 +104,44 @@ func WithPostRun(runs ...types.PostRun) ReconcilerOption {
 	}
 }
 
+// WithOperationTimeout limits the duration of a single installation, uninstallation or consistency check.
+func WithOperationTimeout(timeout time.Duration) ReconcilerOption {
+	return func(allOptions manifestOptions) manifestOptions {
+		allOptions.operationTimeout = timeout
+		return allOptions
+	}
+}
+
+// WithTransitionNotifiers notifies the passed notifiers about every state transition of the reconciled resource,
+// e.g. a WebhookNotifier or a CloudEventsNotifier.
+func WithTransitionNotifiers(notifiers ...types.TransitionNotifier) ReconcilerOption {
+	return func(allOptions manifestOptions) manifestOptions {
+		allOptions.notifiers = append(allOptions.notifiers, notifiers...)
+		return allOptions
+	}
+}
+
+// WithAsyncJobs runs installations and uninstallations as background jobs of at most the passed number of
+// concurrent workers, e.g. for charts taking minutes to apply. Reconciles return immediately and poll
+// the progress of jobs in the passed interval instead of blocking the worker of the controller.
+func WithAsyncJobs(workers int, pollInterval time.Duration) ReconcilerOption {
+	return func(allOptions manifestOptions) manifestOptions {
+		allOptions.jobs = NewJobPool(workers)
+		allOptions.jobPollInterval = pollInterval
+		return allOptions
+	}
+}
+
+// WithConsistencyCheckInterval verifies the resources of Ready objects, whose generation did not change since
+// they were processed, at most once in the passed interval instead of on every reconcile, e.g. of status-only updates.
+// A non-positive interval verifies them on every reconcile. By default, they are verified once per minute.
+func WithConsistencyCheckInterval(interval time.Duration) ReconcilerOption {
+	return func(allOptions manifestOptions) manifestOptions {
+		allOptions.consistencyChecks = NewConsistencyChecks(interval)
+		return allOptions
+	}
+}
+
 func With(option ...ReconcilerOption) ReconcilerOption {
 	return func(allOptions manifestOptions) manifestOptions {
 		for i := range option {
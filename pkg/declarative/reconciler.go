@@ -2,14 +2,13 @@ package declarative
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/kyma-project/module-manager/pkg/cache"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -19,32 +18,42 @@ import (
 
 	"github.com/kyma-project/module-manager/pkg/manifest"
 	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
 )
 
-var _ reconcile.Reconciler = &ManifestReconciler{}
+var _ reconcile.Reconciler = &ManifestReconciler[types.CustomObject]{}
 
 const (
 	requeueInterval = time.Second * 3
 )
 
-type ManifestReconciler struct {
-	prototype types.BaseCustomObject
+// ManifestReconciler reconciles custom resources of type T, e.g. *v1alpha1.Sample,
+// by installing the manifest resolved for them.
+type ManifestReconciler[T types.CustomObject] struct {
+	prototype T
 
 	mgr          manager.Manager
 	cacheManager types.CacheManager
 	// recorder is the EventRecorder for creating k8s events
-	recorder record.EventRecorder
+	recorder *deduplicatingRecorder
 	options  manifestOptions
 }
 
 type manifestOptions struct {
-	force            bool
-	verify           bool
-	resourceLabels   map[string]string
-	objectTransforms []types.ObjectTransform
-	postRuns         []types.PostRun
-	manifestResolver types.ManifestResolver
-	finalizer        string
+	conflictPolicy    types.ConflictPolicy
+	verify            bool
+	resourceLabels    map[string]string
+	objectTransforms  []types.ObjectTransform
+	postRuns          []types.PostRun
+	manifestResolver  types.ManifestResolver
+	finalizer         string
+	operationTimeout  time.Duration
+	notifiers         []types.TransitionNotifier
+	jobs              *JobPool
+	jobPollInterval   time.Duration
+	consistencyChecks *ConsistencyChecks
+	tracking          *types.TrackingMetadata
+	imagePullSecret   *types.ImagePullSecret
 }
 
 func (m *manifestOptions) isFinalizerSet() bool {
@@ -53,17 +62,12 @@ func (m *manifestOptions) isFinalizerSet() bool {
 
 type ReconcilerOption func(manifestOptions) manifestOptions
 
-func (r *ManifestReconciler) Inject(mgr manager.Manager, customObject types.BaseCustomObject,
-	opts ...ReconcilerOption,
-) error {
-	r.prototype = customObject
+// Inject sets up the reconciler for resources of the type of the passed prototype.
+func (r *ManifestReconciler[T]) Inject(mgr manager.Manager, prototype T, opts ...ReconcilerOption) error {
+	r.prototype = prototype
 	r.mgr = mgr
-	controllerName, err := GetComponentName(customObject)
-	if err != nil {
-		return getTypeError(client.ObjectKeyFromObject(customObject).String())
-	}
-	r.recorder = mgr.GetEventRecorderFor(controllerName)
-	if err = r.applyOptions(opts...); err != nil {
+	r.recorder = newDeduplicatingRecorder(mgr.GetEventRecorderFor(prototype.ComponentName()), eventDeduplicationWindow)
+	if err := r.applyOptions(opts...); err != nil {
 		return err
 	}
 	r.cacheManager = cache.NewCacheManager()
@@ -73,32 +77,26 @@ func (r *ManifestReconciler) Inject(mgr manager.Manager, customObject types.Base
 
 // Reconcile is the entry point from the controller-runtime framework.
 // It performs a reconciliation based on the passed ctrl.Request object.
-func (r *ManifestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *ManifestReconciler[T]) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
-	// TODO(user): your logic here
-
-	// check if Sample resource exists
-	objectInstance, ok := r.prototype.DeepCopyObject().(types.CustomObject)
-	if !ok {
-		return ctrl.Result{}, getTypeError(req.String())
-	}
-
+	// the deep copy of the prototype has the type of the prototype
+	objectInstance, _ := r.prototype.DeepCopyObject().(T)
 	if err := r.mgr.GetClient().Get(ctx, req.NamespacedName, objectInstance); err != nil {
 		logger.Info(req.NamespacedName.String() + " got deleted!")
+		if r.options.jobs != nil {
+			r.options.jobs.Forget(req.NamespacedName)
+		}
+		r.options.consistencyChecks.Forget(req.NamespacedName)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
 	// check if deletionTimestamp is set, retry until it gets fully deleted
-	status, err := getStatusFromObjectInstance(objectInstance)
-	if err != nil {
-		return ctrl.Result{}, err
-	}
-
+	status := objectInstance.GetStatus()
 	if !objectInstance.GetDeletionTimestamp().IsZero() &&
 		status.State != types.StateDeleting {
 		// if the status is not yet set to deleting, also update the status
-		return ctrl.Result{}, r.setStatusForObjectInstance(ctx, objectInstance, status.WithState(types.StateDeleting))
+		return ctrl.Result{}, r.setStatusForObjectInstance(ctx, objectInstance, status.WithState(types.StateDeleting), nil)
 	}
 
 	// add finalizer
@@ -106,6 +104,11 @@ func (r *ManifestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, r.mgr.GetClient().Update(ctx, objectInstance)
 	}
 
+	// poll jobs in progress instead of blocking the worker
+	if r.options.jobs != nil && r.options.jobs.Pending(req.NamespacedName) {
+		return ctrl.Result{RequeueAfter: r.options.jobPollInterval}, nil
+	}
+
 	switch status.State {
 	case "":
 		return ctrl.Result{}, r.HandleInitialState(ctx, objectInstance)
@@ -116,6 +119,16 @@ func (r *ManifestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	case types.StateError:
 		return ctrl.Result{Requeue: true}, r.HandleProcessingState(ctx, objectInstance)
 	case types.StateReady:
+		// changes of the spec are installed instead of being verified against the installed resources
+		if status.ObservedGeneration != 0 && status.ObservedGeneration != objectInstance.GetGeneration() {
+			return ctrl.Result{Requeue: true}, r.setStatusForObjectInstance(ctx, objectInstance,
+				status.WithState(types.StateProcessing), nil)
+		}
+		if status.ObservedGeneration != 0 {
+			if due, wait := r.options.consistencyChecks.Due(req.NamespacedName); !due {
+				return ctrl.Result{RequeueAfter: wait}, nil
+			}
+		}
 		return ctrl.Result{RequeueAfter: requeueInterval}, r.HandleReadyState(ctx, objectInstance)
 	}
 
@@ -123,23 +136,18 @@ func (r *ManifestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 }
 
 // HandleInitialState bootstraps state handling for the reconciled resource.
-func (r *ManifestReconciler) HandleInitialState(ctx context.Context, objectInstance types.BaseCustomObject) error {
-	// TODO: initial logic here
-
-	status, err := getStatusFromObjectInstance(objectInstance)
-	if err != nil {
-		return err
-	}
+func (r *ManifestReconciler[T]) HandleInitialState(ctx context.Context, objectInstance T) error {
+	status := objectInstance.GetStatus()
 
 	// set resource labels
 	if r.applyLabels(objectInstance) {
 		return r.mgr.GetClient().Update(ctx, objectInstance)
 	}
 
-	return r.setStatusForObjectInstance(ctx, objectInstance, status.WithState(types.StateProcessing))
+	return r.setStatusForObjectInstance(ctx, objectInstance, status.WithState(types.StateProcessing), nil)
 }
 
-func (r *ManifestReconciler) applyLabels(objectInstance types.BaseCustomObject) bool {
+func (r *ManifestReconciler[T]) applyLabels(objectInstance T) bool {
 	labels := objectInstance.GetLabels()
 	updateRequired := false
 	if len(r.options.resourceLabels) == 0 {
@@ -165,8 +173,7 @@ func (r *ManifestReconciler) applyLabels(objectInstance types.BaseCustomObject)
 
 // HandleProcessingState processes the reconciled resource by processing the underlying resources.
 // Based on the processing either a success or failure state is set on the reconciled resource.
-func (r *ManifestReconciler) HandleProcessingState(ctx context.Context, objectInstance types.BaseCustomObject) error {
-	// TODO: processing logic here
+func (r *ManifestReconciler[T]) HandleProcessingState(ctx context.Context, objectInstance T) error {
 	logger := log.FromContext(ctx)
 
 	// fetch install information
@@ -178,10 +185,7 @@ func (r *ManifestReconciler) HandleProcessingState(ctx context.Context, objectIn
 		return fmt.Errorf("no chart path available for processing")
 	}
 
-	status, err := getStatusFromObjectInstance(objectInstance)
-	if err != nil {
-		return err
-	}
+	status := objectInstance.GetStatus()
 
 	// Use manifest library client to install a sample chart
 	installInfo, err := r.prepareInstallInfo(ctx, objectInstance, installSpec,
@@ -189,28 +193,35 @@ func (r *ManifestReconciler) HandleProcessingState(ctx context.Context, objectIn
 	if err != nil {
 		return err
 	}
-	ready, err := manifest.InstallChart(manifest.OperationOptions{
-		Logger:             logger,
-		InstallInfo:        installInfo,
-		ResourceTransforms: r.options.objectTransforms,
-		PostRuns:           r.options.postRuns,
-		Cache:              r.cacheManager.GetRendererCache(),
+	result, finished := r.runOperation(objectInstance, JobOperationInstall, func() (bool, error) {
+		return manifest.InstallChart(manifest.OperationOptions{
+			Logger:             logger,
+			InstallInfo:        installInfo,
+			ResourceTransforms: r.options.objectTransforms,
+			PostRuns:           r.options.postRuns,
+			Cache:              r.cacheManager.GetRendererCache(),
+		})
 	})
+	if !finished {
+		return nil
+	}
+	ready, err := result.Ready, result.Err
 	if err != nil {
 		logger.Error(nil, fmt.Sprintf("error while installing resource %s %s",
 			client.ObjectKeyFromObject(objectInstance), err.Error()))
-		return r.setStatusForObjectInstance(ctx, objectInstance, status.WithState(types.StateError))
+		r.recordFailure(objectInstance, EventReasonInstallFailed, err)
+		return r.setStatusForObjectInstance(ctx, objectInstance, status.WithState(types.StateError), err)
 	}
 
 	if ready {
-		return r.setStatusForObjectInstance(ctx, objectInstance, status.WithState(types.StateReady))
+		return r.setStatusForObjectInstance(ctx, objectInstance, status.WithState(types.StateReady), nil)
 	}
 	return nil
 }
 
 // HandleDeletingState processed the deletion on the reconciled resource.
 // Once the deletion if processed the relevant finalizers (if applied) are removed.
-func (r *ManifestReconciler) HandleDeletingState(ctx context.Context, objectInstance types.BaseCustomObject) error {
+func (r *ManifestReconciler[T]) HandleDeletingState(ctx context.Context, objectInstance T) error {
 	logger := log.FromContext(ctx)
 
 	// fetch uninstall information
@@ -230,10 +241,7 @@ func (r *ManifestReconciler) HandleDeletingState(ctx context.Context, objectInst
 		installSpec.ConfigFlags = map[string]interface{}{}
 	}
 
-	status, err := getStatusFromObjectInstance(objectInstance)
-	if err != nil {
-		return err
-	}
+	status := objectInstance.GetStatus()
 
 	// Use manifest library client to install a sample chart
 	installInfo, err := r.prepareInstallInfo(ctx, objectInstance, installSpec,
@@ -242,33 +250,42 @@ func (r *ManifestReconciler) HandleDeletingState(ctx context.Context, objectInst
 		return err
 	}
 
-	readyToBeDeleted, err := manifest.UninstallChart(manifest.OperationOptions{
-		Logger:             logger,
-		InstallInfo:        installInfo,
-		ResourceTransforms: r.options.objectTransforms,
-		PostRuns:           r.options.postRuns,
-		Cache:              r.cacheManager.GetRendererCache(),
+	result, finished := r.runOperation(objectInstance, JobOperationUninstall, func() (bool, error) {
+		return manifest.UninstallChart(manifest.OperationOptions{
+			Logger:             logger,
+			InstallInfo:        installInfo,
+			ResourceTransforms: r.options.objectTransforms,
+			PostRuns:           r.options.postRuns,
+			Cache:              r.cacheManager.GetRendererCache(),
+		})
 	})
+	if !finished {
+		return nil
+	}
+	readyToBeDeleted, err := result.Ready, result.Err
 	if err != nil {
 		logger.Error(err, fmt.Sprintf("error while deleting resource %s", client.ObjectKeyFromObject(objectInstance)))
+		r.recordFailure(objectInstance, EventReasonUninstallFailed, err)
 		status.State = types.StateError
-		return r.setStatusForObjectInstance(ctx, objectInstance, status.WithState(types.StateError))
+		return r.setStatusForObjectInstance(ctx, objectInstance, status.WithState(types.StateError), err)
 	}
 	// if resources are ready to be deleted, remove finalizer
 	if readyToBeDeleted && r.options.isFinalizerSet() &&
 		controllerutil.RemoveFinalizer(objectInstance, r.options.finalizer) {
-		return r.mgr.GetClient().Update(ctx, objectInstance)
+		if err := r.mgr.GetClient().Update(ctx, objectInstance); err != nil {
+			return err
+		}
+		r.recorder.Event(objectInstance, "Normal", EventReasonFinalizerRemoved,
+			fmt.Sprintf("finalizer %s removed after uninstallation", r.options.finalizer))
+		r.recorder.Forget(objectInstance)
 	}
 	return nil
 }
 
 // HandleReadyState checks for the consistency of reconciled resource, by verifying the underlying resources.
-func (r *ManifestReconciler) HandleReadyState(ctx context.Context, objectInstance types.BaseCustomObject) error {
+func (r *ManifestReconciler[T]) HandleReadyState(ctx context.Context, objectInstance T) error {
 	logger := log.FromContext(ctx)
-	status, err := getStatusFromObjectInstance(objectInstance)
-	if err != nil {
-		return err
-	}
+	status := objectInstance.GetStatus()
 
 	// fetch install information
 	installSpec, err := r.options.manifestResolver.Get(objectInstance, logger)
@@ -299,36 +316,52 @@ func (r *ManifestReconciler) HandleReadyState(ctx context.Context, objectInstanc
 	if err != nil {
 		logger.Error(err, fmt.Sprintf("error while installing resource %s",
 			client.ObjectKeyFromObject(objectInstance)))
-		return r.setStatusForObjectInstance(ctx, objectInstance, status.WithState(types.StateError))
+		r.recordFailure(objectInstance, EventReasonConsistencyCheckFailed, err)
+		return r.setStatusForObjectInstance(ctx, objectInstance, status.WithState(types.StateError), err)
 	} else if !ready {
-		return r.setStatusForObjectInstance(ctx, objectInstance, status.WithState(types.StateProcessing))
+		return r.setStatusForObjectInstance(ctx, objectInstance, status.WithState(types.StateProcessing), nil)
+	}
+	// objects processed before their generation was observed are stamped once they are verified
+	if status.ObservedGeneration != objectInstance.GetGeneration() {
+		return r.setStatusForObjectInstance(ctx, objectInstance, status, nil)
 	}
 	return nil
 }
 
-func (r *ManifestReconciler) prepareInstallInfo(ctx context.Context, objectInstance types.BaseCustomObject,
+// runOperation runs the operation in place, or as job of the JobPool if async jobs are enabled.
+// Unless finished is set, the job is still in progress and its result is polled by subsequent reconciles.
+func (r *ManifestReconciler[T]) runOperation(objectInstance T, operation string,
+	run func() (bool, error),
+) (JobResult, bool) {
+	if r.options.jobs == nil {
+		ready, err := run()
+		return JobResult{Ready: ready, Err: err}, true
+	}
+	return r.options.jobs.Run(client.ObjectKeyFromObject(objectInstance), operation, objectInstance.GetGeneration(), run)
+}
+
+func (r *ManifestReconciler[T]) prepareInstallInfo(ctx context.Context, objectInstance T,
 	installSpec types.InstallationSpec, releaseName string,
 ) (*types.InstallInfo, error) {
 	obj := &unstructured.Unstructured{}
 	var err error
-	switch typedObject := objectInstance.(type) {
-	case types.CustomObject:
-		obj.Object, err = runtime.DefaultUnstructuredConverter.ToUnstructured(typedObject)
-		if err != nil {
-			return &types.InstallInfo{}, err
-		}
-	case *unstructured.Unstructured:
-		obj = typedObject
-	default:
-		return &types.InstallInfo{}, getTypeError(client.ObjectKeyFromObject(objectInstance).String())
+	obj.Object, err = runtime.DefaultUnstructuredConverter.ToUnstructured(objectInstance)
+	if err != nil {
+		return &types.InstallInfo{}, err
+	}
+
+	conflictPolicy := r.options.conflictPolicy
+	if installSpec.ConflictPolicy != "" {
+		conflictPolicy = installSpec.ConflictPolicy
 	}
 
 	return &types.InstallInfo{
 		Ctx: ctx,
 		ChartInfo: &types.ChartInfo{
-			ChartPath:   installSpec.ChartPath,
-			ReleaseName: releaseName,
-			Flags:       installSpec.ChartFlags,
+			ChartPath:      installSpec.ChartPath,
+			ReleaseName:    releaseName,
+			Flags:          installSpec.ChartFlags,
+			ConflictPolicy: conflictPolicy,
 		},
 		ClusterInfo: &types.ClusterInfo{
 			// destination cluster rest config
@@ -341,12 +374,13 @@ func (r *ManifestReconciler) prepareInstallInfo(ctx context.Context, objectInsta
 			BaseResource: obj,
 		},
 		CheckReadyStates: r.options.verify,
+		Timeout:          r.options.operationTimeout,
 	}, nil
 }
 
-func (r *ManifestReconciler) applyOptions(opts ...ReconcilerOption) error {
+func (r *ManifestReconciler[T]) applyOptions(opts ...ReconcilerOption) error {
 	params := manifestOptions{
-		force:            false,
+		conflictPolicy:   types.ConflictPolicyForce,
 		verify:           false,
 		resourceLabels:   make(map[string]string, 0),
 		objectTransforms: []types.ObjectTransform{},
@@ -357,6 +391,28 @@ func (r *ManifestReconciler) applyOptions(opts ...ReconcilerOption) error {
 		params = opt(params)
 	}
 
+	// secrets rendered for image pulls are tracked like all other resources
+	if params.imagePullSecret != nil {
+		transform, err := util.ImagePullSecretTransform(*params.imagePullSecret)
+		if err != nil {
+			return err
+		}
+		params.objectTransforms = append(params.objectTransforms, transform)
+	}
+
+	// tracking labels are set last, so that post-render transforms cannot override them
+	if params.tracking != nil {
+		params.objectTransforms = append(params.objectTransforms, manifest.TrackingTransform(*params.tracking))
+	}
+
+	if params.consistencyChecks == nil {
+		params.consistencyChecks = NewConsistencyChecks(consistencyCheckIntervalDefault)
+	}
+
+	if params.jobs != nil && params.jobPollInterval <= 0 {
+		params.jobPollInterval = requeueInterval
+	}
+
 	if params.manifestResolver == nil {
 		return fmt.Errorf("no manifest resolver set, reconciliation cannot proceed")
 	}
@@ -365,74 +421,47 @@ func (r *ManifestReconciler) applyOptions(opts ...ReconcilerOption) error {
 	return nil
 }
 
-func (r *ManifestReconciler) setStatusForObjectInstance(ctx context.Context, objectInstance types.BaseCustomObject,
-	status types.Status,
+// setStatusForObjectInstance updates the status, cause is the error leading to the status, if any.
+func (r *ManifestReconciler[T]) setStatusForObjectInstance(ctx context.Context, objectInstance T,
+	status types.Status, cause error,
 ) error {
-	var err error
-	var unstructStatus map[string]interface{}
-
-	switch typedObject := objectInstance.(type) {
-	case types.CustomObject:
-		typedObject.SetStatus(status)
-	case *unstructured.Unstructured:
-		unstructStatus, err = runtime.DefaultUnstructuredConverter.ToUnstructured(status)
-		if err != nil {
-			err = fmt.Errorf("unable to convert unstructured to addonStatus: %w", err)
-			break
-		}
-
-		if err = unstructured.SetNestedMap(typedObject.Object, unstructStatus, "status"); err != nil {
-			err = fmt.Errorf("unable to set status in unstructured: %w", err)
-		}
-	default:
-		err = getTypeError(client.ObjectKeyFromObject(objectInstance).String())
-	}
-
-	// return intermediate error
-	if err != nil {
-		return err
-	}
-
-	if err = r.mgr.GetClient().Status().Update(ctx, objectInstance); err != nil {
+	previousStatus := objectInstance.GetStatus()
+	objectInstance.SetStatus(status.WithObservedGeneration(objectInstance.GetGeneration()))
+	if err := r.mgr.GetClient().Status().Update(ctx, objectInstance); err != nil {
 		return fmt.Errorf("error while updating status %s to: %w", status.State, err)
 	}
+	r.recordStateTransition(objectInstance, previousStatus.State, status.State, cause)
 	return nil
 }
 
-func getTypeError(namespacedName string) error {
-	return fmt.Errorf("invalid custom resource object type for reconciliation %s", namespacedName)
-}
-
-func getStatusFromObjectInstance(objectInstance types.BaseCustomObject) (types.Status, error) {
-	switch typedObject := objectInstance.(type) {
-	case types.CustomObject:
-		return typedObject.GetStatus(), nil
-	case *unstructured.Unstructured:
-		unstructStatus, _, err := unstructured.NestedMap(typedObject.Object, "status")
-		if err != nil {
-			return types.Status{}, fmt.Errorf("unable to get status from unstuctured: %w", err)
-		}
-		var customStatus types.Status
-		err = runtime.DefaultUnstructuredConverter.FromUnstructured(unstructStatus, &customStatus)
-		if err != nil {
-			return customStatus, err
-		}
-
-		return customStatus, nil
-	default:
-		return types.Status{}, getTypeError(client.ObjectKeyFromObject(objectInstance).String())
+func (r *ManifestReconciler[T]) recordStateTransition(objectInstance T,
+	previous types.State, current types.State, cause error,
+) {
+	if previous == current {
+		return
+	}
+	for _, notifier := range r.options.notifiers {
+		notifier.OnTransition(objectInstance, previous, current, cause)
+	}
+	eventType := "Normal"
+	if current == types.StateError {
+		eventType = "Warning"
+	}
+	if previous == "" {
+		previous = "initial"
 	}
+	r.recorder.Event(objectInstance, eventType, EventReasonStateChanged,
+		fmt.Sprintf("state changed from %s to %s", previous, current))
 }
 
-func GetComponentName(objectInstance types.BaseCustomObject) (string, error) {
-	switch typedObject := objectInstance.(type) {
-	case types.CustomObject:
-		return typedObject.ComponentName(), nil
-	case *unstructured.Unstructured:
-		return strings.ToLower(typedObject.GetKind()), nil
-	default:
-		return "", getTypeError(client.ObjectKeyFromObject(objectInstance).String())
+// recordFailure records a warning for the failed operation, operations exceeding their timeout
+// are recorded as readiness timeout.
+func (r *ManifestReconciler[T]) recordFailure(objectInstance T, reason string, err error) {
+	var timeoutErr *types.OperationTimeoutError
+	if errors.As(err, &timeoutErr) {
+		reason = EventReasonReadinessTimeout
 	}
+	r.recorder.Event(objectInstance, "Warning", reason, err.Error())
 }
 
 func resolveReleaseName(releaseName string, objectInstance types.BaseCustomObject) string {
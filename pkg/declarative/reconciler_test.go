@@ -0,0 +1,10 @@
+package declarative_test
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kyma-project/module-manager/pkg/declarative"
+)
+
+// ManifestReconciler is instantiated with Go types of custom resources, unstructured resources are not supported.
+var _ reconcile.Reconciler = &declarative.ManifestReconciler[*TestCRD]{}
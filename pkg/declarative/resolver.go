@@ -10,22 +10,41 @@ import (
 	"github.com/kyma-project/module-manager/pkg/types"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
-	specKey        = "spec"
-	chartPathKey   = "chartPath"
-	releaseNameKey = "releaseName"
-	chartFlagsKey  = "chartFlags"
-
-	errMsgSpec      = "`spec` does not exist in `%s`"
 	ErrMsgMandatory = "invalid type conversion for `%s` or does not exist in spec "
 	infoMsgOptional = "invalid type conversion for `%s` or optional field is not given in spec"
 )
 
+// SpecPaths are JSONPath expressions of the fields of the InstallationSpec within custom resources,
+// which do not implement types.SpecAccessor.
+type SpecPaths struct {
+	ChartPath      string
+	ReleaseName    string
+	ChartFlags     string
+	ConflictPolicy string
+}
+
+// DefaultSpecPaths resolve the fields from the `spec` of the custom resource, e.g. `{.spec.chartPath}`.
+func DefaultSpecPaths() SpecPaths {
+	return SpecPaths{
+		ChartPath:      "{.spec.chartPath}",
+		ReleaseName:    "{.spec.releaseName}",
+		ChartFlags:     "{.spec.chartFlags}",
+		ConflictPolicy: "{.spec.conflictPolicy}",
+	}
+}
+
 // DefaultManifestResolver represents the chart information for the passed BaseCustomObject resource.
-type DefaultManifestResolver struct{}
+// Custom resources implementing types.SpecAccessor provide their InstallationSpec themselves,
+// the fields of all others are resolved from their unstructured content by Paths.
+type DefaultManifestResolver struct {
+	// Paths overrides the DefaultSpecPaths of fields with a non-empty path
+	Paths SpecPaths
+}
 
 // Get returns the chart information to be processed.
 func (m DefaultManifestResolver) Get(
@@ -34,61 +53,129 @@ func (m DefaultManifestResolver) Get(
 ) (types.InstallationSpec, error) {
 	objectString := client.ObjectKeyFromObject(object).String()
 
-	// Cast object to unstructured
-	unstructuredObj, err := assertUnstructured(object)
-	if err != nil {
-		return types.InstallationSpec{}, err
+	var installationSpec types.InstallationSpec
+	var err error
+	if accessor, ok := object.(types.SpecAccessor); ok {
+		installationSpec, err = accessor.GetInstallationSpec()
+	} else {
+		installationSpec, err = m.resolveUnstructured(object, logger)
 	}
-
-	// Get spec of object
-	spec, valid := unstructuredObj.Object[specKey].(map[string]interface{})
-	if !valid {
-		return types.InstallationSpec{}, fmt.Errorf(errMsgSpec, objectString)
+	if err != nil {
+		return types.InstallationSpec{}, fmt.Errorf("resolving spec of `%s`: %w", objectString, err)
 	}
 
 	// Mandatory spec
-	chartPath, valid := spec[chartPathKey].(string)
-	if !valid || chartPath == "" {
+	if installationSpec.ChartPath == "" {
 		return types.InstallationSpec{}, &ResolveError{
 			ObjectName: objectString,
 			Err:        errors.New(ErrMsgMandatory),
 		}
 	}
+	return installationSpec, nil
+}
+
+func (m DefaultManifestResolver) resolveUnstructured(object types.BaseCustomObject,
+	logger logr.Logger,
+) (types.InstallationSpec, error) {
+	unstructuredObj, err := toUnstructured(object)
+	if err != nil {
+		return types.InstallationSpec{}, err
+	}
+	paths := m.paths()
+
+	chartPath, err := lookupString(unstructuredObj, paths.ChartPath)
+	if err != nil {
+		return types.InstallationSpec{}, err
+	}
 
 	// Optional spec
-	releaseName, valid := spec[releaseNameKey].(string)
-	if !valid {
-		logger.V(util.DebugLogLevel).Info(fmt.Sprintf(infoMsgOptional, releaseNameKey))
+	releaseName, err := lookupString(unstructuredObj, paths.ReleaseName)
+	if err != nil {
+		return types.InstallationSpec{}, err
+	}
+	if releaseName == "" {
+		logger.V(util.DebugLogLevel).Info(fmt.Sprintf(infoMsgOptional, paths.ReleaseName))
 	}
-	chartFlags, valid := spec[chartFlagsKey].(types.ChartFlags)
-	if !valid {
-		logger.V(util.DebugLogLevel).Info(fmt.Sprintf(infoMsgOptional, chartFlagsKey))
+	var chartFlags types.ChartFlags
+	flagsValue, found, err := lookup(unstructuredObj, paths.ChartFlags)
+	if err != nil {
+		return types.InstallationSpec{}, err
+	}
+	flagsContent, valid := flagsValue.(map[string]interface{})
+	if !found || !valid {
+		logger.V(util.DebugLogLevel).Info(fmt.Sprintf(infoMsgOptional, paths.ChartFlags))
+	} else if err = runtime.DefaultUnstructuredConverter.FromUnstructured(flagsContent, &chartFlags); err != nil {
+		return types.InstallationSpec{}, fmt.Errorf("invalid chart flags at %s: %w", paths.ChartFlags, err)
+	}
+	policy, err := lookupString(unstructuredObj, paths.ConflictPolicy)
+	if err != nil {
+		return types.InstallationSpec{}, err
+	}
+	if policy == "" {
+		logger.V(util.DebugLogLevel).Info(fmt.Sprintf(infoMsgOptional, paths.ConflictPolicy))
 	}
 
 	return types.InstallationSpec{
-		ChartPath:   chartPath,
-		ReleaseName: releaseName,
-		ChartFlags:  chartFlags,
+		ChartPath:      chartPath,
+		ReleaseName:    releaseName,
+		ChartFlags:     chartFlags,
+		ConflictPolicy: types.ConflictPolicy(policy),
 	}, nil
 }
 
-func assertUnstructured(object types.BaseCustomObject) (*unstructured.Unstructured, error) {
-	objKey := client.ObjectKeyFromObject(object)
-	unstructuredObj := &unstructured.Unstructured{}
-	var err error
+// paths returns the configured Paths, with DefaultSpecPaths for fields without a path.
+func (m DefaultManifestResolver) paths() SpecPaths {
+	paths := DefaultSpecPaths()
+	if m.Paths.ChartPath != "" {
+		paths.ChartPath = m.Paths.ChartPath
+	}
+	if m.Paths.ReleaseName != "" {
+		paths.ReleaseName = m.Paths.ReleaseName
+	}
+	if m.Paths.ChartFlags != "" {
+		paths.ChartFlags = m.Paths.ChartFlags
+	}
+	if m.Paths.ConflictPolicy != "" {
+		paths.ConflictPolicy = m.Paths.ConflictPolicy
+	}
+	return paths
+}
 
-	switch typedObject := object.(type) {
-	case types.CustomObject:
-		unstructuredObj.Object, err = runtime.DefaultUnstructuredConverter.ToUnstructured(typedObject)
-		if err != nil {
-			return nil, fmt.Errorf("invalid type conversion for `%v`: %w", objKey, err)
-		}
-	case *unstructured.Unstructured:
-		unstructuredObj = typedObject
-	default:
-		return nil, fmt.Errorf("no matching type for `%v`", objKey)
+func toUnstructured(object types.BaseCustomObject) (*unstructured.Unstructured, error) {
+	if unstructuredObj, ok := object.(*unstructured.Unstructured); ok {
+		return unstructuredObj, nil
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+	if err != nil {
+		return nil, fmt.Errorf("invalid type conversion: %w", err)
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+// lookup returns the first value matching the JSONPath expression, missing fields are not found.
+func lookup(obj *unstructured.Unstructured, expression string) (interface{}, bool, error) {
+	parser := jsonpath.New("spec").AllowMissingKeys(true)
+	if err := parser.Parse(expression); err != nil {
+		return nil, false, fmt.Errorf("invalid JSONPath %s: %w", expression, err)
+	}
+	results, err := parser.FindResults(obj.Object)
+	if err != nil {
+		return nil, false, fmt.Errorf("evaluating JSONPath %s: %w", expression, err)
+	}
+	if len(results) == 0 || len(results[0]) == 0 || !results[0][0].CanInterface() {
+		return nil, false, nil
+	}
+	return results[0][0].Interface(), true, nil
+}
+
+// lookupString returns the string matching the JSONPath expression, or an empty string for values of other types.
+func lookupString(obj *unstructured.Unstructured, expression string) (string, error) {
+	value, _, err := lookup(obj, expression)
+	if err != nil {
+		return "", err
 	}
-	return unstructuredObj, nil
+	stringValue, _ := value.(string)
+	return stringValue, nil
 }
 
 type ResolveError struct {
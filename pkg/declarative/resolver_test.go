@@ -7,11 +7,13 @@ import (
 
 	"github.com/kyma-project/module-manager/pkg/declarative"
 
+	"github.com/go-logr/logr"
 	"github.com/go-logr/zerologr"
 	"github.com/kyma-project/module-manager/pkg/types"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -64,6 +66,22 @@ func TestGet(t *testing.T) {
 				Err:        errors.New(declarative.ErrMsgMandatory),
 			},
 		},
+		{
+			testName:  "Resolve object with chart flags",
+			name:      "testCR",
+			namespace: "default",
+			object: &TestCRD{
+				Spec: types.InstallationSpec{
+					ChartPath:  "path/to/chart",
+					ChartFlags: types.ChartFlags{SetFlags: types.Flags{"replicas": int64(2)}},
+				},
+			},
+			expectedInstallationSpec: types.InstallationSpec{
+				ChartPath:  "path/to/chart",
+				ChartFlags: types.ChartFlags{SetFlags: types.Flags{"replicas": int64(2)}},
+			},
+			expectedErr: nil,
+		},
 		{
 			testName:  "Resolve object with minimal valid input",
 			name:      "testCR",
@@ -92,6 +110,55 @@ func TestGet(t *testing.T) {
 	}
 }
 
+func TestGetWithSpecPaths(t *testing.T) {
+	t.Parallel()
+	object := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"installation": map[string]interface{}{"chart": "path/to/chart", "policy": "Fail"},
+			"releaseName":  "test-release",
+		},
+	}}
+
+	resolver := declarative.DefaultManifestResolver{Paths: declarative.SpecPaths{
+		ChartPath:      "{.spec.installation.chart}",
+		ConflictPolicy: "{.spec.installation.policy}",
+	}}
+	installationSpec, err := resolver.Get(object, logr.Discard())
+	assert.NoError(t, err)
+	assert.Equal(t, types.InstallationSpec{
+		ChartPath:      "path/to/chart",
+		ReleaseName:    "test-release",
+		ConflictPolicy: types.ConflictPolicyFail,
+	}, installationSpec, "fields without path are resolved by the default paths")
+
+	resolver = declarative.DefaultManifestResolver{Paths: declarative.SpecPaths{ChartPath: "{.spec.installation[}"}}
+	_, err = resolver.Get(object, logr.Discard())
+	assert.Error(t, err)
+}
+
+func TestGetWithSpecAccessor(t *testing.T) {
+	t.Parallel()
+	object := &accessorCRD{spec: types.InstallationSpec{ChartPath: "path/to/chart", ReleaseName: "test-release"}}
+	installationSpec, err := declarative.DefaultManifestResolver{}.Get(object, logr.Discard())
+	assert.NoError(t, err)
+	assert.Equal(t, object.spec, installationSpec)
+
+	object.spec.ChartPath = ""
+	_, err = declarative.DefaultManifestResolver{}.Get(object, logr.Discard())
+	var resolveErr *declarative.ResolveError
+	assert.ErrorAs(t, err, &resolveErr)
+}
+
+// accessorCRD provides its InstallationSpec with types.SpecAccessor.
+type accessorCRD struct {
+	TestCRD
+	spec types.InstallationSpec
+}
+
+func (s *accessorCRD) GetInstallationSpec() (types.InstallationSpec, error) {
+	return s.spec, nil
+}
+
 // TestCRD implements the BaseCustomObject and can be used for easy testing.
 type TestCRD struct {
 	metav1.TypeMeta   `json:",inline"`
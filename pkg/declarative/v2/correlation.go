@@ -0,0 +1,64 @@
+package v2
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// CorrelationIDAnnotation is set on all rendered resources and on events of the operation that applied them,
+// so that resources in a target cluster can be traced back to the operation in the control plane.
+const CorrelationIDAnnotation = "reconciler.kyma-project.io/correlation-id"
+
+const correlationIDLength = 32
+
+type correlationIDKey struct{}
+
+// CorrelationID returns the correlation ID of the current operation on the object. The ID is derived from
+// the UID and generation of the object, so it is stable across retries of the same operation, and rendered
+// resources are not updated on every reconciliation, while it changes with every change of the spec.
+func CorrelationID(obj Object) string {
+	hash := sha256.Sum256([]byte(string(obj.GetUID()) + "/" + strconv.FormatInt(obj.GetGeneration(), 10)))
+	return hex.EncodeToString(hash[:])[:correlationIDLength]
+}
+
+// WithCorrelationID returns a context carrying the correlation ID, also added to the logger of the context.
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues("correlationID", correlationID))
+	return context.WithValue(ctx, correlationIDKey{}, correlationID)
+}
+
+// CorrelationIDFromContext returns the correlation ID of the context, or an empty string if none is set.
+func CorrelationIDFromContext(ctx context.Context) string {
+	correlationID, _ := ctx.Value(correlationIDKey{}).(string)
+	return correlationID
+}
+
+func correlationIDTransform(ctx context.Context, _ Object, resources []*unstructured.Unstructured) error {
+	correlationID := CorrelationIDFromContext(ctx)
+	if correlationID == "" {
+		return nil
+	}
+	for _, resource := range resources {
+		annotations := resource.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[CorrelationIDAnnotation] = correlationID
+		resource.SetAnnotations(annotations)
+	}
+	return nil
+}
+
+// event records an event annotated with the correlation ID of the context.
+func (r *Reconciler) event(ctx context.Context, obj Object, eventType, reason, message string) {
+	annotations := map[string]string{}
+	if correlationID := CorrelationIDFromContext(ctx); correlationID != "" {
+		annotations[CorrelationIDAnnotation] = correlationID
+	}
+	r.AnnotatedEventf(obj, annotations, eventType, reason, "%s", message)
+}
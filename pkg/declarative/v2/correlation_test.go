@@ -0,0 +1,37 @@
+// contains internal tests that should not be exposed, thus no v2_test
+//
+//nolint:testpackage
+package v2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_CorrelationID(t *testing.T) {
+	t.Parallel()
+	obj := testObj{&unstructured.Unstructured{Object: map[string]any{}}}
+	obj.SetUID("3f1c0c2e-8d2a-4b4e-9a57-5d1b2c3d4e5f")
+	obj.SetGeneration(1)
+
+	correlationID := CorrelationID(obj)
+	assert.Len(t, correlationID, correlationIDLength)
+	assert.Equal(t, correlationID, CorrelationID(obj), "retries of an operation share the correlation ID")
+	obj.SetGeneration(2)
+	assert.NotEqual(t, correlationID, CorrelationID(obj), "changes of the spec start a new operation")
+
+	ctx := WithCorrelationID(context.Background(), correlationID)
+	assert.Equal(t, correlationID, CorrelationIDFromContext(ctx))
+	assert.Empty(t, CorrelationIDFromContext(context.Background()))
+
+	resources := []*unstructured.Unstructured{{Object: map[string]any{}}}
+	assert.NoError(t, correlationIDTransform(ctx, obj, resources))
+	assert.Equal(t, correlationID, resources[0].GetAnnotations()[CorrelationIDAnnotation])
+
+	resources = []*unstructured.Unstructured{{Object: map[string]any{}}}
+	assert.NoError(t, correlationIDTransform(context.Background(), obj, resources))
+	assert.Empty(t, resources[0].GetAnnotations(), "resources are not annotated without correlation ID")
+}
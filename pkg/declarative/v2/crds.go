@@ -16,7 +16,7 @@ func getCRDs(clnt Client, crdFiles []chart.CRD) (kube.ResourceList, error) {
 	for i := range crdFiles {
 		crdManifest.Write(append(bytes.TrimPrefix(crdFiles[i].File.Data, []byte("---\n")), '\n'))
 	}
-	crdsObjects, err := util.ParseManifestStringToObjects(crdManifest.String())
+	crdsObjects, err := util.ParseManifestToObjects(&crdManifest)
 	if err != nil {
 		return nil, err
 	}
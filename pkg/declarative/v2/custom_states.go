@@ -0,0 +1,78 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var ErrStateTransitionNotAllowed = errors.New("state transition not allowed")
+
+// StateHandler reconciles an object in a CustomState. It moves the object to another state by setting the state
+// of its status, which is validated against the transitions of the CustomState after the handler returned.
+type StateHandler func(ctx context.Context, clnt Client, obj Object) (ctrl.Result, error)
+
+// CustomState is a state in addition to the built-in states, e.g. "Upgrading", "Suspended" or
+// "WaitingForDependencies", in which objects are reconciled by its Handler instead of the built-in flow.
+// The built-in flow never moves an object to a CustomState, it is entered by a StateHandler or by the owner
+// of the object, and the CRD of the object has to allow it in its status.
+// Deleted objects always move to StateDeleting, regardless of their CustomState.
+type CustomState struct {
+	State   State
+	Handler StateHandler
+	// Transitions lists the states the Handler may move the object to, it can always stay in State
+	Transitions []State
+}
+
+// allows indicates if the CustomState may move to the passed state.
+func (s CustomState) allows(state State) bool {
+	if state == s.State {
+		return true
+	}
+	for _, transition := range s.Transitions {
+		if transition == state {
+			return true
+		}
+	}
+	return false
+}
+
+// WithCustomState registers a CustomState, which is handled by the passed handler and may move
+// to the passed states. Registrations of the built-in states are ignored.
+func WithCustomState(state State, handler StateHandler, transitions ...State) WithCustomStateOption {
+	return WithCustomStateOption{State: state, Handler: handler, Transitions: transitions}
+}
+
+type WithCustomStateOption CustomState
+
+func (o WithCustomStateOption) Apply(options *Options) {
+	switch o.State {
+	case StateReady, StateProcessing, StateError, StateDeleting, "":
+		return
+	}
+	if options.CustomStates == nil {
+		options.CustomStates = map[State]CustomState{}
+	}
+	options.CustomStates[o.State] = CustomState(o)
+}
+
+// handleCustomState runs the handler of the CustomState of the object. A move to a state outside of the transitions
+// of the CustomState is reverted and recorded as ErrStateTransitionNotAllowed in the status of the object.
+func (r *Reconciler) handleCustomState(ctx context.Context, clnt Client, obj Object, customState CustomState,
+) (ctrl.Result, error) {
+	result, err := customState.Handler(ctx, clnt, obj)
+	status := obj.GetStatus()
+	if !customState.allows(status.State) {
+		err = fmt.Errorf("%w: from %s to %s", ErrStateTransitionNotAllowed, customState.State, status.State)
+		r.event(ctx, obj, "Warning", "StateTransition", err.Error())
+		status = status.WithState(customState.State)
+	}
+	if err != nil {
+		obj.SetStatus(status.WithErr(err))
+		return ctrl.Result{}, err
+	}
+	obj.SetStatus(status)
+	return result, nil
+}
@@ -0,0 +1,72 @@
+// contains internal tests that should not be exposed, thus no v2_test
+//
+//nolint:testpackage
+package v2
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	stateUpgrading State = "Upgrading"
+	stateSuspended State = "Suspended"
+)
+
+type statusObj struct {
+	testObj
+	status *Status
+}
+
+func (s statusObj) GetStatus() Status       { return *s.status }
+func (s statusObj) SetStatus(status Status) { *s.status = status }
+
+func Test_CustomStates(t *testing.T) {
+	t.Parallel()
+	moveTo := func(state State, err error) StateHandler {
+		return func(_ context.Context, _ Client, obj Object) (ctrl.Result, error) {
+			obj.SetStatus(obj.GetStatus().WithState(state))
+			return ctrl.Result{RequeueAfter: 1}, err
+		}
+	}
+	options := (&Options{}).Apply(
+		WithCustomState(stateUpgrading, moveTo(StateReady, nil), StateReady, StateError),
+		WithCustomState(stateSuspended, moveTo(StateProcessing, nil)),
+		WithCustomState(StateReady, moveTo(StateError, nil)),
+	)
+	require.Len(t, options.CustomStates, 2, "built-in states cannot be redefined")
+	reconciler := &Reconciler{Options: options}
+	reconciler.EventRecorder = record.NewFakeRecorder(10)
+	ctx := context.Background()
+	newObj := func(state State) Object {
+		return statusObj{testObj{&unstructured.Unstructured{Object: map[string]any{}}}, &Status{State: state}}
+	}
+
+	obj := newObj(stateUpgrading)
+	result, err := reconciler.handleCustomState(ctx, nil, obj, options.CustomStates[stateUpgrading])
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: 1}, result)
+	assert.Equal(t, StateReady, obj.GetStatus().State)
+
+	obj = newObj(stateSuspended)
+	_, err = reconciler.handleCustomState(ctx, nil, obj, options.CustomStates[stateSuspended])
+	assert.ErrorIs(t, err, ErrStateTransitionNotAllowed)
+	assert.Equal(t, stateSuspended, obj.GetStatus().State, "disallowed transitions are reverted")
+	assert.Equal(t, "state transition not allowed: from Suspended to Processing", obj.GetStatus().Operation)
+
+	handlerErr := errors.New("dependency missing")
+	obj = newObj(stateUpgrading)
+	_, err = reconciler.handleCustomState(ctx, nil, obj, CustomState{
+		State: stateUpgrading, Handler: moveTo(stateUpgrading, handlerErr),
+	})
+	assert.ErrorIs(t, err, handlerErr)
+	assert.Equal(t, stateUpgrading, obj.GetStatus().State)
+	assert.Equal(t, handlerErr.Error(), obj.GetStatus().Operation)
+}
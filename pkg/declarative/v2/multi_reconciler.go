@@ -0,0 +1,104 @@
+package v2
+
+import (
+	"fmt"
+	"strings"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/kyma-project/module-manager/pkg/predicates"
+)
+
+// MultiReconcilerBuilder registers a Reconciler for each of multiple prototypes of different GVKs
+// with a single manager. All Reconcilers share the same Options and ClientCache,
+// so that one operator binary can drive several module CRDs.
+// Each GVK gets its own controller and work queue, which can be tuned per GVK with WithOptionsFor.
+type MultiReconcilerBuilder struct {
+	prototypes        []Object
+	options           []Option
+	gvkOptions        map[Object][]Option
+	controllerOptions controller.Options
+}
+
+// ForEachGVK starts a MultiReconcilerBuilder for the passed prototypes.
+// Every prototype must be registered in the scheme of the manager passed in Complete.
+func ForEachGVK(prototypes ...Object) *MultiReconcilerBuilder {
+	return &MultiReconcilerBuilder{prototypes: prototypes, gvkOptions: make(map[Object][]Option)}
+}
+
+// WithOptions adds Options applied to every Reconciler.
+func (b *MultiReconcilerBuilder) WithOptions(options ...Option) *MultiReconcilerBuilder {
+	b.options = append(b.options, options...)
+	return b
+}
+
+// WithOptionsFor adds Options only applied to the Reconciler of the passed prototype, after the shared Options.
+// This allows e.g. a dedicated WithRateLimiter or WithMaxConcurrentReconciles for a module with heavy installations.
+func (b *MultiReconcilerBuilder) WithOptionsFor(prototype Object, options ...Option) *MultiReconcilerBuilder {
+	b.gvkOptions[prototype] = append(b.gvkOptions[prototype], options...)
+	return b
+}
+
+// WithControllerOptions sets the controller.Options used for every controller.
+// MaxConcurrentReconciles and RateLimiter set with Options take precedence.
+func (b *MultiReconcilerBuilder) WithControllerOptions(options controller.Options) *MultiReconcilerBuilder {
+	b.controllerOptions = options
+	return b
+}
+
+// Complete creates a Reconciler and a controller for every prototype and registers them with the manager.
+func (b *MultiReconcilerBuilder) Complete(mgr manager.Manager) error {
+	// the client cache is shared so that target clusters are only connected once for all GVKs,
+	// options passed by the user are applied afterwards and can still override it.
+	options := append([]Option{WithSingletonClientCache(NewMemorySingletonClientCache())}, b.options...)
+
+	controllerNames := make(map[string]struct{}, len(b.prototypes))
+	for _, prototype := range b.prototypes {
+		gvk, err := apiutil.GVKForObject(prototype, mgr.GetScheme())
+		if err != nil {
+			return fmt.Errorf("resolving GVK for declarative reconciler: %w", err)
+		}
+		name := strings.ToLower(fmt.Sprintf("%s.%s", gvk.Kind, gvk.Group))
+		if _, duplicate := controllerNames[name]; duplicate {
+			return fmt.Errorf("declarative reconciler for %s registered more than once", gvk)
+		}
+		controllerNames[name] = struct{}{}
+
+		gvkOptions := append(append([]Option{}, options...), b.gvkOptions[prototype]...)
+		resolved := (&Options{}).Apply(gvkOptions...)
+		if err := ctrl.NewControllerManagedBy(mgr).
+			Named(name).
+			For(prototype, builder.WithPredicates(ControllerPredicates(resolved)...)).
+			WithOptions(ControllerOptions(b.controllerOptions, resolved)).
+			Complete(NewFromManager(mgr, prototype, gvkOptions...)); err != nil {
+			return fmt.Errorf("creating declarative controller for %s: %w", gvk, err)
+		}
+	}
+	return nil
+}
+
+// ControllerPredicates returns the predicates set with WithPredicates. Without any, status updates and objects
+// skipped with DefaultSkipReconcileLabel are filtered with predicates.Default, as the Reconciler requeues
+// itself after every status update.
+func ControllerPredicates(options *Options) []predicate.Predicate {
+	if len(options.Predicates) > 0 {
+		return options.Predicates
+	}
+	return []predicate.Predicate{predicates.Default(DefaultSkipReconcileLabel)}
+}
+
+// ControllerOptions returns the passed controller.Options overridden by the controller settings of the Options.
+func ControllerOptions(base controller.Options, options *Options) controller.Options {
+	if options.MaxConcurrentReconciles > 0 {
+		base.MaxConcurrentReconciles = options.MaxConcurrentReconciles
+	}
+	if options.RateLimiter != nil {
+		base.RateLimiter = options.RateLimiter
+	}
+	return base
+}
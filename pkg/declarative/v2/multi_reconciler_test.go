@@ -0,0 +1,97 @@
+//nolint:testpackage
+package v2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+func Test_ControllerOptions(t *testing.T) {
+	t.Parallel()
+	base := controller.Options{MaxConcurrentReconciles: 2, CacheSyncTimeout: time.Minute}
+
+	assert.Equal(t, base, ControllerOptions(base, (&Options{}).Apply()))
+
+	limiter := workqueue.NewItemExponentialFailureRateLimiter(time.Second, time.Minute)
+	overridden := ControllerOptions(base, (&Options{}).Apply(WithMaxConcurrentReconciles(10), WithRateLimiter(limiter)))
+	assert.Equal(t, 10, overridden.MaxConcurrentReconciles)
+	assert.Equal(t, limiter, overridden.RateLimiter)
+	assert.Equal(t, time.Minute, overridden.CacheSyncTimeout)
+}
+
+// otherTestObj is a second typed Object, so that reconcilers of different GVKs can be registered.
+type otherTestObj struct {
+	reconcilerTestObj
+}
+
+func (o *otherTestObj) DeepCopyObject() runtime.Object {
+	out := &otherTestObj{}
+	out.TypeMeta = o.TypeMeta
+	o.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	o.Status.DeepCopyInto(&out.Status)
+	return out
+}
+
+// recordingOption records the client cache of every Options it is applied to.
+type recordingOption struct {
+	caches *[]ClientCache
+}
+
+func (o recordingOption) Apply(options *Options) {
+	*o.caches = append(*o.caches, options.ClientCache)
+}
+
+func newTestManager(t *testing.T) manager.Manager {
+	t.Helper()
+	testScheme := runtime.NewScheme()
+	testScheme.AddKnownTypeWithName(
+		schema.GroupVersionKind{Group: "test.declarative.kyma-project.io", Version: "v1", Kind: "TestAPI"},
+		&reconcilerTestObj{},
+	)
+	testScheme.AddKnownTypeWithName(
+		schema.GroupVersionKind{Group: "test.declarative.kyma-project.io", Version: "v1", Kind: "OtherTestAPI"},
+		&otherTestObj{},
+	)
+	mgr, err := ctrl.NewManager(&rest.Config{Host: "https://localhost:0"}, ctrl.Options{
+		Scheme:             testScheme,
+		MetricsBindAddress: "0",
+		MapperProvider: func(*rest.Config) (meta.RESTMapper, error) {
+			return meta.NewDefaultRESTMapper(nil), nil
+		},
+	})
+	require.NoError(t, err)
+	return mgr
+}
+
+func Test_ForEachGVK(t *testing.T) {
+	t.Parallel()
+	var shared, dedicated []ClientCache
+	prototype, other := &reconcilerTestObj{}, &otherTestObj{}
+	err := ForEachGVK(prototype, other).
+		WithOptions(recordingOption{caches: &shared}).
+		WithOptionsFor(other, recordingOption{caches: &dedicated}).
+		Complete(newTestManager(t))
+	require.NoError(t, err)
+
+	// the options are resolved once for the controller and once for the reconciler of every GVK
+	require.Len(t, shared, 4, "shared options are applied for both GVKs")
+	require.Len(t, dedicated, 2, "options of a GVK are only applied for its reconciler")
+	assert.NotNil(t, shared[0])
+	for _, cache := range append(shared, dedicated...) {
+		assert.True(t, cache == shared[0], "all reconcilers share the same client cache")
+	}
+
+	err = ForEachGVK(&reconcilerTestObj{}, &reconcilerTestObj{}).Complete(newTestManager(t))
+	assert.ErrorContains(t, err, "registered more than once")
+}
@@ -101,14 +101,20 @@ func (r Resource) ID() string {
 type LastOperation struct {
 	Operation      string      `json:"operation"`
 	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+	// CorrelationID identifies the operation across events, logs and the resources applied to the target cluster
+	CorrelationID string `json:"correlationID,omitempty"`
 }
 
 func (s Status) WithErr(err error) Status {
-	s.LastOperation = LastOperation{Operation: err.Error(), LastUpdateTime: metav1.NewTime(time.Now())}
+	s.LastOperation = LastOperation{
+		Operation: err.Error(), LastUpdateTime: metav1.NewTime(time.Now()), CorrelationID: s.CorrelationID,
+	}
 	return s
 }
 
 func (s Status) WithOperation(operation string) Status {
-	s.LastOperation = LastOperation{Operation: operation, LastUpdateTime: metav1.NewTime(time.Now())}
+	s.LastOperation = LastOperation{
+		Operation: operation, LastUpdateTime: metav1.NewTime(time.Now()), CorrelationID: s.CorrelationID,
+	}
 	return s
 }
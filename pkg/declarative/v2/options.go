@@ -15,6 +15,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
 )
 
 const (
@@ -22,6 +24,7 @@ const (
 	FieldOwnerDefault         = "declarative.kyma-project.io/applier"
 	EventRecorderDefault      = "declarative.kyma-project.io/events"
 	DefaultSkipReconcileLabel = "declarative.kyma-project.io/skip-reconciliation"
+	ProgressIntervalDefault   = 10 * time.Second
 )
 
 func DefaultOptions() *Options {
@@ -34,11 +37,13 @@ func DefaultOptions() *Options {
 			managedByDeclarativeV2,
 			kymaComponentTransform,
 			disclaimerTransform,
+			correlationIDTransform,
 		),
 		WithPermanentConsistencyCheck(false),
 		WithSingletonClientCache(NewMemorySingletonClientCache()),
 		WithManifestCache(os.TempDir()),
 		WithSkipReconcileOn(SkipReconcileOnDefaultLabelPresentAndTrue),
+		WithProgressInterval(ProgressIntervalDefault),
 	)
 }
 
@@ -60,17 +65,36 @@ type Options struct {
 
 	ServerSideApply bool
 	FieldOwner      client.FieldOwner
+	// SSABatchSize enables applying resources in batches ordered by ApplyPriority, see BatchedSSA
+	SSABatchSize int
+	// TargetQPS and TargetBurst limit the requests of the clients created for the target cluster
+	TargetQPS   float32
+	TargetBurst int
+	// ProgressInterval is the interval in which the last operation reports the progress of an apply, see Progress
+	ProgressInterval time.Duration
 
 	PostRenderTransforms []ObjectTransform
 
-	PostRuns   []PostRun
-	PreDeletes []PreDelete
+	PostRuns            []PostRun
+	PreDeletes          []PreDelete
+	PreDeleteFinalizers []PreDeleteFinalizer
+	PreInstalls         []PreInstall
+	PostInstalls        []PostInstall
 
 	DeletePrerequisites bool
 
 	ShouldSkip SkipReconcile
 
 	CtrlOnSuccess ctrl.Result
+
+	// CustomStates are reconciled by their handlers instead of the built-in flow, see CustomState
+	CustomStates map[State]CustomState
+
+	// MaxConcurrentReconciles, RateLimiter and Predicates configure the controller of the Reconciler
+	// when it is registered with MultiReconcilerBuilder. Unset values keep the controller defaults.
+	MaxConcurrentReconciles int
+	RateLimiter             ratelimiter.RateLimiter
+	Predicates              []predicate.Predicate
 }
 
 type Option interface {
@@ -186,6 +210,12 @@ type (
 	PostRun Hook
 	// PreDelete is executed before any deletion of resources calculated from the status.
 	PreDelete Hook
+	// PreInstall is executed after rendering, but before any resources are applied to the cluster.
+	// It is only executed while the object is installed or upgraded, i.e. until it is ready for its generation.
+	PreInstall Hook
+	// PostInstall is executed once all resources are ready, before the object transitions into StateReady.
+	// It is executed once per installed or upgraded generation of the object.
+	PostInstall Hook
 )
 
 // WithPostRun applies PostRun.
@@ -202,6 +232,54 @@ func (o WithPreDelete) Apply(options *Options) {
 	options.PreDeletes = append(options.PreDeletes, o...)
 }
 
+// WithPreInstallHook applies PreInstall hooks, which are executed in order.
+func WithPreInstallHook(hooks ...PreInstall) WithPreInstallHookOption {
+	return hooks
+}
+
+type WithPreInstallHookOption []PreInstall
+
+func (o WithPreInstallHookOption) Apply(options *Options) {
+	options.PreInstalls = append(options.PreInstalls, o...)
+}
+
+// WithPostInstallHook applies PostInstall hooks, which are executed in order.
+func WithPostInstallHook(hooks ...PostInstall) WithPostInstallHookOption {
+	return hooks
+}
+
+type WithPostInstallHookOption []PostInstall
+
+func (o WithPostInstallHookOption) Apply(options *Options) {
+	options.PostInstalls = append(options.PostInstalls, o...)
+}
+
+// WithPreDeleteHook applies PreDelete hooks, which are executed in order.
+func WithPreDeleteHook(hooks ...PreDelete) WithPreDelete {
+	return hooks
+}
+
+// PreDeleteFinalizer is a PreDelete guarded by its own finalizer on the reconciled object.
+// Contrary to a plain PreDelete, it is executed at most once: after it succeeded, its finalizer is removed.
+type PreDeleteFinalizer struct {
+	Name string
+	PreDelete
+}
+
+// WithPreDeleteFinalizer registers a PreDeleteFinalizer. All PreDeleteFinalizers are executed in the order
+// of their registration before any resources are deleted and before the Finalizer is removed.
+func WithPreDeleteFinalizer(name string, preDelete PreDelete) WithPreDeleteFinalizerOption {
+	return WithPreDeleteFinalizerOption{PreDeleteFinalizer{Name: name, PreDelete: preDelete}}
+}
+
+type WithPreDeleteFinalizerOption struct {
+	PreDeleteFinalizer
+}
+
+func (o WithPreDeleteFinalizerOption) Apply(options *Options) {
+	options.PreDeleteFinalizers = append(options.PreDeleteFinalizers, o.PreDeleteFinalizer)
+}
+
 type WithPeriodicConsistencyCheck time.Duration
 
 func (o WithPeriodicConsistencyCheck) Apply(options *Options) {
@@ -292,3 +370,69 @@ type WithSkipReconcileOnOption struct {
 func (o WithSkipReconcileOnOption) Apply(options *Options) {
 	options.ShouldSkip = o.skipReconcile
 }
+
+// WithMaxConcurrentReconciles sets the maximum number of objects of a GVK reconciled in parallel.
+type WithMaxConcurrentReconciles int
+
+func (o WithMaxConcurrentReconciles) Apply(options *Options) {
+	options.MaxConcurrentReconciles = int(o)
+}
+
+// WithRateLimiter sets the rate limiter of the work queue of the controller,
+// e.g. workqueue.NewItemExponentialFailureRateLimiter for a module with slow installations.
+func WithRateLimiter(limiter ratelimiter.RateLimiter) WithRateLimiterOption {
+	return WithRateLimiterOption{RateLimiter: limiter}
+}
+
+type WithRateLimiterOption struct {
+	ratelimiter.RateLimiter
+}
+
+func (o WithRateLimiterOption) Apply(options *Options) {
+	options.RateLimiter = o.RateLimiter
+}
+
+// WithPredicates adds predicates filtering the events of the reconciled objects before they are queued.
+// They replace the default predicates, see ControllerPredicates.
+func WithPredicates(predicates ...predicate.Predicate) WithPredicatesOption {
+	return predicates
+}
+
+type WithPredicatesOption []predicate.Predicate
+
+func (o WithPredicatesOption) Apply(options *Options) {
+	options.Predicates = append(options.Predicates, o...)
+}
+
+// WithSSABatchSize applies resources in batches of at most the passed size, ordered by ApplyPriority,
+// e.g. for large charts hammering the target API server. Zero applies all resources concurrently.
+type WithSSABatchSize int
+
+func (o WithSSABatchSize) Apply(options *Options) {
+	options.SSABatchSize = int(o)
+}
+
+// WithTargetRateLimit limits the requests per second and the burst of the clients created for the target cluster,
+// using a copy of the REST config. Unset values keep the limits of the REST config. Clients passed with
+// WithRemoteTargetCluster keep their own limits.
+func WithTargetRateLimit(qps float32, burst int) WithTargetRateLimitOption {
+	return WithTargetRateLimitOption{QPS: qps, Burst: burst}
+}
+
+type WithTargetRateLimitOption struct {
+	QPS   float32
+	Burst int
+}
+
+func (o WithTargetRateLimitOption) Apply(options *Options) {
+	options.TargetQPS = o.QPS
+	options.TargetBurst = o.Burst
+}
+
+// WithProgressInterval reports the progress of applies in the last operation of the object in the passed interval,
+// e.g. "applied 45/120 resources". Zero disables the reports.
+type WithProgressInterval time.Duration
+
+func (o WithProgressInterval) Apply(options *Options) {
+	options.ProgressInterval = time.Duration(o)
+}
@@ -0,0 +1,78 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type progressKey struct{}
+
+// Progress counts the resources applied by an SSA, so that the progress of long applies can be reported.
+type Progress struct {
+	total   int
+	applied int64
+}
+
+func NewProgress(total int) *Progress {
+	return &Progress{total: total}
+}
+
+// Applied records an applied resource.
+func (p *Progress) Applied() {
+	atomic.AddInt64(&p.applied, 1)
+}
+
+func (p *Progress) String() string {
+	return fmt.Sprintf("applied %d/%d resources", atomic.LoadInt64(&p.applied), p.total)
+}
+
+// WithProgress returns a context carrying the Progress, which is updated by the SSA running with the context.
+func WithProgress(ctx context.Context, progress *Progress) context.Context {
+	return context.WithValue(ctx, progressKey{}, progress)
+}
+
+// ProgressFromContext returns the Progress of the context, or nil if none is set.
+func ProgressFromContext(ctx context.Context) *Progress {
+	progress, _ := ctx.Value(progressKey{}).(*Progress)
+	return progress
+}
+
+// reportProgress updates the last operation of the object with the Progress in every ProgressInterval,
+// so that users watching the object see long applies moving forward. The object is not modified,
+// the returned function stops the reports and has to be called before the object is modified again.
+func (r *Reconciler) reportProgress(ctx context.Context, obj Object, progress *Progress) func() {
+	if r.ProgressInterval <= 0 {
+		return func() {}
+	}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(r.ProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				heartbeat := obj.DeepCopyObject().(Object)
+				heartbeat.SetStatus(heartbeat.GetStatus().WithOperation(progress.String()))
+				if _, err := r.ssaStatus(ctx, heartbeat); err != nil {
+					log.FromContext(ctx).Error(err, "could not report progress", "progress", progress.String())
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
@@ -0,0 +1,41 @@
+package v2_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	. "github.com/kyma-project/module-manager/pkg/declarative/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgress(t *testing.T) {
+	t.Parallel()
+	progress := NewProgress(120)
+	ctx := WithProgress(context.Background(), progress)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 45; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ProgressFromContext(ctx).Applied()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, "applied 45/120 resources", progress.String())
+	assert.Nil(t, ProgressFromContext(context.Background()))
+}
+
+func TestResourceNotReadyError(t *testing.T) {
+	t.Parallel()
+	err := fmt.Errorf("ready check: %w", &ResourceNotReadyError{Kind: "Deployment", Name: "default/redis"})
+
+	assert.ErrorIs(t, err, ErrResourcesNotReady)
+	var notReady *ResourceNotReadyError
+	assert.True(t, errors.As(err, &notReady))
+	assert.Equal(t, "resources are not ready: Deployment default/redis", notReady.Error())
+}
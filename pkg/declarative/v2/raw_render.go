@@ -2,6 +2,7 @@ package v2
 
 import (
 	"context"
+	"io"
 	"os"
 
 	"k8s.io/client-go/tools/record"
@@ -41,6 +42,17 @@ func (r *RawRenderer) Render(_ context.Context, obj Object) ([]byte, error) {
 	return manifest, nil
 }
 
+func (r *RawRenderer) RenderStream(_ context.Context, obj Object) (io.ReadCloser, error) {
+	status := obj.GetStatus()
+	manifest, err := os.Open(r.Path)
+	if err != nil {
+		r.Event(obj, "Warning", "ReadRawManifest", err.Error())
+		obj.SetStatus(status.WithState(StateError).WithErr(err))
+		return nil, err
+	}
+	return manifest, nil
+}
+
 func (r *RawRenderer) RemovePrerequisites(_ context.Context, _ Object) error {
 	return nil
 }
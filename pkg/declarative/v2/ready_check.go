@@ -17,6 +17,28 @@ import (
 
 var ErrResourcesNotReady = errors.New("resources are not ready")
 
+// ResourceNotReadyError identifies a resource found not ready by a ReadyCheck, it matches ErrResourcesNotReady.
+type ResourceNotReadyError struct {
+	Kind string
+	Name string
+}
+
+func (e *ResourceNotReadyError) Error() string {
+	return fmt.Sprintf("%s: %s %s", ErrResourcesNotReady, e.Kind, e.Name)
+}
+
+func (e *ResourceNotReadyError) Is(target error) bool {
+	return target == ErrResourcesNotReady
+}
+
+func newResourceNotReadyError(info *resource.Info) *ResourceNotReadyError {
+	name := info.Name
+	if info.Namespace != "" {
+		name = info.Namespace + "/" + info.Name
+	}
+	return &ResourceNotReadyError{Kind: info.Object.GetObjectKind().GroupVersionKind().Kind, Name: name}
+}
+
 type ReadyCheck interface {
 	Run(ctx context.Context, resources []*resource.Info) error
 }
@@ -49,7 +71,7 @@ func (c *HelmReadyCheck) Run(ctx context.Context, resources []*resource.Info) er
 	isReady := func(ctx context.Context, i int) {
 		ready, err := checker.IsReady(ctx, resources[i])
 		if !ready {
-			readyCheckResults <- ErrResourcesNotReady
+			readyCheckResults <- newResourceNotReadyError(resources[i])
 		} else {
 			readyCheckResults <- err
 		}
@@ -1,8 +1,11 @@
 package v2
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"io"
 
 	manifestClient "github.com/kyma-project/module-manager/pkg/client"
 	manifestLabels "github.com/kyma-project/module-manager/pkg/labels"
@@ -13,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -85,11 +89,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
+	ctx = WithCorrelationID(ctx, CorrelationID(obj))
+
 	if err := r.initialize(obj); err != nil {
 		return r.ssaStatus(ctx, obj)
 	}
 
-	if controllerutil.AddFinalizer(obj, r.Finalizer) {
+	// finalizers cannot be added to objects being deleted, so that removed PreDeleteFinalizers stay removed
+	if obj.GetDeletionTimestamp().IsZero() && r.addFinalizers(obj) {
 		return r.ssa(ctx, obj)
 	}
 
@@ -100,11 +107,30 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	clnt, err := r.getTargetClient(ctx, obj, spec)
 	if err != nil {
-		r.Event(obj, "Warning", "ClientInitialization", err.Error())
+		r.event(ctx, obj, "Warning", "ClientInitialization", err.Error())
 		obj.SetStatus(obj.GetStatus().WithState(StateError).WithErr(err))
 		return r.ssaStatus(ctx, obj)
 	}
 
+	if customState, found := r.CustomStates[obj.GetStatus().State]; found {
+		result, err := r.handleCustomState(ctx, clnt, obj, customState)
+		if err != nil {
+			return r.ssaStatus(ctx, obj)
+		}
+		if _, err := r.ssaStatus(ctx, obj); err != nil {
+			return ctrl.Result{}, err
+		}
+		return result, nil
+	}
+
+	if !obj.GetDeletionTimestamp().IsZero() {
+		if removed, err := r.runPreDeleteFinalizers(ctx, clnt, obj); err != nil {
+			return r.ssaStatus(ctx, obj)
+		} else if removed {
+			return ctrl.Result{}, r.Update(ctx, obj) // no SSA since delete does not work for finalizers.
+		}
+	}
+
 	converter := NewResourceToInfoConverter(clnt, r.Namespace)
 
 	renderer, err := r.initializeRenderer(ctx, obj, spec, clnt)
@@ -129,7 +155,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			return ctrl.Result{}, r.Update(ctx, obj) // no SSA since delete does not work for finalizers.
 		}
 		msg := "waiting as other finalizers are present"
-		r.Event(obj, "Normal", "FinalizerRemoval", msg)
+		r.event(ctx, obj, "Normal", "FinalizerRemoval", msg)
 		obj.SetStatus(obj.GetStatus().WithState(StateDeleting).WithOperation(msg))
 		return r.ssaStatus(ctx, obj)
 	}
@@ -141,6 +167,33 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	return r.CtrlOnSuccess, nil
 }
 
+// addFinalizers adds the Finalizer and the finalizers of all PreDeleteFinalizers to the object.
+func (r *Reconciler) addFinalizers(obj Object) bool {
+	added := controllerutil.AddFinalizer(obj, r.Finalizer)
+	for _, preDeleteFinalizer := range r.PreDeleteFinalizers {
+		added = controllerutil.AddFinalizer(obj, preDeleteFinalizer.Name) || added
+	}
+	return added
+}
+
+// runPreDeleteFinalizers runs the first PreDeleteFinalizer that is still present on the object
+// and removes its finalizer on success. It returns true if a finalizer was removed,
+// in which case the object needs to be updated before the next PreDeleteFinalizer is run.
+func (r *Reconciler) runPreDeleteFinalizers(ctx context.Context, clnt Client, obj Object) (bool, error) {
+	for _, preDeleteFinalizer := range r.PreDeleteFinalizers {
+		if !controllerutil.ContainsFinalizer(obj, preDeleteFinalizer.Name) {
+			continue
+		}
+		if err := preDeleteFinalizer.PreDelete(ctx, clnt, r.Client, obj); err != nil {
+			r.event(ctx, obj, "Warning", "PreDeleteFinalizer", err.Error())
+			obj.SetStatus(obj.GetStatus().WithState(StateDeleting).WithErr(err))
+			return false, err
+		}
+		return controllerutil.RemoveFinalizer(obj, preDeleteFinalizer.Name), nil
+	}
+	return false, nil
+}
+
 func (r *Reconciler) initialize(obj Object) error {
 	status := obj.GetStatus()
 
@@ -149,11 +202,14 @@ func (r *Reconciler) initialize(obj Object) error {
 		return ErrDeletionTimestampSetButNotInDeletingState
 	}
 
+	// existing conditions are kept, so that the installation of the last reconciliation stays observable
 	for _, condition := range []metav1.Condition{
 		newResourcesCondition(obj),
 		newInstallationCondition(obj),
 	} {
-		meta.SetStatusCondition(&status.Conditions, condition)
+		if meta.FindStatusCondition(status.Conditions, condition.Type) == nil {
+			meta.SetStatusCondition(&status.Conditions, condition)
+		}
 	}
 
 	if status.Synced == nil {
@@ -173,7 +229,7 @@ func (r *Reconciler) initialize(obj Object) error {
 func (r *Reconciler) Spec(ctx context.Context, obj Object) (*Spec, error) {
 	spec, err := r.SpecResolver.Spec(ctx, obj)
 	if err != nil {
-		r.Event(obj, "Warning", "Spec", err.Error())
+		r.event(ctx, obj, "Warning", "Spec", err.Error())
 		obj.SetStatus(obj.GetStatus().WithState(StateError).WithErr(err))
 	}
 	return spec, err
@@ -194,13 +250,13 @@ func (r *Reconciler) renderResources(
 
 	current, err = converter.ResourcesToInfos(status.Synced)
 	if err != nil {
-		r.Event(obj, "Warning", "CurrentResourceParsing", err.Error())
+		r.event(ctx, obj, "Warning", "CurrentResourceParsing", err.Error())
 		obj.SetStatus(status.WithState(StateError).WithErr(err))
 		return nil, nil, err
 	}
 
 	if !meta.IsStatusConditionTrue(status.Conditions, resourceCondition.Type) {
-		r.Event(obj, "Normal", resourceCondition.Reason, resourceCondition.Message)
+		r.event(ctx, obj, "Normal", resourceCondition.Reason, resourceCondition.Message)
 		resourceCondition.Status = metav1.ConditionTrue
 		meta.SetStatusCondition(&status.Conditions, resourceCondition)
 		obj.SetStatus(status.WithOperation(resourceCondition.Message))
@@ -214,8 +270,27 @@ func (r *Reconciler) syncResources(
 ) error {
 	status := obj.GetStatus()
 
-	if err := ConcurrentSSA(clnt, r.FieldOwner).Run(ctx, target); err != nil {
-		r.Event(obj, "Warning", "ServerSideApply", err.Error())
+	// resources of installed objects are only synced, PreInstalls are executed for installs and upgrades
+	if installationRequired(obj) {
+		for i := range r.PreInstalls {
+			if err := r.PreInstalls[i](ctx, clnt, r.Client, obj); err != nil {
+				r.event(ctx, obj, "Warning", "PreInstall", err.Error())
+				obj.SetStatus(status.WithState(StateError).WithErr(err))
+				return err
+			}
+		}
+	}
+
+	ssa := ConcurrentSSA(clnt, r.FieldOwner)
+	if r.SSABatchSize > 0 {
+		ssa = BatchedSSA(clnt, r.FieldOwner, r.SSABatchSize)
+	}
+	progress := NewProgress(len(target))
+	stopProgress := r.reportProgress(ctx, obj, progress)
+	err := ssa.Run(WithProgress(ctx, progress), target)
+	stopProgress()
+	if err != nil {
+		r.event(ctx, obj, "Warning", "ServerSideApply", err.Error())
 		obj.SetStatus(status.WithState(StateError).WithErr(err))
 		return err
 	}
@@ -231,7 +306,7 @@ func (r *Reconciler) syncResources(
 
 	for i := range r.PostRuns {
 		if err := r.PostRuns[i](ctx, clnt, r.Client, obj); err != nil {
-			r.Event(obj, "Warning", "PostRun", err.Error())
+			r.event(ctx, obj, "Warning", "PostRun", err.Error())
 			obj.SetStatus(status.WithState(StateError).WithErr(err))
 			return err
 		}
@@ -252,18 +327,29 @@ func (r *Reconciler) checkTargetReadiness(
 
 	if err := resourceReadyCheck.Run(ctx, target); errors.Is(err, ErrResourcesNotReady) {
 		waitingMsg := "waiting for resources to become ready"
-		r.Event(obj, "Normal", "ResourceReadyCheck", waitingMsg)
+		var notReady *ResourceNotReadyError
+		if errors.As(err, &notReady) {
+			waitingMsg = fmt.Sprintf("waiting for %s %s", notReady.Kind, notReady.Name)
+		}
+		r.event(ctx, obj, "Normal", "ResourceReadyCheck", waitingMsg)
 		obj.SetStatus(status.WithState(StateProcessing).WithOperation(waitingMsg))
 		return err
 	} else if err != nil {
-		r.Event(obj, "Warning", "ReadyCheck", err.Error())
+		r.event(ctx, obj, "Warning", "ReadyCheck", err.Error())
 		obj.SetStatus(status.WithState(StateError).WithErr(err))
 		return err
 	}
 
 	installationCondition := newInstallationCondition(obj)
-	if !meta.IsStatusConditionTrue(status.Conditions, installationCondition.Type) || status.State != StateReady {
-		r.Event(obj, "Normal", installationCondition.Reason, installationCondition.Message)
+	if installationRequired(obj) {
+		for i := range r.PostInstalls {
+			if err := r.PostInstalls[i](ctx, clnt, r.Client, obj); err != nil {
+				r.event(ctx, obj, "Warning", "PostInstall", err.Error())
+				obj.SetStatus(status.WithState(StateError).WithErr(err))
+				return err
+			}
+		}
+		r.event(ctx, obj, "Normal", installationCondition.Reason, installationCondition.Message)
 		installationCondition.Status = metav1.ConditionTrue
 		meta.SetStatusCondition(&status.Conditions, installationCondition)
 		obj.SetStatus(status.WithState(StateReady).WithOperation(installationCondition.Message))
@@ -273,6 +359,15 @@ func (r *Reconciler) checkTargetReadiness(
 	return nil
 }
 
+// installationRequired indicates if the object is installed or upgraded, i.e. if it is not Ready
+// or its installation did not observe its current generation.
+func installationRequired(obj Object) bool {
+	status := obj.GetStatus()
+	installation := meta.FindStatusCondition(status.Conditions, string(ConditionTypeInstallation))
+	return status.State != StateReady || installation == nil || installation.Status != metav1.ConditionTrue ||
+		installation.ObservedGeneration != obj.GetGeneration()
+}
+
 func (r *Reconciler) deleteResources(
 	ctx context.Context, clnt Client, obj Object, diff []*resource.Info,
 ) error {
@@ -281,7 +376,7 @@ func (r *Reconciler) deleteResources(
 	if !obj.GetDeletionTimestamp().IsZero() {
 		for _, preDelete := range r.PreDeletes {
 			if err := preDelete(ctx, clnt, r.Client, obj); err != nil {
-				r.Event(obj, "Warning", "PreDelete", err.Error())
+				r.event(ctx, obj, "Warning", "PreDelete", err.Error())
 				// we do not set a status here since it will be deleting if timestamp is set to zero.
 				return err
 			}
@@ -289,10 +384,10 @@ func (r *Reconciler) deleteResources(
 	}
 
 	if err := NewConcurrentCleanup(clnt).Run(ctx, diff); errors.Is(err, ErrDeletionNotFinished) {
-		r.Event(obj, "Normal", "Deletion", ErrDeletionNotFinished.Error())
+		r.event(ctx, obj, "Normal", "Deletion", ErrDeletionNotFinished.Error())
 		return err
 	} else if err != nil {
-		r.Event(obj, "Warning", "Deletion", err.Error())
+		r.event(ctx, obj, "Warning", "Deletion", err.Error())
 		obj.SetStatus(status.WithState(StateError).WithErr(err))
 		return err
 	}
@@ -300,6 +395,37 @@ func (r *Reconciler) deleteResources(
 	return nil
 }
 
+// decodeTargetResources decodes the manifest of the renderer. Manifests of StreamRenderers are decoded
+// document by document while they are read, so that only the decoded objects are kept in memory.
+func (r *Reconciler) decodeTargetResources(
+	ctx context.Context, renderer Renderer, obj Object,
+) (*types.ManifestResources, error) {
+	var manifest io.Reader
+	if streamRenderer, ok := renderer.(StreamRenderer); ok {
+		stream, err := streamRenderer.RenderStream(ctx, obj)
+		if err != nil {
+			return nil, err
+		}
+		defer stream.Close()
+		manifest = stream
+	} else {
+		rendered, err := renderer.Render(ctx, obj)
+		if err != nil {
+			return nil, err
+		}
+		manifest = bytes.NewReader(rendered)
+	}
+
+	targetResources, err := util.ParseManifestToObjects(manifest)
+	if err != nil {
+		status := obj.GetStatus()
+		r.event(ctx, obj, "Warning", "ManifestParsing", err.Error())
+		obj.SetStatus(status.WithState(StateError).WithErr(err))
+		return nil, err
+	}
+	return targetResources, nil
+}
+
 func (r *Reconciler) renderTargetResources(
 	ctx context.Context, renderer Renderer, converter ResourceToInfoConverter, obj Object,
 ) ([]*resource.Info, error) {
@@ -313,21 +439,14 @@ func (r *Reconciler) renderTargetResources(
 
 	status := obj.GetStatus()
 
-	manifest, err := renderer.Render(ctx, obj)
+	targetResources, err := r.decodeTargetResources(ctx, renderer, obj)
 	if err != nil {
 		return nil, err
 	}
 
-	targetResources, err := util.ParseManifestStringToObjects(string(manifest))
-	if err != nil {
-		r.Event(obj, "Warning", "ManifestParsing", err.Error())
-		obj.SetStatus(status.WithState(StateError).WithErr(err))
-		return nil, err
-	}
-
 	for _, transform := range r.PostRenderTransforms {
 		if err := transform(ctx, obj, targetResources.Items); err != nil {
-			r.Event(obj, "Warning", "PostRenderTransform", err.Error())
+			r.event(ctx, obj, "Warning", "PostRenderTransform", err.Error())
 			obj.SetStatus(status.WithState(StateError).WithErr(err))
 			return nil, err
 		}
@@ -335,7 +454,7 @@ func (r *Reconciler) renderTargetResources(
 
 	target, err := converter.UnstructuredToInfos(targetResources.Items)
 	if err != nil {
-		r.Event(obj, "Warning", "TargetResourceParsing", err.Error())
+		r.event(ctx, obj, "Warning", "TargetResourceParsing", err.Error())
 		obj.SetStatus(status.WithState(StateError).WithErr(err))
 		return nil, err
 	}
@@ -388,14 +507,26 @@ func (r *Reconciler) getTargetClient(
 
 	clientsCacheKey := cacheKeyFromObject(ctx, obj)
 
+	var remoteConfig *rest.Config
+	if spec.Sync.Strategy == SyncStrategyRemote {
+		var kubeconfigHash uint32
+		if remoteConfig, kubeconfigHash, err = r.remoteConfig(ctx, obj, spec.Sync); err != nil {
+			return nil, err
+		}
+		clientsCacheKey = syncCacheKey(clientsCacheKey, kubeconfigHash)
+	}
+
 	clnt := r.GetClientFromCache(clientsCacheKey)
 
 	if clnt == nil {
 		cluster := &types.ClusterInfo{
-			Config: r.Config,
+			Config: r.targetConfig(r.Config),
 			Client: r.Client,
 		}
-		if r.TargetClient != nil {
+		if remoteConfig != nil {
+			// the client of the remote cluster is created from its config
+			cluster = &types.ClusterInfo{Config: r.targetConfig(remoteConfig)}
+		} else if r.TargetClient != nil {
 			cluster.Client, err = r.TargetClient(ctx, obj)
 		}
 		if err != nil {
@@ -417,7 +548,7 @@ func (r *Reconciler) getTargetClient(
 		if clnt.Install().Version == "" && clnt.Install().Devel {
 			clnt.Install().Version = ">0.0.0-0"
 		}
-		clnt.Install().ReleaseName = spec.ManifestName
+		clnt.Install().ReleaseName = util.NormalizeReleaseName(spec.ManifestName)
 		r.SetClientInCache(clientsCacheKey, clnt)
 	}
 
@@ -440,6 +571,21 @@ func (r *Reconciler) getTargetClient(
 	return clnt, nil
 }
 
+// targetConfig returns the passed REST config of the target cluster with the configured rate limits.
+func (r *Reconciler) targetConfig(base *rest.Config) *rest.Config {
+	if r.TargetQPS <= 0 && r.TargetBurst <= 0 {
+		return base
+	}
+	config := rest.CopyConfig(base)
+	if r.TargetQPS > 0 {
+		config.QPS = r.TargetQPS
+	}
+	if r.TargetBurst > 0 {
+		config.Burst = r.TargetBurst
+	}
+	return config
+}
+
 func cacheKeyFromObject(ctx context.Context, resource client.Object) client.ObjectKey {
 	logger := log.FromContext(ctx)
 
@@ -469,7 +615,10 @@ func cacheKeyFromObject(ctx context.Context, resource client.Object) client.Obje
 	return client.ObjectKey{Name: label, Namespace: resource.GetNamespace()}
 }
 
-func (r *Reconciler) ssaStatus(ctx context.Context, obj client.Object) (ctrl.Result, error) {
+func (r *Reconciler) ssaStatus(ctx context.Context, obj Object) (ctrl.Result, error) {
+	status := obj.GetStatus()
+	status.LastOperation.CorrelationID = CorrelationIDFromContext(ctx)
+	obj.SetStatus(status)
 	obj.SetManagedFields(nil)
 	obj.SetResourceVersion("")
 	//TODO: replace the SubResourcePatchOptions with  client.ForceOwnership, r.FieldOwner in later compatible version
@@ -0,0 +1,203 @@
+// contains internal tests that should not be exposed, thus no v2_test
+//
+//nolint:testpackage
+package v2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/kube"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// reconcilerTestObj is a typed Object, which the fake client stores with its status.
+type reconcilerTestObj struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Status            Status `json:"status,omitempty"`
+}
+
+func (o *reconcilerTestObj) ComponentName() string   { return "test-object" }
+func (o *reconcilerTestObj) GetStatus() Status       { return o.Status }
+func (o *reconcilerTestObj) SetStatus(status Status) { o.Status = status }
+
+func (o *reconcilerTestObj) DeepCopyObject() runtime.Object {
+	out := &reconcilerTestObj{TypeMeta: o.TypeMeta}
+	o.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	o.Status.DeepCopyInto(&out.Status)
+	return out
+}
+
+// reconcilerTestClient is the Client of the target cluster. Only the install configuration and the scheme
+// are available, all other methods panic, as reconciles without resources do not request the target cluster.
+type reconcilerTestClient struct {
+	Client
+	install    *action.Install
+	kubeClient *kube.Client
+}
+
+func (c *reconcilerTestClient) Install() *action.Install { return c.install }
+func (c *reconcilerTestClient) KubeClient() *kube.Client { return c.kubeClient }
+func (c *reconcilerTestClient) Scheme() *runtime.Scheme  { return scheme.Scheme }
+
+type readyCheckFunc func(ctx context.Context, resources []*resource.Info) error
+
+func (f readyCheckFunc) Run(ctx context.Context, resources []*resource.Info) error {
+	return f(ctx, resources)
+}
+
+type staticSpecResolver struct {
+	spec *Spec
+}
+
+func (s staticSpecResolver) Spec(context.Context, Object) (*Spec, error) {
+	return s.spec, nil
+}
+
+// newTestReconciler returns a Reconciler of obj with a fake client, which contains obj,
+// and a target cluster client, which is cached for obj.
+func newTestReconciler(t *testing.T, obj *reconcilerTestObj, options ...Option) *Reconciler {
+	t.Helper()
+	testScheme := runtime.NewScheme()
+	testScheme.AddKnownTypeWithName(
+		schema.GroupVersionKind{Group: "test.declarative.kyma-project.io", Version: "v1", Kind: "TestAPI"},
+		&reconcilerTestObj{},
+	)
+	reconciler := &Reconciler{prototype: &reconcilerTestObj{}, Options: DefaultOptions().Apply(
+		WithSpecResolver(staticSpecResolver{&Spec{ManifestName: "test", Mode: RenderModeRaw}}),
+	).Apply(options...)}
+	reconciler.Client = fake.NewClientBuilder().WithScheme(testScheme).WithObjects(obj).Build()
+	reconciler.EventRecorder = record.NewFakeRecorder(100)
+	reconciler.SetClientInCache(client.ObjectKeyFromObject(obj), &reconcilerTestClient{
+		install: action.NewInstall(&action.Configuration{}), kubeClient: &kube.Client{},
+	})
+	return reconciler
+}
+
+func Test_Reconciler_PreDeleteFinalizers(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	deletionTimestamp := metav1.Now()
+	obj := &reconcilerTestObj{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test", Namespace: metav1.NamespaceDefault, DeletionTimestamp: &deletionTimestamp,
+			Finalizers: []string{FinalizerDefault, "test/first", "test/second", "test/third"},
+		},
+		Status: Status{State: StateDeleting},
+	}
+	var executed []string
+	preDelete := func(name string) PreDelete {
+		return func(_ context.Context, _ Client, _ client.Client, _ Object) error {
+			executed = append(executed, name)
+			return nil
+		}
+	}
+	reconciler := newTestReconciler(t, obj,
+		WithPreDeleteFinalizer("test/first", preDelete("first")),
+		WithPreDeleteFinalizer("test/second", preDelete("second")),
+		WithPreDeleteFinalizer("test/third", preDelete("third")),
+	)
+
+	request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(obj)}
+	for reconciles := 0; reconciles < 10; reconciles++ {
+		_, err := reconciler.Reconcile(ctx, request)
+		require.NoError(t, err)
+		current := &reconcilerTestObj{}
+		if err := reconciler.Get(ctx, request.NamespacedName, current); apierrors.IsNotFound(err) {
+			break
+		}
+		assert.Subset(t, obj.GetFinalizers(), current.GetFinalizers(), "finalizers are not added during deletion")
+	}
+
+	assert.Equal(t, []string{"first", "second", "third"}, executed,
+		"each PreDeleteFinalizer is executed once in the order of registration")
+	assert.True(t, apierrors.IsNotFound(reconciler.Get(ctx, request.NamespacedName, &reconcilerTestObj{})),
+		"the object is deleted after all finalizers were removed")
+}
+
+func Test_Reconciler_InstallHooks(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	obj := &reconcilerTestObj{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: metav1.NamespaceDefault, Generation: 1},
+		Status:     Status{State: StateProcessing},
+	}
+	var preInstalls, postInstalls int
+	reconciler := newTestReconciler(t, obj,
+		WithPreInstallHook(func(_ context.Context, _ Client, _ client.Client, _ Object) error {
+			preInstalls++
+			return nil
+		}),
+		WithPostInstallHook(func(_ context.Context, _ Client, _ client.Client, _ Object) error {
+			postInstalls++
+			return nil
+		}),
+		WithCustomReadyCheck(readyCheckFunc(func(context.Context, []*resource.Info) error { return nil })),
+	)
+	clnt := reconciler.GetClientFromCache(client.ObjectKeyFromObject(obj))
+	syncResources := func() error {
+		require.NoError(t, reconciler.initialize(obj))
+		return reconciler.syncResources(ctx, clnt, obj, nil)
+	}
+
+	require.ErrorIs(t, syncResources(), ErrInstallationConditionRequiresUpdate)
+	assert.Equal(t, StateReady, obj.GetStatus().State)
+	assert.Equal(t, 1, preInstalls, "PreInstalls are executed on install")
+	assert.Equal(t, 1, postInstalls, "PostInstalls are executed on install")
+
+	require.NoError(t, syncResources())
+	require.NoError(t, syncResources())
+	assert.Equal(t, 1, preInstalls, "PreInstalls are not executed for installed objects")
+	assert.Equal(t, 1, postInstalls, "PostInstalls are not executed for installed objects")
+
+	obj.SetGeneration(2)
+	require.ErrorIs(t, syncResources(), ErrInstallationConditionRequiresUpdate)
+	assert.Equal(t, 2, preInstalls, "PreInstalls are executed on upgrade")
+	assert.Equal(t, 2, postInstalls, "PostInstalls are executed on upgrade")
+
+	obj.SetStatus(obj.GetStatus().WithState(StateError))
+	require.ErrorIs(t, syncResources(), ErrInstallationConditionRequiresUpdate)
+	assert.Equal(t, 3, preInstalls, "PreInstalls are executed until the object is ready")
+	assert.Equal(t, 3, postInstalls)
+}
+
+func Test_Reconciler_PreDeleteHooks(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	deletionTimestamp := metav1.Now()
+	obj := &reconcilerTestObj{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test", Namespace: metav1.NamespaceDefault, DeletionTimestamp: &deletionTimestamp,
+			Finalizers: []string{FinalizerDefault},
+		},
+		Status: Status{State: StateDeleting},
+	}
+	var executed []string
+	preDelete := func(name string) PreDelete {
+		return func(_ context.Context, _ Client, _ client.Client, _ Object) error {
+			executed = append(executed, name)
+			return nil
+		}
+	}
+	reconciler := newTestReconciler(t, obj, WithPreDeleteHook(preDelete("first"), preDelete("second")))
+
+	request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(obj)}
+	_, err := reconciler.Reconcile(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, executed, "PreDeletes are executed in order")
+	assert.True(t, apierrors.IsNotFound(reconciler.Get(ctx, request.NamespacedName, &reconcilerTestObj{})),
+		"the object is deleted after its resources were deleted")
+}
@@ -3,6 +3,7 @@ package v2
 import (
 	"context"
 	"errors"
+	"io"
 
 	"k8s.io/cli-runtime/pkg/resource"
 )
@@ -22,3 +23,10 @@ type Renderer interface {
 	Render(ctx context.Context, obj Object) ([]byte, error)
 	RemovePrerequisites(ctx context.Context, obj Object) error
 }
+
+// StreamRenderer is a Renderer that can return the rendered manifest as a stream, so that large manifests
+// are decoded document by document instead of being read into memory at once. The caller closes the stream.
+type StreamRenderer interface {
+	Renderer
+	RenderStream(ctx context.Context, obj Object) (io.ReadCloser, error)
+}
@@ -1,14 +1,15 @@
 package v2
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"time"
 
-	"github.com/kyma-project/module-manager/pkg/types"
 	"github.com/kyma-project/module-manager/pkg/util"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -41,6 +42,17 @@ type RendererWithCache struct {
 }
 
 func (k *RendererWithCache) Render(ctx context.Context, obj Object) ([]byte, error) {
+	manifest, err := k.RenderStream(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	defer manifest.Close()
+	return io.ReadAll(manifest)
+}
+
+// RenderStream returns the cached manifest as a stream read from the cache file. If no manifest is cached,
+// it is rendered and written to the cache first. Manifests of StreamRenderers are streamed into the cache file.
+func (k *RendererWithCache) RenderStream(ctx context.Context, obj Object) (io.ReadCloser, error) {
 	logger := log.FromContext(ctx, "hash", k.hash, "Path", k.manifestCache.String())
 	status := obj.GetStatus()
 
@@ -51,29 +63,39 @@ func (k *RendererWithCache) Render(ctx context.Context, obj Object) ([]byte, err
 		return nil, err
 	}
 
-	cacheFile := k.ReadYAML()
-
-	if cacheFile.GetRawError() != nil {
-		renderStart := time.Now()
-		logger.Info("no cached manifest, rendering again")
-		manifest, err := k.Renderer.Render(ctx, obj)
-		if err != nil {
-			k.recorder.Event(obj, "Warning", "RenderNonCached", err.Error())
-			obj.SetStatus(status.WithState(StateError).WithErr(err))
-			return nil, fmt.Errorf("rendering new manifest failed: %w", err)
-		}
-		logger.Info("rendering finished", "time", time.Since(renderStart))
-		if err := util.WriteToFile(k.manifestCache.String(), manifest); err != nil {
-			k.recorder.Event(obj, "Warning", "ManifestCacheWrite", err.Error())
-			obj.SetStatus(status.WithState(StateError).WithErr(err))
-			return nil, err
-		}
-		return manifest, nil
+	cacheFile, err := k.Open()
+	if err == nil {
+		logger.V(util.DebugLogLevel).Info("reuse manifest from cache")
+		return cacheFile, nil
 	}
 
-	logger.V(util.DebugLogLevel).Info("reuse manifest from cache")
+	renderStart := time.Now()
+	logger.Info("no cached manifest, rendering again")
+	manifest, err := k.renderNonCached(ctx, obj)
+	if err != nil {
+		k.recorder.Event(obj, "Warning", "RenderNonCached", err.Error())
+		obj.SetStatus(status.WithState(StateError).WithErr(err))
+		return nil, fmt.Errorf("rendering new manifest failed: %w", err)
+	}
+	defer manifest.Close()
+	logger.Info("rendering finished", "time", time.Since(renderStart))
+	if err := util.WriteStreamToFile(k.manifestCache.String(), manifest); err != nil {
+		k.recorder.Event(obj, "Warning", "ManifestCacheWrite", err.Error())
+		obj.SetStatus(status.WithState(StateError).WithErr(err))
+		return nil, err
+	}
+	return k.Open()
+}
 
-	return []byte(cacheFile.GetContent()), nil
+func (k *RendererWithCache) renderNonCached(ctx context.Context, obj Object) (io.ReadCloser, error) {
+	if renderer, ok := k.Renderer.(StreamRenderer); ok {
+		return renderer.RenderStream(ctx, obj)
+	}
+	manifest, err := k.Renderer.Render(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(manifest)), nil
 }
 
 type manifestCache struct {
@@ -114,6 +136,6 @@ func (c *manifestCache) Clean() error {
 	return filepath.Walk(c.root, removeAllOld)
 }
 
-func (c *manifestCache) ReadYAML() *types.ParsedFile {
-	return types.NewParsedFile(util.GetStringifiedYamlFromFilePath(c.String()))
+func (c *manifestCache) Open() (*os.File, error) {
+	return os.Open(c.String())
 }
@@ -3,6 +3,9 @@ package v2_test
 import (
 	"context"
 	"errors"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -131,3 +134,31 @@ func TestWrapWithRendererCache(t *testing.T) {
 		)
 	}
 }
+
+func TestRendererWithCache_RenderStream(t *testing.T) {
+	t.Parallel()
+	assertions := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockObject := mockV2.NewMockObject(ctrl)
+	mockObject.EXPECT().GetStatus().AnyTimes().Return(Status{})
+	mockObject.EXPECT().SetStatus(gomock.AssignableToTypeOf(Status{})).AnyTimes()
+
+	rawPath := filepath.Join(t.TempDir(), "raw-manifest.yaml")
+	assertions.NoError(os.WriteFile(rawPath, []byte("test: true"), 0o600))
+	spec := &Spec{ManifestName: "test-manifest", Path: rawPath, Mode: RenderModeRaw}
+	options := &Options{EventRecorder: record.NewFakeRecorder(1), ManifestCache: ManifestCache(t.TempDir())}
+	cachedRenderer, ok := WrapWithRendererCache(NewRawRenderer(spec, options), spec, options).(StreamRenderer)
+	assertions.True(ok)
+
+	for _, reason := range []string{"rendered into the cache", "streamed from the cache"} {
+		manifest, err := cachedRenderer.RenderStream(context.Background(), mockObject)
+		assertions.NoError(err)
+		content, err := io.ReadAll(manifest)
+		assertions.NoError(err)
+		assertions.NoError(manifest.Close())
+		assertions.Equal("test: true", string(content), reason)
+		// the raw manifest is only streamed once, later renders are read from the cache
+		assertions.NoError(os.RemoveAll(rawPath))
+	}
+}
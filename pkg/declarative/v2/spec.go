@@ -13,6 +13,8 @@ type Spec struct {
 	Path         string
 	Values       any
 	Mode         RenderMode
+	// Sync determines the target cluster of the resources, the cluster of the Reconciler by default
+	Sync Sync
 }
 
 func DefaultSpec(path string, values any, mode RenderMode) *CustomSpecFns {
@@ -30,17 +32,23 @@ type CustomSpecFns struct {
 	PathFn         func(ctx context.Context, obj Object) string
 	ValuesFn       func(ctx context.Context, obj Object) any
 	ModeFn         func(ctx context.Context, obj Object) RenderMode
+	// SyncFn is optional, without it resources are synced with SyncStrategyLocal
+	SyncFn func(ctx context.Context, obj Object) Sync
 }
 
 func (s *CustomSpecFns) Spec(
 	ctx context.Context, obj Object,
 ) (*Spec, error) {
-	return &Spec{
+	spec := &Spec{
 		ManifestName: s.ManifestNameFn(ctx, obj),
 		Path:         s.PathFn(ctx, obj),
 		Values:       s.ValuesFn(ctx, obj),
 		Mode:         s.ModeFn(ctx, obj),
-	}, nil
+	}
+	if s.SyncFn != nil {
+		spec.Sync = s.SyncFn(ctx, obj)
+	}
+	return spec, nil
 }
 
 type RenderMode string
@@ -37,6 +37,74 @@ func ConcurrentSSA(clnt client.Client, owner client.FieldOwner) SSA {
 	}
 }
 
+// Apply priorities of resources, resources with a lower priority are applied first.
+const (
+	PriorityCRD = iota
+	PriorityNamespace
+	PriorityRBAC
+	PriorityDefault
+)
+
+// ApplyPriority returns the priority of the resource, so that CRDs, namespaces and RBAC are applied
+// before the resources depending on them.
+func ApplyPriority(info *resource.Info) int {
+	gvk := info.Object.GetObjectKind().GroupVersionKind()
+	switch {
+	case gvk.Group == "apiextensions.k8s.io" && gvk.Kind == "CustomResourceDefinition":
+		return PriorityCRD
+	case gvk.Group == "" && gvk.Kind == "Namespace":
+		return PriorityNamespace
+	case gvk.Group == "rbac.authorization.k8s.io", gvk.Group == "" && gvk.Kind == "ServiceAccount":
+		return PriorityRBAC
+	default:
+		return PriorityDefault
+	}
+}
+
+// BatchByPriority groups the resources by their ApplyPriority in ascending order, and splits every group
+// into batches of at most batchSize resources. The order of resources within a group is kept.
+func BatchByPriority(resources []*resource.Info, batchSize int) [][]*resource.Info {
+	groups := make([][]*resource.Info, PriorityDefault+1)
+	for _, info := range resources {
+		priority := ApplyPriority(info)
+		groups[priority] = append(groups[priority], info)
+	}
+	var batches [][]*resource.Info
+	for _, group := range groups {
+		for len(group) > 0 {
+			size := batchSize
+			if size <= 0 || size > len(group) {
+				size = len(group)
+			}
+			batches = append(batches, group[:size])
+			group = group[size:]
+		}
+	}
+	return batches
+}
+
+type batchedSSA struct {
+	ssa       SSA
+	batchSize int
+}
+
+// BatchedSSA applies resources in batches of at most batchSize resources, see BatchByPriority, to limit the
+// concurrent requests against the target API server. Batches are applied in order, concurrently within a batch,
+// and a failed batch stops the apply, as later batches can depend on it.
+func BatchedSSA(clnt client.Client, owner client.FieldOwner, batchSize int) SSA {
+	return &batchedSSA{ssa: ConcurrentSSA(clnt, owner), batchSize: batchSize}
+}
+
+func (b *batchedSSA) Run(ctx context.Context, resources []*resource.Info) error {
+	batches := BatchByPriority(resources, b.batchSize)
+	for i, batch := range batches {
+		if err := b.ssa.Run(ctx, batch); err != nil {
+			return fmt.Errorf("batch %d of %d: %w", i+1, len(batches), err)
+		}
+	}
+	return nil
+}
+
 func (c *concurrentDefaultSSA) Run(ctx context.Context, resources []*resource.Info) error {
 	ssaStart := time.Now()
 	logger := log.FromContext(ctx, "owner", c.owner)
@@ -80,7 +148,11 @@ func (c *concurrentDefaultSSA) serverSideApply(
 		fmt.Sprintf("apply %s", resource.ObjectName()),
 	)
 
-	results <- c.serverSideApplyResourceInfo(ctx, resource)
+	err := c.serverSideApplyResourceInfo(ctx, resource)
+	if progress := ProgressFromContext(ctx); err == nil && progress != nil {
+		progress.Applied()
+	}
+	results <- err
 
 	logger.V(util.TraceLogLevel).Info(
 		fmt.Sprintf("apply %s finished", resource.ObjectName()),
@@ -66,3 +66,45 @@ func TestConcurrentSSA(t *testing.T) {
 		)
 	}
 }
+
+func infoOf(apiVersion, kind, name string) *resource.Info {
+	return &resource.Info{Name: name, Object: &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]any{"name": name},
+	}}}
+}
+
+func TestBatchByPriority(t *testing.T) {
+	t.Parallel()
+	resources := []*resource.Info{
+		infoOf("apps/v1", "Deployment", "first"),
+		infoOf("rbac.authorization.k8s.io/v1", "ClusterRoleBinding", "binding"),
+		infoOf("v1", "Namespace", "namespace"),
+		infoOf("apps/v1", "Deployment", "second"),
+		infoOf("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd"),
+		infoOf("v1", "ServiceAccount", "account"),
+		infoOf("v1", "ConfigMap", "third"),
+	}
+
+	names := func(batches [][]*resource.Info) [][]string {
+		result := make([][]string, 0, len(batches))
+		for _, batch := range batches {
+			batchNames := make([]string, 0, len(batch))
+			for _, info := range batch {
+				batchNames = append(batchNames, info.Name)
+			}
+			result = append(result, batchNames)
+		}
+		return result
+	}
+
+	assert.Equal(t, [][]string{
+		{"crd"}, {"namespace"}, {"binding", "account"}, {"first", "second"}, {"third"},
+	}, names(BatchByPriority(resources, 2)))
+	assert.Equal(t, [][]string{
+		{"crd"}, {"namespace"}, {"binding", "account"}, {"first", "second", "third"},
+	}, names(BatchByPriority(resources, 0)), "groups are not split without batch size")
+	assert.Empty(t, BatchByPriority(nil, 2))
+	assert.NoError(t, BatchedSSA(fake.NewClientBuilder().Build(), "test", 2).Run(context.Background(), nil))
+}
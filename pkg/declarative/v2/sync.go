@@ -0,0 +1,90 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+// SyncStrategy determines the cluster the resources of an object are synced to.
+type SyncStrategy string
+
+const (
+	// SyncStrategyLocal syncs resources to the cluster of the Reconciler,
+	// or to the cluster of the client passed with WithRemoteTargetCluster.
+	SyncStrategyLocal SyncStrategy = "local"
+	// SyncStrategyRemote syncs resources to the cluster of the kubeconfig referenced by Sync.
+	SyncStrategyRemote SyncStrategy = "remote"
+)
+
+// KubeconfigSecretKeyDefault is the key of the kubeconfig in a Secret referenced without key.
+const KubeconfigSecretKeyDefault = "config"
+
+var ErrKubeconfigSecretMissing = errors.New("kubeconfig secret for remote sync is missing")
+
+// KubeconfigSecretRef references the kubeconfig of a target cluster in a Secret of the control-plane cluster.
+type KubeconfigSecretRef struct {
+	// Name of the Secret
+	Name string
+	// Namespace of the Secret, the namespace of the object by default
+	Namespace string
+	// Key of the kubeconfig in the Secret, KubeconfigSecretKeyDefault by default
+	Key string
+}
+
+// Sync determines the target cluster of the resources of an object. Without strategy, SyncStrategyLocal is used.
+type Sync struct {
+	Strategy SyncStrategy
+	// KubeconfigSecret references the kubeconfig of the target cluster, it is required for SyncStrategyRemote
+	KubeconfigSecret *KubeconfigSecretRef
+}
+
+// remoteConfig returns the REST config of the kubeconfig referenced by the Sync of the object,
+// together with a hash of the kubeconfig, so that clients are created again once it is rotated.
+func (r *Reconciler) remoteConfig(ctx context.Context, obj Object, sync Sync) (*rest.Config, uint32, error) {
+	ref := sync.KubeconfigSecret
+	if ref == nil || ref.Name == "" {
+		return nil, 0, ErrKubeconfigSecretMissing
+	}
+	key := client.ObjectKey{Name: ref.Name, Namespace: ref.Namespace}
+	if key.Namespace == "" {
+		key.Namespace = obj.GetNamespace()
+	}
+	dataKey := ref.Key
+	if dataKey == "" {
+		dataKey = KubeconfigSecretKeyDefault
+	}
+
+	secret := &v1.Secret{}
+	if err := r.Get(ctx, key, secret); err != nil {
+		return nil, 0, fmt.Errorf("%w: %s: %v", ErrKubeconfigSecretMissing, key, err)
+	}
+	kubeconfig, found := secret.Data[dataKey]
+	if !found {
+		return nil, 0, fmt.Errorf("%w: key %s not found in %s", ErrKubeconfigSecretMissing, dataKey, key)
+	}
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, 0, fmt.Errorf("parsing kubeconfig of %s: %w", key, err)
+	}
+	hash, err := util.CalculateHash(string(kubeconfig))
+	if err != nil {
+		return nil, 0, err
+	}
+	return config, hash, nil
+}
+
+// syncCacheKey returns the key of the clients of a remote target cluster, which changes with its kubeconfig.
+func syncCacheKey(key client.ObjectKey, kubeconfigHash uint32) client.ObjectKey {
+	return client.ObjectKey{
+		Namespace: key.Namespace, Name: key.Name + "-" + strconv.FormatUint(uint64(kubeconfigHash), 10),
+	}
+}
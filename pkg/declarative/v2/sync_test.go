@@ -0,0 +1,64 @@
+// contains internal tests that should not be exposed, thus no v2_test
+//
+//nolint:testpackage
+package v2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: remote
+  cluster:
+    server: https://remote.example.com:6443
+contexts:
+- name: remote
+  context:
+    cluster: remote
+    user: remote
+current-context: remote
+users:
+- name: remote
+  user:
+    token: secret-token
+`
+
+func Test_RemoteConfig(t *testing.T) {
+	t.Parallel()
+	obj := testObj{&unstructured.Unstructured{Object: map[string]any{}}}
+	obj.SetNamespace("kcp-system")
+	secret := &v1.Secret{Data: map[string][]byte{"kubeconfig": []byte(testKubeconfig)}}
+	secret.SetName("remote-kubeconfig")
+	secret.SetNamespace("kcp-system")
+	reconciler := &Reconciler{Options: &Options{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build(),
+	}}
+	ctx := context.Background()
+
+	config, hash, err := reconciler.remoteConfig(ctx, obj, Sync{
+		Strategy:         SyncStrategyRemote,
+		KubeconfigSecret: &KubeconfigSecretRef{Name: "remote-kubeconfig", Key: "kubeconfig"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://remote.example.com:6443", config.Host)
+	assert.Equal(t, "secret-token", config.BearerToken)
+	assert.NotZero(t, hash)
+
+	_, _, err = reconciler.remoteConfig(ctx, obj, Sync{
+		Strategy:         SyncStrategyRemote,
+		KubeconfigSecret: &KubeconfigSecretRef{Name: "remote-kubeconfig"},
+	})
+	assert.ErrorIs(t, err, ErrKubeconfigSecretMissing, "the default key is not set")
+	_, _, err = reconciler.remoteConfig(ctx, obj, Sync{Strategy: SyncStrategyRemote})
+	assert.ErrorIs(t, err, ErrKubeconfigSecretMissing)
+}
@@ -0,0 +1,358 @@
+package descriptor
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// DefaultArchiveTTL is the default duration for which downloads of archives without digest are cached.
+const DefaultArchiveTTL = 10 * time.Minute
+
+const (
+	archiveRetries      = 3
+	archiveBackoff      = time.Second
+	defaultS3Region     = "us-east-1"
+	gcsEndpoint         = "https://storage.googleapis.com"
+	gcsSigningRegion    = "auto"
+	sigV4Algorithm      = "AWS4-HMAC-SHA256"
+	sigV4Service        = "s3"
+	sigV4DateFormat     = "20060102T150405Z"
+	sigV4EmptyPayload   = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	sigV4HeaderPrefix   = "x-amz-"
+	sigV4TerminalString = "aws4_request"
+)
+
+var ErrUnsupportedArchiveURL = errors.New("unsupported archive URL")
+
+// Archives resolves archives of charts or manifests downloaded from HTTP(S) URLs or S3 or GCS buckets into
+// directories of a LayerStore, addressed by the digest of the archive. Archives with a digest are only downloaded
+// once. The digest of archives without digest is cached for TTL, so that they are not downloaded on every
+// reconciliation, but changes of the archive are picked up once the TTL expired.
+// Failed downloads are retried Retries times, waiting Backoff and twice as long for every further retry.
+type Archives struct {
+	Store *LayerStore
+	// TTL is the duration for which the download of an archive without digest is used, before it is downloaded again
+	TTL     time.Duration
+	Retries int
+	Backoff time.Duration
+
+	mu        sync.Mutex
+	downloads map[string]*cachedArchive
+}
+
+type cachedArchive struct {
+	digest     v1.Hash
+	downloaded time.Time
+}
+
+// archiveSource is the resolved location of an archive and the region its requests are signed for,
+// which is empty for HTTP(S) URLs.
+type archiveSource struct {
+	url    *url.URL
+	region string
+}
+
+// NewArchives returns Archives unpacking archives in the store.
+func NewArchives(store *LayerStore, ttl time.Duration) *Archives {
+	return &Archives{
+		Store:     store,
+		TTL:       ttl,
+		Retries:   archiveRetries,
+		Backoff:   archiveBackoff,
+		downloads: make(map[string]*cachedArchive),
+	}
+}
+
+// Locate returns the directory of the unpacked archive of the spec. Archives of a single top level directory,
+// like the archives of `helm package`, resolve to this directory. If the spec declares a digest, the archive
+// is verified against it and the returned verification records it, otherwise the verification is nil.
+func (a *Archives) Locate(ctx context.Context, spec types.ArchiveSpec, credentials *types.ArchiveCredentials,
+) (string, *types.ChartVerification, error) {
+	source, err := resolveArchiveURL(spec)
+	if err != nil {
+		return "", nil, err
+	}
+	var repositoryCredentials *types.HelmRepositoryCredentials
+	if credentials != nil {
+		repositoryCredentials = &credentials.HelmRepositoryCredentials
+	}
+	httpClient, err := chartRepositoryClient(repositoryCredentials)
+	if err != nil {
+		return "", nil, err
+	}
+	fetch := func() (io.ReadCloser, error) {
+		data, err := a.download(ctx, httpClient, source, credentials)
+		if err != nil {
+			return nil, fmt.Errorf("downloading archive %s: %w", spec.URL, err)
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	unpack := unpackLayer("", spec.URL)
+
+	if spec.Digest != "" {
+		digest, err := v1.NewHash(spec.Digest)
+		if err != nil {
+			return "", nil, types.ErrChartVerificationFailed.Wrap(
+				fmt.Errorf("invalid digest of archive %s: %w", spec.URL, err))
+		}
+		dir, err := a.Store.Get(digest, fetch, unpack)
+		if errors.Is(err, ErrLayerDigestMismatch) {
+			return "", nil, types.ErrChartVerificationFailed.Wrap(fmt.Errorf("archive %s: %w", spec.URL, err))
+		} else if err != nil {
+			return "", nil, err
+		}
+		return archiveRoot(dir), &types.ChartVerification{Digest: digest.String()}, nil
+	}
+
+	// downloads are cached per identity, so that archives are not served to callers without access to them
+	key := source.url.String()
+	if credentials != nil {
+		key += "|" + credentials.Username + "|" + credentials.AccessKeyID + "|" + credentials.Token
+	}
+	a.mu.Lock()
+	cached, found := a.downloads[key]
+	a.mu.Unlock()
+	if found && time.Since(cached.downloaded) < a.TTL {
+		// evicted archives are downloaded again, changed archives are stored by their new digest below
+		dir, err := a.Store.Get(cached.digest, fetch, unpack)
+		if err == nil {
+			return archiveRoot(dir), nil, nil
+		} else if !errors.Is(err, ErrLayerDigestMismatch) {
+			return "", nil, err
+		}
+	}
+
+	archive, err := fetch()
+	if err != nil {
+		return "", nil, err
+	}
+	data, err := io.ReadAll(archive)
+	if err != nil {
+		return "", nil, err
+	}
+	sum := sha256.Sum256(data)
+	digest := v1.Hash{Algorithm: "sha256", Hex: hex.EncodeToString(sum[:])}
+	dir, err := a.Store.Get(digest, func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}, unpack)
+	if err != nil {
+		return "", nil, err
+	}
+	a.mu.Lock()
+	a.downloads[key] = &cachedArchive{digest: digest, downloaded: time.Now()}
+	a.mu.Unlock()
+	return archiveRoot(dir), nil, nil
+}
+
+// download downloads the archive, retrying network errors, throttled requests and server errors with backoff.
+func (a *Archives) download(ctx context.Context, httpClient *http.Client, source archiveSource,
+	credentials *types.ArchiveCredentials,
+) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		data, retryable, err := a.get(ctx, httpClient, source, credentials)
+		if err == nil || !retryable || attempt >= a.Retries {
+			return data, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(a.Backoff << attempt):
+		}
+	}
+}
+
+// get downloads the archive once and returns if a failed download can be retried.
+func (a *Archives) get(ctx context.Context, httpClient *http.Client, source archiveSource,
+	credentials *types.ArchiveCredentials,
+) ([]byte, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.url.String(), nil)
+	if err != nil {
+		return nil, false, err
+	}
+	// every attempt is authorized again, as signatures expire
+	switch {
+	case credentials == nil:
+	case source.region != "" && credentials.AccessKeyID != "":
+		SignV4(req, credentials, source.region, time.Now())
+	case credentials.Token != "":
+		req.Header.Set("Authorization", "Bearer "+credentials.Token)
+	case credentials.Username != "" || credentials.Password != "":
+		req.SetBasicAuth(credentials.Username, credentials.Password)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		data, err := io.ReadAll(resp.Body)
+		return data, ctx.Err() == nil, err
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, false, types.ErrChartNotFound.Wrap(fmt.Errorf("failed to fetch %s: %s", source.url, resp.Status))
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		return nil, true, fmt.Errorf("failed to fetch %s: %s", source.url, resp.Status)
+	default:
+		return nil, false, fmt.Errorf("failed to fetch %s: %s", source.url, resp.Status)
+	}
+}
+
+// resolveArchiveURL resolves the URL of the spec to the HTTPS URL its archive is downloaded from.
+// Objects of S3 buckets are addressed virtual-hosted-style on AWS and path-style on custom endpoints.
+func resolveArchiveURL(spec types.ArchiveSpec) (archiveSource, error) {
+	parsed, err := url.Parse(spec.URL)
+	if err != nil {
+		return archiveSource{}, fmt.Errorf("%w: %s: %s", ErrUnsupportedArchiveURL, spec.URL, err.Error())
+	}
+	if parsed.Scheme == "http" || parsed.Scheme == "https" {
+		return archiveSource{url: parsed}, nil
+	}
+	bucket, key := parsed.Host, strings.TrimPrefix(parsed.Path, "/")
+	if bucket == "" || key == "" {
+		return archiveSource{}, fmt.Errorf("%w: %s has no bucket or object", ErrUnsupportedArchiveURL, spec.URL)
+	}
+
+	var endpoint string
+	source := archiveSource{region: spec.Region}
+	switch parsed.Scheme {
+	case "s3":
+		if source.region == "" {
+			source.region = defaultS3Region
+		}
+		endpoint = spec.Endpoint
+		if endpoint == "" {
+			endpoint = "https://" + bucket + ".s3." + source.region + ".amazonaws.com"
+			bucket = ""
+		}
+	case "gs":
+		endpoint, source.region = gcsEndpoint, gcsSigningRegion
+	default:
+		return archiveSource{}, fmt.Errorf("%w: %s", ErrUnsupportedArchiveURL, spec.URL)
+	}
+
+	if source.url, err = url.Parse(strings.TrimSuffix(endpoint, "/")); err != nil {
+		return archiveSource{}, fmt.Errorf("%w: endpoint %s: %s", ErrUnsupportedArchiveURL, endpoint, err.Error())
+	}
+	if bucket != "" {
+		source.url.Path += "/" + bucket
+	}
+	source.url.Path += "/" + key
+	// the path is sent as it is signed
+	source.url.RawPath = uriEncode(source.url.Path, false)
+	return source, nil
+}
+
+// archiveRoot returns the single top level directory of the unpacked archive, or the directory itself.
+func archiveRoot(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+		return dir
+	}
+	return filepath.Join(dir, entries[0].Name())
+}
+
+// SignV4 signs the request without body for S3 in the region with AWS Signature Version 4 at the passed time,
+// which also authenticates requests to GCS with an HMAC key in region "auto".
+// The host, range and x-amz-* headers of the request are signed.
+func SignV4(req *http.Request, credentials *types.ArchiveCredentials, region string, now time.Time) {
+	date := now.UTC().Format(sigV4DateFormat)
+	req.Header.Set("X-Amz-Date", date)
+	req.Header.Set("X-Amz-Content-Sha256", sigV4EmptyPayload)
+	if credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, sigV4HeaderPrefix) || name == "range" {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		sigV4EmptyPayload,
+	}, "\n")
+	scope := strings.Join([]string{date[:8], region, sigV4Service, sigV4TerminalString}, "/")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{sigV4Algorithm, date, scope, hex.EncodeToString(requestHash[:])}, "\n")
+
+	key := []byte("AWS4" + credentials.SecretAccessKey)
+	for _, part := range []string{date[:8], region, sigV4Service, sigV4TerminalString} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(query url.Values) string {
+	parameters := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			parameters = append(parameters, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	sort.Strings(parameters)
+	return strings.Join(parameters, "&")
+}
+
+// uriEncode encodes all but the unreserved characters of RFC 3986 as required by Signature Version 4,
+// slashes are only encoded if requested.
+func uriEncode(value string, encodeSlash bool) string {
+	var encoded strings.Builder
+	for _, char := range []byte(value) {
+		switch {
+		case 'A' <= char && char <= 'Z', 'a' <= char && char <= 'z', '0' <= char && char <= '9',
+			char == '-', char == '.', char == '_', char == '~', char == '/' && !encodeSlash:
+			encoded.WriteByte(char)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", char)
+		}
+	}
+	return encoded.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,134 @@
+package descriptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+var (
+	ErrNoMatchingVersion = errors.New("no version matches")
+	ErrNoTaggedLayer     = errors.New("no single layer to install")
+)
+
+// ChartVersions returns the versions of the chart with the name in the repository at repoURL, latest first.
+// The index of the repository is downloaded again, once the cached index is older than IndexTTL.
+func (r *ChartRepositories) ChartVersions(ctx context.Context, repoURL, chartName string,
+	credentials *types.HelmRepositoryCredentials,
+) ([]string, error) {
+	httpClient, err := chartRepositoryClient(credentials)
+	if err != nil {
+		return nil, err
+	}
+	index, _, err := r.index(ctx, httpClient, repoURL, credentials, false)
+	if err != nil {
+		return nil, err
+	}
+	chartVersions, found := index.Entries[chartName]
+	if !found {
+		return nil, types.ErrChartNotFound.Wrap(fmt.Errorf("chart %s in repository %s", chartName, repoURL))
+	}
+	versions := make([]string, 0, len(chartVersions))
+	for _, chartVersion := range chartVersions {
+		versions = append(versions, chartVersion.Version)
+	}
+	return SortVersions(versions), nil
+}
+
+// OCIVersions returns the tags of the OCI repository, e.g. "europe-docker.pkg.dev/kyma/modules/redis",
+// which are semantic versions, latest first.
+func OCIVersions(ctx context.Context, repository string, insecureRegistry bool, keyChain authn.Keychain,
+) ([]string, error) {
+	var options []name.Option
+	if insecureRegistry {
+		options = append(options, name.Insecure)
+	}
+	repo, err := name.NewRepository(repository, options...)
+	if err != nil {
+		return nil, fmt.Errorf("parsing OCI repository %s: %w", repository, err)
+	}
+	tags, err := remote.List(repo, remote.WithContext(ctx), remote.WithAuthFromKeychain(keyChain))
+	if err != nil {
+		return nil, fmt.Errorf("listing tags of OCI repository %s: %w", repository, err)
+	}
+	return SortVersions(tags), nil
+}
+
+// ResolveTag returns the imageSpec with the digest of the layer of the image its tag points to as ref, and the
+// digest of that image. The layer is the one of the MediaType of the imageSpec, or the only layer of the image
+// if no MediaType is set, otherwise an ErrNoTaggedLayer is returned.
+func ResolveTag(ctx context.Context, imageSpec types.ImageSpec, insecureRegistry bool, keyChain authn.Keychain,
+) (types.ImageSpec, string, error) {
+	var options []name.Option
+	if insecureRegistry {
+		options = append(options, name.Insecure)
+	}
+	tag, err := name.NewTag(fmt.Sprintf("%s/%s:%s", imageSpec.Repo, imageSpec.Name, imageSpec.Tag), options...)
+	if err != nil {
+		return imageSpec, "", fmt.Errorf("parsing OCI tag: %w", err)
+	}
+	image, err := remote.Image(tag, remote.WithContext(ctx), remote.WithAuthFromKeychain(keyChain))
+	if err != nil {
+		return imageSpec, "", fmt.Errorf("resolving OCI tag %s: %w", tag, err)
+	}
+	imageDigest, err := image.Digest()
+	if err != nil {
+		return imageSpec, "", fmt.Errorf("resolving OCI tag %s: %w", tag, err)
+	}
+	imageManifest, err := image.Manifest()
+	if err != nil {
+		return imageSpec, "", fmt.Errorf("resolving OCI tag %s: %w", tag, err)
+	}
+	var layers []string
+	for _, layer := range imageManifest.Layers {
+		if imageSpec.MediaType == "" || string(layer.MediaType) == imageSpec.MediaType {
+			layers = append(layers, layer.Digest.String())
+		}
+	}
+	if len(layers) != 1 {
+		return imageSpec, "", fmt.Errorf("%w: image %s has %d layers of media type %q",
+			ErrNoTaggedLayer, tag, len(layers), imageSpec.MediaType)
+	}
+	imageSpec.Ref = layers[0]
+	return imageSpec, imageDigest.String(), nil
+}
+
+// SortVersions returns the passed versions, which are semantic versions, latest first.
+// All other versions, e.g. tags like "latest", are dropped.
+func SortVersions(versions []string) []string {
+	parsed := make(semver.Collection, 0, len(versions))
+	for _, version := range versions {
+		if semanticVersion, err := semver.NewVersion(version); err == nil {
+			parsed = append(parsed, semanticVersion)
+		}
+	}
+	sort.Sort(sort.Reverse(parsed))
+	sorted := make([]string, 0, len(parsed))
+	for _, version := range parsed {
+		sorted = append(sorted, version.Original())
+	}
+	return sorted
+}
+
+// LatestMatchingVersion returns the first of the versions sorted by SortVersions matching the semver constraint,
+// e.g. ">=1.2.0 <2.0.0". Pre-releases only match constraints naming a pre-release, e.g. ">=1.3.0-0".
+func LatestMatchingVersion(versions []string, constraint string) (string, error) {
+	constraints, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return "", fmt.Errorf("parsing version constraint %q: %w", constraint, err)
+	}
+	for _, version := range versions {
+		if semanticVersion, err := semver.NewVersion(version); err == nil && constraints.Check(semanticVersion) {
+			return version, nil
+		}
+	}
+	return "", fmt.Errorf("%w %q", ErrNoMatchingVersion, constraint)
+}
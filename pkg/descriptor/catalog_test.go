@@ -0,0 +1,104 @@
+package descriptor_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/module-manager/pkg/descriptor"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+func Test_ChartRepositories_ChartVersions(t *testing.T) {
+	t.Parallel()
+	repository := newTestChartRepository(t, "1.1.0", "2.0.0-rc.1", "1.2.3")
+	repositories := descriptor.NewChartRepositories(t.TempDir(), descriptor.DefaultChartIndexTTL)
+	credentials := &types.HelmRepositoryCredentials{Username: "user", Password: "secret"}
+
+	versions, err := repositories.ChartVersions(context.Background(), repository.URL, "demo", credentials)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2.0.0-rc.1", "1.2.3", "1.1.0"}, versions)
+
+	_, err = repositories.ChartVersions(context.Background(), repository.URL, "other", credentials)
+	require.ErrorIs(t, err, types.ErrChartNotFound)
+}
+
+func Test_OCIVersions(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	repository := strings.TrimPrefix(server.URL, "http://") + "/modules/redis"
+	for _, tag := range []string{"1.0.0", "latest", "1.10.0", "1.9.2"} {
+		image, err := random.Image(1, 1)
+		require.NoError(t, err)
+		ref, err := name.NewTag(repository+":"+tag, name.Insecure)
+		require.NoError(t, err)
+		require.NoError(t, remote.Write(ref, image))
+	}
+
+	versions, err := descriptor.OCIVersions(context.Background(), repository, true, authn.DefaultKeychain)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.10.0", "1.9.2", "1.0.0"}, versions, "tags are sorted as versions")
+}
+
+func Test_ResolveTag(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	repo := strings.TrimPrefix(server.URL, "http://") + "/modules"
+	pushImage := func(tag string, layers int64) (string, string) {
+		image, err := random.Image(1, layers)
+		require.NoError(t, err)
+		ref, err := name.NewTag(repo+"/redis:"+tag, name.Insecure)
+		require.NoError(t, err)
+		require.NoError(t, remote.Write(ref, image))
+		imageDigest, err := image.Digest()
+		require.NoError(t, err)
+		imageLayers, err := image.Layers()
+		require.NoError(t, err)
+		layerDigest, err := imageLayers[0].Digest()
+		require.NoError(t, err)
+		return imageDigest.String(), layerDigest.String()
+	}
+	imageDigest, layerDigest := pushImage("1.0.0", 1)
+	pushImage("multi-layer", 2)
+	imageSpec := types.ImageSpec{Repo: repo, Name: "redis", Ref: "sha256:pinned", Tag: "1.0.0", Type: types.OciRefType}
+
+	resolved, resolvedDigest, err := descriptor.ResolveTag(context.Background(), imageSpec, true, authn.DefaultKeychain)
+	require.NoError(t, err)
+	assert.Equal(t, layerDigest, resolved.Ref, "the layer of the tagged image replaces the ref")
+	assert.Equal(t, imageDigest, resolvedDigest)
+
+	imageSpec.Tag = "multi-layer"
+	_, _, err = descriptor.ResolveTag(context.Background(), imageSpec, true, authn.DefaultKeychain)
+	assert.ErrorIs(t, err, descriptor.ErrNoTaggedLayer, "the layer of images with multiple layers requires a media type")
+}
+
+func Test_LatestMatchingVersion(t *testing.T) {
+	t.Parallel()
+	versions := descriptor.SortVersions([]string{"1.2.0", "v1.3.0", "2.0.0-rc.1", "1.2.5"})
+
+	version, err := descriptor.LatestMatchingVersion(versions, ">=1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.3.0", version, "the original version is kept")
+	version, err = descriptor.LatestMatchingVersion(versions, ">=1.0.0-0")
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0-rc.1", version, "pre-releases are only matched on request")
+	version, err = descriptor.LatestMatchingVersion(versions, "~1.2")
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.5", version)
+
+	_, err = descriptor.LatestMatchingVersion(versions, ">=3.0.0")
+	require.ErrorIs(t, err, descriptor.ErrNoMatchingVersion)
+	_, err = descriptor.LatestMatchingVersion(versions, "not a constraint")
+	require.Error(t, err)
+}
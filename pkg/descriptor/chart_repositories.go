@@ -0,0 +1,326 @@
+package descriptor
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/repo"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// DefaultChartIndexTTL is the default duration for which indexes of chart repositories are cached.
+const DefaultChartIndexTTL = 5 * time.Minute
+
+const (
+	chartRepositoriesDir   = "module-manager-charts"
+	chartRepositoryIndex   = "index.yaml"
+	chartRepositoryTimeout = 2 * time.Minute
+	chartFilePermission    = 0o644
+)
+
+var (
+	ErrChartDigestMismatch        = errors.New("chart digest mismatch")
+	ErrInvalidChartRepositoryCert = errors.New("no valid CA certificate found")
+)
+
+// ChartRepositories resolves charts of classic Helm HTTP repositories into local chart directories, which are
+// shared by all reconciliations. The index of every repository is cached for IndexTTL, so that repositories are
+// not queried on every reconciliation, and every chart version is only downloaded and unpacked once.
+type ChartRepositories struct {
+	Root string
+	// IndexTTL is the duration for which a downloaded index is used, before it is downloaded again
+	IndexTTL time.Duration
+
+	mu      sync.Mutex
+	indexes map[string]*cachedIndex
+	pulls   map[string]*sync.Mutex
+}
+
+type cachedIndex struct {
+	index      *repo.IndexFile
+	downloaded time.Time
+}
+
+// NewChartRepositories returns ChartRepositories unpacking charts in the root directory.
+// Charts unpacked there by a previous run are reused.
+func NewChartRepositories(root string, indexTTL time.Duration) *ChartRepositories {
+	return &ChartRepositories{
+		Root:     root,
+		IndexTTL: indexTTL,
+		indexes:  make(map[string]*cachedIndex),
+		pulls:    make(map[string]*sync.Mutex),
+	}
+}
+
+// DefaultChartRepositoriesRoot returns the default root directory of ChartRepositories in the temporary directory.
+func DefaultChartRepositoriesRoot() string {
+	return filepath.Join(os.TempDir(), chartRepositoriesDir)
+}
+
+// Locate returns the directory of the chart with the name in the repository at repoURL, in the latest version
+// matching the passed exact version or semver range, e.g. ">=1.2.0 <2.0.0". Without a version, the latest stable
+// version is used. If no version of a cached index matches, the index is downloaded again,
+// as the version could have been published since.
+func (r *ChartRepositories) Locate(ctx context.Context, repoURL, chartName, version string,
+	credentials *types.HelmRepositoryCredentials,
+) (string, error) {
+	chartDir, _, err := r.LocateVerified(ctx, repoURL, chartName, version, credentials, ChartVerificationOptions{})
+	return chartDir, err
+}
+
+// LocateVerified returns the directory of the chart like Locate, once its archive is verified against the options.
+// The archive of a chart is verified whenever it is located, as the options could change independent of the chart.
+// It returns how the archive was verified, which is nil without options.
+func (r *ChartRepositories) LocateVerified(ctx context.Context, repoURL, chartName, version string,
+	credentials *types.HelmRepositoryCredentials, options ChartVerificationOptions,
+) (string, *types.ChartVerification, error) {
+	httpClient, err := chartRepositoryClient(credentials)
+	if err != nil {
+		return "", nil, err
+	}
+	index, cached, err := r.index(ctx, httpClient, repoURL, credentials, false)
+	if err != nil {
+		return "", nil, err
+	}
+	chartVersion, err := index.Get(chartName, version)
+	if err != nil && cached {
+		if index, _, err = r.index(ctx, httpClient, repoURL, credentials, true); err != nil {
+			return "", nil, err
+		}
+		chartVersion, err = index.Get(chartName, version)
+	}
+	if err != nil {
+		return "", nil, types.ErrChartNotFound.Wrap(
+			fmt.Errorf("chart %s in version %q in repository %s: %w", chartName, version, repoURL, err))
+	}
+	if len(chartVersion.URLs) == 0 {
+		return "", nil, types.ErrChartNotFound.Wrap(
+			fmt.Errorf("chart %s %s in repository %s has no URL", chartName, chartVersion.Version, repoURL))
+	}
+	chartDir, archivePath, err := r.pull(ctx, httpClient, repoURL, credentials, chartVersion,
+		options.verifiesProvenance())
+	if err != nil || !options.enabled() {
+		return chartDir, nil, err
+	}
+	verification, err := verifyChart(archivePath, options)
+	if err != nil {
+		return "", nil, fmt.Errorf("chart %s %s: %w", chartVersion.Name, chartVersion.Version, err)
+	}
+	return chartDir, verification, nil
+}
+
+// index returns the index of the repository and if it was cached, downloading it unless a cached index
+// is younger than IndexTTL.
+func (r *ChartRepositories) index(ctx context.Context, httpClient *http.Client, repoURL string,
+	credentials *types.HelmRepositoryCredentials, refresh bool,
+) (*repo.IndexFile, bool, error) {
+	// indexes of the same repository could differ for different identities
+	key := repoURL
+	if credentials != nil {
+		key += "|" + credentials.Username
+	}
+	r.mu.Lock()
+	cached, found := r.indexes[key]
+	r.mu.Unlock()
+	if found && !refresh && time.Since(cached.downloaded) < r.IndexTTL {
+		return cached.index, true, nil
+	}
+
+	indexURL := strings.TrimSuffix(repoURL, "/") + "/" + chartRepositoryIndex
+	data, err := download(ctx, httpClient, indexURL, credentials)
+	if err != nil {
+		return nil, false, fmt.Errorf("downloading index of chart repository %s: %w", repoURL, err)
+	}
+	index := &repo.IndexFile{}
+	if err := yaml.Unmarshal(data, index); err != nil {
+		return nil, false, fmt.Errorf("parsing index of chart repository %s: %w", repoURL, err)
+	}
+	if index.APIVersion == "" {
+		return nil, false, fmt.Errorf("parsing index of chart repository %s: %w", repoURL, repo.ErrNoAPIVersion)
+	}
+	index.SortEntries()
+
+	r.mu.Lock()
+	r.indexes[key] = &cachedIndex{index: index, downloaded: time.Now()}
+	r.mu.Unlock()
+	return index, false, nil
+}
+
+// pull returns the directory of the unpacked chart version and the path of its archive next to it, which is
+// downloaded and verified against the digest of the index, unless it is unpacked already. With provenance,
+// the provenance file is downloaded next to the archive. Concurrent calls for the same chart version download it
+// only once.
+func (r *ChartRepositories) pull(ctx context.Context, httpClient *http.Client, repoURL string,
+	credentials *types.HelmRepositoryCredentials, chartVersion *repo.ChartVersion, provenance bool,
+) (string, string, error) {
+	repoHash := sha256.Sum256([]byte(repoURL))
+	dir := filepath.Join(r.Root, hex.EncodeToString(repoHash[:8]), chartVersion.Name+"-"+chartVersion.Version)
+	chartDir := filepath.Join(dir, chartVersion.Name)
+
+	chartURL, err := repo.ResolveReferenceURL(repoURL, chartVersion.URLs[0])
+	if err != nil {
+		return "", "", err
+	}
+	parsedChartURL, err := url.Parse(chartURL)
+	if err != nil {
+		return "", "", err
+	}
+	// the archive keeps its name, which is referenced by its provenance file
+	archivePath := filepath.Join(dir, path.Base(parsedChartURL.Path))
+	// like Helm, credentials of the repository are not passed to charts hosted elsewhere
+	if !sameHost(repoURL, chartURL) {
+		credentials = nil
+	}
+
+	pull := r.pullLock(dir)
+	pull.Lock()
+	defer pull.Unlock()
+	// charts unpacked by previous versions are pulled again, as their archive was not kept
+	if !exists(filepath.Join(chartDir, chartutil.ChartfileName)) || !exists(archivePath) {
+		if err := r.unpack(ctx, httpClient, credentials, chartURL, chartVersion, dir, archivePath); err != nil {
+			return "", "", err
+		}
+	}
+	if provenance && !exists(archivePath+provenanceExtension) {
+		signature, err := download(ctx, httpClient, chartURL+provenanceExtension, credentials)
+		if err != nil {
+			return "", "", types.ErrChartVerificationFailed.Wrap(fmt.Errorf(
+				"downloading provenance of chart %s %s: %w", chartVersion.Name, chartVersion.Version, err))
+		}
+		if err := os.WriteFile(archivePath+provenanceExtension, signature, chartFilePermission); err != nil {
+			return "", "", err
+		}
+	}
+	return chartDir, archivePath, nil
+}
+
+// unpack downloads the chart archive, verifies it against the digest of the index and unpacks it into dir.
+func (r *ChartRepositories) unpack(ctx context.Context, httpClient *http.Client,
+	credentials *types.HelmRepositoryCredentials, chartURL string, chartVersion *repo.ChartVersion,
+	dir, archivePath string,
+) error {
+	archive, err := download(ctx, httpClient, chartURL, credentials)
+	if err != nil {
+		return fmt.Errorf("downloading chart %s %s: %w", chartVersion.Name, chartVersion.Version, err)
+	}
+	if chartVersion.Digest != "" {
+		digest := sha256.Sum256(archive)
+		if hex.EncodeToString(digest[:]) != chartVersion.Digest {
+			return fmt.Errorf("%w: chart %s %s", ErrChartDigestMismatch, chartVersion.Name, chartVersion.Version)
+		}
+	}
+
+	// charts are unpacked next to their final directory, so that partially unpacked charts are never used
+	if err := os.MkdirAll(filepath.Dir(dir), os.ModePerm); err != nil {
+		return err
+	}
+	tempDir, err := os.MkdirTemp(filepath.Dir(dir), layerTempPattern)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+	if err := chartutil.Expand(tempDir, bytes.NewReader(archive)); err != nil {
+		return fmt.Errorf("unpacking chart %s %s: %w", chartVersion.Name, chartVersion.Version, err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, filepath.Base(archivePath)), archive, chartFilePermission); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	return os.Rename(tempDir, dir)
+}
+
+func exists(filePath string) bool {
+	_, err := os.Stat(filePath)
+	return err == nil
+}
+
+func (r *ChartRepositories) pullLock(dir string) *sync.Mutex {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pull, found := r.pulls[dir]
+	if !found {
+		pull = &sync.Mutex{}
+		r.pulls[dir] = pull
+	}
+	return pull
+}
+
+// chartRepositoryClient returns an HTTP client trusting the CA and presenting the client certificate
+// of the credentials in addition to the defaults.
+func chartRepositoryClient(credentials *types.HelmRepositoryCredentials) (*http.Client, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if credentials != nil && len(credentials.CAData) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(credentials.CAData) {
+			return nil, ErrInvalidChartRepositoryCert
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if credentials != nil && (len(credentials.CertData) > 0 || len(credentials.KeyData) > 0) {
+		cert, err := tls.X509KeyPair(credentials.CertData, credentials.KeyData)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate of chart repository: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport, _ := http.DefaultTransport.(*http.Transport)
+	transport = transport.Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport, Timeout: chartRepositoryTimeout}, nil
+}
+
+func download(ctx context.Context, httpClient *http.Client, fileURL string,
+	credentials *types.HelmRepositoryCredentials,
+) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if credentials != nil && (credentials.Username != "" || credentials.Password != "") {
+		req.SetBasicAuth(credentials.Username, credentials.Password)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", fileURL, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func sameHost(repoURL, chartURL string) bool {
+	parsedRepoURL, err := url.Parse(repoURL)
+	if err != nil {
+		return false
+	}
+	parsedChartURL, err := url.Parse(chartURL)
+	if err != nil {
+		return false
+	}
+	return parsedRepoURL.Scheme == parsedChartURL.Scheme && parsedRepoURL.Host == parsedChartURL.Host
+}
@@ -0,0 +1,161 @@
+package descriptor_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/openpgp" //nolint:staticcheck // provenance files are signed like by Helm
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/provenance"
+	"helm.sh/helm/v3/pkg/repo"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kyma-project/module-manager/pkg/descriptor"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+type testChartRepository struct {
+	*httptest.Server
+	index         *repo.IndexFile
+	archives      map[string][]byte
+	indexRequests atomic.Int32
+}
+
+func newTestChartRepository(t *testing.T, versions ...string) *testChartRepository {
+	t.Helper()
+	repository := &testChartRepository{index: repo.NewIndexFile(), archives: map[string][]byte{}}
+	repository.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, _ := r.BasicAuth(); username != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/index.yaml" {
+			repository.indexRequests.Add(1)
+			index, err := yaml.Marshal(repository.index)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write(index)
+			return
+		}
+		archive, found := repository.archives[r.URL.Path]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(archive)
+	}))
+	t.Cleanup(repository.Close)
+	for _, version := range versions {
+		repository.publish(t, version)
+	}
+	return repository
+}
+
+func (r *testChartRepository) publish(t *testing.T, version string) {
+	t.Helper()
+	metadata := &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "demo", Version: version}
+	archivePath, err := chartutil.Save(&chart.Chart{Metadata: metadata}, t.TempDir())
+	require.NoError(t, err)
+	archive, err := os.ReadFile(archivePath)
+	require.NoError(t, err)
+	digest := sha256.Sum256(archive)
+	fileName := filepath.Base(archivePath)
+	r.archives["/"+fileName] = archive
+	require.NoError(t, r.index.MustAdd(metadata, fileName, "", hex.EncodeToString(digest[:])))
+	r.index.SortEntries()
+}
+
+func Test_ChartRepositories_Locate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repository := newTestChartRepository(t, "1.1.0", "1.2.3", "2.0.0")
+	repositories := descriptor.NewChartRepositories(t.TempDir(), descriptor.DefaultChartIndexTTL)
+	credentials := &types.HelmRepositoryCredentials{Username: "user", Password: "secret"}
+
+	chartPath, err := repositories.Locate(ctx, repository.URL, "demo", ">=1.2.0 <2.0.0", credentials)
+	require.NoError(t, err)
+	metadata, err := chartutil.LoadChartfile(filepath.Join(chartPath, chartutil.ChartfileName))
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", metadata.Version, "the latest version of the range is used")
+
+	chartPath, err = repositories.Locate(ctx, repository.URL, "demo", "", credentials)
+	require.NoError(t, err)
+	assert.Contains(t, chartPath, "demo-2.0.0")
+	assert.Equal(t, int32(1), repository.indexRequests.Load(), "the index is cached")
+
+	repository.publish(t, "2.1.0")
+	chartPath, err = repositories.Locate(ctx, repository.URL, "demo", "2.1.0", credentials)
+	require.NoError(t, err, "versions published since the index was cached are found")
+	assert.Contains(t, chartPath, "demo-2.1.0")
+	assert.Equal(t, int32(2), repository.indexRequests.Load())
+
+	_, err = repositories.Locate(ctx, repository.URL, "demo", ">=3.0.0", credentials)
+	require.ErrorIs(t, err, types.ErrChartNotFound)
+	_, err = repositories.Locate(ctx, repository.URL+"/other", "demo", "", nil)
+	require.Error(t, err, "the repository requires credentials")
+}
+
+// sign publishes a provenance file for the archive of the version signed by a new key and returns its keyring.
+func (r *testChartRepository) sign(t *testing.T, version string) []byte {
+	t.Helper()
+	entity, err := openpgp.NewEntity("Module Team", "", "module@example.com", nil)
+	require.NoError(t, err)
+	fileName := "demo-" + version + ".tgz"
+	archivePath := filepath.Join(t.TempDir(), fileName)
+	require.NoError(t, os.WriteFile(archivePath, r.archives["/"+fileName], 0o600))
+	signature, err := (&provenance.Signatory{Entity: entity}).ClearSign(archivePath)
+	require.NoError(t, err)
+	r.archives["/"+fileName+".prov"] = []byte(signature)
+
+	keyring := &bytes.Buffer{}
+	require.NoError(t, entity.Serialize(keyring))
+	return keyring.Bytes()
+}
+
+func Test_ChartRepositories_LocateVerified(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repository := newTestChartRepository(t, "1.0.0", "1.1.0")
+	keyring := repository.sign(t, "1.0.0")
+	otherKeyring := newTestChartRepository(t, "1.0.0").sign(t, "1.0.0")
+	digest := sha256.Sum256(repository.archives["/demo-1.0.0.tgz"])
+	repositories := descriptor.NewChartRepositories(t.TempDir(), descriptor.DefaultChartIndexTTL)
+	credentials := &types.HelmRepositoryCredentials{Username: "user", Password: "secret"}
+
+	chartPath, verification, err := repositories.LocateVerified(ctx, repository.URL, "demo", "1.0.0", credentials,
+		descriptor.ChartVerificationOptions{Digest: "sha256:" + hex.EncodeToString(digest[:]), Keyring: keyring})
+	require.NoError(t, err)
+	assert.Contains(t, chartPath, "demo-1.0.0")
+	assert.Equal(t, "sha256:"+hex.EncodeToString(digest[:]), verification.Digest)
+	assert.Equal(t, "Module Team <module@example.com>", verification.SignedBy)
+
+	_, verification, err = repositories.LocateVerified(ctx, repository.URL, "demo", "1.0.0", credentials,
+		descriptor.ChartVerificationOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, verification, "charts are not verified without options")
+
+	_, _, err = repositories.LocateVerified(ctx, repository.URL, "demo", "1.1.0", credentials,
+		descriptor.ChartVerificationOptions{Digest: hex.EncodeToString(digest[:])})
+	require.ErrorIs(t, err, types.ErrChartVerificationFailed)
+	require.ErrorIs(t, err, descriptor.ErrChartDigestMismatch)
+
+	_, _, err = repositories.LocateVerified(ctx, repository.URL, "demo", "1.0.0", credentials,
+		descriptor.ChartVerificationOptions{Keyring: otherKeyring})
+	require.ErrorIs(t, err, types.ErrChartVerificationFailed, "the chart is verified again once it is unpacked")
+	_, _, err = repositories.LocateVerified(ctx, repository.URL, "demo", "1.1.0", credentials,
+		descriptor.ChartVerificationOptions{Keyring: keyring})
+	require.ErrorIs(t, err, types.ErrChartVerificationFailed, "unsigned charts fail the verification")
+}
@@ -0,0 +1,73 @@
+package descriptor
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/openpgp" //nolint:staticcheck // keyrings of Helm provenance files are read like Helm does
+	"helm.sh/helm/v3/pkg/provenance"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+const (
+	sha256DigestPrefix  = "sha256:"
+	provenanceExtension = ".prov"
+)
+
+// ChartVerificationOptions declare what a downloaded chart archive is verified against.
+type ChartVerificationOptions struct {
+	// Digest is the expected sha256 digest of the chart archive, with or without the "sha256:" prefix
+	Digest string
+	// Keyring holds the public keys, binary or ASCII armored, one of which has to sign the provenance file
+	// of the chart. Without keys, the provenance file is not verified.
+	Keyring []byte
+}
+
+func (o ChartVerificationOptions) enabled() bool {
+	return o.Digest != "" || o.verifiesProvenance()
+}
+
+func (o ChartVerificationOptions) verifiesProvenance() bool {
+	return len(o.Keyring) > 0
+}
+
+// verifyChart verifies the chart archive against the digest and the keyring of the options,
+// the provenance file is expected next to the archive.
+func verifyChart(archivePath string, options ChartVerificationOptions) (*types.ChartVerification, error) {
+	digest, err := provenance.DigestFile(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	verification := &types.ChartVerification{Digest: sha256DigestPrefix + digest}
+	if options.Digest != "" && !strings.EqualFold(strings.TrimPrefix(options.Digest, sha256DigestPrefix), digest) {
+		return nil, types.ErrChartVerificationFailed.Wrap(
+			fmt.Errorf("%w: expected %s, got %s", ErrChartDigestMismatch, options.Digest, verification.Digest))
+	}
+	if !options.verifiesProvenance() {
+		return verification, nil
+	}
+
+	keyring, err := readKeyring(options.Keyring)
+	if err != nil {
+		return nil, types.ErrChartVerificationFailed.Wrap(fmt.Errorf("reading keyring: %w", err))
+	}
+	signatory := &provenance.Signatory{KeyRing: keyring}
+	result, err := signatory.Verify(archivePath, archivePath+provenanceExtension)
+	if err != nil {
+		return nil, types.ErrChartVerificationFailed.Wrap(fmt.Errorf("verifying provenance: %w", err))
+	}
+	for name := range result.SignedBy.Identities {
+		verification.SignedBy = name
+		break
+	}
+	return verification, nil
+}
+
+func readKeyring(keyring []byte) (openpgp.EntityList, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(keyring), []byte("-----BEGIN")) {
+		return openpgp.ReadArmoredKeyRing(bytes.NewReader(keyring))
+	}
+	return openpgp.ReadKeyRing(bytes.NewReader(keyring))
+}
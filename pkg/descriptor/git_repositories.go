@@ -0,0 +1,295 @@
+package descriptor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// DefaultGitRevisionTTL is the default duration for which the commits of branches and tags are cached.
+const DefaultGitRevisionTTL = 5 * time.Minute
+
+const (
+	gitRepositoriesDir = "module-manager-git"
+	gitBinaryDefault   = "git"
+	gitDefaultRevision = "HEAD"
+	gitPeeledTagSuffix = "^{}"
+	gitCredentialsFile = 0o600
+	gitFetchHead       = "FETCH_HEAD"
+	gitMetadataDir     = ".git"
+	gitSSHKeyFile      = "id"
+	gitKnownHostsFile  = "known_hosts"
+	gitCredentialsDir  = ".credentials-"
+)
+
+var ErrGitRevisionNotFound = errors.New("git revision not found")
+
+//nolint:gochecknoglobals
+var gitCommitPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// GitRepositories resolves revisions of Git repositories into local checkouts, which are shared by all
+// reconciliations. Every commit is fetched shallowly and checked out once. Commits of branches and tags are cached
+// for RevisionTTL, so that the repository is not queried on every reconciliation, while revisions given as commit
+// are pinned and never resolved again. Repositories are accessed with the git binary at Binary.
+type GitRepositories struct {
+	Root string
+	// RevisionTTL is the duration for which the commit of a branch or tag is used, before it is resolved again
+	RevisionTTL time.Duration
+	// Binary is the name or path of the git binary, "git" if not set
+	Binary string
+
+	mu        sync.Mutex
+	revisions map[string]*cachedRevision
+	pulls     map[string]*sync.Mutex
+}
+
+type cachedRevision struct {
+	commit   string
+	resolved time.Time
+}
+
+// NewGitRepositories returns GitRepositories checking out commits in the root directory.
+// Commits checked out there by a previous run are reused.
+func NewGitRepositories(root string, revisionTTL time.Duration) *GitRepositories {
+	return &GitRepositories{
+		Root:        root,
+		RevisionTTL: revisionTTL,
+		Binary:      gitBinaryDefault,
+		revisions:   make(map[string]*cachedRevision),
+		pulls:       make(map[string]*sync.Mutex),
+	}
+}
+
+// DefaultGitRepositoriesRoot returns the default root directory of GitRepositories in the temporary directory.
+func DefaultGitRepositoriesRoot() string {
+	return filepath.Join(os.TempDir(), gitRepositoriesDir)
+}
+
+// Locate returns the directory at the path of the spec in the checkout of its revision and the commit it resolved to.
+func (r *GitRepositories) Locate(ctx context.Context, spec types.GitSpec, credentials *types.GitCredentials,
+) (string, string, error) {
+	// the path is cleaned as an absolute path, so that it cannot leave the checkout
+	chartPath := filepath.Join("/", spec.Path)
+	revision := spec.Revision
+	if revision == "" {
+		revision = gitDefaultRevision
+	}
+
+	auth, cleanup, err := r.authenticate(credentials)
+	if err != nil {
+		return "", "", err
+	}
+	defer cleanup()
+
+	commit, err := r.resolve(ctx, spec.URL, revision, auth)
+	if err != nil {
+		return "", "", err
+	}
+	repoHash := sha256.Sum256([]byte(spec.URL))
+	repoDir := filepath.Join(r.Root, hex.EncodeToString(repoHash[:8]))
+
+	pull := r.pullLock(filepath.Join(repoDir, commit))
+	pull.Lock()
+	defer pull.Unlock()
+	if !exists(filepath.Join(repoDir, commit)) {
+		if commit, err = r.checkout(ctx, spec.URL, revision, commit, repoDir, auth); err != nil {
+			return "", "", err
+		}
+	}
+
+	dir := filepath.Join(repoDir, commit, chartPath)
+	if !exists(dir) {
+		return "", "", types.ErrChartNotFound.Wrap(
+			fmt.Errorf("path %s not found in commit %s of repository %s", spec.Path, commit, spec.URL))
+	}
+	return dir, commit, nil
+}
+
+// resolve returns the commit of the revision, which is listed by the repository unless the revision is a commit
+// or its cached commit is younger than RevisionTTL.
+func (r *GitRepositories) resolve(ctx context.Context, repoURL, revision string, auth []string) (string, error) {
+	if gitCommitPattern.MatchString(revision) {
+		return revision, nil
+	}
+	key := repoURL + "|" + revision
+	r.mu.Lock()
+	cached, found := r.revisions[key]
+	r.mu.Unlock()
+	if found && time.Since(cached.resolved) < r.RevisionTTL {
+		return cached.commit, nil
+	}
+
+	refs, err := r.git(ctx, "", auth, "ls-remote", "--", repoURL, revision)
+	if err != nil {
+		return "", fmt.Errorf("listing revisions of repository %s: %w", repoURL, err)
+	}
+	commit := matchRevision(refs, revision)
+	if commit == "" {
+		return "", types.ErrChartNotFound.Wrap(
+			fmt.Errorf("%w: %s in repository %s", ErrGitRevisionNotFound, revision, repoURL))
+	}
+
+	r.mu.Lock()
+	r.revisions[key] = &cachedRevision{commit: commit, resolved: time.Now()}
+	r.mu.Unlock()
+	return commit, nil
+}
+
+// matchRevision returns the commit of the revision in the refs listed by git ls-remote. Branches take precedence
+// over tags like for git checkout, annotated tags resolve to the commit they point to.
+func matchRevision(refs, revision string) string {
+	commits := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(refs))
+	for scanner.Scan() {
+		if commit, ref, found := strings.Cut(scanner.Text(), "\t"); found {
+			commits[ref] = commit
+		}
+	}
+	for _, ref := range []string{
+		revision, "refs/heads/" + revision, "refs/tags/" + revision + gitPeeledTagSuffix, "refs/tags/" + revision,
+	} {
+		if commit, found := commits[ref]; found {
+			return commit
+		}
+	}
+	return ""
+}
+
+// checkout fetches the commit shallowly and checks it out into the directory of the commit in repoDir without
+// the metadata of the repository, and returns the commit checked out. Branches and tags are fetched by name,
+// as not all servers allow fetching commits by their SHA-1, and are checked out as the commit they point to
+// by then. Pinned commits not fetchable by their SHA-1 are fetched with all branches and tags instead.
+func (r *GitRepositories) checkout(ctx context.Context, repoURL, revision, commit, repoDir string, auth []string,
+) (string, error) {
+	// commits are checked out next to their final directory, so that partial checkouts are never used
+	if err := os.MkdirAll(repoDir, os.ModePerm); err != nil {
+		return "", err
+	}
+	tempDir, err := os.MkdirTemp(repoDir, layerTempPattern)
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tempDir)
+
+	if _, err := r.git(ctx, tempDir, nil, "init", "--quiet"); err != nil {
+		return "", err
+	}
+	pinned := revision == commit
+	_, err = r.git(ctx, tempDir, auth, "fetch", "--quiet", "--depth", "1", "--no-tags", "--", repoURL, revision)
+	if err != nil && pinned {
+		_, err = r.git(ctx, tempDir, auth, "fetch", "--quiet", "--no-tags", "--", repoURL,
+			"+refs/heads/*:refs/remotes/origin/*", "+refs/tags/*:refs/tags/*")
+	}
+	if err != nil {
+		return "", fmt.Errorf("fetching revision %s of repository %s: %w", revision, repoURL, err)
+	}
+	target := gitFetchHead
+	if pinned {
+		target = commit
+	}
+	if _, err := r.git(ctx, tempDir, nil, "-c", "advice.detachedHead=false", "checkout", "--quiet", target); err != nil {
+		return "", types.ErrChartNotFound.Wrap(
+			fmt.Errorf("%w: %s in repository %s: %s", ErrGitRevisionNotFound, revision, repoURL, err.Error()))
+	}
+	head, err := r.git(ctx, tempDir, nil, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	if head = strings.TrimSpace(head); head != commit {
+		commit = head
+		r.mu.Lock()
+		r.revisions[repoURL+"|"+revision] = &cachedRevision{commit: commit, resolved: time.Now()}
+		r.mu.Unlock()
+	}
+
+	if err := os.RemoveAll(filepath.Join(tempDir, gitMetadataDir)); err != nil {
+		return "", err
+	}
+	checkoutDir := filepath.Join(repoDir, commit)
+	if exists(checkoutDir) {
+		return commit, nil
+	}
+	return commit, os.Rename(tempDir, checkoutDir)
+}
+
+// authenticate returns the environment passing the credentials to git and a function removing the files
+// the environment refers to. Credentials are passed by environment, so that they do not show up in process lists.
+func (r *GitRepositories) authenticate(credentials *types.GitCredentials) ([]string, func(), error) {
+	if credentials == nil {
+		return nil, func() {}, nil
+	}
+	var env []string
+	if credentials.Username != "" || credentials.Password != "" {
+		basicAuth := base64.StdEncoding.EncodeToString([]byte(credentials.Username + ":" + credentials.Password))
+		env = append(env, "GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+basicAuth)
+	}
+	if len(credentials.SSHPrivateKey) == 0 {
+		return env, func() {}, nil
+	}
+
+	if err := os.MkdirAll(r.Root, os.ModePerm); err != nil {
+		return nil, nil, err
+	}
+	dir, err := os.MkdirTemp(r.Root, gitCredentialsDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+	keyFile, knownHostsFile := filepath.Join(dir, gitSSHKeyFile), filepath.Join(dir, gitKnownHostsFile)
+	if err := os.WriteFile(keyFile, credentials.SSHPrivateKey, gitCredentialsFile); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if err := os.WriteFile(knownHostsFile, credentials.KnownHosts, gitCredentialsFile); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	env = append(env, fmt.Sprintf(
+		"GIT_SSH_COMMAND=ssh -i %q -o IdentitiesOnly=yes -o UserKnownHostsFile=%q -o StrictHostKeyChecking=yes",
+		keyFile, knownHostsFile))
+	return env, cleanup, nil
+}
+
+// git runs git with the arguments in the directory and returns its output.
+func (r *GitRepositories) git(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	binary := r.Binary
+	if binary == "" {
+		binary = gitBinaryDefault
+	}
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Dir = dir
+	cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0"), env...)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(output), nil
+}
+
+func (r *GitRepositories) pullLock(dir string) *sync.Mutex {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pull, found := r.pulls[dir]
+	if !found {
+		pull = &sync.Mutex{}
+		r.pulls[dir] = pull
+	}
+	return pull
+}
@@ -0,0 +1,107 @@
+package descriptor_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chartutil"
+
+	"github.com/kyma-project/module-manager/pkg/descriptor"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+type testGitRepository struct {
+	dir string
+}
+
+func newTestGitRepository(t *testing.T) *testGitRepository {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repository := &testGitRepository{dir: t.TempDir()}
+	repository.git(t, "init", "--quiet", "--initial-branch", "main")
+	return repository
+}
+
+func (r *testGitRepository) git(t *testing.T, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=Module Team", "-c", "user.email=module@example.com"},
+		args...)...)
+	cmd.Dir = r.dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+	return strings.TrimSpace(string(output))
+}
+
+// commit commits a chart of the version at chart/ and returns the commit.
+func (r *testGitRepository) commit(t *testing.T, version string) string {
+	t.Helper()
+	chartDir := filepath.Join(r.dir, "chart")
+	require.NoError(t, os.MkdirAll(chartDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(chartDir, chartutil.ChartfileName),
+		[]byte("apiVersion: v2\nname: demo\nversion: "+version+"\n"), 0o600))
+	r.git(t, "add", ".")
+	r.git(t, "commit", "--quiet", "-m", "demo "+version)
+	return r.git(t, "rev-parse", "HEAD")
+}
+
+func chartVersion(t *testing.T, chartPath string) string {
+	t.Helper()
+	metadata, err := chartutil.LoadChartfile(filepath.Join(chartPath, chartutil.ChartfileName))
+	require.NoError(t, err)
+	return metadata.Version
+}
+
+func Test_GitRepositories_Locate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repository := newTestGitRepository(t)
+	first := repository.commit(t, "1.0.0")
+	repository.git(t, "tag", "--annotate", "v1.0.0", "-m", "release 1.0.0")
+	second := repository.commit(t, "1.1.0")
+	repositories := descriptor.NewGitRepositories(t.TempDir(), descriptor.DefaultGitRevisionTTL)
+	repoURL := "file://" + repository.dir
+
+	chartPath, commit, err := repositories.Locate(ctx,
+		types.GitSpec{URL: repoURL, Revision: "main", Path: "chart"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, second, commit)
+	assert.Equal(t, "1.1.0", chartVersion(t, chartPath))
+	assert.NoDirExists(t, filepath.Join(filepath.Dir(chartPath), ".git"), "the checkout has no repository metadata")
+
+	chartPath, commit, err = repositories.Locate(ctx, types.GitSpec{URL: repoURL, Revision: "v1.0.0", Path: "chart"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, first, commit, "annotated tags resolve to their commit")
+	assert.Equal(t, "1.0.0", chartVersion(t, chartPath))
+
+	_, commit, err = repositories.Locate(ctx, types.GitSpec{URL: repoURL, Revision: first, Path: "chart"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, first, commit)
+
+	third := repository.commit(t, "1.2.0")
+	_, commit, err = repositories.Locate(ctx, types.GitSpec{URL: repoURL, Path: "/chart/"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, third, commit, "the default branch is resolved")
+	_, commit, err = repositories.Locate(ctx, types.GitSpec{URL: repoURL, Revision: "main", Path: "chart"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, second, commit, "the commit of the branch is cached")
+
+	repositories.RevisionTTL = 0
+	chartPath, commit, err = repositories.Locate(ctx, types.GitSpec{URL: repoURL, Revision: "main", Path: "chart"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, third, commit)
+	assert.Equal(t, "1.2.0", chartVersion(t, chartPath))
+
+	_, _, err = repositories.Locate(ctx, types.GitSpec{URL: repoURL, Revision: "main", Path: "../other"}, nil)
+	require.ErrorIs(t, err, types.ErrChartNotFound)
+	_, _, err = repositories.Locate(ctx, types.GitSpec{URL: repoURL, Revision: "develop"}, nil)
+	require.ErrorIs(t, err, descriptor.ErrGitRevisionNotFound)
+	require.ErrorIs(t, err, types.ErrChartNotFound)
+}
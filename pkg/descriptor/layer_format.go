@@ -0,0 +1,109 @@
+package descriptor
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LayerFormat describes how the blob of a layer is unpacked.
+type LayerFormat string
+
+const (
+	// LayerFormatTarGzip is a gzipped tar archive of a chart or a directory of manifests.
+	LayerFormatTarGzip LayerFormat = "tar+gzip"
+	// LayerFormatTar is an uncompressed tar archive of a chart or a directory of manifests.
+	LayerFormatTar LayerFormat = "tar"
+	// LayerFormatFile is a single manifest file containing all resources.
+	LayerFormatFile LayerFormat = "file"
+
+	// manifestLayerFile is the name of the file a single file layer is unpacked to.
+	manifestLayerFile = "manifest.yaml"
+	tarMagicOffset    = 257
+)
+
+//nolint:gochecknoglobals
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	tarMagic  = []byte("ustar")
+)
+
+// DetectLayerFormat determines the LayerFormat from the media type of the layer in its OCI descriptor,
+// e.g. "application/vnd.oci.image.layer.v1.tar+gzip" or "application/x-yaml".
+// For unknown media types, the format is detected from the header of the blob.
+func DetectLayerFormat(mediaType string, header []byte) LayerFormat {
+	switch mediaType = strings.ToLower(mediaType); {
+	case strings.HasSuffix(mediaType, "tar+gzip"), strings.HasSuffix(mediaType, "tar.gzip"),
+		strings.HasSuffix(mediaType, "tar.gz"):
+		return LayerFormatTarGzip
+	case strings.HasSuffix(mediaType, "+tar"), strings.HasSuffix(mediaType, ".tar"):
+		return LayerFormatTar
+	case strings.HasSuffix(mediaType, "yaml"), strings.HasSuffix(mediaType, "yml"),
+		strings.HasSuffix(mediaType, "json"):
+		return LayerFormatFile
+	}
+
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		return LayerFormatTarGzip
+	case len(header) >= tarMagicOffset+len(tarMagic) &&
+		bytes.Equal(header[tarMagicOffset:tarMagicOffset+len(tarMagic)], tarMagic):
+		return LayerFormatTar
+	default:
+		return LayerFormatFile
+	}
+}
+
+// unpackLayer returns a LayerUnpackFn unpacking the blob into the content directory depending on its LayerFormat.
+// Archives are extracted, single files are written as manifest.yaml, which is applied as is.
+func unpackLayer(mediaType, layerReference string) LayerUnpackFn {
+	return func(blobPath, contentDir string) error {
+		blob, err := os.Open(blobPath)
+		if err != nil {
+			return err
+		}
+		defer blob.Close()
+		reader := bufio.NewReader(blob)
+		// the header is only peeked, so that the reader still starts at the beginning of the blob
+		header, err := reader.Peek(tarMagicOffset + len(tarMagic))
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("reading header of layer %s: %w", layerReference, err)
+		}
+
+		switch DetectLayerFormat(mediaType, header) {
+		case LayerFormatTarGzip:
+			uncompressedStream, err := gzip.NewReader(reader)
+			if err != nil {
+				return fmt.Errorf("failure in NewReader() while extracting TarGz %s: %w", layerReference, err)
+			}
+			return writeTarContent(contentDir, tar.NewReader(uncompressedStream), layerReference)
+		case LayerFormatTar:
+			return writeTarContent(contentDir, tar.NewReader(reader), layerReference)
+		default:
+			return writeFileContent(contentDir, reader, layerReference)
+		}
+	}
+}
+
+func writeFileContent(contentDir string, reader io.Reader, layerReference string) error {
+	if err := os.MkdirAll(contentDir, fs.ModePerm); err != nil {
+		return fmt.Errorf("creating directory for layer %s: %w", layerReference, err)
+	}
+	file, err := os.Create(filepath.Join(contentDir, manifestLayerFile))
+	if err != nil {
+		return fmt.Errorf("creating manifest of layer %s: %w", layerReference, err)
+	}
+	if _, err := io.Copy(file, reader); err != nil {
+		file.Close()
+		return fmt.Errorf("writing manifest of layer %s: %w", layerReference, err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,59 @@
+package descriptor_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/module-manager/pkg/descriptor"
+)
+
+func tarArchive(t *testing.T) []byte {
+	t.Helper()
+	buffer := &bytes.Buffer{}
+	writer := tar.NewWriter(buffer)
+	content := []byte("apiVersion: v2")
+	require.NoError(t, writer.WriteHeader(&tar.Header{Name: "Chart.yaml", Mode: 0o600, Size: int64(len(content))}))
+	_, err := writer.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func gzipped(t *testing.T, content []byte) []byte {
+	t.Helper()
+	buffer := &bytes.Buffer{}
+	writer := gzip.NewWriter(buffer)
+	_, err := writer.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func Test_DetectLayerFormat(t *testing.T) {
+	t.Parallel()
+	tarBlob := tarArchive(t)
+	manifest := []byte("apiVersion: v1\nkind: ConfigMap")
+	tests := []struct {
+		mediaType string
+		blob      []byte
+		expected  descriptor.LayerFormat
+	}{
+		{"application/vnd.oci.image.layer.v1.tar+gzip", nil, descriptor.LayerFormatTarGzip},
+		{"application/vnd.docker.image.rootfs.diff.tar.gzip", nil, descriptor.LayerFormatTarGzip},
+		{"application/vnd.cncf.helm.chart.content.v1.tar+gzip", nil, descriptor.LayerFormatTarGzip},
+		{"application/vnd.oci.image.layer.v1.tar", nil, descriptor.LayerFormatTar},
+		{"application/x-yaml", nil, descriptor.LayerFormatFile},
+		// unknown media types are detected from the blob
+		{"", gzipped(t, tarBlob), descriptor.LayerFormatTarGzip},
+		{"application/octet-stream", tarBlob, descriptor.LayerFormatTar},
+		{"", manifest, descriptor.LayerFormatFile},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, descriptor.DetectLayerFormat(test.mediaType, test.blob), test.mediaType)
+	}
+}
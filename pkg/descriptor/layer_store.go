@@ -0,0 +1,323 @@
+package descriptor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+const (
+	layerBlobFile    = "blob"
+	layerContentDir  = "content"
+	layerTempPattern = ".tmp-"
+	layerStoreDir    = "module-manager-layers"
+)
+
+var ErrLayerDigestMismatch = errors.New("layer digest mismatch")
+
+// LayerFetchFn returns the blob of a layer as stored in the registry.
+type LayerFetchFn func() (io.ReadCloser, error)
+
+// LayerUnpackFn unpacks the verified blob of a layer into the content directory.
+type LayerUnpackFn func(blobPath, contentDir string) error
+
+// LayerStore is a local store of OCI layers addressed by their digest, which is shared by all reconciliations,
+// so that identical layers are only pulled once. Every layer is kept as its blob and its unpacked content.
+// Blobs are verified against their digest before their content is first used, corrupted layers are pulled again.
+// Once the size of all layers exceeds MaxSize, the least recently used layers are evicted.
+type LayerStore struct {
+	Root string
+	// MaxSize is the size in bytes of all stored layers, which is not limited if 0
+	MaxSize int64
+
+	mu          sync.Mutex
+	initialized bool
+	size        int64
+	layers      map[v1.Hash]*storedLayer
+	pulls       map[v1.Hash]*sync.Mutex
+}
+
+type storedLayer struct {
+	size     int64
+	lastUsed time.Time
+	verified bool
+}
+
+// NewLayerStore returns a LayerStore in the root directory.
+// Layers stored there by a previous run are reused.
+func NewLayerStore(root string, maxSize int64) *LayerStore {
+	return &LayerStore{
+		Root:    root,
+		MaxSize: maxSize,
+		layers:  make(map[v1.Hash]*storedLayer),
+		pulls:   make(map[v1.Hash]*sync.Mutex),
+	}
+}
+
+// DefaultLayerStoreRoot returns the default root directory of a LayerStore in the temporary directory.
+func DefaultLayerStoreRoot() string {
+	return filepath.Join(os.TempDir(), layerStoreDir)
+}
+
+// ContentPath returns the directory of the unpacked content of the layer with the digest.
+func (s *LayerStore) ContentPath(digest v1.Hash) string {
+	return filepath.Join(s.layerPath(digest), layerContentDir)
+}
+
+// Get returns the directory of the unpacked content of the layer with the digest. If the layer is not stored yet,
+// its blob is fetched, verified and unpacked. Concurrent calls for the same digest fetch the layer only once.
+func (s *LayerStore) Get(digest v1.Hash, fetch LayerFetchFn, unpack LayerUnpackFn) (string, error) {
+	pull := s.pullLock(digest)
+	pull.Lock()
+	defer pull.Unlock()
+
+	if err := s.initialize(); err != nil {
+		return "", err
+	}
+	found, err := s.lookup(digest)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		if err := s.store(digest, fetch, unpack); err != nil {
+			return "", err
+		}
+	}
+	return s.ContentPath(digest), nil
+}
+
+func (s *LayerStore) pullLock(digest v1.Hash) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pull, found := s.pulls[digest]
+	if !found {
+		pull = &sync.Mutex{}
+		s.pulls[digest] = pull
+	}
+	return pull
+}
+
+// initialize registers the layers stored by previous runs and removes leftovers of interrupted pulls.
+func (s *LayerStore) initialize() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.initialized {
+		return nil
+	}
+	if err := os.MkdirAll(s.Root, fs.ModePerm); err != nil {
+		return fmt.Errorf("creating layer store: %w", err)
+	}
+	algorithms, err := os.ReadDir(s.Root)
+	if err != nil {
+		return fmt.Errorf("reading layer store: %w", err)
+	}
+	for _, algorithm := range algorithms {
+		algorithmPath := filepath.Join(s.Root, algorithm.Name())
+		// leftovers of pulls interrupted by a restart
+		if !algorithm.IsDir() || strings.HasPrefix(algorithm.Name(), layerTempPattern) {
+			if err := os.RemoveAll(algorithmPath); err != nil {
+				return fmt.Errorf("cleaning up layer store: %w", err)
+			}
+			continue
+		}
+		if err := s.registerLayers(algorithm.Name()); err != nil {
+			return err
+		}
+	}
+	s.initialized = true
+	return nil
+}
+
+func (s *LayerStore) registerLayers(algorithm string) error {
+	entries, err := os.ReadDir(filepath.Join(s.Root, algorithm))
+	if err != nil {
+		return fmt.Errorf("reading layer store: %w", err)
+	}
+	for _, entry := range entries {
+		digest := v1.Hash{Algorithm: algorithm, Hex: entry.Name()}
+		size, err := directorySize(s.layerPath(digest))
+		if err != nil {
+			return fmt.Errorf("reading layer store: %w", err)
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("reading layer store: %w", err)
+		}
+		s.layers[digest] = &storedLayer{size: size, lastUsed: info.ModTime()}
+		s.size += size
+	}
+	return nil
+}
+
+// lookup indicates if a verified layer with the digest is stored. Layers failing verification are removed.
+func (s *LayerStore) lookup(digest v1.Hash) (bool, error) {
+	s.mu.Lock()
+	layer, found := s.layers[digest]
+	verified := found && layer.verified
+	s.mu.Unlock()
+	if !found {
+		return false, nil
+	}
+
+	if !verified {
+		if err := verifyBlob(filepath.Join(s.layerPath(digest), layerBlobFile), digest); err != nil {
+			if err := s.remove(digest); err != nil {
+				return false, err
+			}
+			return false, nil
+		}
+	}
+	now := time.Now()
+	s.mu.Lock()
+	layer.verified = true
+	layer.lastUsed = now
+	s.mu.Unlock()
+	// the modification time keeps the order of usage across restarts
+	_ = os.Chtimes(s.layerPath(digest), now, now)
+	return true, nil
+}
+
+// store fetches, verifies and unpacks the layer next to the store and moves it into place once complete,
+// so that a layer is never used partially.
+func (s *LayerStore) store(digest v1.Hash, fetch LayerFetchFn, unpack LayerUnpackFn) error {
+	tempDir, err := os.MkdirTemp(s.Root, layerTempPattern)
+	if err != nil {
+		return fmt.Errorf("creating layer store directory for %s: %w", digest, err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	blobPath := filepath.Join(tempDir, layerBlobFile)
+	if err := writeBlob(blobPath, digest, fetch); err != nil {
+		return err
+	}
+	if err := verifyBlob(blobPath, digest); err != nil {
+		return err
+	}
+	if err := unpack(blobPath, filepath.Join(tempDir, layerContentDir)); err != nil {
+		return err
+	}
+	size, err := directorySize(tempDir)
+	if err != nil {
+		return err
+	}
+
+	layerPath := s.layerPath(digest)
+	if err := os.MkdirAll(filepath.Dir(layerPath), fs.ModePerm); err != nil {
+		return fmt.Errorf("creating layer store directory for %s: %w", digest, err)
+	}
+	if err := os.RemoveAll(layerPath); err != nil {
+		return fmt.Errorf("replacing stored layer %s: %w", digest, err)
+	}
+	if err := os.Rename(tempDir, layerPath); err != nil {
+		return fmt.Errorf("storing layer %s: %w", digest, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.layers[digest] = &storedLayer{size: size, lastUsed: time.Now(), verified: true}
+	s.size += size
+	return s.evict(digest)
+}
+
+// evict removes the least recently used layers until the store fits into MaxSize.
+// The layer just stored is never evicted, even if it exceeds MaxSize by itself.
+func (s *LayerStore) evict(keep v1.Hash) error {
+	if s.MaxSize <= 0 || s.size <= s.MaxSize {
+		return nil
+	}
+	digests := make([]v1.Hash, 0, len(s.layers))
+	for digest := range s.layers {
+		if digest != keep {
+			digests = append(digests, digest)
+		}
+	}
+	sort.Slice(digests, func(i, j int) bool {
+		return s.layers[digests[i]].lastUsed.Before(s.layers[digests[j]].lastUsed)
+	})
+	for _, digest := range digests {
+		if s.size <= s.MaxSize {
+			break
+		}
+		if err := os.RemoveAll(s.layerPath(digest)); err != nil {
+			return fmt.Errorf("evicting layer %s: %w", digest, err)
+		}
+		s.size -= s.layers[digest].size
+		delete(s.layers, digest)
+	}
+	return nil
+}
+
+func (s *LayerStore) remove(digest v1.Hash) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.RemoveAll(s.layerPath(digest)); err != nil {
+		return fmt.Errorf("removing corrupted layer %s: %w", digest, err)
+	}
+	if layer, found := s.layers[digest]; found {
+		s.size -= layer.size
+		delete(s.layers, digest)
+	}
+	return nil
+}
+
+func (s *LayerStore) layerPath(digest v1.Hash) string {
+	return filepath.Join(s.Root, digest.Algorithm, digest.Hex)
+}
+
+func writeBlob(blobPath string, digest v1.Hash, fetch LayerFetchFn) error {
+	blob, err := fetch()
+	if err != nil {
+		return err
+	}
+	defer blob.Close()
+	file, err := os.Create(blobPath)
+	if err != nil {
+		return fmt.Errorf("creating blob of layer %s: %w", digest, err)
+	}
+	if _, err := io.Copy(file, blob); err != nil {
+		file.Close()
+		return fmt.Errorf("writing blob of layer %s: %w", digest, err)
+	}
+	return file.Close()
+}
+
+func verifyBlob(blobPath string, digest v1.Hash) error {
+	file, err := os.Open(blobPath)
+	if err != nil {
+		return fmt.Errorf("reading blob of layer %s: %w", digest, err)
+	}
+	defer file.Close()
+	actual, _, err := v1.SHA256(file)
+	if err != nil {
+		return fmt.Errorf("reading blob of layer %s: %w", digest, err)
+	}
+	if actual != digest {
+		return fmt.Errorf("%w: expected %s, got %s", ErrLayerDigestMismatch, digest, actual)
+	}
+	return nil
+}
+
+func directorySize(root string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(root, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
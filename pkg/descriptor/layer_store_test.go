@@ -0,0 +1,91 @@
+package descriptor_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/module-manager/pkg/descriptor"
+)
+
+type testLayer struct {
+	content []byte
+	digest  v1.Hash
+	fetches int
+}
+
+func newTestLayer(t *testing.T, content string) *testLayer {
+	t.Helper()
+	digest, _, err := v1.SHA256(bytes.NewReader([]byte(content)))
+	require.NoError(t, err)
+	return &testLayer{content: []byte(content), digest: digest}
+}
+
+func (l *testLayer) fetch() (io.ReadCloser, error) {
+	l.fetches++
+	return io.NopCloser(bytes.NewReader(l.content)), nil
+}
+
+func copyBlob(blobPath, contentDir string) error {
+	blob, err := os.ReadFile(blobPath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(contentDir, os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(contentDir, "file"), blob, os.ModePerm)
+}
+
+func Test_LayerStore(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	store := descriptor.NewLayerStore(root, 0)
+	layer := newTestLayer(t, "chart")
+
+	path, err := store.Get(layer.digest, layer.fetch, copyBlob)
+	require.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(path, "file"))
+	require.NoError(t, err)
+	assert.Equal(t, "chart", string(content))
+
+	// stored layers are reused
+	_, err = store.Get(layer.digest, layer.fetch, copyBlob)
+	require.NoError(t, err)
+	assert.Equal(t, 1, layer.fetches)
+
+	// corrupted layers of previous runs are fetched again
+	require.NoError(t, os.WriteFile(filepath.Join(root, layer.digest.Algorithm, layer.digest.Hex, "blob"),
+		[]byte("corrupted"), os.ModePerm))
+	_, err = descriptor.NewLayerStore(root, 0).Get(layer.digest, layer.fetch, copyBlob)
+	require.NoError(t, err)
+	assert.Equal(t, 2, layer.fetches)
+
+	// blobs not matching their digest are rejected
+	mismatch := newTestLayer(t, "expected")
+	mismatch.content = []byte("tampered")
+	_, err = store.Get(mismatch.digest, mismatch.fetch, copyBlob)
+	assert.ErrorIs(t, err, descriptor.ErrLayerDigestMismatch)
+}
+
+func Test_LayerStoreEviction(t *testing.T) {
+	t.Parallel()
+	// blob and content of a layer with 5 bytes of content take 10 bytes
+	store := descriptor.NewLayerStore(t.TempDir(), 25)
+	first, second, third := newTestLayer(t, "first"), newTestLayer(t, "secnd"), newTestLayer(t, "third")
+
+	for _, layer := range []*testLayer{first, second, first, third} {
+		_, err := store.Get(layer.digest, layer.fetch, copyBlob)
+		require.NoError(t, err)
+	}
+	// the least recently used layer was evicted
+	assert.NoDirExists(t, store.ContentPath(second.digest))
+	assert.DirExists(t, store.ContentPath(first.digest))
+	assert.DirExists(t, store.ContentPath(third.digest))
+}
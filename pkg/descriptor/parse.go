@@ -2,13 +2,13 @@ package descriptor
 
 import (
 	"archive/tar"
-	"compress/gzip"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
 
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 
@@ -23,44 +23,27 @@ import (
 	yaml2 "sigs.k8s.io/yaml"
 )
 
-func GetPathFromExtractedTarGz(imageSpec types.ImageSpec,
+const configFileName = "installConfig.yaml"
+
+// GetPathFromExtractedLayer returns the path of the extracted content of the layer of the imageSpec,
+// which is pulled into the LayerStore unless it is stored already.
+// The layer is unpacked depending on its media type, see DetectLayerFormat.
+func GetPathFromExtractedLayer(store *LayerStore,
+	imageSpec types.ImageSpec,
 	insecureRegistry bool,
 	keyChain authn.Keychain,
 ) (string, error) {
 	imageRef := fmt.Sprintf("%s/%s@%s", imageSpec.Repo, imageSpec.Name, imageSpec.Ref)
-
-	// check existing dir
-	// if dir exists return existing dir
-	installPath := util.GetFsChartPath(imageSpec)
-	dir, err := os.Open(installPath)
-	if err != nil && !os.IsNotExist(err) {
-		return "", fmt.Errorf("opening dir for installs caused an error %s: %w", imageRef, err)
-	}
-	if dir != nil {
-		return installPath, nil
-	}
-
-	// pull image layer
-	layer, err := pullLayer(insecureRegistry, imageRef, keyChain)
+	digest, err := v1.NewHash(imageSpec.Ref)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("parsing layer digest of %s: %w", imageRef, err)
 	}
 
-	// uncompress chart to install path
-	blobReadCloser, err := layer.Compressed()
-	if err != nil {
-		return "", fmt.Errorf("fetching blob for compressed layer %s: %w", imageRef, err)
-	}
-
-	uncompressedStream, err := gzip.NewReader(blobReadCloser)
-	if err != nil {
-		return "", fmt.Errorf("failure in NewReader() while extracting TarGz %s: %w", imageRef, err)
-	}
-	tarReader := tar.NewReader(uncompressedStream)
-	return installPath, writeTarGzContent(installPath, tarReader, imageRef)
+	return store.Get(digest, compressedBlob(insecureRegistry, imageRef, keyChain),
+		unpackLayer(imageSpec.MediaType, imageRef))
 }
 
-func writeTarGzContent(installPath string, tarReader *tar.Reader, layerReference string) error {
+func writeTarContent(installPath string, tarReader *tar.Reader, layerReference string) error {
 	// create dir for uncompressed chart
 	if err := os.MkdirAll(installPath, fs.ModePerm); err != nil {
 		return fmt.Errorf("failure in MkdirAll() while extracting TarGz for installPath %s: %w",
@@ -120,32 +103,48 @@ func handleExtractedHeaderFile(header *tar.Header,
 	return nil
 }
 
-func DecodeUncompressedLayer(imageSpec types.ImageSpec,
+// DecodeUncompressedLayer returns the decoded YAML of the uncompressed layer of the imageSpec,
+// which is pulled into the LayerStore unless it is stored already.
+func DecodeUncompressedLayer(store *LayerStore,
+	imageSpec types.ImageSpec,
 	insecureRegistry bool,
 	keyChain authn.Keychain,
-	fileDestPath string,
 ) (interface{}, error) {
 	imageRef := fmt.Sprintf("%s/%s@%s", imageSpec.Repo, imageSpec.Name, imageSpec.Ref)
-	// check existing file
-	decodedFile, err := util.GetYamlFileContent(fileDestPath)
-	if err == nil {
-		return decodedFile, nil
-	} else if !os.IsNotExist(err) {
-		return nil, fmt.Errorf("opening file for install imageSpec caused an error %s: %w", imageRef, err)
+	digest, err := v1.NewHash(imageSpec.Ref)
+	if err != nil {
+		return nil, fmt.Errorf("parsing layer digest of %s: %w", imageRef, err)
 	}
 
-	// proceed only if file was not found
-	// yaml is not compressed
-	layer, err := pullLayer(insecureRegistry, imageRef, keyChain)
+	contentDir, err := store.Get(digest, compressedBlob(insecureRegistry, imageRef, keyChain),
+		func(blobPath, contentDir string) error {
+			blob, err := os.Open(blobPath)
+			if err != nil {
+				return err
+			}
+			defer blob.Close()
+			_, err = writeYamlContent(blob, imageRef, filepath.Join(contentDir, configFileName))
+			return err
+		})
 	if err != nil {
 		return nil, err
 	}
-	blob, err := layer.Uncompressed()
-	if err != nil {
-		return nil, fmt.Errorf("fetching blob for uncompressed layer %s: %w", imageRef, err)
-	}
+	return util.GetYamlFileContent(filepath.Join(contentDir, configFileName))
+}
 
-	return writeYamlContent(blob, imageRef, fileDestPath)
+// compressedBlob returns a LayerFetchFn pulling the blob of the layer as stored in the registry.
+func compressedBlob(insecureRegistry bool, imageRef string, keyChain authn.Keychain) LayerFetchFn {
+	return func() (io.ReadCloser, error) {
+		layer, err := pullLayer(insecureRegistry, imageRef, keyChain)
+		if err != nil {
+			return nil, err
+		}
+		blob, err := layer.Compressed()
+		if err != nil {
+			return nil, fmt.Errorf("fetching blob for layer %s: %w", imageRef, err)
+		}
+		return blob, nil
+	}
 }
 
 func pullLayer(insecureRegistry bool, imageRef string, keyChain authn.Keychain) (v1.Layer, error) {
@@ -155,7 +154,7 @@ func pullLayer(insecureRegistry bool, imageRef string, keyChain authn.Keychain)
 	return crane.PullLayer(imageRef, crane.WithAuthFromKeychain(keyChain))
 }
 
-func writeYamlContent(blob io.ReadCloser, layerReference string, filePath string) (interface{}, error) {
+func writeYamlContent(blob io.Reader, layerReference string, filePath string) (interface{}, error) {
 	var decodedConfig interface{}
 	err := yaml.NewYAMLOrJSONDecoder(blob, util.YamlDecodeBufferSize).Decode(&decodedConfig)
 	if err != nil {
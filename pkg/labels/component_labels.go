@@ -10,6 +10,37 @@ const (
 	ManifestFinalizer = "operator.kyma-project.io/manifest"
 	OperatorName      = "module-manager"
 	OwnedByLabel      = OperatorPrefix + Separator + "owned-by"
-	OwnedByFormat     = "%s__%s"
+	// OwnedByAnnotation records the owner of a resource, if the value of OwnedByLabel was truncated for long names.
+	OwnedByAnnotation = OwnedByLabel
+	OwnedBySeparator  = "__"
+	OwnedByFormat     = "%s" + OwnedBySeparator + "%s"
 	WatchedByLabel    = OperatorPrefix + Separator + "watched-by"
+	DryRunAnnotation  = OperatorPrefix + Separator + "dry-run"
+	// SecretChecksumAnnotation records the checksum of the Secrets the pods of a workload were rolled out with.
+	SecretChecksumAnnotation = OperatorPrefix + Separator + "secret-checksum"
+	// SecretValuesChecksumAnnotation records the checksum of the keys of Secrets referenced by the secret values
+	// of a Manifest, changes of the keys reinstall the Manifest, while other edits of the Secrets are ignored.
+	SecretValuesChecksumAnnotation = OperatorPrefix + Separator + "secret-values-checksum"
+	// IsolationGroupAnnotation assigns a Manifest to an isolation group limiting concurrent reconciliations,
+	// instead of the group of its target cluster.
+	IsolationGroupAnnotation = OperatorPrefix + Separator + "isolation-group"
+	// ForceDeleteAnnotation set to "true" removes the finalizer of a deleted Manifest once its uninstall failed
+	// repeatedly, leaving the resources of the failed installs behind.
+	ForceDeleteAnnotation = OperatorPrefix + Separator + "force-delete"
+	// RemoteLabel set to "true" or "false" among the runtime labels matched by override selectors indicates
+	// if an install targets a remote cluster. It is not set on any resource.
+	RemoteLabel = OperatorPrefix + Separator + "remote"
+	// ModuleName is the name of the module a Manifest installs, e.g. as set by the lifecycle-manager.
+	ModuleName = OperatorPrefix + Separator + "module-name"
+	// PartOf is the well-known label naming the higher-level application a resource is part of.
+	PartOf = "app.kubernetes.io/part-of"
+	// TraceParentAnnotation carries the W3C traceparent of the request creating or updating a Manifest,
+	// reconciles of the Manifest are traced as part of that trace.
+	TraceParentAnnotation = OperatorPrefix + Separator + "traceparent"
+	// PausedAnnotation set to "true" halts the reconciliation of a Manifest, including its deletion,
+	// keeping its status untouched until the annotation is removed, e.g. during maintenance.
+	PausedAnnotation = "module-manager.kyma-project.io/paused"
+	// RolloutHoldAnnotation names the ModuleRelease holding spec changes of a Manifest,
+	// until the rollout of the ModuleRelease releases the Manifest in one of its stages.
+	RolloutHoldAnnotation = OperatorPrefix + Separator + "rollout-hold"
 )
@@ -0,0 +1,38 @@
+package log
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Keys of the values tagging the log lines of reconciles, so that lines can be filtered by module and cluster.
+const (
+	ModuleKey        = "module"
+	TargetClusterKey = "targetCluster"
+	ReconcileIDKey   = "reconcileID"
+	InstallKey       = "install"
+)
+
+// ForModule returns the logger of the context tagged with the module and its target cluster,
+// together with a context carrying it, so that the manifest library logs with the same values.
+// Reconciles of controllers are tagged with their reconcile ID by controller-runtime already,
+// all other contexts are tagged with a new one.
+func ForModule(ctx context.Context, module, targetCluster string) (context.Context, logr.Logger) {
+	logger := logf.FromContext(ctx).WithValues(ModuleKey, module, TargetClusterKey, targetCluster)
+	if controller.ReconcileIDFromContext(ctx) == "" {
+		logger = logger.WithValues(ReconcileIDKey, uuid.NewUUID())
+	}
+	return logf.IntoContext(ctx, logger), logger
+}
+
+// ForInstall returns the logger tagged with the name of the install, if it is set.
+func ForInstall(logger logr.Logger, releaseName string) logr.Logger {
+	if releaseName == "" {
+		return logger
+	}
+	return logger.WithValues(InstallKey, releaseName)
+}
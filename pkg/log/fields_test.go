@@ -0,0 +1,33 @@
+package log_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kyma-project/module-manager/pkg/log"
+)
+
+func TestForModule(t *testing.T) {
+	t.Parallel()
+	var lines []string
+	ctx := logf.IntoContext(context.Background(), funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{Verbosity: log.DebugLevel}))
+
+	ctx, logger := log.ForModule(ctx, "redis", "local")
+	logger.V(log.DebugLevel).Info("rendering")
+	log.ForInstall(logf.FromContext(ctx), "redis-operator").V(log.TraceLevel).Info("not logged")
+	log.ForInstall(logf.FromContext(ctx), "redis-operator").Info("applied")
+
+	assert.Len(t, lines, 2)
+	for _, line := range lines {
+		assert.Contains(t, line, `"module"="redis"`)
+		assert.Contains(t, line, `"targetCluster"="local"`)
+		assert.Contains(t, line, `"reconcileID"=`)
+	}
+	assert.Contains(t, lines[1], `"install"="redis-operator"`)
+}
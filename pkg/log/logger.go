@@ -9,11 +9,26 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+// Verbosity levels of log lines, which are passed to logr.Logger.V. Lines are only logged
+// if their level does not exceed the verbosity of the logger.
+const (
+	InfoLevel  = 0
+	DebugLevel = 2
+	TraceLevel = 3
+)
+
+// ConfigLogger returns a logger emitting JSON lines up to InfoLevel.
 func ConfigLogger() logr.Logger {
+	return ConfigLoggerWithVerbosity(InfoLevel)
+}
+
+// ConfigLoggerWithVerbosity returns a logger emitting JSON lines up to the passed verbosity level,
+// where registered secret values are redacted.
+func ConfigLoggerWithVerbosity(verbosity int) logr.Logger {
 	// The following settings is based on kyma community Improvement of log messages usability
 	//nolint:lll
 	// https://github.com/kyma-project/community/blob/main/concepts/observability-consistent-logging/improvement-of-log-messages-usability.md#log-structure
-	atomicLevel := zap.NewAtomicLevel()
+	atomicLevel := zap.NewAtomicLevelAt(zapcore.Level(-verbosity))
 	encoderConfig := zap.NewProductionEncoderConfig()
 	encoderConfig.TimeKey = "date"
 	encoderConfig.EncodeTime = zapcore.RFC3339NanoTimeEncoder
@@ -21,5 +36,5 @@ func ConfigLogger() logr.Logger {
 	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.Lock(os.Stdout), atomicLevel)
 	zapLog := zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
 	logger := zapr.NewLogger(zapLog.With(zap.Namespace("context")))
-	return logger
+	return WithRedaction(logger, DefaultRedactor)
 }
@@ -0,0 +1,167 @@
+package log
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+// Redacted replaces secret values in log lines.
+const Redacted = "[REDACTED]"
+
+// minSecretLength prevents that short secret values, e.g. "true", redact unrelated parts of log lines.
+const minSecretLength = 4
+
+// maxSecrets bounds the number of secret values held by a Redactor, as values of rotated or deleted Secrets
+// are never unregistered.
+const maxSecrets = 4096
+
+// DefaultRedactor holds the secret values resolved by the manifest library, which are redacted
+// by loggers created with ConfigLogger.
+//
+//nolint:gochecknoglobals
+var DefaultRedactor = NewRedactor()
+
+// Redactor holds secret values, which are replaced by Redacted in log lines.
+// It holds the most recently registered 4096 values, the least recently registered values are dropped first.
+type Redactor struct {
+	mu      sync.RWMutex
+	secrets map[string]*list.Element
+	// recency orders the secret values from the least to the most recently registered one
+	recency *list.List
+}
+
+// NewRedactor returns a Redactor without any secret values.
+func NewRedactor() *Redactor {
+	return &Redactor{secrets: map[string]*list.Element{}, recency: list.New()}
+}
+
+// Register adds secret values to be redacted. Values shorter than four characters are ignored.
+// Values are registered again on every use, e.g. each reconcile, so that values still in use are kept.
+func (r *Redactor) Register(secrets ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, secret := range secrets {
+		if len(secret) < minSecretLength {
+			continue
+		}
+		if element, found := r.secrets[secret]; found {
+			r.recency.MoveToBack(element)
+			continue
+		}
+		r.secrets[secret] = r.recency.PushBack(secret)
+		if r.recency.Len() > maxSecrets {
+			delete(r.secrets, r.recency.Remove(r.recency.Front()).(string))
+		}
+	}
+}
+
+// Redact replaces all registered secret values in text by Redacted.
+func (r *Redactor) Redact(text string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for secret := range r.secrets {
+		text = strings.ReplaceAll(text, secret, Redacted)
+	}
+	return text
+}
+
+func (r *Redactor) contains(text string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for secret := range r.secrets {
+		if strings.Contains(text, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue returns the value of a key-value pair of a log line, or its redacted string representation
+// if it contains a secret value, e.g. a map of chart values or an error.
+func (r *Redactor) redactValue(value any) any {
+	var text string
+	switch typed := value.(type) {
+	case nil:
+		return value
+	case string:
+		text = typed
+	case error:
+		text = typed.Error()
+	case fmt.Stringer:
+		text = typed.String()
+	default:
+		text = fmt.Sprintf("%+v", typed)
+	}
+	if !r.contains(text) {
+		return value
+	}
+	return r.Redact(text)
+}
+
+func (r *Redactor) redactKeysAndValues(keysAndValues []any) []any {
+	redacted := make([]any, len(keysAndValues))
+	for i, value := range keysAndValues {
+		if i%2 == 0 {
+			redacted[i] = value
+			continue
+		}
+		redacted[i] = r.redactValue(value)
+	}
+	return redacted
+}
+
+// WithRedaction returns a logger, which replaces the secret values registered with the redactor
+// in messages, errors and values of all log lines by Redacted.
+func WithRedaction(logger logr.Logger, redactor *Redactor) logr.Logger {
+	sink := logger.GetSink()
+	// the wrapping sink adds a call frame, which is skipped in the reported call sites
+	if callDepthSink, ok := sink.(logr.CallDepthLogSink); ok {
+		sink = callDepthSink.WithCallDepth(1)
+	}
+	return logr.New(&redactingSink{sink: sink, redactor: redactor})
+}
+
+type redactingSink struct {
+	sink     logr.LogSink
+	redactor *Redactor
+}
+
+type redactedError struct {
+	message string
+}
+
+func (e redactedError) Error() string {
+	return e.message
+}
+
+// Init does nothing, as the wrapped sink was initialized by its logger already.
+func (s *redactingSink) Init(logr.RuntimeInfo) {}
+
+func (s *redactingSink) Enabled(level int) bool {
+	return s.sink.Enabled(level)
+}
+
+func (s *redactingSink) Info(level int, msg string, keysAndValues ...any) {
+	s.sink.Info(level, s.redactor.Redact(msg), s.redactor.redactKeysAndValues(keysAndValues)...)
+}
+
+func (s *redactingSink) Error(err error, msg string, keysAndValues ...any) {
+	if err != nil && s.redactor.contains(err.Error()) {
+		err = redactedError{message: s.redactor.Redact(err.Error())}
+	}
+	s.sink.Error(err, s.redactor.Redact(msg), s.redactor.redactKeysAndValues(keysAndValues)...)
+}
+
+func (s *redactingSink) WithValues(keysAndValues ...any) logr.LogSink {
+	return &redactingSink{
+		sink: s.sink.WithValues(s.redactor.redactKeysAndValues(keysAndValues)...), redactor: s.redactor,
+	}
+}
+
+func (s *redactingSink) WithName(name string) logr.LogSink {
+	return &redactingSink{sink: s.sink.WithName(name), redactor: s.redactor}
+}
@@ -0,0 +1,48 @@
+package log_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kyma-project/module-manager/pkg/log"
+)
+
+func TestWithRedaction(t *testing.T) {
+	t.Parallel()
+	var lines []string
+	redactor := log.NewRedactor()
+	redactor.Register("s3cr3t-password", "abc")
+	logger := log.WithRedaction(funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{}), redactor)
+
+	logger.WithValues("values", map[string]any{"auth": map[string]any{"password": "s3cr3t-password"}}).
+		Info("rendering with s3cr3t-password", "replicas", 3, "user", "abc")
+	logger.Error(errors.New("invalid value s3cr3t-password"), "render failed")
+
+	assert.Len(t, lines, 2)
+	for _, line := range lines {
+		assert.NotContains(t, line, "s3cr3t-password")
+		assert.Contains(t, line, log.Redacted)
+	}
+	assert.Contains(t, lines[0], `"replicas"=3`, "values without secrets are logged unchanged")
+	assert.Contains(t, lines[0], `"user"="abc"`, "short values are not redacted")
+}
+
+func TestRedactorBound(t *testing.T) {
+	t.Parallel()
+	redactor := log.NewRedactor()
+	for i := 0; i < 4096; i++ {
+		redactor.Register(fmt.Sprintf("secret-%04d", i))
+	}
+	redactor.Register("secret-0000")
+	redactor.Register("secret-4096")
+
+	assert.Equal(t, log.Redacted, redactor.Redact("secret-0000"), "values registered again are kept")
+	assert.Equal(t, "secret-0001", redactor.Redact("secret-0001"), "the least recently registered value is dropped")
+	assert.Equal(t, log.Redacted, redactor.Redact("secret-4096"))
+}
@@ -0,0 +1,84 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+// AdoptExistingResources verifies that the passed rendered resources, which already exist in the target cluster,
+// are owned by the base resource, and returns the adopted ones as "Kind/name".
+// Existing resources not managed by the operator are labeled as owned by the base resource if adopt is set,
+// otherwise a types.ErrResourceExists names them. Resources owned by another base resource are never adopted.
+func AdoptExistingResources(ctx context.Context, clnt client.Client, base client.Object,
+	objects []*unstructured.Unstructured, adopt bool,
+) ([]string, error) {
+	baseKey := client.ObjectKeyFromObject(base)
+	var adopted, conflicts []string
+	for _, obj := range objects {
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := clnt.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+			if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			return adopted, fmt.Errorf("reading existing %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		name := current.GetKind() + "/" + objectName(current)
+		switch owner, owned := OwnerOf(current); {
+		case owned && owner == baseKey:
+			continue
+		case owned:
+			conflicts = append(conflicts, fmt.Sprintf("%s (owned by %s)", name, owner))
+		case !adopt:
+			conflicts = append(conflicts, name)
+		default:
+			patch := client.MergeFrom(current.DeepCopy())
+			setOwnerLabels(current, ownerOf(base))
+			if err := clnt.Patch(ctx, current, patch); err != nil {
+				return adopted, fmt.Errorf("adopting %s: %w", name, err)
+			}
+			adopted = append(adopted, name)
+		}
+	}
+	if len(conflicts) > 0 {
+		return adopted, types.ErrResourceExists.Wrap(errors.New(strings.Join(conflicts, ", ")))
+	}
+	return adopted, nil
+}
+
+// adoptExisting verifies the ownership of the rendered resources of the passed manifest, which already exist
+// before the first apply of the install, see AdoptExistingResources.
+func (o *Operations) adoptExisting(manifest string) error {
+	if o.installInfo.Applied {
+		return nil
+	}
+	objects, err := util.Transform(o.installInfo.Ctx, manifest, o.installInfo.BaseResource, o.resourceTransforms)
+	if err != nil {
+		return err
+	}
+	defaultNamespaces(o.client.RESTMapper(), objects.Items, o.targetNamespace())
+	adopted, err := AdoptExistingResources(o.installInfo.Ctx, o.client, o.installInfo.BaseResource, objects.Items,
+		o.installInfo.AdoptExisting)
+	if len(adopted) > 0 {
+		o.logger.Info("adopted existing resources", "resources", adopted)
+	}
+	return err
+}
+
+// objectName returns the name of the object, prefixed by its namespace if it is namespaced.
+func objectName(obj client.Object) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
@@ -0,0 +1,53 @@
+package manifest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+func Test_AdoptExistingResources(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	owner := configMapObject("owner")
+	manual, own, foreign := configMapObject("manual"), configMapObject("own"), configMapObject("foreign")
+	own.SetLabels(map[string]string{labels.ManagedBy: labels.OperatorName, labels.OwnedByLabel: "default__owner"})
+	foreign.SetLabels(map[string]string{labels.ManagedBy: labels.OperatorName, labels.OwnedByLabel: "default__other"})
+	rendered := func(objs ...*unstructured.Unstructured) []*unstructured.Unstructured {
+		copies := make([]*unstructured.Unstructured, 0, len(objs))
+		for _, obj := range objs {
+			copies = append(copies, configMapObject(obj.GetName()))
+		}
+		return copies
+	}
+	clnt := fake.NewClientBuilder().WithObjects(manual, own, foreign).Build()
+
+	adopted, err := manifest.AdoptExistingResources(ctx, clnt, owner, rendered(manual, own, configMapObject("new")), false)
+	require.ErrorIs(t, err, types.ErrResourceExists)
+	assert.Contains(t, err.Error(), "ConfigMap/default/manual")
+	assert.NotContains(t, err.Error(), "own")
+	assert.Empty(t, adopted)
+
+	_, err = manifest.AdoptExistingResources(ctx, clnt, owner, rendered(foreign), true)
+	require.ErrorIs(t, err, types.ErrResourceExists, "resources of other owners are never adopted")
+	assert.Contains(t, err.Error(), "owned by default/other")
+
+	adopted, err = manifest.AdoptExistingResources(ctx, clnt, owner, rendered(manual, own), true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ConfigMap/default/manual"}, adopted)
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(manual.GroupVersionKind())
+	require.NoError(t, clnt.Get(ctx, client.ObjectKeyFromObject(manual), current))
+	key, owned := manifest.OwnerOf(current)
+	assert.True(t, owned)
+	assert.Equal(t, client.ObjectKeyFromObject(owner), key)
+}
@@ -0,0 +1,100 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+// applyTracker compares the rendered resources of an install before and after they are applied,
+// to report how the apply changed each of them.
+type applyTracker struct {
+	ops     *Operations
+	objects []*unstructured.Unstructured
+	// versions are the resourceVersions of the resources existing before the apply, keyed by objectIdentifier
+	versions map[string]string
+}
+
+// trackApply records the resourceVersions of the rendered resources of the passed manifest, which are currently
+// applied to the target cluster. It returns nil if the result of the apply is not reported.
+func (o *Operations) trackApply(manifest string) (*applyTracker, error) {
+	if o.reportApply == nil {
+		return nil, nil
+	}
+	objects, err := util.Transform(o.installInfo.Ctx, manifest, o.installInfo.BaseResource, o.resourceTransforms)
+	if err != nil {
+		return nil, err
+	}
+	defaultNamespaces(o.client.RESTMapper(), objects.Items, o.targetNamespace())
+	tracker := &applyTracker{ops: o, objects: objects.Items}
+	if tracker.versions, err = tracker.resourceVersions(); err != nil {
+		return nil, err
+	}
+	return tracker, nil
+}
+
+// report reports the types.InstallApplyStatus of the tracked resources after the apply finished with the passed
+// error. Resources named by the error are reported as failed, as well as resources not existing after a failed apply.
+// Resources neither existing before nor after a successful apply, e.g. skipped resources, are not reported.
+func (t *applyTracker) report(applyErr error) error {
+	if t == nil {
+		return nil
+	}
+	after, err := t.resourceVersions()
+	if err != nil {
+		return err
+	}
+	resources := make([]types.ResourceApplyStatus, 0, len(t.objects))
+	for _, obj := range t.objects {
+		status := types.ResourceApplyStatus{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+		}
+		id := objectIdentifier(obj)
+		before, existed := t.versions[id]
+		current, exists := after[id]
+		switch {
+		case applyErr != nil && strings.Contains(applyErr.Error(), fmt.Sprintf("%q", obj.GetName())):
+			status.Operation, status.Message = types.ApplyOperationFailed, applyErr.Error()
+		case !exists && applyErr != nil:
+			status.Operation, status.Message = types.ApplyOperationFailed, "not applied"
+		case !exists:
+			continue
+		case !existed:
+			status.Operation = types.ApplyOperationCreated
+		case before != current:
+			status.Operation = types.ApplyOperationUpdated
+		default:
+			status.Operation = types.ApplyOperationUnchanged
+		}
+		resources = append(resources, status)
+	}
+	t.ops.reportApply(util.NewInstallApplyStatus(t.ops.installInfo.ReleaseName, resources))
+	return nil
+}
+
+// resourceVersions returns the resourceVersions of the tracked resources existing in the target cluster.
+func (t *applyTracker) resourceVersions() (map[string]string, error) {
+	versions := make(map[string]string, len(t.objects))
+	for _, obj := range t.objects {
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := t.ops.client.Get(t.ops.installInfo.Ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+			if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, fmt.Errorf("reading applied %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		versions[objectIdentifier(obj)] = current.GetResourceVersion()
+	}
+	return versions, nil
+}
@@ -0,0 +1,268 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+const (
+	backupPrefix  = "backup"
+	backupDataKey = "resources.yaml"
+	// backupTargetAnnotation records the hash of the resources of the upgrade the snapshot was taken for
+	backupTargetAnnotation       = labels.OperatorPrefix + labels.Separator + "backup-target"
+	backupChartVersionAnnotation = labels.OperatorPrefix + labels.Separator + "backup-chart-version"
+	backupTimeAnnotation         = labels.OperatorPrefix + labels.Separator + "backup-time"
+	backupResourcesAnnotation    = labels.OperatorPrefix + labels.Separator + "backup-resources"
+)
+
+// ResourceSnapshots stores the snapshot of the live resources of a single install of a custom resource, which was
+// taken before its last upgrade, as multi-document YAML in a Secret or ConfigMap in the target cluster.
+// Only a single snapshot is kept, it is replaced by the snapshot of the next upgrade.
+type ResourceSnapshots struct {
+	clnt   client.Client
+	key    client.ObjectKey
+	driver types.BackupStorageDriver
+}
+
+// NewResourceSnapshots returns the ResourceSnapshots of the given release, owned by the passed base resource,
+// stored as configured by the passed policy.
+func NewResourceSnapshots(clnt client.Client, owner client.Object, releaseName string, policy *types.BackupPolicy,
+) *ResourceSnapshots {
+	name := util.NormalizeSubdomain(strings.Join(
+		[]string{backupPrefix, owner.GetNamespace(), owner.GetName(), releaseName}, "."),
+	)
+	snapshots := &ResourceSnapshots{
+		clnt:   clnt,
+		key:    client.ObjectKey{Namespace: InventoryNamespace, Name: name},
+		driver: types.BackupStorageSecret,
+	}
+	if policy != nil && policy.Namespace != "" {
+		snapshots.key.Namespace = policy.Namespace
+	}
+	if policy != nil && policy.Driver != "" {
+		snapshots.driver = policy.Driver
+	}
+	return snapshots
+}
+
+// Target returns the hash of the resources of the upgrade the stored snapshot was taken for,
+// or an empty string if no snapshot is stored.
+func (s *ResourceSnapshots) Target(ctx context.Context) (string, error) {
+	obj := s.object()
+	if err := s.clnt.Get(ctx, s.key, obj); apierrors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("loading backup %s: %w", s.key, err)
+	}
+	return obj.GetAnnotations()[backupTargetAnnotation], nil
+}
+
+// Load returns the resources of the stored snapshot, or nil if no snapshot is stored.
+func (s *ResourceSnapshots) Load(ctx context.Context) ([]*unstructured.Unstructured, error) {
+	obj := s.object()
+	if err := s.clnt.Get(ctx, s.key, obj); apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("loading backup %s: %w", s.key, err)
+	}
+	var data io.Reader = strings.NewReader("")
+	switch stored := obj.(type) {
+	case *v1.ConfigMap:
+		data = strings.NewReader(stored.Data[backupDataKey])
+	case *v1.Secret:
+		data = bytes.NewReader(stored.Data[backupDataKey])
+	}
+	objects, err := util.ParseManifestToObjects(data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding backup %s: %w", s.key, err)
+	}
+	return objects.Items, nil
+}
+
+// Store replaces the stored snapshot with the passed resources, taken for the upgrade with the passed target hash
+// while chartVersion was installed, and returns its reference for the install with the passed name.
+func (s *ResourceSnapshots) Store(ctx context.Context, install string, resources []*unstructured.Unstructured,
+	target, chartVersion string,
+) (types.BackupReference, error) {
+	var data strings.Builder
+	for _, resource := range resources {
+		document, err := yaml.Marshal(snapshotOf(resource).Object)
+		if err != nil {
+			return types.BackupReference{}, fmt.Errorf("encoding %s %s for backup: %w",
+				resource.GetKind(), resource.GetName(), err)
+		}
+		data.WriteString("---\n")
+		data.Write(document)
+	}
+
+	now := metav1.NewTime(time.Now().Truncate(time.Second))
+	obj := s.object()
+	obj.SetName(s.key.Name)
+	obj.SetNamespace(s.key.Namespace)
+	if _, err := controllerutil.CreateOrUpdate(ctx, s.clnt, obj, func() error {
+		obj.SetLabels(map[string]string{labels.ManagedBy: labels.OperatorName})
+		obj.SetAnnotations(map[string]string{
+			backupTargetAnnotation:       target,
+			backupChartVersionAnnotation: chartVersion,
+			backupTimeAnnotation:         now.UTC().Format(time.RFC3339),
+			backupResourcesAnnotation:    strconv.Itoa(len(resources)),
+		})
+		switch stored := obj.(type) {
+		case *v1.ConfigMap:
+			stored.Data = map[string]string{backupDataKey: data.String()}
+		case *v1.Secret:
+			stored.Data = map[string][]byte{backupDataKey: []byte(data.String())}
+		}
+		return nil
+	}); err != nil {
+		return types.BackupReference{}, fmt.Errorf("storing backup %s: %w", s.key, err)
+	}
+
+	return types.BackupReference{
+		Name:         install,
+		Kind:         string(s.driver),
+		Namespace:    s.key.Namespace,
+		ObjectName:   s.key.Name,
+		ChartVersion: chartVersion,
+		Resources:    len(resources),
+		Time:         now,
+	}, nil
+}
+
+// Purge removes the stored snapshot.
+func (s *ResourceSnapshots) Purge(ctx context.Context) error {
+	obj := s.object()
+	obj.SetName(s.key.Name)
+	obj.SetNamespace(s.key.Namespace)
+	if err := s.clnt.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("removing backup %s: %w", s.key, err)
+	}
+	return nil
+}
+
+func (s *ResourceSnapshots) object() client.Object {
+	if s.driver == types.BackupStorageConfigMap {
+		return &v1.ConfigMap{}
+	}
+	return &v1.Secret{}
+}
+
+// snapshotOf returns a copy of the live resource without its status and the metadata set by the API server,
+// so that it can be applied again to restore the resource.
+func snapshotOf(live *unstructured.Unstructured) *unstructured.Unstructured {
+	snapshot := live.DeepCopy()
+	unstructured.RemoveNestedField(snapshot.Object, "status")
+	snapshot.SetManagedFields(nil)
+	snapshot.SetResourceVersion("")
+	snapshot.SetUID("")
+	snapshot.SetGeneration(0)
+	snapshot.SetCreationTimestamp(metav1.Time{})
+	snapshot.SetSelfLink("")
+	return snapshot
+}
+
+// backup snapshots the live resources of the install before an upgrade to the passed manifest overwrites them,
+// if the install has a types.BackupPolicy. These are the rendered resources and, if the inventory is tracked,
+// the recorded resources pruned by the upgrade. No snapshot is taken if the install was not applied before,
+// if the inventory records the rendered resources unchanged, or if the snapshot was taken for the same upgrade
+// before, so that retries of a failed upgrade keep the resources as they were before its first attempt.
+func (o *Operations) backup(manifest string) error {
+	if o.installInfo.Backup == nil || !o.installInfo.Applied {
+		return nil
+	}
+	entries, err := o.inventoryEntries(manifest)
+	if err != nil {
+		return err
+	}
+	if o.installInfo.TrackInventory {
+		inventory, err := NewInventory(o.client, o.installInfo.BaseResource, o.installInfo.ReleaseName).
+			Load(o.installInfo.Ctx)
+		if err != nil && !errors.Is(err, ErrInventoryNotFound) {
+			return types.ErrBackupFailed.Wrap(err)
+		}
+		if err == nil && !inventoryChanged(inventory, entries) {
+			return nil
+		}
+		entries = append(entries, StaleInventoryEntries(inventory, entries)...)
+	}
+	target, err := util.CalculateHash(entries)
+	if err != nil {
+		return err
+	}
+
+	snapshots := o.resourceSnapshots()
+	stored, err := snapshots.Target(o.installInfo.Ctx)
+	if err != nil {
+		return types.ErrBackupFailed.Wrap(err)
+	}
+	if stored == strconv.FormatUint(uint64(target), 10) {
+		return nil
+	}
+
+	live := make([]*unstructured.Unstructured, 0, len(entries))
+	for _, entry := range entries {
+		obj := entry.toUnstructured()
+		if err := o.client.Get(o.installInfo.Ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			return types.ErrBackupFailed.Wrap(fmt.Errorf("reading %s %s: %w", entry.Kind, entry.Name, err))
+		}
+		live = append(live, obj)
+	}
+	reference, err := snapshots.Store(o.installInfo.Ctx, o.installInfo.ReleaseName, live,
+		strconv.FormatUint(uint64(target), 10), o.installInfo.InstalledVersion)
+	if err != nil {
+		return types.ErrBackupFailed.Wrap(err)
+	}
+	o.logger.Info("stored backup of resources before upgrade",
+		"resource", client.ObjectKeyFromObject(o.installInfo.BaseResource).String(),
+		"backup", client.ObjectKey{Namespace: reference.Namespace, Name: reference.ObjectName}.String(),
+		"resources", reference.Resources)
+	if o.reportBackup != nil {
+		o.reportBackup(reference)
+	}
+	return nil
+}
+
+// inventoryChanged indicates if the passed entries add, remove or change resources recorded in the inventory.
+func inventoryChanged(inventory, entries []InventoryEntry) bool {
+	if len(inventory) != len(entries) {
+		return true
+	}
+	hashes := make(map[string]uint32, len(inventory))
+	for _, entry := range inventory {
+		hashes[entry.ID()] = entry.Hash
+	}
+	for _, entry := range entries {
+		if hash, found := hashes[entry.ID()]; !found || hash != entry.Hash {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceSnapshots returns the ResourceSnapshots of the install.
+func (o *Operations) resourceSnapshots() *ResourceSnapshots {
+	return NewResourceSnapshots(o.client, o.installInfo.BaseResource, o.installInfo.ReleaseName,
+		o.installInfo.Backup)
+}
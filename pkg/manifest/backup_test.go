@@ -0,0 +1,61 @@
+package manifest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+func Test_ResourceSnapshots(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	clnt := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	snapshots := manifest.NewResourceSnapshots(clnt, configMapObject("owner"), "release",
+		&types.BackupPolicy{Namespace: "backups", Driver: types.BackupStorageConfigMap})
+
+	target, err := snapshots.Target(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, target)
+
+	live := configMapObject("live")
+	live.SetResourceVersion("42")
+	live.SetUID("7b0e8a4c")
+	require.NoError(t, unstructured.SetNestedField(live.Object, "value", "data", "key"))
+	reference, err := snapshots.Store(ctx, "release", []*unstructured.Unstructured{live}, "1234", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "ConfigMap", reference.Kind)
+	assert.Equal(t, "backups", reference.Namespace)
+	assert.Equal(t, "release", reference.Name)
+	assert.Equal(t, "1.0.0", reference.ChartVersion)
+	assert.Equal(t, 1, reference.Resources)
+
+	target, err = snapshots.Target(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "1234", target)
+
+	// the snapshot can be applied again, as the metadata set by the API server is dropped
+	resources, err := snapshots.Load(ctx)
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, "live", resources[0].GetName())
+	assert.Empty(t, resources[0].GetResourceVersion())
+	assert.Empty(t, resources[0].GetUID())
+	value, _, _ := unstructured.NestedString(resources[0].Object, "data", "key")
+	assert.Equal(t, "value", value)
+
+	require.NoError(t, snapshots.Purge(ctx))
+	err = clnt.Get(ctx, client.ObjectKey{Namespace: reference.Namespace, Name: reference.ObjectName}, &v1.ConfigMap{})
+	assert.True(t, apierrors.IsNotFound(err))
+	require.NoError(t, snapshots.Purge(ctx))
+}
@@ -0,0 +1,97 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"helm.sh/helm/v3/pkg/kube"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/resource"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// unknownKindRank ranks kinds not known to Helm, e.g. custom resources, before all known kinds on deletion,
+// so that their controllers can still process them.
+const unknownKindRank = -1
+
+// DeletionGroups splits the resources into the groups deleted one after another for the passed order.
+// With types.DeletionOrderReverse, resources are grouped by their kind in releaseutil.UninstallOrder,
+// i.e. the reverse order of installation with namespaces last, and resources of unknown kinds come first.
+// Otherwise, all resources are deleted at once in a single group.
+func DeletionGroups(resources kube.ResourceList, order types.DeletionOrder) []kube.ResourceList {
+	if len(resources) == 0 {
+		return nil
+	}
+	if order != types.DeletionOrderReverse {
+		return []kube.ResourceList{resources}
+	}
+
+	ranks := make(map[string]int, len(releaseutil.UninstallOrder))
+	for rank, kind := range releaseutil.UninstallOrder {
+		ranks[kind] = rank
+	}
+	rankOf := func(info *resource.Info) int {
+		if rank, known := ranks[info.Mapping.GroupVersionKind.Kind]; known {
+			return rank
+		}
+		return unknownKindRank
+	}
+
+	sorted := make(kube.ResourceList, len(resources))
+	copy(sorted, resources)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return rankOf(sorted[i]) < rankOf(sorted[j])
+	})
+
+	var groups []kube.ResourceList
+	for i, info := range sorted {
+		if i == 0 || rankOf(sorted[i-1]) != rankOf(info) {
+			groups = append(groups, kube.ResourceList{})
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], info)
+	}
+	return groups
+}
+
+// deleteResources deletes the resources group by group according to the passed types.DeletionPolicy.
+// With WaitForTermination, the next group is only deleted once all resources of the previous group are gone.
+// Otherwise, ErrResourceNotDeleted is returned, so that the deletion is continued with the next uninstall.
+func deleteResources(ctx context.Context, resources kube.ResourceList, policy *types.DeletionPolicy) error {
+	propagation := metav1.DeletePropagationBackground
+	if policy.Propagation == types.DeletionPropagationForeground {
+		propagation = metav1.DeletePropagationForeground
+	}
+
+	for _, group := range DeletionGroups(resources, policy.Order) {
+		var deleteErrs []error
+		for _, info := range group {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if _, err := resource.NewHelper(info.Client, info.Mapping).DeleteWithOptions(
+				info.Namespace, info.Name, &metav1.DeleteOptions{PropagationPolicy: &propagation},
+			); err != nil && !apierrors.IsNotFound(err) {
+				deleteErrs = append(deleteErrs, fmt.Errorf("deleting %s %s: %w",
+					info.Mapping.GroupVersionKind.Kind, info.ObjectName(), err))
+			}
+		}
+		if len(deleteErrs) > 0 {
+			return types.NewMultiError(deleteErrs)
+		}
+		if !policy.WaitForTermination {
+			continue
+		}
+		if err := checkResourcesDeleted(ctx, group); errors.Is(err, ErrResourceNotDeleted) {
+			return fmt.Errorf("%w: waiting for termination of %s resources",
+				ErrResourceNotDeleted, group[0].Mapping.GroupVersionKind.Kind)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package manifest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/kube"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/resource"
+
+	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+func resourceInfo(kind, name string) *resource.Info {
+	return &resource.Info{
+		Name:    name,
+		Mapping: &meta.RESTMapping{GroupVersionKind: schema.GroupVersionKind{Kind: kind}},
+	}
+}
+
+func groupNames(groups []kube.ResourceList) [][]string {
+	names := make([][]string, 0, len(groups))
+	for _, group := range groups {
+		groupNames := make([]string, 0, len(group))
+		for _, info := range group {
+			groupNames = append(groupNames, info.Mapping.GroupVersionKind.Kind+"/"+info.Name)
+		}
+		names = append(names, groupNames)
+	}
+	return names
+}
+
+func TestDeletionGroups(t *testing.T) {
+	t.Parallel()
+	resources := kube.ResourceList{
+		resourceInfo("Namespace", "sample"),
+		resourceInfo("ServiceAccount", "operator"),
+		resourceInfo("Deployment", "operator"),
+		resourceInfo("Sample", "default"),
+		resourceInfo("Deployment", "webhook"),
+		resourceInfo("ClusterRoleBinding", "operator"),
+	}
+
+	assert.Equal(t, [][]string{{
+		"Namespace/sample", "ServiceAccount/operator", "Deployment/operator",
+		"Sample/default", "Deployment/webhook", "ClusterRoleBinding/operator",
+	}}, groupNames(manifest.DeletionGroups(resources, types.DeletionOrderParallel)))
+
+	assert.Equal(t, [][]string{
+		{"Sample/default"},
+		{"Deployment/operator", "Deployment/webhook"},
+		{"ClusterRoleBinding/operator"},
+		{"ServiceAccount/operator"},
+		{"Namespace/sample"},
+	}, groupNames(manifest.DeletionGroups(resources, types.DeletionOrderReverse)))
+
+	assert.Empty(t, manifest.DeletionGroups(nil, types.DeletionOrderReverse))
+}
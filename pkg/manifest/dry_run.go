@@ -0,0 +1,155 @@
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+// emptyMergePatch is the JSON merge patch between two equal objects.
+const emptyMergePatch = "{}"
+
+// DryRunChart renders the resources based on types.InstallInfo and previews the changes an install would apply.
+func DryRunChart(options OperationOptions) (*types.DryRunResult, error) {
+	options, cancel := withOperationTimeout(options)
+	defer cancel()
+
+	ops, err := NewOperations(options)
+	if err != nil {
+		return nil, translateTimeout(options.InstallInfo, err)
+	}
+
+	result, err := ops.DryRun()
+	return result, translateTimeout(options.InstallInfo, err)
+}
+
+// DryRun renders the manifest and applies all resources with a server-side dry-run.
+// The result lists the changes an install would apply, none of them are persisted.
+// If the inventory is tracked, resources that would be pruned are listed as well.
+func (o *Operations) DryRun() (*types.DryRunResult, error) {
+	parsedFile := o.getManifestForChartPath(o.installInfo)
+	if parsedFile.GetRawError() != nil {
+		return nil, parsedFile.GetRawError()
+	}
+	objects, err := util.Transform(o.installInfo.Ctx, parsedFile.GetContent(), o.installInfo.BaseResource,
+		o.resourceTransforms)
+	if err != nil {
+		return nil, err
+	}
+	defaultNamespaces(o.client.RESTMapper(), objects.Items, o.targetNamespace())
+
+	result := &types.DryRunResult{Manifest: parsedFile.GetContent()}
+	for _, obj := range objects.Items {
+		diff, err := o.dryRunObject(obj)
+		if err != nil {
+			return nil, err
+		}
+		if diff != nil {
+			result.Diffs = append(result.Diffs, *diff)
+		}
+	}
+
+	if !o.installInfo.TrackInventory {
+		return result, nil
+	}
+	current, err := InventoryEntriesFromObjects(objects.Items)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := NewInventory(o.client, o.installInfo.BaseResource, o.installInfo.ReleaseName).
+		Load(o.installInfo.Ctx)
+	if errors.Is(err, ErrInventoryNotFound) {
+		return result, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for _, entry := range StaleInventoryEntries(previous, current) {
+		obj := entry.toUnstructured()
+		result.Diffs = append(result.Diffs, types.ResourceDiff{
+			Action:     types.DiffActionDelete,
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+		})
+	}
+	return result, nil
+}
+
+// dryRunObject server-side applies the passed object with a dry-run and compares the result with the current state.
+// It returns nil if the object would not change.
+func (o *Operations) dryRunObject(obj *unstructured.Unstructured) (*types.ResourceDiff, error) {
+	diff := &types.ResourceDiff{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(obj.GroupVersionKind())
+	err := o.client.Get(o.installInfo.Ctx, client.ObjectKeyFromObject(obj), current)
+	exists := true
+	if apierrors.IsNotFound(err) {
+		exists = false
+	} else if err != nil {
+		return nil, fmt.Errorf("getting %s %s for dry-run: %w", obj.GetKind(), client.ObjectKeyFromObject(obj), err)
+	}
+
+	// resources to be created are applied with a dry-run as well, so that they are validated by the API server
+	applied := obj.DeepCopy()
+	if err := o.client.Patch(o.installInfo.Ctx, applied, client.Apply, client.DryRunAll, client.ForceOwnership,
+		client.FieldOwner(labels.OperatorName)); err != nil {
+		return nil, fmt.Errorf("dry-run of %s %s: %w", obj.GetKind(), client.ObjectKeyFromObject(obj), err)
+	}
+	if !exists {
+		diff.Action = types.DiffActionCreate
+		return diff, nil
+	}
+
+	patch, err := mergePatch(current, applied)
+	if err != nil {
+		return nil, err
+	}
+	if patch == emptyMergePatch {
+		return nil, nil
+	}
+	diff.Action = types.DiffActionUpdate
+	diff.Patch = patch
+	return diff, nil
+}
+
+// mergePatch returns the JSON merge patch between both objects, ignoring status and server-managed metadata.
+func mergePatch(current, applied *unstructured.Unstructured) (string, error) {
+	original, err := json.Marshal(withoutServerFields(current))
+	if err != nil {
+		return "", err
+	}
+	modified, err := json.Marshal(withoutServerFields(applied))
+	if err != nil {
+		return "", err
+	}
+	patch, err := jsonpatch.CreateMergePatch(original, modified)
+	if err != nil {
+		return "", err
+	}
+	return string(patch), nil
+}
+
+func withoutServerFields(obj *unstructured.Unstructured) map[string]any {
+	stripped := obj.DeepCopy()
+	unstructured.RemoveNestedField(stripped.Object, "status")
+	for _, field := range []string{"managedFields", "resourceVersion", "generation", "creationTimestamp", "uid"} {
+		unstructured.RemoveNestedField(stripped.Object, "metadata", field)
+	}
+	return stripped.Object
+}
@@ -0,0 +1,129 @@
+package manifest_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+var errDryRunRejected = errors.New("rejected by the API server")
+
+const dryRunManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: created
+  namespace: default
+data:
+  key: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: updated
+  namespace: default
+data:
+  key: new
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unchanged
+  namespace: default
+data:
+  key: value
+`
+
+// dryRunClient records the objects applied with a dry-run and rejects the ones with the configured name,
+// as the API server would reject invalid resources.
+type dryRunClient struct {
+	client.Client
+	rejected string
+	applied  []string
+}
+
+func (c *dryRunClient) Patch(ctx context.Context, obj client.Object, patch client.Patch,
+	opts ...client.PatchOption,
+) error {
+	patchOptions := &client.PatchOptions{}
+	patchOptions.ApplyOptions(opts)
+	if len(patchOptions.DryRun) > 0 {
+		if obj.GetName() == c.rejected {
+			return errDryRunRejected
+		}
+		c.applied = append(c.applied, obj.GetName())
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func Test_DryRun(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	chartPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(chartPath, "manifest.yaml"), []byte(dryRunManifest), 0o600))
+
+	owner := configMapObject("owner")
+	updated, unchanged := &v1.ConfigMap{Data: map[string]string{"key": "old"}},
+		&v1.ConfigMap{Data: map[string]string{"key": "value"}}
+	updated.SetName("updated")
+	updated.SetNamespace("default")
+	unchanged.SetName("unchanged")
+	unchanged.SetNamespace("default")
+	clnt := &dryRunClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithObjects(updated, unchanged).Build()}
+	previous, err := manifest.InventoryEntriesFromObjects([]*unstructured.Unstructured{
+		configMapObject("updated"), configMapObject("pruned"),
+	})
+	require.NoError(t, err)
+	require.NoError(t, manifest.NewInventory(clnt, owner, "release").Store(ctx, previous))
+
+	dryRun := func() (*types.DryRunResult, error) {
+		return manifest.DryRunChart(manifest.OperationOptions{Logger: logr.Discard(),
+			InstallInfo: &types.InstallInfo{
+				Ctx:            ctx,
+				ChartInfo:      &types.ChartInfo{ChartPath: chartPath, ChartName: "release", ReleaseName: "release"},
+				ClusterInfo:    &types.ClusterInfo{Client: clnt, Config: &rest.Config{}},
+				ResourceInfo:   &types.ResourceInfo{BaseResource: owner},
+				TrackInventory: true,
+			},
+		})
+	}
+
+	result, err := dryRun()
+	require.NoError(t, err)
+	assert.Equal(t, dryRunManifest, result.Manifest)
+	require.Len(t, result.Diffs, 3)
+	assert.Equal(t, types.DiffActionCreate, result.Diffs[0].Action)
+	assert.Equal(t, "created", result.Diffs[0].Name)
+	assert.Equal(t, types.DiffActionUpdate, result.Diffs[1].Action)
+	assert.Equal(t, "updated", result.Diffs[1].Name)
+	assert.JSONEq(t, `{"data":{"key":"new"}}`, result.Diffs[1].Patch)
+	assert.Equal(t, types.DiffActionDelete, result.Diffs[2].Action)
+	assert.Equal(t, "pruned", result.Diffs[2].Name, "resources no longer rendered would be pruned")
+
+	assert.Contains(t, clnt.applied, "created", "resources to be created are applied with a dry-run")
+	assert.True(t, apierrors.IsNotFound(clnt.Get(ctx, client.ObjectKey{Namespace: "default", Name: "created"},
+		&v1.ConfigMap{})), "dry-runs persist no changes")
+	stored := &v1.ConfigMap{}
+	require.NoError(t, clnt.Get(ctx, client.ObjectKeyFromObject(updated), stored))
+	assert.Equal(t, "old", stored.Data["key"])
+
+	clnt.rejected = "created"
+	_, err = dryRun()
+	assert.ErrorIs(t, err, errDryRunRejected, "invalid resources to be created fail the dry-run")
+}
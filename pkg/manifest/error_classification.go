@@ -0,0 +1,98 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net"
+	"syscall"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kyma-project/module-manager/pkg/resource"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// ClassifyErrors returns the most severe types.ErrorClassification of the passed errors,
+// or an empty classification if all errors are nil.
+func ClassifyErrors(errs ...error) types.ErrorClassification {
+	var classification types.ErrorClassification
+	for _, err := range errs {
+		if err != nil {
+			classification = classification.MoreSevere(ClassifyError(err))
+		}
+	}
+	return classification
+}
+
+// ClassifyError determines if the passed error is expected to resolve on retry.
+// Errors implementing types.ClassifiedError classify themselves, otherwise the classification is derived from
+// well-known errors of Operations, the API server and the network.
+func ClassifyError(err error) types.ErrorClassification {
+	if err == nil {
+		return ""
+	}
+	// the outermost classification wins, errors aggregated in a types.MultiError are classified each
+	for wrapped := err; wrapped != nil; wrapped = errors.Unwrap(wrapped) {
+		switch typed := wrapped.(type) {
+		case *types.MultiError:
+			return ClassifyErrors(typed.Errs...)
+		case types.ClassifiedError:
+			return typed.Classification()
+		}
+	}
+	var pathErr *fs.PathError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, ErrRevisionFailed), errors.Is(err, resource.ErrCRDStoredVersionRemoved),
+		errors.As(err, &pathErr),
+		apierrors.IsInvalid(err), apierrors.IsBadRequest(err),
+		apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		return types.ErrorClassificationTerminal
+	case errors.Is(err, ErrCRsNotRemoved), errors.Is(err, ErrCRDsNotRemoved),
+		errors.Is(err, ErrUninstallInconsistent), errors.Is(err, ErrNamespaceNotFound),
+		errors.Is(err, ErrResourceNotDeleted),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.ECONNRESET), errors.As(err, &netErr),
+		apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), apierrors.IsTooManyRequests(err),
+		apierrors.IsServiceUnavailable(err), apierrors.IsInternalError(err), apierrors.IsConflict(err):
+		return types.ErrorClassificationTransient
+	}
+	return types.ErrorClassificationUnknown
+}
+
+// IsRetryable indicates if the operation failing with the passed error should be retried.
+// Errors implementing types.RetryableError decide themselves, otherwise only Terminal errors are not retried.
+func IsRetryable(err error) bool {
+	var retryableErr types.RetryableError
+	if errors.As(err, &retryableErr) {
+		return retryableErr.IsRetryable()
+	}
+	return ClassifyError(err) != types.ErrorClassificationTerminal
+}
+
+// WithReason wraps well-known errors of the target cluster into the matching types.OperationError,
+// so that they are reported with a human-readable reason. Errors which are typed already are returned unchanged.
+func WithReason(err error) error {
+	var operationErr *types.OperationError
+	var classifiedErr types.ClassifiedError
+	var netErr net.Error
+	switch {
+	case err == nil, errors.As(err, &operationErr), errors.As(err, &classifiedErr):
+		return err
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		return types.ErrForbidden.Wrap(err)
+	case errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.ECONNRESET), errors.As(err, &netErr):
+		return types.ErrRemoteUnreachable.Wrap(err)
+	}
+	return err
+}
+
+// renderFailure wraps errors of rendering into types.ErrRenderFailure, unless they are expected to resolve on retry,
+// e.g. if the API server could not be reached to determine its capabilities.
+func renderFailure(err error) error {
+	if err == nil || ClassifyError(err) == types.ErrorClassificationTransient {
+		return err
+	}
+	return types.ErrRenderFailure.Wrap(err)
+}
@@ -0,0 +1,102 @@
+package manifest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+func Test_ClassifyError(t *testing.T) {
+	t.Parallel()
+	configMaps := schema.GroupResource{Resource: "configmaps"}
+	tests := []struct {
+		name     string
+		err      error
+		expected types.ErrorClassification
+	}{
+		{"no error", nil, ""},
+		{"timeout", &types.OperationTimeoutError{Timeout: time.Minute, Err: context.DeadlineExceeded},
+			types.ErrorClassificationTransient},
+		{"policy violation", fmt.Errorf("install: %w", &types.SecurityFindingsError{
+			Findings: []types.SecurityFinding{{Rule: "private-key"}},
+		}), types.ErrorClassificationTerminal},
+		{"rolled back revision", fmt.Errorf("%w: revision 2", manifest.ErrRevisionFailed),
+			types.ErrorClassificationTerminal},
+		{"conflict", apierrors.NewConflict(configMaps, "cm", nil), types.ErrorClassificationTransient},
+		{"forbidden", apierrors.NewForbidden(configMaps, "cm", nil), types.ErrorClassificationTerminal},
+		{"unclassified", fmt.Errorf("unexpected"), types.ErrorClassificationUnknown},
+		{"multiple errors", types.NewMultiError([]error{
+			apierrors.NewConflict(configMaps, "cm", nil), fmt.Errorf("unexpected"),
+		}), types.ErrorClassificationUnknown},
+		{"multiple errors with a policy violation", fmt.Errorf("apply: %w", types.NewMultiError([]error{
+			&types.OperationTimeoutError{Timeout: time.Minute, Err: context.DeadlineExceeded},
+			&types.SecurityFindingsError{Findings: []types.SecurityFinding{{Rule: "private-key"}}},
+		})), types.ErrorClassificationTerminal},
+		{"classified multiple errors", types.ErrRemoteUnreachable.Wrap(types.NewMultiError([]error{
+			apierrors.NewForbidden(configMaps, "cm", nil),
+		})), types.ErrorClassificationTransient},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, testCase.expected, manifest.ClassifyError(testCase.err))
+		})
+	}
+}
+
+func Test_WithReason(t *testing.T) {
+	t.Parallel()
+	configMaps := schema.GroupResource{Resource: "configmaps"}
+
+	forbidden := manifest.WithReason(apierrors.NewForbidden(configMaps, "cm", nil))
+	assert.ErrorIs(t, forbidden, types.ErrForbidden)
+	assert.True(t, apierrors.IsForbidden(forbidden))
+	assert.False(t, manifest.IsRetryable(forbidden))
+
+	unreachable := manifest.WithReason(fmt.Errorf("apply: %w", syscall.ECONNREFUSED))
+	assert.ErrorIs(t, unreachable, types.ErrRemoteUnreachable)
+	assert.True(t, manifest.IsRetryable(unreachable))
+	assert.Equal(t, types.ErrorClassificationTransient, manifest.ClassifyError(unreachable))
+
+	chartNotFound := types.ErrChartNotFound.Wrap(fmt.Errorf("repo not found"))
+	assert.Equal(t, chartNotFound, manifest.WithReason(chartNotFound))
+	assert.NotErrorIs(t, chartNotFound, types.ErrRenderFailure)
+	assert.Equal(t, "chart not found: repo not found", chartNotFound.Error())
+
+	unclassified := fmt.Errorf("unexpected")
+	assert.Equal(t, unclassified, manifest.WithReason(unclassified))
+	assert.True(t, manifest.IsRetryable(unclassified))
+}
+
+func Test_MultiError(t *testing.T) {
+	t.Parallel()
+	configMaps := schema.GroupResource{Resource: "configmaps"}
+	errs := []error{nil, apierrors.NewNotFound(configMaps, "a"), fmt.Errorf("apply: %w", syscall.ECONNREFUSED)}
+	for i := 0; i < 12; i++ {
+		errs = append(errs, fmt.Errorf("resource %d is invalid", i%11))
+	}
+	err := fmt.Errorf("install: %w", types.NewMultiError(errs))
+
+	assert.ErrorIs(t, err, syscall.ECONNREFUSED)
+	assert.True(t, apierrors.IsNotFound(err))
+	var statusErr *apierrors.StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, "a", statusErr.ErrStatus.Details.Name)
+
+	message := err.Error()
+	assert.Contains(t, message, "resource 0 is invalid (2 times); resource 1 is invalid;")
+	assert.True(t, strings.HasSuffix(message, "and 3 more errors"), message)
+	assert.Equal(t, 10, strings.Count(message, ";"), "ten distinct messages and the remainder are rendered")
+}
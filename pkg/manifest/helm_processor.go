@@ -5,32 +5,39 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"reflect"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/kube"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	manifestTypes "github.com/kyma-project/module-manager/pkg/client"
 
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/resource"
+	"github.com/kyma-project/module-manager/pkg/tracing"
 	"github.com/kyma-project/module-manager/pkg/types"
 	"github.com/kyma-project/module-manager/pkg/util"
 )
 
 type helm struct {
-	clients         *manifestTypes.SingletonClients
-	settings        *cli.EnvSettings
-	repoHandler     *RepoHandler
-	logger          logr.Logger
-	forceCRDRemoval bool
+	clients     *manifestTypes.SingletonClients
+	settings    *cli.EnvSettings
+	repoHandler *RepoHandler
+	logger      logr.Logger
 	*Rendered
 }
 
@@ -47,15 +54,14 @@ var accessor = meta.NewAccessor()
 // On the returned helm instance, installation, uninstallation and verification checks
 // can be executed on the resource manifest.
 func NewHelmProcessor(clients *manifestTypes.SingletonClients, settings *cli.EnvSettings,
-	logger logr.Logger, render *Rendered, deployInfo *types.InstallInfo, forceCRDRemoval bool,
+	logger logr.Logger, render *Rendered, deployInfo *types.InstallInfo,
 ) (types.ManifestClient, error) {
 	helmClient := &helm{
-		clients:         clients,
-		logger:          logger,
-		repoHandler:     NewRepoHandler(logger, settings),
-		settings:        settings,
-		Rendered:        render,
-		forceCRDRemoval: forceCRDRemoval,
+		clients:     clients,
+		logger:      logger,
+		repoHandler: NewRepoHandler(logger, settings),
+		settings:    settings,
+		Rendered:    render,
 	}
 
 	// verify compliance of interface
@@ -80,7 +86,7 @@ func (h *helm) GetRawManifest(info *types.InstallInfo) *types.ParsedFile {
 
 	// if Rendered manifest doesn't exist
 	// check newly Rendered manifest here
-	return types.NewParsedFile(h.renderReleaseFromChartPath(info.Ctx, chartPath, info.Flags.SetFlags))
+	return types.NewParsedFile(h.renderReleaseFromChartPath(info.Ctx, chartPath, info.Flags.SetFlags, info.Release))
 }
 
 func (h *helm) resolveChartPath(info *types.InstallInfo) (string, error) {
@@ -106,8 +112,42 @@ func (h *helm) Install(stringifedManifest string, info *types.InstallInfo, trans
 		return false, err
 	}
 
+	return h.applyResources(resourceLists, info, postRuns)
+}
+
+// Upgrade transforms and applies Helm based manifest using helm client as an upgrade of the previous manifest.
+// Resources of the previous manifest are part of the original state of the three-way merge,
+// so that resources no longer part of the manifest are removed.
+func (h *helm) Upgrade(previous, stringifedManifest string, info *types.InstallInfo,
+	transforms []types.ObjectTransform, postRuns []types.PostRun,
+) (bool, error) {
+	if previous == "" {
+		return h.Install(stringifedManifest, info, transforms, postRuns)
+	}
+
+	// convert for Helm processing
+	resourceLists, err := h.parseToResourceLists(stringifedManifest, info, transforms, true)
+	if err != nil {
+		return false, err
+	}
+	previousResourceLists, err := h.parseToResourceLists(previous, info, transforms, false)
+	if err != nil {
+		return false, fmt.Errorf("could not process previous manifest: %w", err)
+	}
+	resourceLists.Installed = append(resourceLists.Installed,
+		previousResourceLists.Installed.Difference(resourceLists.Installed)...)
+
+	return h.applyResources(resourceLists, info, postRuns)
+}
+
+func (h *helm) applyResources(resourceLists types.ResourceLists, info *types.InstallInfo, postRuns []types.PostRun,
+) (bool, error) {
+	if err := h.verifyNamespace(info); err != nil {
+		return false, err
+	}
+
 	// install resources
-	result, err := h.installResources(resourceLists, false)
+	result, err := h.installResources(info.Ctx, resourceLists, false)
 	if err != nil {
 		return false, err
 	}
@@ -135,7 +175,7 @@ func (h *helm) Install(stringifedManifest string, info *types.InstallInfo, trans
 
 	// update helm repositories
 	if info.UpdateRepositories {
-		if err = h.updateRepos(info.Ctx); err != nil {
+		if err := h.updateRepos(info.Ctx); err != nil {
 			return false, err
 		}
 	}
@@ -167,8 +207,14 @@ func (h *helm) Uninstall(stringifedManifest string, info *types.InstallInfo, tra
 		}
 	}
 
+	// CRDs rendered with the chart templates are kept unless their removal is requested
+	if !info.CRDPolicy.DeletesCRDs() {
+		resourceLists.Installed = withoutCRDs(resourceLists.Installed)
+		resourceLists.Target = withoutCRDs(resourceLists.Target)
+	}
+
 	// uninstall resources
-	err = h.uninstallResources(resourceLists.Installed)
+	err = h.uninstallResources(info.Ctx, resourceLists.Installed, info.DeletionPolicy)
 	if err != nil {
 		return false, err
 	}
@@ -193,20 +239,24 @@ func (h *helm) Uninstall(stringifedManifest string, info *types.InstallInfo, tra
 		return false, err
 	}
 
-	// include CRDs means that the Chart will include the CRDs during rendering, if this is set to false,
-	// we go into the cluster on our own and remove the CRDs since the uninstallation will not have included
-	// the CRDs (as they were not part of the original render)
-	// if CRDRemoval is forced, it will overwrite the IncludeCRDs flag, but if you do already include CRDs
-	// in the rendering, the uninstallation will also try to delete these resources.
-	if h.forceCRDRemoval || !h.clients.Install().IncludeCRDs {
-		// delete all crds located in the original helm chart
-		// WARNING: this can be dangerous if another operator is relying on CRDs here!
-		// If the chart is removed while another chart is depending on it, it can cause havoc!
-		// For more info, see
-		// https://helm.sh/docs/chart_best_practices/custom_resource_definitions/#some-caveats-and-explanations
+	// CRDs are only removed with types.CRDPolicyDelete, as their removal also deletes all custom resources
+	// of them in the cluster, including the ones created by users or depended on by other charts.
+	// For more info, see
+	// https://helm.sh/docs/chart_best_practices/custom_resource_definitions/#some-caveats-and-explanations
+	// Include CRDs means that the Chart includes the CRDs during rendering, so they were already removed above.
+	// Otherwise, we go into the cluster on our own and remove the CRDs located in the original helm chart.
+	if info.CRDPolicy.DeletesCRDs() && !h.clients.Install().IncludeCRDs {
 		if err := h.uninstallChartCRDs(info); err != nil {
 			return false, err
 		}
+	} else if !info.CRDPolicy.DeletesCRDs() {
+		h.logger.V(util.DebugLogLevel).Info("keeping CRDs of uninstalled Helm chart",
+			"chart", info.ChartName,
+			"resource", client.ObjectKeyFromObject(info.BaseResource).String())
+	}
+
+	if err := h.deleteNamespace(info); err != nil {
+		return false, err
 	}
 
 	// update Helm repositories
@@ -219,6 +269,50 @@ func (h *helm) Uninstall(stringifedManifest string, info *types.InstallInfo, tra
 	return true, nil
 }
 
+// verifyNamespace returns ErrNamespaceNotFound, if the namespace of the install must exist, but is missing.
+func (h *helm) verifyNamespace(info *types.InstallInfo) error {
+	namespace := h.clients.Install().Namespace
+	if info.NamespacePolicy != types.NamespaceMustExist || namespace == v1.NamespaceDefault {
+		return nil
+	}
+	clientSet, err := h.clients.KubernetesClientSet()
+	if err != nil {
+		return err
+	}
+	if _, err := clientSet.CoreV1().Namespaces().Get(info.Ctx, namespace, v1.GetOptions{}); apierrors.IsNotFound(err) {
+		return fmt.Errorf("%w: %s", ErrNamespaceNotFound, namespace)
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// deleteNamespace deletes the namespace of the install with types.NamespaceCreateAndDelete,
+// if it was created for the install. Namespaces which existed before, e.g. shared ones, are kept.
+func (h *helm) deleteNamespace(info *types.InstallInfo) error {
+	namespace := h.clients.Install().Namespace
+	if info.NamespacePolicy != types.NamespaceCreateAndDelete || namespace == v1.NamespaceDefault {
+		return nil
+	}
+	clientSet, err := h.clients.KubernetesClientSet()
+	if err != nil {
+		return err
+	}
+	namespaceObj, err := clientSet.CoreV1().Namespaces().Get(info.Ctx, namespace, v1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if namespaceObj.GetLabels()[labels.OwnedByLabel] != ownedBy(info.BaseResource) {
+		h.logger.V(util.DebugLogLevel).Info("keeping namespace not created for the install",
+			"namespace", namespace,
+			"resource", client.ObjectKeyFromObject(info.BaseResource).String())
+		return nil
+	}
+	return client.IgnoreNotFound(clientSet.CoreV1().Namespaces().Delete(info.Ctx, namespace, v1.DeleteOptions{}))
+}
+
 func isHelmClientNotFound(err error) bool {
 	// Refactoring this error check after this PR get merged and released https://github.com/helm/helm/pull/11591
 	if err != nil && strings.Contains(err.Error(), "object not found, skipping delete") {
@@ -251,6 +345,9 @@ func (h *helm) uninstallChartCRDs(info *types.InstallInfo) error {
 	if err != nil {
 		return err
 	}
+	if err := info.Ctx.Err(); err != nil {
+		return err
+	}
 	_, deleteErrs := h.clients.KubeClient().Delete(resourceList)
 	if len(deleteErrs) > 0 {
 		filteredErrs := filterNotFoundError(deleteErrs)
@@ -282,8 +379,12 @@ func (h *helm) IsConsistent(stringifedManifest string, info *types.InstallInfo,
 			"resource", client.ObjectKeyFromObject(info.BaseResource).String())
 	}
 
+	if err := h.verifyNamespace(info); err != nil {
+		return false, err
+	}
+
 	// install resources without force, it will lead to 3 way merge / JSON apply patches
-	result, err := h.installResources(resourceLists, false)
+	result, err := h.installResources(info.Ctx, resourceLists, false)
 	if err != nil {
 		return false, err
 	}
@@ -322,12 +423,25 @@ func (h *helm) IsConsistent(stringifedManifest string, info *types.InstallInfo,
 	return true, nil
 }
 
-func (h *helm) installResources(resourceLists types.ResourceLists, force bool) (*kube.Result, error) {
+// installResources creates or updates the resources using the Helm kube client.
+// As the kube client does not accept a context, it is verified in between requests.
+func (h *helm) installResources(ctx context.Context, resourceLists types.ResourceLists, force bool,
+) (_ *kube.Result, err error) {
+	_, span := tracing.Start(ctx, tracing.SpanApply, attribute.Int("resources", len(resourceLists.Target)))
+	defer func() { tracing.End(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// create namespace resource first!
 	if len(resourceLists.Namespace) > 0 {
 		if _, err := h.clients.KubeClient().Create(resourceLists.Namespace); err != nil && !apierrors.IsAlreadyExists(err) {
 			return nil, err
 		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 	}
 
 	// fresh install
@@ -339,7 +453,20 @@ func (h *helm) installResources(resourceLists types.ResourceLists, force bool) (
 	return h.clients.KubeClient().Update(resourceLists.Installed, resourceLists.Target, force)
 }
 
-func (h *helm) uninstallResources(installedResources kube.ResourceList) error {
+// uninstallResources deletes the resources using the Helm kube client, or according to the passed
+// types.DeletionPolicy if set, as the kube client deletes all resources at once in the background.
+func (h *helm) uninstallResources(ctx context.Context, installedResources kube.ResourceList,
+	policy *types.DeletionPolicy,
+) (err error) {
+	ctx, span := tracing.Start(ctx, tracing.SpanDelete, attribute.Int("resources", len(installedResources)))
+	defer func() { tracing.End(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if policy != nil {
+		return deleteResources(ctx, installedResources, policy)
+	}
 	var deleteErrs []error
 	if installedResources != nil {
 		_, deleteErrs = h.clients.KubeClient().Delete(installedResources)
@@ -368,24 +495,21 @@ func (h *helm) checkTargetResources(ctx context.Context,
 	targetResources kube.ResourceList,
 	operation types.HelmOperation,
 	verifyWithoutTimeout bool,
-) error {
+) (err error) {
+	ctx, span := tracing.Start(ctx, tracing.SpanReadiness, attribute.String("operation", string(operation)),
+		attribute.Int("resources", len(targetResources)))
+	defer func() { tracing.End(span, err) }()
+
 	// verifyWithoutTimeout flag checks native resources are in their respective ready states
 	// without a timeout defined
 	if verifyWithoutTimeout {
 		if operation == types.OperationDelete {
-			return checkResourcesDeleted(targetResources)
+			return checkResourcesDeleted(ctx, targetResources)
 		}
-		clientSet, err := h.clients.KubernetesClientSet()
+		readyChecker, err := h.readyChecker(true)
 		if err != nil {
 			return err
 		}
-		readyChecker := kube.NewReadyChecker(clientSet,
-			func(format string, args ...interface{}) {
-				h.logger.V(util.DebugLogLevel).Info(fmt.Sprintf(format, args...))
-			},
-			kube.PausedAsReady(true),
-			kube.CheckJobs(true))
-
 		return checkReady(ctx, targetResources, readyChecker)
 	}
 
@@ -395,17 +519,33 @@ func (h *helm) checkTargetResources(ctx context.Context,
 		return nil
 	}
 
+	// waiting is done with the context aware waiters instead of the kube client,
+	// so that a cancelled reconciliation does not block until the timeout is reached
 	if operation == types.OperationDelete {
-		// WaitForDelete reports an error if resources are not deleted in the specified timeout
-		return h.clients.KubeClient().WaitForDelete(targetResources, h.clients.Install().Timeout)
+		// WaitForDeleted reports an error if resources are not deleted in the specified timeout
+		return WaitForDeleted(ctx, targetResources, h.clients.Install().Timeout)
 	}
 
-	if h.clients.Install().WaitForJobs {
-		// WaitWithJobs reports an error if resources are not deleted in the specified timeout
-		return h.clients.KubeClient().WaitWithJobs(targetResources, h.clients.Install().Timeout)
+	// WaitForReady reports an error if resources are not ready in the specified timeout,
+	// jobs are only verified if WaitForJobs is enabled
+	readyChecker, err := h.readyChecker(h.clients.Install().WaitForJobs)
+	if err != nil {
+		return err
 	}
-	// Wait reports an error if resources are not deleted in the specified timeout
-	return h.clients.KubeClient().Wait(targetResources, h.clients.Install().Timeout)
+	return WaitForReady(ctx, targetResources, readyChecker, h.clients.Install().Timeout)
+}
+
+func (h *helm) readyChecker(checkJobs bool) (kube.ReadyChecker, error) {
+	clientSet, err := h.clients.KubernetesClientSet()
+	if err != nil {
+		return kube.ReadyChecker{}, err
+	}
+	return kube.NewReadyChecker(clientSet,
+		func(format string, args ...interface{}) {
+			h.logger.V(util.DebugLogLevel).Info(fmt.Sprintf(format, args...))
+		},
+		kube.PausedAsReady(true),
+		kube.CheckJobs(checkJobs)), nil
 }
 
 func (h *helm) updateRepos(ctx context.Context) error {
@@ -414,10 +554,10 @@ func (h *helm) updateRepos(ctx context.Context) error {
 
 func (h *helm) resetFlags(deployInfo *types.InstallInfo) error {
 	// set preliminary flag defaults
-	h.setDefaultFlags(deployInfo.ReleaseName)
+	setDefaultFlags(h.clients.Install(), deployInfo.ReleaseName)
 
 	// set user defined flags
-	return h.setCustomFlags(deployInfo.Flags)
+	return setCustomFlags(h.clients.Install(), deployInfo.Flags)
 }
 
 func (h *helm) downloadChart(repoName, url, chartName string) (string, error) {
@@ -425,14 +565,22 @@ func (h *helm) downloadChart(repoName, url, chartName string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return h.clients.Install().ChartPathOptions.LocateChart(chartName, h.settings)
+	chartPath, err := h.clients.Install().ChartPathOptions.LocateChart(chartName, h.settings)
+	if err != nil {
+		return "", types.ErrChartNotFound.Wrap(err)
+	}
+	return chartPath, nil
 }
 
-func (h *helm) renderReleaseFromChartPath(ctx context.Context, chartPath string, flags types.Flags) (string, error) {
+func (h *helm) renderReleaseFromChartPath(ctx context.Context, chartPath string, flags types.Flags,
+	state *types.ReleaseState,
+) (string, error) {
 	// if Rendered manifest doesn't exist
 	chartRequested, err := h.repoHandler.LoadChart(chartPath, h.clients.Install())
-	if err != nil {
-		return "", err
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", types.ErrChartNotFound.Wrap(err)
+	} else if err != nil {
+		return "", renderFailure(err)
 	}
 
 	// include CRDs means that the Chart will include the CRDs during rendering, if this is set to false,
@@ -445,10 +593,15 @@ func (h *helm) renderReleaseFromChartPath(ctx context.Context, chartPath string,
 		}
 	}
 
+	// render the revision of the release history, action.Install would always render the first revision
+	if state != nil {
+		return h.renderRevision(chartRequested, flags, *state)
+	}
+
 	// retrieve manifest
-	release, err := h.clients.Install().Run(chartRequested, flags)
+	release, err := h.clients.Install().RunWithContext(ctx, chartRequested, flags)
 	if err != nil {
-		return "", err
+		return "", renderFailure(err)
 	}
 
 	return release.Manifest, nil
@@ -461,7 +614,7 @@ func (h *helm) renderReleaseFromChartPath(ctx context.Context, chartPath string,
 // There are 3 key differences between the method in the HELM client and this one
 // First, it resets the singleton client, allowing us to only reset the Mapper once per render process.
 // Second, it parses the manifest string based on the existing methods available in the HELM client.
-// Third, it creates crds and collects requirements concurrently to speed up CRD creation.
+// Third, existing CRDs are upgraded, unless the upgrade removes a stored version, see resource.InstallCRDs.
 func (h *helm) optimizedInstallCRDs(ctx context.Context, chartRequested *chart.Chart) error {
 	crds, err := h.crdsFromChart(chartRequested)
 	if err != nil {
@@ -473,25 +626,16 @@ func (h *helm) optimizedInstallCRDs(ctx context.Context, chartRequested *chart.C
 		return err
 	}
 
-	crdInstallWaitGroup := sync.WaitGroup{}
-	errs := make(chan error, len(resList))
-	createCRD := func(i int) {
-		defer crdInstallWaitGroup.Done()
-		_, err := h.clients.KubeClient().Create(kube.ResourceList{resList[i]})
-		errs <- err
-	}
-
-	for i := range resList {
-		crdInstallWaitGroup.Add(1)
-		go createCRD(i)
+	crdObjects := make([]*unstructured.Unstructured, 0, len(resList))
+	for _, info := range resList {
+		crdObject, err := runtime.DefaultUnstructuredConverter.ToUnstructured(info.Object)
+		if err != nil {
+			return err
+		}
+		crdObjects = append(crdObjects, &unstructured.Unstructured{Object: crdObject})
 	}
-	crdInstallWaitGroup.Wait()
-	close(errs)
 
-	for err := range errs {
-		if err == nil || apierrors.IsAlreadyExists(err) {
-			continue
-		}
+	if err := resource.InstallCRDs(ctx, crdObjects, h.clients); err != nil {
 		h.logger.Error(err, "failed on crd installation as pre-requisite for helm rendering")
 		return err
 	}
@@ -516,33 +660,34 @@ func (h *helm) crdsFromChart(chart *chart.Chart) (*bytes.Buffer, error) {
 	return &crdManifest, nil
 }
 
-func (h *helm) setDefaultFlags(releaseName string) {
-	h.clients.Install().DryRun = true
-	h.clients.Install().Atomic = false
+func setDefaultFlags(actionClient *action.Install, releaseName string) {
+	actionClient.DryRun = true
+	actionClient.Atomic = false
 
-	h.clients.Install().WaitForJobs = false
+	actionClient.WaitForJobs = false
 
-	h.clients.Install().Replace = true // Skip the name check
-	h.clients.Install().IncludeCRDs = false
-	h.clients.Install().UseReleaseName = false
-	h.clients.Install().ReleaseName = releaseName
+	actionClient.Replace = true // Skip the name check
+	actionClient.IncludeCRDs = false
+	actionClient.UseReleaseName = false
+	// Helm rejects release names, which are not valid DNS-1123 names of at most 53 characters
+	actionClient.ReleaseName = util.NormalizeReleaseName(releaseName)
 
 	// ClientOnly has no interaction with the API server
 	// So unless mentioned no additional API Versions can be used as part of helm chart installation
-	h.clients.Install().ClientOnly = false
+	actionClient.ClientOnly = false
 
-	h.clients.Install().Namespace = v1.NamespaceDefault
+	actionClient.Namespace = v1.NamespaceDefault
 	// this will prohibit resource conflict validation while uninstalling
-	h.clients.Install().IsUpgrade = true
+	actionClient.IsUpgrade = true
 
 	// default versioning if unspecified
-	if h.clients.Install().Version == "" && h.clients.Install().Devel {
-		h.clients.Install().Version = ">0.0.0-0"
+	if actionClient.Version == "" && actionClient.Devel {
+		actionClient.Version = ">0.0.0-0"
 	}
 }
 
-func (h *helm) setCustomFlags(flags types.ChartFlags) error {
-	clientValue := reflect.Indirect(reflect.ValueOf(h.clients.Install()))
+func setCustomFlags(actionClient *action.Install, flags types.ChartFlags) error {
+	clientValue := reflect.Indirect(reflect.ValueOf(actionClient))
 
 	// TODO: as per requirements add more Kind types
 	for flagKey, flagValue := range flags.ConfigFlags {
@@ -598,7 +743,7 @@ func (h *helm) setCustomFlags(flags types.ChartFlags) error {
 func (h *helm) parseToResourceLists(stringifiedManifest string, deployInfo *types.InstallInfo,
 	transforms []types.ObjectTransform, retryOnNoMatch bool,
 ) (types.ResourceLists, error) {
-	nsResourceList, err := h.GetNsResource()
+	nsResourceList, err := h.GetNsResource(deployInfo)
 	if err != nil {
 		return types.ResourceLists{Namespace: nsResourceList}, err
 	}
@@ -626,19 +771,27 @@ func (h *helm) parseToResourceLists(stringifiedManifest string, deployInfo *type
 	return list, nil
 }
 
-func (h *helm) GetNsResource() (kube.ResourceList, error) {
+// GetNsResource returns the namespace of the install, if it is created according to its namespace policy.
+func (h *helm) GetNsResource(deployInfo *types.InstallInfo) (kube.ResourceList, error) {
 	// set kubeclient namespace for override
 	// TODO remove setting in kubeclient
 	h.clients.KubeClient().Namespace = h.clients.Install().Namespace
 
 	// validate namespace parameters
 	// proceed only if not default namespace since it already exists
-	if !h.clients.Install().CreateNamespace || h.clients.Install().Namespace == v1.NamespaceDefault {
+	if !deployInfo.NamespacePolicy.CreatesNamespace(h.clients.Install().CreateNamespace) ||
+		h.clients.Install().Namespace == v1.NamespaceDefault {
 		return nil, nil
 	}
 
+	// namespaces deleted on uninstall are marked as owned, as only namespaces created for the install are deleted
+	nsLabels := map[string]string{}
+	if deployInfo.NamespacePolicy == types.NamespaceCreateAndDelete && deployInfo.BaseResource != nil {
+		nsLabels[labels.OwnedByLabel] = ownedBy(deployInfo.BaseResource)
+	}
+
 	ns := h.clients.Install().Namespace
-	nsBuf, err := util.GetNamespaceObjBytes(ns)
+	nsBuf, err := util.GetNamespaceObjBytes(ns, nsLabels)
 	if err != nil {
 		return nil, err
 	}
@@ -720,3 +873,11 @@ func (h *helm) GetClusterInfo() (types.ClusterInfo, error) {
 		Config: restConfig,
 	}, nil
 }
+
+func (h *helm) InvalidateOnServerVersionChange(interval time.Duration) (bool, error) {
+	return h.clients.InvalidateOnServerVersionChange(interval)
+}
+
+func (h *helm) GetServerVersion() (*version.Info, error) {
+	return h.clients.ServerVersion()
+}
@@ -3,25 +3,37 @@ package manifest
 import (
 	"context"
 	"errors"
+	"path/filepath"
 
+	"helm.sh/helm/v3/pkg/chartutil"
 	"helm.sh/helm/v3/pkg/kube"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/resource"
+
+	"github.com/kyma-project/module-manager/pkg/types"
 )
 
 var (
 	ErrResourceNotReady   = errors.New("resource not ready")
 	ErrResourceNotDeleted = errors.New("resource not deleted")
+	ErrNamespaceNotFound  = errors.New("namespace required by install not found")
 )
 
-func checkResourcesDeleted(targetResources kube.ResourceList) error {
+//nolint:gochecknoglobals
+var crdGroupKind = apiextensionsv1.Kind("CustomResourceDefinition")
+
+func checkResourcesDeleted(ctx context.Context, targetResources kube.ResourceList) error {
 	return targetResources.Visit(func(info *resource.Info, err error) error {
 		if err != nil {
 			return err
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		err = info.Get()
 		if err == nil {
 			return ErrResourceNotDeleted
@@ -71,6 +83,9 @@ func checkReady(ctx context.Context, resourceList kube.ResourceList, readyChecke
 		if err != nil {
 			return err
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		ready, err := readyChecker.IsReady(ctx, info)
 		if !ready {
 			return ErrResourceNotReady
@@ -81,3 +96,23 @@ func checkReady(ctx context.Context, resourceList kube.ResourceList, readyChecke
 		return nil
 	})
 }
+
+// ChartVersion returns the version of the chart of the install, if its chart path contains a chart.
+// Otherwise, e.g. for kustomize installs, an empty version is returned.
+func ChartVersion(info *types.InstallInfo) string {
+	if info.ChartInfo == nil || info.ChartPath == "" {
+		return ""
+	}
+	metadata, err := chartutil.LoadChartfile(filepath.Join(info.ChartPath, chartutil.ChartfileName))
+	if err != nil {
+		return ""
+	}
+	return metadata.Version
+}
+
+// withoutCRDs returns the resources without CustomResourceDefinitions.
+func withoutCRDs(resources kube.ResourceList) kube.ResourceList {
+	return resources.Filter(func(info *resource.Info) bool {
+		return info.Mapping == nil || info.Mapping.GroupVersionKind.GroupKind() != crdGroupKind
+	})
+}
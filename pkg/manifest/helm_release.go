@@ -0,0 +1,139 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+const notesFileSuffix = "NOTES.txt"
+
+// RenderChart renders the manifest of the chart for the passed release state, which is exposed to templates as
+// .Release.Revision, .Release.IsInstall and .Release.IsUpgrade. It is oriented on the dry-run of action.Install,
+// which always renders the first revision and validates conflicts with existing resources of first installs.
+// As the release is only rendered, hooks are skipped, like with action.Install, see RenderHooks.
+func RenderChart(chrt *chart.Chart, values map[string]interface{}, releaseName, namespace string,
+	state types.ReleaseState, caps *chartutil.Capabilities, includeCRDs bool,
+) (string, error) {
+	_, manifests, err := renderChartFiles(chrt, values, releaseName, namespace, state, caps)
+	if err != nil {
+		return "", err
+	}
+
+	var manifest bytes.Buffer
+	if includeCRDs {
+		for _, crd := range chrt.CRDObjects() {
+			fmt.Fprintf(&manifest, "---\n# Source: %s\n%s\n", crd.Name, string(crd.File.Data))
+		}
+	}
+	for _, m := range manifests {
+		fmt.Fprintf(&manifest, "---\n# Source: %s\n%s\n", m.Name, m.Content)
+	}
+	return manifest.String(), nil
+}
+
+// renderChartFiles renders the templates of the chart for the passed release state, and returns the hooks
+// and the manifests of the rendered templates in install order.
+func renderChartFiles(chrt *chart.Chart, values map[string]interface{}, releaseName, namespace string,
+	state types.ReleaseState, caps *chartutil.Capabilities,
+) ([]*release.Hook, []releaseutil.Manifest, error) {
+	if err := chartutil.ProcessDependencies(chrt, values); err != nil {
+		return nil, nil, err
+	}
+	if chrt.Metadata.KubeVersion != "" &&
+		!chartutil.IsCompatibleRange(chrt.Metadata.KubeVersion, caps.KubeVersion.String()) {
+		return nil, nil, fmt.Errorf("chart requires kubeVersion: %s which is incompatible with Kubernetes %s",
+			chrt.Metadata.KubeVersion, caps.KubeVersion.String())
+	}
+
+	renderValues, err := chartutil.ToRenderValues(chrt, values, chartutil.ReleaseOptions{
+		Name:      releaseName,
+		Namespace: namespace,
+		Revision:  state.Revision,
+		IsInstall: !state.IsUpgrade(),
+		IsUpgrade: state.IsUpgrade(),
+	}, caps)
+	if err != nil {
+		return nil, nil, err
+	}
+	files, err := engine.Render(chrt, renderValues)
+	if err != nil {
+		return nil, nil, err
+	}
+	for name := range files {
+		if strings.HasSuffix(name, notesFileSuffix) {
+			delete(files, name)
+		}
+	}
+	return releaseutil.SortManifests(files, caps.APIVersions, releaseutil.InstallOrder)
+}
+
+// RenderHooks renders the hooks of the chart for the passed release state, ordered by their weight and name,
+// in which Helm executes hooks of the same event.
+func RenderHooks(chrt *chart.Chart, values map[string]interface{}, releaseName, namespace string,
+	state types.ReleaseState, caps *chartutil.Capabilities,
+) ([]*release.Hook, error) {
+	hooks, _, err := renderChartFiles(chrt, values, releaseName, namespace, state, caps)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(hooks, func(i, j int) bool {
+		if hooks[i].Weight != hooks[j].Weight {
+			return hooks[i].Weight < hooks[j].Weight
+		}
+		return hooks[i].Name < hooks[j].Name
+	})
+	return hooks, nil
+}
+
+// renderRevision renders the manifest of the chart for the passed release state with the flags of the install.
+func (h *helm) renderRevision(chrt *chart.Chart, values map[string]interface{}, state types.ReleaseState,
+) (string, error) {
+	caps, err := h.capabilities()
+	if err != nil {
+		return "", err
+	}
+	install := h.clients.Install()
+	manifest, err := RenderChart(chrt, values, install.ReleaseName, install.Namespace, state, caps, install.IncludeCRDs)
+	if err != nil {
+		return "", renderFailure(err)
+	}
+	return manifest, nil
+}
+
+// capabilities returns the capabilities of the target cluster available to templates,
+// as determined by Helm for action.Install.
+func (h *helm) capabilities() (*chartutil.Capabilities, error) {
+	discoveryClient, err := h.clients.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	kubeVersion, err := discoveryClient.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("could not get server version from Kubernetes: %w", err)
+	}
+	// orphaned API services are ignored, the discovery client returns all other API versions
+	apiVersions, err := action.GetVersionSet(discoveryClient)
+	if err != nil {
+		return nil, err
+	}
+	return &chartutil.Capabilities{
+		APIVersions: apiVersions,
+		KubeVersion: chartutil.KubeVersion{
+			Version: kubeVersion.GitVersion,
+			Major:   kubeVersion.Major,
+			Minor:   kubeVersion.Minor,
+		},
+		HelmVersion: chartutil.DefaultCapabilities.HelmVersion,
+	}, nil
+}
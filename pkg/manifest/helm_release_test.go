@@ -0,0 +1,64 @@
+package manifest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+
+	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+func releaseChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "sample", Version: "1.0.0"},
+		Templates: []*chart.File{
+			{Name: "templates/configmap.yaml", Data: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+  namespace: {{ .Release.Namespace }}
+data:
+  revision: "{{ .Release.Revision }}"
+  install: "{{ .Release.IsInstall }}"
+`)},
+			{Name: "templates/migration.yaml", Data: []byte(`{{- if .Release.IsUpgrade }}
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migration
+{{- end }}
+`)},
+			{Name: "templates/NOTES.txt", Data: []byte("installed {{ .Release.Name }}")},
+		},
+	}
+}
+
+func Test_RenderChart(t *testing.T) {
+	t.Parallel()
+	caps := chartutil.DefaultCapabilities
+
+	rendered, err := manifest.RenderChart(releaseChart(), map[string]interface{}{}, "sample", "default",
+		types.ReleaseState{Revision: 1}, caps, false)
+	require.NoError(t, err)
+	assert.Contains(t, rendered, `revision: "1"`)
+	assert.Contains(t, rendered, `install: "true"`)
+	assert.NotContains(t, rendered, "kind: Job", "migrations are skipped on install")
+	assert.NotContains(t, rendered, "installed sample", "notes are not part of the manifest")
+
+	rendered, err = manifest.RenderChart(releaseChart(), map[string]interface{}{}, "sample", "default",
+		types.ReleaseState{Revision: 3}, caps, false)
+	require.NoError(t, err)
+	assert.Contains(t, rendered, `revision: "3"`)
+	assert.Contains(t, rendered, `install: "false"`)
+	assert.Contains(t, rendered, "kind: Job")
+
+	incompatible := releaseChart()
+	incompatible.Metadata.KubeVersion = "<1.0.0"
+	_, err = manifest.RenderChart(incompatible, map[string]interface{}{}, "sample", "default",
+		types.ReleaseState{Revision: 1}, caps, false)
+	require.Error(t, err)
+}
@@ -0,0 +1,281 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v3/pkg/release"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+const (
+	hookLedgerPrefix  = "hooks"
+	hookLedgerDataKey = "hooks"
+	// defaultHookTimeout limits the execution of a single hook, if the install has no timeout.
+	defaultHookTimeout = 5 * time.Minute
+)
+
+var ErrHookFailed = errors.New("hook failed")
+
+// hookExecutor is implemented by manifest clients supporting Helm hooks.
+type hookExecutor interface {
+	renderHooks(info *types.InstallInfo) ([]*release.Hook, error)
+	execHook(info *types.InstallInfo, hook *release.Hook) error
+}
+
+// HookRecord records the hooks run for the manifests of an install.
+type HookRecord struct {
+	// Released is the hash of the manifest whose post hooks completed last. Manifests are installed
+	// with the install hooks as long as no manifest was released, and with the upgrade hooks afterwards.
+	Released string `json:"released,omitempty"`
+	// Checksums are the checksums of the hooks run for the manifest, by event, kind and name of the hook.
+	// Hooks are not run again for the same manifest, e.g. if the install is retried.
+	Checksums map[string]string `json:"checksums,omitempty"`
+}
+
+// HookLedger records the HookRecord of a single install of a custom resource in the target cluster.
+// It is stored as a ConfigMap in InventoryNamespace next to the Inventory of the install,
+// as hooks deleted by their hook-delete-policy leave no trace in the cluster.
+type HookLedger struct {
+	clnt client.Client
+	key  client.ObjectKey
+}
+
+// NewHookLedger returns the HookLedger of the given release, owned by the passed base resource.
+func NewHookLedger(clnt client.Client, owner client.Object, releaseName string) *HookLedger {
+	name := util.NormalizeSubdomain(strings.Join(
+		[]string{hookLedgerPrefix, owner.GetNamespace(), owner.GetName(), releaseName}, "."),
+	)
+	return &HookLedger{clnt: clnt, key: client.ObjectKey{Namespace: InventoryNamespace, Name: name}}
+}
+
+// Load returns the recorded HookRecord, which is empty if no hook was run yet.
+func (l *HookLedger) Load(ctx context.Context) (*HookRecord, error) {
+	record := &HookRecord{Checksums: map[string]string{}}
+	configMap := &v1.ConfigMap{}
+	if err := l.clnt.Get(ctx, l.key, configMap); apierrors.IsNotFound(err) {
+		return record, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("loading hook ledger %s: %w", l.key, err)
+	}
+	if err := json.Unmarshal([]byte(configMap.Data[hookLedgerDataKey]), record); err != nil {
+		return nil, fmt.Errorf("decoding hook ledger %s: %w", l.key, err)
+	}
+	if record.Checksums == nil {
+		record.Checksums = map[string]string{}
+	}
+	return record, nil
+}
+
+// Store records the passed HookRecord.
+func (l *HookLedger) Store(ctx context.Context, record *HookRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	configMap := &v1.ConfigMap{}
+	configMap.SetName(l.key.Name)
+	configMap.SetNamespace(l.key.Namespace)
+	if _, err := controllerutil.CreateOrUpdate(ctx, l.clnt, configMap, func() error {
+		configMap.SetLabels(map[string]string{labels.ManagedBy: labels.OperatorName})
+		configMap.Data = map[string]string{hookLedgerDataKey: string(data)}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("storing hook ledger %s: %w", l.key, err)
+	}
+	return nil
+}
+
+// Purge removes the recorded HookRecord, e.g. on uninstall.
+func (l *HookLedger) Purge(ctx context.Context) error {
+	configMap := &v1.ConfigMap{}
+	configMap.SetName(l.key.Name)
+	configMap.SetNamespace(l.key.Namespace)
+	if err := l.clnt.Delete(ctx, configMap); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("removing hook ledger %s: %w", l.key, err)
+	}
+	return nil
+}
+
+// HookEvent returns the event of the hooks run before or after a manifest is applied,
+// depending on whether a manifest was released before.
+func HookEvent(record *HookRecord, post bool) release.HookEvent {
+	switch {
+	case record.Released == "" && !post:
+		return release.HookPreInstall
+	case record.Released == "":
+		return release.HookPostInstall
+	case !post:
+		return release.HookPreUpgrade
+	default:
+		return release.HookPostUpgrade
+	}
+}
+
+// runHooks runs the hooks of the install, which are run before or after the passed manifest is applied,
+// if its types.HookPolicy runs hooks. Every hook is run once per manifest, after all post hooks completed,
+// the manifest is recorded as released, so that its hooks are not run again.
+func (o *Operations) runHooks(manifest string, post bool) error {
+	executor, supported := o.renderSrc.(hookExecutor)
+	if !o.installInfo.HookPolicy.RunsHooks() || !supported {
+		return nil
+	}
+	manifestHash, err := util.CalculateHash(manifest)
+	if err != nil {
+		return err
+	}
+	released := strconv.FormatUint(uint64(manifestHash), 10)
+
+	ledger := NewHookLedger(o.client, o.installInfo.BaseResource, o.installInfo.ReleaseName)
+	record, err := ledger.Load(o.installInfo.Ctx)
+	if err != nil || record.Released == released {
+		return err
+	}
+	hooks, err := executor.renderHooks(o.installInfo)
+	if err != nil {
+		return err
+	}
+
+	event := HookEvent(record, post)
+	for _, hook := range hooks {
+		if !hasHookEvent(hook, event) {
+			continue
+		}
+		key := strings.Join([]string{string(event), hook.Kind, hook.Name}, "/")
+		hookHash, err := util.CalculateHash(hook.Manifest)
+		if err != nil {
+			return err
+		}
+		checksum := released + "-" + strconv.FormatUint(uint64(hookHash), 10)
+		if record.Checksums[key] == checksum {
+			continue
+		}
+		o.logger.Info("running hook", "event", event, "hook", hook.Path,
+			"resource", client.ObjectKeyFromObject(o.installInfo.BaseResource).String())
+		if err := executor.execHook(o.installInfo, hook); err != nil {
+			return err
+		}
+		record.Checksums[key] = checksum
+		if err := ledger.Store(o.installInfo.Ctx, record); err != nil {
+			return err
+		}
+	}
+
+	if !post {
+		return nil
+	}
+	record.Released = released
+	record.Checksums = nil
+	return ledger.Store(o.installInfo.Ctx, record)
+}
+
+func hasHookEvent(hook *release.Hook, event release.HookEvent) bool {
+	for _, hookEvent := range hook.Events {
+		if hookEvent == event {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDeletePolicy indicates if the hook is deleted on the passed occasion.
+// Hooks without a policy are deleted before they are created again, like with Helm.
+func hasDeletePolicy(hook *release.Hook, policy release.HookDeletePolicy) bool {
+	if len(hook.DeletePolicies) == 0 {
+		return policy == release.HookBeforeHookCreation
+	}
+	for _, deletePolicy := range hook.DeletePolicies {
+		if deletePolicy == policy {
+			return true
+		}
+	}
+	return false
+}
+
+// renderHooks renders the hooks of the chart of the install for the release state of the install.
+func (h *helm) renderHooks(info *types.InstallInfo) ([]*release.Hook, error) {
+	chartPath, err := h.resolveChartPath(info)
+	if err != nil {
+		return nil, err
+	}
+	chrt, err := h.repoHandler.LoadChart(chartPath, h.clients.Install())
+	if err != nil {
+		return nil, renderFailure(err)
+	}
+	caps, err := h.capabilities()
+	if err != nil {
+		return nil, err
+	}
+	state := types.ReleaseState{Revision: 1}
+	if info.Release != nil {
+		state = *info.Release
+	}
+	install := h.clients.Install()
+	hooks, err := RenderHooks(chrt, info.Flags.SetFlags, install.ReleaseName, install.Namespace, state, caps)
+	if err != nil {
+		return nil, renderFailure(err)
+	}
+	return hooks, nil
+}
+
+// execHook creates the resources of the hook and waits until they are ready, e.g. Jobs until they completed,
+// oriented on the execution of hooks by Helm actions. Resources are deleted according to the hook-delete-policy.
+func (h *helm) execHook(info *types.InstallInfo, hook *release.Hook) error {
+	kubeClient := h.clients.KubeClient()
+	kubeClient.Namespace = h.clients.Install().Namespace
+
+	if hasDeletePolicy(hook, release.HookBeforeHookCreation) {
+		if err := h.deleteHook(hook); err != nil {
+			return err
+		}
+	}
+	resources, err := kubeClient.Build(bytes.NewBufferString(hook.Manifest), true)
+	if err != nil {
+		return fmt.Errorf("%w: building %s: %v", ErrHookFailed, hook.Path, err)
+	}
+	if _, err := kubeClient.Create(resources); err != nil {
+		return fmt.Errorf("%w: creating %s: %v", ErrHookFailed, hook.Path, err)
+	}
+
+	timeout := info.Timeout
+	if timeout <= 0 {
+		timeout = defaultHookTimeout
+	}
+	if err := kubeClient.WatchUntilReady(resources, timeout); err != nil {
+		if hasDeletePolicy(hook, release.HookFailed) {
+			if deleteErr := h.deleteHook(hook); deleteErr != nil {
+				h.logger.Error(deleteErr, "could not delete failed hook", "hook", hook.Path)
+			}
+		}
+		return fmt.Errorf("%w: %s: %v", ErrHookFailed, hook.Path, err)
+	}
+	if hasDeletePolicy(hook, release.HookSucceeded) {
+		return h.deleteHook(hook)
+	}
+	return nil
+}
+
+func (h *helm) deleteHook(hook *release.Hook) error {
+	kubeClient := h.clients.KubeClient()
+	resources, err := kubeClient.Build(bytes.NewBufferString(hook.Manifest), false)
+	if err != nil {
+		return fmt.Errorf("%w: building %s: %v", ErrHookFailed, hook.Path, err)
+	}
+	if _, deleteErrs := kubeClient.Delete(resources); len(filterNotFoundError(deleteErrs)) > 0 {
+		return fmt.Errorf("deleting hook %s: %w", hook.Path, types.NewMultiError(filterNotFoundError(deleteErrs)))
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package manifest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+func hookChart() *chart.Chart {
+	hookJob := func(name, events, weight string) *chart.File {
+		return &chart.File{Name: "templates/" + name + ".yaml", Data: []byte(`apiVersion: batch/v1
+kind: Job
+metadata:
+  name: ` + name + `
+  annotations:
+    helm.sh/hook: ` + events + `
+    helm.sh/hook-weight: "` + weight + `"
+    helm.sh/hook-delete-policy: hook-succeeded
+`)}
+	}
+	chrt := releaseChart()
+	chrt.Templates = append(chrt.Templates,
+		hookJob("migrate", "pre-upgrade", "0"),
+		hookJob("setup", "pre-install,pre-upgrade", "-1"),
+		hookJob("verify", "post-install", "0"),
+	)
+	return chrt
+}
+
+func Test_RenderHooks(t *testing.T) {
+	t.Parallel()
+	hooks, err := manifest.RenderHooks(hookChart(), map[string]interface{}{}, "sample", "default",
+		types.ReleaseState{Revision: 1}, chartutil.DefaultCapabilities)
+	require.NoError(t, err)
+	require.Len(t, hooks, 3)
+	assert.Equal(t, []string{"setup", "migrate", "verify"}, []string{hooks[0].Name, hooks[1].Name, hooks[2].Name},
+		"hooks are ordered by weight and name")
+	assert.Equal(t, []release.HookEvent{release.HookPreInstall, release.HookPreUpgrade}, hooks[0].Events)
+	assert.Equal(t, []release.HookDeletePolicy{release.HookSucceeded}, hooks[0].DeletePolicies)
+
+	rendered, err := manifest.RenderChart(hookChart(), map[string]interface{}{}, "sample", "default",
+		types.ReleaseState{Revision: 1}, chartutil.DefaultCapabilities, false)
+	require.NoError(t, err)
+	assert.NotContains(t, rendered, "helm.sh/hook", "hooks are not part of the manifest")
+}
+
+func Test_HookLedger(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	clnt := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	ledger := manifest.NewHookLedger(clnt, configMapObject("owner"), "release")
+
+	record, err := ledger.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, release.HookPreInstall, manifest.HookEvent(record, false))
+	assert.Equal(t, release.HookPostInstall, manifest.HookEvent(record, true))
+
+	record.Checksums["pre-install/Job/setup"] = "1-2"
+	require.NoError(t, ledger.Store(ctx, record))
+	record, err = ledger.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "1-2", record.Checksums["pre-install/Job/setup"])
+
+	record.Released = "1"
+	require.NoError(t, ledger.Store(ctx, record))
+	record, err = ledger.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, release.HookPreUpgrade, manifest.HookEvent(record, false))
+	assert.Equal(t, release.HookPostUpgrade, manifest.HookEvent(record, true))
+
+	require.NoError(t, ledger.Purge(ctx))
+	require.NoError(t, ledger.Purge(ctx), "purging is idempotent")
+	record, err = ledger.Load(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, record.Released)
+}
@@ -0,0 +1,141 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+const (
+	installAttemptPrefix  = "attempt"
+	installAttemptDataKey = "attempt"
+)
+
+// InstallAttempt records the resources applied by failed attempts of an install, which has not completed yet.
+// The entries are all resources of the attempted manifests, which were applied only partially.
+type InstallAttempt struct {
+	// Entries are the resources applied by the attempt
+	Entries []InventoryEntry `json:"entries"`
+	// Error is the failure of the latest attempt
+	Error string `json:"error"`
+	// FirstError is the first failure since the last completed install, which is kept across retries,
+	// so that the original failure is not masked by failures of retries
+	FirstError string `json:"firstError,omitempty"`
+	// Failures counts the failed attempts since the last completed install
+	Failures int `json:"failures,omitempty"`
+	// Started is the time the first attempt since the last completed install failed
+	Started metav1.Time `json:"started"`
+}
+
+// Leftovers returns all entries of the attempt, which need to be deleted before a retry applying the passed
+// entries with the passed policy. Entries recorded in the inventory of the last completed install are never
+// leftovers, as they are pruned by the next inventory sync otherwise.
+func (a *InstallAttempt) Leftovers(policy types.PartialInstallPolicy, inventory, retried []InventoryEntry,
+) []InventoryEntry {
+	leftovers := StaleInventoryEntries(a.Entries, inventory)
+	if policy.RollsBack() {
+		return leftovers
+	}
+	return StaleInventoryEntries(leftovers, retried)
+}
+
+// InstallAttempts records the failed InstallAttempt of a single install of a custom resource in the target cluster.
+// It is stored as a ConfigMap in InventoryNamespace next to the Inventory of the install, which only records
+// resources of completed installs. The attempt is only written on failure and removed once the install completes.
+type InstallAttempts struct {
+	clnt client.Client
+	key  client.ObjectKey
+}
+
+// NewInstallAttempts returns the InstallAttempts of the given release, owned by the passed base resource.
+func NewInstallAttempts(clnt client.Client, owner client.Object, releaseName string) *InstallAttempts {
+	name := util.NormalizeSubdomain(strings.Join(
+		[]string{installAttemptPrefix, owner.GetNamespace(), owner.GetName(), releaseName}, "."),
+	)
+	return &InstallAttempts{clnt: clnt, key: client.ObjectKey{Namespace: InventoryNamespace, Name: name}}
+}
+
+// Load returns the recorded InstallAttempt or nil if no attempt failed since the last completed install.
+func (a *InstallAttempts) Load(ctx context.Context) (*InstallAttempt, error) {
+	configMap := &v1.ConfigMap{}
+	if err := a.clnt.Get(ctx, a.key, configMap); apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("loading install attempt %s: %w", a.key, err)
+	}
+	attempt := &InstallAttempt{}
+	if err := json.Unmarshal([]byte(configMap.Data[installAttemptDataKey]), attempt); err != nil {
+		return nil, fmt.Errorf("decoding install attempt %s: %w", a.key, err)
+	}
+	return attempt, nil
+}
+
+// Fail records the failure of an attempt applying the passed entries. The failures of the previous attempt are
+// continued and its entries, which are not part of the failed attempt, are kept, as they are still present.
+func (a *InstallAttempts) Fail(ctx context.Context, previous *InstallAttempt, entries []InventoryEntry, err error,
+) error {
+	attempt := &InstallAttempt{Entries: entries, Error: err.Error(), Started: metav1.Now()}
+	if previous != nil {
+		attempt.Entries = append(attempt.Entries, StaleInventoryEntries(previous.Entries, entries)...)
+		attempt.FirstError = previous.FirstError
+		attempt.Failures = previous.Failures
+		attempt.Started = previous.Started
+	}
+	if attempt.FirstError == "" {
+		attempt.FirstError = attempt.Error
+	}
+	attempt.Failures++
+	return a.store(ctx, attempt)
+}
+
+// Purge deletes all resources of the recorded attempt and the attempt itself.
+func (a *InstallAttempts) Purge(ctx context.Context) error {
+	attempt, err := a.Load(ctx)
+	if err != nil || attempt == nil {
+		return err
+	}
+	if err := deleteEntries(ctx, a.clnt, attempt.Entries); err != nil {
+		return fmt.Errorf("deleting resources of install attempt %s: %w", a.key, err)
+	}
+	return a.Complete(ctx)
+}
+
+// Complete removes the recorded attempt once the install completed.
+func (a *InstallAttempts) Complete(ctx context.Context) error {
+	configMap := &v1.ConfigMap{}
+	configMap.SetName(a.key.Name)
+	configMap.SetNamespace(a.key.Namespace)
+	if err := a.clnt.Delete(ctx, configMap); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("removing install attempt %s: %w", a.key, err)
+	}
+	return nil
+}
+
+func (a *InstallAttempts) store(ctx context.Context, attempt *InstallAttempt) error {
+	data, err := json.Marshal(attempt)
+	if err != nil {
+		return err
+	}
+	configMap := &v1.ConfigMap{}
+	configMap.SetName(a.key.Name)
+	configMap.SetNamespace(a.key.Namespace)
+	if _, err := controllerutil.CreateOrUpdate(ctx, a.clnt, configMap, func() error {
+		configMap.SetLabels(map[string]string{labels.ManagedBy: labels.OperatorName})
+		configMap.Data = map[string]string{installAttemptDataKey: string(data)}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("storing install attempt %s: %w", a.key, err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package manifest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+func inventoryEntries(t *testing.T, names ...string) []manifest.InventoryEntry {
+	t.Helper()
+	objects := make([]*unstructured.Unstructured, 0, len(names))
+	for _, name := range names {
+		objects = append(objects, configMapObject(name))
+	}
+	entries, err := manifest.InventoryEntriesFromObjects(objects)
+	require.NoError(t, err)
+	return entries
+}
+
+func entryNames(entries []manifest.InventoryEntry) []string {
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	return names
+}
+
+func Test_InstallAttemptLeftovers(t *testing.T) {
+	t.Parallel()
+	attempt := &manifest.InstallAttempt{Entries: inventoryEntries(t, "installed", "partial", "renamed")}
+	inventory := inventoryEntries(t, "installed")
+	retried := inventoryEntries(t, "installed", "partial", "new-name")
+
+	assert.Equal(t, []string{"renamed"},
+		entryNames(attempt.Leftovers(types.PartialInstallPolicyComplete, inventory, retried)))
+	assert.Equal(t, []string{"partial", "renamed"},
+		entryNames(attempt.Leftovers(types.PartialInstallPolicyRollback, inventory, retried)))
+}
+
+func Test_InstallAttempts(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	partial := &v1.ConfigMap{}
+	partial.SetName("partial")
+	partial.SetNamespace("default")
+	clnt := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(partial).Build()
+	attempts := manifest.NewInstallAttempts(clnt, configMapObject("owner"), "release")
+
+	attempt, err := attempts.Load(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, attempt)
+
+	require.NoError(t, attempts.Fail(ctx, nil, inventoryEntries(t, "partial"), errors.New("first")))
+	attempt, err = attempts.Load(ctx)
+	require.NoError(t, err)
+	require.NoError(t, attempts.Fail(ctx, attempt, inventoryEntries(t, "other"), errors.New("second")))
+	attempt, err = attempts.Load(ctx)
+	require.NoError(t, err)
+	// the first failure is kept and entries of previous attempts are still recorded
+	assert.Equal(t, "second", attempt.Error)
+	assert.Equal(t, "first", attempt.FirstError)
+	assert.Equal(t, 2, attempt.Failures)
+	assert.ElementsMatch(t, []string{"other", "partial"}, entryNames(attempt.Entries))
+
+	require.NoError(t, attempts.Purge(ctx))
+	err = clnt.Get(ctx, client.ObjectKeyFromObject(partial), &v1.ConfigMap{})
+	assert.True(t, apierrors.IsNotFound(err))
+	attempt, err = attempts.Load(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, attempt)
+}
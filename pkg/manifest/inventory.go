@@ -0,0 +1,246 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+const (
+	// InventoryNamespace is the namespace in the target cluster in which inventories are stored.
+	InventoryNamespace = metav1.NamespaceDefault
+	inventoryPrefix    = "inventory"
+	inventoryDataKey   = "inventory"
+)
+
+var ErrInventoryNotFound = errors.New("inventory not found")
+
+// InventoryEntry identifies a single applied resource together with the hash of its applied state.
+type InventoryEntry struct {
+	Group     string `json:"group"`
+	Version   string `json:"version"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Hash      uint32 `json:"hash"`
+}
+
+// ID returns a unique identifier for the resource referenced by the entry, independent of its hash.
+func (e InventoryEntry) ID() string {
+	return strings.Join([]string{e.Namespace, e.Name, e.Group, e.Version, e.Kind}, "/")
+}
+
+func (e InventoryEntry) toUnstructured() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: e.Group, Version: e.Version, Kind: e.Kind})
+	obj.SetNamespace(e.Namespace)
+	obj.SetName(e.Name)
+	return obj
+}
+
+// InventoryEntriesFromObjects creates an InventoryEntry for every passed object.
+func InventoryEntriesFromObjects(objects []*unstructured.Unstructured) ([]InventoryEntry, error) {
+	entries := make([]InventoryEntry, 0, len(objects))
+	for _, obj := range objects {
+		hash, err := util.CalculateHash(obj.Object)
+		if err != nil {
+			return nil, err
+		}
+		gvk := obj.GroupVersionKind()
+		entries = append(entries, InventoryEntry{
+			Group:     gvk.Group,
+			Version:   gvk.Version,
+			Kind:      gvk.Kind,
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+			Hash:      hash,
+		})
+	}
+	return entries, nil
+}
+
+// defaultNamespaces sets the namespace of all namespaced objects without a namespace to the passed namespace,
+// so that the recorded entries reflect the resources as they were applied to the target cluster.
+func defaultNamespaces(mapper meta.RESTMapper, objects []*unstructured.Unstructured, namespace string) {
+	for _, obj := range objects {
+		if obj.GetNamespace() != "" {
+			continue
+		}
+		gvk := obj.GroupVersionKind()
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err == nil && mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			obj.SetNamespace(namespace)
+		}
+	}
+}
+
+// StaleInventoryEntries returns all entries of previous that are no longer present in current.
+func StaleInventoryEntries(previous, current []InventoryEntry) []InventoryEntry {
+	present := make(map[string]struct{}, len(current))
+	for _, entry := range current {
+		present[entry.ID()] = struct{}{}
+	}
+	var stale []InventoryEntry
+	for _, entry := range previous {
+		if _, found := present[entry.ID()]; !found {
+			stale = append(stale, entry)
+		}
+	}
+	return stale
+}
+
+// Inventory records all resources applied for a single install of a custom resource in the target cluster.
+// It is stored as a ConfigMap in InventoryNamespace, which enables accurate uninstallation and pruning
+// of resources that are no longer part of a rendered manifest without having to re-render previous versions.
+type Inventory struct {
+	clnt client.Client
+	key  client.ObjectKey
+}
+
+// NewInventory returns the Inventory of the given release, owned by the passed base resource.
+func NewInventory(clnt client.Client, owner client.Object, releaseName string) *Inventory {
+	// names derived from long names of the owner or release are truncated to valid names
+	name := util.NormalizeSubdomain(strings.Join(
+		[]string{inventoryPrefix, owner.GetNamespace(), owner.GetName(), releaseName}, "."),
+	)
+	return &Inventory{clnt: clnt, key: client.ObjectKey{Namespace: InventoryNamespace, Name: name}}
+}
+
+// Load returns the recorded entries of the Inventory. If no Inventory exists, ErrInventoryNotFound is returned.
+func (i *Inventory) Load(ctx context.Context) ([]InventoryEntry, error) {
+	configMap := &v1.ConfigMap{}
+	if err := i.clnt.Get(ctx, i.key, configMap); apierrors.IsNotFound(err) {
+		return nil, ErrInventoryNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("loading inventory %s: %w", i.key, err)
+	}
+	var entries []InventoryEntry
+	if err := json.Unmarshal([]byte(configMap.Data[inventoryDataKey]), &entries); err != nil {
+		return nil, fmt.Errorf("decoding inventory %s: %w", i.key, err)
+	}
+	return entries, nil
+}
+
+// Store persists the passed entries, replacing any previously recorded entries.
+func (i *Inventory) Store(ctx context.Context, entries []InventoryEntry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	configMap := &v1.ConfigMap{}
+	configMap.SetName(i.key.Name)
+	configMap.SetNamespace(i.key.Namespace)
+	if _, err := controllerutil.CreateOrUpdate(ctx, i.clnt, configMap, func() error {
+		configMap.SetLabels(map[string]string{labels.ManagedBy: labels.OperatorName})
+		configMap.Data = map[string]string{inventoryDataKey: string(data)}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("storing inventory %s: %w", i.key, err)
+	}
+	return nil
+}
+
+// Restore stores the passed entries, unless the Inventory already exists.
+// It is used to recover an Inventory from an archive, in which case a still existing Inventory is more recent.
+// It indicates if the entries were stored.
+func (i *Inventory) Restore(ctx context.Context, entries []InventoryEntry) (bool, error) {
+	if _, err := i.Load(ctx); err == nil {
+		return false, nil
+	} else if !errors.Is(err, ErrInventoryNotFound) {
+		return false, err
+	}
+	return true, i.Store(ctx, entries)
+}
+
+// Sync prunes all resources recorded previously but missing in entries and records entries afterwards.
+func (i *Inventory) Sync(ctx context.Context, entries []InventoryEntry) error {
+	return i.SyncOrphaning(ctx, entries, nil)
+}
+
+// SyncOrphaning is Sync, but resources recorded previously and matched by orphaned are not pruned,
+// they are only dropped from the Inventory and kept in the target cluster. A nil orphaned orphans no resources.
+func (i *Inventory) SyncOrphaning(ctx context.Context, entries []InventoryEntry,
+	orphaned func(InventoryEntry) bool,
+) error {
+	previous, err := i.Load(ctx)
+	if err != nil && !errors.Is(err, ErrInventoryNotFound) {
+		return err
+	}
+	stale := StaleInventoryEntries(previous, entries)
+	if orphaned != nil {
+		pruned := stale[:0]
+		for _, entry := range stale {
+			if !orphaned(entry) {
+				pruned = append(pruned, entry)
+			}
+		}
+		stale = pruned
+	}
+	if err := deleteEntries(ctx, i.clnt, stale); err != nil {
+		return fmt.Errorf("pruning resources of inventory %s: %w", i.key, err)
+	}
+	return i.Store(ctx, entries)
+}
+
+// Purge deletes all recorded resources and the Inventory itself.
+func (i *Inventory) Purge(ctx context.Context) error {
+	entries, err := i.Load(ctx)
+	if errors.Is(err, ErrInventoryNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if err := deleteEntries(ctx, i.clnt, entries); err != nil {
+		return fmt.Errorf("deleting resources of inventory %s: %w", i.key, err)
+	}
+	configMap := &v1.ConfigMap{}
+	configMap.SetName(i.key.Name)
+	configMap.SetNamespace(i.key.Namespace)
+	return client.IgnoreNotFound(i.clnt.Delete(ctx, configMap))
+}
+
+// Drift returns all recorded entries whose resources are no longer present in the target cluster.
+func (i *Inventory) Drift(ctx context.Context) ([]InventoryEntry, error) {
+	entries, err := i.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var missing []InventoryEntry
+	for _, entry := range entries {
+		obj := entry.toUnstructured()
+		if err := i.clnt.Get(ctx, client.ObjectKeyFromObject(obj), obj); apierrors.IsNotFound(err) {
+			missing = append(missing, entry)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return missing, nil
+}
+
+func deleteEntries(ctx context.Context, clnt client.Client, entries []InventoryEntry) error {
+	var errs []error
+	for _, entry := range entries {
+		if err := clnt.Delete(ctx, entry.toUnstructured()); !UninstallSuccess(err) {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return types.NewMultiError(errs)
+	}
+	return nil
+}
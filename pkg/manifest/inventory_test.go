@@ -0,0 +1,128 @@
+package manifest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/module-manager/pkg/manifest"
+)
+
+func configMapObject(name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	return obj
+}
+
+func Test_StaleInventoryEntries(t *testing.T) {
+	t.Parallel()
+	previous, err := manifest.InventoryEntriesFromObjects(
+		[]*unstructured.Unstructured{configMapObject("kept"), configMapObject("removed")},
+	)
+	require.NoError(t, err)
+	current, err := manifest.InventoryEntriesFromObjects(
+		[]*unstructured.Unstructured{configMapObject("kept"), configMapObject("added")},
+	)
+	require.NoError(t, err)
+
+	stale := manifest.StaleInventoryEntries(previous, current)
+	require.Len(t, stale, 1)
+	assert.Equal(t, "removed", stale[0].Name)
+	assert.Empty(t, manifest.StaleInventoryEntries(nil, current))
+	assert.Len(t, manifest.StaleInventoryEntries(previous, nil), 2)
+}
+
+func Test_InventorySyncAndPurge(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	owner := configMapObject("owner")
+	kept, removed := &v1.ConfigMap{}, &v1.ConfigMap{}
+	kept.SetName("kept")
+	kept.SetNamespace("default")
+	removed.SetName("removed")
+	removed.SetNamespace("default")
+	clnt := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(kept, removed).Build()
+	inventory := manifest.NewInventory(clnt, owner, "release")
+
+	_, err := inventory.Load(ctx)
+	require.ErrorIs(t, err, manifest.ErrInventoryNotFound)
+
+	previous, err := manifest.InventoryEntriesFromObjects(
+		[]*unstructured.Unstructured{configMapObject("kept"), configMapObject("removed")},
+	)
+	require.NoError(t, err)
+	require.NoError(t, inventory.Store(ctx, previous))
+
+	current, err := manifest.InventoryEntriesFromObjects([]*unstructured.Unstructured{configMapObject("kept")})
+	require.NoError(t, err)
+	require.NoError(t, inventory.Sync(ctx, current))
+
+	recorded, err := inventory.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, current, recorded)
+	assert.True(t, apierrors.IsNotFound(clnt.Get(ctx, client.ObjectKeyFromObject(removed), &v1.ConfigMap{})))
+	assert.NoError(t, clnt.Get(ctx, client.ObjectKeyFromObject(kept), &v1.ConfigMap{}))
+
+	require.NoError(t, inventory.Purge(ctx))
+	assert.True(t, apierrors.IsNotFound(clnt.Get(ctx, client.ObjectKeyFromObject(kept), &v1.ConfigMap{})))
+	_, err = inventory.Load(ctx)
+	assert.ErrorIs(t, err, manifest.ErrInventoryNotFound)
+}
+
+func Test_InventorySyncOrphaning(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	skipped := &v1.ConfigMap{}
+	skipped.SetName("skipped")
+	skipped.SetNamespace("default")
+	clnt := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(skipped).Build()
+	inventory := manifest.NewInventory(clnt, configMapObject("owner"), "release")
+
+	previous, err := manifest.InventoryEntriesFromObjects([]*unstructured.Unstructured{configMapObject("skipped")})
+	require.NoError(t, err)
+	require.NoError(t, inventory.Store(ctx, previous))
+
+	require.NoError(t, inventory.SyncOrphaning(ctx, nil, func(entry manifest.InventoryEntry) bool {
+		return entry.Name == "skipped"
+	}))
+	recorded, err := inventory.Load(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, recorded, "orphaned resources are dropped from the inventory")
+	assert.NoError(t, clnt.Get(ctx, client.ObjectKeyFromObject(skipped), &v1.ConfigMap{}),
+		"orphaned resources are kept")
+}
+
+func Test_InventoryRestore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	clnt := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	inventory := manifest.NewInventory(clnt, configMapObject("owner"), "release")
+
+	archived, err := manifest.InventoryEntriesFromObjects([]*unstructured.Unstructured{configMapObject("archived")})
+	require.NoError(t, err)
+	restored, err := inventory.Restore(ctx, archived)
+	require.NoError(t, err)
+	assert.True(t, restored)
+
+	current, err := manifest.InventoryEntriesFromObjects([]*unstructured.Unstructured{configMapObject("current")})
+	require.NoError(t, err)
+	require.NoError(t, inventory.Store(ctx, current))
+	restored, err = inventory.Restore(ctx, archived)
+	require.NoError(t, err)
+	assert.False(t, restored, "an existing inventory is never overwritten by an archived one")
+
+	recorded, err := inventory.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, current, recorded)
+}
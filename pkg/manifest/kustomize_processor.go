@@ -2,8 +2,12 @@ package manifest
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/version"
 	"sigs.k8s.io/kustomize/api/krusty"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
 
@@ -57,7 +61,7 @@ func (k *kustomize) GetRawManifest(deployInfo *types.InstallInfo) *types.ParsedF
 	resMap, err := kustomizer.Run(fileSystem, path)
 	if err != nil {
 		k.logger.Error(err, "running kustomize to create final manifest")
-		return types.NewParsedFile("", fmt.Errorf("error running kustomize: %w", err))
+		return types.NewParsedFile("", types.ErrRenderFailure.Wrap(fmt.Errorf("error running kustomize: %w", err)))
 	}
 
 	var manifestStringified string
@@ -86,6 +90,48 @@ func (k *kustomize) Install(manifest string, deployInfo *types.InstallInfo,
 	return k.applier.Apply(deployInfo, objects, "")
 }
 
+// Upgrade transforms and applies kustomize based manifest using dynamic client as an upgrade of the previous manifest.
+// Resources of the previous manifest, which are no longer part of the manifest, are deleted.
+func (k *kustomize) Upgrade(previous, manifest string, deployInfo *types.InstallInfo,
+	transforms []types.ObjectTransform, postRuns []types.PostRun,
+) (bool, error) {
+	ready, err := k.Install(manifest, deployInfo, transforms, postRuns)
+	if err != nil || previous == "" {
+		return ready, err
+	}
+
+	// transform
+	objects, err := util.Transform(deployInfo.Ctx, manifest, deployInfo.BaseResource, transforms)
+	if err != nil {
+		return false, err
+	}
+	previousObjects, err := util.Transform(deployInfo.Ctx, previous, deployInfo.BaseResource, transforms)
+	if err != nil {
+		return false, err
+	}
+
+	current := make(map[string]struct{}, len(objects.Items))
+	for _, obj := range objects.Items {
+		current[objectIdentifier(obj)] = struct{}{}
+	}
+	removed := &types.ManifestResources{}
+	for _, obj := range previousObjects.Items {
+		if _, found := current[objectIdentifier(obj)]; !found {
+			removed.Items = append(removed.Items, obj)
+		}
+	}
+	if len(removed.Items) == 0 {
+		return ready, nil
+	}
+
+	// TODO fill namespace from user options
+	deleted, err := k.applier.Delete(deployInfo, removed, "")
+	if err != nil {
+		return false, err
+	}
+	return ready && deleted, nil
+}
+
 // Uninstall transforms and deletes kustomize based manifest using dynamic client.
 func (k *kustomize) Uninstall(manifest string, deployInfo *types.InstallInfo,
 	transforms []types.ObjectTransform, _ []types.PostRun,
@@ -95,6 +141,10 @@ func (k *kustomize) Uninstall(manifest string, deployInfo *types.InstallInfo,
 	if err != nil {
 		return false, err
 	}
+	// CRDs are kept unless their removal is requested, as it deletes all custom resources of them
+	if !deployInfo.CRDPolicy.DeletesCRDs() {
+		objects = withoutCRDObjects(objects)
+	}
 	// TODO fill namespace from user options
 	deletionSuccess, err := k.applier.Delete(deployInfo, objects, "")
 	if err != nil {
@@ -127,3 +177,26 @@ func (k *kustomize) GetClusterInfo() (types.ClusterInfo, error) {
 		Config: restConfig,
 	}, nil
 }
+
+func (k *kustomize) InvalidateOnServerVersionChange(interval time.Duration) (bool, error) {
+	return k.clients.InvalidateOnServerVersionChange(interval)
+}
+
+func (k *kustomize) GetServerVersion() (*version.Info, error) {
+	return k.clients.ServerVersion()
+}
+
+func objectIdentifier(obj *unstructured.Unstructured) string {
+	return strings.Join([]string{obj.GroupVersionKind().GroupKind().String(), obj.GetNamespace(), obj.GetName()}, "/")
+}
+
+// withoutCRDObjects returns the objects without CustomResourceDefinitions.
+func withoutCRDObjects(objects *types.ManifestResources) *types.ManifestResources {
+	filtered := &types.ManifestResources{Blobs: objects.Blobs}
+	for _, obj := range objects.Items {
+		if obj.GroupVersionKind().GroupKind() != crdGroupKind {
+			filtered.Items = append(filtered.Items, obj)
+		}
+	}
+	return filtered
+}
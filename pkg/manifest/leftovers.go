@@ -0,0 +1,59 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// ClusterScopedLeftoverKinds are the cluster-scoped kinds frequently left behind by uninstalls,
+// e.g. if charts template their names differently between releases.
+//
+//nolint:gochecknoglobals
+var ClusterScopedLeftoverKinds = []schema.GroupVersionKind{
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"},
+	{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfiguration"},
+	{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "ValidatingWebhookConfiguration"},
+	{Group: "apiregistration.k8s.io", Version: "v1", Kind: "APIService"},
+}
+
+// CleanupClusterScopedLeftovers deletes all resources of ClusterScopedLeftoverKinds, which are labeled as owned
+// by the base resource by OwnerLabelTransform, and returns the deleted ones as "Kind/name".
+// Kinds not served by the cluster are skipped.
+func CleanupClusterScopedLeftovers(ctx context.Context, clnt client.Client, base client.Object) ([]string, error) {
+	selector := k8slabels.SelectorFromSet(k8slabels.Set{
+		labels.ManagedBy:    labels.OperatorName,
+		labels.OwnedByLabel: ownedBy(base),
+	})
+	var deleted []string
+	var errs []error
+	for _, gvk := range ClusterScopedLeftoverKinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := clnt.List(ctx, list, client.MatchingLabelsSelector{Selector: selector}); meta.IsNoMatchError(err) {
+			continue
+		} else if err != nil {
+			errs = append(errs, fmt.Errorf("listing leftovers of kind %s: %w", gvk.Kind, err))
+			continue
+		}
+		for i := range list.Items {
+			if err := clnt.Delete(ctx, &list.Items[i]); !UninstallSuccess(err) {
+				errs = append(errs, err)
+				continue
+			}
+			deleted = append(deleted, gvk.Kind+"/"+list.Items[i].GetName())
+		}
+	}
+	if len(errs) > 0 {
+		return deleted, types.NewMultiError(errs)
+	}
+	return deleted, nil
+}
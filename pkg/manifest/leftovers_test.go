@@ -0,0 +1,57 @@
+package manifest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/manifest"
+)
+
+func Test_CleanupClusterScopedLeftovers(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	owner := configMapObject("owner")
+	ownerLabels := map[string]string{
+		labels.ManagedBy:    labels.OperatorName,
+		labels.OwnedByLabel: "default__owner",
+	}
+	leftoverBinding, foreignBinding := &rbacv1.ClusterRoleBinding{}, &rbacv1.ClusterRoleBinding{}
+	leftoverBinding.SetName("release-name-mistemplated")
+	leftoverBinding.SetLabels(ownerLabels)
+	foreignBinding.SetName("foreign")
+	foreignBinding.SetLabels(map[string]string{
+		labels.ManagedBy:    labels.OperatorName,
+		labels.OwnedByLabel: "default__other",
+	})
+	leftoverWebhook := &admissionv1.ValidatingWebhookConfiguration{}
+	leftoverWebhook.SetName("release-webhook")
+	leftoverWebhook.SetLabels(ownerLabels)
+	clnt := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithObjects(leftoverBinding, foreignBinding, leftoverWebhook).Build()
+
+	deleted, err := manifest.CleanupClusterScopedLeftovers(ctx, clnt, owner)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"ClusterRoleBinding/release-name-mistemplated", "ValidatingWebhookConfiguration/release-webhook",
+	}, deleted)
+	assert.True(t, apierrors.IsNotFound(
+		clnt.Get(ctx, client.ObjectKeyFromObject(leftoverBinding), &rbacv1.ClusterRoleBinding{})))
+	assert.True(t, apierrors.IsNotFound(
+		clnt.Get(ctx, client.ObjectKeyFromObject(leftoverWebhook), &admissionv1.ValidatingWebhookConfiguration{})))
+	assert.NoError(t, clnt.Get(ctx, client.ObjectKeyFromObject(foreignBinding), &rbacv1.ClusterRoleBinding{}),
+		"resources of other owners are kept")
+
+	deleted, err = manifest.CleanupClusterScopedLeftovers(ctx, clnt, owner)
+	require.NoError(t, err)
+	assert.Empty(t, deleted)
+}
@@ -0,0 +1,66 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// PendingMigrations returns the migrations whose version is not listed in the applied versions, in their order.
+func PendingMigrations(migrations []types.Migration, applied []string) []types.Migration {
+	appliedVersions := sets.NewString(applied...)
+	pending := make([]types.Migration, 0, len(migrations))
+	for _, migration := range migrations {
+		if !appliedVersions.Has(migration.Version) {
+			pending = append(pending, migration)
+		}
+	}
+	return pending
+}
+
+// ApplyMigrations runs the passed migrations in order on the objects currently applied in the target cluster
+// for the passed desired objects and updates the changed ones. Objects not present in the cluster are skipped,
+// e.g. for installs which were never applied. It returns the versions of all migrations applied successfully.
+func ApplyMigrations(ctx context.Context, clnt client.Client, objects []*unstructured.Unstructured,
+	migrations []types.Migration,
+) ([]string, error) {
+	applied := make([]string, 0, len(migrations))
+	for _, migration := range migrations {
+		for _, obj := range objects {
+			if err := ctx.Err(); err != nil {
+				return applied, err
+			}
+			if err := migrateObject(ctx, clnt, obj, migration); err != nil {
+				return applied, fmt.Errorf("migration %s of %s %s failed: %w",
+					migration.Version, obj.GetKind(), client.ObjectKeyFromObject(obj), err)
+			}
+		}
+		applied = append(applied, migration.Version)
+	}
+	return applied, nil
+}
+
+func migrateObject(ctx context.Context, clnt client.Client, desired *unstructured.Unstructured,
+	migration types.Migration,
+) error {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(desired.GroupVersionKind())
+	err := clnt.Get(ctx, client.ObjectKeyFromObject(desired), current)
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	changed, err := migration.Migrate(ctx, current)
+	if err != nil || !changed {
+		return err
+	}
+	return clnt.Update(ctx, current)
+}
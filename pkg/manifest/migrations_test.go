@@ -0,0 +1,66 @@
+package manifest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// renameLabelMigration renames the label oldKey to newKey, keeping its value.
+func renameLabelMigration(version, oldKey, newKey string) types.Migration {
+	return types.Migration{
+		Version: version,
+		Migrate: func(_ context.Context, obj *unstructured.Unstructured) (bool, error) {
+			objLabels := obj.GetLabels()
+			value, found := objLabels[oldKey]
+			if !found {
+				return false, nil
+			}
+			delete(objLabels, oldKey)
+			objLabels[newKey] = value
+			obj.SetLabels(objLabels)
+			return true, nil
+		},
+	}
+}
+
+func Test_PendingMigrations(t *testing.T) {
+	t.Parallel()
+	migrations := []types.Migration{{Version: "v1"}, {Version: "v2"}, {Version: "v3"}}
+	pending := manifest.PendingMigrations(migrations, []string{"v2"})
+	require.Len(t, pending, 2)
+	assert.Equal(t, "v1", pending[0].Version)
+	assert.Equal(t, "v3", pending[1].Version)
+	assert.Empty(t, manifest.PendingMigrations(migrations, []string{"v1", "v2", "v3"}))
+}
+
+func Test_ApplyMigrations(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	applied := configMapObject("applied")
+	applied.SetLabels(map[string]string{"old/managed-by": "module-manager"})
+	clnt := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(applied).Build()
+
+	migrations := []types.Migration{
+		renameLabelMigration("v1", "old/managed-by", "intermediate/managed-by"),
+		renameLabelMigration("v2", "intermediate/managed-by", "new/managed-by"),
+	}
+	// objects not applied yet are skipped
+	desired := []*unstructured.Unstructured{configMapObject("applied"), configMapObject("not-applied")}
+	versions, err := manifest.ApplyMigrations(ctx, clnt, desired, migrations)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1", "v2"}, versions)
+
+	migrated := configMapObject("applied")
+	require.NoError(t, clnt.Get(ctx, client.ObjectKeyFromObject(migrated), migrated))
+	assert.Equal(t, map[string]string{"new/managed-by": "module-manager"}, migrated.GetLabels())
+}
@@ -0,0 +1,80 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+// NamespaceTransform returns an ObjectTransform moving all rendered namespaced resources to the target namespace,
+// or to the namespace of the mappings matching them. Resources without a namespace are defaulted to the
+// target namespace before, if the mapper identifies them as namespaced. It fails with types.ErrNamespaceConflict
+// if a resource matches mappings of different namespaces, or if resources of the same kind and name are moved
+// to the same namespace.
+func NamespaceTransform(mapper meta.RESTMapper, targetNamespace string, override bool,
+	mappings []types.NamespaceMapping,
+) types.ObjectTransform {
+	return func(_ context.Context, _ types.BaseCustomObject, resources *types.ManifestResources) error {
+		defaultNamespaces(mapper, resources.Items, targetNamespace)
+
+		var conflicts []string
+		moved := make(map[string]string, len(resources.Items))
+		for _, obj := range resources.Items {
+			if obj.GetNamespace() == "" {
+				continue
+			}
+			origin := objectName(obj)
+			namespace, err := mappedNamespace(obj, mappings)
+			if err != nil {
+				conflicts = append(conflicts, err.Error())
+				continue
+			}
+			if namespace == "" && override {
+				namespace = targetNamespace
+			}
+			if namespace != "" {
+				obj.SetNamespace(namespace)
+			}
+
+			key := namespacedObjectKey(obj)
+			if previous, found := moved[key]; found {
+				conflicts = append(conflicts, fmt.Sprintf("%s %s would be moved from both %s and %s",
+					obj.GetKind(), objectName(obj), previous, origin))
+				continue
+			}
+			moved[key] = origin
+		}
+		if len(conflicts) > 0 {
+			return types.ErrNamespaceConflict.Wrap(errors.New(strings.Join(conflicts, "; ")))
+		}
+		return nil
+	}
+}
+
+// mappedNamespace returns the namespace of the mappings matching the object, or an empty string if none matches.
+func mappedNamespace(obj *unstructured.Unstructured, mappings []types.NamespaceMapping) (string, error) {
+	namespace := ""
+	for _, mapping := range mappings {
+		if !util.MatchesResourcePatterns(mapping.Resources, obj.GroupVersionKind(), obj.GetName()) {
+			continue
+		}
+		if namespace != "" && namespace != mapping.Namespace {
+			return "", fmt.Errorf("%s %s is mapped to namespaces %s and %s",
+				obj.GetKind(), objectName(obj), namespace, mapping.Namespace)
+		}
+		namespace = mapping.Namespace
+	}
+	return namespace, nil
+}
+
+// namespacedObjectKey identifies an object by its group, kind, namespace and name.
+func namespacedObjectKey(obj *unstructured.Unstructured) string {
+	return obj.GroupVersionKind().GroupKind().String() + " " + obj.GetNamespace() + "/" + obj.GetName()
+}
@@ -0,0 +1,60 @@
+package manifest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+func Test_NamespaceTransform(t *testing.T) {
+	t.Parallel()
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	namespace := &unstructured.Unstructured{}
+	namespace.SetAPIVersion("v1")
+	namespace.SetKind("Namespace")
+	namespace.SetName("redis-system")
+	defaulted, explicit, webhook := configMapObject("defaulted"), configMapObject("explicit"), configMapObject("webhook")
+	defaulted.SetNamespace("")
+	explicit.SetNamespace("other")
+	mappings := []types.NamespaceMapping{{
+		Namespace: "redis-system", Resources: []types.ResourcePattern{{Kind: "ConfigMap", Name: "web*"}},
+	}}
+	resources := &types.ManifestResources{Items: []*unstructured.Unstructured{namespace, defaulted, explicit, webhook}}
+
+	transform := manifest.NamespaceTransform(mapper, "redis", true, mappings)
+	require.NoError(t, transform(context.Background(), nil, resources))
+	assert.Empty(t, namespace.GetNamespace(), "cluster-scoped resources are not moved")
+	assert.Equal(t, "redis", defaulted.GetNamespace())
+	assert.Equal(t, "redis", explicit.GetNamespace(), "explicit namespaces are overridden")
+	assert.Equal(t, "redis-system", webhook.GetNamespace())
+
+	explicit.SetNamespace("other")
+	require.NoError(t, manifest.NamespaceTransform(mapper, "redis", false, nil)(context.Background(), nil,
+		&types.ManifestResources{Items: []*unstructured.Unstructured{explicit}}))
+	assert.Equal(t, "other", explicit.GetNamespace(), "explicit namespaces are kept without override")
+
+	first, second := configMapObject("config"), configMapObject("config")
+	second.SetNamespace("other")
+	err := transform(context.Background(), nil,
+		&types.ManifestResources{Items: []*unstructured.Unstructured{first, second}})
+	require.ErrorIs(t, err, types.ErrNamespaceConflict)
+	assert.Contains(t, err.Error(), "ConfigMap redis/config would be moved from both default/config and other/config")
+
+	ambiguous := append(mappings, types.NamespaceMapping{
+		Namespace: "webhooks", Resources: []types.ResourcePattern{{Name: "webhook"}},
+	})
+	err = manifest.NamespaceTransform(mapper, "redis", true, ambiguous)(context.Background(), nil,
+		&types.ManifestResources{Items: []*unstructured.Unstructured{configMapObject("webhook")}})
+	require.ErrorIs(t, err, types.ErrNamespaceConflict)
+	assert.Contains(t, err.Error(), "mapped to namespaces redis-system and webhooks")
+}
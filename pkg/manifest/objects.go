@@ -0,0 +1,303 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/kube"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+var (
+	ErrNoTargetClient          = errors.New("no client for the target cluster configured")
+	ErrObjectSetNameMissing    = errors.New("name of object set is required to track an inventory")
+	ErrReadyCheckWithoutConfig = errors.New("ready check requires a REST config for the target cluster")
+)
+
+// ApplyOptions configures how Operations.ApplyObjects and Operations.DeleteObjects process objects.
+type ApplyOptions struct {
+	// Name identifies the set of objects. It is used as the release name of the Inventory.
+	Name string
+	// Namespace is set on all namespaced objects without a namespace. Defaults to metav1.NamespaceDefault.
+	Namespace string
+	// FieldOwner is the field manager used for server-side apply. Defaults to labels.OperatorName.
+	FieldOwner client.FieldOwner
+	// TrackInventory records applied objects in an Inventory, pruning objects no longer passed on apply.
+	TrackInventory bool
+	// CheckReadyStates verifies that native objects are in their respective ready states.
+	CheckReadyStates bool
+	// ConflictPolicy determines how field ownership conflicts are resolved. Defaults to types.ConflictPolicyForce.
+	ConflictPolicy types.ConflictPolicy
+}
+
+func (o ApplyOptions) withDefaults() ApplyOptions {
+	if o.Namespace == "" {
+		o.Namespace = metav1.NamespaceDefault
+	}
+	if o.FieldOwner == "" {
+		o.FieldOwner = labels.OperatorName
+	}
+	return o
+}
+
+// NewObjectOperations returns Operations for ApplyObjects and DeleteObjects.
+// Contrary to NewOperations, no chart is resolved, as objects are passed directly.
+func NewObjectOperations(options OperationOptions) (*Operations, error) {
+	if options.InstallInfo == nil || options.InstallInfo.ClusterInfo == nil ||
+		options.InstallInfo.ClusterInfo.Client == nil {
+		return nil, ErrNoTargetClient
+	}
+	return &Operations{
+		logger:      options.Logger,
+		installInfo: options.InstallInfo,
+		client:      options.InstallInfo.ClusterInfo.Client,
+	}, nil
+}
+
+// ApplyObjects server-side applies the passed objects to the target cluster, labeled as owned by the base resource.
+// It returns true if all objects were applied and, if requested, are ready.
+func (o *Operations) ApplyObjects(ctx context.Context, objects []unstructured.Unstructured, options ApplyOptions,
+) (bool, error) {
+	options = options.withDefaults()
+	if options.TrackInventory && options.Name == "" {
+		return false, ErrObjectSetNameMissing
+	}
+
+	targets := o.prepareObjects(objects, options)
+	for _, obj := range targets {
+		applied, err := util.ApplyWithConflictPolicy(obj, options.ConflictPolicy,
+			func(objToApply *unstructured.Unstructured, force bool) error {
+				patchOptions := []client.PatchOption{options.FieldOwner}
+				if force {
+					patchOptions = append(patchOptions, client.ForceOwnership)
+				}
+				return o.client.Patch(ctx, objToApply, client.Apply, patchOptions...)
+			})
+		if err != nil {
+			return false, fmt.Errorf("applying %s %s: %w", obj.GetKind(), client.ObjectKeyFromObject(obj), err)
+		}
+		if !applied {
+			o.logger.Info("skipped applying object with fields owned by another manager",
+				"kind", obj.GetKind(), "object", client.ObjectKeyFromObject(obj).String())
+		}
+	}
+	o.logger.V(util.DebugLogLevel).Info("applied objects", "count", len(targets), "name", options.Name)
+
+	if options.TrackInventory {
+		entries, err := InventoryEntriesFromObjects(targets)
+		if err != nil {
+			return false, err
+		}
+		if err := NewInventory(o.client, o.installInfo.BaseResource, options.Name).Sync(ctx, entries); err != nil {
+			return false, err
+		}
+	}
+
+	if options.CheckReadyStates {
+		return o.objectsReady(ctx, targets)
+	}
+	return true, nil
+}
+
+// DeleteObjects deletes the passed objects from the target cluster. If the inventory is tracked,
+// all objects recorded in it are deleted as well. It returns true once all objects are removed.
+func (o *Operations) DeleteObjects(ctx context.Context, objects []unstructured.Unstructured, options ApplyOptions,
+) (bool, error) {
+	options = options.withDefaults()
+	if options.TrackInventory && options.Name == "" {
+		return false, ErrObjectSetNameMissing
+	}
+
+	targets := o.prepareObjects(objects, options)
+	var errs []error
+	for _, obj := range targets {
+		if err := o.client.Delete(ctx, obj); !UninstallSuccess(err) {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return false, types.NewMultiError(errs)
+	}
+
+	if options.TrackInventory {
+		if err := NewInventory(o.client, o.installInfo.BaseResource, options.Name).Purge(ctx); err != nil {
+			return false, err
+		}
+	}
+
+	for _, obj := range targets {
+		if err := o.client.Get(ctx, client.ObjectKeyFromObject(obj), obj); err == nil {
+			return false, nil
+		} else if !UninstallSuccess(err) {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// prepareObjects copies the passed objects, defaults their namespace and labels them as owned by the base resource.
+func (o *Operations) prepareObjects(objects []unstructured.Unstructured, options ApplyOptions,
+) []*unstructured.Unstructured {
+	targets := make([]*unstructured.Unstructured, 0, len(objects))
+	for i := range objects {
+		targets = append(targets, objects[i].DeepCopy())
+	}
+	defaultNamespaces(o.client.RESTMapper(), targets, options.Namespace)
+
+	owner := ""
+	if base := o.installInfo.BaseResource; base != nil {
+		owner = ownerOf(base)
+	}
+	for _, obj := range targets {
+		setOwnerLabels(obj, owner)
+		obj.SetResourceVersion("")
+		obj.SetManagedFields(nil)
+	}
+	return targets
+}
+
+// OwnerLabelTransform labels all resources as managed by the operator and owned by the base resource,
+// so that events of the applied resources can be mapped back to it with OwnerOf.
+func OwnerLabelTransform(_ context.Context, base types.BaseCustomObject, resources *types.ManifestResources) error {
+	owner := ownerOf(base)
+	for _, obj := range resources.Items {
+		setOwnerLabels(obj, owner)
+	}
+	return nil
+}
+
+// TrackingTransform returns an ObjectTransform labeling all resources like OwnerLabelTransform and with the module
+// and application they are part of according to the passed metadata. Additional labels and annotations
+// of the metadata are only set if the resources do not set them already.
+func TrackingTransform(metadata types.TrackingMetadata) types.ObjectTransform {
+	return func(_ context.Context, base types.BaseCustomObject, resources *types.ManifestResources) error {
+		moduleName := metadata.ModuleName
+		if moduleName == "" {
+			moduleName = base.GetLabels()[labels.ModuleName]
+		}
+		owner := ownerOf(base)
+		for _, obj := range resources.Items {
+			obj.SetLabels(withDefaults(obj.GetLabels(), metadata.Labels))
+			obj.SetAnnotations(withDefaults(obj.GetAnnotations(), metadata.Annotations))
+			setOwnerLabels(obj, owner)
+			objLabels := obj.GetLabels()
+			if moduleName != "" {
+				objLabels[labels.ModuleName] = util.NormalizeLabelValue(moduleName)
+			}
+			if metadata.PartOf != "" {
+				objLabels[labels.PartOf] = metadata.PartOf
+			}
+			obj.SetLabels(objLabels)
+		}
+		return nil
+	}
+}
+
+// withDefaults returns the values extended by all defaults, which are not set in the values.
+func withDefaults(values, defaults map[string]string) map[string]string {
+	if len(defaults) == 0 {
+		return values
+	}
+	if values == nil {
+		values = make(map[string]string, len(defaults))
+	}
+	for key, value := range defaults {
+		if _, found := values[key]; !found {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// OwnerOf returns the key of the base resource owning a resource labeled with OwnerLabelTransform.
+func OwnerOf(obj metav1.Object) (client.ObjectKey, bool) {
+	owner := obj.GetAnnotations()[labels.OwnedByAnnotation]
+	if owner == "" {
+		owner = obj.GetLabels()[labels.OwnedByLabel]
+	}
+	namespace, name, found := strings.Cut(owner, labels.OwnedBySeparator)
+	if !found || name == "" {
+		return client.ObjectKey{}, false
+	}
+	return client.ObjectKey{Namespace: namespace, Name: name}, true
+}
+
+// setOwnerLabels labels the object as managed by the operator and, unless empty, owned by the passed owner.
+// Owners too long for a label value are recorded in labels.OwnedByAnnotation in addition.
+func setOwnerLabels(obj *unstructured.Unstructured, owner string) {
+	objLabels := obj.GetLabels()
+	if objLabels == nil {
+		objLabels = make(map[string]string)
+	}
+	objLabels[labels.ManagedBy] = labels.OperatorName
+	if owner != "" {
+		objLabels[labels.OwnedByLabel] = util.NormalizeLabelValue(owner)
+		if objLabels[labels.OwnedByLabel] != owner {
+			annotations := obj.GetAnnotations()
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+			annotations[labels.OwnedByAnnotation] = owner
+			obj.SetAnnotations(annotations)
+		}
+	}
+	obj.SetLabels(objLabels)
+}
+
+func (o *Operations) objectsReady(ctx context.Context, objects []*unstructured.Unstructured) (bool, error) {
+	if o.installInfo.Config == nil {
+		return false, ErrReadyCheckWithoutConfig
+	}
+	clientSet, err := kubernetes.NewForConfig(o.installInfo.Config)
+	if err != nil {
+		return false, err
+	}
+	readyChecker := kube.NewReadyChecker(clientSet,
+		func(format string, args ...interface{}) {
+			o.logger.V(util.DebugLogLevel).Info(fmt.Sprintf(format, args...))
+		},
+		kube.PausedAsReady(true),
+		kube.CheckJobs(true))
+
+	resourceList := make(kube.ResourceList, 0, len(objects))
+	for _, obj := range objects {
+		gvk := obj.GroupVersionKind()
+		mapping, err := o.client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return false, err
+		}
+		resourceList = append(resourceList, &resource.Info{
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+			Object:    obj,
+			Mapping:   mapping,
+		})
+	}
+
+	if err := checkReady(ctx, resourceList, readyChecker); errors.Is(err, ErrResourceNotReady) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ownerOf returns the owner of resources owned by the passed base resource.
+func ownerOf(base client.Object) string {
+	return fmt.Sprintf(labels.OwnedByFormat, base.GetNamespace(), base.GetName())
+}
+
+// ownedBy returns the value of labels.OwnedByLabel for resources owned by the passed base resource.
+func ownedBy(base client.Object) string {
+	return util.NormalizeLabelValue(ownerOf(base))
+}
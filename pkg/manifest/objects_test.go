@@ -0,0 +1,261 @@
+package manifest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+func Test_DeleteObjects(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	adHoc, recorded := &v1.ConfigMap{}, &v1.ConfigMap{}
+	adHoc.SetName("ad-hoc")
+	adHoc.SetNamespace("default")
+	recorded.SetName("recorded")
+	recorded.SetNamespace("default")
+	clnt := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(adHoc, recorded).Build()
+	owner := configMapObject("owner")
+
+	_, err := manifest.NewObjectOperations(manifest.OperationOptions{InstallInfo: &types.InstallInfo{}})
+	require.ErrorIs(t, err, manifest.ErrNoTargetClient)
+
+	ops, err := manifest.NewObjectOperations(manifest.OperationOptions{InstallInfo: &types.InstallInfo{
+		ClusterInfo:  &types.ClusterInfo{Client: clnt},
+		ResourceInfo: &types.ResourceInfo{BaseResource: owner},
+	}})
+	require.NoError(t, err)
+
+	entries, err := manifest.InventoryEntriesFromObjects([]*unstructured.Unstructured{configMapObject("recorded")})
+	require.NoError(t, err)
+	require.NoError(t, manifest.NewInventory(clnt, owner, "ad-hoc-set").Store(ctx, entries))
+
+	_, err = ops.DeleteObjects(ctx, nil, manifest.ApplyOptions{TrackInventory: true})
+	require.ErrorIs(t, err, manifest.ErrObjectSetNameMissing)
+
+	deleted, err := ops.DeleteObjects(ctx, []unstructured.Unstructured{*configMapObject("ad-hoc")},
+		manifest.ApplyOptions{Name: "ad-hoc-set", TrackInventory: true})
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	assert.True(t, apierrors.IsNotFound(clnt.Get(ctx, client.ObjectKeyFromObject(adHoc), &v1.ConfigMap{})))
+	assert.True(t, apierrors.IsNotFound(clnt.Get(ctx, client.ObjectKeyFromObject(recorded), &v1.ConfigMap{})))
+}
+
+func Test_OwnerLabelTransform(t *testing.T) {
+	t.Parallel()
+	owner := configMapObject("owner")
+	owner.SetNamespace("kcp-system")
+	resources := &types.ManifestResources{
+		Items: []*unstructured.Unstructured{configMapObject("applied")},
+	}
+	require.NoError(t, manifest.OwnerLabelTransform(context.Background(), owner, resources))
+
+	key, found := manifest.OwnerOf(resources.Items[0])
+	require.True(t, found)
+	assert.Equal(t, client.ObjectKeyFromObject(owner), key)
+
+	_, found = manifest.OwnerOf(configMapObject("unowned"))
+	assert.False(t, found, "resources without owner label are not mapped")
+
+	// owners too long for label values are still mapped
+	owner.SetName(strings.Repeat("owner", 20))
+	resources.Items = []*unstructured.Unstructured{configMapObject("applied")}
+	require.NoError(t, manifest.OwnerLabelTransform(context.Background(), owner, resources))
+	assert.Empty(t, validation.IsValidLabelValue(resources.Items[0].GetLabels()[labels.OwnedByLabel]))
+	key, found = manifest.OwnerOf(resources.Items[0])
+	require.True(t, found)
+	assert.Equal(t, client.ObjectKeyFromObject(owner), key)
+}
+
+func Test_TrackingTransform(t *testing.T) {
+	t.Parallel()
+	owner := configMapObject("owner")
+	owner.SetLabels(map[string]string{labels.ModuleName: "redis"})
+	labeled := configMapObject("labeled")
+	labeled.SetLabels(map[string]string{"team": "storage", labels.ManagedBy: "helm"})
+	resources := &types.ManifestResources{Items: []*unstructured.Unstructured{configMapObject("plain"), labeled}}
+	transform := manifest.TrackingTransform(types.TrackingMetadata{
+		PartOf:      "Kyma",
+		Labels:      map[string]string{"team": "platform", "tier": "backend"},
+		Annotations: map[string]string{"contact": "platform@example.com"},
+	})
+	require.NoError(t, transform(context.Background(), owner, resources))
+
+	for _, obj := range resources.Items {
+		assert.Equal(t, labels.OperatorName, obj.GetLabels()[labels.ManagedBy], "tracking labels cannot be overridden")
+		assert.Equal(t, "redis", obj.GetLabels()[labels.ModuleName], "module defaults to the label of the owner")
+		assert.Equal(t, "Kyma", obj.GetLabels()[labels.PartOf])
+		assert.Equal(t, "backend", obj.GetLabels()["tier"])
+		assert.Equal(t, "platform@example.com", obj.GetAnnotations()["contact"])
+		key, found := manifest.OwnerOf(obj)
+		require.True(t, found)
+		assert.Equal(t, client.ObjectKeyFromObject(owner), key)
+	}
+	assert.Equal(t, "platform", resources.Items[0].GetLabels()["team"])
+	assert.Equal(t, "storage", resources.Items[1].GetLabels()["team"], "labels of resources are kept")
+}
+
+// applyClient emulates server-side apply, which the fake client does not support, by creating or updating objects.
+// It records the field owner and if ownership was forced for every applied object.
+type applyClient struct {
+	client.Client
+	fieldOwners map[string]string
+	forced      map[string]bool
+}
+
+func (c *applyClient) Patch(ctx context.Context, obj client.Object, patch client.Patch,
+	opts ...client.PatchOption,
+) error {
+	if patch != client.Apply {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+	patchOptions := &client.PatchOptions{}
+	patchOptions.ApplyOptions(opts)
+	c.fieldOwners[obj.GetName()] = patchOptions.FieldManager
+	c.forced[obj.GetName()] = patchOptions.Force != nil && *patchOptions.Force
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), current); apierrors.IsNotFound(err) {
+		return c.Create(ctx, obj)
+	} else if err != nil {
+		return err
+	}
+	obj.SetResourceVersion(current.GetResourceVersion())
+	return c.Update(ctx, obj)
+}
+
+// newApplyClient returns an applyClient, whose REST mapper knows ConfigMaps and Pods as namespaced.
+func newApplyClient() *applyClient {
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{v1.SchemeGroupVersion})
+	mapper.Add(v1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mapper.Add(v1.SchemeGroupVersion.WithKind("Pod"), meta.RESTScopeNamespace)
+	return &applyClient{
+		Client:      fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRESTMapper(mapper).Build(),
+		fieldOwners: map[string]string{},
+		forced:      map[string]bool{},
+	}
+}
+
+func Test_ApplyObjects(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	clnt := newApplyClient()
+	owner := configMapObject("owner")
+	ops, err := manifest.NewObjectOperations(manifest.OperationOptions{Logger: logr.Discard(),
+		InstallInfo: &types.InstallInfo{
+			ClusterInfo:  &types.ClusterInfo{Client: clnt},
+			ResourceInfo: &types.ResourceInfo{BaseResource: owner},
+		},
+	})
+	require.NoError(t, err)
+	unnamespaced := configMapObject("kept")
+	unnamespaced.SetNamespace("")
+
+	_, err = ops.ApplyObjects(ctx, nil, manifest.ApplyOptions{TrackInventory: true})
+	require.ErrorIs(t, err, manifest.ErrObjectSetNameMissing)
+
+	ready, err := ops.ApplyObjects(ctx, []unstructured.Unstructured{*unnamespaced, *configMapObject("pruned")},
+		manifest.ApplyOptions{Name: "object-set", TrackInventory: true})
+	require.NoError(t, err)
+	assert.True(t, ready)
+	assert.Empty(t, unnamespaced.GetNamespace(), "passed objects are not modified")
+	applied := &v1.ConfigMap{}
+	require.NoError(t, clnt.Get(ctx, client.ObjectKey{Namespace: "default", Name: "kept"}, applied),
+		"objects without namespace are applied to the default namespace")
+	assert.Equal(t, labels.OperatorName, applied.Labels[labels.ManagedBy])
+	key, found := manifest.OwnerOf(applied)
+	require.True(t, found, "applied objects are labeled as owned by the base resource")
+	assert.Equal(t, client.ObjectKeyFromObject(owner), key)
+	assert.Equal(t, labels.OperatorName, clnt.fieldOwners["kept"])
+	assert.True(t, clnt.forced["kept"], "field ownership is forced by default")
+
+	ready, err = ops.ApplyObjects(ctx, []unstructured.Unstructured{*configMapObject("kept")},
+		manifest.ApplyOptions{Name: "object-set", TrackInventory: true, FieldOwner: "custom-owner"})
+	require.NoError(t, err)
+	assert.True(t, ready)
+	assert.Equal(t, "custom-owner", clnt.fieldOwners["kept"])
+	assert.True(t, apierrors.IsNotFound(clnt.Get(ctx, client.ObjectKey{Namespace: "default", Name: "pruned"},
+		&v1.ConfigMap{})), "objects no longer passed are pruned")
+	recorded, err := manifest.NewInventory(clnt, owner, "object-set").Load(ctx)
+	require.NoError(t, err)
+	require.Len(t, recorded, 1)
+	assert.Equal(t, "kept", recorded[0].Name)
+}
+
+func Test_ApplyObjectsReadiness(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	// the API server of the target cluster reports the Pod named "running" as ready, all others as pending
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		pod := &v1.Pod{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: path.Base(request.URL.Path)},
+			Status:     v1.PodStatus{Phase: v1.PodPending}}
+		if pod.Name == "running" {
+			pod.Status = v1.PodStatus{Phase: v1.PodRunning, Conditions: []v1.PodCondition{
+				{Type: v1.PodReady, Status: v1.ConditionTrue},
+			}}
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(writer).Encode(pod)
+	}))
+	t.Cleanup(server.Close)
+	clnt := newApplyClient()
+	newOperations := func(config *rest.Config) *manifest.Operations {
+		ops, err := manifest.NewObjectOperations(manifest.OperationOptions{Logger: logr.Discard(),
+			InstallInfo: &types.InstallInfo{
+				ClusterInfo:  &types.ClusterInfo{Client: clnt, Config: config},
+				ResourceInfo: &types.ResourceInfo{BaseResource: configMapObject("owner")},
+			},
+		})
+		require.NoError(t, err)
+		return ops
+	}
+	pod := func(name string) unstructured.Unstructured {
+		obj := unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("Pod")
+		obj.SetName(name)
+		return obj
+	}
+
+	_, err := newOperations(nil).ApplyObjects(ctx, []unstructured.Unstructured{*configMapObject("config")},
+		manifest.ApplyOptions{CheckReadyStates: true})
+	require.ErrorIs(t, err, manifest.ErrReadyCheckWithoutConfig)
+
+	ops := newOperations(&rest.Config{Host: server.URL})
+	ready, err := ops.ApplyObjects(ctx, []unstructured.Unstructured{*configMapObject("config"), pod("running")},
+		manifest.ApplyOptions{CheckReadyStates: true})
+	require.NoError(t, err)
+	assert.True(t, ready, "objects are ready once applied and in their ready state")
+
+	ready, err = ops.ApplyObjects(ctx, []unstructured.Unstructured{*configMapObject("config"), pod("pending")},
+		manifest.ApplyOptions{CheckReadyStates: true})
+	require.NoError(t, err)
+	assert.False(t, ready, "pending Pods are not ready")
+	assert.NoError(t, clnt.Get(ctx, client.ObjectKey{Namespace: "default", Name: "pending"}, &v1.Pod{}),
+		"objects are applied regardless of their readiness")
+}
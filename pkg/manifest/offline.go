@@ -0,0 +1,97 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/go-logr/logr"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+
+	"github.com/kyma-project/module-manager/pkg/resource"
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+// RenderOffline renders the resources based on types.InstallInfo without a target cluster and applies
+// the transforms of the install to them, the ClusterInfo of the install is not used.
+// Helm charts are rendered client-only like `helm template`: CRDs of the chart are part of the manifest,
+// lookups find no objects and capabilities are the defaults of Helm for the passed Kubernetes version,
+// which defaults to the version supported by Helm if empty.
+func RenderOffline(info *types.InstallInfo, logger logr.Logger, kubeVersion string,
+) (string, *types.ManifestResources, error) {
+	manifest, err := renderOffline(info, logger, kubeVersion)
+	if err != nil {
+		return "", nil, err
+	}
+	objects, err := util.Transform(info.Ctx, manifest, info.BaseResource, info.Transforms)
+	if err != nil {
+		return "", nil, err
+	}
+	return manifest, objects, nil
+}
+
+func renderOffline(info *types.InstallInfo, logger logr.Logger, kubeVersion string) (string, error) {
+	// pre-rendered manifests are used as is
+	parsedFile := NewRendered(logger).GetManifestResources(info.ChartPath)
+	if parsedFile.IsResultConclusive() {
+		parsedFile = parsedFile.FilterOsErrors()
+		return parsedFile.GetContent(), parsedFile.GetRawError()
+	}
+
+	chartKind, err := resource.GetChartKind(info)
+	if err != nil {
+		return "", err
+	}
+	if chartKind == resource.KustomizeKind {
+		// kustomizations are rendered without a cluster anyway
+		parsedFile = (&kustomize{logger: logger}).GetRawManifest(info)
+		return parsedFile.GetContent(), parsedFile.GetRawError()
+	}
+	return renderChartOffline(info, logger, kubeVersion)
+}
+
+func renderChartOffline(info *types.InstallInfo, logger logr.Logger, kubeVersion string) (string, error) {
+	settings := cli.New()
+	actionClient := action.NewInstall(&action.Configuration{Log: func(string, ...interface{}) {}})
+	setDefaultFlags(actionClient, info.ReleaseName)
+	if err := setCustomFlags(actionClient, info.Flags); err != nil {
+		return "", err
+	}
+	// CRDs cannot be installed in advance without a cluster, so they are rendered as part of the manifest
+	actionClient.ClientOnly = true
+	actionClient.IncludeCRDs = true
+	if kubeVersion != "" {
+		parsedVersion, err := chartutil.ParseKubeVersion(kubeVersion)
+		if err != nil {
+			return "", fmt.Errorf("invalid Kubernetes version %q: %w", kubeVersion, err)
+		}
+		actionClient.KubeVersion = parsedVersion
+	}
+
+	repoHandler := NewRepoHandler(logger, settings)
+	chartPath := info.ChartPath
+	if chartPath == "" {
+		if err := repoHandler.Add(info.RepoName, info.URL); err != nil {
+			return "", err
+		}
+		var err error
+		if chartPath, err = actionClient.ChartPathOptions.LocateChart(info.ChartName, settings); err != nil {
+			return "", types.ErrChartNotFound.Wrap(err)
+		}
+	}
+
+	chartRequested, err := repoHandler.LoadChart(chartPath, actionClient)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", types.ErrChartNotFound.Wrap(err)
+	} else if err != nil {
+		return "", renderFailure(err)
+	}
+	release, err := actionClient.RunWithContext(info.Ctx, chartRequested, info.Flags.SetFlags)
+	if err != nil {
+		return "", renderFailure(err)
+	}
+	return release.Manifest, nil
+}
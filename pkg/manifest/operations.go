@@ -1,18 +1,30 @@
 package manifest
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"helm.sh/helm/v3/pkg/cli"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	manifestClient "github.com/kyma-project/module-manager/pkg/client"
 
 	"github.com/kyma-project/module-manager/pkg/labels"
+	logging "github.com/kyma-project/module-manager/pkg/log"
 	"github.com/kyma-project/module-manager/pkg/resource"
+	"github.com/kyma-project/module-manager/pkg/tracing"
 	"github.com/kyma-project/module-manager/pkg/types"
 	"github.com/kyma-project/module-manager/pkg/util"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -24,6 +36,18 @@ type Operations struct {
 	installInfo        *types.InstallInfo
 	resourceTransforms []types.ObjectTransform
 	postRuns           []types.PostRun
+	reportFindings     func([]types.SecurityFinding)
+	reportRelease      func(types.ReleaseRevision)
+	reportScheduling   func([]types.SchedulingIssue)
+	reportCapacity     func([]types.CapacityShortage)
+	reportMissingAPIs  func([]types.MissingAPI)
+	reportUpgrade      func(error)
+	reportMigrations   func([]string)
+	reportResources    func([]schema.GroupVersionResource)
+	reportHealth       func([]types.HealthIssue)
+	reportWaitFor      func([]types.UnsatisfiedWait)
+	reportApply        func(types.InstallApplyStatus)
+	reportBackup       func(types.BackupReference)
 	client             client.Client
 }
 
@@ -33,45 +57,191 @@ type OperationOptions struct {
 	ResourceTransforms []types.ObjectTransform
 	PostRuns           []types.PostRun
 	Cache              types.RendererCache
+	// ReportFindings is called with the findings of all content scanners of the install before resources are applied
+	ReportFindings func([]types.SecurityFinding)
+	// ReportRelease is called with the latest release revision, if the release history of the install is enabled
+	ReportRelease func(types.ReleaseRevision)
+	// ReportScheduling is called with the workloads of the install, which cannot be scheduled on any node
+	// of the target cluster, before resources are applied. If it is nil, scheduling is not verified.
+	ReportScheduling func([]types.SchedulingIssue)
+	// ReportCapacity is called with the resources requested by the workloads of the install beyond the capacity
+	// left in the target cluster, before resources are applied. If it is nil, capacity is not verified.
+	ReportCapacity func([]types.CapacityShortage)
+	// ReportMissingAPIs is called with the resource types of the rendered resources of the install,
+	// which are not served by the target cluster, before resources are applied or verified
+	ReportMissingAPIs func([]types.MissingAPI)
+	// ReportUpgrade is called with the violation of the types.InstallInfo.UpgradePolicy by the chart version
+	// of the install, or nil if the version is allowed, before anything is applied
+	ReportUpgrade func(error)
+	// ReportMigrations is called with the versions of all types.InstallInfo.Migrations applied to the install
+	ReportMigrations func([]string)
+	// ReportResources is called with the resource types of all resources applied by the install,
+	// e.g. to watch them for changes
+	ReportResources func([]schema.GroupVersionResource)
+	// ReportHealth is called with the degraded resources of the install during a consistency check,
+	// once its resources are applied. If it is nil, the health of resources is not evaluated.
+	ReportHealth func([]types.HealthIssue)
+	// ReportWaitFor is called with the objects of types.InstallInfo.WaitFor, which do not satisfy their expectation,
+	// before anything is applied
+	ReportWaitFor func([]types.UnsatisfiedWait)
+	// ReportApply is called with the result of the apply of each rendered resource of the install, also if the apply
+	// failed. If it is nil, the resources are not compared before and after they are applied.
+	ReportApply func(types.InstallApplyStatus)
+	// ReportBackup is called with the reference of the snapshot of the live resources of the install,
+	// once it was stored before an upgrade overwrites them
+	ReportBackup func(types.BackupReference)
 }
 
 var (
-	ErrCRsNotRemoved         = errors.New("CustomResources not completely removed")
-	ErrCRDsNotRemoved        = errors.New("CRDs not completely removed")
-	ErrUninstallInconsistent = errors.New("uninstallation inconsistent")
+	ErrCRsNotRemoved           = errors.New("CustomResources not completely removed")
+	ErrCRDsNotRemoved          = errors.New("CRDs not completely removed")
+	ErrUninstallInconsistent   = errors.New("uninstallation inconsistent")
+	ErrReleaseHistoryDisabled  = errors.New("release history disabled")
+	ErrRevisionFailed          = errors.New("release revision failed and was rolled back")
+	ErrRemovedInstallUntracked = errors.New("resources of removed install are not recorded in an inventory")
 )
 
 // InstallChart installs the resources based on types.InstallInfo and an appropriate rendering mechanism.
-func InstallChart(options OperationOptions) (bool, error) {
+func InstallChart(options OperationOptions) (ready bool, err error) {
+	options, span := withOperationSpan(options, tracing.SpanInstall)
+	defer func() { tracing.End(span, err) }()
+	options, cancel := withOperationTimeout(options)
+	defer cancel()
+
 	ops, err := NewOperations(options)
 	if err != nil {
-		return false, err
+		return false, WithReason(translateTimeout(options.InstallInfo, err))
 	}
 
-	return ops.install()
+	ready, err = ops.install()
+	return ready, WithReason(translateTimeout(options.InstallInfo, err))
 }
 
 // UninstallChart uninstalls the resources based on types.InstallInfo and an appropriate rendering mechanism.
-func UninstallChart(options OperationOptions) (bool, error) {
+func UninstallChart(options OperationOptions) (ready bool, err error) {
+	options, span := withOperationSpan(options, tracing.SpanUninstall)
+	defer func() { tracing.End(span, err) }()
+	options, cancel := withOperationTimeout(options)
+	defer cancel()
+
 	ops, err := NewOperations(options)
 	if err != nil {
-		return false, err
+		return false, WithReason(translateTimeout(options.InstallInfo, err))
 	}
 
-	return ops.uninstall()
+	ready, err = ops.uninstall()
+	return ready, WithReason(translateTimeout(options.InstallInfo, err))
+}
+
+// UninstallRemovedRelease uninstalls the resources recorded for the release with the passed name, whose install
+// was removed from the base resource of types.InstallInfo, while all other installs stay untouched.
+// As the removed install cannot be rendered anymore, only resources recorded in its Inventory are deleted.
+// If no Inventory of the release exists, ErrRemovedInstallUntracked is returned and nothing is deleted.
+func UninstallRemovedRelease(options OperationOptions, releaseName string) error {
+	installInfo := *options.InstallInfo
+	installInfo.ReleaseName = releaseName
+	options.InstallInfo = &installInfo
+	options, cancel := withOperationTimeout(options)
+	defer cancel()
+
+	ops, err := NewOperations(options)
+	if err != nil {
+		return WithReason(translateTimeout(options.InstallInfo, err))
+	}
+	if err := ops.uninstallRemoved(); err != nil {
+		return WithReason(translateTimeout(options.InstallInfo, err))
+	}
+
+	// the processor of the removed release is not used anymore
+	if options.Cache != nil && releaseName != "" {
+		_, processorKey, err := processorCacheKeys(options.InstallInfo, options.Logger)
+		if err != nil {
+			return err
+		}
+		options.Cache.DeleteProcessor(processorKey)
+		options.Cache.DeleteConfig(ReleaseCacheKey(client.ObjectKeyFromObject(installInfo.BaseResource), releaseName))
+	}
+	return nil
+}
+
+// RollbackChart rolls back the resources based on types.InstallInfo to the passed revision of the release history.
+func RollbackChart(options OperationOptions, revision int) (ready bool, err error) {
+	options, span := withOperationSpan(options, tracing.SpanRollback, attribute.Int("revision", revision))
+	defer func() { tracing.End(span, err) }()
+	options, cancel := withOperationTimeout(options)
+	defer cancel()
+
+	ops, err := NewOperations(options)
+	if err != nil {
+		return false, WithReason(translateTimeout(options.InstallInfo, err))
+	}
+
+	ready, err = ops.Rollback(revision)
+	return ready, WithReason(translateTimeout(options.InstallInfo, err))
 }
 
 // ConsistencyCheck verifies consistency of resources based on types.InstallInfo and an appropriate rendering mechanism.
-func ConsistencyCheck(options OperationOptions) (bool, error) {
+func ConsistencyCheck(options OperationOptions) (ready bool, err error) {
+	options, span := withOperationSpan(options, tracing.SpanConsistencyCheck)
+	defer func() { tracing.End(span, err) }()
+	options, cancel := withOperationTimeout(options)
+	defer cancel()
+
 	ops, err := NewOperations(options)
 	if err != nil {
-		return false, err
+		return false, WithReason(translateTimeout(options.InstallInfo, err))
+	}
+
+	ready, err = ops.consistencyCheck()
+	return ready, WithReason(translateTimeout(options.InstallInfo, err))
+}
+
+// withOperationSpan returns options with a copy of the InstallInfo, whose context carries a new span
+// of the operation, so that the spans of its steps are recorded as its children.
+func withOperationSpan(options OperationOptions, name string, attributes ...attribute.KeyValue,
+) (OperationOptions, trace.Span) {
+	if options.InstallInfo == nil || options.InstallInfo.Ctx == nil {
+		return options, trace.SpanFromContext(context.Background())
+	}
+	installInfo := *options.InstallInfo
+	if installInfo.ChartInfo != nil {
+		attributes = append(attributes, attribute.String("release", installInfo.ReleaseName))
 	}
+	ctx, span := tracing.Start(installInfo.Ctx, name, attributes...)
+	installInfo.Ctx = ctx
+	options.InstallInfo = &installInfo
+	return options, span
+}
 
-	return ops.consistencyCheck()
+// withOperationTimeout returns options with a copy of the InstallInfo,
+// whose context is cancelled after types.InstallInfo.Timeout.
+func withOperationTimeout(options OperationOptions) (OperationOptions, context.CancelFunc) {
+	if options.InstallInfo == nil || options.InstallInfo.Timeout <= 0 || options.InstallInfo.Ctx == nil {
+		return options, func() {}
+	}
+	installInfo := *options.InstallInfo
+	ctx, cancel := context.WithTimeout(installInfo.Ctx, installInfo.Timeout)
+	installInfo.Ctx = ctx
+	options.InstallInfo = &installInfo
+	return options, cancel
+}
+
+// translateTimeout wraps errors caused by an exceeded operation timeout into a types.OperationTimeoutError.
+func translateTimeout(installInfo *types.InstallInfo, err error) error {
+	if err == nil || installInfo == nil || installInfo.Timeout <= 0 {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) ||
+		(installInfo.Ctx != nil && errors.Is(installInfo.Ctx.Err(), context.DeadlineExceeded)) {
+		return &types.OperationTimeoutError{Timeout: installInfo.Timeout, Err: err}
+	}
+	return err
 }
 
 func NewOperations(options OperationOptions) (*Operations, error) {
+	if options.InstallInfo.ChartInfo != nil {
+		options.Logger = logging.ForInstall(options.Logger, options.InstallInfo.ReleaseName)
+	}
 	renderSrc, err := getRenderSrc(options.Cache, options.InstallInfo, options.Logger)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create manifest processor: %w", err)
@@ -81,15 +251,40 @@ func NewOperations(options OperationOptions) (*Operations, error) {
 		return nil, err
 	}
 
+	// declarative transforms of the install are chained after the programmatic transforms
+	resourceTransforms := make([]types.ObjectTransform, 0,
+		len(options.ResourceTransforms)+len(options.InstallInfo.Transforms))
+	resourceTransforms = append(resourceTransforms, options.ResourceTransforms...)
+	resourceTransforms = append(resourceTransforms, options.InstallInfo.Transforms...)
+
 	ops := &Operations{
 		logger:             options.Logger,
 		renderSrc:          renderSrc,
 		installInfo:        options.InstallInfo,
-		resourceTransforms: options.ResourceTransforms,
+		resourceTransforms: resourceTransforms,
 		postRuns:           options.PostRuns,
+		reportFindings:     options.ReportFindings,
+		reportRelease:      options.ReportRelease,
+		reportScheduling:   options.ReportScheduling,
+		reportCapacity:     options.ReportCapacity,
+		reportMissingAPIs:  options.ReportMissingAPIs,
+		reportUpgrade:      options.ReportUpgrade,
+		reportMigrations:   options.ReportMigrations,
+		reportResources:    options.ReportResources,
+		reportHealth:       options.ReportHealth,
+		reportWaitFor:      options.ReportWaitFor,
+		reportApply:        options.ReportApply,
+		reportBackup:       options.ReportBackup,
 		client:             clusterInfo.Client,
 	}
 
+	// namespaces are moved last, so that mappings match the resources as transformed before
+	if chartInfo := options.InstallInfo.ChartInfo; chartInfo != nil && clusterInfo.Client != nil &&
+		(chartInfo.TargetNamespace != "" || len(chartInfo.NamespaceMappings) > 0) {
+		ops.resourceTransforms = append(ops.resourceTransforms, NamespaceTransform(clusterInfo.Client.RESTMapper(),
+			ops.targetNamespace(), chartInfo.TargetNamespace != "", chartInfo.NamespaceMappings))
+	}
+
 	return ops, nil
 }
 
@@ -103,7 +298,7 @@ func getRenderSrc(
 	var renderSrc types.ManifestClient
 
 	/* Manifest processor handling */
-	clusterCacheKey, err := discoverCacheKey(deployInfo.BaseResource, logger)
+	rateLimiterKey, clusterCacheKey, err := processorCacheKeys(deployInfo, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -117,15 +312,17 @@ func getRenderSrc(
 	// look for existing processor entries
 	// read manifest renderer from processor
 	if renderSrc = cache.GetProcessor(clusterCacheKey); renderSrc == nil {
-		renderSrc, err = getManifestProcessor(deployInfo, logger)
+		renderSrc, err = getManifestProcessor(withRateLimiter(cache, rateLimiterKey, deployInfo), logger)
 		if err != nil {
 			return nil, err
 		}
+	} else if err = invalidateOnServerVersionChange(renderSrc, deployInfo, logger); err != nil {
+		return nil, err
 	}
 
 	/* Configuration handling */
 	// if there is no update on config - return from here
-	nsNameBaseResource := client.ObjectKeyFromObject(deployInfo.BaseResource)
+	nsNameBaseResource := ReleaseCacheKey(client.ObjectKeyFromObject(deployInfo.BaseResource), deployInfo.ReleaseName)
 	configHash, err := renderSrc.InvalidateConfigAndRenderedManifest(
 		deployInfo,
 		cache.GetConfig(nsNameBaseResource),
@@ -147,6 +344,36 @@ func getRenderSrc(
 	return renderSrc, nil
 }
 
+// processorCacheKeys returns the key of the cluster of the install, which its rate limiter is cached by,
+// and the key of its processor. Processors are bound to a single release and impersonated identity,
+// so that installs of different releases to the cluster do not share action clients.
+func processorCacheKeys(deployInfo *types.InstallInfo, logger logr.Logger) (client.ObjectKey, client.ObjectKey, error) {
+	clusterKey, err := discoverCacheKey(deployInfo.BaseResource, logger)
+	if err != nil {
+		return client.ObjectKey{}, client.ObjectKey{}, err
+	}
+	processorKey := clusterKey
+	if deployInfo.ClusterInfo != nil && deployInfo.Config != nil {
+		processorKey = ImpersonatedCacheKey(processorKey, deployInfo.Config.Impersonate)
+	}
+	return clusterKey, ReleaseCacheKey(processorKey, deployInfo.ReleaseName), nil
+}
+
+// withRateLimiter returns a copy of the passed types.InstallInfo, whose REST config uses the rate limiter
+// shared by all processors of the cluster, so that they do not exceed its QPS and burst together.
+func withRateLimiter(cache types.RendererCache, key client.ObjectKey, deployInfo *types.InstallInfo,
+) *types.InstallInfo {
+	if deployInfo.ClusterInfo == nil || deployInfo.Config == nil {
+		return deployInfo
+	}
+	clusterInfo := *deployInfo.ClusterInfo
+	clusterInfo.Config = rest.CopyConfig(deployInfo.Config)
+	clusterInfo.Config.RateLimiter = cache.GetRateLimiter(key, deployInfo.Config)
+	limitedInfo := *deployInfo
+	limitedInfo.ClusterInfo = &clusterInfo
+	return &limitedInfo
+}
+
 // discoverCacheKey returns processor key for caching of manifest renderer,
 // by label value operator.kyma-project.io/processor-key.
 // If label not found on base resource an empty processor key is returned.
@@ -177,6 +404,36 @@ func discoverCacheKey(resource client.Object, logger logr.Logger) (client.Object
 	return client.ObjectKey{Name: label, Namespace: resource.GetNamespace()}, nil
 }
 
+// ImpersonatedCacheKey returns the processor key for an impersonated identity on the cluster of the passed key.
+// As clients of a processor are bound to the identity they were created with,
+// processors of different impersonated identities are cached separately.
+func ImpersonatedCacheKey(key client.ObjectKey, impersonation rest.ImpersonationConfig) client.ObjectKey {
+	if impersonation.UserName == "" {
+		return key
+	}
+	key.Name = strings.Join(append([]string{key.Name, impersonation.UserName}, impersonation.Groups...), "|")
+	return key
+}
+
+// invalidateOnServerVersionChange invalidates the cached discovery data of a cached processor
+// after an upgrade of its target cluster, which would otherwise lead to NoKindMatch errors
+// or resources applied with removed versions.
+func invalidateOnServerVersionChange(renderSrc types.ManifestClient, deployInfo *types.InstallInfo,
+	logger logr.Logger,
+) error {
+	if deployInfo.ServerVersionCheckInterval <= 0 {
+		return nil
+	}
+	invalidated, err := renderSrc.InvalidateOnServerVersionChange(deployInfo.ServerVersionCheckInterval)
+	if err != nil {
+		return fmt.Errorf("checking API server version of target cluster: %w", err)
+	}
+	if invalidated {
+		logger.Info("API server version of target cluster changed, invalidated cached discovery data")
+	}
+	return nil
+}
+
 // getManifestProcessor returns a new types.ManifestClient instance
 // this render source will handle subsequent Operations for manifest resources based on types.InstallInfo.
 func getManifestProcessor(deployInfo *types.InstallInfo, logger logr.Logger) (types.ManifestClient, error) {
@@ -196,7 +453,7 @@ func getManifestProcessor(deployInfo *types.InstallInfo, logger logr.Logger) (ty
 		// create HelmClient instance
 		return NewHelmProcessor(
 			singletonClients, cli.New(), logger,
-			render, deployInfo, true,
+			render, deployInfo,
 		)
 	case resource.KustomizeKind:
 		// create dynamic client for rest config
@@ -232,20 +489,36 @@ func (o *Operations) consistencyCheck() (bool, error) {
 	}
 
 	// process manifest
-	parsedFile := o.getManifestForChartPath(o.installInfo)
+	parsedFile := o.getManifestForRelease()
 	if parsedFile.GetRawError() != nil {
 		return false, parsedFile
 	}
 
+	// resources of missing APIs are either skipped or fail the check like the install
+	if err := o.verifyAPIs(parsedFile.GetContent()); err != nil {
+		return false, err
+	}
+
 	// consistency check
 	consistent, err := o.renderSrc.IsConsistent(
 		parsedFile.GetContent(),
 		o.installInfo, o.resourceTransforms, o.postRuns,
 	)
+	// resources are applied, unless the check failed before the verification of their readiness
+	if (err == nil && consistent) || errors.Is(err, ErrResourceNotReady) {
+		if healthErr := o.verifyHealth(parsedFile.GetContent()); healthErr != nil {
+			return false, healthErr
+		}
+	}
 	if err != nil || !consistent {
 		return false, err
 	}
 
+	// record installed resources and prune resources no longer part of the manifest
+	if err := o.syncInventory(parsedFile.GetContent()); err != nil {
+		return false, err
+	}
+
 	// custom states check
 	if o.installInfo.CheckFn != nil {
 		return o.installInfo.CheckFn(o.installInfo.Ctx, o.installInfo.BaseResource, o.logger, o.installInfo.ClusterInfo)
@@ -254,6 +527,21 @@ func (o *Operations) consistencyCheck() (bool, error) {
 }
 
 func (o *Operations) install() (bool, error) {
+	// wait for the objects the install depends on, e.g. of other modules, before anything is applied
+	if satisfied, err := o.waitForDependencies(); err != nil || !satisfied {
+		return false, err
+	}
+
+	// block installs on target clusters not supported by the module before anything is applied
+	if err := o.verifyKubernetesVersion(); err != nil {
+		return false, err
+	}
+
+	// block upgrades skipping mandatory versions and downgrades before anything is applied
+	if err := o.verifyUpgrade(); err != nil {
+		return false, err
+	}
+
 	// install crds first - if present do not update!
 	if err := resource.CheckCRDs(
 		o.installInfo.Ctx, o.installInfo.Crds, o.client, true,
@@ -262,19 +550,94 @@ func (o *Operations) install() (bool, error) {
 	}
 
 	// process manifest
-	parsedFile := o.getManifestForChartPath(o.installInfo)
+	parsedFile := o.getManifestForRelease()
 	if parsedFile.GetRawError() != nil {
 		return false, parsedFile.GetRawError()
 	}
 
-	// install resources
-	consistent, err := o.renderSrc.Install(
-		parsedFile.GetContent(),
-		o.installInfo,
-		o.resourceTransforms,
-		o.postRuns,
-	)
-	if err != nil || !consistent {
+	// probe the target cluster for the APIs of all resources, so that missing APIs do not fail halfway through
+	if err := o.verifyAPIs(parsedFile.GetContent()); err != nil {
+		return false, err
+	}
+
+	// migrate previously applied resources before they are compared with the rendered resources
+	if err := o.migrate(parsedFile.GetContent()); err != nil {
+		return false, err
+	}
+
+	// scan rendered resources before they are applied
+	if err := o.scan(parsedFile.GetContent()); err != nil {
+		return false, err
+	}
+
+	// verify workloads fit the nodes of the target cluster, so they do not silently wait for a readiness timeout
+	if err := o.verifyScheduling(parsedFile.GetContent()); err != nil {
+		return false, err
+	}
+
+	// block installs whose workloads exceed the capacity left in the target cluster, so pods are not pending forever
+	if err := o.verifyCapacity(parsedFile.GetContent()); err != nil {
+		return false, err
+	}
+
+	// refuse or adopt resources existing before the first apply, so that foreign resources are not taken over silently
+	if err := o.adoptExisting(parsedFile.GetContent()); err != nil {
+		return false, err
+	}
+
+	// snapshot the live resources before an upgrade overwrites them, so that a failed upgrade can be restored
+	if err := o.backup(parsedFile.GetContent()); err != nil {
+		return false, err
+	}
+
+	// handle leftovers of a previously failed attempt before resources are applied again
+	attempt, err := o.handlePartialInstall(parsedFile.GetContent())
+	if err != nil {
+		return false, err
+	}
+
+	// run pre-install or pre-upgrade hooks of the chart before the manifest is applied
+	if err := o.runHooks(parsedFile.GetContent(), false); err != nil {
+		return false, err
+	}
+
+	// install resources, comparing them before and after the apply to report how each of them changed
+	tracker, err := o.trackApply(parsedFile.GetContent())
+	if err != nil {
+		return false, err
+	}
+	consistent, err := o.release(parsedFile.GetContent())
+	if reportErr := tracker.report(err); reportErr != nil && err == nil {
+		return false, reportErr
+	}
+	if err != nil {
+		return false, o.failAttempt(attempt, parsedFile.GetContent(), err)
+	}
+	if !consistent {
+		return false, nil
+	}
+
+	// record installed resources and prune resources no longer part of the manifest
+	if err := o.syncInventory(parsedFile.GetContent()); err != nil {
+		return false, err
+	}
+	if attempt != nil {
+		if err := o.installAttempts().Complete(o.installInfo.Ctx); err != nil {
+			return false, err
+		}
+	}
+
+	if err := o.reportAppliedResources(parsedFile.GetContent()); err != nil {
+		return false, err
+	}
+
+	// restart consumers of rotated Secrets one after another, once the applied resources are ready
+	if rotated, err := o.rotateSecrets(parsedFile.GetContent()); err != nil || !rotated {
+		return false, err
+	}
+
+	// run post-install or post-upgrade hooks of the chart once the applied resources are ready
+	if err := o.runHooks(parsedFile.GetContent(), true); err != nil {
 		return false, err
 	}
 
@@ -324,10 +687,57 @@ func (o *Operations) uninstall() (bool, error) {
 		return false, ErrUninstallInconsistent
 	}
 
+	// remove resources recorded during previous installations, which might not be part of the manifest anymore
+	if o.installInfo.TrackInventory {
+		if err := NewInventory(o.client, o.installInfo.BaseResource, o.installInfo.ReleaseName).
+			Purge(o.installInfo.Ctx); err != nil {
+			return false, err
+		}
+		if err := o.installAttempts().Purge(o.installInfo.Ctx); err != nil {
+			return false, err
+		}
+	}
+
+	// remove recorded hooks, delete hooks are not run
+	if err := NewHookLedger(o.client, o.installInfo.BaseResource, o.installInfo.ReleaseName).
+		Purge(o.installInfo.Ctx); err != nil {
+		return false, err
+	}
+
+	// remove the snapshot taken before the last upgrade
+	if o.installInfo.Backup != nil {
+		if err := o.resourceSnapshots().Purge(o.installInfo.Ctx); err != nil {
+			return false, err
+		}
+	}
+
+	// remove recorded release revisions
+	if o.installInfo.ReleaseHistoryLimit > 0 {
+		history, err := o.loadReleaseHistory()
+		if err != nil {
+			return false, err
+		}
+		if err := history.Purge(o.installInfo.Ctx); err != nil {
+			return false, err
+		}
+	}
+
 	// delete crds last - if not present ignore!
-	crdDeleted := resource.RemoveCRDs(o.installInfo.Ctx, o.installInfo.Crds, o.client)
-	if !crdDeleted {
-		return false, ErrCRDsNotRemoved
+	// they are kept by default, as their removal also deletes all custom resources of them in the cluster
+	if o.installInfo.CRDPolicy.DeletesCRDs() {
+		if crdDeleted := resource.RemoveCRDs(o.installInfo.Ctx, o.installInfo.Crds, o.client); !crdDeleted {
+			return false, ErrCRDsNotRemoved
+		}
+	}
+
+	// remove cluster-scoped resources owned by the base resource, which were not part of the uninstalled manifest
+	leftovers, cleanupErr := CleanupClusterScopedLeftovers(o.installInfo.Ctx, o.client, o.installInfo.BaseResource)
+	if len(leftovers) > 0 {
+		o.logger.Info("removed cluster-scoped leftovers of uninstalled resources",
+			"resource", client.ObjectKeyFromObject(o.installInfo.BaseResource).String(), "leftovers", leftovers)
+	}
+	if cleanupErr != nil {
+		return false, cleanupErr
 	}
 
 	// custom states check
@@ -337,10 +747,690 @@ func (o *Operations) uninstall() (bool, error) {
 	return true, err
 }
 
+// uninstallRemoved deletes all resources recorded in the Inventory of a removed install,
+// together with its recorded install attempts, hooks, backup and release revisions.
+// Without an Inventory, all records are kept, as the resources of the install are not known.
+func (o *Operations) uninstallRemoved() error {
+	if !o.installInfo.TrackInventory {
+		return ErrRemovedInstallUntracked
+	}
+	inventory := NewInventory(o.client, o.installInfo.BaseResource, o.installInfo.ReleaseName)
+	if _, err := inventory.Load(o.installInfo.Ctx); errors.Is(err, ErrInventoryNotFound) {
+		return ErrRemovedInstallUntracked
+	} else if err != nil {
+		return err
+	}
+	if err := NewHookLedger(o.client, o.installInfo.BaseResource, o.installInfo.ReleaseName).
+		Purge(o.installInfo.Ctx); err != nil {
+		return err
+	}
+	if err := inventory.Purge(o.installInfo.Ctx); err != nil {
+		return err
+	}
+	if err := o.installAttempts().Purge(o.installInfo.Ctx); err != nil {
+		return err
+	}
+	if o.installInfo.Backup != nil {
+		if err := o.resourceSnapshots().Purge(o.installInfo.Ctx); err != nil {
+			return err
+		}
+	}
+	if o.installInfo.ReleaseHistoryLimit > 0 {
+		history, err := o.loadReleaseHistory()
+		if err != nil {
+			return err
+		}
+		return history.Purge(o.installInfo.Ctx)
+	}
+	return nil
+}
+
+// release installs the passed manifest. If the release history of the install is enabled,
+// every change of the manifest is recorded as a new revision and applied as an upgrade of the last deployed revision.
+// A revision not ready within types.InstallInfo.RollbackWindow is rolled back to the last deployed revision.
+// A manifest matching a revision that failed before is not applied again.
+func (o *Operations) release(manifest string) (bool, error) {
+	if o.installInfo.ReleaseHistoryLimit <= 0 {
+		return o.renderSrc.Install(manifest, o.installInfo, o.resourceTransforms, o.postRuns)
+	}
+	history, err := o.loadReleaseHistory()
+	if err != nil {
+		return false, err
+	}
+	hash, err := util.CalculateHash(manifest)
+	if err != nil {
+		return false, err
+	}
+
+	var failedErr error
+	latest := history.Latest()
+	switch failed := history.Failed(hash); {
+	case failed != nil && latest.Hash != hash:
+		// keep the revision rolled back to until the manifest changes
+		failedErr = fmt.Errorf("%w: revision %d", ErrRevisionFailed, failed.Revision)
+	case latest == nil || latest.Hash != hash:
+		if latest, err = history.Add(manifest, hash, types.ReleaseStatusPending, "upgrade"); err != nil {
+			return false, err
+		}
+	}
+
+	revision, previous := latest.Revision, 0
+	if deployed := history.LastDeployed(); deployed != nil {
+		previous = deployed.Revision
+	}
+	ready, err := o.deployRevision(history, revision, previous)
+	if pending := history.Revision(revision); (err != nil || !ready) && previous != 0 &&
+		pending.Status == types.ReleaseStatusPending && o.installInfo.RollbackWindow > 0 &&
+		time.Since(pending.Created.Time) > o.installInfo.RollbackWindow {
+		o.logger.Info("release revision not ready within rollback window, rolling back",
+			"release", o.installInfo.ReleaseName, "revision", revision, "rollbackTo", previous, "error", err)
+		ready, err = o.rollback(history, previous)
+	}
+	if saveErr := o.saveReleaseHistory(history); saveErr != nil {
+		return false, saveErr
+	}
+	if err != nil {
+		return false, err
+	}
+	return ready, failedErr
+}
+
+// Rollback applies the manifest recorded for the passed revision as a new revision of the release history.
+// A pending revision is marked as failed.
+func (o *Operations) Rollback(revision int) (bool, error) {
+	if o.installInfo.ReleaseHistoryLimit <= 0 {
+		return false, ErrReleaseHistoryDisabled
+	}
+	history, err := o.loadReleaseHistory()
+	if err != nil {
+		return false, err
+	}
+	ready, err := o.rollback(history, revision)
+	if saveErr := o.saveReleaseHistory(history); saveErr != nil {
+		return false, saveErr
+	}
+	return ready, err
+}
+
+func (o *Operations) rollback(history *ReleaseHistory, revision int) (bool, error) {
+	target := history.Revision(revision)
+	if target == nil {
+		return false, fmt.Errorf("revision %d not found in release history", revision)
+	}
+	manifest, err := history.Manifest(revision)
+	if err != nil {
+		return false, err
+	}
+	hash := target.Hash
+
+	from := history.Latest().Revision
+	if latest := history.Latest(); latest.Status == types.ReleaseStatusPending {
+		latest.Status = types.ReleaseStatusFailed
+	}
+	record, err := history.Add(manifest, hash, types.ReleaseStatusPending, fmt.Sprintf("rollback to %d", revision))
+	if err != nil {
+		return false, err
+	}
+	record.RolledBackFrom = from
+	return o.deployRevision(history, record.Revision, from)
+}
+
+// deployRevision applies the manifest of the passed revision as an upgrade of the previous revision.
+// The revision is marked as deployed once it is ready.
+func (o *Operations) deployRevision(history *ReleaseHistory, revision, previous int) (bool, error) {
+	manifest, err := history.Manifest(revision)
+	if err != nil {
+		return false, err
+	}
+	var previousManifest string
+	if previous != 0 && previous != revision {
+		// the previous revision might have been dropped from the history already
+		previousManifest, _ = history.Manifest(previous)
+	}
+	ready, err := o.renderSrc.Upgrade(previousManifest, manifest,
+		o.installInfo, o.resourceTransforms, o.postRuns)
+	if err != nil || !ready {
+		return false, err
+	}
+	if history.Revision(revision).Status == types.ReleaseStatusPending {
+		history.MarkDeployed(revision)
+	}
+	return true, nil
+}
+
+func (o *Operations) loadReleaseHistory() (*ReleaseHistory, error) {
+	storageClient, err := ReleaseStorageClient(o.client, o.installInfo.Config, o.installInfo.ReleaseStorage)
+	if err != nil {
+		return nil, err
+	}
+	return LoadReleaseHistory(o.installInfo.Ctx, storageClient, o.installInfo.BaseResource, o.installInfo.ReleaseName,
+		o.installInfo.ReleaseStorage, o.installInfo.ReleaseHistoryLimit, o.installInfo.ReleaseEncrypter)
+}
+
+func (o *Operations) saveReleaseHistory(history *ReleaseHistory) error {
+	if err := history.Save(o.installInfo.Ctx); err != nil {
+		return err
+	}
+	if latest := history.Latest(); latest != nil && o.reportRelease != nil {
+		o.reportRelease(latest.ToRevision(o.installInfo.ReleaseName))
+	}
+	return nil
+}
+
+// waitForDependencies indicates if all objects of the types.InstallInfo.WaitFor of the install
+// satisfy their expectation, otherwise the install is not ready and is processed again.
+func (o *Operations) waitForDependencies() (bool, error) {
+	if len(o.installInfo.WaitFor) == 0 {
+		return true, nil
+	}
+	unsatisfied, err := util.UnsatisfiedWaits(o.installInfo.Ctx, o.client, o.installInfo.WaitFor)
+	if err != nil {
+		return false, err
+	}
+	if o.reportWaitFor != nil {
+		o.reportWaitFor(unsatisfied)
+	}
+	if len(unsatisfied) > 0 {
+		o.logger.Info("waiting for dependencies of install", "dependencies", types.UnsatisfiedWaitsMessage(unsatisfied),
+			"resource", client.ObjectKeyFromObject(o.installInfo.BaseResource).String())
+		return false, nil
+	}
+	return true, nil
+}
+
+// verifyKubernetesVersion returns a types.ErrUnsupportedKubernetesVersion if the version of the target cluster
+// does not satisfy the Kubernetes versions supported by the install.
+func (o *Operations) verifyKubernetesVersion() error {
+	if o.installInfo.KubernetesVersions == "" {
+		return nil
+	}
+	serverVersion, err := o.renderSrc.GetServerVersion()
+	if err != nil {
+		return fmt.Errorf("determining Kubernetes version of target cluster: %w", err)
+	}
+	supported, err := util.KubernetesVersionSupported(o.installInfo.KubernetesVersions, serverVersion.GitVersion)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return types.ErrUnsupportedKubernetesVersion.Wrap(fmt.Errorf("version %s of the target cluster does not "+
+			"satisfy the supported versions %q", serverVersion.GitVersion, o.installInfo.KubernetesVersions))
+	}
+	return nil
+}
+
+// verifyUpgrade returns a types.ErrUpgradeNotAllowed if the chart version of the install violates its
+// types.UpgradePolicy compared to the version installed last.
+func (o *Operations) verifyUpgrade() error {
+	if o.installInfo.UpgradePolicy == nil {
+		return nil
+	}
+	err := util.ValidateUpgrade(o.installInfo.InstalledVersion, ChartVersion(o.installInfo), o.installInfo.UpgradePolicy)
+	if o.reportUpgrade != nil {
+		o.reportUpgrade(err)
+	}
+	return err
+}
+
+// scan runs all content scanners of the install on the transformed resources of the passed manifest.
+// Findings are reported and, in types.ScanModeBlock, returned as types.SecurityFindingsError.
+func (o *Operations) scan(manifest string) error {
+	if o.installInfo.ScanMode == types.ScanModeDisabled || len(o.installInfo.Scanners) == 0 {
+		return nil
+	}
+	objects, err := util.Transform(o.installInfo.Ctx, manifest, o.installInfo.BaseResource, o.resourceTransforms)
+	if err != nil {
+		return err
+	}
+	findings, err := util.ScanObjects(o.installInfo.Ctx, o.installInfo.Scanners, objects.Items)
+	if err != nil {
+		return err
+	}
+	if o.reportFindings != nil {
+		o.reportFindings(findings)
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+	if o.installInfo.ScanMode == types.ScanModeBlock {
+		return &types.SecurityFindingsError{Findings: findings}
+	}
+	for _, finding := range findings {
+		o.logger.Info("security finding in rendered resources", "finding", finding.String())
+	}
+	return nil
+}
+
+// verifyScheduling reports all workloads of the passed manifest, which cannot be scheduled on any node
+// of the target cluster. Unschedulable workloads are still applied, as matching nodes could be added later,
+// e.g. by an autoscaler. Verification is skipped if the nodes of the target cluster cannot be listed.
+func (o *Operations) verifyScheduling(manifest string) error {
+	if o.reportScheduling == nil {
+		return nil
+	}
+	nodeList := &corev1.NodeList{}
+	if err := o.client.List(o.installInfo.Ctx, nodeList); err != nil {
+		if ctxErr := o.installInfo.Ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		o.logger.V(util.DebugLogLevel).Info("skipping scheduling verification, nodes cannot be listed",
+			"resource", client.ObjectKeyFromObject(o.installInfo.BaseResource).String(), "error", err.Error())
+		return nil
+	}
+	// clusters without nodes, e.g. for testing, cannot run any workload
+	if len(nodeList.Items) == 0 {
+		return nil
+	}
+	objects, err := util.Transform(o.installInfo.Ctx, manifest, o.installInfo.BaseResource, o.resourceTransforms)
+	if err != nil {
+		return err
+	}
+	issues, err := util.FindUnschedulableWorkloads(objects.Items, nodeList.Items)
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		o.logger.Info("workload cannot be scheduled on any node of the target cluster", "issue", issue.String())
+	}
+	o.reportScheduling(issues)
+	return nil
+}
+
+// verifyAPIs reports the resource types of the passed manifest, which are not served by the target cluster.
+// According to types.InstallInfo.MissingAPIPolicy, their resources are skipped by all further steps of the operation
+// or a types.ErrPrerequisitesNotMet is returned for them.
+func (o *Operations) verifyAPIs(manifest string) error {
+	objects, err := util.Transform(o.installInfo.Ctx, manifest, o.installInfo.BaseResource, o.resourceTransforms)
+	if err != nil {
+		return err
+	}
+	missing, err := util.FindMissingAPIs(objects.Items, o.client.RESTMapper())
+	if err != nil {
+		return err
+	}
+	if o.reportMissingAPIs != nil {
+		o.reportMissingAPIs(missing)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	messages := make([]string, 0, len(missing))
+	for _, api := range missing {
+		messages = append(messages, api.String())
+	}
+	if o.installInfo.MissingAPIPolicy == types.MissingAPIPolicySkip {
+		o.logger.Info("skipping resources of APIs not served by target cluster", "apis", messages,
+			"resource", client.ObjectKeyFromObject(o.installInfo.BaseResource).String())
+		o.resourceTransforms = append(o.resourceTransforms, util.MissingAPITransform(missing))
+		return nil
+	}
+	return types.ErrPrerequisitesNotMet.Wrap(errors.New(strings.Join(messages, "; ")))
+}
+
+// verifyCapacity reports all resources requested by the workloads of the passed manifest beyond the capacity
+// left by the ResourceQuotas or the schedulable nodes of the target cluster and returns a
+// types.ErrInsufficientCapacity for them. Quotas or nodes, which cannot be listed, are not verified.
+func (o *Operations) verifyCapacity(manifest string) error {
+	if o.reportCapacity == nil {
+		return nil
+	}
+	nodeList := &corev1.NodeList{}
+	if err := o.listForVerification(nodeList, "nodes"); err != nil {
+		return err
+	}
+	quotaList := &corev1.ResourceQuotaList{}
+	if err := o.listForVerification(quotaList, "resource quotas"); err != nil {
+		return err
+	}
+	// clusters without nodes, e.g. for testing, cannot run any workload
+	if len(nodeList.Items) == 0 && len(quotaList.Items) == 0 {
+		return nil
+	}
+
+	objects, err := util.Transform(o.installInfo.Ctx, manifest, o.installInfo.BaseResource, o.resourceTransforms)
+	if err != nil {
+		return err
+	}
+	defaultNamespaces(o.client.RESTMapper(), objects.Items, o.targetNamespace())
+	requested, err := util.WorkloadRequests(objects.Items)
+	if err != nil {
+		return err
+	}
+	appliedObjects, err := o.appliedWorkloads(objects.Items)
+	if err != nil {
+		return err
+	}
+	applied, err := util.WorkloadRequests(appliedObjects)
+	if err != nil {
+		return err
+	}
+
+	shortages := util.FindCapacityShortages(requested, applied, quotaList.Items, nodeList.Items)
+	o.reportCapacity(shortages)
+	if len(shortages) == 0 {
+		return nil
+	}
+	messages := make([]string, 0, len(shortages))
+	for _, shortage := range shortages {
+		messages = append(messages, shortage.String())
+	}
+	return types.ErrInsufficientCapacity.Wrap(errors.New(strings.Join(messages, "; ")))
+}
+
+// verifyHealth reports the applied resources of the passed manifest, which are degraded according to their status,
+// e.g. Deployments with fewer ready replicas than desired, or according to the types.InstallInfo.HealthRules.
+func (o *Operations) verifyHealth(manifest string) error {
+	if o.reportHealth == nil {
+		return nil
+	}
+	objects, err := util.Transform(o.installInfo.Ctx, manifest, o.installInfo.BaseResource, o.resourceTransforms)
+	if err != nil {
+		return err
+	}
+	defaultNamespaces(o.client.RESTMapper(), objects.Items, o.targetNamespace())
+	applied, err := o.appliedObjects(objects.Items, func(obj *unstructured.Unstructured) bool {
+		return util.IsHealthChecked(obj) || util.MatchesHealthRules(o.installInfo.HealthRules, obj)
+	})
+	if err != nil {
+		return err
+	}
+	issues, err := util.FindHealthIssues(applied)
+	if err != nil {
+		return err
+	}
+	ruleIssues, err := util.FindHealthRuleIssues(client.ObjectKeyFromObject(o.installInfo.BaseResource),
+		o.installInfo.ReleaseName, o.installInfo.HealthRules, applied)
+	if err != nil {
+		return err
+	}
+	o.reportHealth(append(issues, ruleIssues...))
+	return nil
+}
+
+// rotateSecrets restarts the applied workloads consuming Secrets of the passed manifest, whose data changed since
+// their pods were rolled out. Consumers are restarted one after another in the configured order and only once
+// the previously restarted consumer is rolled out, so that rotated credentials do not disrupt all consumers at once.
+// It returns true once all consumers run with the current Secrets.
+func (o *Operations) rotateSecrets(manifest string) (bool, error) {
+	if o.installInfo.SecretRotation == nil {
+		return true, nil
+	}
+	objects, err := util.Transform(o.installInfo.Ctx, manifest, o.installInfo.BaseResource, o.resourceTransforms)
+	if err != nil {
+		return false, err
+	}
+	defaultNamespaces(o.client.RESTMapper(), objects.Items, o.targetNamespace())
+	consumers, err := util.SecretConsumers(objects.Items, o.installInfo.SecretRotation.Order)
+	if err != nil {
+		return false, err
+	}
+	for _, consumer := range consumers {
+		applied := &unstructured.Unstructured{}
+		applied.SetGroupVersionKind(consumer.Workload.GroupVersionKind())
+		if err := o.client.Get(o.installInfo.Ctx, client.ObjectKeyFromObject(consumer.Workload), applied); err != nil {
+			return false, fmt.Errorf("reading applied %s %s: %w", applied.GetKind(), consumer.Workload.GetName(), err)
+		}
+		if applied.GetAnnotations()[labels.SecretChecksumAnnotation] == consumer.Checksum {
+			// the next consumer is only restarted once this consumer is rolled out with the current Secrets
+			if rolledOut, err := util.RolledOut(applied); err != nil || !rolledOut {
+				return false, err
+			}
+			continue
+		}
+		patch := client.MergeFrom(applied.DeepCopy())
+		restarted, err := util.RecordSecretChecksum(applied, consumer.Checksum, time.Now())
+		if err != nil {
+			return false, err
+		}
+		if err := o.client.Patch(o.installInfo.Ctx, applied, patch); err != nil {
+			return false, fmt.Errorf("recording secret checksum of %s %s: %w", applied.GetKind(), applied.GetName(), err)
+		}
+		if restarted {
+			o.logger.Info("restarted consumer of rotated secrets", "kind", applied.GetKind(), "name", applied.GetName(),
+				"resource", client.ObjectKeyFromObject(o.installInfo.BaseResource).String())
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// listForVerification lists the passed list from the target cluster. If it cannot be listed,
+// e.g. due to missing permissions, the list stays empty, so that its verification is skipped.
+func (o *Operations) listForVerification(list client.ObjectList, description string) error {
+	if err := o.client.List(o.installInfo.Ctx, list); err != nil {
+		if ctxErr := o.installInfo.Ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		o.logger.V(util.DebugLogLevel).Info("skipping capacity verification against "+description+
+			", they cannot be listed",
+			"resource", client.ObjectKeyFromObject(o.installInfo.BaseResource).String(), "error", err.Error())
+	}
+	return nil
+}
+
+// appliedWorkloads returns the currently applied versions of the passed objects, which exist in the target cluster.
+func (o *Operations) appliedWorkloads(objects []*unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	return o.appliedObjects(objects, util.IsWorkload)
+}
+
+// appliedObjects returns the currently applied versions of the passed objects matching the filter,
+// which exist in the target cluster.
+func (o *Operations) appliedObjects(objects []*unstructured.Unstructured,
+	filter func(*unstructured.Unstructured) bool,
+) ([]*unstructured.Unstructured, error) {
+	var applied []*unstructured.Unstructured
+	for _, obj := range objects {
+		if !filter(obj) {
+			continue
+		}
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := o.client.Get(o.installInfo.Ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+			if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, fmt.Errorf("reading applied %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		applied = append(applied, current)
+	}
+	return applied, nil
+}
+
+// migrate applies the pending migrations of the install to the previously applied resources of the passed manifest
+// and reports the versions of the applied migrations, also if a later migration failed.
+func (o *Operations) migrate(manifest string) error {
+	if len(o.installInfo.Migrations) == 0 {
+		return nil
+	}
+	objects, err := util.Transform(o.installInfo.Ctx, manifest, o.installInfo.BaseResource, o.resourceTransforms)
+	if err != nil {
+		return err
+	}
+	defaultNamespaces(o.client.RESTMapper(), objects.Items, o.targetNamespace())
+	applied, err := ApplyMigrations(o.installInfo.Ctx, o.client, objects.Items, o.installInfo.Migrations)
+	if len(applied) > 0 {
+		o.logger.Info("migrated previously applied resources",
+			"resource", client.ObjectKeyFromObject(o.installInfo.BaseResource).String(), "migrations", applied)
+	}
+	if o.reportMigrations != nil {
+		o.reportMigrations(applied)
+	}
+	return err
+}
+
+// reportAppliedResources reports the distinct resource types of the passed manifest.
+// Kinds unknown to the target cluster are not reported.
+func (o *Operations) reportAppliedResources(manifest string) error {
+	if o.reportResources == nil {
+		return nil
+	}
+	objects, err := util.Transform(o.installInfo.Ctx, manifest, o.installInfo.BaseResource, o.resourceTransforms)
+	if err != nil {
+		return err
+	}
+	reported := make(map[schema.GroupVersionResource]bool, len(objects.Items))
+	resources := make([]schema.GroupVersionResource, 0, len(objects.Items))
+	for _, obj := range objects.Items {
+		gvk := obj.GroupVersionKind()
+		mapping, err := o.client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if !reported[mapping.Resource] {
+			reported[mapping.Resource] = true
+			resources = append(resources, mapping.Resource)
+		}
+	}
+	o.reportResources(resources)
+	return nil
+}
+
+// handlePartialInstall returns the failed attempt of the install, whose leftovers are deleted according to
+// types.InstallInfo.PartialInstallPolicy before it is retried. Attempts are only recorded if the inventory is tracked.
+func (o *Operations) handlePartialInstall(manifest string) (*InstallAttempt, error) {
+	if !o.installInfo.TrackInventory {
+		return nil, nil
+	}
+	attempt, err := o.installAttempts().Load(o.installInfo.Ctx)
+	if err != nil || attempt == nil {
+		return nil, err
+	}
+	entries, err := o.inventoryEntries(manifest)
+	if err != nil {
+		return nil, err
+	}
+	inventory, err := NewInventory(o.client, o.installInfo.BaseResource, o.installInfo.ReleaseName).
+		Load(o.installInfo.Ctx)
+	if err != nil && !errors.Is(err, ErrInventoryNotFound) {
+		return nil, err
+	}
+
+	leftovers := attempt.Leftovers(o.installInfo.PartialInstallPolicy, inventory, entries)
+	// skipped resources are not applied anymore, but they are not deleted either
+	kept := leftovers[:0]
+	for _, leftover := range leftovers {
+		if !o.skipped(leftover) {
+			kept = append(kept, leftover)
+		}
+	}
+	leftovers = kept
+	o.logger.Info("retrying failed install",
+		"resource", client.ObjectKeyFromObject(o.installInfo.BaseResource).String(),
+		"policy", o.installInfo.PartialInstallPolicy, "failures", attempt.Failures,
+		"firstError", attempt.FirstError, "leftovers", len(leftovers))
+	if len(leftovers) == 0 {
+		return attempt, nil
+	}
+	if err := deleteEntries(o.installInfo.Ctx, o.client, leftovers); err != nil {
+		return nil, fmt.Errorf("deleting leftovers of failed install: %w", err)
+	}
+	attempt.Entries = StaleInventoryEntries(attempt.Entries, leftovers)
+	return attempt, nil
+}
+
+// failAttempt records the failure of the attempt applying the passed manifest and returns the failure.
+// If the install failed differently before, the first failure is added, so that it is not masked by retries.
+func (o *Operations) failAttempt(previous *InstallAttempt, manifest string, err error) error {
+	if !o.installInfo.TrackInventory {
+		return err
+	}
+	entries, entriesErr := o.inventoryEntries(manifest)
+	if entriesErr == nil {
+		entriesErr = o.installAttempts().Fail(o.installInfo.Ctx, previous, entries, err)
+	}
+	if entriesErr != nil {
+		o.logger.Error(entriesErr, "unable to record failed install attempt",
+			"resource", client.ObjectKeyFromObject(o.installInfo.BaseResource).String())
+	}
+	if previous != nil && previous.FirstError != err.Error() {
+		return fmt.Errorf("%w (first failure of install: %s)", err, previous.FirstError)
+	}
+	return err
+}
+
+func (o *Operations) installAttempts() *InstallAttempts {
+	return NewInstallAttempts(o.client, o.installInfo.BaseResource, o.installInfo.ReleaseName)
+}
+
+// inventoryEntries returns the InventoryEntries of all resources of the passed manifest.
+func (o *Operations) inventoryEntries(manifest string) ([]InventoryEntry, error) {
+	objects, err := util.Transform(o.installInfo.Ctx, manifest, o.installInfo.BaseResource, o.resourceTransforms)
+	if err != nil {
+		return nil, err
+	}
+	defaultNamespaces(o.client.RESTMapper(), objects.Items, o.targetNamespace())
+	return InventoryEntriesFromObjects(objects.Items)
+}
+
+// syncInventory records the resources of the passed manifest in the Inventory of the install.
+// Resources recorded previously that are no longer part of the manifest are pruned.
+func (o *Operations) syncInventory(manifest string) error {
+	if !o.installInfo.TrackInventory {
+		return nil
+	}
+	entries, err := o.inventoryEntries(manifest)
+	if err != nil {
+		return err
+	}
+	return NewInventory(o.client, o.installInfo.BaseResource, o.installInfo.ReleaseName).
+		SyncOrphaning(o.installInfo.Ctx, entries, o.skipped)
+}
+
+// skipped indicates if the resource of the entry matches types.InstallInfo.SkipResources,
+// so that it is kept in the target cluster, if it was applied before it was skipped.
+func (o *Operations) skipped(entry InventoryEntry) bool {
+	return util.MatchesResourcePatterns(o.installInfo.SkipResources,
+		schema.GroupVersionKind{Group: entry.Group, Version: entry.Version, Kind: entry.Kind}, entry.Name)
+}
+
+// targetNamespace returns the namespace configured for namespaced resources without a namespace.
+func (o *Operations) targetNamespace() string {
+	if configuredNamespace, ok := o.installInfo.Flags.ConfigFlags["Namespace"].(string); ok &&
+		configuredNamespace != "" {
+		return configuredNamespace
+	}
+	return metav1.NamespaceDefault
+}
+
 func UninstallSuccess(err error) bool {
 	return err == nil || apierrors.IsNotFound(err) || meta.IsNoMatchError(err)
 }
 
+// getManifestForRelease returns the manifest of the install. If the release history is enabled, Helm charts are
+// rendered for the latest revision, so that an unchanged install matches it. A changed manifest is rendered again
+// for the next revision, as templates can depend on .Release.Revision, .Release.IsInstall or .Release.IsUpgrade.
+func (o *Operations) getManifestForRelease() *types.ParsedFile {
+	if o.installInfo.ReleaseHistoryLimit <= 0 {
+		return o.getManifestForChartPath(o.installInfo)
+	}
+	history, err := o.loadReleaseHistory()
+	if err != nil {
+		return types.NewParsedFile("", err)
+	}
+	latest := history.Latest()
+	if latest == nil {
+		o.installInfo.Release = &types.ReleaseState{Revision: 1}
+		return o.getManifestForChartPath(o.installInfo)
+	}
+
+	o.installInfo.Release = &types.ReleaseState{Revision: latest.Revision}
+	parsedFile := o.getManifestForChartPath(o.installInfo)
+	if parsedFile.GetRawError() != nil {
+		return parsedFile
+	}
+	hash, err := util.CalculateHash(parsedFile.GetContent())
+	if err != nil || hash == latest.Hash {
+		return types.NewParsedFile(parsedFile.GetContent(), err)
+	}
+	o.installInfo.Release = &types.ReleaseState{Revision: latest.Revision + 1}
+	if parsedFile := o.renderSrc.DeleteCachedResources(o.installInfo.ChartPath); parsedFile.GetRawError() != nil {
+		return parsedFile
+	}
+	return o.getManifestForChartPath(o.installInfo)
+}
+
 func (o *Operations) getManifestForChartPath(installInfo *types.InstallInfo) *types.ParsedFile {
 	// 1. check provided manifest file
 	// It is expected for installInfo.Path to contain ONE .yaml or .yml file,
@@ -364,7 +1454,9 @@ func (o *Operations) getManifestForChartPath(installInfo *types.InstallInfo) *ty
 	// 3. render new manifests
 	// Depending upon the chart the request will be sent to a processor,
 	// either Helm or Kustomize.
+	_, span := tracing.Start(installInfo.Ctx, tracing.SpanRender)
 	parsedFile = o.renderSrc.GetRawManifest(installInfo)
+	tracing.End(span, parsedFile.GetRawError())
 	// If there is any type of error return from here, as there is nothing to be cached.
 	if parsedFile.GetRawError() != nil {
 		// no manifest could be processed
@@ -379,6 +1471,7 @@ func (o *Operations) getManifestForChartPath(installInfo *types.InstallInfo) *ty
 	}
 	// Write Rendered manifest static chart to installInfo.Path.
 	// If the location doesn't exist or has permission issues, it will be ignored.
-	err := util.WriteToFile(util.GetFsManifestChartPath(installInfo.ChartPath), []byte(parsedFile.GetContent()))
+	err := util.WriteStreamToFile(util.GetFsManifestChartPath(installInfo.ChartPath),
+		strings.NewReader(parsedFile.GetContent()))
 	return types.NewParsedFile(parsedFile.GetContent(), err).FilterOsErrors()
 }
@@ -1,13 +1,24 @@
 package manifest_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
+	"github.com/go-logr/logr"
 	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func Test_UninstallSuccess(t *testing.T) {
@@ -59,3 +70,49 @@ func Test_UninstallSuccess(t *testing.T) {
 		})
 	}
 }
+
+func Test_UninstallRemovedRelease(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	owner := configMapObject("owner")
+	clnt := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	for _, release := range []string{"kept", "removed"} {
+		resource := &v1.ConfigMap{}
+		resource.SetName(release)
+		resource.SetNamespace("default")
+		require.NoError(t, clnt.Create(ctx, resource))
+		entries, err := manifest.InventoryEntriesFromObjects([]*unstructured.Unstructured{configMapObject(release)})
+		require.NoError(t, err)
+		require.NoError(t, manifest.NewInventory(clnt, owner, release).Store(ctx, entries))
+	}
+	uninstall := func(release string, trackInventory bool) error {
+		return manifest.UninstallRemovedRelease(manifest.OperationOptions{Logger: logr.Discard(),
+			InstallInfo: &types.InstallInfo{
+				Ctx:            ctx,
+				ChartInfo:      &types.ChartInfo{ChartPath: "../test_samples/helm", ChartName: "kept"},
+				ClusterInfo:    &types.ClusterInfo{Client: clnt, Config: &rest.Config{}},
+				ResourceInfo:   &types.ResourceInfo{BaseResource: owner},
+				TrackInventory: trackInventory,
+			},
+		}, release)
+	}
+	exists := func(name string) bool {
+		err := clnt.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, &v1.ConfigMap{})
+		require.True(t, err == nil || apierrors.IsNotFound(err))
+		return err == nil
+	}
+
+	assert.ErrorIs(t, uninstall("removed", false), manifest.ErrRemovedInstallUntracked,
+		"without inventory tracking, the resources of removed installs are unknown")
+	assert.ErrorIs(t, uninstall("untracked", true), manifest.ErrRemovedInstallUntracked,
+		"removed installs without inventory are not uninstalled")
+	assert.True(t, exists("removed"))
+
+	require.NoError(t, uninstall("removed", true))
+	assert.False(t, exists("removed"), "resources of the removed install are deleted")
+	_, err := manifest.NewInventory(clnt, owner, "removed").Load(ctx)
+	assert.ErrorIs(t, err, manifest.ErrInventoryNotFound)
+	assert.True(t, exists("kept"), "resources of other installs stay untouched")
+	_, err = manifest.NewInventory(clnt, owner, "kept").Load(ctx)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,247 @@
+package manifest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+const (
+	releaseHistoryPrefix      = "release"
+	releaseHistoryRecordsKey  = "history"
+	releaseHistoryManifestKey = "manifest."
+	// releaseHistoryKeyAnnotation records the key encrypting the manifests of the release history.
+	releaseHistoryKeyAnnotation = labels.OperatorPrefix + labels.Separator + "encryption-key"
+)
+
+// ReleaseRecord describes a single revision of a release.
+type ReleaseRecord struct {
+	Revision    int                 `json:"revision"`
+	Status      types.ReleaseStatus `json:"status"`
+	Hash        uint32              `json:"hash"`
+	Created     metav1.Time         `json:"created"`
+	Description string              `json:"description,omitempty"`
+	// RolledBackFrom is the failed revision, if this revision is the result of a rollback
+	RolledBackFrom int `json:"rolledBackFrom,omitempty"`
+}
+
+// ReleaseHistory records the revisions of a release together with their rendered manifests,
+// so that a release can be rolled back to a previous revision without re-rendering it.
+// It is stored in the types.ReleaseStorage of the install, by default as a Secret in InventoryNamespace,
+// as rendered manifests can contain sensitive values.
+// If a types.KeyEncrypter is passed, manifests are additionally envelope encrypted.
+// As the history is re-encrypted with the current key on every Save, keys are rotated with the next change.
+type ReleaseHistory struct {
+	Records []ReleaseRecord
+
+	clnt      client.Client
+	key       client.ObjectKey
+	driver    types.ReleaseStorageDriver
+	limit     int
+	encrypter types.KeyEncrypter
+	manifests map[int][]byte
+}
+
+// LoadReleaseHistory returns the ReleaseHistory of the given release, owned by the passed base resource,
+// from the passed storage, nil loads it from a Secret in InventoryNamespace.
+// At most limit revisions are kept. If no history exists yet, an empty ReleaseHistory is returned.
+// The encrypter is optional, manifests stored without encryption can be loaded with an encrypter.
+func LoadReleaseHistory(ctx context.Context, clnt client.Client, owner client.Object, releaseName string,
+	storage *types.ReleaseStorage, limit int, encrypter types.KeyEncrypter,
+) (*ReleaseHistory, error) {
+	name := util.NormalizeSubdomain(strings.Join(
+		[]string{releaseHistoryPrefix, owner.GetNamespace(), owner.GetName(), releaseName}, "."),
+	)
+	history := &ReleaseHistory{
+		clnt:      clnt,
+		key:       client.ObjectKey{Namespace: ReleaseStorageNamespace(storage), Name: name},
+		driver:    types.ReleaseStorageSecret,
+		limit:     limit,
+		encrypter: encrypter,
+		manifests: make(map[int][]byte),
+	}
+	if storage != nil && storage.Driver != "" {
+		history.driver = storage.Driver
+	}
+
+	data, err := history.load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading release history %s: %w", history.key, err)
+	} else if data == nil {
+		return history, nil
+	}
+	if err := json.Unmarshal(data[releaseHistoryRecordsKey], &history.Records); err != nil {
+		return nil, fmt.Errorf("decoding release history %s: %w", history.key, err)
+	}
+	for _, record := range history.Records {
+		manifest, err := util.OpenEnvelope(ctx, encrypter,
+			data[releaseHistoryManifestKey+strconv.Itoa(record.Revision)])
+		if err != nil {
+			return nil, fmt.Errorf("decrypting revision %d of release history %s: %w", record.Revision, history.key, err)
+		}
+		history.manifests[record.Revision] = manifest
+	}
+	return history, nil
+}
+
+// Latest returns the latest revision or nil if no revision was recorded.
+func (h *ReleaseHistory) Latest() *ReleaseRecord {
+	if len(h.Records) == 0 {
+		return nil
+	}
+	return &h.Records[len(h.Records)-1]
+}
+
+// LastDeployed returns the latest revision in types.ReleaseStatusDeployed or nil if there is none.
+func (h *ReleaseHistory) LastDeployed() *ReleaseRecord {
+	for i := len(h.Records) - 1; i >= 0; i-- {
+		if h.Records[i].Status == types.ReleaseStatusDeployed {
+			return &h.Records[i]
+		}
+	}
+	return nil
+}
+
+// Failed returns the latest failed revision with the passed manifest hash or nil if there is none.
+func (h *ReleaseHistory) Failed(hash uint32) *ReleaseRecord {
+	for i := len(h.Records) - 1; i >= 0; i-- {
+		if h.Records[i].Hash == hash && h.Records[i].Status == types.ReleaseStatusFailed {
+			return &h.Records[i]
+		}
+	}
+	return nil
+}
+
+// Revision returns the passed revision or nil if it is not (or no longer) recorded.
+func (h *ReleaseHistory) Revision(revision int) *ReleaseRecord {
+	for i := range h.Records {
+		if h.Records[i].Revision == revision {
+			return &h.Records[i]
+		}
+	}
+	return nil
+}
+
+// Add records a new revision for the passed manifest. Revisions exceeding the limit are dropped, oldest first.
+func (h *ReleaseHistory) Add(manifest string, hash uint32, status types.ReleaseStatus, description string,
+) (*ReleaseRecord, error) {
+	compressed, err := compressManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	revision := 1
+	if latest := h.Latest(); latest != nil {
+		revision = latest.Revision + 1
+	}
+	h.Records = append(h.Records, ReleaseRecord{
+		Revision:    revision,
+		Status:      status,
+		Hash:        hash,
+		Created:     metav1.Now(),
+		Description: description,
+	})
+	h.manifests[revision] = compressed
+	if h.limit > 0 && len(h.Records) > h.limit {
+		for _, dropped := range h.Records[:len(h.Records)-h.limit] {
+			delete(h.manifests, dropped.Revision)
+		}
+		h.Records = h.Records[len(h.Records)-h.limit:]
+	}
+	return h.Latest(), nil
+}
+
+// MarkDeployed sets the passed revision to types.ReleaseStatusDeployed
+// and supersedes all previously deployed revisions.
+func (h *ReleaseHistory) MarkDeployed(revision int) {
+	for i := range h.Records {
+		switch {
+		case h.Records[i].Revision == revision:
+			h.Records[i].Status = types.ReleaseStatusDeployed
+		case h.Records[i].Status == types.ReleaseStatusDeployed:
+			h.Records[i].Status = types.ReleaseStatusSuperseded
+		}
+	}
+}
+
+// Manifest returns the rendered manifest recorded for the passed revision.
+func (h *ReleaseHistory) Manifest(revision int) (string, error) {
+	compressed, found := h.manifests[revision]
+	if !found || len(compressed) == 0 {
+		return "", fmt.Errorf("revision %d not found in release history %s", revision, h.key)
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	manifest, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	return string(manifest), nil
+}
+
+// Save persists the ReleaseHistory.
+func (h *ReleaseHistory) Save(ctx context.Context) error {
+	records, err := json.Marshal(h.Records)
+	if err != nil {
+		return err
+	}
+	data := map[string][]byte{releaseHistoryRecordsKey: records}
+	for revision, manifest := range h.manifests {
+		if h.encrypter != nil {
+			if manifest, err = util.SealEnvelope(ctx, h.encrypter, manifest); err != nil {
+				return fmt.Errorf("encrypting revision %d of release history %s: %w", revision, h.key, err)
+			}
+		}
+		data[releaseHistoryManifestKey+strconv.Itoa(revision)] = manifest
+	}
+
+	var annotations map[string]string
+	if h.encrypter != nil {
+		annotations = map[string]string{releaseHistoryKeyAnnotation: h.encrypter.KeyID()}
+	}
+	if err := h.store(ctx, data, annotations); err != nil {
+		return fmt.Errorf("storing release history %s: %w", h.key, err)
+	}
+	return nil
+}
+
+// Purge deletes the ReleaseHistory.
+func (h *ReleaseHistory) Purge(ctx context.Context) error {
+	return h.remove(ctx)
+}
+
+// ToRevision converts the record into the types.ReleaseRevision reported in the status of the release.
+func (r *ReleaseRecord) ToRevision(releaseName string) types.ReleaseRevision {
+	return types.ReleaseRevision{
+		Name:           releaseName,
+		Revision:       r.Revision,
+		Status:         r.Status,
+		RolledBackFrom: r.RolledBackFrom,
+	}
+}
+
+func compressManifest(manifest string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	writer := gzip.NewWriter(buf)
+	if _, err := writer.Write([]byte(manifest)); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
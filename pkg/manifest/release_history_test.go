@@ -0,0 +1,97 @@
+package manifest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+func Test_ReleaseHistory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	clnt := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	owner := configMapObject("owner")
+
+	history, err := manifest.LoadReleaseHistory(ctx, clnt, owner, "release", nil, 2, nil)
+	require.NoError(t, err)
+	assert.Nil(t, history.Latest())
+
+	for i, content := range []string{"first", "second", "third"} {
+		record, err := history.Add(content, uint32(i), types.ReleaseStatusPending, "upgrade")
+		require.NoError(t, err)
+		history.MarkDeployed(record.Revision)
+	}
+	require.NoError(t, history.Save(ctx))
+
+	history, err = manifest.LoadReleaseHistory(ctx, clnt, owner, "release", nil, 2, nil)
+	require.NoError(t, err)
+	require.Len(t, history.Records, 2)
+	assert.Nil(t, history.Revision(1), "revisions exceeding the limit are dropped")
+	assert.Equal(t, types.ReleaseStatusSuperseded, history.Revision(2).Status)
+	assert.Equal(t, 3, history.LastDeployed().Revision)
+
+	content, err := history.Manifest(2)
+	require.NoError(t, err)
+	assert.Equal(t, "second", content)
+	_, err = history.Manifest(1)
+	assert.Error(t, err)
+
+	history.Latest().Status = types.ReleaseStatusFailed
+	assert.Equal(t, 3, history.Failed(2).Revision)
+	assert.Nil(t, history.Failed(1))
+
+	require.NoError(t, history.Purge(ctx))
+	history, err = manifest.LoadReleaseHistory(ctx, clnt, owner, "release", nil, 2, nil)
+	require.NoError(t, err)
+	assert.Empty(t, history.Records)
+}
+
+func Test_ReleaseHistoryStorage(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	owner := configMapObject("storage-owner")
+	storages := []*types.ReleaseStorage{
+		{Namespace: "kyma-system", Driver: types.ReleaseStorageConfigMap},
+		{Driver: types.ReleaseStorageMemory},
+	}
+	for _, storage := range storages {
+		clnt := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+		history, err := manifest.LoadReleaseHistory(ctx, clnt, owner, "release", storage, 2, nil)
+		require.NoError(t, err)
+		_, err = history.Add("first", 1, types.ReleaseStatusDeployed, "install")
+		require.NoError(t, err)
+		require.NoError(t, history.Save(ctx))
+
+		history, err = manifest.LoadReleaseHistory(ctx, clnt, owner, "release", storage, 2, nil)
+		require.NoError(t, err)
+		require.NotNil(t, history.LastDeployed(), string(storage.Driver))
+		content, err := history.Manifest(history.LastDeployed().Revision)
+		require.NoError(t, err)
+		assert.Equal(t, "first", content)
+
+		configMaps := &v1.ConfigMapList{}
+		require.NoError(t, clnt.List(ctx, configMaps))
+		secrets := &v1.SecretList{}
+		require.NoError(t, clnt.List(ctx, secrets))
+		assert.Empty(t, secrets.Items, "the history is not stored as a Secret with the %s driver", storage.Driver)
+		if storage.Driver == types.ReleaseStorageConfigMap {
+			require.Len(t, configMaps.Items, 1)
+			assert.Equal(t, "kyma-system", configMaps.Items[0].Namespace)
+		} else {
+			assert.Empty(t, configMaps.Items)
+		}
+
+		require.NoError(t, history.Purge(ctx))
+		history, err = manifest.LoadReleaseHistory(ctx, clnt, owner, "release", storage, 2, nil)
+		require.NoError(t, err)
+		assert.Empty(t, history.Records)
+	}
+}
@@ -0,0 +1,130 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+const serviceAccountUsernameFormat = "system:serviceaccount:%s:%s"
+
+// memoryReleaseStorage holds the release histories stored with types.ReleaseStorageMemory.
+//
+//nolint:gochecknoglobals
+var memoryReleaseStorage = struct {
+	sync.Mutex
+	histories map[client.ObjectKey]map[string][]byte
+}{histories: map[client.ObjectKey]map[string][]byte{}}
+
+// ReleaseStorageNamespace returns the namespace the release history is stored in, InventoryNamespace by default.
+func ReleaseStorageNamespace(storage *types.ReleaseStorage) string {
+	if storage == nil || storage.Namespace == "" {
+		return InventoryNamespace
+	}
+	return storage.Namespace
+}
+
+// ReleaseStorageClient returns a client impersonating the ServiceAccount of the release storage,
+// or the passed client, if no ServiceAccount is configured. The impersonating client shares the scheme
+// and the RESTMapper of the passed client.
+func ReleaseStorageClient(clnt client.Client, config *rest.Config, storage *types.ReleaseStorage,
+) (client.Client, error) {
+	if storage == nil || storage.ServiceAccountName == "" {
+		return clnt, nil
+	}
+	impersonated := rest.CopyConfig(config)
+	impersonated.Impersonate = rest.ImpersonationConfig{
+		UserName: fmt.Sprintf(serviceAccountUsernameFormat, ReleaseStorageNamespace(storage), storage.ServiceAccountName),
+	}
+	storageClient, err := client.New(impersonated, client.Options{Scheme: clnt.Scheme(), Mapper: clnt.RESTMapper()})
+	if err != nil {
+		return nil, fmt.Errorf("creating client for release storage of ServiceAccount %s: %w",
+			storage.ServiceAccountName, err)
+	}
+	return storageClient, nil
+}
+
+// load returns the stored data of the ReleaseHistory, or nil if it was not stored yet.
+func (h *ReleaseHistory) load(ctx context.Context) (map[string][]byte, error) {
+	switch h.driver {
+	case types.ReleaseStorageMemory:
+		memoryReleaseStorage.Lock()
+		defer memoryReleaseStorage.Unlock()
+		return memoryReleaseStorage.histories[h.key], nil
+	case types.ReleaseStorageConfigMap:
+		configMap := &v1.ConfigMap{}
+		if err := h.clnt.Get(ctx, h.key, configMap); apierrors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		return configMap.BinaryData, nil
+	default:
+		secret := &v1.Secret{}
+		if err := h.clnt.Get(ctx, h.key, secret); apierrors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		return secret.Data, nil
+	}
+}
+
+func (h *ReleaseHistory) store(ctx context.Context, data map[string][]byte, annotations map[string]string) error {
+	switch h.driver {
+	case types.ReleaseStorageMemory:
+		memoryReleaseStorage.Lock()
+		defer memoryReleaseStorage.Unlock()
+		memoryReleaseStorage.histories[h.key] = data
+		return nil
+	case types.ReleaseStorageConfigMap:
+		configMap := &v1.ConfigMap{}
+		configMap.SetName(h.key.Name)
+		configMap.SetNamespace(h.key.Namespace)
+		_, err := controllerutil.CreateOrUpdate(ctx, h.clnt, configMap, func() error {
+			configMap.SetLabels(map[string]string{labels.ManagedBy: labels.OperatorName})
+			configMap.SetAnnotations(annotations)
+			configMap.BinaryData = data
+			return nil
+		})
+		return err
+	default:
+		secret := &v1.Secret{}
+		secret.SetName(h.key.Name)
+		secret.SetNamespace(h.key.Namespace)
+		_, err := controllerutil.CreateOrUpdate(ctx, h.clnt, secret, func() error {
+			secret.SetLabels(map[string]string{labels.ManagedBy: labels.OperatorName})
+			secret.SetAnnotations(annotations)
+			secret.Data = data
+			return nil
+		})
+		return err
+	}
+}
+
+func (h *ReleaseHistory) remove(ctx context.Context) error {
+	var obj client.Object
+	switch h.driver {
+	case types.ReleaseStorageMemory:
+		memoryReleaseStorage.Lock()
+		defer memoryReleaseStorage.Unlock()
+		delete(memoryReleaseStorage.histories, h.key)
+		return nil
+	case types.ReleaseStorageConfigMap:
+		obj = &v1.ConfigMap{}
+	default:
+		obj = &v1.Secret{}
+	}
+	obj.SetName(h.key.Name)
+	obj.SetNamespace(h.key.Namespace)
+	return client.IgnoreNotFound(h.clnt.Delete(ctx, obj))
+}
@@ -1,31 +1,52 @@
 package manifest
 
 import (
+	"strings"
 	"sync"
 
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kyma-project/module-manager/pkg/types"
 )
 
+// releaseKeySeparator separates the release from the name of a cache key, it is not allowed in object names.
+const releaseKeySeparator = "/"
+
 // RendererCacheImpl provides an in-memory processor to store types.ManifestClient instances applicable
 // to remote clusters. By using sync.Map for caching,
 // concurrent operations to the processor from diverse reconciliations are considered safe.
 //
-// Inside the processor cluster specific unique name (in Client.ObjectKey format)
-// is used as key and the types.ManifestClient instance is stored as the value.
+// Inside the processor cluster specific unique name (in Client.ObjectKey format) combined with the release
+// by ReleaseCacheKey is used as key and the types.ManifestClient instance is stored as the value.
+// As processors of all releases of a cluster share a rate limiter, their combined requests
+// are limited to the QPS and burst configured for the cluster.
 // Inside the config resource level configuration is stored by its key.
 type RendererCacheImpl struct {
-	processor sync.Map // Cluster specific
-	config    sync.Map // Resource specific
+	processor    sync.Map // Cluster and release specific
+	rateLimiters sync.Map // Cluster specific
+	config       sync.Map // Resource and release specific
 }
 
 // NewRendererCache returns a new instance of RemoteClusterCache.
 func NewRendererCache() *RendererCacheImpl {
 	return &RendererCacheImpl{
-		processor: sync.Map{},
-		config:    sync.Map{},
+		processor:    sync.Map{},
+		rateLimiters: sync.Map{},
+		config:       sync.Map{},
+	}
+}
+
+// ReleaseCacheKey returns the key of the passed release for the cluster or resource of the passed key.
+// Processors are cached per release, as their Helm action clients are configured for a single release,
+// so that installs of different releases to the same cluster can run concurrently.
+func ReleaseCacheKey(key client.ObjectKey, releaseName string) client.ObjectKey {
+	if releaseName == "" {
+		return key
 	}
+	key.Name = key.Name + releaseKeySeparator + releaseName
+	return key
 }
 
 // GetProcessor loads the types.ManifestClient from RendererCacheImpl for the passed client.ObjectKey.
@@ -37,14 +58,47 @@ func (r *RendererCacheImpl) GetProcessor(key client.ObjectKey) types.ManifestCli
 	return value.(types.ManifestClient)
 }
 
+// GetClusterProcessor loads any types.ManifestClient from RendererCacheImpl cached for the cluster
+// of the passed client.ObjectKey, regardless of its release.
+func (r *RendererCacheImpl) GetClusterProcessor(key client.ObjectKey) types.ManifestClient {
+	var found types.ManifestClient
+	r.processor.Range(func(cached, value any) bool {
+		if !matchesReleaseOf(cached.(client.ObjectKey), key) {
+			return true
+		}
+		found = value.(types.ManifestClient)
+		return false
+	})
+	return found
+}
+
 // SetProcessor saves the passed types.ManifestClient into RendererCacheImpl for the client.ObjectKey.
 func (r *RendererCacheImpl) SetProcessor(key client.ObjectKey, helmClient types.ManifestClient) {
 	r.processor.Store(key, helmClient)
 }
 
-// DeleteProcessor deletes the types.ManifestClient from RendererCacheImpl for the passed client.ObjectKey.
+// DeleteProcessor deletes the types.ManifestClient of all releases and the rate limiter
+// from RendererCacheImpl for the cluster of the passed client.ObjectKey, e.g. once the cluster is removed.
 func (r *RendererCacheImpl) DeleteProcessor(key client.ObjectKey) {
-	r.processor.Delete(key)
+	deleteReleasesOf(&r.processor, key)
+	r.rateLimiters.Delete(key)
+}
+
+// GetRateLimiter loads the rate limiter shared by all processors of the cluster of the passed client.ObjectKey.
+// If not cached yet, it is created with the QPS and burst of the passed config or the client-go defaults.
+func (r *RendererCacheImpl) GetRateLimiter(key client.ObjectKey, config *rest.Config) flowcontrol.RateLimiter {
+	if value, ok := r.rateLimiters.Load(key); ok {
+		return value.(flowcontrol.RateLimiter)
+	}
+	qps, burst := config.QPS, config.Burst
+	if qps <= 0 {
+		qps = rest.DefaultQPS
+	}
+	if burst <= 0 {
+		burst = rest.DefaultBurst
+	}
+	value, _ := r.rateLimiters.LoadOrStore(key, flowcontrol.NewTokenBucketRateLimiter(qps, burst))
+	return value.(flowcontrol.RateLimiter)
 }
 
 // GetConfig loads the configuration from RendererCacheImpl for the passed client.ObjectKey.
@@ -61,7 +115,22 @@ func (r *RendererCacheImpl) SetConfig(key client.ObjectKey, config uint32) {
 	r.config.Store(key, config)
 }
 
-// DeleteConfig deletes the configuration from RendererCacheImpl for the passed client.ObjectKey.
+// DeleteConfig deletes the configuration of all releases from RendererCacheImpl for the passed client.ObjectKey.
 func (r *RendererCacheImpl) DeleteConfig(key client.ObjectKey) {
-	r.config.Delete(key)
+	deleteReleasesOf(&r.config, key)
+}
+
+// matchesReleaseOf indicates if the cached key is the passed key or a ReleaseCacheKey of it.
+func matchesReleaseOf(cached, key client.ObjectKey) bool {
+	return cached.Namespace == key.Namespace &&
+		(cached.Name == key.Name || strings.HasPrefix(cached.Name, key.Name+releaseKeySeparator))
+}
+
+func deleteReleasesOf(entries *sync.Map, key client.ObjectKey) {
+	entries.Range(func(cached, _ any) bool {
+		if matchesReleaseOf(cached.(client.ObjectKey), key) {
+			entries.Delete(cached)
+		}
+		return true
+	})
 }
@@ -8,6 +8,7 @@ import (
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kyma-project/module-manager/pkg/labels"
@@ -117,6 +118,11 @@ func verifyNilCacheEntries(resourceName string, parentKeyName string, _ uint32)
 		Should(BeZero())
 }
 
+// mockProcessor stands in for cached processors, which are not called.
+type mockProcessor struct {
+	types.ManifestClient
+}
+
 type mockCache struct {
 	processor sync.Map
 	config    sync.Map
@@ -130,6 +136,14 @@ func (m *mockCache) GetProcessor(key client.ObjectKey) types.ManifestClient {
 	return value.(types.ManifestClient)
 }
 
+func (m *mockCache) GetClusterProcessor(key client.ObjectKey) types.ManifestClient {
+	return m.GetProcessor(key)
+}
+
+func (m *mockCache) GetRateLimiter(_ client.ObjectKey, config *rest.Config) flowcontrol.RateLimiter {
+	return flowcontrol.NewTokenBucketRateLimiter(config.QPS, config.Burst)
+}
+
 func (m *mockCache) SetProcessor(key client.ObjectKey, renderSrc types.ManifestClient) {
 	m.processor.Store(key, renderSrc)
 	setProcessorCount++
@@ -236,3 +250,34 @@ var _ = Describe("given manifest with a helm repo", Ordered, func() {
 			Entry("when local helm chart is provided", localHelm()),
 		})
 })
+
+var _ = Describe("given renderer cache with processors of several releases", func() {
+	clusterKey := client.ObjectKey{Name: parentCacheKey, Namespace: testNs}
+	otherClusterKey := client.ObjectKey{Name: parentCacheKey2, Namespace: testNs}
+
+	It("should cache processors per release and remove all of them with their cluster", func() {
+		cache := manifest.NewRendererCache()
+
+		releaseKey := manifest.ReleaseCacheKey(clusterKey, "release")
+		Expect(releaseKey).To(Equal(client.ObjectKey{Name: parentCacheKey + "/release", Namespace: testNs}))
+		Expect(manifest.ReleaseCacheKey(clusterKey, "")).To(Equal(clusterKey))
+
+		renderSrc := &mockProcessor{}
+		cache.SetProcessor(releaseKey, renderSrc)
+		cache.SetProcessor(manifest.ReleaseCacheKey(otherClusterKey, "release"), &mockProcessor{})
+		cache.SetConfig(manifest.ReleaseCacheKey(clusterKey, "release"), 1)
+		Expect(cache.GetProcessor(clusterKey)).To(BeNil())
+		Expect(cache.GetClusterProcessor(clusterKey)).To(BeIdenticalTo(renderSrc))
+
+		limiter := cache.GetRateLimiter(clusterKey, &rest.Config{})
+		Expect(cache.GetRateLimiter(clusterKey, &rest.Config{QPS: 1, Burst: 1})).To(BeIdenticalTo(limiter))
+		Expect(limiter.QPS()).To(Equal(rest.DefaultQPS))
+
+		cache.DeleteProcessor(clusterKey)
+		cache.DeleteConfig(clusterKey)
+		Expect(cache.GetClusterProcessor(clusterKey)).To(BeNil())
+		Expect(cache.GetConfig(releaseKey)).To(BeZero())
+		Expect(cache.GetClusterProcessor(otherClusterKey)).ShouldNot(BeNil(), "other clusters are kept")
+		Expect(cache.GetRateLimiter(clusterKey, &rest.Config{QPS: 1, Burst: 1}).QPS()).To(Equal(float32(1)))
+	})
+})
@@ -0,0 +1,67 @@
+package manifest_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// blockingClient blocks all reads and applies until their context is done, as an unresponsive API server would.
+type blockingClient struct {
+	client.Client
+}
+
+func (c *blockingClient) Get(ctx context.Context, _ client.ObjectKey, _ client.Object,
+	_ ...client.GetOption,
+) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (c *blockingClient) Patch(ctx context.Context, _ client.Object, _ client.Patch,
+	_ ...client.PatchOption,
+) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func Test_OperationTimeout(t *testing.T) {
+	t.Parallel()
+	chartPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(chartPath, "manifest.yaml"), []byte(dryRunManifest), 0o600))
+	clnt := &blockingClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()}
+	dryRun := func(timeout time.Duration) error {
+		_, err := manifest.DryRunChart(manifest.OperationOptions{Logger: logr.Discard(),
+			InstallInfo: &types.InstallInfo{
+				Ctx:          context.Background(),
+				ChartInfo:    &types.ChartInfo{ChartPath: chartPath, ChartName: "release", ReleaseName: "release"},
+				ClusterInfo:  &types.ClusterInfo{Client: clnt, Config: &rest.Config{}},
+				ResourceInfo: &types.ResourceInfo{BaseResource: configMapObject("owner")},
+				Timeout:      timeout,
+			},
+		})
+		return err
+	}
+
+	err := dryRun(10 * time.Millisecond)
+	var timeoutErr *types.OperationTimeoutError
+	require.True(t, errors.As(err, &timeoutErr), "exceeded deadlines are reported as timeouts, got %v", err)
+	assert.Equal(t, 10*time.Millisecond, timeoutErr.Timeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, types.ErrorClassificationTransient, manifest.ClassifyError(err))
+	assert.True(t, manifest.IsRetryable(err), "timed out operations are retried")
+}
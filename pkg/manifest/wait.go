@@ -0,0 +1,81 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"helm.sh/helm/v3/pkg/kube"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// waitPollInterval is the interval in which resources are verified while waiting, equal to the one used by Helm.
+const waitPollInterval = 2 * time.Second
+
+// WaitForReady waits until all resources are ready or the timeout is reached.
+// Unlike kube.Client.Wait, it aborts as soon as the context is done, e.g. on operator shutdown.
+// A timeout of 0 waits until the context is done.
+func WaitForReady(ctx context.Context, resources kube.ResourceList, readyChecker kube.ReadyChecker,
+	timeout time.Duration,
+) error {
+	return waitFor(ctx, timeout, func(ctx context.Context) (bool, error) {
+		for _, info := range resources {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+			ready, err := readyChecker.IsReady(ctx, info)
+			if apierrors.IsServiceUnavailable(err) {
+				return false, nil
+			}
+			if err != nil || !ready {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+}
+
+// WaitForDeleted waits until all resources are deleted or the timeout is reached.
+// Unlike kube.Client.WaitForDelete, it aborts as soon as the context is done, e.g. on operator shutdown.
+// A timeout of 0 waits until the context is done.
+func WaitForDeleted(ctx context.Context, resources kube.ResourceList, timeout time.Duration) error {
+	return waitFor(ctx, timeout, func(ctx context.Context) (bool, error) {
+		for _, info := range resources {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+			err := info.Get()
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err == nil || apierrors.IsServiceUnavailable(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	})
+}
+
+func waitFor(ctx context.Context, timeout time.Duration, condition wait.ConditionWithContextFunc) error {
+	waitCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err := wait.PollImmediateUntilWithContext(waitCtx, waitPollInterval, condition)
+	if err == nil {
+		return nil
+	}
+	// the context of the caller takes precedence, so that its cancellation is not reported as a wait timeout
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("waiting for resources aborted: %w", ctxErr)
+	}
+	if waitCtx.Err() != nil {
+		return fmt.Errorf("waiting for resources exceeded %s: %w", timeout, wait.ErrWaitTimeout)
+	}
+	return err
+}
@@ -0,0 +1,114 @@
+package manifest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/kube"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/pkg/client/fake"
+	"github.com/kyma-project/module-manager/pkg/manifest"
+)
+
+// cancellationBound is the maximum duration a wait may take after its context is cancelled.
+const cancellationBound = time.Second
+
+// progressingCluster returns a fake cluster, in which the passed objects exist, but are not ready yet.
+func progressingCluster(t *testing.T, objects ...client.Object) (*fake.Cluster, kube.ResourceList) {
+	t.Helper()
+	cluster, err := fake.NewCluster()
+	require.NoError(t, err)
+	for _, obj := range objects {
+		require.NoError(t, cluster.SetState(obj, fake.StateProgressing))
+	}
+	resources, err := cluster.ResourceList(objects...)
+	require.NoError(t, err)
+	return cluster, resources
+}
+
+func pendingPodResources(t *testing.T) (kube.ResourceList, kube.ReadyChecker) {
+	t.Helper()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: metav1.NamespaceDefault}}
+	cluster, resources := progressingCluster(t, pod)
+	return resources, cluster.ReadyChecker()
+}
+
+func Test_WaitForReady(t *testing.T) {
+	t.Parallel()
+	labels := map[string]string{"app": "sample"}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "sample", Namespace: metav1.NamespaceDefault},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app:1.0.0"}}},
+			},
+		},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "sample", Namespace: metav1.NamespaceDefault}}
+	cluster, resources := progressingCluster(t, deployment, pod)
+
+	// the resources become ready while they are waited for
+	time.AfterFunc(100*time.Millisecond, func() {
+		assert.NoError(t, cluster.SetState(deployment, fake.StateReady))
+		assert.NoError(t, cluster.SetState(pod, fake.StateReady))
+	})
+	require.NoError(t, manifest.WaitForReady(context.Background(), resources, cluster.ReadyChecker(), time.Minute))
+	assert.NotEmpty(t, cluster.CallsWithVerb(fake.VerbGet), "readiness is checked in the cluster")
+	assert.Empty(t, cluster.CallsWithVerb(fake.VerbUpdate), "waiting does not change resources")
+}
+
+func Test_WaitForReady_AbortsOnCancellation(t *testing.T) {
+	t.Parallel()
+	resources, readyChecker := pendingPodResources(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := manifest.WaitForReady(ctx, resources, readyChecker, time.Hour)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 100*time.Millisecond+cancellationBound)
+}
+
+func Test_WaitForReady_AbortsOnDeadline(t *testing.T) {
+	t.Parallel()
+	resources, readyChecker := pendingPodResources(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := manifest.WaitForReady(ctx, resources, readyChecker, 0)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 100*time.Millisecond+cancellationBound)
+}
+
+func Test_WaitForReady_Timeout(t *testing.T) {
+	t.Parallel()
+	resources, readyChecker := pendingPodResources(t)
+
+	err := manifest.WaitForReady(context.Background(), resources, readyChecker, 100*time.Millisecond)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, context.DeadlineExceeded, "an exceeded wait timeout is not a cancellation")
+}
+
+func Test_WaitForDeleted_CancelledContext(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// the resource has no client, so it must not be requested once the context is cancelled
+	resources := kube.ResourceList{&resource.Info{Name: "deleted", Namespace: metav1.NamespaceDefault}}
+	start := time.Now()
+	err := manifest.WaitForDeleted(ctx, resources, time.Hour)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), cancellationBound)
+}
@@ -0,0 +1,101 @@
+package predicates
+
+import (
+	"reflect"
+
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const skipValue = "true"
+
+// Default returns the predicate reconcilers should be wired with to avoid event storms. Updates are only passed
+// if the generation, the finalizers, the passed annotations or the skip label changed, so that status updates
+// and changes of unrelated metadata do not trigger reconciliations. Events of objects skipped with the skip label
+// are dropped, see SkipLabeled.
+func Default(skipLabel string, annotations ...string) predicate.Predicate {
+	return predicate.And(
+		predicate.Or(
+			predicate.GenerationChangedPredicate{},
+			FinalizersChanged(),
+			AnnotationsChanged(annotations...),
+			LabelsChanged(skipLabel),
+		),
+		SkipLabeled(skipLabel),
+	)
+}
+
+// AnnotationsChanged passes updates changing any of the passed annotations.
+func AnnotationsChanged(annotations ...string) predicate.Predicate {
+	return updateFunc(func(oldObj, newObj client.Object) bool {
+		return valuesChanged(oldObj.GetAnnotations(), newObj.GetAnnotations(), annotations)
+	})
+}
+
+// LabelsChanged passes updates changing any of the passed labels.
+func LabelsChanged(labels ...string) predicate.Predicate {
+	return updateFunc(func(oldObj, newObj client.Object) bool {
+		return valuesChanged(oldObj.GetLabels(), newObj.GetLabels(), labels)
+	})
+}
+
+// FinalizersChanged passes updates adding or removing finalizers, which do not change the generation.
+func FinalizersChanged() predicate.Predicate {
+	return updateFunc(func(oldObj, newObj client.Object) bool {
+		return !reflect.DeepEqual(oldObj.GetFinalizers(), newObj.GetFinalizers())
+	})
+}
+
+// MatchingLabels passes events of objects matching the selector only, e.g. to shard objects across operators.
+// Updates are passed if either version matches, so that objects moved out of the shard are processed once more.
+func MatchingLabels(selector k8slabels.Selector) predicate.Predicate {
+	matches := func(obj client.Object) bool {
+		return selector.Matches(k8slabels.Set(obj.GetLabels()))
+	}
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return matches(e.Object) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return matches(e.Object) },
+		GenericFunc: func(e event.GenericEvent) bool { return matches(e.Object) },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return matches(e.ObjectOld) || matches(e.ObjectNew)
+		},
+	}
+}
+
+// SkipLabeled drops events of objects whose skip label is set to "true", e.g. while they are paused for
+// maintenance. Updates removing the label are passed, so that reconciliation resumes immediately.
+// Deletions are always passed.
+func SkipLabeled(skipLabel string) predicate.Predicate {
+	skipped := func(obj client.Object) bool {
+		return obj.GetLabels()[skipLabel] == skipValue
+	}
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return !skipped(e.Object) },
+		GenericFunc: func(e event.GenericEvent) bool { return !skipped(e.Object) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return !skipped(e.ObjectNew) },
+	}
+}
+
+func updateFunc(changed func(oldObj, newObj client.Object) bool) predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			return changed(e.ObjectOld, e.ObjectNew)
+		},
+	}
+}
+
+func valuesChanged(oldValues, newValues map[string]string, keys []string) bool {
+	for _, key := range keys {
+		oldValue, oldFound := oldValues[key]
+		newValue, newFound := newValues[key]
+		if oldFound != newFound || oldValue != newValue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package predicates_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/kyma-project/module-manager/pkg/predicates"
+)
+
+const (
+	skipLabel       = "skip-reconciliation"
+	watchAnnotation = "watched"
+)
+
+func configMap(generation int64, labels, annotations map[string]string) *corev1.ConfigMap {
+	obj := &corev1.ConfigMap{}
+	obj.SetName("test")
+	obj.SetGeneration(generation)
+	obj.SetLabels(labels)
+	obj.SetAnnotations(annotations)
+	return obj
+}
+
+func Test_Default(t *testing.T) {
+	t.Parallel()
+	defaultPredicate := predicates.Default(skipLabel, watchAnnotation)
+	update := func(oldObj, newObj *corev1.ConfigMap) bool {
+		return defaultPredicate.Update(event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj})
+	}
+
+	assert.True(t, update(configMap(1, nil, nil), configMap(2, nil, nil)), "generation changed")
+	assert.False(t, update(configMap(1, nil, nil), configMap(1, nil, map[string]string{"other": "x"})),
+		"unrelated annotation changed")
+	assert.True(t, update(configMap(1, nil, nil), configMap(1, nil, map[string]string{watchAnnotation: "x"})),
+		"watched annotation changed")
+
+	skipped := map[string]string{skipLabel: "true"}
+	assert.False(t, update(configMap(1, skipped, nil), configMap(2, skipped, nil)), "skipped object changed")
+	assert.True(t, update(configMap(1, skipped, nil), configMap(1, nil, nil)), "skip label removed")
+	assert.False(t, defaultPredicate.Create(event.CreateEvent{Object: configMap(1, skipped, nil)}))
+	assert.True(t, defaultPredicate.Delete(event.DeleteEvent{Object: configMap(1, skipped, nil)}))
+}
+
+func Test_MatchingLabels(t *testing.T) {
+	t.Parallel()
+	selector := k8slabels.SelectorFromSet(k8slabels.Set{"shard": "a"})
+	matching := predicates.MatchingLabels(selector)
+	shardA := configMap(1, map[string]string{"shard": "a"}, nil)
+	shardB := configMap(1, map[string]string{"shard": "b"}, nil)
+
+	assert.True(t, matching.Create(event.CreateEvent{Object: shardA}))
+	assert.False(t, matching.Create(event.CreateEvent{Object: shardB}))
+	assert.True(t, matching.Update(event.UpdateEvent{ObjectOld: shardA, ObjectNew: shardB}))
+	assert.False(t, matching.Update(event.UpdateEvent{ObjectOld: shardB, ObjectNew: shardB}))
+}
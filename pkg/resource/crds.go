@@ -0,0 +1,176 @@
+package resource
+
+import (
+	"context"
+	standardErrors "errors"
+	"fmt"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	crdEstablishedPollInterval = 500 * time.Millisecond
+	// crdEstablishedTimeout bounds the wait for new CRDs, which are usually established within seconds.
+	crdEstablishedTimeout = time.Minute
+)
+
+var (
+	ErrCRDStoredVersionRemoved = standardErrors.New("CRD upgrade removes a version still stored in the cluster")
+	ErrCRDNotEstablished       = standardErrors.New("CRD not established")
+)
+
+// InstallCRDs creates the passed CRDs or upgrades the existing ones and waits until all of them are established,
+// so that custom resources of them can be applied right after.
+// Upgrades removing a version which is still listed in the stored versions of the existing CRD
+// are refused with ErrCRDStoredVersionRemoved, as the persisted custom resources could no longer be served.
+func InstallCRDs(ctx context.Context, crds []*unstructured.Unstructured, runtimeClient client.Client) error {
+	for _, crd := range crds {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := installCRD(ctx, crd, runtimeClient); err != nil {
+			return fmt.Errorf("installing CRD %s: %w", crd.GetName(), err)
+		}
+	}
+	return waitForCRDsEstablished(ctx, crds, runtimeClient)
+}
+
+func installCRD(ctx context.Context, crd *unstructured.Unstructured, runtimeClient client.Client) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(crd.GroupVersionKind())
+	err := runtimeClient.Get(ctx, client.ObjectKeyFromObject(crd), existing)
+	if apierrors.IsNotFound(err) {
+		if err := runtimeClient.Create(ctx, crd.DeepCopy()); !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		// created concurrently, e.g. by another install of the same chart
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	existingCRD, err := toCRD(existing)
+	if err != nil {
+		return err
+	}
+	desiredCRD, err := toCRD(crd)
+	if err != nil {
+		return err
+	}
+	if err := VerifyStoredVersions(existingCRD, desiredCRD); err != nil {
+		return err
+	}
+
+	desired := crd.DeepCopy()
+	desired.SetResourceVersion(existing.GetResourceVersion())
+	// the status is owned by the API server and must not be reset by the upgrade
+	if status, found := existing.Object["status"]; found {
+		desired.Object["status"] = status
+	}
+	return runtimeClient.Update(ctx, desired)
+}
+
+// VerifyStoredVersions returns ErrCRDStoredVersionRemoved, if the desired CRD does not serve a version
+// which is listed in the stored versions of the existing CRD.
+// Such versions can only be removed after all custom resources were migrated and the stored versions were reset.
+func VerifyStoredVersions(existing, desired *apiextensionsv1.CustomResourceDefinition) error {
+	desiredVersions := sets.NewString()
+	for _, version := range desired.Spec.Versions {
+		desiredVersions.Insert(version.Name)
+	}
+	var removed []string
+	for _, stored := range existing.Status.StoredVersions {
+		if !desiredVersions.Has(stored) {
+			removed = append(removed, stored)
+		}
+	}
+	if len(removed) > 0 {
+		return fmt.Errorf("%w: %s would lose stored versions %v", ErrCRDStoredVersionRemoved, existing.Name, removed)
+	}
+	return nil
+}
+
+// IsEstablished indicates if the CRD is established and its names are accepted by the API server.
+func IsEstablished(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	return crdConditionTrue(crd, apiextensionsv1.Established) && crdConditionTrue(crd, apiextensionsv1.NamesAccepted)
+}
+
+func waitForCRDsEstablished(ctx context.Context, crds []*unstructured.Unstructured,
+	runtimeClient client.Client,
+) error {
+	pending := crds
+	err := wait.PollImmediateWithContext(ctx, crdEstablishedPollInterval, crdEstablishedTimeout,
+		func(ctx context.Context) (bool, error) {
+			var notEstablished []*unstructured.Unstructured
+			for _, crd := range pending {
+				existing := &unstructured.Unstructured{}
+				existing.SetGroupVersionKind(crd.GroupVersionKind())
+				if err := runtimeClient.Get(ctx, client.ObjectKeyFromObject(crd), existing); err != nil {
+					return false, client.IgnoreNotFound(err)
+				}
+				existingCRD, err := toCRD(existing)
+				if err != nil {
+					return false, err
+				}
+				// conflicting names are not resolved by waiting
+				if crdConditionFalse(existingCRD, apiextensionsv1.NamesAccepted) {
+					return false, fmt.Errorf("%w: names of %s not accepted", ErrCRDNotEstablished, crd.GetName())
+				}
+				if !IsEstablished(existingCRD) {
+					notEstablished = append(notEstablished, crd)
+				}
+			}
+			pending = notEstablished
+			return len(pending) == 0, nil
+		})
+	// cancellation is reported as wait timeout as well
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if standardErrors.Is(err, wait.ErrWaitTimeout) {
+		names := make([]string, 0, len(pending))
+		for _, crd := range pending {
+			names = append(names, crd.GetName())
+		}
+		return fmt.Errorf("%w within %s: %v", ErrCRDNotEstablished, crdEstablishedTimeout, names)
+	}
+	return err
+}
+
+func toCRD(obj *unstructured.Unstructured) (*apiextensionsv1.CustomResourceDefinition, error) {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, crd); err != nil {
+		return nil, fmt.Errorf("converting %s to CRD: %w", obj.GetName(), err)
+	}
+	return crd, nil
+}
+
+func crdConditionTrue(crd *apiextensionsv1.CustomResourceDefinition,
+	conditionType apiextensionsv1.CustomResourceDefinitionConditionType,
+) bool {
+	return crdCondition(crd, conditionType) == apiextensionsv1.ConditionTrue
+}
+
+func crdConditionFalse(crd *apiextensionsv1.CustomResourceDefinition,
+	conditionType apiextensionsv1.CustomResourceDefinitionConditionType,
+) bool {
+	return crdCondition(crd, conditionType) == apiextensionsv1.ConditionFalse
+}
+
+func crdCondition(crd *apiextensionsv1.CustomResourceDefinition,
+	conditionType apiextensionsv1.CustomResourceDefinitionConditionType,
+) apiextensionsv1.ConditionStatus {
+	for _, condition := range crd.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status
+		}
+	}
+	return apiextensionsv1.ConditionUnknown
+}
@@ -0,0 +1,83 @@
+package resource_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/module-manager/pkg/resource"
+)
+
+func sampleCRD(versions ...string) *apiextensionsv1.CustomResourceDefinition {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiextensionsv1.SchemeGroupVersion.String(), Kind: "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: "samples.operator.kyma-project.io"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "operator.kyma-project.io",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "Sample", Plural: "samples"},
+			Scope: apiextensionsv1.NamespaceScoped,
+		},
+	}
+	for i, version := range versions {
+		crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{
+			Name: version, Served: true, Storage: i == len(versions)-1,
+		})
+	}
+	return crd
+}
+
+func toUnstructured(t *testing.T, crd *apiextensionsv1.CustomResourceDefinition) *unstructured.Unstructured {
+	t.Helper()
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(crd)
+	require.NoError(t, err)
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func Test_VerifyStoredVersions(t *testing.T) {
+	t.Parallel()
+	existing := sampleCRD("v1alpha1", "v1")
+	existing.Status.StoredVersions = []string{"v1alpha1", "v1"}
+
+	assert.NoError(t, resource.VerifyStoredVersions(existing, sampleCRD("v1alpha1", "v1", "v2")))
+	assert.ErrorIs(t, resource.VerifyStoredVersions(existing, sampleCRD("v1", "v2")),
+		resource.ErrCRDStoredVersionRemoved)
+
+	// versions, which were never stored, can be removed
+	existing.Status.StoredVersions = []string{"v1"}
+	assert.NoError(t, resource.VerifyStoredVersions(existing, sampleCRD("v1")))
+}
+
+func Test_InstallCRDs(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	existing := sampleCRD("v1alpha1")
+	existing.Status.StoredVersions = []string{"v1alpha1"}
+	existing.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{
+		{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue},
+		{Type: apiextensionsv1.NamesAccepted, Status: apiextensionsv1.ConditionTrue},
+	}
+	clnt := fake.NewClientBuilder().WithObjects(toUnstructured(t, existing)).Build()
+
+	err := resource.InstallCRDs(ctx, []*unstructured.Unstructured{toUnstructured(t, sampleCRD("v1"))}, clnt)
+	require.ErrorIs(t, err, resource.ErrCRDStoredVersionRemoved)
+
+	upgrade := toUnstructured(t, sampleCRD("v1alpha1", "v1"))
+	require.NoError(t, resource.InstallCRDs(ctx, []*unstructured.Unstructured{upgrade}, clnt))
+
+	installed := &unstructured.Unstructured{}
+	installed.SetGroupVersionKind(upgrade.GroupVersionKind())
+	require.NoError(t, clnt.Get(ctx, client.ObjectKeyFromObject(upgrade), installed))
+	versions, _, err := unstructured.NestedSlice(installed.Object, "spec", "versions")
+	require.NoError(t, err)
+	assert.Len(t, versions, 2)
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authenticationclient "k8s.io/client-go/kubernetes/typed/authentication/v1"
+)
+
+const bearerPrefix = "Bearer "
+
+var (
+	ErrUnauthenticated = errors.New("unauthenticated")
+	ErrUnauthorized    = errors.New("unauthorized")
+)
+
+// Authenticator authenticates callers of the Service by their bearer token and returns their user name.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (string, error)
+}
+
+// TokenReviewAuthenticator authenticates bearer tokens with TokenReviews of a cluster, e.g. the tokens of
+// ServiceAccounts. If AllowedGroups are set, only members of one of them are authorized.
+type TokenReviewAuthenticator struct {
+	TokenReviews authenticationclient.TokenReviewInterface
+	// Audiences the tokens have to be issued for, the audience of the API server if empty
+	Audiences     []string
+	AllowedGroups []string
+}
+
+// Authenticate returns the user name of the token, ErrUnauthenticated if it is invalid, or ErrUnauthorized if its
+// user is not a member of AllowedGroups.
+func (a *TokenReviewAuthenticator) Authenticate(ctx context.Context, token string) (string, error) {
+	review, err := a.TokenReviews.Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token, Audiences: a.Audiences},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("reviewing token: %w", err)
+	}
+	if !review.Status.Authenticated {
+		return "", fmt.Errorf("%w: %s", ErrUnauthenticated, review.Status.Error)
+	}
+	user := review.Status.User
+	if len(a.AllowedGroups) == 0 {
+		return user.Username, nil
+	}
+	for _, group := range user.Groups {
+		for _, allowed := range a.AllowedGroups {
+			if group == allowed {
+				return user.Username, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%w: %s is not a member of an allowed group", ErrUnauthorized, user.Username)
+}
+
+// authenticate returns the user name of a caller, who is authenticated by a client certificate verified during the
+// TLS handshake, or by the bearer token of the authorization header with the Authenticator of the Service.
+func (s *Service) authenticate(ctx context.Context, state *tls.ConnectionState, authorization string,
+) (string, error) {
+	if state != nil && len(state.VerifiedChains) > 0 && len(state.VerifiedChains[0]) > 0 {
+		return state.VerifiedChains[0][0].Subject.CommonName, nil
+	}
+	token := strings.TrimPrefix(authorization, bearerPrefix)
+	if token == "" || token == authorization {
+		return "", fmt.Errorf("%w: neither a client certificate nor a bearer token was passed", ErrUnauthenticated)
+	}
+	if s.Authenticator == nil {
+		return "", fmt.Errorf("%w: bearer tokens are not accepted", ErrUnauthenticated)
+	}
+	return s.Authenticator.Authenticate(ctx, token)
+}
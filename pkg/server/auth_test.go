@@ -0,0 +1,45 @@
+package server_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/kyma-project/module-manager/pkg/server"
+)
+
+func Test_TokenReviewAuthenticator(t *testing.T) {
+	t.Parallel()
+	clientSet := fake.NewSimpleClientset()
+	clientSet.PrependReactor("create", "tokenreviews",
+		func(action clienttesting.Action) (bool, runtime.Object, error) {
+			review := action.(clienttesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+			if review.Spec.Token == "valid" {
+				review.Status.Authenticated = true
+				review.Status.User = authenticationv1.UserInfo{
+					Username: "system:serviceaccount:default:installer", Groups: []string{"system:serviceaccounts"},
+				}
+			}
+			return true, review, nil
+		})
+	authenticator := &server.TokenReviewAuthenticator{TokenReviews: clientSet.AuthenticationV1().TokenReviews()}
+
+	user, err := authenticator.Authenticate(context.Background(), "valid")
+	require.NoError(t, err)
+	assert.Equal(t, "system:serviceaccount:default:installer", user)
+	_, err = authenticator.Authenticate(context.Background(), "invalid")
+	assert.ErrorIs(t, err, server.ErrUnauthenticated)
+
+	authenticator.AllowedGroups = []string{"module-installers"}
+	_, err = authenticator.Authenticate(context.Background(), "valid")
+	assert.ErrorIs(t, err, server.ErrUnauthorized)
+	authenticator.AllowedGroups = append(authenticator.AllowedGroups, "system:serviceaccounts")
+	_, err = authenticator.Authenticate(context.Background(), "valid")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/kyma-project/module-manager/pkg/log"
+)
+
+// GRPCServiceName is the name of the gRPC service described in manifest.proto.
+const GRPCServiceName = "manifest.v1.ManifestService"
+
+// RegisterGRPC registers the operations of the Service as the gRPC service described in manifest.proto.
+// Requests and responses are passed as google.protobuf.Struct with the JSON fields of InstallRequest and
+// the responses, so that any gRPC client can call the service without generated message types.
+// Callers are authenticated like for the HTTP API, by a client certificate or by the bearer token of the
+// "authorization" metadata.
+func RegisterGRPC(registrar grpc.ServiceRegistrar, service *Service) {
+	registrar.RegisterService(&grpc.ServiceDesc{
+		ServiceName: GRPCServiceName,
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{
+			grpcMethod(service, "Install", func(ctx context.Context, request *InstallRequest) (any, error) {
+				return service.Install(ctx, request)
+			}),
+			grpcMethod(service, "Uninstall", func(ctx context.Context, request *InstallRequest) (any, error) {
+				return service.Uninstall(ctx, request)
+			}),
+			grpcMethod(service, "DryRun", func(ctx context.Context, request *InstallRequest) (any, error) {
+				return service.DryRun(ctx, request)
+			}),
+			grpcMethod(service, "Status", func(ctx context.Context, request *InstallRequest) (any, error) {
+				return service.Status(ctx, request)
+			}),
+		},
+		Metadata: "manifest.proto",
+	}, service)
+}
+
+func grpcMethod(service *Service, name string, call operation) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv any, ctx context.Context, decode func(any) error,
+			interceptor grpc.UnaryServerInterceptor,
+		) (any, error) {
+			in := &structpb.Struct{}
+			if err := decode(in); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, in any) (any, error) {
+				return handleGRPC(ctx, service, in.(*structpb.Struct), call)
+			}
+			if interceptor == nil {
+				return handler(ctx, in)
+			}
+			return interceptor(ctx, in, &grpc.UnaryServerInfo{
+				Server:     srv,
+				FullMethod: fmt.Sprintf("/%s/%s", GRPCServiceName, name),
+			}, handler)
+		},
+	}
+}
+
+func handleGRPC(ctx context.Context, service *Service, in *structpb.Struct, call operation,
+) (*structpb.Struct, error) {
+	if err := authenticateGRPC(ctx, service); err != nil {
+		return nil, err
+	}
+	request := &InstallRequest{}
+	if err := convert(in.AsMap(), request); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s: %v", ErrInvalidRequest, err)
+	}
+	response, err := call(ctx, request)
+	switch {
+	case errors.Is(err, ErrInvalidRequest):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	out := map[string]any{}
+	if err := convert(response, &out); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return structpb.NewStruct(out)
+}
+
+func authenticateGRPC(ctx context.Context, service *Service) error {
+	var state *tls.ConnectionState
+	if caller, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := caller.AuthInfo.(credentials.TLSInfo); ok {
+			state = &tlsInfo.State
+		}
+	}
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("authorization")) > 0 {
+		authorization = md.Get("authorization")[0]
+	}
+	user, err := service.authenticate(ctx, state, authorization)
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, ErrUnauthorized):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case err != nil:
+		return status.Error(codes.Internal, err.Error())
+	}
+	service.Logger.V(log.DebugLevel).Info("authenticated request", "user", user)
+	return nil
+}
+
+// convert converts between structs and maps by their JSON representation.
+func convert(from, to any) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/kyma-project/module-manager/pkg/log"
+	"github.com/kyma-project/module-manager/pkg/version"
+)
+
+const (
+	InstallPath   = "/v1/install"
+	UninstallPath = "/v1/uninstall"
+	DryRunPath    = "/v1/dry-run"
+	StatusPath    = "/v1/status"
+	VersionPath   = "/version"
+
+	maxRequestBodyBytes = 1 << 20
+)
+
+// operation is a method of the Service handling an InstallRequest.
+type operation func(ctx context.Context, request *InstallRequest) (any, error)
+
+// NewHTTPHandler returns a handler exposing the operations of the Service as JSON over HTTP.
+// Every operation is a POST of an InstallRequest, invalid requests are answered with status 400.
+// Callers of operations are authenticated by a verified client certificate or by a bearer token and
+// are answered with status 401 or 403 otherwise. The build info of module-manager is served on GET of VersionPath.
+func NewHTTPHandler(service *Service) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(InstallPath, httpOperation(service, func(ctx context.Context, request *InstallRequest) (any, error) {
+		return service.Install(ctx, request)
+	}))
+	mux.Handle(UninstallPath, httpOperation(service, func(ctx context.Context, request *InstallRequest) (any, error) {
+		return service.Uninstall(ctx, request)
+	}))
+	mux.Handle(DryRunPath, httpOperation(service, func(ctx context.Context, request *InstallRequest) (any, error) {
+		return service.DryRun(ctx, request)
+	}))
+	mux.Handle(StatusPath, httpOperation(service, func(ctx context.Context, request *InstallRequest) (any, error) {
+		return service.Status(ctx, request)
+	}))
+	mux.Handle(VersionPath, version.Handler())
+	return mux
+}
+
+func httpOperation(service *Service, call operation) http.HandlerFunc {
+	return func(writer http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			writer.Header().Set("Allow", http.MethodPost)
+			writeHTTPError(writer, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+		user, err := service.authenticate(req.Context(), req.TLS, req.Header.Get("Authorization"))
+		switch {
+		case errors.Is(err, ErrUnauthenticated):
+			writer.Header().Set("WWW-Authenticate", "Bearer")
+			writeHTTPError(writer, http.StatusUnauthorized, err)
+			return
+		case errors.Is(err, ErrUnauthorized):
+			writeHTTPError(writer, http.StatusForbidden, err)
+			return
+		case err != nil:
+			writeHTTPError(writer, http.StatusInternalServerError, err)
+			return
+		}
+		service.Logger.V(log.DebugLevel).Info("authenticated request", "user", user, "path", req.URL.Path)
+		request := &InstallRequest{}
+		decoder := json.NewDecoder(http.MaxBytesReader(writer, req.Body, maxRequestBodyBytes))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(request); err != nil {
+			writeHTTPError(writer, http.StatusBadRequest, fmt.Errorf("%w: %v", ErrInvalidRequest, err))
+			return
+		}
+		response, err := call(req.Context(), request)
+		switch {
+		case errors.Is(err, ErrInvalidRequest):
+			writeHTTPError(writer, http.StatusBadRequest, err)
+		case err != nil:
+			writeHTTPError(writer, http.StatusInternalServerError, err)
+		default:
+			writeJSON(writer, http.StatusOK, response)
+		}
+	}
+}
+
+func writeHTTPError(writer http.ResponseWriter, status int, err error) {
+	writeJSON(writer, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(writer http.ResponseWriter, status int, body any) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	_ = json.NewEncoder(writer).Encode(body)
+}
@@ -0,0 +1,155 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/module-manager/pkg/server"
+)
+
+type stubAuthenticator struct{}
+
+func (stubAuthenticator) Authenticate(_ context.Context, token string) (string, error) {
+	switch token {
+	case "valid":
+		return "system:serviceaccount:default:installer", nil
+	case "denied":
+		return "", server.ErrUnauthorized
+	default:
+		return "", server.ErrUnauthenticated
+	}
+}
+
+// testKubeconfig returns a kubeconfig, whose user has the passed YAML fields.
+func testKubeconfig(user string) string {
+	return `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:6443
+users:
+- name: test
+  user:
+    token: test` + user + `
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+`
+}
+
+func Test_HTTPHandler(t *testing.T) {
+	t.Parallel()
+	service := server.NewService(logr.Discard(), nil)
+	service.Authenticator = stubAuthenticator{}
+	service.ChartRoot = t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(service.ChartRoot, "nginx"), os.ModePerm))
+	require.NoError(t, os.Symlink("/etc", filepath.Join(service.ChartRoot, "etc")))
+	handler := server.NewHTTPHandler(service)
+	testCases := []struct {
+		name   string
+		method string
+		token  string
+		body   string
+		status int
+		error  string
+	}{
+		{
+			name: "only POST is allowed", method: http.MethodGet,
+			status: http.StatusMethodNotAllowed, error: "method GET not allowed",
+		},
+		{
+			name: "callers are authenticated", method: http.MethodPost, token: "-",
+			body:   `{"name":"test","chartPath":"nginx"}`,
+			status: http.StatusUnauthorized, error: "neither a client certificate nor a bearer token was passed",
+		},
+		{
+			name: "invalid tokens are rejected", method: http.MethodPost, token: "invalid",
+			body:   `{"name":"test","chartPath":"nginx"}`,
+			status: http.StatusUnauthorized, error: server.ErrUnauthenticated.Error(),
+		},
+		{
+			name: "callers are authorized", method: http.MethodPost, token: "denied",
+			body:   `{"name":"test","chartPath":"nginx"}`,
+			status: http.StatusForbidden, error: server.ErrUnauthorized.Error(),
+		},
+		{
+			name: "chart paths cannot leave the chart root", method: http.MethodPost,
+			body:   `{"name":"test","chartPath":"../../usr","kubeconfig":` + strconv.Quote(testKubeconfig("")) + `}`,
+			status: http.StatusBadRequest, error: "no such file or directory",
+		},
+		{
+			name: "chart paths cannot link out of the chart root", method: http.MethodPost,
+			body:   `{"name":"test","chartPath":"etc","kubeconfig":` + strconv.Quote(testKubeconfig("")) + `}`,
+			status: http.StatusBadRequest, error: "is not in the chart root",
+		},
+		{
+			name: "kubeconfigs with exec plugins are rejected", method: http.MethodPost,
+			body: `{"name":"test","chartPath":"nginx","kubeconfig":` + strconv.Quote(testKubeconfig(`
+    exec:
+      apiVersion: client.authentication.k8s.io/v1
+      command: touch`)) + `}`,
+			status: http.StatusBadRequest, error: server.ErrUnsafeKubeconfig.Error(),
+		},
+		{
+			name: "kubeconfigs with auth-provider are rejected", method: http.MethodPost,
+			body: `{"name":"test","chartPath":"nginx","kubeconfig":` + strconv.Quote(testKubeconfig(`
+    auth-provider:
+      name: gcp`)) + `}`,
+			status: http.StatusBadRequest, error: server.ErrUnsafeKubeconfig.Error(),
+		},
+		{
+			name: "kubeconfigs reading files are rejected", method: http.MethodPost,
+			body: `{"name":"test","chartPath":"nginx","kubeconfig":` + strconv.Quote(testKubeconfig(`
+    tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token`)) + `}`,
+			status: http.StatusBadRequest, error: server.ErrUnsafeKubeconfig.Error(),
+		},
+		{
+			name: "unknown fields are rejected", method: http.MethodPost, body: `{"name":"test","chart":"x"}`,
+			status: http.StatusBadRequest, error: "unknown field",
+		},
+		{
+			name: "charts need a source", method: http.MethodPost, body: `{"name":"test","chartName":"nginx"}`,
+			status: http.StatusBadRequest, error: "either chartPath or chartName and url are required",
+		},
+		{
+			name: "names are validated", method: http.MethodPost, body: `{"name":"Test","chartPath":"/charts/nginx"}`,
+			status: http.StatusBadRequest, error: "lowercase RFC 1123 subdomain",
+		},
+		{
+			name: "target clusters are required without default", method: http.MethodPost,
+			body:   `{"name":"test","chartPath":"nginx"}`,
+			status: http.StatusBadRequest, error: server.ErrNoTargetConfig.Error(),
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(testCase.method, server.InstallPath, strings.NewReader(testCase.body))
+			if testCase.token == "" {
+				testCase.token = "valid"
+			}
+			if testCase.token != "-" {
+				request.Header.Set("Authorization", "Bearer "+testCase.token)
+			}
+			handler.ServeHTTP(recorder, request)
+			assert.Equal(t, testCase.status, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), testCase.error)
+		})
+	}
+}
@@ -0,0 +1,315 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/manifest"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+const (
+	// installAPIVersion and installKind identify the base resource of installs driven by the server,
+	// which only exists to label and cache the resources of the install.
+	installAPIVersion  = labels.OperatorPrefix + "/v1alpha1"
+	installKind        = "ManifestServerInstall"
+	cacheKeyPrefix     = "server-"
+	defaultClusterName = "default"
+)
+
+var (
+	ErrInvalidRequest   = errors.New("invalid request")
+	ErrNoTargetConfig   = errors.New("no kubeconfig passed and no default cluster configured")
+	ErrNoChartRoot      = errors.New("no chart root configured for charts on the file system of the server")
+	ErrUnsafeKubeconfig = errors.New("kubeconfig runs commands or reads files on the server")
+)
+
+// InstallRequest describes an install of a chart to a target cluster.
+type InstallRequest struct {
+	// Name identifies the install together with its Namespace, e.g. for caching
+	Name string `json:"name"`
+	// Namespace identifies the install together with its Name, it defaults to "default"
+	Namespace string `json:"namespace,omitempty"`
+	// ChartName is the name of the chart in its repository
+	ChartName string `json:"chartName"`
+	// ReleaseName is the name of the release of the chart, it defaults to the Name of the install
+	ReleaseName string `json:"releaseName,omitempty"`
+	// ChartPath is the path of a chart or a directory of manifests relative to the chart root of the server
+	ChartPath string `json:"chartPath,omitempty"`
+	// RepoName is the name of the Helm repository of the chart
+	RepoName string `json:"repoName,omitempty"`
+	// URL is the URL of the Helm repository of the chart
+	URL string `json:"url,omitempty"`
+	// ConfigFlags are the Helm install flags, e.g. Namespace or CreateNamespace
+	ConfigFlags types.Flags `json:"configFlags,omitempty"`
+	// SetFlags are the chart value overrides
+	SetFlags types.Flags `json:"setFlags,omitempty"`
+	// Kubeconfig is the kubeconfig of the target cluster, the default cluster of the server is used if empty.
+	// Credentials have to be embedded, as exec and auth-provider plugins and references to files are rejected.
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	// CheckReadyStates indicates if native resources should be checked for ready states
+	CheckReadyStates bool `json:"checkReadyStates,omitempty"`
+	// Timeout limits the duration of the operation, e.g. "5m"
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// Validate returns an ErrInvalidRequest, if the request does not identify an install of a chart.
+func (r *InstallRequest) Validate() error {
+	if errs := validation.IsDNS1123Subdomain(r.Name); len(errs) > 0 {
+		return fmt.Errorf("%w: name %q: %v", ErrInvalidRequest, r.Name, errs)
+	}
+	if r.Namespace != "" {
+		if errs := validation.IsDNS1123Label(r.Namespace); len(errs) > 0 {
+			return fmt.Errorf("%w: namespace %q: %v", ErrInvalidRequest, r.Namespace, errs)
+		}
+	}
+	if r.ChartPath == "" && (r.ChartName == "" || r.URL == "") {
+		return fmt.Errorf("%w: either chartPath or chartName and url are required", ErrInvalidRequest)
+	}
+	return nil
+}
+
+// OperationResponse is the result of an install, uninstall or status operation.
+// Failures of the operation itself are described by the response instead of being returned as errors.
+type OperationResponse struct {
+	// Ready indicates if the operation completed and all resources are ready
+	Ready bool `json:"ready"`
+	// Error describes why the operation failed
+	Error string `json:"error,omitempty"`
+	// Reason is the CamelCase reason of a types.OperationError
+	Reason string `json:"reason,omitempty"`
+	// Classification indicates if the operation is expected to succeed on retry
+	Classification types.ErrorClassification `json:"classification,omitempty"`
+}
+
+// DryRunResponse is the rendered preview of an install.
+type DryRunResponse struct {
+	// Manifest is the rendered manifest of the install
+	Manifest string `json:"manifest,omitempty"`
+	// Diffs lists all resources that would be created, updated or pruned
+	Diffs []types.ResourceDiff `json:"diffs,omitempty"`
+	// Error describes why the dry-run failed
+	Error string `json:"error,omitempty"`
+}
+
+// Service drives the operations of the manifest library for InstallRequests, so that installs can be
+// managed by components not embedding the library. Manifest processors are cached per target cluster.
+// Callers are authenticated by the HTTP and gRPC APIs of the Service, see Authenticator.
+type Service struct {
+	Logger logr.Logger
+	// Config is the REST config of the default cluster used for requests without a kubeconfig.
+	// If it is nil, every request needs to pass a kubeconfig.
+	Config *rest.Config
+	// Authenticator authenticates callers by their bearer token. If it is nil, only callers with a client
+	// certificate verified by the TLS config of the server are authenticated.
+	Authenticator Authenticator
+	// ChartRoot is the directory on the file system of the server, which the chart paths of requests are resolved in.
+	// If it is empty, requests can only install charts of repositories.
+	ChartRoot string
+
+	cache types.RendererCache
+}
+
+// NewService returns a Service with a new renderer cache.
+func NewService(logger logr.Logger, config *rest.Config) *Service {
+	return &Service{Logger: logger, Config: config, cache: manifest.NewRendererCache()}
+}
+
+// Install installs the chart of the request and reports if all of its resources are ready.
+func (s *Service) Install(ctx context.Context, request *InstallRequest) (*OperationResponse, error) {
+	options, err := s.operationOptions(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	ready, err := manifest.InstallChart(options)
+	return operationResponse(ready, err), nil
+}
+
+// Uninstall removes the resources of the chart of the request and reports if all of them are removed.
+func (s *Service) Uninstall(ctx context.Context, request *InstallRequest) (*OperationResponse, error) {
+	options, err := s.operationOptions(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	ready, err := manifest.UninstallChart(options)
+	return operationResponse(ready, err), nil
+}
+
+// Status verifies if the resources of the chart of the request are installed consistently and ready.
+func (s *Service) Status(ctx context.Context, request *InstallRequest) (*OperationResponse, error) {
+	options, err := s.operationOptions(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	ready, err := manifest.ConsistencyCheck(options)
+	return operationResponse(ready, err), nil
+}
+
+// DryRun renders the chart of the request and previews the changes an install would apply.
+func (s *Service) DryRun(ctx context.Context, request *InstallRequest) (*DryRunResponse, error) {
+	options, err := s.operationOptions(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	result, err := manifest.DryRunChart(options)
+	if err != nil {
+		return &DryRunResponse{Error: err.Error()}, nil
+	}
+	return &DryRunResponse{Manifest: result.Manifest, Diffs: result.Diffs}, nil
+}
+
+func (s *Service) operationOptions(ctx context.Context, request *InstallRequest) (manifest.OperationOptions, error) {
+	if err := request.Validate(); err != nil {
+		return manifest.OperationOptions{}, err
+	}
+	config, clusterName, err := s.restConfig(request.Kubeconfig)
+	if err != nil {
+		return manifest.OperationOptions{}, err
+	}
+	chartPath, err := s.chartPath(request.ChartPath)
+	if err != nil {
+		return manifest.OperationOptions{}, err
+	}
+
+	namespace := request.Namespace
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	releaseName := request.ReleaseName
+	if releaseName == "" {
+		releaseName = request.Name
+	}
+	baseResource := &unstructured.Unstructured{}
+	baseResource.SetAPIVersion(installAPIVersion)
+	baseResource.SetKind(installKind)
+	baseResource.SetName(request.Name)
+	baseResource.SetNamespace(namespace)
+	// processors are cached per target cluster instead of per install
+	baseResource.SetLabels(map[string]string{labels.CacheKey: cacheKeyPrefix + clusterName})
+
+	installInfo := &types.InstallInfo{
+		ChartInfo: &types.ChartInfo{
+			ChartPath:   chartPath,
+			RepoName:    request.RepoName,
+			URL:         request.URL,
+			ChartName:   request.ChartName,
+			ReleaseName: releaseName,
+			Flags: types.ChartFlags{
+				ConfigFlags: request.ConfigFlags,
+				SetFlags:    request.SetFlags,
+			},
+		},
+		ResourceInfo: &types.ResourceInfo{
+			BaseResource:    baseResource,
+			CustomResources: []*unstructured.Unstructured{},
+		},
+		ClusterInfo:      &types.ClusterInfo{Config: config},
+		Ctx:              ctx,
+		CheckReadyStates: request.CheckReadyStates,
+	}
+	if request.Timeout != nil {
+		installInfo.Timeout = request.Timeout.Duration
+	}
+	return manifest.OperationOptions{
+		Logger:      s.Logger.WithValues("install", namespace+"/"+request.Name),
+		InstallInfo: installInfo,
+		Cache:       s.cache,
+	}, nil
+}
+
+// restConfig returns the REST config of the target cluster and a name identifying the cluster for caching.
+func (s *Service) restConfig(kubeconfig string) (*rest.Config, string, error) {
+	if kubeconfig == "" {
+		if s.Config == nil {
+			return nil, "", fmt.Errorf("%w: %s", ErrInvalidRequest, ErrNoTargetConfig)
+		}
+		return s.Config, defaultClusterName, nil
+	}
+	apiConfig, err := clientcmd.Load([]byte(kubeconfig))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: kubeconfig: %v", ErrInvalidRequest, err)
+	}
+	if err := verifyKubeconfig(apiConfig); err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	config, err := clientcmd.NewDefaultClientConfig(*apiConfig, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: kubeconfig: %v", ErrInvalidRequest, err)
+	}
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(kubeconfig))
+	return config, strconv.FormatUint(hash.Sum64(), 16), nil
+}
+
+// verifyKubeconfig returns ErrUnsafeKubeconfig, if the kubeconfig of a request would run commands or read files
+// on the server when its credentials are used, e.g. by exec plugins.
+func verifyKubeconfig(config *clientcmdapi.Config) error {
+	for name, authInfo := range config.AuthInfos {
+		switch {
+		case authInfo.Exec != nil:
+			return fmt.Errorf("%w: user %s has an exec plugin", ErrUnsafeKubeconfig, name)
+		case authInfo.AuthProvider != nil:
+			return fmt.Errorf("%w: user %s has an auth-provider", ErrUnsafeKubeconfig, name)
+		case authInfo.TokenFile != "" || authInfo.ClientCertificate != "" || authInfo.ClientKey != "":
+			return fmt.Errorf("%w: user %s references files", ErrUnsafeKubeconfig, name)
+		}
+	}
+	for name, cluster := range config.Clusters {
+		if cluster.CertificateAuthority != "" {
+			return fmt.Errorf("%w: cluster %s references files", ErrUnsafeKubeconfig, name)
+		}
+	}
+	return nil
+}
+
+// chartPath resolves the chart path of a request in the ChartRoot, so that requests cannot read other files
+// of the server, neither by relative paths nor by symbolic links.
+func (s *Service) chartPath(chartPath string) (string, error) {
+	if chartPath == "" {
+		return "", nil
+	}
+	if s.ChartRoot == "" {
+		return "", fmt.Errorf("%w: %s", ErrInvalidRequest, ErrNoChartRoot)
+	}
+	root, err := filepath.EvalSymlinks(s.ChartRoot)
+	if err != nil {
+		return "", fmt.Errorf("resolving chart root: %w", err)
+	}
+	// the path is cleaned as an absolute path, so that it cannot leave the root
+	resolved, err := filepath.EvalSymlinks(filepath.Join(root, filepath.Join("/", chartPath)))
+	if err != nil {
+		return "", fmt.Errorf("%w: chartPath %s: %v", ErrInvalidRequest, chartPath, err)
+	}
+	if resolved != root && !strings.HasPrefix(resolved, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: chartPath %s is not in the chart root", ErrInvalidRequest, chartPath)
+	}
+	return resolved, nil
+}
+
+func operationResponse(ready bool, err error) *OperationResponse {
+	response := &OperationResponse{Ready: ready}
+	if err == nil {
+		return response
+	}
+	response.Error = err.Error()
+	response.Classification = manifest.ClassifyError(err)
+	var operationErr *types.OperationError
+	if errors.As(err, &operationErr) {
+		response.Reason = operationErr.Reason
+	}
+	return response
+}
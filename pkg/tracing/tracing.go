@@ -0,0 +1,83 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the tracer, which records the spans of the operator and the manifest library.
+const TracerName = "github.com/kyma-project/module-manager"
+
+// Names of the spans recorded for the steps of reconciles and operations.
+const (
+	SpanReconcile        = "Reconcile"
+	SpanStateTransition  = "StateTransition"
+	SpanChartFetch       = "ChartFetch"
+	SpanInstall          = "Install"
+	SpanUninstall        = "Uninstall"
+	SpanConsistencyCheck = "ConsistencyCheck"
+	SpanRollback         = "Rollback"
+	SpanRender           = "Render"
+	SpanTransform        = "Transform"
+	SpanApply            = "Apply"
+	SpanDelete           = "Delete"
+	SpanReadiness        = "Readiness"
+)
+
+// Start starts a span of the TracerName tracer as child of the span in ctx, if any. Without a configured
+// TracerProvider, spans are not recorded. A nil context is treated as an empty context.
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End ends the span, marking it as failed with the passed error, if set.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Setup exports spans to the Jaeger collector at endpoint, e.g. http://jaeger-collector:14268/api/traces,
+// sampling the passed ratio of traces without sampled parent, and propagates W3C trace contexts.
+// The returned function flushes and stops the export.
+func Setup(endpoint, serviceName string, sampleRatio float64) (func(context.Context) error, error) {
+	exporter, err := jaeger.New(jaeger.WithCollectorEndpoint(jaeger.WithEndpoint(endpoint)))
+	if err != nil {
+		return nil, fmt.Errorf("creating jaeger exporter for %s: %w", endpoint, err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return provider.Shutdown, nil
+}
+
+// ExtractFromAnnotations returns ctx with the remote span context propagated in the W3C traceparent
+// annotation annotationKey of a resource, e.g. of the request creating it, so that reconciles
+// are recorded as part of the same trace.
+func ExtractFromAnnotations(ctx context.Context, annotations map[string]string, annotationKey string,
+) context.Context {
+	traceParent := annotations[annotationKey]
+	if traceParent == "" {
+		return ctx
+	}
+	return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{"traceparent": traceParent})
+}
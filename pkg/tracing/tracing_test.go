@@ -0,0 +1,53 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/kyma-project/module-manager/pkg/tracing"
+)
+
+const traceParentAnnotation = "operator.kyma-project.io/traceparent"
+
+// TestSpans is not run in parallel, as it replaces the global TracerProvider.
+func TestSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	ctx := tracing.ExtractFromAnnotations(context.Background(), map[string]string{
+		traceParentAnnotation: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}, traceParentAnnotation)
+	ctx, reconcile := tracing.Start(ctx, tracing.SpanReconcile, attribute.String("module", "redis"))
+	_, apply := tracing.Start(ctx, tracing.SpanApply)
+	tracing.End(apply, errors.New("conflict"))
+	tracing.End(reconcile, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, tracing.SpanApply, spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "conflict", spans[0].Status().Description)
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+
+	assert.Equal(t, tracing.SpanReconcile, spans[1].Name())
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[1].SpanContext().TraceID().String(),
+		"the reconcile continues the propagated trace")
+	assert.True(t, spans[1].Parent().IsRemote())
+	assert.Contains(t, spans[1].Attributes(), attribute.String("module", "redis"))
+
+	_, unparented := tracing.Start(tracing.ExtractFromAnnotations(context.Background(), nil, traceParentAnnotation),
+		tracing.SpanReconcile)
+	tracing.End(unparented, nil)
+	require.Len(t, recorder.Ended(), 3)
+	assert.False(t, recorder.Ended()[2].Parent().IsValid())
+}
@@ -0,0 +1,49 @@
+package types
+
+// ApplyOperation describes how the last apply of an install changed a single resource.
+type ApplyOperation string
+
+const (
+	// ApplyOperationCreated signifies a resource, which did not exist before it was applied.
+	ApplyOperationCreated ApplyOperation = "Created"
+	// ApplyOperationUpdated signifies an existing resource, which was changed by the apply.
+	ApplyOperationUpdated ApplyOperation = "Updated"
+	// ApplyOperationUnchanged signifies an existing resource, which already matched the rendered resource.
+	ApplyOperationUnchanged ApplyOperation = "Unchanged"
+	// ApplyOperationFailed signifies a resource, which could not be applied.
+	ApplyOperationFailed ApplyOperation = "Failed"
+)
+
+// +k8s:deepcopy-gen=true
+
+// ResourceApplyStatus describes the result of the last apply of a single resource of an install.
+type ResourceApplyStatus struct {
+	// APIVersion is the apiVersion of the resource
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the resource
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the resource
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the resource
+	Name string `json:"name"`
+	// Operation describes how the resource was changed by the apply
+	Operation ApplyOperation `json:"operation"`
+	// Message describes why the resource could not be applied
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// InstallApplyStatus lists the results of the last apply of the resources of an install.
+type InstallApplyStatus struct {
+	// Install is the name of the install
+	Install string `json:"install"`
+	// Resources lists the applied resources, failed resources first
+	// +kubebuilder:validation:Optional
+	Resources []ResourceApplyStatus `json:"resources,omitempty"`
+	// Omitted is the number of applied resources not listed in Resources, as their number exceeds the limit
+	// +kubebuilder:validation:Optional
+	Omitted int `json:"omitted,omitempty"`
+}
@@ -0,0 +1,48 @@
+package types
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// BackupStorageDriver determines the kind of resource the snapshot of the resources of an install is stored in.
+// +kubebuilder:validation:Enum=Secret;ConfigMap
+type BackupStorageDriver string
+
+const (
+	// BackupStorageSecret stores the snapshot in a Secret, as live resources can contain sensitive values.
+	BackupStorageSecret BackupStorageDriver = "Secret"
+	// BackupStorageConfigMap stores the snapshot in a ConfigMap, e.g. if the operator may not write Secrets.
+	BackupStorageConfigMap BackupStorageDriver = "ConfigMap"
+)
+
+// +k8s:deepcopy-gen=true
+
+// BackupPolicy enables snapshots of the live resources of an install, which are taken before an upgrade
+// overwrites them, so that a failed upgrade can be inspected or restored manually.
+type BackupPolicy struct {
+	// Namespace is the namespace the snapshot is stored in. If not set, it is stored next to the inventory.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+	// Driver determines the kind of resource the snapshot is stored in. If not set, a Secret is used.
+	// +kubebuilder:validation:Optional
+	Driver BackupStorageDriver `json:"driver,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// BackupReference locates the snapshot of the live resources of an install taken before its last upgrade.
+type BackupReference struct {
+	// Name of the install
+	Name string `json:"name"`
+	// Kind of the resource the snapshot is stored in, Secret or ConfigMap
+	Kind string `json:"kind"`
+	// Namespace of the resource the snapshot is stored in
+	Namespace string `json:"namespace"`
+	// ObjectName is the name of the resource the snapshot is stored in
+	ObjectName string `json:"objectName"`
+	// ChartVersion is the chart version installed when the snapshot was taken, empty if it is unknown
+	// +kubebuilder:validation:Optional
+	ChartVersion string `json:"chartVersion,omitempty"`
+	// Resources is the number of resources in the snapshot
+	Resources int `json:"resources"`
+	// Time the snapshot was taken
+	Time metav1.Time `json:"time"`
+}
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -24,19 +26,28 @@ type RendererCache interface {
 	// GetProcessor returns the manifest processor cached entry by key passed.
 	GetProcessor(key client.ObjectKey) ManifestClient
 
+	// GetClusterProcessor returns any manifest processor cached entry of the cluster by key passed,
+	// regardless of its release, e.g. to reuse its cluster information.
+	GetClusterProcessor(key client.ObjectKey) ManifestClient
+
 	// SetProcessor sets the manifest processor cached entry by key passed.
 	SetProcessor(key client.ObjectKey, renderSrc ManifestClient)
 
-	// DeleteProcessor deletes the manifest processor cached entry by key passed.
+	// DeleteProcessor deletes the manifest processor cached entries of all releases
+	// and the rate limiter of the cluster by key passed.
 	DeleteProcessor(key client.ObjectKey)
 
+	// GetRateLimiter returns the rate limiter shared by the manifest processors of all releases of the cluster
+	// by key passed. If not cached yet, it is created with the QPS and burst of the passed config.
+	GetRateLimiter(key client.ObjectKey, config *rest.Config) flowcontrol.RateLimiter
+
 	// GetConfig returns the hashed configuration cached entry by key passed.
 	GetConfig(key client.ObjectKey) uint32
 
 	// SetConfig returns the hashed configuration cached entry by key passed.
 	SetConfig(key client.ObjectKey, cfg uint32)
 
-	// DeleteConfig deletes the hashed configuration cached entry by key passed.
+	// DeleteConfig deletes the hashed configuration cached entries of all releases by key passed.
 	DeleteConfig(key client.ObjectKey)
 }
 
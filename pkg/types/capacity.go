@@ -0,0 +1,26 @@
+package types
+
+import "fmt"
+
+// CapacityShortage describes a resource, whose requests by the rendered workloads of an install exceed
+// the capacity left in the target cluster, e.g. by a ResourceQuota of their namespace.
+type CapacityShortage struct {
+	// Namespace is the namespace of the affected workloads, it is empty for the capacity of the whole cluster
+	Namespace string
+	// Limit names what limits the capacity, e.g. a ResourceQuota or the allocatable resources of all nodes
+	Limit string
+	// Resource is the name of the exhausted resource, e.g. requests.cpu
+	Resource string
+	// Requested is the quantity requested additionally by the workloads
+	Requested string
+	// Available is the quantity still available
+	Available string
+}
+
+func (s CapacityShortage) String() string {
+	if s.Namespace == "" {
+		return fmt.Sprintf("%s of %s exceed %s available in %s", s.Resource, s.Requested, s.Available, s.Limit)
+	}
+	return fmt.Sprintf("%s/%s of %s exceed %s available in %s",
+		s.Namespace, s.Resource, s.Requested, s.Available, s.Limit)
+}
@@ -0,0 +1,41 @@
+package types
+
+// +k8s:deepcopy-gen=true
+
+// ChannelReference references a channel of a ModuleCatalog in the namespace of the Manifest,
+// which resolves to the version of the chart of an install instead of a pinned version.
+type ChannelReference struct {
+	// Catalog is the name of the ModuleCatalog
+	Catalog string `json:"catalog"`
+
+	// Channel is the name of the channel of the ModuleCatalog, e.g. "regular".
+	// If not set, the channel of the Manifest is used.
+	// +kubebuilder:validation:Optional
+	Channel string `json:"channel,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ResolvedChannel is the version a channel resolved to, when it was installed last by an install.
+type ResolvedChannel struct {
+	// Name of the install
+	Name string `json:"name"`
+	// Catalog is the name of the ModuleCatalog
+	Catalog string `json:"catalog"`
+	// Channel is the name of the channel
+	Channel string `json:"channel"`
+	// Version the channel resolved to
+	Version string `json:"version"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ResolvedImage is the image a followed tag resolved to, when it was installed last by an install.
+type ResolvedImage struct {
+	// Name of the install
+	Name string `json:"name"`
+	// Tag of the image
+	Tag string `json:"tag"`
+	// Digest of the image the tag pointed to
+	Digest string `json:"digest"`
+}
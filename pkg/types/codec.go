@@ -14,6 +14,8 @@ type Codec struct {
 	imageSpecSchema     *gojsonschema.Schema
 	helmChartSpecSchema *gojsonschema.Schema
 	kustomizeSpecSchema *gojsonschema.Schema
+	archiveSpecSchema   *gojsonschema.Schema
+	gitSpecSchema       *gojsonschema.Schema
 }
 
 func NewCodec() (*Codec, error) {
@@ -50,10 +52,34 @@ func NewCodec() (*Codec, error) {
 		return nil, err
 	}
 
+	archiveSpecJSONBytes := jsonschema.Reflect(ArchiveSpec{})
+	bytes, err = archiveSpecJSONBytes.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	archiveSpecSchema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(bytes))
+	if err != nil {
+		return nil, err
+	}
+
+	gitSpecJSONBytes := jsonschema.Reflect(GitSpec{})
+	bytes, err = gitSpecJSONBytes.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	gitSpecSchema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(bytes))
+	if err != nil {
+		return nil, err
+	}
+
 	return &Codec{
 		imageSpecSchema:     imageSpecSchema,
 		helmChartSpecSchema: helmChartSpecSchema,
 		kustomizeSpecSchema: kustomizeSpecSchema,
+		archiveSpecSchema:   archiveSpecSchema,
+		gitSpecSchema:       gitSpecSchema,
 	}, nil
 }
 
@@ -104,6 +130,16 @@ func (c *Codec) Validate(data []byte, refType RefTypeMetadata) error {
 		if err != nil {
 			return err
 		}
+	case ArchiveType:
+		result, err = c.archiveSpecSchema.Validate(dataBytes)
+		if err != nil {
+			return err
+		}
+	case GitType:
+		result, err = c.gitSpecSchema.Validate(dataBytes)
+		if err != nil {
+			return err
+		}
 	case NilRefType:
 		return fmt.Errorf("unsupported %s passed as installation type", refType)
 	}
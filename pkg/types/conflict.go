@@ -0,0 +1,20 @@
+package types
+
+// ConflictPolicy determines how conflicts on the field ownership of resources are resolved on server-side apply,
+// e.g. if a HorizontalPodAutoscaler changed the replicas of a Deployment of the install.
+// +kubebuilder:validation:Enum=Force;Ignore;Fail
+type ConflictPolicy string
+
+const (
+	// ConflictPolicyForce takes over the ownership of conflicting fields and overwrites their values.
+	ConflictPolicyForce ConflictPolicy = "Force"
+	// ConflictPolicyIgnore leaves conflicting fields to their current managers and applies all other fields.
+	ConflictPolicyIgnore ConflictPolicy = "Ignore"
+	// ConflictPolicyFail fails the install with an ErrFieldOwnershipConflict naming the conflicting fields.
+	ConflictPolicyFail ConflictPolicy = "Fail"
+)
+
+// Forces indicates if the ownership of conflicting fields is taken over, which is the default.
+func (p ConflictPolicy) Forces() bool {
+	return p != ConflictPolicyIgnore && p != ConflictPolicyFail
+}
@@ -0,0 +1,18 @@
+package types
+
+// CRDPolicy determines if the CustomResourceDefinitions of an install are removed on its uninstall.
+// +kubebuilder:validation:Enum=Keep;Delete
+type CRDPolicy string
+
+const (
+	// CRDPolicyKeep keeps all CustomResourceDefinitions on uninstall, as their removal also deletes
+	// all custom resources of them in the cluster, including the ones not managed by the install.
+	CRDPolicyKeep CRDPolicy = "Keep"
+	// CRDPolicyDelete removes all CustomResourceDefinitions of the install on uninstall.
+	CRDPolicyDelete CRDPolicy = "Delete"
+)
+
+// DeletesCRDs indicates if CustomResourceDefinitions are removed on uninstall. Without a policy, they are kept.
+func (p CRDPolicy) DeletesCRDs() bool {
+	return p == CRDPolicyDelete
+}
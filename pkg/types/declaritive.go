@@ -10,11 +10,22 @@ import (
 
 type CustomObject interface {
 	BaseCustomObject
+	StatusAccessor
 	ComponentName() string
+}
+
+// StatusAccessor reads and writes the Status of a custom resource.
+type StatusAccessor interface {
 	GetStatus() Status
 	SetStatus(Status)
 }
 
+// SpecAccessor is implemented by custom resources, which provide their InstallationSpec directly,
+// e.g. as their spec has a custom shape, instead of having it resolved from their unstructured content.
+type SpecAccessor interface {
+	GetInstallationSpec() (InstallationSpec, error)
+}
+
 type BaseCustomObject interface {
 	runtime.Object
 	metav1.Object
@@ -63,9 +74,17 @@ type Status struct {
 
 	// Conditions associated with CustomStatus.
 	Conditions []*metav1.Condition `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the generation of the CustomObject processed last.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 func (s *Status) WithState(state State) Status {
 	s.State = state
 	return *s
 }
+
+func (s *Status) WithObservedGeneration(generation int64) Status {
+	s.ObservedGeneration = generation
+	return *s
+}
@@ -0,0 +1,41 @@
+package types
+
+// DeletionOrder determines the order in which the resources of an install are deleted on uninstall.
+// +kubebuilder:validation:Enum=Parallel;Reverse
+type DeletionOrder string
+
+const (
+	// DeletionOrderParallel deletes all resources at once.
+	DeletionOrderParallel DeletionOrder = "Parallel"
+	// DeletionOrderReverse deletes resources by their kind in the reverse order they are applied in,
+	// e.g. workloads before their ServiceAccounts and namespaces last. Custom resources are deleted first.
+	DeletionOrderReverse DeletionOrder = "Reverse"
+)
+
+// DeletionPropagation determines how the dependents of the resources of an install are deleted on uninstall.
+// +kubebuilder:validation:Enum=Background;Foreground
+type DeletionPropagation string
+
+const (
+	// DeletionPropagationBackground deletes dependents in the background after the resource is deleted.
+	DeletionPropagationBackground DeletionPropagation = "Background"
+	// DeletionPropagationForeground keeps the resource until all of its dependents are deleted.
+	DeletionPropagationForeground DeletionPropagation = "Foreground"
+)
+
+// +k8s:deepcopy-gen=true
+
+// DeletionPolicy configures how the resources of an install are deleted on uninstall.
+type DeletionPolicy struct {
+	// Order determines the order in which resources are deleted. If not set, they are deleted at once.
+	// +kubebuilder:validation:Optional
+	Order DeletionOrder `json:"order,omitempty"`
+	// Propagation determines how dependents of resources are deleted. If not set, they are deleted in the background.
+	// +kubebuilder:validation:Optional
+	Propagation DeletionPropagation `json:"propagation,omitempty"`
+	// WaitForTermination completes the uninstall only once all resources are gone, e.g. blocked by finalizers,
+	// instead of once their deletion was requested. With the Reverse order, the resources of a kind are only
+	// deleted once the resources of all kinds deleted before are gone.
+	// +kubebuilder:validation:Optional
+	WaitForTermination bool `json:"waitForTermination,omitempty"`
+}
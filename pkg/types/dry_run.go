@@ -0,0 +1,41 @@
+package types
+
+// DiffAction describes how an install would change a single resource.
+type DiffAction string
+
+const (
+	// DiffActionCreate signifies a resource not yet present in the target cluster.
+	DiffActionCreate DiffAction = "Create"
+	// DiffActionUpdate signifies a resource that would be changed.
+	DiffActionUpdate DiffAction = "Update"
+	// DiffActionDelete signifies a resource that would be pruned, as it is no longer part of the manifest.
+	DiffActionDelete DiffAction = "Delete"
+)
+
+// +k8s:deepcopy-gen=true
+
+// ResourceDiff describes a change of a single resource that an install would apply.
+type ResourceDiff struct {
+	// Action describes how the resource would change
+	Action DiffAction `json:"action"`
+	// APIVersion is the apiVersion of the resource
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the resource
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the resource
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the resource
+	Name string `json:"name"`
+	// Patch is the JSON merge patch from the current to the resulting state of an updated resource
+	// +kubebuilder:validation:Optional
+	Patch string `json:"patch,omitempty"`
+}
+
+// DryRunResult is the rendered preview of an install.
+type DryRunResult struct {
+	// Manifest is the rendered manifest of the install
+	Manifest string
+	// Diffs lists all resources that would be created, updated or pruned
+	Diffs []ResourceDiff
+}
@@ -0,0 +1,16 @@
+package types
+
+import "context"
+
+// KeyEncrypter wraps the data encryption keys used for envelope encryption of data stored in the target cluster,
+// e.g. the rendered manifests of the release history. Implementations can delegate to a KMS plugin
+// or use a local key. To rotate keys, an implementation encrypts with its current key,
+// while it is still able to decrypt with previous keys.
+type KeyEncrypter interface {
+	// KeyID identifies the key currently used by Encrypt.
+	KeyID() string
+	// Encrypt wraps the passed data encryption key with the current key.
+	Encrypt(ctx context.Context, dataKey []byte) ([]byte, error)
+	// Decrypt unwraps the passed data encryption key with the key identified by keyID.
+	Decrypt(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error)
+}
@@ -0,0 +1,42 @@
+package types
+
+// ErrorClassification distinguishes errors that resolve on retry from errors that require intervention.
+// +kubebuilder:validation:Enum=Transient;Terminal;Unknown
+type ErrorClassification string
+
+const (
+	// ErrorClassificationTransient signifies an error expected to resolve on retry, e.g. a network timeout.
+	ErrorClassificationTransient ErrorClassification = "Transient"
+	// ErrorClassificationTerminal signifies an error that will persist until the install or cluster is changed,
+	// e.g. a policy violation or an invalid resource.
+	ErrorClassificationTerminal ErrorClassification = "Terminal"
+	// ErrorClassificationUnknown signifies an error that could not be classified.
+	ErrorClassificationUnknown ErrorClassification = "Unknown"
+)
+
+// ClassifiedError is implemented by errors that know their ErrorClassification.
+type ClassifiedError interface {
+	error
+	Classification() ErrorClassification
+}
+
+// severity orders classifications, so that the most severe classification of multiple errors can be reported.
+func (c ErrorClassification) severity() int {
+	switch c {
+	case ErrorClassificationTerminal:
+		return 3
+	case ErrorClassificationUnknown:
+		return 2
+	case ErrorClassificationTransient:
+		return 1
+	}
+	return 0
+}
+
+// MoreSevere returns the more severe of both classifications, Terminal over Unknown over Transient.
+func (c ErrorClassification) MoreSevere(other ErrorClassification) ErrorClassification {
+	if other.severity() > c.severity() {
+		return other
+	}
+	return c
+}
@@ -43,6 +43,11 @@ func (fe *ParsedFile) GetContent() string {
 	return fe.content
 }
 
+// Unwrap returns the error encountered during processing of the file.
+func (fe *ParsedFile) Unwrap() error {
+	return fe.err
+}
+
 // GetRawError returns the raw error during parsing of the file.
 func (fe *ParsedFile) GetRawError() error {
 	return fe.err
@@ -0,0 +1,23 @@
+package types
+
+import "fmt"
+
+// HealthIssue describes an applied resource of an install, which is degraded after the install succeeded,
+// e.g. a Deployment with fewer ready replicas than desired or an unbound PersistentVolumeClaim.
+type HealthIssue struct {
+	// Kind is the kind of the degraded resource
+	Kind string
+	// Namespace is the namespace of the degraded resource
+	Namespace string
+	// Name is the name of the degraded resource
+	Name string
+	// Reason describes how the resource is degraded, e.g. "1/3 replicas ready"
+	Reason string
+}
+
+func (i HealthIssue) String() string {
+	if i.Namespace == "" {
+		return fmt.Sprintf("%s/%s: %s", i.Kind, i.Name, i.Reason)
+	}
+	return fmt.Sprintf("%s/%s/%s: %s", i.Kind, i.Namespace, i.Name, i.Reason)
+}
@@ -0,0 +1,30 @@
+package types
+
+// +k8s:deepcopy-gen=true
+
+// HealthRule evaluates the health of applied resources of an install of a kind by a CEL expression, in addition
+// to the built-in health checks, e.g. for custom resources reporting their readiness in an operator-specific way.
+type HealthRule struct {
+	// APIVersion of the evaluated resources, e.g. "apps/v1". Only the group is matched, so that the rule
+	// also applies to resources rendered with another version of the group.
+	// +kubebuilder:validation:MinLength=1
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the evaluated resources, e.g. "Deployment"
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+
+	// Name of the evaluated resource, all resources of the kind are evaluated if not set
+	// +kubebuilder:validation:Optional
+	Name string `json:"name,omitempty"`
+
+	// Expression is a CEL expression evaluating to true if the resource, which is available as "object",
+	// is healthy, e.g. "object.status.readyReplicas == object.spec.replicas"
+	// +kubebuilder:validation:MinLength=1
+	Expression string `json:"expression"`
+
+	// Message describes the issue of a resource, for which Expression evaluates to false.
+	// If not set, the expression is reported.
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,18 @@
+package types
+
+// HookPolicy determines how the Helm hooks of the chart of an install are handled.
+// +kubebuilder:validation:Enum=Skip;Run
+type HookPolicy string
+
+const (
+	// HookPolicySkip renders charts without their hooks, like `helm install --no-hooks`.
+	HookPolicySkip HookPolicy = "Skip"
+	// HookPolicyRun runs the pre-install, post-install, pre-upgrade and post-upgrade hooks of the chart,
+	// waiting for their completion and respecting their hook-delete-policy.
+	HookPolicyRun HookPolicy = "Run"
+)
+
+// RunsHooks indicates if hooks are run. Without a policy, they are skipped.
+func (p HookPolicy) RunsHooks() bool {
+	return p == HookPolicyRun
+}
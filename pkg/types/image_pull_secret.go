@@ -0,0 +1,14 @@
+package types
+
+// ImagePullSecret references the secret used to pull the images of all rendered workloads, e.g. from a
+// private registry mirroring the images of a module. If DockerConfigJSON is set, the secret is rendered
+// alongside the workloads in every namespace they are installed to.
+type ImagePullSecret struct {
+	// Name of the secret. Defaults to DefaultImagePullSecretName if DockerConfigJSON is set.
+	Name string
+	// DockerConfigJSON is the content of a docker config file, with the credentials for each registry in "auths".
+	DockerConfigJSON []byte
+}
+
+// DefaultImagePullSecretName is the name of the rendered secret of an ImagePullSecret without a name.
+const DefaultImagePullSecretName = "module-image-pull-secret"
@@ -2,11 +2,14 @@ package types
 
 import (
 	"context"
+	"time"
 
 	"helm.sh/helm/v3/pkg/kube"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -19,6 +22,12 @@ type ManifestClient interface {
 	// Install transforms and applies resources based on InstallInfo.
 	Install(manifest string, deployInfo *InstallInfo, transforms []ObjectTransform, postRuns []PostRun) (bool, error)
 
+	// Upgrade transforms and applies resources based on InstallInfo as an upgrade of the previous manifest.
+	// Resources of the previous manifest, which are no longer part of the manifest, are removed.
+	// If previous is empty, Upgrade is equivalent to Install.
+	Upgrade(previous, manifest string, deployInfo *InstallInfo, transforms []ObjectTransform,
+		postRuns []PostRun) (bool, error)
+
 	// Uninstall transforms and applies resources based on InstallInfo.
 	Uninstall(manifest string, deployInfo *InstallInfo, transforms []ObjectTransform, postRuns []PostRun) (bool, error)
 
@@ -43,12 +52,19 @@ type ManifestClient interface {
 
 	// GetClusterInfo returns Client and REST config for the target cluster
 	GetClusterInfo() (ClusterInfo, error)
+
+	// InvalidateOnServerVersionChange invalidates the cached discovery data of the target cluster,
+	// if the version of its API server changed. The version is checked at most once per interval.
+	InvalidateOnServerVersionChange(interval time.Duration) (bool, error)
+
+	// GetServerVersion returns the version of the API server of the target cluster
+	GetServerVersion() (*version.Info, error)
 }
 
 // RefTypeMetadata specifies the type of installation specification
 // that could be provided as part of a custom resource.
 // This time is used in codec to successfully decode from raw extensions.
-// +kubebuilder:validation:Enum=helm-chart;oci-ref;"kustomize";""
+// +kubebuilder:validation:Enum=helm-chart;oci-ref;"kustomize";"archive";"git";""
 type RefTypeMetadata string
 
 func (r RefTypeMetadata) NotEmpty() bool {
@@ -59,6 +75,8 @@ const (
 	HelmChartType RefTypeMetadata = "helm-chart"
 	OciRefType    RefTypeMetadata = "oci-ref"
 	KustomizeType RefTypeMetadata = "kustomize"
+	ArchiveType   RefTypeMetadata = "archive"
+	GitType       RefTypeMetadata = "git"
 	NilRefType    RefTypeMetadata = ""
 )
 
@@ -90,10 +108,21 @@ type ImageSpec struct {
 	// +kubebuilder:validation:Optional
 	Ref string `json:"ref"`
 
+	// Tag is the tag of the image the install follows instead of Ref. Whenever the install is prepared, the layer
+	// of MediaType, or the only layer, of the image the tag points to is installed, and registry push notifications
+	// of another image to the tag enqueue the Manifest. It is only followed by the sources of installs.
+	// +kubebuilder:validation:Optional
+	Tag string `json:"tag,omitempty"`
+
 	// Type defines the chart as "oci-ref"
 	// +kubebuilder:validation:Optional
 	Type RefTypeMetadata `json:"type"`
 
+	// MediaType is the media type of the layer in the OCI descriptor, which determines how the layer is unpacked.
+	// If not set, it is detected from the content of the layer.
+	// +kubebuilder:validation:Optional
+	MediaType string `json:"mediaType,omitempty"`
+
 	// CredSecretSelector is on optional field, for OCI image saved in private registry,
 	// use it to indicate the secret which contains registry credentials,
 	// must exist in the namespace same as manifest
@@ -112,11 +141,55 @@ type HelmChartSpec struct {
 	// +kubebuilder:validation:Optional
 	ChartName string `json:"chartName"`
 
+	// Version is an exact version or a semver range of the chart, e.g. ">=1.2.0 <2.0.0", where the latest
+	// matching version is used. If not set, the latest stable version is used.
+	// +kubebuilder:validation:Optional
+	Version string `json:"version,omitempty"`
+
+	// CredSecretSelector is an optional field, for charts of private repositories,
+	// use it to indicate the secret which contains the username and password for basic auth
+	// and the CA or client certificate for TLS, must exist in the namespace same as manifest
+	// +kubebuilder:validation:Optional
+	CredSecretSelector *metav1.LabelSelector `json:"credSecretSelector,omitempty"`
+
+	// Digest is the sha256 digest of the chart archive, e.g. "sha256:<hex>", which the downloaded archive
+	// has to match, regardless of the digest published in the index of the repository
+	// +kubebuilder:validation:Optional
+	Digest string `json:"digest,omitempty"`
+
+	// Provenance verifies the provenance file published next to the chart archive, as signed by helm package --sign
+	// +kubebuilder:validation:Optional
+	Provenance *ProvenanceVerification `json:"provenance,omitempty"`
+
 	// Type defines the chart as "helm-chart"
 	// +kubebuilder:validation:Optional
 	Type RefTypeMetadata `json:"type"`
 }
 
+// +k8s:deepcopy-gen=true
+
+// ProvenanceVerification verifies the Helm provenance file of a chart against a keyring.
+type ProvenanceVerification struct {
+	// KeyringSecretRef selects the key of a Secret in the namespace of the Manifest holding the public keyring,
+	// binary or ASCII armored, one of whose keys has to sign the provenance file of the chart
+	KeyringSecretRef corev1.SecretKeySelector `json:"keyringSecretRef"`
+}
+
+// ChartVerification records how the archive of a chart was verified.
+type ChartVerification struct {
+	// Digest is the verified sha256 digest of the chart archive, e.g. "sha256:<hex>"
+	Digest string
+	// SignedBy is the identity of the key, which signed the verified provenance file of the chart
+	SignedBy string
+}
+
+func (v ChartVerification) String() string {
+	if v.SignedBy == "" {
+		return "digest " + v.Digest + " verified"
+	}
+	return "digest " + v.Digest + " verified, provenance signed by " + v.SignedBy
+}
+
 // KustomizeSpec defines the specification for a Kustomize specification.
 type KustomizeSpec struct {
 	// Path defines the Kustomize local path
@@ -130,6 +203,68 @@ type KustomizeSpec struct {
 	Type RefTypeMetadata `json:"type"`
 }
 
+// +k8s:deepcopy-gen=true
+
+// ArchiveSpec defines a tarball of a chart or of a directory of manifests, or a single manifest file,
+// which is downloaded from an HTTP(S) URL or from an S3 or GCS bucket.
+type ArchiveSpec struct {
+	// URL of the archive, e.g. "https://example.com/charts/redis-1.2.0.tgz", "s3://bucket/redis-1.2.0.tgz"
+	// or "gs://bucket/redis-1.2.0.tgz"
+	URL string `json:"url"`
+
+	// Digest is the sha256 digest of the archive, e.g. "sha256:<hex>", which the downloaded archive has to match.
+	// Archives with a digest are downloaded only once, all others again once their cached download expired.
+	// +kubebuilder:validation:Optional
+	Digest string `json:"digest,omitempty"`
+
+	// Region of the S3 bucket, "us-east-1" if not set
+	// +kubebuilder:validation:Optional
+	Region string `json:"region,omitempty"`
+
+	// Endpoint of an S3 compatible storage, e.g. "https://minio.example.com", whose buckets are addressed
+	// path-style. If not set, the endpoint of AWS S3 in Region is used.
+	// +kubebuilder:validation:Optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// CredSecretSelector selects the secret with the credentials of the archive, which must exist in the namespace
+	// of the Manifest. HTTP(S) downloads use basic auth, a bearer token and the CA or client certificate,
+	// S3 and GCS downloads an access key signing the request or a bearer token
+	// +kubebuilder:validation:Optional
+	CredSecretSelector *metav1.LabelSelector `json:"credSecretSelector,omitempty"`
+
+	// Type defines the chart as "archive"
+	// +kubebuilder:validation:Optional
+	Type RefTypeMetadata `json:"type"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// GitSpec defines a chart or a directory of manifests in a Git repository, which is installed without packaging.
+type GitSpec struct {
+	// URL of the repository, e.g. "https://github.com/kyma-project/module-manager.git"
+	// or "ssh://git@github.com/kyma-project/module-manager.git"
+	URL string `json:"url"`
+
+	// Revision is a commit, tag or branch of the repository. Commits are given as their full SHA-1 and pin the
+	// revision, tags and branches are resolved again once their cached commit expired.
+	// If not set, the default branch of the repository is used.
+	// +kubebuilder:validation:Optional
+	Revision string `json:"revision,omitempty"`
+
+	// Path of the chart or the manifests in the repository, the root of the repository if not set
+	// +kubebuilder:validation:Optional
+	Path string `json:"path,omitempty"`
+
+	// CredSecretSelector selects the secret with the credentials of the repository, which must exist in the namespace
+	// of the Manifest. HTTPS repositories use basic auth, SSH repositories the private key and known hosts
+	// +kubebuilder:validation:Optional
+	CredSecretSelector *metav1.LabelSelector `json:"credSecretSelector,omitempty"`
+
+	// Type defines the chart as "git"
+	// +kubebuilder:validation:Optional
+	Type RefTypeMetadata `json:"type"`
+}
+
 // ManifestResources holds a collection of objects, so that we can filter / sequence them.
 type ManifestResources struct {
 	Items []*unstructured.Unstructured
@@ -174,10 +309,45 @@ type InstallInfo struct {
 	Ctx context.Context //nolint:containedctx
 	// CheckFn returns a boolean indicating ready state based on custom checks
 	CheckFn CheckFnType
+	// Transforms are declarative transformations executed after any programmatic ObjectTransform
+	Transforms []ObjectTransform
 	// CheckReadyStates indicates if native resources should be checked for ready states
 	CheckReadyStates bool
 	// UpdateRepositories indicates if repositories should be updated
 	UpdateRepositories bool
+	// TrackInventory indicates if applied resources should be recorded in an inventory in the target cluster
+	TrackInventory bool
+	// Scanners inspect rendered resources before they are applied
+	Scanners []ContentScanner
+	// ScanMode determines if findings of Scanners block the installation
+	ScanMode ScanMode
+	// Timeout limits the duration of a single operation on the install, zero disables the limit
+	Timeout time.Duration
+	// ReleaseHistoryLimit is the number of release revisions recorded in the target cluster, zero disables the history
+	ReleaseHistoryLimit int
+	// RollbackWindow is the duration a new release revision may take to become ready,
+	// before it is rolled back to the last deployed revision. Zero disables automatic rollbacks.
+	RollbackWindow time.Duration
+	// ReleaseEncrypter envelope encrypts the rendered manifests recorded in the release history, nil disables it
+	ReleaseEncrypter KeyEncrypter
+	// Release is the state of the release Helm charts are rendered for, it is determined from the release history.
+	// If it is nil, charts are rendered as an upgrade of the first revision.
+	Release *ReleaseState
+	// CRDPolicy determines if the CustomResourceDefinitions of the install are removed on uninstall
+	CRDPolicy CRDPolicy
+	// Migrations are the pending migrations of previously applied resources, applied in order before installing
+	Migrations []Migration
+	// ServerVersionCheckInterval is the interval in which the API server version of the target cluster is checked
+	// for upgrades, which invalidate the cached discovery data of the cluster. Zero disables the check.
+	ServerVersionCheckInterval time.Duration
+	// KubernetesVersions is a semantic version constraint for the versions of target clusters supported by the install,
+	// e.g. ">=1.24.0 <1.27.0". Installs on unsupported clusters are blocked, an empty constraint allows all versions.
+	KubernetesVersions string
+	// PartialInstallPolicy determines how resources left over by a failed install attempt are handled on retry.
+	// Failed attempts are only recorded if TrackInventory is enabled.
+	PartialInstallPolicy PartialInstallPolicy
+	// SecretRotation restarts the workloads consuming rendered Secrets whose data changed, nil disables it
+	SecretRotation *SecretRotationSpec
 }
 
 // ChartInfo defines helm chart information.
@@ -188,6 +358,53 @@ type ChartInfo struct {
 	ChartName   string
 	ReleaseName string
 	Flags       ChartFlags
+	// NamespacePolicy determines how the target namespace of the install is managed
+	NamespacePolicy NamespaceCreatePolicy
+	// ConflictPolicy determines how field ownership conflicts are resolved on server-side apply,
+	// the ownership of conflicting fields is forced by default
+	ConflictPolicy ConflictPolicy
+	// MissingAPIPolicy determines how rendered resources of APIs not served by the target cluster are handled,
+	// the install fails by default
+	MissingAPIPolicy MissingAPIPolicy
+	// UpgradePolicy restricts the changes of the chart version compared to InstalledVersion,
+	// all changes are allowed by default
+	UpgradePolicy *UpgradePolicy
+	// InstalledVersion is the chart version installed last by the install
+	InstalledVersion string
+	// Channel is the channel of a ModuleCatalog the chart version was resolved from,
+	// nil if the install does not follow a channel
+	Channel *ResolvedChannel
+	// Image is the image the followed tag of an OCI source resolved to, nil if the install does not follow a tag
+	Image *ResolvedImage
+	// SkipResources matches rendered resources, which are neither applied nor recorded in the inventory,
+	// without deleting them if they were applied before
+	SkipResources []ResourcePattern
+	// HookPolicy determines if the Helm hooks of the chart are run, they are skipped by default
+	HookPolicy HookPolicy
+	// DeletionPolicy configures how resources are deleted on uninstall, nil deletes them at once in the background
+	DeletionPolicy *DeletionPolicy
+	// ReleaseStorage configures where the release history is stored, nil stores it as a Secret next to the inventory
+	ReleaseStorage *ReleaseStorage
+	// Backup configures the snapshot of the live resources taken before an upgrade overwrites them,
+	// nil disables snapshots
+	Backup *BackupPolicy
+	// Verification records how the chart archive was verified, nil if it was not verified
+	Verification *ChartVerification
+	// WaitFor lists objects in the target cluster, which have to satisfy their expectation before resources
+	// are applied
+	WaitFor []WaitFor
+	// HealthRules evaluate the health of applied resources by CEL expressions during consistency checks
+	HealthRules []HealthRule
+	// AdoptExisting labels rendered resources, which already exist in the target cluster without being managed by
+	// the operator, as owned by the install on its first apply, instead of failing with ErrResourceExists
+	AdoptExisting bool
+	// Applied indicates if resources of the install were applied before, so that existing resources are its own
+	Applied bool
+	// TargetNamespace overrides the namespace of all rendered namespaced resources, including those rendered
+	// into explicit namespaces, unless moved by NamespaceMappings. It is set as Namespace of the config flags
+	TargetNamespace string
+	// NamespaceMappings move rendered namespaced resources to other namespaces than the target namespace
+	NamespaceMappings []NamespaceMapping
 }
 
 // ResourceInfo represents additional resources.
@@ -199,3 +416,38 @@ type ResourceInfo struct {
 	// Crds represents a set of additional custom resource definitions to be installed
 	Crds []*v1.CustomResourceDefinition
 }
+
+// HelmRepositoryCredentials authenticate the requests to a Helm repository.
+type HelmRepositoryCredentials struct {
+	// Username and Password are sent as basic auth, if set
+	Username string
+	Password string
+	// CAData is a PEM encoded CA bundle trusted in addition to the system CAs
+	CAData []byte
+	// CertData and KeyData are a PEM encoded client certificate and key for TLS client authentication
+	CertData []byte
+	KeyData  []byte
+}
+
+// GitCredentials authenticate the fetches of a Git repository.
+type GitCredentials struct {
+	// Username and Password authenticate HTTPS repositories with basic auth, the password can be an access token
+	Username string
+	Password string
+	// SSHPrivateKey authenticates SSH repositories, whose host keys have to be listed in KnownHosts
+	SSHPrivateKey []byte
+	KnownHosts    []byte
+}
+
+// ArchiveCredentials authenticate the download of an archive.
+type ArchiveCredentials struct {
+	// HelmRepositoryCredentials authenticate HTTP(S) downloads with basic auth and TLS
+	HelmRepositoryCredentials
+	// Token is sent as bearer token, e.g. an OAuth 2.0 access token for GCS
+	Token string
+	// AccessKeyID and SecretAccessKey sign downloads from S3 or GCS with AWS Signature Version 4,
+	// for GCS they are an HMAC key. SessionToken is sent along for temporary credentials.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
@@ -0,0 +1,20 @@
+package types
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Migration rewrites the resources previously applied for a Manifest, when the conventions used to manage them
+// change between versions of the operator, e.g. to rename a label scheme.
+// Each Migration is applied once per Manifest and recorded in its status afterwards.
+// As a Manifest is only recorded as migrated once all of its installs are migrated,
+// Migrate should not change objects which were migrated already.
+type Migration struct {
+	// Version uniquely identifies the migration, e.g. the version of the operator introducing it
+	Version string
+	// Migrate rewrites the passed object, as currently applied in the target cluster, in place
+	// and indicates if it was changed and needs to be updated
+	Migrate func(ctx context.Context, obj *unstructured.Unstructured) (bool, error)
+}
@@ -1,22 +1,80 @@
 package types
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
+	"strings"
 )
 
+// maxMultiErrorMessages bounds the distinct messages rendered by MultiError.Error,
+// so that failures of many resources do not exceed the size of status conditions.
+const maxMultiErrorMessages = 10
+
+// NewMultiError returns a MultiError of all non-nil errors.
 func NewMultiError(errs []error) *MultiError {
-	return &MultiError{Errs: errs}
+	nonNil := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+	return &MultiError{Errs: nonNil}
 }
 
+// MultiError aggregates the errors of operations on multiple resources. Like errors joined with errors.Join,
+// it matches errors.Is and errors.As if any of its errors matches, so that every error keeps its classification.
 type MultiError struct {
 	Errs []error
 }
 
-func (m MultiError) Error() string {
-	buf := &bytes.Buffer{}
+// Error renders the distinct messages of the errors in their order, each repeated message is rendered once
+// with the number of its occurrences, and messages exceeding maxMultiErrorMessages are only counted.
+func (m *MultiError) Error() string {
+	messages := make([]string, 0, len(m.Errs))
+	occurrences := make(map[string]int, len(m.Errs))
+	for _, err := range m.Errs {
+		message := err.Error()
+		if occurrences[message] == 0 {
+			messages = append(messages, message)
+		}
+		occurrences[message]++
+	}
+
+	rendered := make([]string, 0, maxMultiErrorMessages+1)
+	for i, message := range messages {
+		if i == maxMultiErrorMessages {
+			rendered = append(rendered, fmt.Sprintf("and %d more errors", len(messages)-maxMultiErrorMessages))
+			break
+		}
+		if occurrences[message] > 1 {
+			message = fmt.Sprintf("%s (%d times)", message, occurrences[message])
+		}
+		rendered = append(rendered, message)
+	}
+	return strings.Join(rendered, "; ")
+}
+
+// Unwrap returns the aggregated errors, which are traversed by errors.Is and errors.As since Go 1.20.
+func (m *MultiError) Unwrap() []error {
+	return m.Errs
+}
+
+// Is reports whether any of the aggregated errors matches target.
+func (m *MultiError) Is(target error) bool {
+	for _, err := range m.Errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the aggregated errors matching target.
+func (m *MultiError) As(target any) bool {
 	for _, err := range m.Errs {
-		_, _ = fmt.Fprintf(buf, "%v\n", err.Error())
+		if errors.As(err, target) {
+			return true
+		}
 	}
-	return buf.String()
+	return false
 }
@@ -0,0 +1,44 @@
+package types
+
+// NamespaceCreatePolicy determines how the target namespace of an install is managed.
+// +kubebuilder:validation:Enum=CreateIfMissing;MustExist;CreateAndDelete
+type NamespaceCreatePolicy string
+
+const (
+	// NamespaceCreateIfMissing creates the namespace if it does not exist and never deletes it.
+	NamespaceCreateIfMissing NamespaceCreatePolicy = "CreateIfMissing"
+	// NamespaceMustExist never creates the namespace and fails the install if it does not exist,
+	// e.g. for namespaces shared between modules.
+	NamespaceMustExist NamespaceCreatePolicy = "MustExist"
+	// NamespaceCreateAndDelete creates the namespace if it does not exist and deletes it on uninstall,
+	// unless it existed before the install.
+	NamespaceCreateAndDelete NamespaceCreatePolicy = "CreateAndDelete"
+)
+
+// CreatesNamespace indicates if a missing namespace is created. Without a policy,
+// the legacy CreateNamespace flag of the install configuration decides.
+func (p NamespaceCreatePolicy) CreatesNamespace(createNamespaceFlag bool) bool {
+	switch p {
+	case NamespaceCreateIfMissing, NamespaceCreateAndDelete:
+		return true
+	case NamespaceMustExist:
+		return false
+	default:
+		return createNamespaceFlag
+	}
+}
+
+// +k8s:deepcopy-gen=true
+
+// NamespaceMapping moves rendered namespaced resources matching any of its patterns to its namespace,
+// e.g. to split the resources of a single chart between a system and a workload namespace.
+type NamespaceMapping struct {
+	// Namespace the matching resources are moved to. It is not created by the install.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Namespace string `json:"namespace"`
+
+	// Resources are the patterns of the moved resources.
+	// +kubebuilder:validation:MinItems=1
+	Resources []ResourcePattern `json:"resources"`
+}
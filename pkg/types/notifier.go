@@ -0,0 +1,9 @@
+package types
+
+// TransitionNotifier is notified about state transitions of reconciled custom resources, so that external
+// systems, e.g. inventory databases or UIs, can track the lifecycle of modules without watching the API server.
+// Err is the error causing a transition to StateError, nil otherwise.
+// Notifications are delivered synchronously during reconciliation and should therefore be short.
+type TransitionNotifier interface {
+	OnTransition(obj BaseCustomObject, oldState, newState State, err error)
+}
@@ -0,0 +1,120 @@
+package types
+
+// RetryableError is implemented by errors that know if the failed operation succeeds on retry.
+type RetryableError interface {
+	error
+	IsRetryable() bool
+}
+
+//nolint:gochecknoglobals
+var (
+	// ErrChartNotFound signifies that the chart of an install could not be located in its repository or path.
+	ErrChartNotFound = &OperationError{Reason: "ChartNotFound", Message: "chart not found"}
+	// ErrRenderFailure signifies that the resources of an install could not be rendered, e.g. due to invalid values.
+	ErrRenderFailure = &OperationError{Reason: "RenderFailure", Message: "rendering resources failed"}
+	// ErrRemoteUnreachable signifies that the API server of the target cluster could not be reached.
+	ErrRemoteUnreachable = &OperationError{
+		Reason: "RemoteUnreachable", Message: "target cluster unreachable", Retryable: true,
+	}
+	// ErrUnsupportedKubernetesVersion signifies that the version of the target cluster is not supported by a module.
+	// It is retried, as the cluster could be upgraded to a supported version.
+	ErrUnsupportedKubernetesVersion = &OperationError{
+		Reason: "UnsupportedKubernetesVersion", Message: "Kubernetes version not supported", Retryable: true,
+	}
+	// ErrInsufficientCapacity signifies that the rendered workloads of an install request more resources than
+	// left by the ResourceQuotas or the nodes of the target cluster. It is retried, as capacity could be freed.
+	ErrInsufficientCapacity = &OperationError{
+		Reason: "InsufficientCapacity", Message: "insufficient capacity in target cluster", Retryable: true,
+	}
+	// ErrForbidden signifies that the identity used for the target cluster lacks permissions for an operation.
+	ErrForbidden = &OperationError{Reason: "Forbidden", Message: "operation forbidden in target cluster"}
+	// ErrProfileNotFound signifies that the chart of an install does not bundle the values of the selected profile.
+	ErrProfileNotFound = &OperationError{Reason: "ProfileNotFound", Message: "profile not found"}
+	// ErrFieldOwnershipConflict signifies that fields of applied resources are owned by another field manager,
+	// while the install does not force their ownership. It is retried, as the other manager could release them.
+	ErrFieldOwnershipConflict = &OperationError{
+		Reason: "FieldOwnershipConflict", Message: "fields owned by another manager", Retryable: true,
+	}
+	// ErrPrerequisitesNotMet signifies that rendered resources of an install require APIs not served by the target
+	// cluster, e.g. of CustomResourceDefinitions of another module. It is retried, as the APIs could be installed.
+	ErrPrerequisitesNotMet = &OperationError{
+		Reason: "PrerequisitesNotMet", Message: "required APIs not served by target cluster", Retryable: true,
+	}
+	// ErrInvalidName signifies that a name configured for an install, e.g. its target namespace,
+	// is not a valid DNS-1123 name, so that it would be rejected by the target cluster.
+	ErrInvalidName = &OperationError{Reason: "InvalidName", Message: "invalid name"}
+	// ErrUpgradeNotAllowed signifies that the chart version of an install violates its UpgradePolicy,
+	// e.g. by skipping a mandatory version. It is only retried once the spec changes.
+	ErrUpgradeNotAllowed = &OperationError{Reason: "UpgradeNotAllowed", Message: "upgrade not allowed"}
+	// ErrChartVerificationFailed signifies that the archive of a chart does not match the digest declared for it,
+	// or that its provenance file is missing or not signed by a key of the keyring.
+	ErrChartVerificationFailed = &OperationError{Reason: "ChartVerificationFailed", Message: "chart verification failed"}
+	// ErrSecretValueNotFound signifies that a Secret or key referenced by a value of an install is missing.
+	// It is retried, as the Secret could be created later.
+	ErrSecretValueNotFound = &OperationError{
+		Reason: "SecretValueNotFound", Message: "secret value not found", Retryable: true,
+	}
+	// ErrResourceExists signifies that rendered resources of an install, which was not applied before, already exist
+	// in the target cluster without being managed by it, e.g. as they were installed manually. It is retried,
+	// as the resources could be removed or the install could be allowed to adopt them.
+	ErrResourceExists = &OperationError{
+		Reason: "ResourceExists", Message: "resources already exist", Retryable: true,
+	}
+	// ErrNamespaceConflict signifies that the namespace mappings of an install move a rendered resource
+	// to several namespaces, or several resources of the same kind and name to the same namespace.
+	ErrNamespaceConflict = &OperationError{Reason: "NamespaceConflict", Message: "conflicting namespace mappings"}
+	// ErrChannelNotResolved signifies that the ModuleCatalog referenced by an install does not resolve its channel
+	// to a version. It is retried, as the catalog could be created or discover a matching version later.
+	ErrChannelNotResolved = &OperationError{
+		Reason: "ChannelNotResolved", Message: "channel not resolved", Retryable: true,
+	}
+	// ErrBackupFailed signifies that the snapshot of the live resources of an install could not be stored before
+	// an upgrade, so that the upgrade is not applied. It is retried, as the storage could become writable.
+	ErrBackupFailed = &OperationError{Reason: "BackupFailed", Message: "backup before upgrade failed", Retryable: true}
+)
+
+// OperationError is a typed error of an operation on an install with a human-readable reason.
+// The exported Err* values are prototypes, which are matched with errors.Is and wrap causes with Wrap.
+type OperationError struct {
+	// Reason is a CamelCase identifier of the error, e.g. for condition reasons
+	Reason string
+	// Message describes the error in a human-readable way
+	Message   string
+	Retryable bool
+	Err       error
+}
+
+// Wrap returns a copy of the OperationError caused by err.
+func (e *OperationError) Wrap(err error) error {
+	wrapped := *e
+	wrapped.Err = err
+	return &wrapped
+}
+
+func (e *OperationError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches OperationErrors of the same Reason, independent of their cause.
+func (e *OperationError) Is(target error) bool {
+	operationErr, isOperationErr := target.(*OperationError)
+	return isOperationErr && operationErr.Reason == e.Reason
+}
+
+func (e *OperationError) IsRetryable() bool {
+	return e.Retryable
+}
+
+func (e *OperationError) Classification() ErrorClassification {
+	if e.Retryable {
+		return ErrorClassificationTransient
+	}
+	return ErrorClassificationTerminal
+}
@@ -0,0 +1,21 @@
+package types
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen=true
+
+// SelectedOverride is a block of values, which is only applied to an install if its selector matches
+// the labels of the runtime the install targets, e.g. to configure installs per environment from a single spec.
+type SelectedOverride struct {
+	// Selector is matched against the runtime labels of the install, which are the labels of its Manifest
+	// and operator.kyma-project.io/remote set to "true" or "false". An empty selector matches all runtimes.
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// Values are merged into the values of the install if the selector matches. String values may reference
+	// fields of the resource of the Manifest, e.g. "{{ .spec.resources.profile }}".
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Values apiextensionsv1.JSON `json:"values"`
+}
@@ -0,0 +1,18 @@
+package types
+
+// PartialInstallPolicy determines how resources left over by a failed install attempt are handled on its retry.
+type PartialInstallPolicy string
+
+const (
+	// PartialInstallPolicyComplete applies all resources again over the leftovers of the failed attempt.
+	// Only leftovers that are neither part of the retried manifest nor of the last completed install are deleted.
+	PartialInstallPolicyComplete PartialInstallPolicy = "Complete"
+	// PartialInstallPolicyRollback deletes all resources created by the failed attempt, which are not part
+	// of the last completed install, before all resources are applied again.
+	PartialInstallPolicyRollback PartialInstallPolicy = "Rollback"
+)
+
+// RollsBack indicates if the leftovers of a failed attempt are deleted before the retry.
+func (p PartialInstallPolicy) RollsBack() bool {
+	return p == PartialInstallPolicyRollback
+}
@@ -0,0 +1,33 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// MissingAPIPolicy determines how rendered resources of APIs not served by the target cluster are handled,
+// e.g. ServiceMonitors on clusters without the monitoring.coreos.com API group.
+// +kubebuilder:validation:Enum=Fail;Skip
+type MissingAPIPolicy string
+
+const (
+	// MissingAPIPolicyFail fails the install with an ErrPrerequisitesNotMet naming the missing APIs,
+	// before any resource is applied.
+	MissingAPIPolicyFail MissingAPIPolicy = "Fail"
+	// MissingAPIPolicySkip applies all other resources and skips the resources of missing APIs.
+	MissingAPIPolicySkip MissingAPIPolicy = "Skip"
+)
+
+// MissingAPI describes a resource type of rendered resources, which is not served by the target cluster.
+type MissingAPI struct {
+	// GroupVersionKind is the type of the affected resources
+	GroupVersionKind schema.GroupVersionKind
+	// Resources are the names of the affected resources, prefixed by their namespace if they have one
+	Resources []string
+}
+
+func (a MissingAPI) String() string {
+	return fmt.Sprintf("%s required by %s", a.GroupVersionKind.String(), strings.Join(a.Resources, ", "))
+}
@@ -0,0 +1,72 @@
+package types
+
+// ReleaseStatus describes the state of a single revision of a release.
+type ReleaseStatus string
+
+const (
+	// ReleaseStatusPending signifies a revision that is applied, but not yet ready.
+	ReleaseStatusPending ReleaseStatus = "pending"
+	// ReleaseStatusDeployed signifies the revision that is currently applied and ready.
+	ReleaseStatusDeployed ReleaseStatus = "deployed"
+	// ReleaseStatusSuperseded signifies a revision that was replaced by a later deployed revision.
+	ReleaseStatusSuperseded ReleaseStatus = "superseded"
+	// ReleaseStatusFailed signifies a revision that did not become ready and was rolled back.
+	ReleaseStatusFailed ReleaseStatus = "failed"
+)
+
+// +k8s:deepcopy-gen=true
+
+// ReleaseRevision describes the latest revision of the release of an install.
+type ReleaseRevision struct {
+	// Name is the release name of the install
+	Name string `json:"name"`
+	// Revision is incremented for every applied change of the rendered resources, including rollbacks
+	Revision int `json:"revision"`
+	// Status of the revision
+	Status ReleaseStatus `json:"status"`
+	// RolledBackFrom is the failed revision, if this revision is the result of a rollback
+	// +kubebuilder:validation:Optional
+	RolledBackFrom int `json:"rolledBackFrom,omitempty"`
+}
+
+// ReleaseState is the state of a release exposed to Helm templates as .Release.Revision, .Release.IsInstall
+// and .Release.IsUpgrade. The first revision of a release is an install, all later revisions are upgrades.
+type ReleaseState struct {
+	// Revision is the revision the chart is rendered for
+	Revision int
+}
+
+// IsUpgrade returns true if the revision upgrades a previous revision of the release.
+func (s ReleaseState) IsUpgrade() bool {
+	return s.Revision > 1
+}
+
+// ReleaseStorageDriver determines the kind of resource the release history of an install is stored in.
+// +kubebuilder:validation:Enum=Secret;ConfigMap;Memory
+type ReleaseStorageDriver string
+
+const (
+	// ReleaseStorageSecret stores the release history in a Secret, as rendered manifests can contain sensitive values.
+	ReleaseStorageSecret ReleaseStorageDriver = "Secret"
+	// ReleaseStorageConfigMap stores the release history in a ConfigMap, e.g. if the operator may not write Secrets.
+	ReleaseStorageConfigMap ReleaseStorageDriver = "ConfigMap"
+	// ReleaseStorageMemory keeps the release history in the memory of the operator, e.g. for read-only target
+	// clusters. The history is lost on restarts of the operator.
+	ReleaseStorageMemory ReleaseStorageDriver = "Memory"
+)
+
+// +k8s:deepcopy-gen=true
+
+// ReleaseStorage configures where the release history of an install is stored in the target cluster.
+type ReleaseStorage struct {
+	// Namespace is the namespace the release history is stored in. If not set, it is stored next to the inventory.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+	// Driver determines the kind of resource the release history is stored in. If not set, a Secret is used.
+	// +kubebuilder:validation:Optional
+	Driver ReleaseStorageDriver `json:"driver,omitempty"`
+	// ServiceAccountName is a ServiceAccount in Namespace, which is impersonated for all requests to the release
+	// history, e.g. in multi-tenant target clusters. If not set, the identity of the install is used.
+	// +kubebuilder:validation:Optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
@@ -0,0 +1,30 @@
+package types
+
+import "k8s.io/apimachinery/pkg/util/intstr"
+
+// DefaultResilienceMinReplicas is the number of replicas from which Deployments are considered highly available,
+// if ResilienceSpec.MinReplicas is not set.
+const DefaultResilienceMinReplicas = 2
+
+// +k8s:deepcopy-gen=true
+
+// ResilienceSpec configures PodDisruptionBudgets and topology spread constraints, which are injected
+// for all rendered Deployments with at least MinReplicas replicas, unless the rendered resources define them already.
+type ResilienceSpec struct {
+	// MinReplicas is the number of replicas from which a Deployment is considered highly available
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:default:=2
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+
+	// MaxUnavailable is the number or percentage of pods of a Deployment the injected PodDisruptionBudget
+	// allows to be unavailable during voluntary disruptions. Defaults to 1.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XIntOrString
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// TopologySpreadKey is the node label the pods of a Deployment are spread across,
+	// e.g. "topology.kubernetes.io/zone". If not set, no topology spread constraints are injected.
+	// +kubebuilder:validation:Optional
+	TopologySpreadKey string `json:"topologySpreadKey,omitempty"`
+}
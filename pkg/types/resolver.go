@@ -12,4 +12,6 @@ type InstallationSpec struct {
 	ChartPath   string
 	ReleaseName string
 	ChartFlags
+	// ConflictPolicy overrides the ConflictPolicy of the reconciler for this installation, if it is set
+	ConflictPolicy ConflictPolicy
 }
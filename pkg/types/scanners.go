@@ -0,0 +1,66 @@
+package types
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ContentScanner inspects rendered resources before they are applied to the target cluster,
+// e.g. to detect embedded credentials or disallowed images.
+type ContentScanner interface {
+	// Name identifies the scanner in reported findings.
+	Name() string
+	// Scan returns all findings for the passed resources. An error signifies that scanning itself failed.
+	Scan(ctx context.Context, objects []*unstructured.Unstructured) ([]SecurityFinding, error)
+}
+
+// ScanMode determines how findings of a ContentScanner are handled.
+type ScanMode string
+
+const (
+	// ScanModeDisabled skips content scanning.
+	ScanModeDisabled ScanMode = ""
+	// ScanModeWarn records findings without blocking the installation.
+	ScanModeWarn ScanMode = "warn"
+	// ScanModeBlock records findings and blocks the installation if any finding is present.
+	ScanModeBlock ScanMode = "block"
+)
+
+// +k8s:deepcopy-gen=true
+
+// SecurityFinding describes a single issue detected by a ContentScanner in a rendered resource.
+type SecurityFinding struct {
+	// Scanner is the name of the ContentScanner reporting the finding
+	Scanner string `json:"scanner"`
+	// Rule identifies the check of the scanner that matched
+	Rule string `json:"rule"`
+	// Kind is the kind of the affected resource
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the affected resource
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the affected resource
+	Name string `json:"name"`
+	// Message describes the finding without disclosing the detected content
+	Message string `json:"message"`
+}
+
+func (f SecurityFinding) String() string {
+	return fmt.Sprintf("%s/%s %s/%s: %s", f.Scanner, f.Rule, f.Kind, f.Name, f.Message)
+}
+
+// SecurityFindingsError is returned if an installation is blocked because of findings of a ContentScanner.
+type SecurityFindingsError struct {
+	Findings []SecurityFinding
+}
+
+func (e *SecurityFindingsError) Error() string {
+	return fmt.Sprintf("installation blocked by %d security finding(s), first: %s",
+		len(e.Findings), e.Findings[0])
+}
+
+func (e *SecurityFindingsError) Classification() ErrorClassification {
+	return ErrorClassificationTerminal
+}
@@ -0,0 +1,23 @@
+package types
+
+import "fmt"
+
+// SchedulingIssue describes a rendered workload, whose pods cannot be scheduled on any node of the target cluster,
+// e.g. because no node matches its node selector or tolerates the taints of the nodes.
+type SchedulingIssue struct {
+	// Kind is the kind of the affected workload
+	Kind string
+	// Namespace is the namespace of the affected workload
+	Namespace string
+	// Name is the name of the affected workload
+	Name string
+	// Reason summarizes why the nodes of the target cluster do not fit, similar to the scheduler
+	Reason string
+}
+
+func (i SchedulingIssue) String() string {
+	if i.Namespace == "" {
+		return fmt.Sprintf("%s/%s: %s", i.Kind, i.Name, i.Reason)
+	}
+	return fmt.Sprintf("%s/%s/%s: %s", i.Kind, i.Namespace, i.Name, i.Reason)
+}
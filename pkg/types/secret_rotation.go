@@ -0,0 +1,22 @@
+package types
+
+import "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+// +k8s:deepcopy-gen=true
+
+// SecretRotationSpec configures the rollout of workloads consuming rendered Secrets, whose data changed,
+// e.g. rotated credentials. Consumers are restarted one after another, each once the previous one is ready.
+type SecretRotationSpec struct {
+	// Order lists consumers as "<kind>/<name>", e.g. "StatefulSet/database", which are restarted first
+	// and in the listed order. All other consumers are restarted afterwards in the order of the rendered resources.
+	// +kubebuilder:validation:Optional
+	Order []string `json:"order,omitempty"`
+}
+
+// SecretConsumer is a rendered workload referencing rendered Secrets of the same install.
+type SecretConsumer struct {
+	// Workload is the rendered workload
+	Workload *unstructured.Unstructured
+	// Checksum identifies the data of all Secrets referenced by the workload
+	Checksum string
+}
@@ -0,0 +1,17 @@
+package types
+
+import corev1 "k8s.io/api/core/v1"
+
+// +k8s:deepcopy-gen=true
+
+// SecretValue sets a chart value to the value of a key of a Secret, which is resolved whenever the install is
+// rendered, so that credentials are neither part of the spec nor of the config of an install.
+type SecretValue struct {
+	// Path is the dot-separated path of the chart value, e.g. "auth.password"
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+
+	// SecretKeyRef selects the key of a Secret in the namespace of the Manifest. If the Secret or key are missing,
+	// the install fails, unless the reference is optional.
+	SecretKeyRef corev1.SecretKeySelector `json:"secretKeyRef"`
+}
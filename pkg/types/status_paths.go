@@ -0,0 +1,15 @@
+package types
+
+// +k8s:deepcopy-gen=true
+
+// StatusPaths locate the state and conditions within the status of unstructured custom resources,
+// e.g. `.status.moduleState` for resources not reporting their state in `.status.state`.
+// Paths are JSONPath expressions of fields, such as `.status.state` or `{.status.state}`.
+type StatusPaths struct {
+	// State is the path of the state, `.status.state` if not set
+	// +kubebuilder:validation:Optional
+	State string `json:"state,omitempty"`
+	// Conditions is the path of the list of conditions, `.status.conditions` if not set
+	// +kubebuilder:validation:Optional
+	Conditions string `json:"conditions,omitempty"`
+}
@@ -0,0 +1,24 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// OperationTimeoutError is returned if an install, uninstall or consistency check exceeded its timeout.
+type OperationTimeoutError struct {
+	Timeout time.Duration
+	Err     error
+}
+
+func (m *OperationTimeoutError) Error() string {
+	return fmt.Sprintf("operation exceeded its timeout of %s and will be retried: %v", m.Timeout, m.Err)
+}
+
+func (m *OperationTimeoutError) Unwrap() error {
+	return m.Err
+}
+
+func (m *OperationTimeoutError) Classification() ErrorClassification {
+	return ErrorClassificationTransient
+}
@@ -0,0 +1,14 @@
+package types
+
+// TrackingMetadata configures the labels and annotations set on every rendered resource of an install,
+// so that applied resources can be queried by module and owner, e.g. to prune them or to detect drift.
+type TrackingMetadata struct {
+	// ModuleName is set as labels.ModuleName. If empty, the label of the base resource is used, if any.
+	ModuleName string
+	// PartOf is set as app.kubernetes.io/part-of, e.g. "Kyma". If empty, the label is not set.
+	PartOf string
+	// Labels are set on all resources, without overriding their labels or the tracking labels
+	Labels map[string]string
+	// Annotations are set on all resources, without overriding their annotations
+	Annotations map[string]string
+}
@@ -0,0 +1,107 @@
+package types
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// TransformType determines the post-render operation executed by a TransformSpec.
+// +kubebuilder:validation:Enum=Labels;Annotations;Namespace;ImageRegistry;StrategicMergePatch;JSON6902Patch
+type TransformType string
+
+const (
+	// LabelsTransform injects TransformSpec.Values as labels.
+	LabelsTransform TransformType = "Labels"
+	// AnnotationsTransform injects TransformSpec.Values as annotations.
+	AnnotationsTransform TransformType = "Annotations"
+	// NamespaceTransform overrides the namespace of all selected resources that define a namespace.
+	NamespaceTransform TransformType = "Namespace"
+	// ImageRegistryTransform rewrites the registry of all container images.
+	ImageRegistryTransform TransformType = "ImageRegistry"
+	// StrategicMergePatchTransform applies TransformSpec.Patch as a strategic merge patch.
+	// Resources unknown to the native scheme fall back to a JSON merge patch.
+	StrategicMergePatchTransform TransformType = "StrategicMergePatch"
+	// JSON6902PatchTransform applies TransformSpec.Patch as a list of RFC 6902 JSON patch operations.
+	JSON6902PatchTransform TransformType = "JSON6902Patch"
+)
+
+// +k8s:deepcopy-gen=true
+
+// TransformSpec declares a transformation of rendered resources that is executed after rendering,
+// but before the resources are applied to the target cluster.
+type TransformSpec struct {
+	// Type determines the transformation
+	Type TransformType `json:"type"`
+
+	// Target selects the resources the transformation is applied to. If not set, all resources are selected.
+	// +kubebuilder:validation:Optional
+	Target *TransformTarget `json:"target,omitempty"`
+
+	// Values contains key-value pairs for Labels and Annotations transformations
+	// +kubebuilder:validation:Optional
+	Values map[string]string `json:"values,omitempty"`
+
+	// Namespace is the namespace used by Namespace transformations
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Registry is the registry used by ImageRegistry transformations
+	// +kubebuilder:validation:Optional
+	Registry *RegistryRewrite `json:"registry,omitempty"`
+
+	// Patch is a YAML or JSON patch used by StrategicMergePatch and JSON6902Patch transformations
+	// +kubebuilder:validation:Optional
+	Patch string `json:"patch,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TransformTarget selects resources by their group, version, kind, name and namespace.
+// Empty fields match any value.
+type TransformTarget struct {
+	// +kubebuilder:validation:Optional
+	Group string `json:"group,omitempty"`
+	// +kubebuilder:validation:Optional
+	Version string `json:"version,omitempty"`
+	// +kubebuilder:validation:Optional
+	Kind string `json:"kind,omitempty"`
+	// +kubebuilder:validation:Optional
+	Name string `json:"name,omitempty"`
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ResourceSelector selects resources by their group, version, kind, name, namespace and labels.
+// Empty fields match any value, but at least one field has to be set.
+type ResourceSelector struct {
+	TransformTarget `json:",inline"`
+	// LabelSelector selects resources by their labels
+	// +kubebuilder:validation:Optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ResourcePattern matches resources by shell patterns of their group, version, kind and name,
+// e.g. "*-alerts" for names ending in "-alerts". Empty fields match any value, but at least one field has to be set.
+type ResourcePattern struct {
+	// +kubebuilder:validation:Optional
+	Group string `json:"group,omitempty"`
+	// +kubebuilder:validation:Optional
+	Version string `json:"version,omitempty"`
+	// +kubebuilder:validation:Optional
+	Kind string `json:"kind,omitempty"`
+	// +kubebuilder:validation:Optional
+	Name string `json:"name,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RegistryRewrite rewrites container image registries.
+type RegistryRewrite struct {
+	// From restricts the rewrite to images of this registry. If not set, all images are rewritten.
+	// +kubebuilder:validation:Optional
+	From string `json:"from,omitempty"`
+
+	// To is the registry images are rewritten to
+	To string `json:"to"`
+}
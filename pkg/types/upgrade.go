@@ -0,0 +1,38 @@
+package types
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +k8s:deepcopy-gen=true
+
+// UpgradePolicy restricts the changes of the chart version of an install, compared to the version installed last.
+type UpgradePolicy struct {
+	// MandatoryVersions lists chart versions, which cannot be skipped by upgrades, e.g. as they migrate data
+	// required by later versions. Upgrades to a version after a mandatory version are refused,
+	// unless the mandatory version was installed before.
+	// +kubebuilder:validation:Optional
+	MandatoryVersions []string `json:"mandatoryVersions,omitempty"`
+	// AllowDowngrades permits installing chart versions lower than the version installed last.
+	// +kubebuilder:validation:Optional
+	AllowDowngrades bool `json:"allowDowngrades,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// InstalledVersion is the chart version installed last by an install.
+type InstalledVersion struct {
+	// Name of the install
+	Name string `json:"name"`
+	// Version of the chart
+	Version string `json:"version"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// MaintenanceWindow is a recurring period, in which installs may change their chart version.
+type MaintenanceWindow struct {
+	// Schedule is a cron expression with five fields for the start of the window in UTC, e.g. "0 2 * * SAT"
+	// for every Saturday at 02:00, optionally prefixed with "CRON_TZ=<zone>" for another time zone.
+	Schedule string `json:"schedule"`
+	// Duration is the length of the window after each start.
+	Duration metav1.Duration `json:"duration"`
+}
@@ -0,0 +1,18 @@
+package types
+
+import "fmt"
+
+// ChartVerificationError is returned if the chart of an install failed its verification,
+// it wraps ErrChartVerificationFailed.
+type ChartVerificationError struct {
+	Install string
+	Err     error
+}
+
+func (e *ChartVerificationError) Error() string {
+	return fmt.Sprintf("install %s: %v", e.Install, e.Err)
+}
+
+func (e *ChartVerificationError) Unwrap() error {
+	return e.Err
+}
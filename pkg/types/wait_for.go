@@ -0,0 +1,65 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// +k8s:deepcopy-gen=true
+
+// WaitFor references an object in the target cluster, which has to satisfy an expectation before the resources
+// of an install are applied, e.g. the custom resource of another module the install depends on.
+type WaitFor struct {
+	// APIVersion of the object, e.g. "operator.kyma-project.io/v1alpha1"
+	// +kubebuilder:validation:MinLength=1
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the object, e.g. "Istio"
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+
+	// Name of the object
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the object, empty for cluster-scoped objects
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// JSONPath selects a field of the object, e.g. "{.status.state}", which has to equal Value.
+	// If not set, the object has to have a condition of type Ready with status True.
+	// +kubebuilder:validation:Optional
+	JSONPath string `json:"jsonPath,omitempty"`
+
+	// Value is the expected value of the field selected by JSONPath
+	// +kubebuilder:validation:Optional
+	Value string `json:"value,omitempty"`
+}
+
+func (w WaitFor) String() string {
+	name := w.Name
+	if w.Namespace != "" {
+		name = w.Namespace + "/" + w.Name
+	}
+	return fmt.Sprintf("%s %s", w.Kind, name)
+}
+
+// UnsatisfiedWait describes an object of a WaitFor, which does not satisfy its expectation yet.
+type UnsatisfiedWait struct {
+	WaitFor WaitFor
+	// Reason describes why the expectation is not satisfied, e.g. "not found"
+	Reason string
+}
+
+func (w UnsatisfiedWait) String() string {
+	return w.WaitFor.String() + ": " + w.Reason
+}
+
+// UnsatisfiedWaitsMessage joins the descriptions of the passed unsatisfied waits.
+func UnsatisfiedWaitsMessage(waits []UnsatisfiedWait) string {
+	messages := make([]string, 0, len(waits))
+	for _, wait := range waits {
+		messages = append(messages, wait.String())
+	}
+	return strings.Join(messages, "; ")
+}
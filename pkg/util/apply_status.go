@@ -0,0 +1,58 @@
+package util
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+const (
+	// maxApplyStatusResources is the number of resources listed per install, so that the status of Manifests
+	// with large installs stays well below the size limit of etcd.
+	maxApplyStatusResources = 100
+	// maxApplyStatusMessageLength is the number of bytes a message of a single resource is truncated to.
+	maxApplyStatusMessageLength = 256
+	truncationSuffix            = "..."
+)
+
+//nolint:gochecknoglobals
+var applyOperationOrder = map[types.ApplyOperation]int{
+	types.ApplyOperationFailed:    0,
+	types.ApplyOperationCreated:   1,
+	types.ApplyOperationUpdated:   2,
+	types.ApplyOperationUnchanged: 3,
+}
+
+// NewInstallApplyStatus returns the status of the last apply of the passed resources of an install.
+// Failed resources are listed first, followed by created, updated and unchanged resources, so that the
+// most relevant resources are kept if their number exceeds the limit. Long messages are truncated.
+func NewInstallApplyStatus(install string, resources []types.ResourceApplyStatus) types.InstallApplyStatus {
+	sorted := make([]types.ResourceApplyStatus, len(resources))
+	copy(sorted, resources)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return applyOperationOrder[sorted[i].Operation] < applyOperationOrder[sorted[j].Operation]
+	})
+	status := types.InstallApplyStatus{Install: install}
+	if len(sorted) > maxApplyStatusResources {
+		status.Omitted = len(sorted) - maxApplyStatusResources
+		sorted = sorted[:maxApplyStatusResources]
+	}
+	for i := range sorted {
+		sorted[i].Message = truncateMessage(sorted[i].Message, maxApplyStatusMessageLength)
+	}
+	status.Resources = sorted
+	return status
+}
+
+// truncateMessage shortens the message to at most maxLength bytes without splitting a multibyte character.
+func truncateMessage(message string, maxLength int) string {
+	if len(message) <= maxLength {
+		return message
+	}
+	cut := maxLength - len(truncationSuffix)
+	for cut > 0 && !utf8.RuneStart(message[cut]) {
+		cut--
+	}
+	return message[:cut] + truncationSuffix
+}
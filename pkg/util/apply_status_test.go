@@ -0,0 +1,44 @@
+package util_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func Test_NewInstallApplyStatus(t *testing.T) {
+	t.Parallel()
+	resources := make([]types.ResourceApplyStatus, 0, 150)
+	for i := 0; i < 149; i++ {
+		resources = append(resources, types.ResourceApplyStatus{
+			APIVersion: "v1", Kind: "ConfigMap", Namespace: "redis", Name: fmt.Sprintf("config-%d", i),
+			Operation: types.ApplyOperationUnchanged,
+		})
+	}
+	resources[10].Operation = types.ApplyOperationUpdated
+	resources[20].Operation = types.ApplyOperationCreated
+	resources = append(resources, types.ResourceApplyStatus{
+		APIVersion: "apps/v1", Kind: "StatefulSet", Namespace: "redis", Name: "redis",
+		Operation: types.ApplyOperationFailed, Message: strings.Repeat("ä", 200),
+	})
+
+	status := util.NewInstallApplyStatus("redis", resources)
+	assert.Equal(t, "redis", status.Install)
+	require.Len(t, status.Resources, 100)
+	assert.Equal(t, 50, status.Omitted)
+	assert.Equal(t, types.ApplyOperationFailed, status.Resources[0].Operation)
+	assert.Equal(t, types.ApplyOperationCreated, status.Resources[1].Operation)
+	assert.Equal(t, types.ApplyOperationUpdated, status.Resources[2].Operation)
+	assert.Equal(t, "config-0", status.Resources[3].Name, "order of resources with the same operation is kept")
+	assert.LessOrEqual(t, len(status.Resources[0].Message), 256)
+	assert.True(t, utf8.ValidString(status.Resources[0].Message), "multibyte characters are not split")
+	assert.True(t, strings.HasSuffix(status.Resources[0].Message, "..."))
+	assert.Len(t, resources[149].Message, 400, "passed resources are not modified")
+}
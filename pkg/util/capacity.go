@@ -0,0 +1,196 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+const clusterCapacityLimit = "allocatable resources of all schedulable nodes"
+
+//nolint:gochecknoglobals
+var (
+	// podCountPaths are the paths of the number of pods running concurrently for workloads with a pod template.
+	// Bare pods always run a single pod.
+	podCountPaths = map[schema.GroupKind][]string{
+		{Group: "apps", Kind: "Deployment"}:  {"spec", "replicas"},
+		{Group: "apps", Kind: "StatefulSet"}: {"spec", "replicas"},
+		{Group: "apps", Kind: "ReplicaSet"}:  {"spec", "replicas"},
+		{Group: "batch", Kind: "Job"}:        {"spec", "parallelism"},
+		{Group: "batch", Kind: "CronJob"}:    {"spec", "jobTemplate", "spec", "parallelism"},
+	}
+	// quotaResources are the names of the quota resources limiting the requests of a resource.
+	quotaResources = map[corev1.ResourceName][]corev1.ResourceName{
+		corev1.ResourceCPU:    {corev1.ResourceRequestsCPU, corev1.ResourceCPU},
+		corev1.ResourceMemory: {corev1.ResourceRequestsMemory, corev1.ResourceMemory},
+	}
+)
+
+// WorkloadRequests returns the aggregated CPU and memory requests of the pods of all workloads
+// of the passed objects by their namespace. Containers without requests count with their limits,
+// as requests default to limits.
+func WorkloadRequests(objects []*unstructured.Unstructured) (map[string]corev1.ResourceList, error) {
+	requests := map[string]corev1.ResourceList{}
+	for _, obj := range objects {
+		podSpec, err := podSpecOf(obj)
+		if err != nil {
+			return nil, err
+		}
+		if podSpec == nil {
+			continue
+		}
+		pods := int64(1)
+		if path, found := podCountPaths[obj.GroupVersionKind().GroupKind()]; found {
+			count, found, err := unstructured.NestedInt64(obj.Object, path...)
+			if err != nil {
+				return nil, fmt.Errorf("reading pod count of %s %s: %w", obj.GetKind(), obj.GetName(), err)
+			}
+			if found {
+				pods = count
+			}
+		}
+		namespaceRequests, found := requests[obj.GetNamespace()]
+		if !found {
+			namespaceRequests = corev1.ResourceList{}
+			requests[obj.GetNamespace()] = namespaceRequests
+		}
+		for name, quantity := range podRequests(podSpec) {
+			addQuantity(namespaceRequests, name, *resource.NewMilliQuantity(quantity.MilliValue()*pods, quantity.Format))
+		}
+	}
+	return requests, nil
+}
+
+// FindCapacityShortages returns a types.CapacityShortage for every resource, whose requests exceed the capacity
+// left in the target cluster. The requested resources are compared with the ResourceQuotas of their namespace
+// after subtracting the requests of the currently applied workloads, which already count as used by the quotas.
+// All requested resources are compared with the allocatable resources of all schedulable nodes,
+// which is skipped without any node.
+// ResourceQuotas with scopes are ignored, as they only apply to some pods.
+func FindCapacityShortages(requested, applied map[string]corev1.ResourceList,
+	quotas []corev1.ResourceQuota, nodes []corev1.Node,
+) []types.CapacityShortage {
+	var shortages []types.CapacityShortage
+	total := corev1.ResourceList{}
+	for namespace, namespaceRequests := range requested {
+		for name, quantity := range namespaceRequests {
+			addQuantity(total, name, quantity)
+			additional := quantity.DeepCopy()
+			if appliedQuantity, found := applied[namespace][name]; found {
+				additional.Sub(appliedQuantity)
+			}
+			if additional.Sign() <= 0 {
+				continue
+			}
+			shortages = append(shortages, quotaShortages(namespace, name, additional, quotas)...)
+		}
+	}
+
+	if len(nodes) == 0 {
+		return sortShortages(shortages)
+	}
+	allocatable := corev1.ResourceList{}
+	for i := range nodes {
+		if nodes[i].Spec.Unschedulable {
+			continue
+		}
+		for name, quantity := range nodes[i].Status.Allocatable {
+			addQuantity(allocatable, name, quantity)
+		}
+	}
+	for name, quantity := range total {
+		available := allocatable[name]
+		if quantity.Cmp(available) > 0 {
+			shortages = append(shortages, types.CapacityShortage{
+				Limit:     clusterCapacityLimit,
+				Resource:  string(name),
+				Requested: quantity.String(),
+				Available: available.String(),
+			})
+		}
+	}
+
+	return sortShortages(shortages)
+}
+
+func sortShortages(shortages []types.CapacityShortage) []types.CapacityShortage {
+	sort.Slice(shortages, func(i, j int) bool {
+		return shortages[i].String() < shortages[j].String()
+	})
+	return shortages
+}
+
+// quotaShortages returns a types.CapacityShortage for every ResourceQuota of the namespace,
+// which has less of the resource left than the additional requests.
+func quotaShortages(namespace string, name corev1.ResourceName, additional resource.Quantity,
+	quotas []corev1.ResourceQuota,
+) []types.CapacityShortage {
+	var shortages []types.CapacityShortage
+	for i := range quotas {
+		quota := &quotas[i]
+		if quota.Namespace != namespace || len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+			continue
+		}
+		for _, quotaResource := range quotaResources[name] {
+			hard, found := quota.Status.Hard[quotaResource]
+			if !found {
+				continue
+			}
+			available := hard.DeepCopy()
+			available.Sub(quota.Status.Used[quotaResource])
+			if additional.Cmp(available) > 0 {
+				shortages = append(shortages, types.CapacityShortage{
+					Namespace: namespace,
+					Limit:     "ResourceQuota " + quota.Name,
+					Resource:  string(quotaResource),
+					Requested: additional.String(),
+					Available: available.String(),
+				})
+			}
+		}
+	}
+	return shortages
+}
+
+// podRequests returns the effective CPU and memory requests of a pod, which are the higher of the sum
+// of the requests of all containers and the highest request of its init containers, which run one by one.
+func podRequests(podSpec *corev1.PodSpec) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for i := range podSpec.Containers {
+		for name, quantity := range containerRequests(&podSpec.Containers[i]) {
+			addQuantity(requests, name, quantity)
+		}
+	}
+	for i := range podSpec.InitContainers {
+		for name, quantity := range containerRequests(&podSpec.InitContainers[i]) {
+			if current, found := requests[name]; !found || quantity.Cmp(current) > 0 {
+				requests[name] = quantity
+			}
+		}
+	}
+	return requests
+}
+
+func containerRequests(container *corev1.Container) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for name := range quotaResources {
+		if quantity, found := container.Resources.Requests[name]; found {
+			requests[name] = quantity.DeepCopy()
+		} else if quantity, found := container.Resources.Limits[name]; found {
+			requests[name] = quantity.DeepCopy()
+		}
+	}
+	return requests
+}
+
+func addQuantity(list corev1.ResourceList, name corev1.ResourceName, quantity resource.Quantity) {
+	sum := list[name]
+	sum.Add(quantity)
+	list[name] = sum
+}
@@ -0,0 +1,98 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func deploymentWithRequests(name string, replicas int64, cpu, memory string) *unstructured.Unstructured {
+	container := map[string]any{
+		"name": "app", "image": "app:1.0.0",
+		"resources": map[string]any{"requests": map[string]any{"cpu": cpu, "memory": memory}},
+	}
+	initContainer := map[string]any{
+		"name": "init", "image": "init:1.0.0",
+		"resources": map[string]any{"limits": map[string]any{"memory": "1Gi"}},
+	}
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": name, "namespace": "default"},
+		"spec": map[string]any{
+			"replicas": replicas,
+			"template": map[string]any{"spec": map[string]any{
+				"containers":     []any{container},
+				"initContainers": []any{initContainer},
+			}},
+		},
+	}}
+}
+
+func Test_WorkloadRequests(t *testing.T) {
+	t.Parallel()
+	requests, err := util.WorkloadRequests([]*unstructured.Unstructured{
+		deploymentWithRequests("first", 2, "500m", "256Mi"),
+		deploymentWithRequests("second", 1, "1", "128Mi"),
+	})
+	require.NoError(t, err)
+	// the init container limits count as requests, which are higher than the memory requests of the containers
+	cpu, memory := requests["default"][corev1.ResourceCPU], requests["default"][corev1.ResourceMemory]
+	assert.Equal(t, int64(2000), cpu.MilliValue())
+	assert.Equal(t, int64(3<<30), memory.Value())
+}
+
+func Test_FindCapacityShortages(t *testing.T) {
+	t.Parallel()
+	requested := map[string]corev1.ResourceList{"default": {
+		corev1.ResourceCPU: resource.MustParse("3"), corev1.ResourceMemory: resource.MustParse("1Gi"),
+	}}
+	applied := map[string]corev1.ResourceList{"default": {corev1.ResourceCPU: resource.MustParse("1")}}
+	quotas := []corev1.ResourceQuota{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "default"},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{
+					corev1.ResourceRequestsCPU:    resource.MustParse("4"),
+					corev1.ResourceRequestsMemory: resource.MustParse("2Gi"),
+				},
+				Used: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("3")},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "other"},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("0")},
+			},
+		},
+	}
+	nodes := []corev1.Node{
+		{Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("4Gi"),
+		}}},
+		{
+			Spec: corev1.NodeSpec{Unschedulable: true},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("8"), corev1.ResourceMemory: resource.MustParse("4Gi"),
+			}},
+		},
+	}
+
+	shortages := util.FindCapacityShortages(requested, applied, quotas, nodes)
+	require.Len(t, shortages, 2)
+	assert.Equal(t, "cpu of 3 exceed 2 available in allocatable resources of all schedulable nodes",
+		shortages[0].String())
+	// the requests of the applied workloads are already used in the quota
+	assert.Equal(t, "default/requests.cpu of 2 exceed 1 available in ResourceQuota compute", shortages[1].String())
+
+	// without nodes, only quotas are verified
+	assert.Len(t, util.FindCapacityShortages(requested, applied, quotas, nil), 1)
+	assert.Empty(t, util.FindCapacityShortages(requested, requested, nil, nil))
+}
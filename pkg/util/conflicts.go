@@ -0,0 +1,249 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// maxConflictResolutions limits how often conflicting fields are removed from an object applied with
+// types.ConflictPolicyIgnore, as other managers could take over further fields in between.
+const maxConflictResolutions = 3
+
+// ApplyWithConflictPolicy server-side applies the object with the passed apply function and resolves field ownership
+// conflicts according to the policy. The ownership of conflicting fields is forced, unless the policy is
+// types.ConflictPolicyFail, which returns a types.ErrFieldOwnershipConflict, or types.ConflictPolicyIgnore,
+// which removes conflicting fields from a copy of the object before it is applied again.
+// It returns false if the object was skipped, as its conflicting fields could not be removed.
+func ApplyWithConflictPolicy(obj *unstructured.Unstructured, policy types.ConflictPolicy,
+	apply func(obj *unstructured.Unstructured, force bool) error,
+) (bool, error) {
+	if policy.Forces() {
+		return true, apply(obj, true)
+	}
+	current := obj
+	for attempt := 0; ; attempt++ {
+		err := apply(current, false)
+		fields := ConflictingFields(err)
+		if len(fields) == 0 {
+			return err == nil, err
+		}
+		if policy == types.ConflictPolicyFail {
+			return false, types.ErrFieldOwnershipConflict.Wrap(err)
+		}
+		if attempt == maxConflictResolutions {
+			return false, nil
+		}
+		if current == obj {
+			current = obj.DeepCopy()
+		}
+		if !RemoveFields(current, fields) {
+			return false, nil
+		}
+	}
+}
+
+// ConflictingFields returns the paths of the fields of a field manager conflict reported by server-side apply,
+// e.g. ".spec.replicas" or `.spec.template.spec.containers[name="app"].image`.
+// It returns nil if the error is no field manager conflict.
+func ConflictingFields(err error) []string {
+	var statusErr apierrors.APIStatus
+	if !apierrors.IsConflict(err) || !errors.As(err, &statusErr) || statusErr.Status().Details == nil {
+		return nil
+	}
+	var fields []string
+	for _, cause := range statusErr.Status().Details.Causes {
+		if cause.Type == metav1.CauseTypeFieldManagerConflict && cause.Field != "" {
+			fields = append(fields, cause.Field)
+		}
+	}
+	return fields
+}
+
+// RemoveFields removes the fields with the passed paths in the format of ConflictingFields from the object,
+// so that it can be applied without taking over their ownership. It returns false without changing the object,
+// if a path cannot be resolved, e.g. for field names containing dots, which are ambiguous in the format.
+func RemoveFields(obj *unstructured.Unstructured, paths []string) bool {
+	content := obj.DeepCopy().Object
+	for _, path := range paths {
+		elements, ok := parseFieldPath(path)
+		if !ok {
+			return false
+		}
+		updated, removed := removeFieldPath(content, elements)
+		if !removed {
+			return false
+		}
+		content, _ = updated.(map[string]any)
+	}
+	obj.Object = content
+	return true
+}
+
+// fieldPathElement is an element of a field path: a field of a map, or a list item selected by its keys,
+// its index or its value.
+type fieldPathElement struct {
+	field string
+	// keys map the key fields of the selected list item to their JSON encoded values
+	keys  map[string]string
+	index int
+	// value is the JSON encoded value of the selected list item
+	value string
+}
+
+func (e fieldPathElement) matches(index int, item any) bool {
+	switch {
+	case e.keys != nil:
+		itemMap, isMap := item.(map[string]any)
+		if !isMap {
+			return false
+		}
+		for key, value := range e.keys {
+			if !jsonEquals(itemMap[key], value) {
+				return false
+			}
+		}
+		return true
+	case e.value != "":
+		return jsonEquals(item, e.value)
+	default:
+		return e.index == index
+	}
+}
+
+func jsonEquals(value any, encoded string) bool {
+	actual, err := json.Marshal(value)
+	return err == nil && string(actual) == encoded
+}
+
+// parseFieldPath parses paths like `.spec.containers[name="app"].ports[0]`.
+func parseFieldPath(path string) ([]fieldPathElement, bool) {
+	var elements []fieldPathElement
+	for path != "" {
+		switch path[0] {
+		case '.':
+			end := strings.IndexAny(path[1:], ".[") + 1
+			if end == 0 {
+				end = len(path)
+			}
+			if end == 1 {
+				return nil, false
+			}
+			elements = append(elements, fieldPathElement{field: path[1:end]})
+			path = path[end:]
+		case '[':
+			end := closingBracket(path)
+			if end < 0 {
+				return nil, false
+			}
+			element, ok := parseListElement(path[1:end])
+			if !ok {
+				return nil, false
+			}
+			elements = append(elements, element)
+			path = path[end+1:]
+		default:
+			return nil, false
+		}
+	}
+	return elements, len(elements) > 0
+}
+
+// closingBracket returns the index of the bracket closing the list element at the start of the path,
+// ignoring brackets in quoted values.
+func closingBracket(path string) int {
+	quoted := false
+	for i := 1; i < len(path); i++ {
+		switch {
+		case path[i] == '\\' && quoted:
+			i++
+		case path[i] == '"':
+			quoted = !quoted
+		case path[i] == ']' && !quoted:
+			return i
+		}
+	}
+	return -1
+}
+
+// parseListElement parses the content of a list element, which is an index, a value like `="a"`
+// or keys like `name="app",protocol="TCP"`.
+func parseListElement(content string) (fieldPathElement, bool) {
+	if index, err := strconv.Atoi(content); err == nil {
+		return fieldPathElement{index: index}, true
+	}
+	if strings.HasPrefix(content, "=") {
+		return fieldPathElement{value: content[1:]}, len(content) > 1
+	}
+	element := fieldPathElement{keys: map[string]string{}}
+	for content != "" {
+		separator := strings.Index(content, "=")
+		if separator <= 0 {
+			return element, false
+		}
+		key := content[:separator]
+		content = content[separator+1:]
+		end := len(content)
+		quoted := false
+		for i := 0; i < len(content); i++ {
+			if content[i] == '\\' && quoted {
+				i++
+			} else if content[i] == '"' {
+				quoted = !quoted
+			} else if content[i] == ',' && !quoted {
+				end = i
+				break
+			}
+		}
+		element.keys[key] = content[:end]
+		content = strings.TrimPrefix(content[end:], ",")
+	}
+	return element, len(element.keys) > 0
+}
+
+// removeFieldPath removes the element at the end of the path from the node and returns the updated node,
+// as removing an item of a list creates a new list.
+func removeFieldPath(node any, path []fieldPathElement) (any, bool) {
+	element, last := path[0], len(path) == 1
+	switch typed := node.(type) {
+	case map[string]any:
+		child, found := typed[element.field]
+		if element.field == "" || !found {
+			return node, false
+		}
+		if last {
+			delete(typed, element.field)
+			return typed, true
+		}
+		updated, removed := removeFieldPath(child, path[1:])
+		if removed {
+			typed[element.field] = updated
+		}
+		return typed, removed
+	case []any:
+		if element.field != "" {
+			return node, false
+		}
+		for i, item := range typed {
+			if !element.matches(i, item) {
+				continue
+			}
+			if last {
+				return append(typed[:i:i], typed[i+1:]...), true
+			}
+			updated, removed := removeFieldPath(item, path[1:])
+			if removed {
+				typed[i] = updated
+			}
+			return typed, removed
+		}
+	}
+	return node, false
+}
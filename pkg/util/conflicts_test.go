@@ -0,0 +1,103 @@
+package util_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func deploymentWithContainers() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "sample", "namespace": "default"},
+		"spec": map[string]any{
+			"replicas": int64(2),
+			"template": map[string]any{"spec": map[string]any{"containers": []any{
+				map[string]any{"name": "app", "image": "app:1.0.0", "args": []any{"--verbose", "--debug"}},
+				map[string]any{"name": "sidecar", "image": "sidecar:1.0.0"},
+			}}},
+		},
+	}}
+}
+
+func conflict(fields ...string) error {
+	causes := make([]metav1.StatusCause, 0, len(fields))
+	for _, field := range fields {
+		causes = append(causes, metav1.StatusCause{
+			Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "other"`, Field: field,
+		})
+	}
+	return apierrors.NewApplyConflict(causes, "Apply failed with conflicts")
+}
+
+func Test_RemoveFields(t *testing.T) {
+	t.Parallel()
+	obj := deploymentWithContainers()
+	assert.Equal(t, []string{".spec.replicas", `.spec.template.spec.containers[name="app"].image`},
+		util.ConflictingFields(conflict(".spec.replicas", `.spec.template.spec.containers[name="app"].image`)))
+	assert.Empty(t, util.ConflictingFields(errors.New("no conflict")))
+
+	require.True(t, util.RemoveFields(obj, []string{
+		".spec.replicas",
+		`.spec.template.spec.containers[name="app"].image`,
+		`.spec.template.spec.containers[name="app"].args[="--debug"]`,
+		`.spec.template.spec.containers[1]`,
+	}))
+	assert.Equal(t, map[string]any{
+		"template": map[string]any{"spec": map[string]any{"containers": []any{
+			map[string]any{"name": "app", "args": []any{"--verbose"}},
+		}}},
+	}, obj.Object["spec"])
+
+	// unresolvable paths leave the object unchanged
+	obj = deploymentWithContainers()
+	assert.False(t, util.RemoveFields(obj, []string{".spec.replicas", ".metadata.labels.app.kubernetes.io/name"}))
+	assert.Equal(t, deploymentWithContainers(), obj)
+}
+
+func Test_ApplyWithConflictPolicy(t *testing.T) {
+	t.Parallel()
+	// applyOwningReplicas conflicts while the replicas are applied without force
+	applyOwningReplicas := func(applied *[]*unstructured.Unstructured) func(*unstructured.Unstructured, bool) error {
+		return func(obj *unstructured.Unstructured, force bool) error {
+			if _, found := obj.Object["spec"].(map[string]any)["replicas"]; found && !force {
+				return conflict(".spec.replicas")
+			}
+			*applied = append(*applied, obj)
+			return nil
+		}
+	}
+
+	var applied []*unstructured.Unstructured
+	ok, err := util.ApplyWithConflictPolicy(deploymentWithContainers(), types.ConflictPolicyForce,
+		applyOwningReplicas(&applied))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	require.Len(t, applied, 1)
+	assert.Contains(t, applied[0].Object["spec"], "replicas")
+
+	applied = nil
+	obj := deploymentWithContainers()
+	ok, err = util.ApplyWithConflictPolicy(obj, types.ConflictPolicyIgnore, applyOwningReplicas(&applied))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	require.Len(t, applied, 1)
+	assert.NotContains(t, applied[0].Object["spec"], "replicas")
+	assert.Contains(t, obj.Object["spec"], "replicas", "the passed object is not changed")
+
+	applied = nil
+	ok, err = util.ApplyWithConflictPolicy(deploymentWithContainers(), types.ConflictPolicyFail,
+		applyOwningReplicas(&applied))
+	require.ErrorIs(t, err, types.ErrFieldOwnershipConflict)
+	assert.False(t, ok)
+	assert.Empty(t, applied)
+}
@@ -0,0 +1,206 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+const (
+	// envelopePrefix marks data sealed by SealEnvelope.
+	envelopePrefix = "k8s:enc:envelope:v1:"
+	dataKeySize    = 32
+)
+
+var (
+	ErrNoEncryptionKeys     = errors.New("no encryption keys configured")
+	ErrUnknownEncryptionKey = errors.New("unknown encryption key")
+	ErrEnvelopeWithoutKeys  = errors.New("data is encrypted, but no key encrypter is configured")
+	ErrWrappedKeyTooShort   = errors.New("wrapped data key too short")
+)
+
+type envelope struct {
+	KeyID      string `json:"keyID"`
+	WrappedKey []byte `json:"wrappedKey"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+// SealEnvelope encrypts data with a new AES-GCM data encryption key, which is wrapped by the passed encrypter.
+func SealEnvelope(ctx context.Context, encrypter types.KeyEncrypter, data []byte) ([]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+	nonce, sealed, err := sealAESGCM(dataKey, data)
+	if err != nil {
+		return nil, err
+	}
+	keyID := encrypter.KeyID()
+	wrappedKey, err := encrypter.Encrypt(ctx, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("wrapping data key with %s: %w", keyID, err)
+	}
+	sealedEnvelope, err := json.Marshal(envelope{KeyID: keyID, WrappedKey: wrappedKey, Nonce: nonce, Data: sealed})
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(envelopePrefix), sealedEnvelope...), nil
+}
+
+// OpenEnvelope decrypts data sealed by SealEnvelope. Data that is not sealed is returned unchanged,
+// so that data stored before encryption was enabled can still be read.
+func OpenEnvelope(ctx context.Context, encrypter types.KeyEncrypter, data []byte) ([]byte, error) {
+	if !IsEnvelope(data) {
+		return data, nil
+	}
+	if encrypter == nil {
+		return nil, ErrEnvelopeWithoutKeys
+	}
+	var sealedEnvelope envelope
+	if err := json.Unmarshal(data[len(envelopePrefix):], &sealedEnvelope); err != nil {
+		return nil, fmt.Errorf("decoding envelope: %w", err)
+	}
+	dataKey, err := encrypter.Decrypt(ctx, sealedEnvelope.KeyID, sealedEnvelope.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapping data key with %s: %w", sealedEnvelope.KeyID, err)
+	}
+	return openAESGCM(dataKey, sealedEnvelope.Nonce, sealedEnvelope.Data)
+}
+
+// IsEnvelope indicates if the passed data was sealed by SealEnvelope.
+func IsEnvelope(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(envelopePrefix))
+}
+
+// LocalKeyEncrypter is a types.KeyEncrypter wrapping data encryption keys with local AES-GCM keys.
+// The first key encrypts, all keys decrypt, so that a key can be rotated by prepending a new one.
+type LocalKeyEncrypter struct {
+	keyIDs []string
+	keys   map[string][]byte
+}
+
+// LocalKey is a named AES key of 16, 24 or 32 bytes.
+type LocalKey struct {
+	ID  string
+	Key []byte
+}
+
+// NewLocalKeyEncrypter returns a LocalKeyEncrypter encrypting with the first of the passed keys.
+func NewLocalKeyEncrypter(keys ...LocalKey) (*LocalKeyEncrypter, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoEncryptionKeys
+	}
+	encrypter := &LocalKeyEncrypter{keys: make(map[string][]byte, len(keys))}
+	for _, key := range keys {
+		if _, err := aes.NewCipher(key.Key); err != nil {
+			return nil, fmt.Errorf("invalid encryption key %s: %w", key.ID, err)
+		}
+		if _, duplicate := encrypter.keys[key.ID]; duplicate {
+			return nil, fmt.Errorf("encryption key %s configured more than once", key.ID)
+		}
+		encrypter.keyIDs = append(encrypter.keyIDs, key.ID)
+		encrypter.keys[key.ID] = key.Key
+	}
+	return encrypter, nil
+}
+
+// LoadLocalKeyEncrypter reads keys for a LocalKeyEncrypter from the passed file.
+// Every line contains a key in the format "<key-id>=<base64 encoded key>", empty lines and lines starting
+// with "#" are ignored. The first key encrypts.
+func LoadLocalKeyEncrypter(path string) (*LocalKeyEncrypter, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var keys []LocalKey
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keyID, encodedKey, found := strings.Cut(line, "=")
+		if !found || keyID == "" {
+			return nil, fmt.Errorf("invalid encryption key entry in %s, expected <key-id>=<key>", path)
+		}
+		key, err := base64.StdEncoding.DecodeString(encodedKey)
+		if err != nil {
+			return nil, fmt.Errorf("decoding encryption key %s: %w", keyID, err)
+		}
+		keys = append(keys, LocalKey{ID: keyID, Key: key})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewLocalKeyEncrypter(keys...)
+}
+
+func (e *LocalKeyEncrypter) KeyID() string {
+	return e.keyIDs[0]
+}
+
+func (e *LocalKeyEncrypter) Encrypt(_ context.Context, dataKey []byte) ([]byte, error) {
+	nonce, sealed, err := sealAESGCM(e.keys[e.KeyID()], dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return append(nonce, sealed...), nil
+}
+
+func (e *LocalKeyEncrypter) Decrypt(_ context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	key, found := e.keys[keyID]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEncryptionKey, keyID)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrappedKey) < gcm.NonceSize() {
+		return nil, ErrWrappedKeyTooShort
+	}
+	return gcm.Open(nil, wrappedKey[:gcm.NonceSize()], wrappedKey[gcm.NonceSize():], nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func sealAESGCM(key, data []byte) ([]byte, []byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, nil, err
+	}
+	return nonce, gcm.Seal(nil, nonce, data, nil), nil
+}
+
+func openAESGCM(key, nonce, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Open(nil, nonce, data, nil)
+}
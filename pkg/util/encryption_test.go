@@ -0,0 +1,66 @@
+package util_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func Test_EnvelopeKeyRotation(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	previousKey := util.LocalKey{ID: "previous", Key: bytes.Repeat([]byte{1}, 32)}
+	currentKey := util.LocalKey{ID: "current", Key: bytes.Repeat([]byte{2}, 32)}
+	plaintext := []byte("password: hunter2")
+
+	previous, err := util.NewLocalKeyEncrypter(previousKey)
+	require.NoError(t, err)
+	sealed, err := util.SealEnvelope(ctx, previous, plaintext)
+	require.NoError(t, err)
+	assert.True(t, util.IsEnvelope(sealed))
+	assert.NotContains(t, string(sealed), "hunter2")
+
+	// after rotation, data sealed with the previous key can still be opened
+	rotated, err := util.NewLocalKeyEncrypter(currentKey, previousKey)
+	require.NoError(t, err)
+	assert.Equal(t, "current", rotated.KeyID())
+	opened, err := util.OpenEnvelope(ctx, rotated, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, opened)
+
+	resealed, err := util.SealEnvelope(ctx, rotated, opened)
+	require.NoError(t, err)
+	_, err = util.OpenEnvelope(ctx, previous, resealed)
+	assert.ErrorIs(t, err, util.ErrUnknownEncryptionKey)
+
+	// unencrypted data is passed through, encrypted data requires keys
+	opened, err = util.OpenEnvelope(ctx, nil, plaintext)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, opened)
+	_, err = util.OpenEnvelope(ctx, nil, sealed)
+	assert.ErrorIs(t, err, util.ErrEnvelopeWithoutKeys)
+}
+
+func Test_LoadLocalKeyEncrypter(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "keys")
+	content := "# rotated 2022-11\ncurrent=" + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32)) +
+		"\n\nprevious=" + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 16)) + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	encrypter, err := util.LoadLocalKeyEncrypter(path)
+	require.NoError(t, err)
+	assert.Equal(t, "current", encrypter.KeyID())
+
+	require.NoError(t, os.WriteFile(path, []byte("invalid="+base64.StdEncoding.EncodeToString([]byte("short"))), 0o600))
+	_, err = util.LoadLocalKeyEncrypter(path)
+	assert.Error(t, err)
+}
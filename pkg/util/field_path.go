@@ -0,0 +1,27 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseFieldPath returns the fields of a JSONPath expression selecting a single field by its names,
+// e.g. `{.status.state}` or `.status.state`. Expressions with filters, indices or wildcards are refused,
+// as the selected field has to be readable and writable with unstructured helpers.
+func ParseFieldPath(expression string) ([]string, error) {
+	path := strings.TrimSpace(expression)
+	if strings.HasPrefix(path, "{") && strings.HasSuffix(path, "}") {
+		path = path[1 : len(path)-1]
+	}
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return nil, fmt.Errorf("field path %q selects no field", expression)
+	}
+	fields := strings.Split(path, ".")
+	for _, field := range fields {
+		if field == "" || strings.ContainsAny(field, "[]*?@$(){}'\" ") {
+			return nil, fmt.Errorf("field path %q is not a path of field names", expression)
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,24 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func Test_ParseFieldPath(t *testing.T) {
+	t.Parallel()
+	for _, expression := range []string{"{.status.moduleState}", ".status.moduleState", "status.moduleState"} {
+		fields, err := util.ParseFieldPath(expression)
+		assert.NoError(t, err, expression)
+		assert.Equal(t, []string{"status", "moduleState"}, fields, expression)
+	}
+
+	for _, expression := range []string{"", "{}", ".status..state", "{.status.conditions[0]}",
+		".status.*", "{.status.conditions[?(@.type=='Ready')]}"} {
+		_, err := util.ParseFieldPath(expression)
+		assert.Error(t, err, expression)
+	}
+}
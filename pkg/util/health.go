@@ -0,0 +1,121 @@
+package util
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// healthCheckers evaluate the health of applied resources by their status.
+//
+//nolint:gochecknoglobals
+var healthCheckers = map[schema.GroupKind]func(obj *unstructured.Unstructured) (string, error){
+	{Group: "apps", Kind: "Deployment"}:        deploymentHealth,
+	{Group: "apps", Kind: "StatefulSet"}:       statefulSetHealth,
+	{Group: "apps", Kind: "DaemonSet"}:         daemonSetHealth,
+	{Group: "", Kind: "PersistentVolumeClaim"}: persistentVolumeClaimHealth,
+}
+
+// IsHealthChecked indicates if the health of the object is evaluated by FindHealthIssues.
+func IsHealthChecked(obj *unstructured.Unstructured) bool {
+	_, checked := healthCheckers[obj.GroupVersionKind().GroupKind()]
+	return checked
+}
+
+// FindHealthIssues returns a types.HealthIssue for every passed object currently applied to a cluster,
+// which is degraded according to its status: Deployments and StatefulSets with fewer ready replicas than desired,
+// DaemonSets with pods not scheduled or not ready on all eligible nodes and PersistentVolumeClaims not bound.
+// Objects of other kinds are ignored.
+func FindHealthIssues(objects []*unstructured.Unstructured) ([]types.HealthIssue, error) {
+	var issues []types.HealthIssue
+	for _, obj := range objects {
+		checkHealth, checked := healthCheckers[obj.GroupVersionKind().GroupKind()]
+		if !checked {
+			continue
+		}
+		reason, err := checkHealth(obj)
+		if err != nil {
+			return nil, fmt.Errorf("evaluating health of %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		if reason != "" {
+			issues = append(issues, types.HealthIssue{
+				Kind:      obj.GetKind(),
+				Namespace: obj.GetNamespace(),
+				Name:      obj.GetName(),
+				Reason:    reason,
+			})
+		}
+	}
+	return issues, nil
+}
+
+func deploymentHealth(obj *unstructured.Unstructured) (string, error) {
+	deployment := &appsv1.Deployment{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, deployment); err != nil {
+		return "", err
+	}
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse {
+			return fmt.Sprintf("rollout stuck: %s", condition.Message), nil
+		}
+	}
+	return replicasHealth(deployment.Spec.Replicas, deployment.Status.ReadyReplicas), nil
+}
+
+func statefulSetHealth(obj *unstructured.Unstructured) (string, error) {
+	statefulSet := &appsv1.StatefulSet{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, statefulSet); err != nil {
+		return "", err
+	}
+	return replicasHealth(statefulSet.Spec.Replicas, statefulSet.Status.ReadyReplicas), nil
+}
+
+// replicasHealth compares the ready with the desired replicas, which default to 1.
+func replicasHealth(replicas *int32, readyReplicas int32) string {
+	desired := int32(1)
+	if replicas != nil {
+		desired = *replicas
+	}
+	if readyReplicas >= desired {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d replicas ready", readyReplicas, desired)
+}
+
+func daemonSetHealth(obj *unstructured.Unstructured) (string, error) {
+	daemonSet := &appsv1.DaemonSet{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, daemonSet); err != nil {
+		return "", err
+	}
+	status := daemonSet.Status
+	switch {
+	case status.CurrentNumberScheduled < status.DesiredNumberScheduled:
+		return fmt.Sprintf("%d/%d pods scheduled", status.CurrentNumberScheduled, status.DesiredNumberScheduled), nil
+	case status.NumberMisscheduled > 0:
+		return fmt.Sprintf("%d pods running on ineligible nodes", status.NumberMisscheduled), nil
+	case status.NumberReady < status.DesiredNumberScheduled:
+		return fmt.Sprintf("%d/%d pods ready", status.NumberReady, status.DesiredNumberScheduled), nil
+	}
+	return "", nil
+}
+
+func persistentVolumeClaimHealth(obj *unstructured.Unstructured) (string, error) {
+	claim := &corev1.PersistentVolumeClaim{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, claim); err != nil {
+		return "", err
+	}
+	switch claim.Status.Phase {
+	case corev1.ClaimBound:
+		return "", nil
+	case "":
+		return "claim not bound", nil
+	default:
+		return fmt.Sprintf("claim %s", claim.Status.Phase), nil
+	}
+}
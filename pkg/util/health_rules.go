@@ -0,0 +1,165 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// healthRuleObject is the variable the evaluated resource is available as in the expression of a types.HealthRule.
+const healthRuleObject = "object"
+
+var ErrHealthRuleNotBoolean = errors.New("expression does not evaluate to a boolean")
+
+// healthRulePrograms caches the compiled expressions of health rules per Manifest and install,
+// as compiling an expression is considerably more expensive than evaluating it.
+//
+//nolint:gochecknoglobals
+var healthRulePrograms = &healthRuleCache{programs: make(map[healthRuleKey]map[string]cel.Program)}
+
+type healthRuleKey struct {
+	owner   client.ObjectKey
+	install string
+}
+
+type healthRuleCache struct {
+	envOnce sync.Once
+	env     *cel.Env
+	envErr  error
+
+	mu       sync.Mutex
+	programs map[healthRuleKey]map[string]cel.Program
+}
+
+// ValidateHealthRule returns an error if the expression of the HealthRule does not compile to a boolean.
+func ValidateHealthRule(rule types.HealthRule) error {
+	_, err := healthRulePrograms.compile(rule.Expression)
+	return err
+}
+
+// MatchesHealthRules indicates if the health of the object is evaluated by one of the passed rules.
+func MatchesHealthRules(rules []types.HealthRule, obj *unstructured.Unstructured) bool {
+	for _, rule := range rules {
+		if healthRuleMatches(rule, obj) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindHealthRuleIssues returns a types.HealthIssue for every passed object currently applied to a cluster,
+// for which the expression of a matching rule evaluates to false or cannot be evaluated, e.g. as a referenced field
+// is not set yet. The compiled expressions are cached for the passed owner and install
+// until the rules of the install change or ForgetHealthRules is called.
+func FindHealthRuleIssues(owner client.ObjectKey, install string, rules []types.HealthRule,
+	objects []*unstructured.Unstructured,
+) ([]types.HealthIssue, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	programs, err := healthRulePrograms.programsFor(healthRuleKey{owner: owner, install: install}, rules)
+	if err != nil {
+		return nil, err
+	}
+	var issues []types.HealthIssue
+	for _, obj := range objects {
+		for _, rule := range rules {
+			if !healthRuleMatches(rule, obj) {
+				continue
+			}
+			if reason := evaluateHealthRule(programs[rule.Expression], rule, obj); reason != "" {
+				issues = append(issues, types.HealthIssue{
+					Kind:      obj.GetKind(),
+					Namespace: obj.GetNamespace(),
+					Name:      obj.GetName(),
+					Reason:    reason,
+				})
+			}
+		}
+	}
+	return issues, nil
+}
+
+// ForgetHealthRules drops the compiled expressions cached for the passed owner, e.g. once it was deleted.
+func ForgetHealthRules(owner client.ObjectKey) {
+	healthRulePrograms.mu.Lock()
+	defer healthRulePrograms.mu.Unlock()
+	for key := range healthRulePrograms.programs {
+		if key.owner == owner {
+			delete(healthRulePrograms.programs, key)
+		}
+	}
+}
+
+func healthRuleMatches(rule types.HealthRule, obj *unstructured.Unstructured) bool {
+	group, err := schema.ParseGroupVersion(rule.APIVersion)
+	if err != nil {
+		return false
+	}
+	gvk := obj.GroupVersionKind()
+	return group.Group == gvk.Group && rule.Kind == gvk.Kind && (rule.Name == "" || rule.Name == obj.GetName())
+}
+
+// evaluateHealthRule returns the reason the object is not healthy according to the rule,
+// or an empty reason if it is healthy.
+func evaluateHealthRule(program cel.Program, rule types.HealthRule, obj *unstructured.Unstructured) string {
+	result, _, err := program.Eval(map[string]interface{}{healthRuleObject: obj.Object})
+	if err != nil {
+		return fmt.Sprintf("evaluating %q: %s", rule.Expression, err)
+	}
+	if healthy, ok := result.Value().(bool); ok && healthy {
+		return ""
+	}
+	if rule.Message != "" {
+		return rule.Message
+	}
+	return fmt.Sprintf("%q is not satisfied", rule.Expression)
+}
+
+// programsFor returns the compiled expressions of the passed rules, keyed by expression.
+// Expressions cached for the key, which are not used by the rules anymore, are dropped.
+func (c *healthRuleCache) programsFor(key healthRuleKey, rules []types.HealthRule) (map[string]cel.Program, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached := c.programs[key]
+	programs := make(map[string]cel.Program, len(rules))
+	for _, rule := range rules {
+		if program, found := cached[rule.Expression]; found {
+			programs[rule.Expression] = program
+			continue
+		}
+		program, err := c.compile(rule.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("compiling health rule of %s %q: %w", rule.Kind, rule.Expression, err)
+		}
+		programs[rule.Expression] = program
+	}
+	c.programs[key] = programs
+	return programs, nil
+}
+
+func (c *healthRuleCache) compile(expression string) (cel.Program, error) {
+	c.envOnce.Do(func() {
+		c.env, c.envErr = cel.NewEnv(cel.Variable(healthRuleObject, cel.DynType))
+	})
+	if c.envErr != nil {
+		return nil, c.envErr
+	}
+	ast, issues := c.env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	// fields of the object are dynamically typed, so that their type is only checked on evaluation
+	if outputType := ast.OutputType(); !cel.BoolType.IsAssignableType(outputType) &&
+		!outputType.IsAssignableType(cel.BoolType) {
+		return nil, fmt.Errorf("%w: %s", ErrHealthRuleNotBoolean, outputType)
+	}
+	return c.env.Program(ast)
+}
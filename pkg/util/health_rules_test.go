@@ -0,0 +1,63 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func Test_FindHealthRuleIssues(t *testing.T) {
+	t.Parallel()
+	owner := client.ObjectKey{Name: "redis", Namespace: "kcp-system"}
+	defer util.ForgetHealthRules(owner)
+	statefulSet := func(name string, replicas, readyReplicas int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "StatefulSet",
+			"metadata":   map[string]any{"name": name, "namespace": "redis"},
+			"spec":       map[string]any{"replicas": replicas},
+			"status":     map[string]any{"readyReplicas": readyReplicas},
+		}}
+	}
+	rules := []types.HealthRule{{
+		APIVersion: "apps/v1beta2", Kind: "StatefulSet",
+		Expression: "object.status.readyReplicas == object.spec.replicas",
+	}, {
+		APIVersion: "apps/v1", Kind: "StatefulSet", Name: "redis-replica",
+		Expression: "object.status.readyReplicas >= 1", Message: "no replica ready",
+	}, {
+		APIVersion: "apps/v1", Kind: "StatefulSet", Name: "redis-sentinel",
+		Expression: "object.status.currentRevision == object.status.updateRevision",
+	}}
+	healthy := statefulSet("redis-master", 1, 1)
+	degraded := statefulSet("redis-replica", 3, 0)
+	unevaluable := statefulSet("redis-sentinel", 1, 1)
+	assert.True(t, util.MatchesHealthRules(rules, healthy))
+	assert.False(t, util.MatchesHealthRules(rules, &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1", "kind": "Service", "metadata": map[string]any{"name": "redis"},
+	}}))
+
+	issues, err := util.FindHealthRuleIssues(owner, "redis", rules,
+		[]*unstructured.Unstructured{healthy, degraded, unevaluable})
+	require.NoError(t, err)
+	require.Len(t, issues, 3)
+	assert.Equal(t, "redis-replica", issues[0].Name)
+	assert.Contains(t, issues[0].Reason, "is not satisfied")
+	assert.Equal(t, "no replica ready", issues[1].Reason)
+	assert.Equal(t, "redis-sentinel", issues[2].Name)
+	assert.Contains(t, issues[2].Reason, "no such key")
+}
+
+func Test_ValidateHealthRule(t *testing.T) {
+	t.Parallel()
+	assert.NoError(t, util.ValidateHealthRule(types.HealthRule{Expression: "object.status.phase == 'Bound'"}))
+	assert.NoError(t, util.ValidateHealthRule(types.HealthRule{Expression: "object.status.ready"}))
+	assert.ErrorIs(t, util.ValidateHealthRule(types.HealthRule{Expression: "1 + 2"}), util.ErrHealthRuleNotBoolean)
+	assert.Error(t, util.ValidateHealthRule(types.HealthRule{Expression: "object.status.("}))
+}
@@ -0,0 +1,56 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func objectWithStatus(apiVersion, kind, name string, spec, status map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]any{"name": name, "namespace": "default"},
+		"spec":       spec,
+		"status":     status,
+	}}
+}
+
+func Test_FindHealthIssues(t *testing.T) {
+	t.Parallel()
+	issues, err := util.FindHealthIssues([]*unstructured.Unstructured{
+		objectWithStatus("apps/v1", "Deployment", "healthy",
+			map[string]any{"replicas": int64(2)}, map[string]any{"readyReplicas": int64(2)}),
+		objectWithStatus("apps/v1", "Deployment", "degraded",
+			map[string]any{"replicas": int64(3)}, map[string]any{"readyReplicas": int64(1)}),
+		objectWithStatus("apps/v1", "Deployment", "default-replicas",
+			map[string]any{}, map[string]any{}),
+		objectWithStatus("apps/v1", "DaemonSet", "unscheduled", map[string]any{}, map[string]any{
+			"desiredNumberScheduled": int64(3), "currentNumberScheduled": int64(2), "numberReady": int64(2),
+		}),
+		objectWithStatus("apps/v1", "DaemonSet", "healthy", map[string]any{}, map[string]any{
+			"desiredNumberScheduled": int64(3), "currentNumberScheduled": int64(3), "numberReady": int64(3),
+		}),
+		objectWithStatus("v1", "PersistentVolumeClaim", "pending",
+			map[string]any{}, map[string]any{"phase": "Pending"}),
+		objectWithStatus("v1", "PersistentVolumeClaim", "bound",
+			map[string]any{}, map[string]any{"phase": "Bound"}),
+		objectWithStatus("v1", "ConfigMap", "ignored", nil, nil),
+	})
+	require.NoError(t, err)
+
+	messages := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		messages = append(messages, issue.String())
+	}
+	assert.Equal(t, []string{
+		"Deployment/default/degraded: 1/3 replicas ready",
+		"Deployment/default/default-replicas: 0/1 replicas ready",
+		"DaemonSet/default/unscheduled: 2/3 pods scheduled",
+		"PersistentVolumeClaim/default/pending: claim Pending",
+	}, messages)
+}
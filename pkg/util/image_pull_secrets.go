@@ -0,0 +1,136 @@
+package util
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+var (
+	ErrImagePullSecretNameMissing = errors.New("image pull secret requires a name or a docker config")
+	ErrInvalidDockerConfig        = errors.New("docker config of image pull secret is invalid")
+)
+
+//nolint:gochecknoglobals
+var (
+	serviceAccountKind = schema.GroupKind{Kind: "ServiceAccount"}
+	daemonSetKind      = schema.GroupKind{Group: "apps", Kind: "DaemonSet"}
+)
+
+// ImagePullSecretTransform returns an ObjectTransform adding the passed secret to the image pull secrets
+// of all rendered ServiceAccounts and pod templates of workloads, so that images of private registries
+// can be pulled without changes to the chart. Secrets with a docker config are rendered in every
+// namespace of these resources, unless rendered by the chart already.
+func ImagePullSecretTransform(secret types.ImagePullSecret) (types.ObjectTransform, error) {
+	if secret.Name == "" {
+		if len(secret.DockerConfigJSON) == 0 {
+			return nil, ErrImagePullSecretNameMissing
+		}
+		secret.Name = types.DefaultImagePullSecretName
+	}
+	if errs := validation.IsDNS1123Subdomain(secret.Name); len(errs) > 0 {
+		return nil, fmt.Errorf("image pull secret name %q is invalid: %v", secret.Name, errs)
+	}
+	if len(secret.DockerConfigJSON) > 0 {
+		config := struct {
+			Auths map[string]any `json:"auths"`
+		}{}
+		if err := json.Unmarshal(secret.DockerConfigJSON, &config); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidDockerConfig, err.Error())
+		}
+		if len(config.Auths) == 0 {
+			return nil, fmt.Errorf("%w: no registry credentials in auths", ErrInvalidDockerConfig)
+		}
+	}
+
+	return func(_ context.Context, _ types.BaseCustomObject, resources *types.ManifestResources) error {
+		namespaces := map[string]struct{}{}
+		rendered := map[string]struct{}{}
+		for _, obj := range resources.Items {
+			gk := obj.GroupVersionKind().GroupKind()
+			if gk == (schema.GroupKind{Kind: secretKind}) && obj.GetName() == secret.Name {
+				rendered[obj.GetNamespace()] = struct{}{}
+				continue
+			}
+			path := pullSecretsPath(gk)
+			if path == nil {
+				continue
+			}
+			if err := addImagePullSecret(obj, path, secret.Name); err != nil {
+				return fmt.Errorf("adding image pull secret to %s %s: %w", obj.GetKind(), obj.GetName(), err)
+			}
+			namespaces[obj.GetNamespace()] = struct{}{}
+		}
+
+		if len(secret.DockerConfigJSON) == 0 {
+			return nil
+		}
+		missing := make([]string, 0, len(namespaces))
+		for namespace := range namespaces {
+			if _, found := rendered[namespace]; !found {
+				missing = append(missing, namespace)
+			}
+		}
+		sort.Strings(missing)
+		for _, namespace := range missing {
+			resources.Items = append(resources.Items, dockerConfigSecret(secret, namespace))
+		}
+		return nil
+	}, nil
+}
+
+// pullSecretsPath returns the path of the image pull secrets of ServiceAccounts and workloads,
+// or nil for all other kinds.
+func pullSecretsPath(gk schema.GroupKind) []string {
+	if gk == serviceAccountKind {
+		return []string{"imagePullSecrets"}
+	}
+	if gk == daemonSetKind {
+		return []string{"spec", "template", "spec", "imagePullSecrets"}
+	}
+	if path, isWorkload := podSpecPaths[gk]; isWorkload {
+		return append(append([]string{}, path...), "imagePullSecrets")
+	}
+	return nil
+}
+
+// addImagePullSecret appends a reference to the named secret to the list at the path, unless referenced already.
+func addImagePullSecret(obj *unstructured.Unstructured, path []string, name string) error {
+	references, _, err := unstructured.NestedSlice(obj.Object, path...)
+	if err != nil {
+		return err
+	}
+	for _, reference := range references {
+		if referenceMap, isMap := reference.(map[string]any); isMap && referenceMap["name"] == name {
+			return nil
+		}
+	}
+	references = append(references, map[string]any{"name": name})
+	return unstructured.SetNestedSlice(obj.Object, references, path...)
+}
+
+// dockerConfigSecret returns the secret with the docker config in the passed namespace.
+// Secrets without namespace are defaulted during apply just like the resources referencing them.
+func dockerConfigSecret(secret types.ImagePullSecret, namespace string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"type": string(corev1.SecretTypeDockerConfigJson),
+		"data": map[string]any{
+			corev1.DockerConfigJsonKey: base64.StdEncoding.EncodeToString(secret.DockerConfigJSON),
+		},
+	}}
+	obj.SetAPIVersion("v1")
+	obj.SetKind(secretKind)
+	obj.SetName(secret.Name)
+	obj.SetNamespace(namespace)
+	return obj
+}
@@ -0,0 +1,68 @@
+package util_test
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func Test_ImagePullSecretTransform(t *testing.T) {
+	t.Parallel()
+	config := []byte(`{"auths":{"registry.example.io":{"auth":"dXNlcjpwYXNz"}}}`)
+	serviceAccount := objectWithStatus("v1", "ServiceAccount", "app", nil, nil)
+	serviceAccount.Object["imagePullSecrets"] = []any{map[string]any{"name": "chart-secret"}}
+	daemonSet := deploymentWithReplicas("agent", 1)
+	daemonSet.SetKind("DaemonSet")
+	daemonSet.SetNamespace("agents")
+	configMap := objectWithStatus("v1", "ConfigMap", "config", nil, nil)
+	resources := &types.ManifestResources{Items: []*unstructured.Unstructured{
+		deploymentWithReplicas("app", 1), serviceAccount, daemonSet, configMap,
+	}}
+
+	transform, err := util.ImagePullSecretTransform(types.ImagePullSecret{DockerConfigJSON: config})
+	require.NoError(t, err)
+	require.NoError(t, transform(context.Background(), nil, resources))
+	require.NoError(t, transform(context.Background(), nil, resources), "the transform is idempotent")
+
+	pullSecrets := func(obj *unstructured.Unstructured, path ...string) []any {
+		references, _, err := unstructured.NestedSlice(obj.Object, append(path, "imagePullSecrets")...)
+		require.NoError(t, err)
+		return references
+	}
+	reference := map[string]any{"name": types.DefaultImagePullSecretName}
+	assert.Equal(t, []any{reference}, pullSecrets(resources.Items[0], "spec", "template", "spec"))
+	assert.Equal(t, []any{map[string]any{"name": "chart-secret"}, reference}, pullSecrets(serviceAccount))
+	assert.Equal(t, []any{reference}, pullSecrets(daemonSet, "spec", "template", "spec"))
+	assert.Empty(t, pullSecrets(configMap))
+
+	require.Len(t, resources.Items, 6, "a secret is rendered once per namespace")
+	for i, namespace := range []string{"agents", "default"} {
+		secret := resources.Items[4+i]
+		assert.Equal(t, "Secret", secret.GetKind())
+		assert.Equal(t, types.DefaultImagePullSecretName, secret.GetName())
+		assert.Equal(t, namespace, secret.GetNamespace())
+		assert.Equal(t, "kubernetes.io/dockerconfigjson", secret.Object["type"])
+		data, _, _ := unstructured.NestedString(secret.Object, "data", ".dockerconfigjson")
+		assert.Equal(t, base64.StdEncoding.EncodeToString(config), data)
+	}
+
+	referenceOnly, err := util.ImagePullSecretTransform(types.ImagePullSecret{Name: "registry-credentials"})
+	require.NoError(t, err)
+	resources = &types.ManifestResources{Items: []*unstructured.Unstructured{deploymentWithReplicas("app", 1)}}
+	require.NoError(t, referenceOnly(context.Background(), nil, resources))
+	assert.Len(t, resources.Items, 1, "secrets without docker config are expected in the target cluster")
+
+	_, err = util.ImagePullSecretTransform(types.ImagePullSecret{})
+	require.ErrorIs(t, err, util.ErrImagePullSecretNameMissing)
+	_, err = util.ImagePullSecretTransform(types.ImagePullSecret{DockerConfigJSON: []byte(`{"auths":{}}`)})
+	require.ErrorIs(t, err, util.ErrInvalidDockerConfig)
+	_, err = util.ImagePullSecretTransform(types.ImagePullSecret{Name: "Invalid_Name"})
+	require.Error(t, err)
+}
@@ -0,0 +1,39 @@
+package util
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ImageReferences returns the distinct image references of all containers, init containers and
+// ephemeral containers of the workloads of the passed objects, sorted by reference.
+func ImageReferences(objects []*unstructured.Unstructured) ([]string, error) {
+	images := map[string]struct{}{}
+	for _, obj := range objects {
+		podSpec, err := podSpecOf(obj)
+		if err != nil {
+			return nil, err
+		}
+		if podSpec == nil {
+			continue
+		}
+		for i := range podSpec.Containers {
+			images[podSpec.Containers[i].Image] = struct{}{}
+		}
+		for i := range podSpec.InitContainers {
+			images[podSpec.InitContainers[i].Image] = struct{}{}
+		}
+		for i := range podSpec.EphemeralContainers {
+			images[podSpec.EphemeralContainers[i].Image] = struct{}{}
+		}
+	}
+	delete(images, "")
+
+	references := make([]string, 0, len(images))
+	for image := range images {
+		references = append(references, image)
+	}
+	sort.Strings(references)
+	return references, nil
+}
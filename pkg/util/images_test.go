@@ -0,0 +1,28 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func Test_ImageReferences(t *testing.T) {
+	t.Parallel()
+	configMap := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": "images"},
+		"data":       map[string]any{"image": "ignored:1.0.0"},
+	}}
+	images, err := util.ImageReferences([]*unstructured.Unstructured{
+		deploymentWithRequests("first", 1, "1", "1Gi"),
+		deploymentWithRequests("second", 1, "1", "1Gi"),
+		configMap,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app:1.0.0", "init:1.0.0"}, images)
+}
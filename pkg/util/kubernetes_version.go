@@ -0,0 +1,30 @@
+package util
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// KubernetesVersionSupported indicates if the passed version of a Kubernetes API server, e.g. "v1.25.4-gke.1600",
+// satisfies the semantic version constraint, e.g. ">=1.24.0 <1.27.0". Pre-release and build suffixes of
+// provider distributions are ignored, so that they match the upstream version they are based on.
+func KubernetesVersionSupported(constraint string, gitVersion string) (bool, error) {
+	constraints, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return false, fmt.Errorf("invalid Kubernetes version constraint %q: %w", constraint, err)
+	}
+	version, err := semver.NewVersion(gitVersion)
+	if err != nil {
+		return false, fmt.Errorf("invalid Kubernetes version %q: %w", gitVersion, err)
+	}
+	release, err := version.SetPrerelease("")
+	if err != nil {
+		return false, err
+	}
+	release, err = release.SetMetadata("")
+	if err != nil {
+		return false, err
+	}
+	return constraints.Check(&release), nil
+}
@@ -0,0 +1,32 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func Test_KubernetesVersionSupported(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		version   string
+		supported bool
+	}{
+		{"v1.24.0", true},
+		{"v1.25.4-gke.1600", true},
+		{"v1.26.1+k3s1", true},
+		{"v1.23.9", false},
+		{"v1.27.0", false},
+	}
+	for _, test := range tests {
+		supported, err := util.KubernetesVersionSupported(">=1.24.0 <1.27.0", test.version)
+		require.NoError(t, err)
+		assert.Equal(t, test.supported, supported, test.version)
+	}
+
+	_, err := util.KubernetesVersionSupported("not-a-constraint", "v1.25.0")
+	assert.Error(t, err)
+}
@@ -0,0 +1,49 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+var errNonPositiveWindowDuration = errors.New("duration of the maintenance window must be positive")
+
+// ValidateMaintenanceWindow returns an error, if the schedule of the window is no valid cron expression
+// with five fields or its duration is not positive.
+func ValidateMaintenanceWindow(window types.MaintenanceWindow) error {
+	if _, err := cron.ParseStandard(window.Schedule); err != nil {
+		return fmt.Errorf("schedule %q: %w", window.Schedule, err)
+	}
+	if window.Duration.Duration <= 0 {
+		return errNonPositiveWindowDuration
+	}
+	return nil
+}
+
+// MaintenanceWindowOpen indicates if any of the passed windows is open at the passed time.
+// If none is open, the start of the next window is returned as well. Without windows, changes are always allowed.
+func MaintenanceWindowOpen(windows []types.MaintenanceWindow, now time.Time) (bool, time.Time, error) {
+	if len(windows) == 0 {
+		return true, time.Time{}, nil
+	}
+	var next time.Time
+	for _, window := range windows {
+		if err := ValidateMaintenanceWindow(window); err != nil {
+			return false, time.Time{}, err
+		}
+		schedule, _ := cron.ParseStandard(window.Schedule)
+		// the first start after the beginning of a window ending now is the start of the current window, if any
+		start := schedule.Next(now.Add(-window.Duration.Duration))
+		if !start.After(now) {
+			return true, time.Time{}, nil
+		}
+		if next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+	return false, next, nil
+}
@@ -0,0 +1,45 @@
+package util_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func Test_MaintenanceWindowOpen(t *testing.T) {
+	t.Parallel()
+	windows := []types.MaintenanceWindow{
+		{Schedule: "0 2 * * SAT", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+		{Schedule: "30 22 * * WED", Duration: metav1.Duration{Duration: time.Hour}},
+	}
+	// 2023-01-07 is a Saturday
+	saturday := time.Date(2023, 1, 7, 0, 0, 0, 0, time.UTC)
+
+	open, _, err := util.MaintenanceWindowOpen(windows, saturday.Add(3*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, open)
+
+	open, next, err := util.MaintenanceWindowOpen(windows, saturday.Add(6*time.Hour))
+	require.NoError(t, err)
+	assert.False(t, open, "the window ends after its duration")
+	assert.Equal(t, time.Date(2023, 1, 11, 22, 30, 0, 0, time.UTC), next)
+
+	open, next, err = util.MaintenanceWindowOpen(windows, saturday.Add(time.Hour))
+	require.NoError(t, err)
+	assert.False(t, open)
+	assert.Equal(t, saturday.Add(2*time.Hour), next)
+
+	open, _, err = util.MaintenanceWindowOpen(nil, saturday)
+	require.NoError(t, err)
+	assert.True(t, open, "without windows changes are always allowed")
+
+	_, _, err = util.MaintenanceWindowOpen([]types.MaintenanceWindow{{Schedule: "every saturday"}}, saturday)
+	assert.Error(t, err)
+	assert.Error(t, util.ValidateMaintenanceWindow(types.MaintenanceWindow{Schedule: "0 2 * * SAT"}))
+}
@@ -0,0 +1,72 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	yamlUtil "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// ManifestDecoder decodes a multi-document YAML manifest from a reader one document at a time.
+// Only the document being decoded is buffered, so that manifests are never held in memory as a whole
+// next to the objects decoded from them.
+type ManifestDecoder struct {
+	reader *yamlUtil.YAMLReader
+}
+
+// NewManifestDecoder returns a ManifestDecoder reading the manifest from the reader.
+func NewManifestDecoder(reader io.Reader) *ManifestDecoder {
+	return &ManifestDecoder{reader: yamlUtil.NewYAMLReader(bufio.NewReader(reader))}
+}
+
+// Next returns the object of the next document of the manifest. Documents which are not an object are returned
+// as blob instead, empty documents are skipped. io.EOF is returned once all documents are decoded.
+func (d *ManifestDecoder) Next() (*unstructured.Unstructured, []byte, error) {
+	for {
+		rawBytes, err := d.reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, nil, io.EOF
+			}
+			return nil, nil, fmt.Errorf("invalid YAML doc: %w", err)
+		}
+
+		rawBytes = bytes.TrimSpace(rawBytes)
+		unstructuredObj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(rawBytes, unstructuredObj); err != nil {
+			return nil, append(bytes.TrimPrefix(rawBytes, []byte("---\n")), '\n'), nil
+		}
+
+		if len(rawBytes) == 0 || bytes.Equal(rawBytes, []byte("null")) || len(unstructuredObj.Object) == 0 {
+			continue
+		}
+		return unstructuredObj, nil, nil
+	}
+}
+
+// ParseManifestToObjects decodes the manifest read from the reader into its objects and blobs.
+func ParseManifestToObjects(reader io.Reader) (*types.ManifestResources, error) {
+	objects := &types.ManifestResources{}
+	decoder := NewManifestDecoder(reader)
+	for {
+		obj, blob, err := decoder.Next()
+		if errors.Is(err, io.EOF) {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if blob != nil {
+			objects.Blobs = append(objects.Blobs, blob)
+			continue
+		}
+		objects.Items = append(objects.Items, obj)
+	}
+}
@@ -0,0 +1,68 @@
+package util_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+const decoderTestManifest = `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+# only a comment
+---
+null
+---
+- not
+- an object
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+`
+
+func Test_ManifestDecoder(t *testing.T) {
+	t.Parallel()
+	decoder := util.NewManifestDecoder(strings.NewReader(decoderTestManifest))
+
+	obj, blob, err := decoder.Next()
+	require.NoError(t, err)
+	assert.Nil(t, blob)
+	assert.Equal(t, "first", obj.GetName())
+
+	obj, blob, err = decoder.Next()
+	require.NoError(t, err)
+	assert.Nil(t, obj, "empty documents are skipped")
+	assert.Equal(t, "- not\n- an object\n", string(blob))
+
+	obj, _, err = decoder.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "second", obj.GetName())
+
+	_, _, err = decoder.Next()
+	assert.True(t, errors.Is(err, io.EOF))
+}
+
+func Test_ParseManifestToObjects(t *testing.T) {
+	t.Parallel()
+	objects, err := util.ParseManifestToObjects(strings.NewReader(decoderTestManifest))
+	require.NoError(t, err)
+	require.Len(t, objects.Items, 2)
+	assert.Equal(t, "first", objects.Items[0].GetName())
+	assert.Equal(t, "second", objects.Items[1].GetName())
+	assert.Len(t, objects.Blobs, 1)
+
+	fromString, err := util.ParseManifestStringToObjects(decoderTestManifest)
+	require.NoError(t, err)
+	assert.Equal(t, objects, fromString)
+}
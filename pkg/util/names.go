@@ -0,0 +1,84 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// releaseNameMaxLength is the maximum length of Helm release names.
+	releaseNameMaxLength = 53
+	// nameHashLength is the length of the hash suffix of normalized names.
+	nameHashLength = 8
+)
+
+// NormalizeSubdomain returns the lower-cased value, if it is a valid DNS-1123 subdomain, e.g. for names
+// of ConfigMaps derived from names of custom resources. Otherwise, invalid characters are replaced,
+// and the value is truncated to 253 characters, ending in a hash of the original value to keep it unique.
+func NormalizeSubdomain(value string) string {
+	value = strings.ToLower(value)
+	return normalizeName(value, value, validation.DNS1123SubdomainMaxLength, func(name string) bool {
+		return len(validation.IsDNS1123Subdomain(name)) == 0
+	}, isDNS1123Char)
+}
+
+// NormalizeReleaseName returns the value, if it is a valid Helm release name. Otherwise, it is lower-cased,
+// invalid characters are replaced, and it is truncated to 53 characters, ending in a hash of the original value.
+func NormalizeReleaseName(value string) string {
+	return normalizeName(value, strings.ToLower(value), releaseNameMaxLength, func(name string) bool {
+		return chartutil.ValidateReleaseName(name) == nil
+	}, isDNS1123Char)
+}
+
+// NormalizeLabelValue returns the value, if it is a valid label value. Otherwise, invalid characters are
+// replaced, and it is truncated to 63 characters, ending in a hash of the original value to keep it unique.
+func NormalizeLabelValue(value string) string {
+	return normalizeName(value, value, validation.LabelValueMaxLength, func(name string) bool {
+		return len(validation.IsValidLabelValue(name)) == 0
+	}, func(char rune) bool {
+		return isDNS1123Char(char) || (char >= 'A' && char <= 'Z') || char == '_'
+	})
+}
+
+// normalizeName returns the value if it is valid, otherwise the normalized value with all chars not allowed
+// replaced by dashes, truncated to maxLength including the suffix of the hash of the value.
+// Dots are replaced as well, if the result is still invalid afterwards, e.g. due to consecutive dots.
+func normalizeName(value, normalized string, maxLength int, valid func(string) bool, allowed func(rune) bool) string {
+	if valid(value) {
+		return value
+	}
+	hash := sha256.Sum256([]byte(value))
+	suffix := hex.EncodeToString(hash[:])[:nameHashLength]
+	replace := func(char rune) rune {
+		if !allowed(char) {
+			return '-'
+		}
+		return char
+	}
+	for _, replaceDots := range []bool{false, true} {
+		candidate := strings.Map(replace, normalized)
+		if replaceDots {
+			candidate = strings.ReplaceAll(candidate, ".", "-")
+		}
+		if len(candidate) > maxLength-nameHashLength-1 {
+			candidate = candidate[:maxLength-nameHashLength-1]
+		}
+		// names have to start and end alphanumeric
+		candidate = strings.Trim(candidate, "-_.")
+		if candidate == "" {
+			return suffix
+		}
+		if candidate += "-" + suffix; valid(candidate) {
+			return candidate
+		}
+	}
+	return suffix
+}
+
+func isDNS1123Char(char rune) bool {
+	return (char >= 'a' && char <= 'z') || (char >= '0' && char <= '9') || char == '-' || char == '.'
+}
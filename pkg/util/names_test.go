@@ -0,0 +1,53 @@
+package util_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func Test_NormalizeSubdomain(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "inventory.kcp-system.manifest.redis", util.NormalizeSubdomain("inventory.kcp-system.Manifest.redis"),
+		"valid names are only lower-cased")
+
+	long := "inventory.kcp-system." + strings.Repeat("manifest", 40) + ".redis"
+	normalized := util.NormalizeSubdomain(long)
+	assert.Empty(t, validation.IsDNS1123Subdomain(normalized))
+	assert.Len(t, normalized, validation.DNS1123SubdomainMaxLength)
+	assert.Equal(t, normalized, util.NormalizeSubdomain(long), "normalization is deterministic")
+	assert.NotEqual(t, normalized, util.NormalizeSubdomain(long+"-cache"), "truncated names stay unique")
+
+	for _, name := range []string{"inventory.kcp-system.manifest.my_release", "inventory..release-", "_"} {
+		assert.Empty(t, validation.IsDNS1123Subdomain(util.NormalizeSubdomain(name)), name)
+	}
+}
+
+func Test_NormalizeReleaseName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "redis", util.NormalizeReleaseName("redis"), "valid names are kept")
+
+	for _, name := range []string{"Redis", "my_release", strings.Repeat("release", 10), "-release-"} {
+		normalized := util.NormalizeReleaseName(name)
+		assert.NoError(t, chartutil.ValidateReleaseName(normalized), name)
+		assert.Equal(t, normalized, util.NormalizeReleaseName(name), "normalization is deterministic")
+	}
+	assert.NotEqual(t, util.NormalizeReleaseName("Redis"), util.NormalizeReleaseName("redis"))
+}
+
+func Test_NormalizeLabelValue(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "kcp-system__Manifest", util.NormalizeLabelValue("kcp-system__Manifest"), "valid values are kept")
+
+	long := "kcp-system__" + strings.Repeat("manifest", 10)
+	normalized := util.NormalizeLabelValue(long)
+	assert.Empty(t, validation.IsValidLabelValue(normalized))
+	assert.Len(t, normalized, validation.LabelValueMaxLength)
+	assert.True(t, strings.HasPrefix(normalized, "kcp-system__manifest"), "the prefix of the value is kept")
+	assert.Empty(t, validation.IsValidLabelValue(util.NormalizeLabelValue("owner/with spaces")))
+}
@@ -0,0 +1,126 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// parentReference matches references to fields of the parent resource in string values of overrides,
+// e.g. "{{ .spec.resources.profile }}".
+var parentReference = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+var ErrParentFieldMissing = errors.New("field of parent resource not found")
+
+// MergeSelectedOverrides merges the values of all overrides, whose selector matches the passed runtime labels,
+// into the passed values. Overrides take precedence over the values and are merged in order, so later ones win.
+// References to fields of the passed parent resource in the values of overrides are resolved before merging.
+func MergeSelectedOverrides(overrides []types.SelectedOverride, runtimeLabels map[string]string,
+	values map[string]any, parent map[string]any,
+) (map[string]any, error) {
+	for i := range overrides {
+		selector, err := metav1.LabelSelectorAsSelector(&overrides[i].Selector)
+		if err != nil {
+			return nil, fmt.Errorf("selector of override %d: %w", i, err)
+		}
+		if !selector.Matches(k8slabels.Set(runtimeLabels)) {
+			continue
+		}
+		overrideValues := map[string]any{}
+		if len(overrides[i].Values.Raw) > 0 {
+			if err := json.Unmarshal(overrides[i].Values.Raw, &overrideValues); err != nil {
+				return nil, fmt.Errorf("values of override %d: %w", i, err)
+			}
+		}
+		resolved, err := ResolveParentReferences(overrideValues, parent)
+		if err != nil {
+			return nil, fmt.Errorf("values of override %d: %w", i, err)
+		}
+		overrideValues = resolved.(map[string]any)
+		if values == nil {
+			values = map[string]any{}
+		}
+		values = chartutil.CoalesceTables(overrideValues, values)
+	}
+	return values, nil
+}
+
+// ResolveParentReferences replaces references to fields of the parent resource in all string values,
+// e.g. "{{ .spec.resources.profile }}". A string consisting of a single reference is replaced with the field
+// as it is, so that numbers, booleans and objects keep their type, references within a longer string are
+// replaced with the formatted field. References to missing fields return an ErrParentFieldMissing.
+func ResolveParentReferences(value any, parent map[string]any) (any, error) {
+	switch typed := value.(type) {
+	case map[string]any:
+		resolved := make(map[string]any, len(typed))
+		for key, nested := range typed {
+			resolvedNested, err := ResolveParentReferences(nested, parent)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			resolved[key] = resolvedNested
+		}
+		return resolved, nil
+	case []any:
+		resolved := make([]any, len(typed))
+		for i, nested := range typed {
+			resolvedNested, err := ResolveParentReferences(nested, parent)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			resolved[i] = resolvedNested
+		}
+		return resolved, nil
+	case string:
+		return resolveStringReferences(typed, parent)
+	default:
+		return value, nil
+	}
+}
+
+func resolveStringReferences(value string, parent map[string]any) (any, error) {
+	matches := parentReference.FindAllStringSubmatchIndex(value, -1)
+	if len(matches) == 0 {
+		return value, nil
+	}
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(value) {
+		return parentField(value[matches[0][2]:matches[0][3]], parent)
+	}
+	var resolved strings.Builder
+	last := 0
+	for _, match := range matches {
+		field, err := parentField(value[match[2]:match[3]], parent)
+		if err != nil {
+			return nil, err
+		}
+		resolved.WriteString(value[last:match[0]])
+		resolved.WriteString(fmt.Sprint(field))
+		last = match[1]
+	}
+	resolved.WriteString(value[last:])
+	return resolved.String(), nil
+}
+
+func parentField(path string, parent map[string]any) (any, error) {
+	fields, err := ParseFieldPath(path)
+	if err != nil {
+		return nil, err
+	}
+	field, found, err := unstructured.NestedFieldCopy(parent, fields...)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s of parent resource: %w", path, err)
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrParentFieldMissing, path)
+	}
+	return field, nil
+}
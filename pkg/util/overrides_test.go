@@ -0,0 +1,78 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func Test_MergeSelectedOverrides(t *testing.T) {
+	t.Parallel()
+	overrides := []types.SelectedOverride{
+		{
+			Selector: metav1.LabelSelector{MatchLabels: map[string]string{"environment": "production"}},
+			Values:   apiextensionsv1.JSON{Raw: []byte(`{"replicaCount":3,"resources":{"limits":{"memory":"1Gi"}}}`)},
+		},
+		{
+			Selector: metav1.LabelSelector{MatchLabels: map[string]string{labels.RemoteLabel: "true"}},
+			Values:   apiextensionsv1.JSON{Raw: []byte(`{"replicaCount":5}`)},
+		},
+	}
+	values := map[string]any{"replicaCount": 1, "resources": map[string]any{"limits": map[string]any{"cpu": "1"}}}
+
+	merged, err := util.MergeSelectedOverrides(overrides, map[string]string{"environment": "production"}, values,
+		nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"replicaCount": float64(3),
+		"resources":    map[string]any{"limits": map[string]any{"memory": "1Gi", "cpu": "1"}},
+	}, merged)
+
+	merged, err = util.MergeSelectedOverrides(overrides,
+		map[string]string{"environment": "production", labels.RemoteLabel: "true"}, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, float64(5), merged["replicaCount"], "later overrides take precedence")
+
+	merged, err = util.MergeSelectedOverrides(overrides, map[string]string{"environment": "staging"},
+		map[string]any{"replicaCount": 1}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"replicaCount": 1}, merged)
+
+	_, err = util.MergeSelectedOverrides([]types.SelectedOverride{{Selector: metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "environment", Operator: "Unknown"}},
+	}}}, nil, nil, nil)
+	require.Error(t, err)
+}
+
+func Test_MergeSelectedOverridesWithParentReferences(t *testing.T) {
+	t.Parallel()
+	parent := map[string]any{"spec": map[string]any{
+		"resources": map[string]any{"profile": "production", "replicas": int64(3)},
+		"channel":   "fast",
+	}}
+	overrides := []types.SelectedOverride{{Values: apiextensionsv1.JSON{Raw: []byte(
+		`{"profile":"{{ .spec.resources.profile }}","replicaCount":"{{.spec.resources.replicas}}",` +
+			`"image":{"tag":"{{ .spec.channel }}-{{ .spec.resources.profile }}"},"args":["--channel={{ .spec.channel }}"]}`,
+	)}}}
+
+	merged, err := util.MergeSelectedOverrides(overrides, nil, nil, parent)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"profile":      "production",
+		"replicaCount": int64(3),
+		"image":        map[string]any{"tag": "fast-production"},
+		"args":         []any{"--channel=fast"},
+	}, merged)
+
+	_, err = util.MergeSelectedOverrides([]types.SelectedOverride{{Values: apiextensionsv1.JSON{
+		Raw: []byte(`{"profile":"{{ .spec.missing }}"}`),
+	}}}, nil, nil, parent)
+	assert.ErrorIs(t, err, util.ErrParentFieldMissing)
+}
@@ -0,0 +1,96 @@
+package util
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+//nolint:gochecknoglobals
+var crdGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+
+// FindMissingAPIs returns the resource types of the passed objects, which are not served by the target cluster
+// according to the mapper, in the order of their first object. Types defined by a CustomResourceDefinition among
+// the objects are served once it is applied and not reported. As the mapper could cache outdated discovery data,
+// e.g. of APIs installed since, it is reset once before types are reported as missing.
+func FindMissingAPIs(objects []*unstructured.Unstructured, mapper meta.RESTMapper) ([]types.MissingAPI, error) {
+	defined := make(map[schema.GroupKind]bool)
+	for _, obj := range objects {
+		if obj.GroupVersionKind().GroupKind() != crdGroupKind {
+			continue
+		}
+		group, _, err := unstructured.NestedString(obj.Object, "spec", "group")
+		if err != nil {
+			return nil, fmt.Errorf("reading group of CustomResourceDefinition %s: %w", obj.GetName(), err)
+		}
+		kind, _, err := unstructured.NestedString(obj.Object, "spec", "names", "kind")
+		if err != nil {
+			return nil, fmt.Errorf("reading kind of CustomResourceDefinition %s: %w", obj.GetName(), err)
+		}
+		defined[schema.GroupKind{Group: group, Kind: kind}] = true
+	}
+
+	missing, err := findUnmappedAPIs(objects, mapper, defined)
+	if err != nil || len(missing) == 0 {
+		return missing, err
+	}
+	meta.MaybeResetRESTMapper(mapper)
+	return findUnmappedAPIs(objects, mapper, defined)
+}
+
+func findUnmappedAPIs(objects []*unstructured.Unstructured, mapper meta.RESTMapper,
+	defined map[schema.GroupKind]bool,
+) ([]types.MissingAPI, error) {
+	var missing []types.MissingAPI
+	positions := make(map[schema.GroupVersionKind]int)
+	served := make(map[schema.GroupVersionKind]bool)
+	for _, obj := range objects {
+		gvk := obj.GroupVersionKind()
+		if defined[gvk.GroupKind()] || served[gvk] {
+			continue
+		}
+		name := obj.GetName()
+		if obj.GetNamespace() != "" {
+			name = obj.GetNamespace() + "/" + name
+		}
+		if i, found := positions[gvk]; found {
+			missing[i].Resources = append(missing[i].Resources, name)
+			continue
+		}
+		_, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		switch {
+		case meta.IsNoMatchError(err):
+			positions[gvk] = len(missing)
+			missing = append(missing, types.MissingAPI{GroupVersionKind: gvk, Resources: []string{name}})
+		case err != nil:
+			return nil, err
+		default:
+			served[gvk] = true
+		}
+	}
+	return missing, nil
+}
+
+// MissingAPITransform returns an ObjectTransform removing all resources of the passed missing APIs,
+// so that the remaining resources can be applied.
+func MissingAPITransform(missing []types.MissingAPI) types.ObjectTransform {
+	skipped := make(map[schema.GroupVersionKind]bool, len(missing))
+	for _, api := range missing {
+		skipped[api.GroupVersionKind] = true
+	}
+	return func(_ context.Context, _ types.BaseCustomObject, resources *types.ManifestResources) error {
+		included := resources.Items[:0]
+		for _, obj := range resources.Items {
+			if !skipped[obj.GroupVersionKind()] {
+				included = append(included, obj)
+			}
+		}
+		resources.Items = included
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+package util_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+// staleRESTMapper only maps the kinds installed since its creation after it was reset,
+// like a mapper caching discovery data.
+type staleRESTMapper struct {
+	*meta.DefaultRESTMapper
+	installed []schema.GroupVersionKind
+}
+
+func (m *staleRESTMapper) Reset() {
+	for _, gvk := range m.installed {
+		m.Add(gvk, meta.RESTScopeNamespace)
+	}
+}
+
+func Test_FindMissingAPIs(t *testing.T) {
+	t.Parallel()
+	mapper := &staleRESTMapper{
+		DefaultRESTMapper: meta.NewDefaultRESTMapper(nil),
+		installed:         []schema.GroupVersionKind{{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}},
+	}
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	crd := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]any{"name": "widgets.example.io"},
+		"spec":       map[string]any{"group": "example.io", "names": map[string]any{"kind": "Widget"}},
+	}}
+	mapper.Add(crd.GroupVersionKind(), meta.RESTScopeRoot)
+
+	missing, err := util.FindMissingAPIs([]*unstructured.Unstructured{
+		deploymentWithReplicas("app", 1),
+		objectWithStatus("monitoring.coreos.com/v1", "ServiceMonitor", "metrics", nil, nil),
+		objectWithStatus("monitoring.coreos.com/v1", "PodMonitor", "pods", nil, nil),
+		crd,
+		objectWithStatus("example.io/v1", "Widget", "widget", nil, nil),
+		objectWithStatus("monitoring.coreos.com/v1", "ServiceMonitor", "probes", nil, nil),
+	}, mapper)
+	require.NoError(t, err)
+	require.Len(t, missing, 1, "installed APIs and kinds of rendered CRDs are not missing")
+	assert.Equal(t, "ServiceMonitor", missing[0].GroupVersionKind.Kind)
+	assert.Equal(t, []string{"default/metrics", "default/probes"}, missing[0].Resources)
+	assert.Equal(t, "monitoring.coreos.com/v1, Kind=ServiceMonitor required by default/metrics, default/probes",
+		missing[0].String())
+}
+
+func Test_MissingAPITransform(t *testing.T) {
+	t.Parallel()
+	serviceMonitor := objectWithStatus("monitoring.coreos.com/v1", "ServiceMonitor", "metrics", nil, nil)
+	resources := &types.ManifestResources{Items: []*unstructured.Unstructured{
+		deploymentWithReplicas("app", 1), serviceMonitor,
+	}}
+	transform := util.MissingAPITransform([]types.MissingAPI{
+		{GroupVersionKind: serviceMonitor.GroupVersionKind(), Resources: []string{"default/metrics"}},
+	})
+	require.NoError(t, transform(context.Background(), nil, resources))
+	require.Len(t, resources.Items, 1)
+	assert.Equal(t, "app", resources.Items[0].GetName())
+}
@@ -0,0 +1,45 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// profileFileTemplate is the name of the values overlay of a profile bundled with a chart.
+const profileFileTemplate = "profile-%s.yaml"
+
+// MergeProfileValues merges the values overlay of the profile bundled with the chart at chartPath,
+// e.g. profile-production.yaml, into the passed values, which take precedence over the values of the profile.
+// The values of the chart itself are merged by Helm on render, so the precedence is chart < profile < values.
+// Without a profile, the values are returned as is.
+func MergeProfileValues(chartPath, profile string, values map[string]any) (map[string]any, error) {
+	if profile == "" {
+		return values, nil
+	}
+	fileName := fmt.Sprintf(profileFileTemplate, profile)
+	if chartPath == "" {
+		return nil, types.ErrProfileNotFound.Wrap(
+			fmt.Errorf("%s can only be read from charts of images or local paths, not from Helm repositories", fileName))
+	}
+	data, err := os.ReadFile(filepath.Join(chartPath, fileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, types.ErrProfileNotFound.Wrap(fmt.Errorf("no %s in chart at %s", fileName, chartPath))
+	} else if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", fileName, err)
+	}
+	profileValues, err := chartutil.ReadValues(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", fileName, err)
+	}
+	if values == nil {
+		values = map[string]any{}
+	}
+	return chartutil.CoalesceTables(values, profileValues), nil
+}
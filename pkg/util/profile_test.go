@@ -0,0 +1,39 @@
+package util_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func Test_MergeProfileValues(t *testing.T) {
+	t.Parallel()
+	chartPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(chartPath, "profile-production.yaml"),
+		[]byte("replicaCount: 3\nresources:\n  limits:\n    memory: 1Gi\n    cpu: 500m\n"), 0o600))
+
+	values, err := util.MergeProfileValues(chartPath, "production", map[string]any{
+		"resources": map[string]any{"limits": map[string]any{"memory": "2Gi"}},
+	})
+	require.NoError(t, err)
+	// values of the config take precedence over the values of the profile
+	assert.Equal(t, map[string]any{
+		"replicaCount": float64(3),
+		"resources":    map[string]any{"limits": map[string]any{"memory": "2Gi", "cpu": "500m"}},
+	}, values)
+
+	values, err = util.MergeProfileValues(chartPath, "", map[string]any{"replicaCount": 1})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"replicaCount": 1}, values)
+
+	_, err = util.MergeProfileValues(chartPath, "evaluation", nil)
+	require.ErrorIs(t, err, types.ErrProfileNotFound)
+	_, err = util.MergeProfileValues("", "production", nil)
+	require.ErrorIs(t, err, types.ErrProfileNotFound)
+}
@@ -0,0 +1,160 @@
+package util
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+const topologySpreadMaxSkew = 1
+
+//nolint:gochecknoglobals
+var (
+	deploymentGroupKind          = schema.GroupKind{Group: "apps", Kind: "Deployment"}
+	podDisruptionBudgetGroupKind = schema.GroupKind{Group: "policy", Kind: "PodDisruptionBudget"}
+	defaultMaxUnavailable        = intstr.FromInt(1)
+)
+
+// ResilienceTransform returns a types.ObjectTransform, which injects a PodDisruptionBudget for every rendered
+// Deployment with at least spec.MinReplicas replicas, whose pods are not selected by a rendered
+// PodDisruptionBudget already. If spec.TopologySpreadKey is set, a topology spread constraint for the key is
+// added to these Deployments as well. Constraints are only preferred, so that pods are still scheduled
+// on clusters without enough topology domains.
+func ResilienceTransform(spec types.ResilienceSpec) types.ObjectTransform {
+	minReplicas := int64(spec.MinReplicas)
+	if minReplicas <= 0 {
+		minReplicas = types.DefaultResilienceMinReplicas
+	}
+	maxUnavailable := defaultMaxUnavailable
+	if spec.MaxUnavailable != nil {
+		maxUnavailable = *spec.MaxUnavailable
+	}
+
+	return func(_ context.Context, _ types.BaseCustomObject, resources *types.ManifestResources) error {
+		// injected budgets are appended, only the rendered resources are iterated
+		rendered := resources.Items
+		for _, obj := range rendered {
+			if obj.GroupVersionKind().GroupKind() != deploymentGroupKind {
+				continue
+			}
+			replicas, found, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+			if err != nil {
+				return fmt.Errorf("reading replicas of Deployment %s: %w", obj.GetName(), err)
+			}
+			if !found {
+				replicas = 1
+			}
+			if replicas < minReplicas {
+				continue
+			}
+			selector, found, err := unstructured.NestedMap(obj.Object, "spec", "selector")
+			if err != nil || !found {
+				// Deployments without selector are rejected by the API server on apply
+				continue
+			}
+			podLabels, _, err := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "labels")
+			if err != nil {
+				return fmt.Errorf("reading pod labels of Deployment %s: %w", obj.GetName(), err)
+			}
+
+			budgeted, err := hasDisruptionBudget(rendered, obj, podLabels)
+			if err != nil {
+				return err
+			}
+			if !budgeted {
+				resources.Items = append(resources.Items, disruptionBudgetFor(obj, selector, maxUnavailable))
+			}
+			if spec.TopologySpreadKey != "" {
+				if err := addTopologySpreadConstraint(obj, spec.TopologySpreadKey, selector); err != nil {
+					return fmt.Errorf("adding topology spread constraint to Deployment %s: %w", obj.GetName(), err)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// hasDisruptionBudget indicates if any of the PodDisruptionBudgets in the namespace of the Deployment selects
+// its pod labels. A PodDisruptionBudget with the name of the Deployment is never replaced either.
+func hasDisruptionBudget(objects []*unstructured.Unstructured, deployment *unstructured.Unstructured,
+	podLabels map[string]string,
+) (bool, error) {
+	for _, obj := range objects {
+		if obj.GroupVersionKind().GroupKind() != podDisruptionBudgetGroupKind ||
+			obj.GetNamespace() != deployment.GetNamespace() {
+			continue
+		}
+		if obj.GetName() == deployment.GetName() {
+			return true, nil
+		}
+		selectorObject, found, err := unstructured.NestedMap(obj.Object, "spec", "selector")
+		if err != nil || !found {
+			continue
+		}
+		labelSelector := &metav1.LabelSelector{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(selectorObject, labelSelector); err != nil {
+			return false, fmt.Errorf("reading selector of PodDisruptionBudget %s: %w", obj.GetName(), err)
+		}
+		selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+		if err != nil {
+			return false, fmt.Errorf("reading selector of PodDisruptionBudget %s: %w", obj.GetName(), err)
+		}
+		if selector.Matches(labels.Set(podLabels)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func disruptionBudgetFor(deployment *unstructured.Unstructured, selector map[string]any,
+	maxUnavailable intstr.IntOrString,
+) *unstructured.Unstructured {
+	budget := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"selector":       runtime.DeepCopyJSONValue(selector),
+			"maxUnavailable": intOrStringValue(maxUnavailable),
+		},
+	}}
+	budget.SetGroupVersionKind(podDisruptionBudgetGroupKind.WithVersion("v1"))
+	budget.SetName(deployment.GetName())
+	budget.SetNamespace(deployment.GetNamespace())
+	budget.SetLabels(deployment.GetLabels())
+	return budget
+}
+
+// addTopologySpreadConstraint adds a constraint for the key to the pod template,
+// unless it defines one for the key already.
+func addTopologySpreadConstraint(deployment *unstructured.Unstructured, key string, selector map[string]any) error {
+	path := []string{"spec", "template", "spec", "topologySpreadConstraints"}
+	constraints, _, err := unstructured.NestedSlice(deployment.Object, path...)
+	if err != nil {
+		return err
+	}
+	for _, constraint := range constraints {
+		if constraintMap, isMap := constraint.(map[string]any); isMap && constraintMap["topologyKey"] == key {
+			return nil
+		}
+	}
+	constraints = append(constraints, map[string]any{
+		"maxSkew":           int64(topologySpreadMaxSkew),
+		"topologyKey":       key,
+		"whenUnsatisfiable": "ScheduleAnyway",
+		"labelSelector":     runtime.DeepCopyJSONValue(selector),
+	})
+	return unstructured.SetNestedSlice(deployment.Object, constraints, path...)
+}
+
+func intOrStringValue(value intstr.IntOrString) any {
+	if value.Type == intstr.String {
+		return value.StrVal
+	}
+	return int64(value.IntValue())
+}
@@ -0,0 +1,80 @@
+package util_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func deploymentWithReplicas(name string, replicas int64) *unstructured.Unstructured {
+	selector := map[string]any{"matchLabels": map[string]any{"app": name}}
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": name, "namespace": "default"},
+		"spec": map[string]any{
+			"replicas": replicas,
+			"selector": selector,
+			"template": map[string]any{
+				"metadata": map[string]any{"labels": map[string]any{"app": name}},
+				"spec":     map[string]any{"containers": []any{map[string]any{"name": "app", "image": "app:1.0.0"}}},
+			},
+		},
+	}}
+}
+
+func Test_ResilienceTransform(t *testing.T) {
+	t.Parallel()
+	budgeted := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "policy/v1",
+		"kind":       "PodDisruptionBudget",
+		"metadata":   map[string]any{"name": "custom-budget", "namespace": "default"},
+		"spec": map[string]any{
+			"minAvailable": int64(1),
+			"selector":     map[string]any{"matchLabels": map[string]any{"app": "budgeted"}},
+		},
+	}}
+	resources := &types.ManifestResources{Items: []*unstructured.Unstructured{
+		deploymentWithReplicas("single", 1),
+		deploymentWithReplicas("highly-available", 3),
+		deploymentWithReplicas("budgeted", 3),
+		budgeted,
+	}}
+	maxUnavailable := intstr.FromString("25%")
+	transform := util.ResilienceTransform(types.ResilienceSpec{
+		MaxUnavailable:    &maxUnavailable,
+		TopologySpreadKey: "topology.kubernetes.io/zone",
+	})
+	require.NoError(t, transform(context.Background(), nil, resources))
+
+	require.Len(t, resources.Items, 5)
+	injected := resources.Items[4]
+	assert.Equal(t, "PodDisruptionBudget", injected.GetKind())
+	assert.Equal(t, "highly-available", injected.GetName())
+	value, _, _ := unstructured.NestedString(injected.Object, "spec", "maxUnavailable")
+	assert.Equal(t, "25%", value)
+
+	for _, deployment := range resources.Items[:3] {
+		constraints, _, err := unstructured.NestedSlice(deployment.Object,
+			"spec", "template", "spec", "topologySpreadConstraints")
+		require.NoError(t, err)
+		if deployment.GetName() == "single" {
+			assert.Empty(t, constraints)
+		} else {
+			assert.Len(t, constraints, 1)
+		}
+	}
+
+	// repeated transforms do not inject constraints twice
+	require.NoError(t, transform(context.Background(), nil, resources))
+	constraints, _, _ := unstructured.NestedSlice(resources.Items[1].Object,
+		"spec", "template", "spec", "topologySpreadConstraints")
+	assert.Len(t, constraints, 1)
+}
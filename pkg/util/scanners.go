@@ -0,0 +1,189 @@
+package util
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+const (
+	privateKeyScannerName     = "private-key"
+	awsCredentialsScannerName = "aws-credentials"
+	imageDenyListScannerName  = "image-denylist"
+	privateKeyRule            = "private-key"
+	awsAccessKeyIDRule        = "aws-access-key-id"
+	awsSecretAccessKeyRule    = "aws-secret-access-key"
+	deniedImageRule           = "denied-image"
+	secretKind                = "Secret"
+	secretDataField           = "data"
+	dockerHubLibrary          = "library"
+)
+
+//nolint:gochecknoglobals
+var (
+	privateKeyPattern = regexp.MustCompile(
+		`-----BEGIN ((RSA|EC|DSA|OPENSSH|ENCRYPTED|PGP) )?PRIVATE KEY( BLOCK)?-----`)
+	awsAccessKeyIDPattern     = regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)
+	awsSecretAccessKeyPattern = regexp.MustCompile(
+		`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}\b`)
+)
+
+// DefaultContentScanners returns the built-in ContentScanners. Images matching any of the passed
+// deniedImages prefixes are reported by an image deny-list scanner.
+func DefaultContentScanners(deniedImages []string) []types.ContentScanner {
+	scanners := []types.ContentScanner{&PrivateKeyScanner{}, &AWSCredentialsScanner{}}
+	if len(deniedImages) > 0 {
+		scanners = append(scanners, &ImageDenyListScanner{Denied: deniedImages})
+	}
+	return scanners
+}
+
+// ScanObjects runs all passed scanners on the passed objects and returns their combined findings.
+func ScanObjects(ctx context.Context, scanners []types.ContentScanner, objects []*unstructured.Unstructured,
+) ([]types.SecurityFinding, error) {
+	var findings []types.SecurityFinding
+	for _, scanner := range scanners {
+		scannerFindings, err := scanner.Scan(ctx, objects)
+		if err != nil {
+			return nil, fmt.Errorf("content scanner %s failed: %w", scanner.Name(), err)
+		}
+		findings = append(findings, scannerFindings...)
+	}
+	return findings, nil
+}
+
+// PrivateKeyScanner detects PEM encoded private keys embedded in rendered resources.
+type PrivateKeyScanner struct{}
+
+func (s *PrivateKeyScanner) Name() string {
+	return privateKeyScannerName
+}
+
+func (s *PrivateKeyScanner) Scan(_ context.Context, objects []*unstructured.Unstructured,
+) ([]types.SecurityFinding, error) {
+	return scanStrings(objects, s.Name(), func(value string) (string, bool) {
+		return privateKeyRule, privateKeyPattern.MatchString(value)
+	}), nil
+}
+
+// AWSCredentialsScanner detects AWS access key IDs and secret access keys embedded in rendered resources.
+type AWSCredentialsScanner struct{}
+
+func (s *AWSCredentialsScanner) Name() string {
+	return awsCredentialsScannerName
+}
+
+func (s *AWSCredentialsScanner) Scan(_ context.Context, objects []*unstructured.Unstructured,
+) ([]types.SecurityFinding, error) {
+	return scanStrings(objects, s.Name(), func(value string) (string, bool) {
+		if awsSecretAccessKeyPattern.MatchString(value) {
+			return awsSecretAccessKeyRule, true
+		}
+		return awsAccessKeyIDRule, awsAccessKeyIDPattern.MatchString(value)
+	}), nil
+}
+
+// ImageDenyListScanner detects container images matching any of the Denied image prefixes,
+// e.g. "docker.io/library/busybox" or "evil.registry.io/".
+type ImageDenyListScanner struct {
+	Denied []string
+}
+
+func (s *ImageDenyListScanner) Name() string {
+	return imageDenyListScannerName
+}
+
+func (s *ImageDenyListScanner) Scan(_ context.Context, objects []*unstructured.Unstructured,
+) ([]types.SecurityFinding, error) {
+	var findings []types.SecurityFinding
+	for _, obj := range objects {
+		visitContainers(obj.Object, func(container map[string]any) {
+			image, isString := container["image"].(string)
+			if !isString || image == "" {
+				return
+			}
+			registry, repository := splitImageRegistry(image)
+			if registry == dockerHubRegistry && !strings.Contains(repository, "/") {
+				repository = dockerHubLibrary + "/" + repository
+			}
+			qualifiedImage := registry + "/" + repository
+			for _, denied := range s.Denied {
+				if strings.HasPrefix(image, denied) || strings.HasPrefix(qualifiedImage, denied) {
+					findings = append(findings, newFinding(obj, s.Name(), deniedImageRule,
+						fmt.Sprintf("container %v uses denied image %s", container["name"], image)))
+					return
+				}
+			}
+		})
+	}
+	return findings, nil
+}
+
+// scanStrings reports a finding for every string field of the objects matched by the passed function.
+// Data of Secrets is base64 decoded before matching.
+func scanStrings(objects []*unstructured.Unstructured, scanner string,
+	match func(value string) (string, bool),
+) []types.SecurityFinding {
+	var findings []types.SecurityFinding
+	for _, obj := range objects {
+		fields := make(map[string]string)
+		collectStrings(obj.Object, "", fields)
+		if obj.GetKind() == secretKind {
+			decodeSecretData(obj, fields)
+		}
+		paths := make([]string, 0, len(fields))
+		for path := range fields {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			if rule, matched := match(fields[path]); matched {
+				findings = append(findings, newFinding(obj, scanner, rule,
+					fmt.Sprintf("%s detected in field %s", rule, path)))
+			}
+		}
+	}
+	return findings
+}
+
+func collectStrings(obj any, path string, fields map[string]string) {
+	switch typed := obj.(type) {
+	case map[string]any:
+		for key, value := range typed {
+			collectStrings(value, strings.TrimPrefix(path+"."+key, "."), fields)
+		}
+	case []any:
+		for i, value := range typed {
+			collectStrings(value, fmt.Sprintf("%s[%d]", path, i), fields)
+		}
+	case string:
+		fields[path] = typed
+	}
+}
+
+func decodeSecretData(obj *unstructured.Unstructured, fields map[string]string) {
+	data, _, _ := unstructured.NestedStringMap(obj.Object, secretDataField)
+	for key, value := range data {
+		if decoded, err := base64.StdEncoding.DecodeString(value); err == nil {
+			fields[secretDataField+"."+key] = string(decoded)
+		}
+	}
+}
+
+func newFinding(obj *unstructured.Unstructured, scanner, rule, message string) types.SecurityFinding {
+	return types.SecurityFinding{
+		Scanner:   scanner,
+		Rule:      rule,
+		Kind:      obj.GetKind(),
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Message:   message,
+	}
+}
@@ -0,0 +1,199 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+const (
+	nodeNameField                 = "metadata.name"
+	reasonNodeSelectorMismatch    = "node(s) didn't match Pod's node affinity/selector"
+	reasonUntoleratedTaint        = "node(s) had untolerated taint"
+	reasonNodeSchedulingDisabled  = "node(s) were unschedulable"
+	schedulingIssueReasonTemplate = "0/%d nodes are available: %s"
+)
+
+//nolint:gochecknoglobals
+var (
+	// podSpecPaths are the paths of the pod template in the spec of workloads.
+	// DaemonSets are not included, as they only run on the nodes they match by design.
+	podSpecPaths = map[schema.GroupKind][]string{
+		{Group: "", Kind: "Pod"}:             {"spec"},
+		{Group: "apps", Kind: "Deployment"}:  {"spec", "template", "spec"},
+		{Group: "apps", Kind: "StatefulSet"}: {"spec", "template", "spec"},
+		{Group: "apps", Kind: "ReplicaSet"}:  {"spec", "template", "spec"},
+		{Group: "batch", Kind: "Job"}:        {"spec", "template", "spec"},
+		{Group: "batch", Kind: "CronJob"}:    {"spec", "jobTemplate", "spec", "template", "spec"},
+	}
+	nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+		corev1.NodeSelectorOpIn:           selection.In,
+		corev1.NodeSelectorOpNotIn:        selection.NotIn,
+		corev1.NodeSelectorOpExists:       selection.Exists,
+		corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+		corev1.NodeSelectorOpGt:           selection.GreaterThan,
+		corev1.NodeSelectorOpLt:           selection.LessThan,
+	}
+)
+
+// FindUnschedulableWorkloads returns a types.SchedulingIssue for every workload of the passed objects,
+// whose pods cannot be scheduled on any of the passed nodes because of their node selector,
+// required node affinity or untolerated taints. Resource requests and pod affinities are not verified,
+// as they depend on the current utilization of the cluster instead of its nodes.
+func FindUnschedulableWorkloads(objects []*unstructured.Unstructured, nodes []corev1.Node,
+) ([]types.SchedulingIssue, error) {
+	var issues []types.SchedulingIssue
+	for _, obj := range objects {
+		podSpec, err := podSpecOf(obj)
+		if err != nil {
+			return nil, err
+		}
+		if podSpec == nil {
+			continue
+		}
+		if reason, schedulable := fitsAnyNode(podSpec, nodes); !schedulable {
+			issues = append(issues, types.SchedulingIssue{
+				Kind:      obj.GetKind(),
+				Namespace: obj.GetNamespace(),
+				Name:      obj.GetName(),
+				Reason:    reason,
+			})
+		}
+	}
+	return issues, nil
+}
+
+// IsWorkload indicates if the object runs pods from a pod template.
+func IsWorkload(obj *unstructured.Unstructured) bool {
+	_, isWorkload := podSpecPaths[obj.GroupVersionKind().GroupKind()]
+	return isWorkload
+}
+
+// podSpecOf returns the pod template of the workload, or nil if the object is no workload.
+func podSpecOf(obj *unstructured.Unstructured) (*corev1.PodSpec, error) {
+	path, isWorkload := podSpecPaths[obj.GroupVersionKind().GroupKind()]
+	if !isWorkload {
+		return nil, nil
+	}
+	podSpecObject, found, err := unstructured.NestedMap(obj.Object, path...)
+	if err != nil || !found {
+		return nil, err
+	}
+	podSpec := &corev1.PodSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(podSpecObject, podSpec); err != nil {
+		return nil, fmt.Errorf("reading pod template of %s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	return podSpec, nil
+}
+
+// fitsAnyNode indicates if the pod fits on at least one node. Otherwise, it returns the reasons
+// for all nodes, summarized in the format of the scheduler.
+func fitsAnyNode(podSpec *corev1.PodSpec, nodes []corev1.Node) (string, bool) {
+	reasons := map[string]int{}
+	for i := range nodes {
+		reason := nodeMismatch(podSpec, &nodes[i])
+		if reason == "" {
+			return "", true
+		}
+		reasons[reason]++
+	}
+	summary := make([]string, 0, len(reasons))
+	for reason, count := range reasons {
+		summary = append(summary, fmt.Sprintf("%d %s", count, reason))
+	}
+	sort.Strings(summary)
+	return fmt.Sprintf(schedulingIssueReasonTemplate, len(nodes), strings.Join(summary, ", ")), false
+}
+
+// nodeMismatch returns the reason why the pod does not fit on the node, or an empty string if it does.
+func nodeMismatch(podSpec *corev1.PodSpec, node *corev1.Node) string {
+	switch {
+	case node.Spec.Unschedulable:
+		return reasonNodeSchedulingDisabled
+	case !matchesNodeSelector(podSpec, node):
+		return reasonNodeSelectorMismatch
+	case !toleratesTaints(podSpec.Tolerations, node.Spec.Taints):
+		return reasonUntoleratedTaint
+	}
+	return ""
+}
+
+func matchesNodeSelector(podSpec *corev1.PodSpec, node *corev1.Node) bool {
+	if !labels.SelectorFromSet(podSpec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	if podSpec.Affinity == nil || podSpec.Affinity.NodeAffinity == nil ||
+		podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	// terms are ORed, an empty list of terms matches no node
+	for _, term := range podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		if matchesNodeSelectorTerm(term, node) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesNodeSelectorTerm indicates if all requirements of the term match the node.
+// A term without any requirement matches no node.
+func matchesNodeSelectorTerm(term corev1.NodeSelectorTerm, node *corev1.Node) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	nodeFields := labels.Set{nodeNameField: node.Name}
+	for _, requirement := range term.MatchFields {
+		if requirement.Key != nodeNameField || !matchesRequirement(requirement, nodeFields) {
+			return false
+		}
+	}
+	for _, requirement := range term.MatchExpressions {
+		if !matchesRequirement(requirement, labels.Set(node.Labels)) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesRequirement(requirement corev1.NodeSelectorRequirement, values labels.Set) bool {
+	operator, known := nodeSelectorOperators[requirement.Operator]
+	if !known {
+		return false
+	}
+	selector, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+	if err != nil {
+		// invalid requirements are rejected by the API server, they never match
+		return false
+	}
+	return selector.Matches(values)
+}
+
+// toleratesTaints indicates if all taints preventing scheduling are tolerated.
+func toleratesTaints(tolerations []corev1.Toleration, taints []corev1.Taint) bool {
+	for i := range taints {
+		taint := &taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
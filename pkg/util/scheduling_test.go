@@ -0,0 +1,72 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func deploymentWithPodSpec(name string, podSpec map[string]any) *unstructured.Unstructured {
+	podSpec["containers"] = []any{map[string]any{"name": "app", "image": "app:1.0.0"}}
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": name, "namespace": "default"},
+		"spec":       map[string]any{"template": map[string]any{"spec": podSpec}},
+	}}
+}
+
+func Test_FindUnschedulableWorkloads(t *testing.T) {
+	t.Parallel()
+	nodes := []corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "linux", Labels: map[string]string{"kubernetes.io/os": "linux"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "gpu", Labels: map[string]string{"kubernetes.io/os": "linux"}},
+			Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+				{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "cordoned", Labels: map[string]string{"kubernetes.io/os": "windows"}},
+			Spec:       corev1.NodeSpec{Unschedulable: true},
+		},
+	}
+	gpuAffinity := map[string]any{"nodeAffinity": map[string]any{
+		"requiredDuringSchedulingIgnoredDuringExecution": map[string]any{"nodeSelectorTerms": []any{
+			map[string]any{"matchFields": []any{
+				map[string]any{"key": "metadata.name", "operator": "In", "values": []any{"gpu"}},
+			}},
+		}},
+	}}
+	objects := []*unstructured.Unstructured{
+		deploymentWithPodSpec("linux", map[string]any{"nodeSelector": map[string]any{"kubernetes.io/os": "linux"}}),
+		deploymentWithPodSpec("windows", map[string]any{"nodeSelector": map[string]any{"kubernetes.io/os": "windows"}}),
+		deploymentWithPodSpec("gpu-untolerated", map[string]any{"affinity": gpuAffinity}),
+		deploymentWithPodSpec("gpu", map[string]any{
+			"affinity":    gpuAffinity,
+			"tolerations": []any{map[string]any{"key": "gpu", "operator": "Exists", "effect": "NoSchedule"}},
+		}),
+		{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"name": "no-workload"},
+		}},
+	}
+
+	issues, err := util.FindUnschedulableWorkloads(objects, nodes)
+	require.NoError(t, err)
+	require.Len(t, issues, 2)
+	assert.Equal(t, "windows", issues[0].Name)
+	assert.Equal(t, "0/3 nodes are available: 1 node(s) were unschedulable, "+
+		"2 node(s) didn't match Pod's node affinity/selector", issues[0].Reason)
+	assert.Equal(t, "gpu-untolerated", issues[1].Name)
+	assert.Contains(t, issues[1].Reason, "1 node(s) had untolerated taint")
+}
@@ -0,0 +1,164 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// RestartedAtAnnotation is set on the pod template of restarted workloads, as done by kubectl rollout restart.
+const RestartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// restartableWorkloads are the workloads, whose pods are restarted by a change of their pod template.
+//
+//nolint:gochecknoglobals
+var restartableWorkloads = map[schema.GroupKind]bool{
+	{Group: "apps", Kind: "Deployment"}:  true,
+	{Group: "apps", Kind: "StatefulSet"}: true,
+	{Group: "apps", Kind: "DaemonSet"}:   true,
+}
+
+// SecretConsumers returns the Deployments, StatefulSets and DaemonSets of the passed objects, which reference
+// Secrets of the passed objects in their namespace by volumes or environment variables, together with a checksum
+// of the data of the referenced Secrets. Consumers listed in order as "<kind>/<name>" are returned first
+// and in the listed order, all other consumers follow in the order of the passed objects.
+func SecretConsumers(objects []*unstructured.Unstructured, order []string) ([]types.SecretConsumer, error) {
+	secrets := make(map[string]*unstructured.Unstructured)
+	for _, obj := range objects {
+		if obj.GroupVersionKind().GroupKind() == (schema.GroupKind{Kind: "Secret"}) {
+			secrets[obj.GetNamespace()+"/"+obj.GetName()] = obj
+		}
+	}
+
+	var consumers []types.SecretConsumer
+	for _, obj := range objects {
+		if !restartableWorkloads[obj.GroupVersionKind().GroupKind()] {
+			continue
+		}
+		template, found, err := unstructured.NestedMap(obj.Object, "spec", "template", "spec")
+		if err != nil || !found {
+			return nil, err
+		}
+		podSpec := &corev1.PodSpec{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template, podSpec); err != nil {
+			return nil, fmt.Errorf("reading pod template of %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		var referenced []interface{}
+		for _, name := range referencedSecrets(podSpec) {
+			if secret, rendered := secrets[obj.GetNamespace()+"/"+name]; rendered {
+				referenced = append(referenced, []interface{}{name, secret.Object["data"], secret.Object["stringData"]})
+			}
+		}
+		if len(referenced) == 0 {
+			continue
+		}
+		checksum, err := CalculateHash(referenced)
+		if err != nil {
+			return nil, err
+		}
+		consumers = append(consumers, types.SecretConsumer{Workload: obj, Checksum: strconv.FormatUint(uint64(checksum), 10)})
+	}
+
+	position := make(map[string]int, len(order))
+	for i, consumer := range order {
+		position[consumer] = i
+	}
+	rank := func(consumer types.SecretConsumer) int {
+		if i, listed := position[consumer.Workload.GetKind()+"/"+consumer.Workload.GetName()]; listed {
+			return i
+		}
+		return len(order)
+	}
+	sort.SliceStable(consumers, func(i, j int) bool {
+		return rank(consumers[i]) < rank(consumers[j])
+	})
+	return consumers, nil
+}
+
+// referencedSecrets returns the sorted names of the Secrets referenced by volumes and environment variables
+// of the pod spec.
+func referencedSecrets(podSpec *corev1.PodSpec) []string {
+	names := make(map[string]bool)
+	for _, volume := range podSpec.Volumes {
+		if volume.Secret != nil {
+			names[volume.Secret.SecretName] = true
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.Secret != nil {
+					names[source.Secret.Name] = true
+				}
+			}
+		}
+	}
+	for _, container := range append(podSpec.InitContainers, podSpec.Containers...) {
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.SecretRef != nil {
+				names[envFrom.SecretRef.Name] = true
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+				names[env.ValueFrom.SecretKeyRef.Name] = true
+			}
+		}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// RecordSecretChecksum records the checksum of the Secrets consumed by the applied workload.
+// If a different checksum was recorded before, the pods of the workload are restarted and true is returned.
+// The checksum of workloads without a recorded checksum is only recorded, as their pods consume the current Secrets.
+func RecordSecretChecksum(workload *unstructured.Unstructured, checksum string, now time.Time) (bool, error) {
+	annotations := workload.GetAnnotations()
+	recorded, wasRecorded := annotations[labels.SecretChecksumAnnotation]
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[labels.SecretChecksumAnnotation] = checksum
+	workload.SetAnnotations(annotations)
+	if !wasRecorded || recorded == checksum {
+		return false, nil
+	}
+	return true, unstructured.SetNestedField(workload.Object, now.UTC().Format(time.RFC3339),
+		"spec", "template", "metadata", "annotations", RestartedAtAnnotation)
+}
+
+// RolledOut indicates if all pods of the applied workload run its current pod template and are ready.
+func RolledOut(workload *unstructured.Unstructured) (bool, error) {
+	observedGeneration, _, err := unstructured.NestedInt64(workload.Object, "status", "observedGeneration")
+	if err != nil || observedGeneration < workload.GetGeneration() {
+		return false, err
+	}
+	desiredPath, updatedPath := []string{"spec", "replicas"}, []string{"status", "updatedReplicas"}
+	if workload.GetKind() == "DaemonSet" {
+		desiredPath, updatedPath = []string{"status", "desiredNumberScheduled"}, []string{"status", "updatedNumberScheduled"}
+	}
+	desired, found, err := unstructured.NestedInt64(workload.Object, desiredPath...)
+	if err != nil {
+		return false, err
+	}
+	if !found && workload.GetKind() != "DaemonSet" {
+		desired = 1
+	}
+	updated, _, err := unstructured.NestedInt64(workload.Object, updatedPath...)
+	if err != nil || updated < desired {
+		return false, err
+	}
+	issues, err := FindHealthIssues([]*unstructured.Unstructured{workload})
+	return len(issues) == 0, err
+}
@@ -0,0 +1,110 @@
+package util_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kyma-project/module-manager/pkg/labels"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func secretObject(name, password string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]any{"name": name, "namespace": "default"},
+		"stringData": map[string]any{"password": password},
+	}}
+}
+
+func workloadWithPodSpec(kind, name string, podSpec map[string]any) *unstructured.Unstructured {
+	return objectWithStatus("apps/v1", kind, name,
+		map[string]any{"template": map[string]any{"spec": podSpec}}, map[string]any{})
+}
+
+func secretConsumerObjects(password string) []*unstructured.Unstructured {
+	return []*unstructured.Unstructured{
+		secretObject("credentials", password),
+		workloadWithPodSpec("Deployment", "service", map[string]any{"containers": []any{map[string]any{
+			"name": "service",
+			"env": []any{map[string]any{"name": "PASSWORD", "valueFrom": map[string]any{
+				"secretKeyRef": map[string]any{"name": "credentials", "key": "password"},
+			}}},
+		}}}),
+		workloadWithPodSpec("StatefulSet", "database", map[string]any{
+			"containers": []any{map[string]any{"name": "database"}},
+			"volumes": []any{map[string]any{
+				"name": "credentials", "secret": map[string]any{"secretName": "credentials"},
+			}},
+		}),
+		workloadWithPodSpec("Deployment", "unrelated", map[string]any{
+			"containers": []any{map[string]any{"name": "unrelated", "envFrom": []any{map[string]any{
+				"secretRef": map[string]any{"name": "not-rendered"},
+			}}}},
+		}),
+	}
+}
+
+func Test_SecretConsumers(t *testing.T) {
+	t.Parallel()
+	consumers, err := util.SecretConsumers(secretConsumerObjects("initial"), []string{"StatefulSet/database"})
+	require.NoError(t, err)
+	require.Len(t, consumers, 2, "consumers of Secrets not part of the objects are ignored")
+	assert.Equal(t, "database", consumers[0].Workload.GetName(), "listed consumers are returned first")
+	assert.Equal(t, "service", consumers[1].Workload.GetName())
+	assert.Equal(t, consumers[0].Checksum, consumers[1].Checksum)
+
+	rotated, err := util.SecretConsumers(secretConsumerObjects("rotated"), nil)
+	require.NoError(t, err)
+	require.Len(t, rotated, 2)
+	assert.Equal(t, "service", rotated[0].Workload.GetName())
+	assert.NotEqual(t, consumers[1].Checksum, rotated[0].Checksum)
+}
+
+func Test_RecordSecretChecksum(t *testing.T) {
+	t.Parallel()
+	workload := workloadWithPodSpec("Deployment", "service", map[string]any{})
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	restarted, err := util.RecordSecretChecksum(workload, "1", now)
+	require.NoError(t, err)
+	assert.False(t, restarted, "the checksum of running pods is only recorded")
+	assert.Equal(t, "1", workload.GetAnnotations()[labels.SecretChecksumAnnotation])
+
+	restarted, err = util.RecordSecretChecksum(workload, "2", now)
+	require.NoError(t, err)
+	assert.True(t, restarted)
+	restartedAt, _, err := unstructured.NestedString(workload.Object,
+		"spec", "template", "metadata", "annotations", util.RestartedAtAnnotation)
+	require.NoError(t, err)
+	assert.Equal(t, "2022-10-01T12:00:00Z", restartedAt)
+}
+
+func Test_RolledOut(t *testing.T) {
+	t.Parallel()
+	rollingOut := objectWithStatus("apps/v1", "Deployment", "service", map[string]any{"replicas": int64(2)},
+		map[string]any{"observedGeneration": int64(2), "updatedReplicas": int64(1), "readyReplicas": int64(2)})
+	rollingOut.SetGeneration(2)
+	rolledOut, err := util.RolledOut(rollingOut)
+	require.NoError(t, err)
+	assert.False(t, rolledOut, "ready pods of the previous template do not count")
+
+	notObserved := objectWithStatus("apps/v1", "StatefulSet", "database", map[string]any{},
+		map[string]any{"observedGeneration": int64(1), "updatedReplicas": int64(1), "readyReplicas": int64(1)})
+	notObserved.SetGeneration(2)
+	rolledOut, err = util.RolledOut(notObserved)
+	require.NoError(t, err)
+	assert.False(t, rolledOut)
+
+	daemonSet := objectWithStatus("apps/v1", "DaemonSet", "agent", map[string]any{}, map[string]any{
+		"desiredNumberScheduled": int64(2), "currentNumberScheduled": int64(2),
+		"updatedNumberScheduled": int64(2), "numberReady": int64(2),
+	})
+	rolledOut, err = util.RolledOut(daemonSet)
+	require.NoError(t, err)
+	assert.True(t, rolledOut)
+}
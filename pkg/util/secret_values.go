@@ -0,0 +1,101 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// ResolveSecretValues sets the chart values at the paths of the passed SecretValues to the values
+// of the referenced keys of Secrets in namespace, taking precedence over the passed values.
+// It returns the merged values and the resolved secret values, e.g. to redact them from logs.
+func ResolveSecretValues(ctx context.Context, clnt client.Reader, namespace string,
+	secretValues []types.SecretValue, values map[string]any,
+) (map[string]any, []string, error) {
+	resolved := make([]string, 0, len(secretValues))
+	for _, secretValue := range secretValues {
+		ref := secretValue.SecretKeyRef
+		optional := ref.Optional != nil && *ref.Optional
+		secret := &corev1.Secret{}
+		if err := clnt.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return nil, nil, fmt.Errorf("getting secret %s for value %s: %w", ref.Name, secretValue.Path, err)
+			}
+			if optional {
+				continue
+			}
+			return nil, nil, types.ErrSecretValueNotFound.Wrap(
+				fmt.Errorf("secret %s/%s for value %s", namespace, ref.Name, secretValue.Path))
+		}
+		data, found := secret.Data[ref.Key]
+		if !found {
+			if optional {
+				continue
+			}
+			return nil, nil, types.ErrSecretValueNotFound.Wrap(
+				fmt.Errorf("key %s of secret %s/%s for value %s", ref.Key, namespace, ref.Name, secretValue.Path))
+		}
+		if values == nil {
+			values = map[string]any{}
+		}
+		if err := setValue(values, secretValue.Path, string(data)); err != nil {
+			return nil, nil, err
+		}
+		resolved = append(resolved, string(data))
+	}
+	return values, resolved, nil
+}
+
+// SecretValuesChecksum returns a checksum of the keys of Secrets in namespace referenced by the passed SecretValues.
+// Edits of Secrets not changing referenced keys keep the checksum, missing Secrets and keys are left out.
+func SecretValuesChecksum(ctx context.Context, clnt client.Reader, namespace string,
+	secretValues []types.SecretValue,
+) (string, error) {
+	referenced := make([]any, 0, len(secretValues))
+	for _, secretValue := range secretValues {
+		ref := secretValue.SecretKeyRef
+		secret := &corev1.Secret{}
+		if err := clnt.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return "", fmt.Errorf("getting secret %s for value %s: %w", ref.Name, secretValue.Path, err)
+			}
+			continue
+		}
+		if data, found := secret.Data[ref.Key]; found {
+			referenced = append(referenced, []any{secretValue.Path, ref.Name, ref.Key, data})
+		}
+	}
+	checksum, err := CalculateHash(referenced)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatUint(uint64(checksum), 10), nil
+}
+
+// setValue sets the value at the dot-separated path, creating missing maps along the path.
+func setValue(values map[string]any, path, value string) error {
+	keys := strings.Split(path, ".")
+	current := values
+	for i, key := range keys[:len(keys)-1] {
+		next, found := current[key]
+		if !found || next == nil {
+			nested := map[string]any{}
+			current[key] = nested
+			current = nested
+			continue
+		}
+		nested, isMap := next.(map[string]any)
+		if !isMap {
+			return fmt.Errorf("value %s: %s is not a map", path, strings.Join(keys[:i+1], "."))
+		}
+		current = nested
+	}
+	current[keys[len(keys)-1]] = value
+	return nil
+}
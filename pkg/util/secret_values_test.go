@@ -0,0 +1,83 @@
+package util_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func secretValue(path, name, key string, optional bool) types.SecretValue {
+	return types.SecretValue{Path: path, SecretKeyRef: corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: key, Optional: &optional,
+	}}
+}
+
+func Test_ResolveSecretValues(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "redis-auth", Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte("s3cr3t")},
+	}
+	clnt := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+
+	values, resolved, err := util.ResolveSecretValues(ctx, clnt, "default", []types.SecretValue{
+		secretValue("auth.password", "redis-auth", "password", false),
+		secretValue("auth.token", "redis-auth", "token", true),
+		secretValue("tls.key", "redis-tls", "tls.key", true),
+	}, map[string]any{"auth": map[string]any{"enabled": true}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"auth": map[string]any{"enabled": true, "password": "s3cr3t"}}, values)
+	assert.Equal(t, []string{"s3cr3t"}, resolved)
+
+	_, _, err = util.ResolveSecretValues(ctx, clnt, "default", []types.SecretValue{
+		secretValue("auth.token", "redis-auth", "token", false),
+	}, nil)
+	require.ErrorIs(t, err, types.ErrSecretValueNotFound)
+	_, _, err = util.ResolveSecretValues(ctx, clnt, "kyma-system", []types.SecretValue{
+		secretValue("auth.password", "redis-auth", "password", false),
+	}, nil)
+	require.ErrorIs(t, err, types.ErrSecretValueNotFound)
+	_, _, err = util.ResolveSecretValues(ctx, clnt, "default", []types.SecretValue{
+		secretValue("auth.password.value", "redis-auth", "password", false),
+	}, map[string]any{"auth": map[string]any{"password": "plain"}})
+	require.Error(t, err)
+}
+
+func Test_SecretValuesChecksum(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "redis-auth", Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte("s3cr3t"), "user": []byte("admin")},
+	}
+	clnt := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+	secretValues := []types.SecretValue{
+		secretValue("auth.password", "redis-auth", "password", false),
+		secretValue("tls.key", "redis-tls", "tls.key", true),
+	}
+
+	checksum, err := util.SecretValuesChecksum(ctx, clnt, "default", secretValues)
+	require.NoError(t, err)
+
+	secret.Data["user"] = []byte("root")
+	require.NoError(t, clnt.Update(ctx, secret))
+	unchanged, err := util.SecretValuesChecksum(ctx, clnt, "default", secretValues)
+	require.NoError(t, err)
+	assert.Equal(t, checksum, unchanged, "keys which are not referenced do not change the checksum")
+
+	secret.Data["password"] = []byte("rotated")
+	require.NoError(t, clnt.Update(ctx, secret))
+	changed, err := util.SecretValuesChecksum(ctx, clnt, "default", secretValues)
+	require.NoError(t, err)
+	assert.NotEqual(t, checksum, changed)
+}
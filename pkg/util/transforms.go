@@ -0,0 +1,299 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+const dockerHubRegistry = "docker.io"
+
+// TransformsFromSpecs converts declarative TransformSpecs into ObjectTransforms that can be chained
+// as a post-render pipeline. Transforms are executed in the order of the passed specs.
+func TransformsFromSpecs(specs []types.TransformSpec) ([]types.ObjectTransform, error) {
+	transforms := make([]types.ObjectTransform, 0, len(specs))
+	for i := range specs {
+		transform, err := transformFromSpec(specs[i])
+		if err != nil {
+			return nil, fmt.Errorf("transform %v of type %s is invalid: %w", i, specs[i].Type, err)
+		}
+		transforms = append(transforms, transform)
+	}
+	return transforms, nil
+}
+
+func transformFromSpec(spec types.TransformSpec) (types.ObjectTransform, error) {
+	var mutate func(*unstructured.Unstructured) error
+
+	switch spec.Type {
+	case types.LabelsTransform:
+		mutate = func(obj *unstructured.Unstructured) error {
+			obj.SetLabels(mergeStringMaps(obj.GetLabels(), spec.Values))
+			return nil
+		}
+	case types.AnnotationsTransform:
+		mutate = func(obj *unstructured.Unstructured) error {
+			obj.SetAnnotations(mergeStringMaps(obj.GetAnnotations(), spec.Values))
+			return nil
+		}
+	case types.NamespaceTransform:
+		if spec.Namespace == "" {
+			return nil, fmt.Errorf("namespace is required")
+		}
+		mutate = func(obj *unstructured.Unstructured) error {
+			// resources without namespace are either cluster-scoped or defaulted during apply
+			if obj.GetNamespace() != "" {
+				obj.SetNamespace(spec.Namespace)
+			}
+			return nil
+		}
+	case types.ImageRegistryTransform:
+		if spec.Registry == nil || spec.Registry.To == "" {
+			return nil, fmt.Errorf("target registry is required")
+		}
+		mutate = func(obj *unstructured.Unstructured) error {
+			rewriteImages(obj.Object, *spec.Registry)
+			return nil
+		}
+	case types.StrategicMergePatchTransform:
+		patch, err := yaml.YAMLToJSON([]byte(spec.Patch))
+		if err != nil {
+			return nil, err
+		}
+		mutate = func(obj *unstructured.Unstructured) error {
+			return strategicMergePatch(obj, patch)
+		}
+	case types.JSON6902PatchTransform:
+		rawPatch, err := yaml.YAMLToJSON([]byte(spec.Patch))
+		if err != nil {
+			return nil, err
+		}
+		patch, err := jsonpatch.DecodePatch(rawPatch)
+		if err != nil {
+			return nil, err
+		}
+		mutate = func(obj *unstructured.Unstructured) error {
+			return applyJSONPatch(obj, func(original []byte) ([]byte, error) {
+				return patch.Apply(original)
+			})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported transform type")
+	}
+
+	return func(_ context.Context, _ types.BaseCustomObject, resources *types.ManifestResources) error {
+		for _, obj := range resources.Items {
+			if !targetMatches(spec.Target, obj) {
+				continue
+			}
+			if err := mutate(obj); err != nil {
+				return fmt.Errorf("%s transform of %s/%s failed: %w",
+					spec.Type, obj.GetKind(), obj.GetName(), err)
+			}
+		}
+		return nil
+	}, nil
+}
+
+// ExclusionTransform returns an ObjectTransform removing all resources matching any of the passed selectors,
+// e.g. ServiceMonitors bundled with a chart for clusters without Prometheus.
+func ExclusionTransform(selectors []types.ResourceSelector) (types.ObjectTransform, error) {
+	labelSelectors := make([]labels.Selector, len(selectors))
+	for i, selector := range selectors {
+		if selector.TransformTarget == (types.TransformTarget{}) && selector.LabelSelector == nil {
+			return nil, fmt.Errorf("exclusion %v selects all resources", i)
+		}
+		labelSelectors[i] = labels.Everything()
+		if selector.LabelSelector == nil {
+			continue
+		}
+		labelSelector, err := metav1.LabelSelectorAsSelector(selector.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("exclusion %v has an invalid label selector: %w", i, err)
+		}
+		labelSelectors[i] = labelSelector
+	}
+	excluded := func(obj *unstructured.Unstructured) bool {
+		for i := range selectors {
+			if targetMatches(&selectors[i].TransformTarget, obj) &&
+				labelSelectors[i].Matches(labels.Set(obj.GetLabels())) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(_ context.Context, _ types.BaseCustomObject, resources *types.ManifestResources) error {
+		included := resources.Items[:0]
+		for _, obj := range resources.Items {
+			if !excluded(obj) {
+				included = append(included, obj)
+			}
+		}
+		resources.Items = included
+		return nil
+	}, nil
+}
+
+// SkipTransform returns an ObjectTransform removing all resources matching any of the passed patterns,
+// e.g. PrometheusRules bundled with a chart, which conflict with rules managed elsewhere.
+func SkipTransform(patterns []types.ResourcePattern) (types.ObjectTransform, error) {
+	if err := ValidateResourcePatterns(patterns); err != nil {
+		return nil, fmt.Errorf("skipped %w", err)
+	}
+	return func(_ context.Context, _ types.BaseCustomObject, resources *types.ManifestResources) error {
+		included := resources.Items[:0]
+		for _, obj := range resources.Items {
+			if !MatchesResourcePatterns(patterns, obj.GroupVersionKind(), obj.GetName()) {
+				included = append(included, obj)
+			}
+		}
+		resources.Items = included
+		return nil
+	}, nil
+}
+
+// ValidateResourcePatterns refuses empty patterns, which match all resources, and patterns with invalid syntax.
+func ValidateResourcePatterns(patterns []types.ResourcePattern) error {
+	for i, pattern := range patterns {
+		if pattern == (types.ResourcePattern{}) {
+			return fmt.Errorf("resource pattern %v matches all resources", i)
+		}
+		for _, field := range []string{pattern.Group, pattern.Version, pattern.Kind, pattern.Name} {
+			if _, err := path.Match(field, ""); err != nil {
+				return fmt.Errorf("resource pattern %v is invalid: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// MatchesResourcePatterns indicates if a resource of the passed type and name matches any of the patterns.
+// Invalid patterns do not match any resource.
+func MatchesResourcePatterns(patterns []types.ResourcePattern, gvk schema.GroupVersionKind, name string) bool {
+	matches := func(pattern, value string) bool {
+		if pattern == "" {
+			return true
+		}
+		matched, err := path.Match(pattern, value)
+		return err == nil && matched
+	}
+	for _, pattern := range patterns {
+		if matches(pattern.Group, gvk.Group) && matches(pattern.Version, gvk.Version) &&
+			matches(pattern.Kind, gvk.Kind) && matches(pattern.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func targetMatches(target *types.TransformTarget, obj *unstructured.Unstructured) bool {
+	if target == nil {
+		return true
+	}
+	gvk := obj.GroupVersionKind()
+	matches := func(expected, actual string) bool { return expected == "" || expected == actual }
+	return matches(target.Group, gvk.Group) && matches(target.Version, gvk.Version) &&
+		matches(target.Kind, gvk.Kind) && matches(target.Name, obj.GetName()) &&
+		matches(target.Namespace, obj.GetNamespace())
+}
+
+func mergeStringMaps(existing, additional map[string]string) map[string]string {
+	if existing == nil {
+		existing = make(map[string]string, len(additional))
+	}
+	for key, value := range additional {
+		existing[key] = value
+	}
+	return existing
+}
+
+// visitContainers traverses the object and calls visit for every container
+// found in "containers", "initContainers" or "ephemeralContainers" lists.
+func visitContainers(obj any, visit func(container map[string]any)) {
+	switch typed := obj.(type) {
+	case map[string]any:
+		for key, value := range typed {
+			if key == "containers" || key == "initContainers" || key == "ephemeralContainers" {
+				if containers, isList := value.([]any); isList {
+					for _, container := range containers {
+						if containerMap, isMap := container.(map[string]any); isMap {
+							visit(containerMap)
+						}
+					}
+				}
+			}
+			visitContainers(value, visit)
+		}
+	case []any:
+		for _, value := range typed {
+			visitContainers(value, visit)
+		}
+	}
+}
+
+func rewriteImages(obj any, rewrite types.RegistryRewrite) {
+	visitContainers(obj, func(container map[string]any) {
+		image, isString := container["image"].(string)
+		if !isString || image == "" {
+			return
+		}
+		registry, repository := splitImageRegistry(image)
+		if rewrite.From != "" && rewrite.From != registry {
+			return
+		}
+		container["image"] = strings.TrimSuffix(rewrite.To, "/") + "/" + repository
+	})
+}
+
+// splitImageRegistry splits an image reference into its registry and the remaining repository reference,
+// following the same rules as the docker CLI to determine if the first path component is a registry host.
+func splitImageRegistry(image string) (string, string) {
+	parts := strings.SplitN(image, "/", 2) //nolint:gomnd
+	if len(parts) == 1 ||
+		(!strings.ContainsAny(parts[0], ".:") && parts[0] != "localhost") {
+		return dockerHubRegistry, image
+	}
+	return parts[0], parts[1]
+}
+
+func strategicMergePatch(obj *unstructured.Unstructured, patch []byte) error {
+	return applyJSONPatch(obj, func(original []byte) ([]byte, error) {
+		typed, err := scheme.Scheme.New(obj.GroupVersionKind())
+		if err != nil {
+			// resources without a native schema do not have merge keys, so they fall back to a merge patch
+			return jsonpatch.MergePatch(original, patch)
+		}
+		return strategicpatch.StrategicMergePatch(original, patch, typed)
+	})
+}
+
+func applyJSONPatch(obj *unstructured.Unstructured, patchFn func([]byte) ([]byte, error)) error {
+	original, err := json.Marshal(obj.Object)
+	if err != nil {
+		return err
+	}
+	patched, err := patchFn(original)
+	if err != nil {
+		return err
+	}
+	patchedObject := map[string]any{}
+	if err := json.Unmarshal(patched, &patchedObject); err != nil {
+		return err
+	}
+	obj.Object = patchedObject
+	return nil
+}
@@ -0,0 +1,213 @@
+package util_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+// transformTestResources returns a namespaced Deployment, a cluster-scoped ClusterRole
+// and a namespaced custom resource without a native schema.
+func transformTestResources() *types.ManifestResources {
+	clusterRole := objectWithStatus("rbac.authorization.k8s.io/v1", "ClusterRole", "reader", nil, nil)
+	clusterRole.SetNamespace("")
+	return &types.ManifestResources{Items: []*unstructured.Unstructured{
+		deploymentWithReplicas("app", 1), clusterRole,
+		objectWithStatus("example.com/v1", "Widget", "widget", map[string]any{"size": int64(1)}, nil),
+	}}
+}
+
+func Test_TransformsFromSpecs(t *testing.T) {
+	t.Parallel()
+	deploymentTarget := &types.TransformTarget{Group: "apps", Kind: "Deployment"}
+	nestedString := func(obj *unstructured.Unstructured, fields ...string) string {
+		value, _, _ := unstructured.NestedString(obj.Object, fields...)
+		return value
+	}
+	containerField := func(obj *unstructured.Unstructured, fields ...string) any {
+		containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		value, _, _ := unstructured.NestedFieldNoCopy(containers[0].(map[string]any), fields...)
+		return value
+	}
+	tests := []struct {
+		name   string
+		spec   types.TransformSpec
+		verify func(t *testing.T, deployment, clusterRole, widget *unstructured.Unstructured)
+	}{
+		{
+			name: "labels of targeted resources",
+			spec: types.TransformSpec{
+				Type: types.LabelsTransform, Target: deploymentTarget, Values: map[string]string{"team": "kyma"},
+			},
+			verify: func(t *testing.T, deployment, clusterRole, widget *unstructured.Unstructured) {
+				t.Helper()
+				assert.Equal(t, map[string]string{"team": "kyma"}, deployment.GetLabels())
+				assert.Empty(t, clusterRole.GetLabels())
+				assert.Empty(t, widget.GetLabels())
+			},
+		},
+		{
+			name: "annotations of all resources",
+			spec: types.TransformSpec{Type: types.AnnotationsTransform, Values: map[string]string{"owner": "kyma"}},
+			verify: func(t *testing.T, deployment, clusterRole, widget *unstructured.Unstructured) {
+				t.Helper()
+				for _, obj := range []*unstructured.Unstructured{deployment, clusterRole, widget} {
+					assert.Equal(t, map[string]string{"owner": "kyma"}, obj.GetAnnotations())
+				}
+			},
+		},
+		{
+			name: "namespace of namespaced resources",
+			spec: types.TransformSpec{Type: types.NamespaceTransform, Namespace: "kyma-system"},
+			verify: func(t *testing.T, deployment, clusterRole, widget *unstructured.Unstructured) {
+				t.Helper()
+				assert.Equal(t, "kyma-system", deployment.GetNamespace())
+				assert.Equal(t, "kyma-system", widget.GetNamespace())
+				assert.Empty(t, clusterRole.GetNamespace(), "cluster-scoped resources keep having no namespace")
+			},
+		},
+		{
+			name: "image registry",
+			spec: types.TransformSpec{
+				Type: types.ImageRegistryTransform, Registry: &types.RegistryRewrite{To: "registry.example.com/mirror/"},
+			},
+			verify: func(t *testing.T, deployment, _, _ *unstructured.Unstructured) {
+				t.Helper()
+				assert.Equal(t, "registry.example.com/mirror/app:1.0.0", containerField(deployment, "image"))
+			},
+		},
+		{
+			name: "image registry of other registries",
+			spec: types.TransformSpec{
+				Type:     types.ImageRegistryTransform,
+				Registry: &types.RegistryRewrite{From: "quay.io", To: "registry.example.com"},
+			},
+			verify: func(t *testing.T, deployment, _, _ *unstructured.Unstructured) {
+				t.Helper()
+				assert.Equal(t, "app:1.0.0", containerField(deployment, "image"), "images of docker.io are kept")
+			},
+		},
+		{
+			name: "strategic merge patch of native resources",
+			spec: types.TransformSpec{
+				Type: types.StrategicMergePatchTransform, Target: deploymentTarget,
+				Patch: "spec:\n  template:\n    spec:\n      containers:\n      - name: app\n" +
+					"        resources:\n          limits:\n            cpu: 100m\n",
+			},
+			verify: func(t *testing.T, deployment, _, _ *unstructured.Unstructured) {
+				t.Helper()
+				assert.Equal(t, "100m", containerField(deployment, "resources", "limits", "cpu"))
+				assert.Equal(t, "app:1.0.0", containerField(deployment, "image"), "containers are merged by name")
+			},
+		},
+		{
+			name: "strategic merge patch of custom resources",
+			spec: types.TransformSpec{
+				Type: types.StrategicMergePatchTransform, Target: &types.TransformTarget{Kind: "Widget"},
+				Patch: `{"spec": {"color": "blue"}}`,
+			},
+			verify: func(t *testing.T, _, _, widget *unstructured.Unstructured) {
+				t.Helper()
+				assert.Equal(t, "blue", nestedString(widget, "spec", "color"))
+				size, _, _ := unstructured.NestedFieldNoCopy(widget.Object, "spec", "size")
+				assert.EqualValues(t, 1, size, "custom resources fall back to a merge patch")
+			},
+		},
+		{
+			name: "json 6902 patch",
+			spec: types.TransformSpec{
+				Type: types.JSON6902PatchTransform, Target: deploymentTarget,
+				Patch: "- op: replace\n  path: /spec/replicas\n  value: 3\n",
+			},
+			verify: func(t *testing.T, deployment, _, _ *unstructured.Unstructured) {
+				t.Helper()
+				replicas, _, _ := unstructured.NestedFieldNoCopy(deployment.Object, "spec", "replicas")
+				assert.EqualValues(t, 3, replicas)
+			},
+		},
+	}
+	for _, testCase := range tests {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			transforms, err := util.TransformsFromSpecs([]types.TransformSpec{testCase.spec})
+			require.NoError(t, err)
+			require.Len(t, transforms, 1)
+			resources := transformTestResources()
+			require.NoError(t, transforms[0](context.Background(), nil, resources))
+			testCase.verify(t, resources.Items[0], resources.Items[1], resources.Items[2])
+		})
+	}
+}
+
+func Test_TransformsFromSpecs_Invalid(t *testing.T) {
+	t.Parallel()
+	for _, spec := range []types.TransformSpec{
+		{Type: types.NamespaceTransform},
+		{Type: types.ImageRegistryTransform, Registry: &types.RegistryRewrite{From: "docker.io"}},
+		{Type: types.JSON6902PatchTransform, Patch: `{"op": "replace"}`},
+		{Type: "Unknown"},
+	} {
+		_, err := util.TransformsFromSpecs([]types.TransformSpec{{Type: types.LabelsTransform}, spec})
+		assert.Error(t, err, "transforms of type %s are refused", spec.Type)
+	}
+
+	transforms, err := util.TransformsFromSpecs([]types.TransformSpec{{
+		Type: types.JSON6902PatchTransform, Patch: `[{"op": "remove", "path": "/spec/missing"}]`,
+	}})
+	require.NoError(t, err)
+	assert.Error(t, transforms[0](context.Background(), nil, transformTestResources()),
+		"patches failing for a resource fail the transform")
+}
+
+func Test_ExclusionTransform(t *testing.T) {
+	t.Parallel()
+	serviceMonitor := objectWithStatus("monitoring.coreos.com/v1", "ServiceMonitor", "metrics", nil, nil)
+	optional := deploymentWithReplicas("optional", 1)
+	optional.SetLabels(map[string]string{"component": "optional"})
+	resources := &types.ManifestResources{Items: []*unstructured.Unstructured{
+		deploymentWithReplicas("app", 1), serviceMonitor, optional,
+	}}
+
+	transform, err := util.ExclusionTransform([]types.ResourceSelector{
+		{TransformTarget: types.TransformTarget{Group: "monitoring.coreos.com", Kind: "ServiceMonitor"}},
+		{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"component": "optional"}}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, transform(context.Background(), nil, resources))
+	require.Len(t, resources.Items, 1)
+	assert.Equal(t, "app", resources.Items[0].GetName())
+
+	_, err = util.ExclusionTransform([]types.ResourceSelector{{}})
+	require.Error(t, err, "an empty selector would exclude all resources")
+}
+
+func Test_SkipTransform(t *testing.T) {
+	t.Parallel()
+	alerts := objectWithStatus("monitoring.coreos.com/v1", "PrometheusRule", "redis-alerts", nil, nil)
+	recordings := objectWithStatus("monitoring.coreos.com/v1", "PrometheusRule", "redis-recordings", nil, nil)
+	resources := &types.ManifestResources{Items: []*unstructured.Unstructured{
+		deploymentWithReplicas("redis-alerts", 1), alerts, recordings,
+	}}
+
+	patterns := []types.ResourcePattern{{Group: "monitoring.coreos.com", Kind: "PrometheusRule", Name: "*-alerts"}}
+	transform, err := util.SkipTransform(patterns)
+	require.NoError(t, err)
+	require.NoError(t, transform(context.Background(), nil, resources))
+	require.Len(t, resources.Items, 2)
+	assert.Equal(t, "Deployment", resources.Items[0].GetKind(), "resources of other kinds are kept")
+	assert.Equal(t, "redis-recordings", resources.Items[1].GetName())
+	assert.True(t, util.MatchesResourcePatterns(patterns, alerts.GroupVersionKind(), "kafka-alerts"))
+
+	_, err = util.SkipTransform([]types.ResourcePattern{{}})
+	require.Error(t, err, "an empty pattern would skip all resources")
+	_, err = util.SkipTransform([]types.ResourcePattern{{Name: "[invalid"}})
+	require.Error(t, err)
+}
@@ -0,0 +1,43 @@
+package util
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+// ValidateUpgrade returns a types.ErrUpgradeNotAllowed, if changing the installed chart version to the target version
+// violates the passed policy, i.e. if it is a downgrade without types.UpgradePolicy.AllowDowngrades, or an upgrade
+// skipping a mandatory version between both versions. Without a policy or an installed version, any change is valid.
+func ValidateUpgrade(installed, target string, policy *types.UpgradePolicy) error {
+	if policy == nil || installed == "" || target == "" || installed == target {
+		return nil
+	}
+	installedVersion, err := semver.NewVersion(installed)
+	if err != nil {
+		return types.ErrUpgradeNotAllowed.Wrap(fmt.Errorf("installed version %q: %w", installed, err))
+	}
+	targetVersion, err := semver.NewVersion(target)
+	if err != nil {
+		return types.ErrUpgradeNotAllowed.Wrap(fmt.Errorf("target version %q: %w", target, err))
+	}
+	if targetVersion.LessThan(installedVersion) {
+		if policy.AllowDowngrades {
+			return nil
+		}
+		return types.ErrUpgradeNotAllowed.Wrap(fmt.Errorf("downgrade from %s to %s", installed, target))
+	}
+	for _, mandatory := range policy.MandatoryVersions {
+		mandatoryVersion, err := semver.NewVersion(mandatory)
+		if err != nil {
+			return types.ErrUpgradeNotAllowed.Wrap(fmt.Errorf("mandatory version %q: %w", mandatory, err))
+		}
+		if mandatoryVersion.GreaterThan(installedVersion) && mandatoryVersion.LessThan(targetVersion) {
+			return types.ErrUpgradeNotAllowed.Wrap(fmt.Errorf("upgrade from %s to %s skips mandatory version %s",
+				installed, target, mandatory))
+		}
+	}
+	return nil
+}
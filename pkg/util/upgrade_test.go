@@ -0,0 +1,40 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func Test_ValidateUpgrade(t *testing.T) {
+	t.Parallel()
+	policy := &types.UpgradePolicy{MandatoryVersions: []string{"1.5.0", "2.0.0"}}
+	tests := []struct {
+		installed, target string
+		allowed           bool
+	}{
+		{"", "3.0.0", true},
+		{"1.2.0", "1.2.0", true},
+		{"1.2.0", "1.4.0", true},
+		{"1.2.0", "1.5.0", true},
+		{"1.5.0", "1.9.0", true},
+		{"1.2.0", "1.6.0", false},
+		{"1.5.0", "2.1.0", false},
+		{"1.4.0", "1.3.0", false},
+		{"1.4.0", "not-a-version", false},
+	}
+	for _, test := range tests {
+		err := util.ValidateUpgrade(test.installed, test.target, policy)
+		if test.allowed {
+			assert.NoError(t, err, "%s to %s", test.installed, test.target)
+		} else {
+			assert.ErrorIs(t, err, types.ErrUpgradeNotAllowed, "%s to %s", test.installed, test.target)
+		}
+	}
+
+	assert.NoError(t, util.ValidateUpgrade("1.4.0", "1.3.0", &types.UpgradePolicy{AllowDowngrades: true}))
+	assert.NoError(t, util.ValidateUpgrade("1.2.0", "3.0.0", nil), "without a policy any change is allowed")
+}
@@ -1,7 +1,6 @@
 package util
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -14,11 +13,13 @@ import (
 	"path/filepath"
 	"strings"
 
+	"go.opentelemetry.io/otel/attribute"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	yamlUtil "k8s.io/apimachinery/pkg/util/yaml"
 
+	"github.com/kyma-project/module-manager/pkg/log"
+	"github.com/kyma-project/module-manager/pkg/tracing"
 	"github.com/kyma-project/module-manager/pkg/types"
 
 	"github.com/pkg/errors"
@@ -33,14 +34,14 @@ import (
 const (
 	ManifestDir                     = "manifest"
 	manifestFile                    = "manifest.yaml"
-	configFileName                  = "installConfig.yaml"
 	YamlDecodeBufferSize            = 2048
 	OthersReadExecuteFilePermission = 0o755
-	DebugLogLevel                   = 2
-	TraceLogLevel                   = 3
+	DebugLogLevel                   = log.DebugLevel
+	TraceLogLevel                   = log.TraceLevel
 )
 
-func GetNamespaceObjBytes(clientNs string) ([]byte, error) {
+// GetNamespaceObjBytes returns the YAML of the namespace, labeled with its name and the passed labels.
+func GetNamespaceObjBytes(clientNs string, nsLabels map[string]string) ([]byte, error) {
 	namespace := v1.Namespace{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "v1",
@@ -53,6 +54,9 @@ func GetNamespaceObjBytes(clientNs string) ([]byte, error) {
 			},
 		},
 	}
+	for key, value := range nsLabels {
+		namespace.Labels[key] = value
+	}
 	return yaml.Marshal(namespace)
 }
 
@@ -114,38 +118,7 @@ func CleanFilePathJoin(root, destDir string) (string, error) {
 }
 
 func ParseManifestStringToObjects(manifest string) (*types.ManifestResources, error) {
-	objects := &types.ManifestResources{}
-	reader := yamlUtil.NewYAMLReader(bufio.NewReader(strings.NewReader(manifest)))
-	for {
-		rawBytes, err := reader.Read()
-		if err != nil {
-			if errors.Is(err, io.EOF) {
-				return objects, nil
-			}
-
-			return nil, fmt.Errorf("invalid YAML doc: %w", err)
-		}
-
-		rawBytes = bytes.TrimSpace(rawBytes)
-		unstructuredObj := unstructured.Unstructured{}
-		if err := yaml.Unmarshal(rawBytes, &unstructuredObj); err != nil {
-			objects.Blobs = append(objects.Blobs, append(bytes.TrimPrefix(rawBytes, []byte("---\n")), '\n'))
-		}
-
-		if len(rawBytes) == 0 || bytes.Equal(rawBytes, []byte("null")) || len(unstructuredObj.Object) == 0 {
-			continue
-		}
-
-		objects.Items = append(objects.Items, &unstructuredObj)
-	}
-}
-
-func GetFsChartPath(imageSpec types.ImageSpec) string {
-	return filepath.Join(os.TempDir(), fmt.Sprintf("%s-%s", imageSpec.Name, imageSpec.Ref))
-}
-
-func GetConfigFilePath(config types.ImageSpec) string {
-	return filepath.Join(os.TempDir(), filepath.Join(config.Ref, configFileName))
+	return ParseManifestToObjects(strings.NewReader(manifest))
 }
 
 func GetFsManifestChartPath(imageChartPath string) string {
@@ -168,7 +141,12 @@ func GetYamlFileContent(filePath string) (interface{}, error) {
 	return fileContent, err
 }
 
-func WriteToFile(filePath string, bytes []byte) error {
+func WriteToFile(filePath string, content []byte) error {
+	return WriteStreamToFile(filePath, bytes.NewReader(content))
+}
+
+// WriteStreamToFile writes the content read from the reader to the file at filePath, without buffering it as a whole.
+func WriteStreamToFile(filePath string, reader io.Reader) error {
 	// create directory
 	if err := os.MkdirAll(filepath.Dir(filePath), fs.ModePerm); err != nil {
 		return err
@@ -181,7 +159,8 @@ func WriteToFile(filePath string, bytes []byte) error {
 	}
 
 	// write to file
-	if _, err = file.Write(bytes); err != nil {
+	if _, err = io.Copy(file, reader); err != nil {
+		_ = file.Close()
 		return fmt.Errorf("writing file to path %s caused an error: %w", filePath, err)
 	}
 	return file.Close()
@@ -200,12 +179,21 @@ func GetResourceLabel(resource client.Object, labelName string) (string, error)
 }
 
 func GetStringifiedYamlFromFilePath(filePath string) (string, error) {
-	file, err := os.ReadFile(filePath)
+	file, err := os.Open(filePath)
 	if err != nil {
 		return "", err
 	}
+	defer file.Close()
 
-	return string(file), err
+	// the content is read into the string directly, instead of copying it from a byte slice of the same size
+	var content strings.Builder
+	if info, err := file.Stat(); err == nil {
+		content.Grow(int(info.Size()))
+	}
+	if _, err := io.Copy(&content, file); err != nil {
+		return "", err
+	}
+	return content.String(), nil
 }
 
 // CalculateHash returns hash for interfaceToBeHashed.
@@ -223,7 +211,10 @@ func CalculateHash(interfaceToBeHashed any) (uint32, error) {
 // Transform applies the passed object transformations to the manifest string passed.
 func Transform(ctx context.Context, manifestStringified string,
 	object types.BaseCustomObject, transforms []types.ObjectTransform,
-) (*types.ManifestResources, error) {
+) (_ *types.ManifestResources, err error) {
+	ctx, span := tracing.Start(ctx, tracing.SpanTransform, attribute.Int("transforms", len(transforms)))
+	defer func() { tracing.End(span, err) }()
+
 	objects, err := ParseManifestStringToObjects(manifestStringified)
 	if err != nil {
 		return nil, err
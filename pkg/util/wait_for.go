@@ -0,0 +1,98 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+)
+
+var ErrWaitForValueWithoutJSONPath = errors.New("value requires a jsonPath")
+
+// ValidateWaitFor returns an error if the JSONPath of the WaitFor cannot be parsed,
+// or if a Value is expected without a JSONPath.
+func ValidateWaitFor(waitFor types.WaitFor) error {
+	if waitFor.JSONPath == "" {
+		if waitFor.Value != "" {
+			return ErrWaitForValueWithoutJSONPath
+		}
+		return nil
+	}
+	_, err := parseWaitForJSONPath(waitFor.JSONPath)
+	return err
+}
+
+// UnsatisfiedWaits returns the objects of the passed WaitFors, which do not exist or do not satisfy
+// their expectation yet. Objects of APIs not served by the cluster do not exist.
+func UnsatisfiedWaits(ctx context.Context, clnt client.Reader, waits []types.WaitFor,
+) ([]types.UnsatisfiedWait, error) {
+	var unsatisfied []types.UnsatisfiedWait
+	for _, waitFor := range waits {
+		reason, err := unsatisfiedReason(ctx, clnt, waitFor)
+		if err != nil {
+			return nil, fmt.Errorf("waiting for %s: %w", waitFor, err)
+		}
+		if reason != "" {
+			unsatisfied = append(unsatisfied, types.UnsatisfiedWait{WaitFor: waitFor, Reason: reason})
+		}
+	}
+	return unsatisfied, nil
+}
+
+// unsatisfiedReason returns why the object of the WaitFor does not satisfy its expectation,
+// or an empty reason if it does.
+func unsatisfiedReason(ctx context.Context, clnt client.Reader, waitFor types.WaitFor) (string, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(waitFor.APIVersion)
+	obj.SetKind(waitFor.Kind)
+	if err := clnt.Get(ctx, client.ObjectKey{Namespace: waitFor.Namespace, Name: waitFor.Name}, obj); err != nil {
+		if client.IgnoreNotFound(err) == nil || meta.IsNoMatchError(err) {
+			return "not found", nil
+		}
+		return "", err
+	}
+
+	if waitFor.JSONPath == "" {
+		conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		for _, condition := range conditions {
+			fields, isMap := condition.(map[string]interface{})
+			if isMap && fields["type"] == "Ready" && fields["status"] == "True" {
+				return "", nil
+			}
+		}
+		return "condition Ready is not True", nil
+	}
+
+	path, err := parseWaitForJSONPath(waitFor.JSONPath)
+	if err != nil {
+		return "", err
+	}
+	value := &bytes.Buffer{}
+	if err := path.Execute(value, obj.Object); err != nil {
+		return fmt.Sprintf("%s not found", waitFor.JSONPath), nil //nolint:nilerr // missing fields are unsatisfied
+	}
+	if value.String() != waitFor.Value {
+		return fmt.Sprintf("%s is %q, expected %q", waitFor.JSONPath, value.String(), waitFor.Value), nil
+	}
+	return "", nil
+}
+
+// parseWaitForJSONPath parses a JSONPath like kubectl wait --for=jsonpath, with or without braces.
+func parseWaitForJSONPath(expression string) (*jsonpath.JSONPath, error) {
+	if !strings.HasPrefix(expression, "{") {
+		expression = "{" + expression + "}"
+	}
+	path := jsonpath.New("waitFor")
+	if err := path.Parse(expression); err != nil {
+		return nil, fmt.Errorf("parsing jsonPath %s: %w", expression, err)
+	}
+	return path, nil
+}
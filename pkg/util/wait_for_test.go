@@ -0,0 +1,61 @@
+package util_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/module-manager/pkg/types"
+	"github.com/kyma-project/module-manager/pkg/util"
+)
+
+func Test_UnsatisfiedWaits(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	istio := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "operator.kyma-project.io/v1alpha1",
+		"kind":       "Istio",
+		"metadata":   map[string]any{"name": "default", "namespace": "kyma-system"},
+		"status": map[string]any{
+			"state":      "Ready",
+			"conditions": []any{map[string]any{"type": "Ready", "status": "True"}},
+		},
+	}}
+	clnt := fake.NewClientBuilder().WithObjects(istio).Build()
+	waitFor := func(name, jsonPath, value string) types.WaitFor {
+		return types.WaitFor{
+			APIVersion: "operator.kyma-project.io/v1alpha1", Kind: "Istio", Name: name, Namespace: "kyma-system",
+			JSONPath: jsonPath, Value: value,
+		}
+	}
+
+	unsatisfied, err := util.UnsatisfiedWaits(ctx, clnt, []types.WaitFor{
+		waitFor("default", "", ""),
+		waitFor("default", ".status.state", "Ready"),
+		waitFor("default", "{.status.conditions[?(@.type==\"Ready\")].status}", "True"),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, unsatisfied)
+
+	unsatisfied, err = util.UnsatisfiedWaits(ctx, clnt, []types.WaitFor{
+		waitFor("other", "", ""),
+		waitFor("default", ".status.state", "Processing"),
+		waitFor("default", ".status.version", "1.0.0"),
+	})
+	require.NoError(t, err)
+	require.Len(t, unsatisfied, 3)
+	assert.Equal(t, "Istio kyma-system/other: not found", unsatisfied[0].String())
+	assert.Equal(t, `.status.state is "Ready", expected "Processing"`, unsatisfied[1].Reason)
+	assert.Equal(t, ".status.version not found", unsatisfied[2].Reason)
+}
+
+func Test_ValidateWaitFor(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, util.ValidateWaitFor(types.WaitFor{JSONPath: ".status.state", Value: "Ready"}))
+	require.ErrorIs(t, util.ValidateWaitFor(types.WaitFor{Value: "Ready"}), util.ErrWaitForValueWithoutJSONPath)
+	require.Error(t, util.ValidateWaitFor(types.WaitFor{JSONPath: "{.status[", Value: "Ready"}))
+}
@@ -0,0 +1,121 @@
+// Package version describes the build of the module-manager operator or library running in a process.
+package version
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// Name identifies module-manager in the managedBy status of Manifests.
+	Name       = "module-manager"
+	modulePath = "github.com/kyma-project/module-manager"
+	devVersion = "dev"
+
+	buildInfoMetricName = "module_manager_build_info"
+)
+
+// Version, GitCommit and BuildDate are set on build with
+// -ldflags "-X github.com/kyma-project/module-manager/pkg/version.Version=v1.2.3".
+// If they are not set, they are read from the build info of the binary, which contains the version of
+// module-manager also if it is embedded as a library.
+//
+//nolint:gochecknoglobals
+var (
+	Version   string
+	GitCommit string
+	BuildDate string
+)
+
+// Info is the build info of module-manager.
+type Info struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"gitCommit,omitempty"`
+	BuildDate string `json:"buildDate,omitempty"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
+}
+
+// Get returns the build info of module-manager.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		fromBuildInfo(&info, buildInfo)
+	}
+	if info.Version == "" {
+		info.Version = devVersion
+	}
+	return info
+}
+
+// ManagedBy returns the identifier of the running module-manager version, e.g. "module-manager/v1.2.3".
+func ManagedBy() string {
+	return Name + "/" + Get().Version
+}
+
+// Handler returns an http.Handler serving the build info as JSON.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(writer).Encode(Get())
+	})
+}
+
+// Collector returns a metric with the build info as labels and a constant value of 1,
+// so that outdated versions can be detected across a fleet of operators.
+func Collector() prometheus.Collector {
+	info := Get()
+	buildInfo := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: buildInfoMetricName,
+		Help: "Indicates the version of module-manager managing the Manifests",
+		ConstLabels: prometheus.Labels{
+			"version": info.Version, "git_commit": info.GitCommit, "go_version": info.GoVersion,
+		},
+	})
+	buildInfo.Set(1)
+	return buildInfo
+}
+
+// fromBuildInfo completes unset fields of the info from the build info of the binary.
+func fromBuildInfo(info *Info, buildInfo *debug.BuildInfo) {
+	if info.Version == "" {
+		if buildInfo.Main.Path == modulePath {
+			info.Version = moduleVersion(&buildInfo.Main)
+		}
+		for _, dep := range buildInfo.Deps {
+			if dep.Path == modulePath {
+				info.Version = moduleVersion(dep)
+			}
+		}
+	}
+	// VCS settings only describe the main module
+	if buildInfo.Main.Path != modulePath {
+		return
+	}
+	for _, setting := range buildInfo.Settings {
+		if setting.Key == "vcs.revision" && info.GitCommit == "" {
+			info.GitCommit = setting.Value
+		}
+	}
+}
+
+func moduleVersion(module *debug.Module) string {
+	if module.Replace != nil && module.Replace.Version != "" {
+		module = module.Replace
+	}
+	// binaries built from a checkout are versioned as "(devel)"
+	if module.Version == "" || module.Version == "(devel)" {
+		return ""
+	}
+	return module.Version
+}
@@ -0,0 +1,28 @@
+package version_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/module-manager/pkg/version"
+)
+
+func Test_Version(t *testing.T) {
+	version.Version = "v1.2.3"
+	defer func() { version.Version = "" }()
+
+	assert.Equal(t, "module-manager/v1.2.3", version.ManagedBy())
+
+	recorder := httptest.NewRecorder()
+	version.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/version", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	info := version.Info{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &info))
+	assert.Equal(t, "v1.2.3", info.Version)
+	assert.NotEmpty(t, info.GoVersion)
+}